@@ -0,0 +1,228 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"pkm-sync/internal/archive"
+	"pkm-sync/internal/config"
+	"pkm-sync/pkg/models"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	archiveExtractSource    string
+	archiveExtractOutputDir string
+	archiveExtractLimit     int
+	archiveExtractDryRun    bool
+)
+
+var archiveCmd = &cobra.Command{
+	Use:   "archive",
+	Short: "Maintain the Gmail email archive",
+	Long: `Maintenance commands for the Gmail archive (archive.enabled), which keeps
+every synced message as a raw .eml file indexed in archive.db.`,
+}
+
+var archiveExtractAttachmentsCmd = &cobra.Command{
+	Use:   "extract-attachments",
+	Short: "Rebuild attachment files from the archived .eml files",
+	Long: `Write the attachments of archived messages to a directory, read from the
+stored .eml files instead of fetching them from Gmail again, e.g. after the
+vault's attachment folder was deleted.
+
+Each message's Gmail source decides which attachments are kept: its
+attachment_types and max_attachment_size apply as during sync. Files already
+present with the same content are left alone; a different file with the same
+name gets the message ID appended.
+
+Examples:
+  pkm-sync archive extract-attachments --output ./vault/attachments
+  pkm-sync archive extract-attachments --source work_gmail --output ./restore --dry-run`,
+	Args: cobra.NoArgs,
+	RunE: runArchiveExtractAttachmentsCommand,
+}
+
+func init() {
+	rootCmd.AddCommand(archiveCmd)
+	archiveCmd.AddCommand(archiveExtractAttachmentsCmd)
+	archiveExtractAttachmentsCmd.Flags().StringVar(&archiveExtractSource, "source", "",
+		"Only messages from this Gmail source")
+	archiveExtractAttachmentsCmd.Flags().StringVarP(&archiveExtractOutputDir, "output", "o", "",
+		"Directory to write attachments to (required)")
+	archiveExtractAttachmentsCmd.Flags().IntVar(&archiveExtractLimit, "limit", 0,
+		"Maximum number of messages to read, newest first (0 = all)")
+	archiveExtractAttachmentsCmd.Flags().BoolVar(&archiveExtractDryRun, "dry-run", false,
+		"List the attachments that would be written without writing them")
+	_ = archiveExtractAttachmentsCmd.MarkFlagRequired("output")
+}
+
+func runArchiveExtractAttachmentsCommand(_ *cobra.Command, _ []string) error {
+	cfg, err := config.LoadConfigOrDefault()
+	if err != nil {
+		return err
+	}
+
+	dbPath, err := resolveArchiveDBPath(cfg)
+	if err != nil {
+		return err
+	}
+
+	if _, err := os.Stat(dbPath); err != nil {
+		return fmt.Errorf("archive database not found at %s: %w", dbPath, err)
+	}
+
+	store, err := archive.NewStore(dbPath)
+	if err != nil {
+		return fmt.Errorf("failed to open archive: %w", err)
+	}
+	defer store.Close()
+
+	messages, err := store.ListMessages(archiveExtractSource, archiveExtractLimit)
+	if err != nil {
+		return err
+	}
+
+	result, err := extractArchivedAttachments(cfg, messages, archiveExtractOutputDir, archiveExtractDryRun)
+	if err != nil {
+		return err
+	}
+
+	verb := "Wrote"
+	if archiveExtractDryRun {
+		verb = "Would write"
+	}
+
+	fmt.Printf("%s %d attachments to %s (%d already present, %d skipped by policy, %d messages unreadable)\n",
+		verb, result.written, archiveExtractOutputDir, result.present, result.skipped, result.failed)
+
+	return nil
+}
+
+// extractResult counts what extractArchivedAttachments did.
+type extractResult struct {
+	written, present, skipped, failed int
+}
+
+// extractArchivedAttachments writes the attachments of messages to outputDir
+// under the policy of each message's Gmail source. Messages whose .eml cannot
+// be read are reported and counted, not fatal.
+func extractArchivedAttachments(
+	cfg *models.Config, messages []archive.StoredMessage, outputDir string, dryRun bool,
+) (extractResult, error) {
+	var result extractResult
+
+	policies := make(map[string]archive.AttachmentPolicy)
+
+	for _, msg := range messages {
+		if !msg.HasAttachments {
+			continue
+		}
+
+		policy, ok := policies[msg.SourceName]
+		if !ok {
+			var err error
+
+			policy, err = attachmentPolicyFor(cfg, msg.SourceName)
+			if err != nil {
+				return result, err
+			}
+
+			policies[msg.SourceName] = policy
+		}
+
+		attachments, err := archive.ReadEMLAttachments(msg.EMLPath)
+		if err != nil {
+			fmt.Printf("Warning: skipping message %s: %v\n", msg.GmailID, err)
+
+			result.failed++
+
+			continue
+		}
+
+		for _, att := range attachments {
+			if allowed, reason := policy.Allows(att); !allowed {
+				fmt.Printf("Skipping %s from %s: %s\n", att.Name, msg.GmailID, reason)
+
+				result.skipped++
+
+				continue
+			}
+
+			path, exists := attachmentOutputPath(outputDir, msg.GmailID, att)
+			if exists {
+				result.present++
+
+				continue
+			}
+
+			if dryRun {
+				fmt.Printf("Would write %s (%s)\n", path, formatByteSize(int64(len(att.Data))))
+
+				result.written++
+
+				continue
+			}
+
+			if err := os.MkdirAll(outputDir, 0755); err != nil {
+				return result, fmt.Errorf("failed to create %s: %w", outputDir, err)
+			}
+
+			if err := os.WriteFile(path, att.Data, 0644); err != nil {
+				return result, fmt.Errorf("failed to write %s: %w", path, err)
+			}
+
+			result.written++
+		}
+	}
+
+	return result, nil
+}
+
+// attachmentPolicyFor returns the attachment policy of a Gmail source, or no
+// limits when the source is no longer configured.
+func attachmentPolicyFor(cfg *models.Config, sourceName string) (archive.AttachmentPolicy, error) {
+	sourceConfig, ok := cfg.Sources[sourceName]
+	if !ok {
+		return archive.AttachmentPolicy{}, nil
+	}
+
+	maxBytes, err := archive.ParseByteSize(sourceConfig.Gmail.MaxAttachmentSize)
+	if err != nil {
+		return archive.AttachmentPolicy{}, fmt.Errorf("source %s: max_attachment_size: %w", sourceName, err)
+	}
+
+	return archive.AttachmentPolicy{Types: sourceConfig.Gmail.AttachmentTypes, MaxBytes: maxBytes}, nil
+}
+
+// attachmentOutputPath returns where att is written and whether an identical
+// file is already there. A different file with the same name moves the new
+// one to <name>_<gmailID><ext>.
+func attachmentOutputPath(outputDir, gmailID string, att archive.Attachment) (string, bool) {
+	name := filepath.Base(strings.ReplaceAll(att.Name, `\`, "/"))
+	if name == "." || name == ".." || name == "/" {
+		name = "attachment"
+	}
+
+	path := filepath.Join(outputDir, name)
+
+	existing, err := os.ReadFile(path)
+	if err != nil {
+		return path, false
+	}
+
+	if bytes.Equal(existing, att.Data) {
+		return path, true
+	}
+
+	ext := filepath.Ext(name)
+	path = filepath.Join(outputDir, strings.TrimSuffix(name, ext)+"_"+gmailID+ext)
+
+	existing, err = os.ReadFile(path)
+
+	return path, err == nil && bytes.Equal(existing, att.Data)
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"pkm-sync/internal/archive"
+	"pkm-sync/pkg/models"
+)
+
+func TestExtractArchivedAttachments_AppliesSourcePolicy(t *testing.T) {
+	cfg := &models.Config{Sources: map[string]models.SourceConfig{
+		"work": {Type: "gmail", Gmail: models.GmailSourceConfig{AttachmentTypes: []string{"pdf", "txt"}}},
+	}}
+
+	fixture, err := filepath.Abs("../internal/archive/testdata/report.eml")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	messages := []archive.StoredMessage{
+		{Message: archive.Message{GmailID: "m1", SourceName: "work", EMLPath: fixture, HasAttachments: true}},
+		{Message: archive.Message{GmailID: "m2", SourceName: "work", EMLPath: "/missing.eml", HasAttachments: true}},
+		{Message: archive.Message{GmailID: "m3", SourceName: "work", EMLPath: "/missing.eml"}},
+	}
+
+	outputDir := filepath.Join(t.TempDir(), "attachments")
+
+	result, err := extractArchivedAttachments(cfg, messages, outputDir, false)
+	if err != nil {
+		t.Fatalf("extractArchivedAttachments failed: %v", err)
+	}
+
+	if want := (extractResult{written: 2, skipped: 1, failed: 1}); result != want {
+		t.Errorf("Expected %+v, got %+v", want, result)
+	}
+
+	data, err := os.ReadFile(filepath.Join(outputDir, "report.pdf"))
+	if err != nil || string(data) != "%PDF-1.4 fake report\n" {
+		t.Errorf("Expected report.pdf to be restored, got %q (%v)", data, err)
+	}
+
+	if _, err := os.Stat(filepath.Join(outputDir, "grafik-ü.png")); !os.IsNotExist(err) {
+		t.Error("Expected the png to be skipped by attachment_types")
+	}
+
+	result, err = extractArchivedAttachments(cfg, messages[:1], outputDir, false)
+	if err != nil {
+		t.Fatalf("second run failed: %v", err)
+	}
+
+	if want := (extractResult{present: 2, skipped: 1}); result != want {
+		t.Errorf("Expected a rerun to find the files present, got %+v", result)
+	}
+}
+
+func TestAttachmentOutputPath_NameClash(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "report.pdf"), []byte("other"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	path, exists := attachmentOutputPath(dir, "m1", archive.Attachment{Name: "../report.pdf", Data: []byte("new")})
+	if exists || path != filepath.Join(dir, "report_m1.pdf") {
+		t.Errorf("Expected report_m1.pdf beside the different file, got %s (exists %v)", path, exists)
+	}
+}
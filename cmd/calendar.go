@@ -8,6 +8,7 @@ import (
 	"strings"
 	"time"
 
+	"pkm-sync/internal/naming"
 	"pkm-sync/internal/sources/google/auth"
 	internalcalendar "pkm-sync/internal/sources/google/calendar"
 	"pkm-sync/internal/sources/google/drive"
@@ -223,7 +224,7 @@ func displayEventsAsTable(events []*calendar.Event, start, end time.Time, calend
 
 		// Export docs if requested.
 		if exportDocs && driveService != nil && event.Description != "" {
-			eventDir := filepath.Join(exportDir, sanitizeEventName(event.Summary))
+			eventDir := filepath.Join(exportDir, naming.Clean(event.Summary))
 
 			exportedFiles, err := driveService.ExportAttachedDocsFromEvent(event.Description, eventDir)
 			if err != nil {
@@ -291,32 +292,6 @@ func printEventDetails(modelEvent *models.CalendarEvent, event *calendar.Event)
 	}
 }
 
-func sanitizeEventName(name string) string {
-	// Define a replacer for characters not safe for filenames.
-	replacer := strings.NewReplacer(
-		"/", "-",
-		"\\", "-",
-		":", "-",
-		"*", "-",
-		"?", "-",
-		"\"", "-",
-		"<", "-",
-		">", "-",
-		"|", "-",
-		"&", "and",
-	)
-	// Replace all invalid characters.
-	sanitized := replacer.Replace(name)
-	// Trim leading/trailing whitespace and periods.
-	sanitized = strings.Trim(sanitized, " .")
-	// Limit length to avoid issues with max path length.
-	if len(sanitized) > 100 {
-		sanitized = sanitized[:100]
-	}
-
-	return sanitized
-}
-
 // displayEventsAsJSON displays events in JSON format.
 func displayEventsAsJSON(events []*calendar.Event, calendarService *internalcalendar.Service, driveService *drive.Service) error {
 	// Convert to a serializable format.
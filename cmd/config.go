@@ -7,6 +7,7 @@ import (
 	"strings"
 
 	"pkm-sync/internal/config"
+	"pkm-sync/internal/configure"
 	"pkm-sync/internal/keystore"
 	"pkm-sync/internal/sources/google/auth"
 
@@ -29,9 +30,19 @@ Examples:
 
 var configInitCmd = &cobra.Command{
 	Use:   "init",
-	Short: "Create default configuration file",
-	Long:  "Creates a default configuration file with sensible defaults for pkm-sync.",
-	RunE:  runConfigInitCommand,
+	Short: "Create a configuration file",
+	Long: `Creates a minimal configuration file for pkm-sync.
+
+On a terminal with no --source or --target, an interactive wizard asks for a
+source, runs its auth flow, and asks for a target and output directory.
+With --source, a commented template for that source type is written instead.
+An existing config file is never overwritten without --force.
+
+Examples:
+  pkm-sync config init                                  # Interactive setup
+  pkm-sync config init --source gmail --target obsidian # Commented template
+  pkm-sync config init --source slack -o ~/vault --force`,
+	RunE: runConfigInitCommand,
 }
 
 var configShowCmd = &cobra.Command{
@@ -90,9 +101,11 @@ func init() {
 	// Flags for config init
 	configInitCmd.Flags().BoolP("force", "f", false, "Overwrite existing config file")
 	configInitCmd.Flags().StringP("output", "o", "", "Output directory for default target")
-	configInitCmd.Flags().String("target", "", "Default target (obsidian, logseq)")
-	configInitCmd.Flags().String("source", "", "Default source (google_calendar)")
+	configInitCmd.Flags().String("target", "", "Default target (obsidian, logseq, roam)")
+	configInitCmd.Flags().String("source", "", "Scaffold a commented template for this source type "+
+		"(gmail, google_calendar, google_drive, slack, jira, servicenow)")
 }
+
 func runConfigInitCommand(cmd *cobra.Command, args []string) error {
 	force, _ := cmd.Flags().GetBool("force")
 	output, _ := cmd.Flags().GetString("output")
@@ -109,6 +122,44 @@ func runConfigInitCommand(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("config file already exists at %s. Use --force to overwrite", configPath)
 	}
 
+	// With no source or target given on a terminal, walk the user through setup.
+	if source == "" && target == "" && configure.IsInteractive() {
+		answers, err := configure.PromptInit(configure.InitAnswers{OutputDir: output})
+		if err != nil || answers == nil {
+			return err
+		}
+
+		if err := writeInitConfig(configPath, *answers); err != nil {
+			return err
+		}
+
+		if err := runInitAuth(cmd, *answers); err != nil {
+			fmt.Printf("Warning: %v\n", err)
+			fmt.Printf("Authenticate later with: %s\n", initAuthHint(*answers))
+		}
+
+		printConfigInitNextSteps(answers.SourceName)
+
+		return nil
+	}
+
+	if isInitTemplateSourceType(source) {
+		answers := newInitAnswers(source, target, output)
+		if err := writeInitConfig(configPath, answers); err != nil {
+			return err
+		}
+
+		fmt.Printf("Review the placeholder values, then authenticate with: %s\n", initAuthHint(answers))
+		printConfigInitNextSteps(answers.SourceName)
+
+		return nil
+	}
+
+	if source != "" {
+		return fmt.Errorf("unsupported source type %q (supported: %s)",
+			source, strings.Join(initTemplateSourceTypes, ", "))
+	}
+
 	// Create default config
 	cfg := config.GetDefaultConfig()
 
@@ -121,29 +172,6 @@ func runConfigInitCommand(cmd *cobra.Command, args []string) error {
 		cfg.Sync.DefaultTarget = target
 	}
 
-	if source != "" {
-		// Add to enabled sources if not already present
-		found := false
-
-		for _, src := range cfg.Sync.EnabledSources {
-			if src == source {
-				found = true
-
-				break
-			}
-		}
-
-		if !found {
-			cfg.Sync.EnabledSources = append(cfg.Sync.EnabledSources, source)
-		}
-
-		// Enable the source in the sources config
-		if sourceConfig, exists := cfg.Sources[source]; exists {
-			sourceConfig.Enabled = true
-			cfg.Sources[source] = sourceConfig
-		}
-	}
-
 	// Save config
 	if err := config.SaveConfig(cfg); err != nil {
 		return fmt.Errorf("failed to save config: %w", err)
@@ -158,6 +186,33 @@ func runConfigInitCommand(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// writeInitConfig renders the minimal config for answers and writes it to configPath.
+func writeInitConfig(configPath string, answers configure.InitAnswers) error {
+	content, err := renderInitConfig(answers)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(configPath), 0755); err != nil {
+		return fmt.Errorf("failed to create config directory: %w", err)
+	}
+
+	if err := os.WriteFile(configPath, []byte(content), 0644); err != nil {
+		return fmt.Errorf("failed to write config file: %w", err)
+	}
+
+	fmt.Printf("Configuration file created at: %s\n", configPath)
+
+	return nil
+}
+
+func printConfigInitNextSteps(sourceName string) {
+	fmt.Println("\nYou can now:")
+	fmt.Printf("  - Choose what to sync: pkm-sync configure %s\n", sourceName)
+	fmt.Printf("  - Preview a sync: pkm-sync sync --dry-run\n")
+	fmt.Printf("  - Edit the config: pkm-sync config edit\n")
+}
+
 func runConfigShowCommand(cmd *cobra.Command, args []string) error {
 	cfg, err := config.LoadConfig()
 	if err != nil {
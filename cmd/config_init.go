@@ -0,0 +1,229 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"pkm-sync/internal/config"
+	"pkm-sync/internal/configure"
+	"pkm-sync/internal/sources/google/auth"
+	"pkm-sync/pkg/models"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+// initTemplateSourceTypes lists the source types `config init --source` can scaffold.
+var initTemplateSourceTypes = []string{
+	"gmail", "google_calendar", "google_drive", "slack", "jira", "servicenow",
+}
+
+// initPlaceholders fills required fields when scaffolding without prompts.
+var initPlaceholders = map[string]string{
+	"calendar_id":   "primary",
+	"workspace_url": "https://myworkspace.slack.com",
+	"instance_url":  "https://issues.example.com",
+	"project_keys":  "PROJ",
+}
+
+func isInitTemplateSourceType(sourceType string) bool {
+	for _, t := range initTemplateSourceTypes {
+		if t == sourceType {
+			return true
+		}
+	}
+
+	return false
+}
+
+// newInitAnswers returns answers for a non-interactive scaffold, filling any
+// field the source type needs with a placeholder.
+func newInitAnswers(sourceType, target, outputDir string) configure.InitAnswers {
+	answers := configure.InitAnswers{
+		SourceType: sourceType,
+		SourceName: "my_" + sourceType,
+		Fields:     make(map[string]string, len(initPlaceholders)),
+		Target:     target,
+		OutputDir:  outputDir,
+	}
+
+	for key, value := range initPlaceholders {
+		answers.Fields[key] = value
+	}
+
+	if sourceType == "servicenow" {
+		answers.Fields["instance_url"] = "https://example.service-now.com"
+	}
+
+	if answers.Target == "" {
+		answers.Target = "obsidian"
+	}
+
+	if answers.OutputDir == "" {
+		answers.OutputDir = "./output"
+	}
+
+	return answers
+}
+
+// renderInitConfig produces a minimal, commented config file for one source
+// and one target, and checks that it loads and validates.
+func renderInitConfig(a configure.InitAnswers) (string, error) {
+	sourceBlock, err := initSourceBlock(a)
+	if err != nil {
+		return "", err
+	}
+
+	targetBlock, err := initTargetBlock(a.Target)
+	if err != nil {
+		return "", err
+	}
+
+	var b strings.Builder
+
+	b.WriteString("# pkm-sync configuration generated by `pkm-sync config init`.\n")
+	b.WriteString("# See CONFIGURATION.md for every available setting.\n\n")
+	b.WriteString("sync:\n")
+	b.WriteString("  # Sources synced when no --source flag is given.\n")
+	fmt.Fprintf(&b, "  enabled_sources: [%s]\n", strconv.Quote(a.SourceName))
+	b.WriteString("  # Output format for exported notes (obsidian, logseq or roam).\n")
+	fmt.Fprintf(&b, "  default_target: %s\n", a.Target)
+	b.WriteString("  # Vault or graph directory notes are written to.\n")
+	fmt.Fprintf(&b, "  default_output_dir: %s\n", strconv.Quote(a.OutputDir))
+	b.WriteString("  # How far back to sync by default (7d, 30d, 2006-01-02, today).\n")
+	b.WriteString("  default_since: 7d\n\n")
+	b.WriteString("sources:\n")
+	fmt.Fprintf(&b, "  %s:\n", a.SourceName)
+	fmt.Fprintf(&b, "    type: %s\n", a.SourceType)
+	b.WriteString("    enabled: true\n")
+	b.WriteString(sourceBlock)
+	b.WriteString("\ntargets:\n")
+	fmt.Fprintf(&b, "  %s:\n", a.Target)
+	fmt.Fprintf(&b, "    type: %s\n", a.Target)
+	b.WriteString(targetBlock)
+
+	var cfg models.Config
+	if err := yaml.Unmarshal([]byte(b.String()), &cfg); err != nil {
+		return "", fmt.Errorf("generated config is not valid YAML: %w", err)
+	}
+
+	if err := config.ValidateConfig(&cfg); err != nil {
+		return "", fmt.Errorf("generated config is invalid: %w", err)
+	}
+
+	return b.String(), nil
+}
+
+func initSourceBlock(a configure.InitAnswers) (string, error) {
+	field := func(key string) string { return strconv.Quote(a.Fields[key]) }
+
+	switch a.SourceType {
+	case "gmail":
+		name := a.Fields["name"]
+		if name == "" {
+			name = "Gmail"
+		}
+
+		return "    gmail:\n" +
+			"      name: " + strconv.Quote(name) + "\n" +
+			"      # Gmail search query selecting which messages to sync.\n" +
+			"      query: \"in:inbox\"\n", nil
+	case "google_calendar":
+		return "    google:\n" +
+			"      # Calendar to sync; \"primary\" is your main calendar.\n" +
+			"      calendar_id: " + field("calendar_id") + "\n", nil
+	case "google_drive":
+		name := a.Fields["name"]
+		if name == "" {
+			name = "My Drive"
+		}
+
+		return "    drive:\n" +
+			"      name: " + strconv.Quote(name) + "\n" +
+			"      # Folder IDs to sync; pick them with `pkm-sync configure " + a.SourceName + "`.\n" +
+			"      folder_ids: []\n" +
+			"      doc_export_format: md\n", nil
+	case "slack":
+		return "    slack:\n" +
+			"      workspace_url: " + field("workspace_url") + "\n" +
+			"      # Pick channels with `pkm-sync configure " + a.SourceName + "`; until then only DMs are synced.\n" +
+			"      channels: []\n" +
+			"      include_dms: true\n", nil
+	case "jira":
+		return "    jira:\n" +
+			"      instance_url: " + field("instance_url") + "\n" +
+			"      # Projects to sync; replace with your project keys or set jql instead.\n" +
+			"      project_keys: [" + field("project_keys") + "]\n", nil
+	case "servicenow":
+		return "    servicenow:\n" +
+			"      instance_url: " + field("instance_url") + "\n", nil
+	default:
+		return "", fmt.Errorf("unsupported source type %q (supported: %s)",
+			a.SourceType, strings.Join(initTemplateSourceTypes, ", "))
+	}
+}
+
+func initTargetBlock(target string) (string, error) {
+	switch target {
+	case "obsidian":
+		return "    obsidian:\n" +
+			"      # Folder inside the vault for new notes.\n" +
+			"      default_folder: Inbox\n" +
+			"      include_frontmatter: true\n", nil
+	case "logseq":
+		return "    logseq:\n" +
+			"      # Page new blocks are added to.\n" +
+			"      default_page: Inbox\n" +
+			"      use_properties: true\n", nil
+	case "roam":
+		return "    roam:\n" +
+			"      # Link created dates to Roam daily notes.\n" +
+			"      daily_note_refs: true\n", nil
+	default:
+		return "", fmt.Errorf("unsupported target %q (supported: obsidian, logseq, roam)", target)
+	}
+}
+
+// runInitAuth runs the auth flow for the chosen source type so the first sync
+// works without further setup.
+func runInitAuth(cmd *cobra.Command, a configure.InitAnswers) error {
+	switch a.SourceType {
+	case "gmail", "google_calendar", "google_drive":
+		fmt.Println("\nAuthorizing Google access...")
+
+		if _, err := auth.GetClient(); err != nil {
+			return fmt.Errorf("google authorization failed: %w (run 'pkm-sync setup' for help)", err)
+		}
+
+		return nil
+	case "slack":
+		slackAuthWorkspace = a.Fields["workspace_url"]
+
+		return runSlackAuthCommand(cmd, nil)
+	case "servicenow":
+		servicenowAuthInstance = a.Fields["instance_url"]
+
+		return runServiceNowAuthCommand(cmd, nil)
+	case "jira":
+		fmt.Println("\nJira uses jira-cli credentials: set JIRA_API_TOKEN or run 'jira init'.")
+
+		return nil
+	default:
+		return nil
+	}
+}
+
+// initAuthHint returns the command that authenticates the given source type.
+func initAuthHint(a configure.InitAnswers) string {
+	switch a.SourceType {
+	case "slack":
+		return "pkm-sync slack auth --workspace " + a.Fields["workspace_url"]
+	case "servicenow":
+		return "pkm-sync servicenow auth --instance " + a.Fields["instance_url"]
+	case "jira":
+		return "jira init (or export JIRA_API_TOKEN)"
+	default:
+		return "pkm-sync setup"
+	}
+}
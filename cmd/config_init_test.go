@@ -0,0 +1,113 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"pkm-sync/internal/config"
+	"pkm-sync/pkg/models"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+func TestRenderInitConfig_AllSourceTypesValidate(t *testing.T) {
+	for _, sourceType := range initTemplateSourceTypes {
+		for _, target := range []string{"obsidian", "logseq"} {
+			t.Run(sourceType+"_"+target, func(t *testing.T) {
+				content, err := renderInitConfig(newInitAnswers(sourceType, target, "~/vault"))
+				if err != nil {
+					t.Fatalf("renderInitConfig failed: %v", err)
+				}
+
+				if !strings.HasPrefix(content, "# pkm-sync configuration") {
+					t.Error("expected template to start with a header comment")
+				}
+
+				var cfg models.Config
+				if err := yaml.Unmarshal([]byte(content), &cfg); err != nil {
+					t.Fatalf("template is not valid YAML: %v", err)
+				}
+
+				if cfg.Sync.DefaultTarget != target {
+					t.Errorf("expected default_target %q, got %q", target, cfg.Sync.DefaultTarget)
+				}
+
+				if src := cfg.Sources["my_"+sourceType]; src.Type != sourceType || !src.Enabled {
+					t.Errorf("expected enabled %s source, got %+v", sourceType, src)
+				}
+			})
+		}
+	}
+}
+
+func TestRenderInitConfig_UnsupportedValues(t *testing.T) {
+	if _, err := renderInitConfig(newInitAnswers("carrier_pigeon", "obsidian", "")); err == nil {
+		t.Error("expected error for unsupported source type")
+	}
+
+	if _, err := renderInitConfig(newInitAnswers("gmail", "notion", "")); err == nil {
+		t.Error("expected error for unsupported target")
+	}
+}
+
+func newConfigInitTestCommand(t *testing.T, args ...string) *cobra.Command {
+	t.Helper()
+
+	cmd := &cobra.Command{RunE: runConfigInitCommand}
+	cmd.Flags().BoolP("force", "f", false, "")
+	cmd.Flags().StringP("output", "o", "", "")
+	cmd.Flags().String("target", "", "")
+	cmd.Flags().String("source", "", "")
+
+	if err := cmd.Flags().Parse(args); err != nil {
+		t.Fatalf("failed to parse flags: %v", err)
+	}
+
+	return cmd
+}
+
+func TestConfigInit_TemplateRespectsForce(t *testing.T) {
+	tempDir := t.TempDir()
+	oldConfigDir := configDir
+	configDir = tempDir
+
+	defer func() { configDir = oldConfigDir }()
+
+	configPath := filepath.Join(tempDir, config.ConfigFileName)
+	existing := []byte("# hand-written config\n")
+
+	if err := os.WriteFile(configPath, existing, 0644); err != nil {
+		t.Fatalf("failed to write existing config: %v", err)
+	}
+
+	cmd := newConfigInitTestCommand(t, "--source", "gmail", "--target", "obsidian")
+	if err := runConfigInitCommand(cmd, nil); err == nil {
+		t.Fatal("expected error when config exists and --force is not set")
+	}
+
+	data, err := os.ReadFile(configPath)
+	if err != nil {
+		t.Fatalf("failed to read config: %v", err)
+	}
+
+	if string(data) != string(existing) {
+		t.Fatal("existing config was modified without --force")
+	}
+
+	cmd = newConfigInitTestCommand(t, "--source", "gmail", "--target", "obsidian", "--force")
+	if err := runConfigInitCommand(cmd, nil); err != nil {
+		t.Fatalf("expected --force to overwrite, got %v", err)
+	}
+
+	data, err = os.ReadFile(configPath)
+	if err != nil {
+		t.Fatalf("failed to read config: %v", err)
+	}
+
+	if !strings.Contains(string(data), "type: gmail") {
+		t.Errorf("expected gmail template to be written, got:\n%s", data)
+	}
+}
@@ -1,9 +1,6 @@
 package main
 
 import (
-	"fmt"
-	"os"
-
 	"pkm-sync/internal/config"
 	"pkm-sync/internal/configure"
 
@@ -36,11 +33,11 @@ func init() {
 }
 
 func runConfigureCommand(_ *cobra.Command, args []string) error {
-	cfg, err := config.LoadConfig()
+	// configure saves the whole config back, so an invalid file must stop it
+	// rather than be replaced with the defaults.
+	cfg, err := config.LoadConfigOrDefault()
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Warning: could not load config (%v); using defaults\n", err)
-
-		cfg = config.GetDefaultConfig()
+		return err
 	}
 
 	sourceID := ""
@@ -0,0 +1,29 @@
+package main
+
+import (
+	"os"
+	"testing"
+)
+
+func TestRunConfigureCommand_InvalidConfigLeftUntouched(t *testing.T) {
+	invalidConfig := `transformers:
+  enabled: true
+  pipeline_order: ["link_extractor"]
+`
+
+	configPath, cleanup := createTempConfig(t, invalidConfig)
+	defer cleanup()
+
+	if err := runConfigureCommand(nil, nil); err == nil {
+		t.Fatal("Expected an invalid config to fail configure, got nil")
+	}
+
+	data, err := os.ReadFile(configPath)
+	if err != nil {
+		t.Fatalf("Failed to read config: %v", err)
+	}
+
+	if string(data) != invalidConfig {
+		t.Errorf("configure rewrote the invalid config:\n%s", data)
+	}
+}
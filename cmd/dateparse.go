@@ -12,6 +12,7 @@ import (
 // parseDateTime parses a date string with support for multiple formats.
 // It supports:
 // - Named dates: "today", "yesterday", "tomorrow" (explicit, returning midnight)
+// - Calendar periods: "this week", "this month", "this quarter", "this year" (the period's start)
 // - ISO 8601: "2006-01-02", "2006-01-02T15:04:05", with timezone variants
 // - Relative day durations: "7d", "30d" (Go's ParseDuration doesn't support "d")
 // - Go durations: "24h", "2h30m"
@@ -41,6 +42,10 @@ func parseDateTime(dateStr string) (time.Time, error) {
 		return time.Date(yesterday.Year(), yesterday.Month(), yesterday.Day(), 0, 0, 0, 0, yesterday.Location()), nil
 	}
 
+	if start, ok := periodStart(dateStr, now); ok {
+		return start, nil
+	}
+
 	// Try parsing ISO 8601 date formats (these take precedence over natural language)
 	isoFormats := []string{
 		"2006-01-02T15:04:05Z07:00", // ISO 8601 with timezone
@@ -75,12 +80,36 @@ func parseDateTime(dateStr string) (time.Time, error) {
 	return parseNaturalDate(dateStr, now)
 }
 
+// periodStart returns midnight at the start of the calendar period named by
+// "this week" (weeks start on Monday), "this month", "this quarter" or "this
+// year", in now's location. ok is false for any other input.
+func periodStart(period string, now time.Time) (start time.Time, ok bool) {
+	year, month, day := now.Date()
+
+	switch strings.ToLower(strings.Join(strings.Fields(period), " ")) {
+	case "this week":
+		daysSinceMonday := (int(now.Weekday()) + 6) % 7
+
+		return time.Date(year, month, day-daysSinceMonday, 0, 0, 0, 0, now.Location()), true
+	case "this month":
+		return time.Date(year, month, 1, 0, 0, 0, 0, now.Location()), true
+	case "this quarter":
+		firstMonth := month - (month-1)%3
+
+		return time.Date(year, firstMonth, 1, 0, 0, 0, 0, now.Location()), true
+	case "this year":
+		return time.Date(year, time.January, 1, 0, 0, 0, 0, now.Location()), true
+	default:
+		return time.Time{}, false
+	}
+}
+
 // parseNaturalDate attempts to parse natural language dates.
 // Returns an error if the input appears to be invalid or unparseable.
 func parseNaturalDate(dateStr string, now time.Time) (time.Time, error) {
 	t, err := naturaldate.Parse(dateStr, now)
 	if err != nil {
-		return time.Time{}, fmt.Errorf("unable to parse date: %s. Supported formats: ISO 8601 (2006-01-02), relative durations (7d, 24h), named dates (today, yesterday, tomorrow), or natural language (last week, 3 days ago)", dateStr)
+		return time.Time{}, fmt.Errorf("unable to parse date: %s. Supported formats: ISO 8601 (2006-01-02), relative durations (7d, 24h), named dates (today, yesterday, tomorrow, this week/month/quarter/year), or natural language (last week, 3 days ago)", dateStr)
 	}
 
 	// Check if the result is the same as the reference time
@@ -0,0 +1,195 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sort"
+	"sync"
+
+	"pkm-sync/internal/config"
+	"pkm-sync/internal/sources/google/auth"
+	"pkm-sync/pkg/interfaces"
+	"pkm-sync/pkg/models"
+
+	"github.com/spf13/cobra"
+)
+
+var doctorCmd = &cobra.Command{
+	Use:   "doctor",
+	Short: "Check the config and the credentials of every enabled source",
+	Long: `Check that pkm-sync is ready to sync, without fetching any items.
+
+The config is loaded and validated, then each enabled source's settings are
+checked and its credentials tried with one lightweight call: the Gmail
+profile, the Drive root folder, the calendar, Slack's auth.test or the
+current Jira user. Google access never starts the browser authorization
+flow; a missing token is reported instead.
+
+Each check prints [OK] or [FAIL] with a hint on how to fix it. The command
+exits non-zero when any check fails, so it can run before a cron or CI sync.
+
+Examples:
+  pkm-sync doctor
+  pkm-sync doctor && pkm-sync sync`,
+	Args: cobra.NoArgs,
+	RunE: runDoctorCommand,
+}
+
+func init() {
+	rootCmd.AddCommand(doctorCmd)
+}
+
+func runDoctorCommand(_ *cobra.Command, _ []string) error {
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		fmt.Printf("[FAIL] config: %v\n", err)
+
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	results := runDoctorChecks(cfg, sync.OnceValues(auth.GetStoredClient))
+	printDoctorResults(os.Stdout, results)
+
+	failed := 0
+
+	for _, r := range results {
+		if r.Err != nil {
+			failed++
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d checks failed", failed, len(results))
+	}
+
+	return nil
+}
+
+// doctorResult is the outcome of one doctor check: the config as a whole, or
+// one enabled source.
+type doctorResult struct {
+	Name   string
+	Type   string // source type; empty for the config check
+	Detail string // what passed, e.g. the authenticated account
+	Err    error
+	Hint   string // how to fix Err
+}
+
+// runDoctorChecks validates cfg and checks every enabled source, in name
+// order. googleClient returns the stored Google OAuth client; it is only
+// called when a Google source is enabled.
+func runDoctorChecks(cfg *models.Config, googleClient func() (*http.Client, error)) []doctorResult {
+	results := []doctorResult{{Name: "config"}}
+	if err := config.ValidateConfig(cfg); err != nil {
+		results[0].Err = err
+		results[0].Hint = "fix the config file (pkm-sync config edit)"
+	}
+
+	names := getEnabledSources(cfg)
+	sort.Strings(names)
+
+	for _, name := range names {
+		results = append(results, checkDoctorSource(name, cfg.Sources[name], googleClient))
+	}
+
+	return results
+}
+
+// checkDoctorSource validates one source's settings, creates it and, when it
+// implements interfaces.AuthChecker, tries its credentials.
+func checkDoctorSource(
+	name string, sourceConfig models.SourceConfig, googleClient func() (*http.Client, error),
+) doctorResult {
+	result := doctorResult{Name: name, Type: sourceConfig.Type}
+
+	if err := config.ValidateSource(sourceConfig); err != nil {
+		result.Err = fmt.Errorf("invalid config: %w", err)
+		result.Hint = fmt.Sprintf("fix sources.%s in the config file (pkm-sync config edit)", name)
+
+		return result
+	}
+
+	var client *http.Client
+
+	if isGoogleSourceType(sourceConfig.Type) {
+		var err error
+
+		if client, err = googleClient(); err != nil {
+			result.Err = err
+			result.Hint = doctorAuthHint(sourceConfig)
+
+			return result
+		}
+	}
+
+	src, err := createSourceWithConfig(name, sourceConfig, client)
+	if err != nil {
+		result.Err = err
+		result.Hint = doctorAuthHint(sourceConfig)
+
+		return result
+	}
+
+	checker, ok := src.(interfaces.AuthChecker)
+	if !ok {
+		result.Detail = "configured (no live check for this source type)"
+
+		return result
+	}
+
+	if result.Detail, err = checker.CheckAuth(); err != nil {
+		result.Err = err
+		result.Hint = doctorAuthHint(sourceConfig)
+	}
+
+	return result
+}
+
+func isGoogleSourceType(sourceType string) bool {
+	return sourceType == "gmail" || sourceType == "google_calendar" || sourceType == "google_drive"
+}
+
+// doctorAuthHint tells the user how to fix the credentials of a source type.
+func doctorAuthHint(sourceConfig models.SourceConfig) string {
+	switch sourceConfig.Type {
+	case "gmail", "google_calendar", "google_drive":
+		return "run 'pkm-sync setup' to authorize Google access ('pkm-sync config clear-token' first if the token is stale)"
+	case "slack":
+		return fmt.Sprintf("run 'pkm-sync slack auth --workspace %s'", sourceConfig.Slack.WorkspaceURL)
+	case "servicenow":
+		return fmt.Sprintf("run 'pkm-sync servicenow auth --instance %s'", sourceConfig.ServiceNow.InstanceURL)
+	case "jira":
+		return "check instance_url and the jira-cli config and API token ('jira init')"
+	default:
+		return "check the source's credentials and settings"
+	}
+}
+
+// printDoctorResults writes one [OK] or [FAIL] line per check, with the hint
+// under each failure.
+func printDoctorResults(w io.Writer, results []doctorResult) {
+	for _, r := range results {
+		label := r.Name
+		if r.Type != "" {
+			label = fmt.Sprintf("%s (%s)", r.Name, r.Type)
+		}
+
+		if r.Err != nil {
+			fmt.Fprintf(w, "[FAIL] %s: %v\n", label, r.Err)
+
+			if r.Hint != "" {
+				fmt.Fprintf(w, "       → %s\n", r.Hint)
+			}
+
+			continue
+		}
+
+		if r.Detail != "" {
+			fmt.Fprintf(w, "[OK]   %s: %s\n", label, r.Detail)
+		} else {
+			fmt.Fprintf(w, "[OK]   %s\n", label)
+		}
+	}
+}
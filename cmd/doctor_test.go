@@ -0,0 +1,82 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"pkm-sync/pkg/models"
+)
+
+func TestRunDoctorChecks(t *testing.T) {
+	cfg := &models.Config{
+		Sync: models.SyncConfig{
+			DefaultOutputDir: t.TempDir(),
+			EnabledSources:   []string{"notes", "gmail_work", "broken"},
+		},
+		Sources: map[string]models.SourceConfig{
+			"notes":      {Enabled: true, Type: "localfs", LocalFS: models.LocalFSSourceConfig{RootDir: t.TempDir()}},
+			"gmail_work": {Enabled: true, Type: "gmail", Gmail: models.GmailSourceConfig{Name: "Work"}},
+			"broken":     {Enabled: true, Type: "localfs"},
+			"disabled":   {Enabled: false, Type: "localfs"},
+		},
+		Targets: map[string]models.TargetConfig{"obsidian": {Type: "obsidian"}},
+	}
+
+	googleCalls := 0
+	googleClient := func() (*http.Client, error) {
+		googleCalls++
+
+		return nil, errors.New("no stored token")
+	}
+
+	results := runDoctorChecks(cfg, googleClient)
+
+	names := make([]string, len(results))
+	for i, r := range results {
+		names[i] = r.Name
+	}
+
+	if got := strings.Join(names, ","); got != "config,broken,gmail_work,notes" {
+		t.Fatalf("checks = %s, want config then the enabled sources in name order", got)
+	}
+
+	if results[0].Err == nil {
+		t.Error("expected the config check to fail on the source without root_dir")
+	}
+
+	if results[1].Err == nil || !strings.Contains(results[1].Err.Error(), "root_dir") {
+		t.Errorf("broken: expected an invalid config error, got %v", results[1].Err)
+	}
+
+	if results[2].Err == nil || !strings.Contains(results[2].Hint, "pkm-sync setup") || googleCalls != 1 {
+		t.Errorf("gmail_work: expected the missing token with a setup hint, got %+v (%d client calls)",
+			results[2], googleCalls)
+	}
+
+	if results[3].Err != nil || !strings.Contains(results[3].Detail, "no live check") {
+		t.Errorf("notes: expected a pass without a live check, got %+v", results[3])
+	}
+}
+
+func TestPrintDoctorResults(t *testing.T) {
+	var buf bytes.Buffer
+
+	printDoctorResults(&buf, []doctorResult{
+		{Name: "config"},
+		{Name: "slack_eng", Type: "slack", Err: errors.New("no Slack token"), Hint: doctorAuthHint(models.SourceConfig{
+			Type: "slack", Slack: models.SlackSourceConfig{WorkspaceURL: "https://myorg.slack.com"},
+		})},
+		{Name: "jira_work", Type: "jira", Detail: "jdoe on https://jira.example.com"},
+	})
+
+	want := "[OK]   config\n" +
+		"[FAIL] slack_eng (slack): no Slack token\n" +
+		"       → run 'pkm-sync slack auth --workspace https://myorg.slack.com'\n" +
+		"[OK]   jira_work (jira): jdoe on https://jira.example.com\n"
+	if buf.String() != want {
+		t.Errorf("output:\n%s\nwant:\n%s", buf.String(), want)
+	}
+}
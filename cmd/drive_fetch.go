@@ -9,10 +9,10 @@ import (
 	"strings"
 	"time"
 
+	"pkm-sync/internal/naming"
 	"pkm-sync/internal/sinks"
 	"pkm-sync/internal/sources/google/auth"
 	"pkm-sync/internal/sources/google/drive"
-	"pkm-sync/internal/utils"
 	"pkm-sync/pkg/models"
 
 	mdconverter "github.com/JohannesKaufmann/html-to-markdown/v2"
@@ -222,7 +222,7 @@ func resolveOutputPath(outputFlag, docTitle, format string) string {
 
 	info, err := os.Stat(outputFlag)
 	if (err == nil && info.IsDir()) || strings.HasSuffix(outputFlag, "/") {
-		safe := utils.SanitizeFilename(docTitle)
+		safe := naming.Slug(docTitle)
 
 		return filepath.Join(outputFlag, safe+ext)
 	}
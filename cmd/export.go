@@ -13,9 +13,12 @@ var (
 	driveTargetName   string
 	driveOutputDir    string
 	driveSince        string
+	driveUntil        string
 	driveDryRun       bool
 	driveLimit        int
 	driveOutputFormat string
+	drivePreviewLines int
+	driveFull         bool
 )
 
 var driveCmd = &cobra.Command{
@@ -32,25 +35,31 @@ Use 'pkm-sync fetch <URL>' to fetch a single document by URL:
 
 Examples:
   pkm-sync drive --source my_drive --target obsidian --output ./vault
-  pkm-sync drive --since 7d --dry-run`,
+  pkm-sync drive --since 7d --dry-run
+  pkm-sync drive --since 2023-01-01 --until 2024-01-01`,
 	RunE: runDriveCommand,
 }
 
 func init() {
 	rootCmd.AddCommand(driveCmd)
 	driveCmd.Flags().StringVar(&driveSourceName, "source", "", "Drive source name (as configured in config file)")
-	driveCmd.Flags().StringVar(&driveTargetName, "target", "", "PKM target (obsidian, logseq)")
+	driveCmd.Flags().StringVar(&driveTargetName, "target", "", "PKM target (obsidian, logseq, roam)")
 	driveCmd.Flags().StringVarP(&driveOutputDir, "output", "o", "", "Output directory")
 	driveCmd.Flags().StringVar(&driveSince, "since", "", "Sync documents modified since (7d, 2006-01-02, today)")
+	driveCmd.Flags().StringVar(&driveUntil, "until", "", "Sync documents modified before this date (2006-01-02)")
 	driveCmd.Flags().BoolVar(&driveDryRun, "dry-run", false, "Show what would be synced without making changes")
 	driveCmd.Flags().IntVar(&driveLimit, "limit", 100, "Maximum number of documents to fetch")
 	driveCmd.Flags().StringVar(&driveOutputFormat, "format", "summary", "Output format for dry-run (summary, json)")
+	driveCmd.Flags().IntVar(&drivePreviewLines, "preview-lines", 0,
+		"With --dry-run, show the first N lines of each note that would be created or updated")
+	driveCmd.Flags().BoolVar(&driveFull, "full", false,
+		"Re-fetch the whole since window instead of resuming from the last sync")
 }
 
 func runDriveCommand(cmd *cobra.Command, args []string) error {
-	cfg, err := config.LoadConfig()
+	cfg, err := config.LoadConfigOrDefault()
 	if err != nil {
-		cfg = config.GetDefaultConfig()
+		return err
 	}
 
 	var sourcesToSync []string
@@ -86,9 +95,12 @@ func runDriveCommand(cmd *cobra.Command, args []string) error {
 		OutputDir:    finalOutputDir,
 		Since:        finalSince,
 		SinceFlag:    driveSince,
+		Until:        driveUntil,
 		DefaultLimit: driveLimit,
 		DryRun:       driveDryRun,
 		OutputFormat: driveOutputFormat,
+		PreviewLines: drivePreviewLines,
+		Full:         driveFull,
 		SourceKind:   "Drive",
 		ItemKind:     "documents",
 	})
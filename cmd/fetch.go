@@ -6,14 +6,15 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"pkm-sync/internal/config"
+	"pkm-sync/internal/naming"
 	"pkm-sync/internal/resolve"
 	"pkm-sync/internal/sinks"
 	"pkm-sync/internal/sources/google/auth"
 	"pkm-sync/internal/sources/google/drive"
 	jirasource "pkm-sync/internal/sources/jira"
-	"pkm-sync/internal/utils"
 	"pkm-sync/pkg/interfaces"
 	"pkm-sync/pkg/models"
 	"pkm-sync/pkg/routing"
@@ -26,10 +27,13 @@ var (
 	fetchCmdFormat   string
 	fetchCmdOutput   string
 	fetchCmdComments bool
+	fetchCmdID       string
+	fetchCmdTarget   string
+	fetchCmdDryRun   bool
 )
 
 var fetchCmd = &cobra.Command{
-	Use:   "fetch <url-or-identifier>",
+	Use:   "fetch [url-or-identifier]",
 	Short: "Fetch a single item by URL or source-qualified identifier",
 	Long: `Fetch a single item by URL or identifier and output its content.
 
@@ -47,6 +51,14 @@ Identifier formats:
   When multiple sources of the same type exist, use --source to disambiguate:
     pkm-sync fetch jira/PROJ-123 --source jira_work
 
+Resync a single item by ID:
+
+  --source NAME --id ID fetches one item from a configured source, runs it
+  through the transform pipeline and exports it to the source's usual sinks,
+  overwriting a botched note without a full sync. With --dry-run the
+  transformed item is printed instead. IDs are Gmail thread IDs (message IDs
+  when include_threads is off), Drive file IDs, or Jira issue keys.
+
 By default content is written to stdout. Use --output to write a markdown file
 with YAML frontmatter (enables re-fetch later).
 
@@ -60,8 +72,10 @@ Examples:
   pkm-sync fetch "https://docs.google.com/document/d/abc123/edit" --format md --comments
   pkm-sync fetch "https://docs.google.com/document/d/abc123/edit" --output ./docs/
   pkm-sync fetch jira/PROJ-123
-  pkm-sync fetch jira/PROJ-123 --source jira_work --output ./jira/`,
-	Args: cobra.ExactArgs(1),
+  pkm-sync fetch jira/PROJ-123 --source jira_work --output ./jira/
+  pkm-sync fetch --source gmail_work --id 18c2f0a9b7d1e234
+  pkm-sync fetch --source my_drive --id FILE_ID --dry-run`,
+	Args: cobra.MaximumNArgs(1),
 	RunE: runFetchCommand,
 }
 
@@ -71,6 +85,9 @@ func init() {
 	fetchCmd.Flags().StringVar(&fetchCmdFormat, "format", "", "Output format (txt, md, json). Defaults to md with --output, txt otherwise")
 	fetchCmd.Flags().StringVarP(&fetchCmdOutput, "output", "o", "", "Write to file/directory with frontmatter")
 	fetchCmd.Flags().BoolVar(&fetchCmdComments, "comments", false, "Append document comments as markdown footnotes (Drive documents)")
+	fetchCmd.Flags().StringVar(&fetchCmdID, "id", "", "Resync one item by source-native ID through the sync pipeline (requires --source)")
+	fetchCmd.Flags().StringVar(&fetchCmdTarget, "target", "", "PKM target for --id resync (obsidian, logseq, roam). Defaults to sync.default_target")
+	fetchCmd.Flags().BoolVar(&fetchCmdDryRun, "dry-run", false, "With --id, print the transformed item instead of exporting it")
 }
 
 func runFetchCommand(cmd *cobra.Command, args []string) error {
@@ -79,6 +96,22 @@ func runFetchCommand(cmd *cobra.Command, args []string) error {
 		ctx = context.Background()
 	}
 
+	if fetchCmdID != "" {
+		if len(args) > 0 {
+			return fmt.Errorf("--id cannot be combined with a positional identifier")
+		}
+
+		if fetchCmdSource == "" {
+			return fmt.Errorf("--id requires --source")
+		}
+
+		return runFetchByID(ctx, fetchCmdSource, fetchCmdID)
+	}
+
+	if len(args) == 0 {
+		return fmt.Errorf("a URL or identifier is required (or use --source with --id)")
+	}
+
 	id := routing.Parse(args[0])
 
 	// Case 1 & 2: the identifier is (or contains) a URL — route through resolvers.
@@ -217,6 +250,128 @@ func runFetchBySourceName(ctx context.Context, key, sourceName string) error {
 	return outputFetchedItem(item, "")
 }
 
+// runFetchByID resyncs one item: it fetches itemID from the named source and
+// runs it through the same transform pipeline and sinks as a regular sync.
+func runFetchByID(ctx context.Context, sourceName, itemID string) error {
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	sc, ok := cfg.Sources[sourceName]
+	if !ok {
+		return fmt.Errorf("source %q not found in config", sourceName)
+	}
+
+	targetName := cfg.Sync.DefaultTarget
+	if fetchCmdTarget != "" {
+		targetName = fetchCmdTarget
+	}
+
+	outputDir := cfg.Sync.DefaultOutputDir
+	if fetchCmdOutput != "" {
+		outputDir = fetchCmdOutput
+	}
+
+	return runSourceSync(cfg, sourceSyncConfig{
+		SourceType:   sc.Type,
+		Sources:      []string{sourceName},
+		TargetName:   targetName,
+		OutputDir:    outputDir,
+		Since:        "today",
+		DefaultLimit: 1,
+		DryRun:       fetchCmdDryRun,
+		SourceKind:   sc.Type,
+		ItemKind:     "item",
+		ItemID:       itemID,
+		Context:      ctx,
+	})
+}
+
+// singleItemSource adapts a Fetcher to interfaces.Source so that one item can
+// be pushed through MultiSyncer. since and limit are ignored.
+type singleItemSource struct {
+	interfaces.Source
+	fetcher interfaces.Fetcher
+	key     string
+}
+
+// newSingleItemSource wraps src so that fetching returns only the item with key.
+// Returns an error when src does not support single-item fetch.
+func newSingleItemSource(src interfaces.Source, key string) (*singleItemSource, error) {
+	fetcher, ok := src.(interfaces.Fetcher)
+	if !ok {
+		return nil, fmt.Errorf("source %q does not support single-item fetch", src.Name())
+	}
+
+	return &singleItemSource{Source: src, fetcher: fetcher, key: key}, nil
+}
+
+// Capabilities reports the wrapped source's capabilities; a single-item fetch
+// is never incremental.
+func (s *singleItemSource) Capabilities() interfaces.SourceCapabilities {
+	caps := s.Source.Capabilities()
+	caps.SupportsIncremental = false
+
+	return caps
+}
+
+func (s *singleItemSource) Fetch(_ time.Time, _ int) ([]models.FullItem, error) {
+	return s.FetchContext(context.Background(), time.Time{}, 1)
+}
+
+func (s *singleItemSource) FetchContext(ctx context.Context, _ time.Time, _ int) ([]models.FullItem, error) {
+	item, err := s.fetcher.FetchOne(ctx, s.key)
+	if err != nil {
+		return nil, err
+	}
+
+	if item == nil {
+		return nil, fmt.Errorf("item %q not found", s.key)
+	}
+
+	return []models.FullItem{item}, nil
+}
+
+// printFetchedItems writes transformed items to stdout for `fetch --id --dry-run`,
+// honoring --format (txt, md, json).
+func printFetchedItems(items []models.FullItem) error {
+	for _, item := range items {
+		if fetchCmdFormat == "json" {
+			data, err := item.MarshalJSON()
+			if err != nil {
+				return fmt.Errorf("failed to serialize item: %w", err)
+			}
+
+			fmt.Println(string(data))
+
+			continue
+		}
+
+		if fetchCmdFormat == "md" {
+			bi := &models.BasicItem{
+				ID:         item.GetID(),
+				Title:      item.GetTitle(),
+				SourceType: item.GetSourceType(),
+				ItemType:   item.GetItemType(),
+				Content:    item.GetContent(),
+				CreatedAt:  item.GetCreatedAt(),
+				UpdatedAt:  item.GetUpdatedAt(),
+				Tags:       item.GetTags(),
+				Metadata:   item.GetMetadata(),
+			}
+
+			fmt.Print(sinks.NewObsidianFormatterPublic().FormatItemContent(bi))
+
+			continue
+		}
+
+		fmt.Printf("# %s\n\n%s\n", item.GetTitle(), item.GetContent())
+	}
+
+	return nil
+}
+
 // buildResolvers constructs a resolver slice from available auth and config.
 // sourceName optionally restricts to a specific configured source (by name).
 // Drive resolution only requires Google OAuth. Jira resolution additionally
@@ -432,7 +587,7 @@ func resolveItemOutputPath(outputFlag, title, format string) string {
 
 	info, err := os.Stat(outputFlag)
 	if (err == nil && info.IsDir()) || strings.HasSuffix(outputFlag, "/") {
-		safe := utils.SanitizeFilename(title)
+		safe := naming.Slug(title)
 
 		return filepath.Join(outputFlag, safe+ext)
 	}
@@ -0,0 +1,113 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	syncer "pkm-sync/internal/sync"
+	"pkm-sync/pkg/interfaces"
+	"pkm-sync/pkg/models"
+)
+
+// fakeSource is a Source without single-item support.
+type fakeSource struct{ name string }
+
+func (f *fakeSource) Name() string                                             { return f.name }
+func (f *fakeSource) Configure(_ map[string]interface{}, _ *http.Client) error { return nil }
+func (f *fakeSource) SupportsRealtime() bool                                   { return false }
+func (f *fakeSource) Capabilities() interfaces.SourceCapabilities {
+	return interfaces.SourceCapabilities{SupportsIncremental: true}
+}
+
+func (f *fakeSource) Fetch(_ time.Time, _ int) ([]models.FullItem, error) {
+	return []models.FullItem{
+		&models.BasicItem{ID: "a", Title: "A"},
+		&models.BasicItem{ID: "b", Title: "B"},
+	}, nil
+}
+
+// fakeFetcherSource records the keys it was asked for.
+type fakeFetcherSource struct {
+	fakeSource
+	requested []string
+}
+
+func (f *fakeFetcherSource) FetchOne(_ context.Context, key string) (models.FullItem, error) {
+	f.requested = append(f.requested, key)
+
+	if key == "missing" {
+		return nil, nil
+	}
+
+	return &models.BasicItem{ID: key, Title: "Item " + key, Content: "body"}, nil
+}
+
+func TestSingleItemSource_SyncsOnlyRequestedItem(t *testing.T) {
+	src := &fakeFetcherSource{fakeSource: fakeSource{name: "gmail_work"}}
+
+	single, err := newSingleItemSource(src, "thread-42")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if single.Name() != "gmail_work" {
+		t.Errorf("Name() = %q, want %q", single.Name(), "gmail_work")
+	}
+
+	result, err := syncer.NewMultiSyncer(nil).SyncAll(
+		t.Context(),
+		[]syncer.SourceEntry{{Name: "gmail_work", Src: single}},
+		nil,
+		syncer.MultiSyncOptions{DryRun: true},
+	)
+	if err != nil {
+		t.Fatalf("SyncAll failed: %v", err)
+	}
+
+	if len(result.Items) != 1 || result.Items[0].GetID() != "thread-42" {
+		t.Fatalf("expected exactly item thread-42, got %d items", len(result.Items))
+	}
+
+	if len(src.requested) != 1 || src.requested[0] != "thread-42" {
+		t.Errorf("FetchOne keys = %v, want [thread-42]", src.requested)
+	}
+}
+
+func TestSingleItemSource_NotIncremental(t *testing.T) {
+	single, err := newSingleItemSource(&fakeFetcherSource{fakeSource: fakeSource{name: "drive"}}, "file1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if single.Capabilities().SupportsIncremental {
+		t.Error("single-item fetch must not report incremental support")
+	}
+}
+
+func TestSingleItemSource_NotFound(t *testing.T) {
+	single, err := newSingleItemSource(&fakeFetcherSource{fakeSource: fakeSource{name: "drive"}}, "missing")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, err := single.Fetch(time.Time{}, 0); err == nil {
+		t.Fatal("expected not-found error, got nil")
+	}
+}
+
+func TestNewSingleItemSource_RequiresFetcher(t *testing.T) {
+	if _, err := newSingleItemSource(&fakeSource{name: "calendar"}, "event1"); err == nil {
+		t.Fatal("expected error for source without FetchOne, got nil")
+	}
+}
+
+func TestRunFetchCommand_IDRequiresSource(t *testing.T) {
+	fetchCmdID, fetchCmdSource = "abc", ""
+	t.Cleanup(func() { fetchCmdID = "" })
+
+	if err := runFetchCommand(fetchCmd, nil); err == nil {
+		t.Fatal("expected error when --id is used without --source")
+	}
+}
@@ -13,9 +13,12 @@ var (
 	gmailTargetName   string
 	gmailOutputDir    string
 	gmailSince        string
+	gmailUntil        string
 	gmailDryRun       bool
 	gmailLimit        int
 	gmailOutputFormat string
+	gmailPreviewLines int
+	gmailFull         bool
 )
 
 var gmailCmd = &cobra.Command{
@@ -27,25 +30,32 @@ var gmailCmd = &cobra.Command{
 Examples:
   pkm-sync gmail --source gmail_work --target obsidian --output ./vault
   pkm-sync gmail --source gmail_personal --target logseq --output ./graph --since 7d
-  pkm-sync gmail --source gmail_work --target obsidian --dry-run`,
+  pkm-sync gmail --source gmail_work --target obsidian --dry-run
+  pkm-sync gmail --source gmail_work --since 2023-01-01 --until 2024-01-01`,
 	RunE: runGmailCommand,
 }
 
 func init() {
 	rootCmd.AddCommand(gmailCmd)
 	gmailCmd.Flags().StringVar(&gmailSourceName, "source", "", "Gmail source (gmail_work, gmail_personal, etc.)")
-	gmailCmd.Flags().StringVar(&gmailTargetName, "target", "", "PKM target (obsidian, logseq)")
+	gmailCmd.Flags().StringVar(&gmailTargetName, "target", "", "PKM target (obsidian, logseq, roam)")
 	gmailCmd.Flags().StringVarP(&gmailOutputDir, "output", "o", "", "Output directory")
 	gmailCmd.Flags().StringVar(&gmailSince, "since", "", "Sync emails since (7d, 2006-01-02, today)")
+	gmailCmd.Flags().StringVar(&gmailUntil, "until", "",
+		"Sync emails before this date, to backfill a past window (2006-01-02)")
 	gmailCmd.Flags().BoolVar(&gmailDryRun, "dry-run", false, "Show what would be synced without making changes")
 	gmailCmd.Flags().IntVar(&gmailLimit, "limit", 1000, "Maximum number of emails to fetch (default: 1000)")
 	gmailCmd.Flags().StringVar(&gmailOutputFormat, "format", "summary", "Output format for dry-run (summary, json)")
+	gmailCmd.Flags().IntVar(&gmailPreviewLines, "preview-lines", 0,
+		"With --dry-run, show the first N lines of each note that would be created or updated")
+	gmailCmd.Flags().BoolVar(&gmailFull, "full", false,
+		"Re-fetch the whole since window instead of resuming from the last sync")
 }
 
 func runGmailCommand(cmd *cobra.Command, args []string) error {
-	cfg, err := config.LoadConfig()
+	cfg, err := config.LoadConfigOrDefault()
 	if err != nil {
-		cfg = config.GetDefaultConfig()
+		return err
 	}
 
 	var sourcesToSync []string
@@ -81,9 +91,12 @@ func runGmailCommand(cmd *cobra.Command, args []string) error {
 		OutputDir:    finalOutputDir,
 		Since:        finalSince,
 		SinceFlag:    gmailSince,
+		Until:        gmailUntil,
 		DefaultLimit: gmailLimit,
 		DryRun:       gmailDryRun,
 		OutputFormat: gmailOutputFormat,
+		PreviewLines: gmailPreviewLines,
+		Full:         gmailFull,
 		SourceKind:   "Gmail",
 		ItemKind:     "emails",
 	})
@@ -4,17 +4,29 @@ import (
 	"context"
 	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
+	"os"
+	"os/signal"
 	"path/filepath"
 	"sort"
 	"strings"
+	"syscall"
 	"time"
 
+	"pkm-sync/internal/archive"
+	"pkm-sync/internal/attachments"
 	"pkm-sync/internal/config"
 	"pkm-sync/internal/sinks"
+	githubsource "pkm-sync/internal/sources/github"
 	"pkm-sync/internal/sources/google"
+	"pkm-sync/internal/sources/google/gmail"
 	jirasource "pkm-sync/internal/sources/jira"
+	jsonlsource "pkm-sync/internal/sources/jsonl"
+	localfssource "pkm-sync/internal/sources/localfs"
+	notionsource "pkm-sync/internal/sources/notion"
+	rsssource "pkm-sync/internal/sources/rss"
 	serviceNowSource "pkm-sync/internal/sources/servicenow"
 	slacksource "pkm-sync/internal/sources/slack"
 	"pkm-sync/internal/state"
@@ -38,7 +50,8 @@ func createSource(name string, client *http.Client) (interfaces.Source, error) {
 
 		return source, nil
 	default:
-		return nil, fmt.Errorf("unknown source '%s': supported sources are 'google_calendar' (others like slack, gmail, jira are planned for future releases)", name)
+		return nil, fmt.Errorf("unknown source '%s': only 'google_calendar' can be created without config; "+
+			"configure other types (gmail, google_drive, slack, jira, servicenow, jsonl, notion, rss, localfs, github) under sources", name)
 	}
 }
 
@@ -86,10 +99,145 @@ func createSourceWithConfig(sourceID string, sourceConfig models.SourceConfig, c
 			return nil, err
 		}
 
+		return source, nil
+	case notionsource.SourceType:
+		source := notionsource.NewNotionSource(sourceID, sourceConfig)
+		if err := source.Configure(nil, nil); err != nil {
+			return nil, err
+		}
+
+		return source, nil
+	case githubsource.SourceType:
+		source := githubsource.NewGitHubSource(sourceID, sourceConfig)
+		if err := source.Configure(nil, nil); err != nil {
+			return nil, err
+		}
+
+		return source, nil
+	case localfssource.SourceType:
+		source := localfssource.NewLocalFSSource(sourceID, sourceConfig)
+		if err := source.Configure(nil, nil); err != nil {
+			return nil, err
+		}
+
+		return source, nil
+	case rsssource.SourceType:
+		source := rsssource.NewRSSSource(sourceID, sourceConfig)
+		if err := source.Configure(nil, nil); err != nil {
+			return nil, err
+		}
+
+		return source, nil
+	case jsonlsource.SourceType:
+		source := jsonlsource.NewJSONLSource(sourceID, sourceConfig)
+		if err := source.Configure(nil, nil); err != nil {
+			return nil, err
+		}
+
 		return source, nil
 	default:
-		return nil, fmt.Errorf("unknown source type '%s': supported types are 'google_calendar', 'gmail', 'google_drive', 'slack', 'jira'", sourceConfig.Type)
+		return nil, fmt.Errorf("unknown source type '%s': supported types are 'google_calendar', 'gmail', 'google_drive', 'slack', 'jira', 'servicenow', 'jsonl', 'notion', 'rss', 'localfs', 'github'", sourceConfig.Type)
+	}
+}
+
+// attachGmailCache enables the on-disk fetch cache (app.cache_enabled) for a
+// Gmail source. Each source gets its own directory under app.cache_dir
+// (default <config dir>/cache). Failing to set it up only disables caching.
+func attachGmailCache(cfg *models.Config, sourceName string, src interfaces.Source) {
+	if !cfg.App.CacheEnabled {
+		return
+	}
+
+	gs, ok := src.(*google.GoogleSource)
+	if !ok || gs.GetGmailService() == nil {
+		return
+	}
+
+	cacheDir := cfg.App.CacheDir
+	if cacheDir == "" {
+		configDir, err := config.GetConfigDir()
+		if err != nil {
+			return
+		}
+
+		cacheDir = filepath.Join(configDir, "cache")
+	}
+
+	cache, err := gmail.NewFetchCache(filepath.Join(cacheDir, "gmail", sourceName), cfg.App.CacheTTL)
+	if err != nil {
+		fmt.Printf("Warning: Gmail cache disabled for '%s': %v\n", sourceName, err)
+
+		return
+	}
+
+	gs.GetGmailService().SetCache(cache)
+}
+
+// attachGmailFilesDir saves the attachments a Gmail source with
+// download_attachments downloads under <config dir>/gmail-files/<subdir>,
+// where subdir is its attachment_subdir or else the source name, so their
+// LocalPath can be linked from notes.
+func attachGmailFilesDir(sourceName string, sourceConfig models.SourceConfig, src interfaces.Source) {
+	gs, ok := src.(*google.GoogleSource)
+	if !ok || gs.GetGmailService() == nil || !sourceConfig.Gmail.DownloadAttachments {
+		return
+	}
+
+	configDir, err := config.GetConfigDir()
+	if err != nil {
+		return
+	}
+
+	subdir := sourceConfig.Gmail.AttachmentSubdir
+	if subdir == "" {
+		subdir = sourceName
+	}
+
+	gs.GetGmailService().SetFilesDir(filepath.Join(configDir, "gmail-files", subdir))
+}
+
+// historyTracker is implemented by sources that can fetch only the changes
+// since a provider-side cursor (Gmail's history ID) kept in the sync state.
+type historyTracker interface {
+	SetStartHistoryID(id string)
+	HistoryID() string
+}
+
+// fileIndexSetter is implemented by filesystem-backed sources that skip files
+// unchanged since the last run, tracked in a per-source state.FileIndex.
+type fileIndexSetter interface {
+	SetFileIndex(idx *state.FileIndex)
+}
+
+// attachmentStoreSetter is implemented by sources that write downloaded
+// attachment files to disk.
+type attachmentStoreSetter interface {
+	SetAttachmentStore(store *attachments.Store)
+}
+
+// openAttachmentStore opens the cross-run attachment index at
+// <config dir>/attachments.db when sync.dedupe_attachments_global is set, and
+// returns nil otherwise. Failing to open it only disables global dedup.
+func openAttachmentStore(cfg *models.Config) *attachments.Store {
+	if !cfg.Sync.DedupeAttachmentsGlobal {
+		return nil
+	}
+
+	configDir, err := config.GetConfigDir()
+	if err != nil {
+		fmt.Printf("Warning: global attachment dedup disabled: %v\n", err)
+
+		return nil
 	}
+
+	store, err := attachments.NewStore(filepath.Join(configDir, attachments.DefaultDBFile))
+	if err != nil {
+		fmt.Printf("Warning: global attachment dedup disabled: %v\n", err)
+
+		return nil
+	}
+
+	return store
 }
 
 // createFileSink creates a FileSink for the given formatter name and output directory.
@@ -97,8 +245,22 @@ func createFileSink(name string, outputDir string) (*sinks.FileSink, error) {
 	return sinks.NewFileSink(name, outputDir, nil)
 }
 
+// isJSONLTarget reports whether the named target appends items to a JSONL
+// file instead of writing notes.
+func isJSONLTarget(cfg *models.Config, name string) bool {
+	if targetConfig, exists := cfg.Targets[name]; exists && targetConfig.Type != "" {
+		return targetConfig.Type == "jsonl"
+	}
+
+	return name == "jsonl"
+}
+
 // createFileSinkWithConfig creates a FileSink configured from the application config.
 func createFileSinkWithConfig(name string, outputDir string, cfg *models.Config) (*sinks.FileSink, error) {
+	if isJSONLTarget(cfg, name) {
+		return nil, fmt.Errorf("target '%s' writes a JSONL file, not notes; it is only supported by sync", name)
+	}
+
 	fmtConfig := make(map[string]any)
 
 	if targetConfig, exists := cfg.Targets[name]; exists {
@@ -106,12 +268,161 @@ func createFileSinkWithConfig(name string, outputDir string, cfg *models.Config)
 		case "obsidian":
 			fmtConfig["template_dir"] = targetConfig.Obsidian.DefaultFolder
 			fmtConfig["daily_notes_format"] = targetConfig.Obsidian.DateFormat
+			fmtConfig["filename_template"] = targetConfig.Obsidian.FilenameTemplate
+			fmtConfig["tag_prefix"] = targetConfig.Obsidian.TagPrefix
+			fmtConfig["custom_fields"] = targetConfig.Obsidian.CustomFields
 		case "logseq":
 			fmtConfig["default_page"] = targetConfig.Logseq.DefaultPage
+		case "roam":
+			fmtConfig["daily_note_refs"] = targetConfig.Roam.DailyNoteRefs
 		}
+
+		fmtConfig["metadata_include"] = targetConfig.Metadata.Include
+		fmtConfig["metadata_exclude"] = targetConfig.Metadata.Exclude
+		fmtConfig["tag_hierarchy_separator"] = targetConfig.TagHierarchySeparator
+		fmtConfig["content_prefix"] = targetConfig.ContentPrefix
+		fmtConfig["content_suffix"] = targetConfig.ContentSuffix
+	}
+
+	fmtConfig["timezone"] = cfg.App.Timezone
+	fmtConfig["date_format"] = cfg.App.DateFormat
+
+	fileSink, err := sinks.NewFileSink(name, outputDir, fmtConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	deadLetterPath, err := resolveDeadLetterPath(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	fileSink.SetDeadLetter(sinks.NewDeadLetter(deadLetterPath))
+
+	if err := fileSink.SetConflictPolicy(cfg.Sync.OnConflict); err != nil {
+		return nil, err
 	}
 
-	return sinks.NewFileSink(name, outputDir, fmtConfig)
+	if err := fileSink.SetSubdirFormat(cfg.Sync.SubdirFormat); err != nil {
+		return nil, err
+	}
+
+	// Gmail notes only reach a file sink through reprocess, as sync feeds
+	// Gmail to the archive alone (see writesVaultFiles).
+	for sourceName, sourceConfig := range cfg.Sources {
+		if sourceConfig.Type == "gmail" {
+			fileSink.SetGmailFilenameTemplate(sourceName, sourceConfig.Gmail.FilenameTemplate)
+			fileSink.SetLabelFolders(sourceName, sourceConfig.Gmail.Folders)
+		}
+	}
+
+	if folder, ok := transform.RewrittenAttachmentFolder(transformConfig(cfg, name)); ok {
+		fileSink.SetAttachmentFolder(folder)
+	}
+
+	return fileSink, nil
+}
+
+// sourceTagFormat returns the prefix and separator for "source" tags. An
+// unset sync.source_tag_prefix reuses the Obsidian target's tag_prefix as the
+// namespace (tag_prefix "sync/" gives "sync/source/<name>"). An unset
+// separator nests with "/" when the prefix already does, else uses ":".
+func sourceTagFormat(cfg *models.Config, targetName string) (prefix, separator string) {
+	prefix = cfg.Sync.SourceTagPrefix
+	if prefix == "" && targetName == "obsidian" {
+		if tagPrefix := cfg.Targets["obsidian"].Obsidian.TagPrefix; tagPrefix != "" {
+			prefix = strings.TrimSuffix(tagPrefix, "/") + "/source"
+		}
+	}
+
+	if prefix == "" {
+		prefix = "source"
+	}
+
+	separator = cfg.Sync.SourceTagSeparator
+	if separator == "" {
+		separator = ":"
+		if strings.Contains(prefix, "/") {
+			separator = "/"
+		}
+	}
+
+	return prefix, separator
+}
+
+// transformConfig is the transformer pipeline config for a sync to targetName:
+// cfg.Transformers plus sync.deduplicate_by and, for Obsidian, the target's
+// attachment_folder.
+func transformConfig(cfg *models.Config, targetName string) models.TransformConfig {
+	transformCfg := transform.WithDeduplication(cfg.Transformers, cfg.Sync.DeduplicateBy)
+	if targetName == "obsidian" {
+		transformCfg = transform.WithAttachmentFolder(transformCfg, cfg.Targets["obsidian"].Obsidian.AttachmentFolder)
+	}
+
+	return transformCfg
+}
+
+// recordSyncHistory appends one sync history record per source result.
+func recordSyncHistory(configDir string, results []syncer.SourceResult) {
+	records := make([]state.HistoryRecord, 0, len(results))
+
+	for _, r := range results {
+		record := state.HistoryRecord{
+			Source:    r.Name,
+			StartedAt: r.Started,
+			Duration:  r.Duration,
+			Items:     r.ItemCount,
+		}
+
+		if r.Err != nil {
+			record.Error = r.Err.Error()
+		}
+
+		records = append(records, record)
+	}
+
+	if err := state.AppendHistory(configDir, records...); err != nil {
+		fmt.Printf("Warning: failed to record sync history: %v\n", err)
+	}
+}
+
+// recordFailedSyncHistory records every entry of a sync run that failed as a
+// whole (e.g. a sink error) with that error.
+func recordFailedSyncHistory(configDir string, entries []syncer.SourceEntry, started time.Time, syncErr error) {
+	results := make([]syncer.SourceResult, 0, len(entries))
+
+	for _, e := range entries {
+		results = append(results, syncer.SourceResult{
+			Name: e.Name, Err: syncErr, Started: started, Duration: time.Since(started),
+		})
+	}
+
+	recordSyncHistory(configDir, results)
+}
+
+// resolveDeadLetterPath returns sync.dead_letter_path, defaulting to
+// dead-letter.jsonl in the config directory.
+func resolveDeadLetterPath(cfg *models.Config) (string, error) {
+	if cfg.Sync.DeadLetterPath != "" {
+		return cfg.Sync.DeadLetterPath, nil
+	}
+
+	configDir, err := config.GetConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get config directory: %w", err)
+	}
+
+	return filepath.Join(configDir, "dead-letter.jsonl"), nil
+}
+
+// newSignalContext returns a context that is canceled on SIGINT or SIGTERM so a
+// stuck sync can be interrupted cleanly. The caller must call the returned stop.
+func newSignalContext(parent context.Context) (context.Context, context.CancelFunc) {
+	if parent == nil {
+		parent = context.Background()
+	}
+
+	return signal.NotifyContext(parent, os.Interrupt, syscall.SIGTERM)
 }
 
 // parseSinceTime delegates to the unified date parser.
@@ -119,8 +430,28 @@ func parseSinceTime(since string) (time.Time, error) {
 	return parseDateTime(since)
 }
 
+// parseUntilTime parses an --until value, which must be after since. Empty
+// returns the zero time: fetch up to now.
+func parseUntilTime(until string, since time.Time) (time.Time, error) {
+	if until == "" {
+		return time.Time{}, nil
+	}
+
+	t, err := parseDateTime(until)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	if !t.After(since) {
+		return time.Time{}, fmt.Errorf("until (%s) must be after since (%s)",
+			t.Format(time.DateOnly), since.Format(time.DateOnly))
+	}
+
+	return t, nil
+}
+
 // maybeCreateArchiveSink creates an ArchiveSink when archive.enabled is true in config.
-// Returns nil, nil when archive is disabled or source type is not gmail.
+// Returns nil, nil when archive is disabled or no source provides raw messages.
 // The caller must call Close() on non-nil results.
 func maybeCreateArchiveSink(cfg *models.Config, fetcher sinks.RawMessageFetcher) (*sinks.ArchiveSink, error) {
 	if !cfg.Archive.Enabled || fetcher == nil {
@@ -137,14 +468,9 @@ func maybeCreateArchiveSink(cfg *models.Config, fetcher sinks.RawMessageFetcher)
 		emlDir = filepath.Join(configDir, "archive", "eml")
 	}
 
-	dbPath := cfg.Archive.DBPath
-	if dbPath == "" {
-		configDir, err := config.GetConfigDir()
-		if err != nil {
-			return nil, fmt.Errorf("failed to get config directory: %w", err)
-		}
-
-		dbPath = filepath.Join(configDir, "archive.db")
+	dbPath, err := resolveArchiveDBPath(cfg)
+	if err != nil {
+		return nil, err
 	}
 
 	return sinks.NewArchiveSink(sinks.ArchiveSinkConfig{
@@ -155,6 +481,50 @@ func maybeCreateArchiveSink(cfg *models.Config, fetcher sinks.RawMessageFetcher)
 	}, fetcher)
 }
 
+// resolveArchiveDBPath returns archive.db_path, defaulting to archive.db in the
+// config directory.
+func resolveArchiveDBPath(cfg *models.Config) (string, error) {
+	if cfg.Archive.DBPath != "" {
+		return cfg.Archive.DBPath, nil
+	}
+
+	configDir, err := config.GetConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get config directory: %w", err)
+	}
+
+	return filepath.Join(configDir, "archive.db"), nil
+}
+
+// maybeAttachArchiveTruncation enables the target's truncate_at on fileSink
+// when the archive is enabled and its database exists. The caller must call
+// the returned close function.
+func maybeAttachArchiveTruncation(fileSink *sinks.FileSink, targetName string, cfg *models.Config) (func(), error) {
+	maxChars := cfg.Targets[targetName].TruncateAt
+	if maxChars <= 0 || !cfg.Archive.Enabled {
+		return func() {}, nil
+	}
+
+	dbPath, err := resolveArchiveDBPath(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	// Nothing has been archived yet; every note keeps its full content.
+	if _, err := os.Stat(dbPath); errors.Is(err, os.ErrNotExist) {
+		return func() {}, nil
+	}
+
+	store, err := archive.NewStore(dbPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open archive store at %s: %w", dbPath, err)
+	}
+
+	fileSink.SetArchiveTruncation(maxChars, sinks.NewArchiveLocator(store))
+
+	return func() { _ = store.Close() }, nil
+}
+
 // maybeCreateSlackArchiveSink creates a SlackArchiveSink using the fallback chain:
 // explicit dbPath arg (CLI flag) → cfg.Slack.DBPath (config file) → platform default.
 // The caller must call Close() on non-nil results.
@@ -175,17 +545,21 @@ func maybeCreateSlackArchiveSink(dbPath string, cfg *models.Config) (*sinks.Slac
 	return sinks.NewSlackArchiveSink(dbPath)
 }
 
-// gmailFetcherFromEntries returns the first RawMessageFetcher found among the source entries.
-// Returns nil if no Gmail source with an initialized service is found.
-func gmailFetcherFromEntries(entries []syncer.SourceEntry) sinks.RawMessageFetcher {
+// rawMessageFetcherFromEntries returns the first RawMessageFetcher offered by a
+// source that declares ProvidesRawMessages. Returns nil if none is configured.
+func rawMessageFetcherFromEntries(entries []syncer.SourceEntry) sinks.RawMessageFetcher {
 	for _, entry := range entries {
-		gs, ok := entry.Src.(*google.GoogleSource)
+		if !entry.Src.Capabilities().ProvidesRawMessages {
+			continue
+		}
+
+		rs, ok := entry.Src.(interfaces.RawMessageSource)
 		if !ok {
 			continue
 		}
 
-		if svc := gs.GetGmailService(); svc != nil {
-			return svc
+		if fetcher := rs.RawMessageFetcher(); fetcher != nil {
+			return fetcher
 		}
 	}
 
@@ -228,6 +602,23 @@ func resolveVectorDBPath(cfg *models.Config) (string, error) {
 	return filepath.Join(configDir, "vectors.db"), nil
 }
 
+// lastSyncedFor returns the timestamp an incremental sync of sourceName resumes
+// from: its high-water mark in st when one was recorded, otherwise the newest
+// item in vectors.db. A zero time means neither is known.
+func lastSyncedFor(st *state.SyncState, dbPath string, dbPathErr error, sourceName string) (time.Time, error) {
+	if st != nil {
+		if hw := st.HighWater(sourceName); !hw.IsZero() {
+			return hw, nil
+		}
+	}
+
+	if dbPathErr != nil {
+		return time.Time{}, nil
+	}
+
+	return inferLastSynced(dbPath, sourceName)
+}
+
 // inferLastSynced queries vectors.db for the maximum item timestamp recorded
 // for sourceName. Returns zero time when no documents exist for the source yet.
 func inferLastSynced(dbPath, sourceName string) (time.Time, error) {
@@ -302,6 +693,27 @@ func getEnabledDriveSources(cfg *models.Config) []string {
 	return getEnabledSourcesByType(cfg, "google_drive")
 }
 
+// sourceOmitsBody reports whether a source's items are written as stub notes:
+// the source's omit_body when set, else sync.omit_body.
+func sourceOmitsBody(cfg *models.Config, sourceConfig models.SourceConfig) bool {
+	if sourceConfig.OmitBody != nil {
+		return *sourceConfig.OmitBody
+	}
+
+	return cfg.Sync.OmitBody
+}
+
+// setOmitBody applies `sync --include-body`/`--exclude-body` to every source
+// for this run, replacing per-source omit_body settings.
+func setOmitBody(cfg *models.Config, omit bool) {
+	cfg.Sync.OmitBody = omit
+
+	for name, sourceConfig := range cfg.Sources {
+		sourceConfig.OmitBody = nil
+		cfg.Sources[name] = sourceConfig
+	}
+}
+
 // getSourceSubItems returns the identifiable sub-item keys for a source that
 // represent distinct data scopes (project keys, channel IDs, folder IDs, …).
 // Returning a non-empty slice enables sub-item change detection: if the current
@@ -315,6 +727,25 @@ func getSourceSubItems(sourceType string, sourceConfig models.SourceConfig) []st
 	case "jira":
 		items = append(items, sourceConfig.Jira.ProjectKeys...)
 
+	case "notion":
+		items = append(items, sourceConfig.Notion.DatabaseIDs...)
+		items = append(items, sourceConfig.Notion.PageIDs...)
+
+	case "rss":
+		items = append(items, sourceConfig.RSS.FeedURLs...)
+
+	case "github":
+		items = append(items, sourceConfig.GitHub.Repos...)
+		for _, l := range sourceConfig.GitHub.Labels {
+			items = append(items, "label:"+l)
+		}
+
+	case "localfs":
+		items = append(items, sourceConfig.LocalFS.RootDir)
+		for _, ext := range sourceConfig.LocalFS.Extensions {
+			items = append(items, "ext:"+ext)
+		}
+
 	case "servicenow":
 		items = append(items, sourceConfig.ServiceNow.Tables...)
 		if q := sourceConfig.ServiceNow.Query; q != "" {
@@ -360,6 +791,121 @@ func getSourceOutputDirectory(baseOutputDir string, sourceConfig models.SourceCo
 	return baseOutputDir
 }
 
+// groupOutputDirectory returns the directory one runSourceSync call writes the
+// notes of sources into: their common output_subdir, or the base directory
+// when they disagree (agreed is then false).
+func groupOutputDirectory(baseOutputDir string, cfg *models.Config, sources []string) (dir string, agreed bool) {
+	if len(sources) == 0 {
+		return baseOutputDir, true
+	}
+
+	first := getSourceOutputDirectory(baseOutputDir, cfg.Sources[sources[0]])
+
+	for _, name := range sources[1:] {
+		if getSourceOutputDirectory(baseOutputDir, cfg.Sources[name]) != first {
+			return baseOutputDir, false
+		}
+	}
+
+	return first, true
+}
+
+// outputCollision is a directory that several sources write notes into.
+type outputCollision struct {
+	Dir     string
+	Sources []string
+}
+
+// outputPathCollisions finds the directories more than one source of a sync
+// run writes notes into, where notes with the same filename (e.g. a calendar
+// event and a Jira issue both titled "Weekly sync") overwrite each other.
+// typeGroups maps source types to source names as runSync dispatches them;
+// groups that write no notes (Gmail, Slack without export_to_vault) never
+// collide.
+func outputPathCollisions(cfg *models.Config, typeGroups map[string][]string, baseOutputDir string) []outputCollision {
+	bySubdir := make(map[string][]string)
+
+	for sourceType, sources := range typeGroups {
+		if !writesVaultFiles(cfg, sourceType, sources) {
+			continue
+		}
+
+		dir, _ := groupOutputDirectory(baseOutputDir, cfg, sources)
+		bySubdir[dir] = append(bySubdir[dir], sources...)
+	}
+
+	var collisions []outputCollision
+
+	for dir, sources := range bySubdir {
+		if len(sources) < 2 {
+			continue
+		}
+
+		sort.Strings(sources)
+		collisions = append(collisions, outputCollision{Dir: dir, Sources: sources})
+	}
+
+	sort.Slice(collisions, func(i, j int) bool { return collisions[i].Dir < collisions[j].Dir })
+
+	return collisions
+}
+
+// checkOutputPaths warns about each output path collision, or fails the run
+// when strict is set.
+func checkOutputPaths(cfg *models.Config, typeGroups map[string][]string, baseOutputDir string, strict bool) error {
+	collisions := outputPathCollisions(cfg, typeGroups, baseOutputDir)
+	if len(collisions) == 0 {
+		return nil
+	}
+
+	var msgs []string
+
+	for _, c := range collisions {
+		msgs = append(msgs, fmt.Sprintf("sources %s all write notes to %s; notes with the same filename "+
+			"overwrite each other. Give each source its own output_subdir",
+			strings.Join(c.Sources, ", "), c.Dir))
+	}
+
+	if strict {
+		return fmt.Errorf("overlapping output paths: %s", strings.Join(msgs, "; "))
+	}
+
+	for _, msg := range msgs {
+		fmt.Printf("Warning: %s\n", msg)
+	}
+
+	return nil
+}
+
+// writesVaultFiles reports whether a group of sources of sourceType writes
+// notes to the target. Gmail only feeds its archive. Slack writes notes when
+// every source in the group sets export_to_vault, as the group shares its
+// sinks.
+func writesVaultFiles(cfg *models.Config, sourceType string, sources []string) bool {
+	switch sourceType {
+	case "gmail":
+		return false
+	case "slack":
+		return len(sources) > 0 && len(slackVaultExporters(cfg, sources)) == len(sources)
+	default:
+		return true
+	}
+}
+
+// slackVaultExporters returns the sources among sources with Slack
+// export_to_vault set.
+func slackVaultExporters(cfg *models.Config, sources []string) []string {
+	var exporters []string
+
+	for _, name := range sources {
+		if cfg.Sources[name].Slack.ExportToVault {
+			exporters = append(exporters, name)
+		}
+	}
+
+	return exporters
+}
+
 // sourceSyncConfig holds all parameters for running a source-type-specific sync.
 type sourceSyncConfig struct {
 	SourceType   string   // e.g. "gmail", "google_drive"
@@ -368,12 +914,15 @@ type sourceSyncConfig struct {
 	OutputDir    string
 	Since        string // display/default value
 	SinceFlag    string // raw --since CLI flag value (empty = not set by user)
+	Until        string // raw --until CLI flag value (empty = up to now)
 	DefaultLimit int
 	DryRun       bool
 	OutputFormat string
+	PreviewLines int    // summary dry-run: show the first lines of each changed file (0 = off)
 	SourceKind   string // e.g. "Gmail", "Drive" — used in log messages
 	ItemKind     string // e.g. "emails", "documents" — used in success message
 	SlackDBPath  string // override for slack archive DB path (empty = default)
+	ItemID       string // when set, fetch only this item via interfaces.Fetcher
 
 	// SharedVectorSink is an optional pre-created VectorSink shared across concurrent
 	// runSourceSync calls. When set, runSourceSync uses it instead of creating its own
@@ -385,18 +934,101 @@ type sourceSyncConfig struct {
 	// reads from and writes to this state but does NOT save it — the caller owns
 	// the save. When nil, runSourceSync loads and saves its own state.
 	SyncState *state.SyncState
+
+	// Digest is an optional inbox review sink shared across concurrent
+	// runSourceSync calls. When nil and sync.digest.enabled is set,
+	// runSourceSync creates its own.
+	Digest *sinks.DigestSink
+
+	// AttachmentStore is an optional cross-run attachment index shared across
+	// concurrent runSourceSync calls; the caller closes it. When nil and
+	// sync.dedupe_attachments_global is set, runSourceSync opens its own.
+	AttachmentStore *attachments.Store
+
+	// DriveDocIndex is shared by the Drive and Calendar groups of one sync run so
+	// the drive_attachment_links transformer can correlate file IDs across them.
+	DriveDocIndex *transform.DriveDocIndex
+
+	// NoIndex, NoArchive and NoFiles skip the vector, archive (raw message
+	// and Slack) and vault file sinks for this run, whatever the config says.
+	// NoFiles also skips the digest note.
+	NoIndex   bool
+	NoArchive bool
+	NoFiles   bool
+
+	// SyncedPaths, when set, receives the note path of every item synced, so
+	// the post-sync prune (sync.archive_old_files) leaves them alone.
+	SyncedPaths *pathSet
+
+	// Full skips the incremental window inferred from the vector index, so
+	// every source re-fetches its whole default or configured window.
+	Full bool
+
+	// All makes filesystem-backed sources re-read every file instead of
+	// skipping those unchanged since the last run.
+	All bool
+
+	// Summary, when set, receives this group's SyncAll result for the run
+	// summary file. The caller records group errors and writes the file.
+	Summary *syncer.RunSummary
+
+	// Context cancels in-flight source requests when done (e.g. on Ctrl-C).
+	// When nil, runSourceSync installs its own SIGINT/SIGTERM handler.
+	Context context.Context //nolint:containedctx // per-invocation options struct
+}
+
+// incremental reports whether sources without their own since resume from the
+// newest item already indexed for them: none of --since, --until and --full
+// was given.
+func (ssc sourceSyncConfig) incremental() bool {
+	return ssc.SinceFlag == "" && !ssc.Full && ssc.Until == ""
+}
+
+// recordsState reports whether the run updates the sync state: high-water
+// marks, history cursors, sub-items and file indexes. A single-item resync
+// does not, since the item's age says nothing about what the source has been
+// synced up to, and neither does a run skipping a sink (--no-files,
+// --no-index, --no-archive): the next full run must still write the items
+// that sink missed.
+func (ssc sourceSyncConfig) recordsState() bool {
+	return ssc.ItemID == "" && !ssc.NoFiles && !ssc.NoIndex && !ssc.NoArchive
 }
 
 // runSourceSync executes the full sync pipeline for a specific source type.
 // It is the shared implementation used by the gmail, drive, slack, and sync commands.
 func runSourceSync(cfg *models.Config, ssc sourceSyncConfig) error {
+	ctx := ssc.Context
+	if ctx == nil {
+		var stop context.CancelFunc
+
+		ctx, stop = newSignalContext(context.Background())
+		defer stop()
+	}
+
+	// cfg is shared with concurrent groups, so the override goes on a copy.
+	if ssc.NoArchive && cfg.Archive.Enabled {
+		runCfg := *cfg
+		runCfg.Archive.Enabled = false
+		cfg = &runCfg
+	}
+
 	defaultSinceTime, err := parseSinceTime(ssc.Since)
 	if err != nil {
 		return fmt.Errorf("invalid since parameter: %w", err)
 	}
 
+	untilTime, err := parseUntilTime(ssc.Until, defaultSinceTime)
+	if err != nil {
+		return fmt.Errorf("invalid until parameter: %w", err)
+	}
+
+	window := ssc.Since
+	if ssc.Until != "" {
+		window += ", until: " + ssc.Until
+	}
+
 	fmt.Printf("Syncing %s from sources [%s] to %s (output: %s, since: %s)\n",
-		ssc.SourceKind, strings.Join(ssc.Sources, ", "), ssc.TargetName, ssc.OutputDir, ssc.Since)
+		ssc.SourceKind, strings.Join(ssc.Sources, ", "), ssc.TargetName, ssc.OutputDir, window)
 
 	// Resolve the vector DB path for incremental since-time inference and for
 	// sub-item state tracking.
@@ -422,11 +1054,26 @@ func runSourceSync(cfg *models.Config, ssc sourceSyncConfig) error {
 		ownedState = true
 	}
 
+	// Use the shared attachment index when the caller provides one, otherwise
+	// open a dedicated one for single-source commands.
+	attachmentStore := ssc.AttachmentStore
+	if attachmentStore == nil {
+		if attachmentStore = openAttachmentStore(cfg); attachmentStore != nil {
+			defer attachmentStore.Close()
+		}
+	}
+
 	entries := make([]syncer.SourceEntry, 0, len(ssc.Sources))
 	// sourceSubItems maps each source name to its current config sub-items
 	// (project keys, channel IDs, etc.). Populated during entry building and
 	// used after the sync to persist the current set in state.
 	sourceSubItems := make(map[string][]string, len(ssc.Sources))
+	// historyTrackers holds the sources that resume from a provider change
+	// cursor, so the cursor each one reached can be saved after the sync.
+	historyTrackers := make(map[string]historyTracker)
+	// fileIndexes holds the file index of each filesystem-backed source; they
+	// are saved only after a successful, non-dry run.
+	fileIndexes := make(map[string]*state.FileIndex)
 
 	for _, srcName := range ssc.Sources {
 		sourceConfig, exists := cfg.Sources[srcName]
@@ -448,6 +1095,11 @@ func runSourceSync(cfg *models.Config, ssc sourceSyncConfig) error {
 			continue
 		}
 
+		// Sources without their own request_timeout inherit the app-wide value.
+		if sourceConfig.RequestTimeout == 0 {
+			sourceConfig.RequestTimeout = cfg.App.RequestTimeout
+		}
+
 		src, err := createSourceWithConfig(srcName, sourceConfig, nil)
 		if err != nil {
 			fmt.Printf("Warning: failed to create %s source '%s': %v, skipping\n", ssc.SourceKind, srcName, err)
@@ -455,7 +1107,49 @@ func runSourceSync(cfg *models.Config, ssc sourceSyncConfig) error {
 			continue
 		}
 
-		entry := syncer.SourceEntry{Name: srcName, Src: src}
+		attachGmailCache(cfg, srcName, src)
+		attachGmailFilesDir(srcName, sourceConfig, src)
+
+		if setter, ok := src.(fileIndexSetter); ok && configDirErr == nil && ssc.ItemID == "" {
+			idx := state.NewFileIndex()
+			if !ssc.All {
+				if idx, err = state.LoadFileIndex(configDir, srcName); err != nil {
+					fmt.Printf("Warning: %s: %v, re-reading every file\n", srcName, err)
+
+					idx = state.NewFileIndex()
+				}
+			}
+
+			setter.SetFileIndex(idx)
+			fileIndexes[srcName] = idx
+		}
+
+		if setter, ok := src.(attachmentStoreSetter); ok && attachmentStore != nil {
+			setter.SetAttachmentStore(attachmentStore)
+		}
+
+		// A single-item resync skips the incremental window and sub-item
+		// bookkeeping: the item is fetched by ID regardless of its age.
+		if ssc.ItemID != "" {
+			single, err := newSingleItemSource(src, ssc.ItemID)
+			if err != nil {
+				fmt.Printf("Warning: %v, skipping\n", err)
+
+				continue
+			}
+
+			entries = append(entries, syncer.SourceEntry{
+				Name: srcName, Src: single, OmitBody: sourceOmitsBody(cfg, sourceConfig),
+			})
+
+			continue
+		}
+
+		entry := syncer.SourceEntry{
+			Name: srcName, Src: src,
+			OmitBody:      sourceOmitsBody(cfg, sourceConfig),
+			OnEmptyResult: sourceConfig.OnEmptyResult,
+		}
 
 		// Record current sub-items for post-sync state update.
 		currentSubItems := getSourceSubItems(ssc.SourceType, sourceConfig)
@@ -471,12 +1165,21 @@ func runSourceSync(cfg *models.Config, ssc sourceSyncConfig) error {
 			}
 		}
 
-		// Fall back to data-inferred incremental since when no explicit CLI or
-		// config per-source override is set. We query vectors.db for the maximum
-		// item timestamp already stored for this source — anchoring the window to
-		// the actual data rather than to the wall-clock time of a previous sync.
-		if entry.Since.IsZero() && ssc.SinceFlag == "" && vectorDBPathErr == nil {
-			if lastSynced, err := inferLastSynced(vectorDBPath, srcName); err != nil {
+		if !untilTime.IsZero() && !untilTime.After(entry.Since) && !entry.Since.IsZero() {
+			fmt.Printf("Warning: since of source '%s' is not before --until, skipping\n", srcName)
+
+			continue
+		}
+
+		// Fall back to an incremental since when no explicit CLI or config
+		// per-source override is set: the high-water mark recorded in state by
+		// the last successful sync, or, for sources synced before marks were
+		// recorded, the maximum item timestamp already stored in vectors.db.
+		// Either anchors the window to the actual data rather than to the
+		// wall-clock time of a previous sync.
+		if entry.Since.IsZero() && ssc.incremental() {
+			lastSynced, err := lastSyncedFor(syncState, vectorDBPath, vectorDBPathErr, srcName)
+			if err != nil {
 				fmt.Printf("  → %s: could not infer last sync time: %v; using default window\n", srcName, err)
 			} else if !lastSynced.IsZero() {
 				entry.Since = lastSynced.Add(-state.SinceOverlap)
@@ -489,14 +1192,29 @@ func runSourceSync(cfg *models.Config, ssc sourceSyncConfig) error {
 		// (e.g. a newly added Jira project or Slack channel) have no history in
 		// the incremental window, so we reset to zero and let the source fetch
 		// from the full default lookback window instead.
+		newSubItems := false
+
 		if !entry.Since.IsZero() && syncState != nil {
 			if newItems := syncState.NewSubItems(srcName, currentSubItems); len(newItems) > 0 {
 				entry.Since = time.Time{} // zero → use defaultSinceTime
+				newSubItems = true
 
 				fmt.Printf("  → %s: new sub-items %v detected, using full lookback window\n", srcName, newItems)
 			}
 		}
 
+		// Sources with a change cursor (Gmail's history ID) resume from it under
+		// the same conditions as the inferred since; they record a fresh cursor
+		// either way, except for a backfill window, which would move it past
+		// mail that arrived after the window.
+		if tracker, ok := src.(historyTracker); ok && syncState != nil && untilTime.IsZero() {
+			historyTrackers[srcName] = tracker
+
+			if ssc.incremental() && sourceConfig.Since == "" && !newSubItems {
+				tracker.SetStartHistoryID(syncState.HistoryID(srcName))
+			}
+		}
+
 		// Per-source limit (cap at 2500).
 		if sourceConfig.Google.MaxResults > 0 {
 			if sourceConfig.Google.MaxResults > 2500 {
@@ -516,46 +1234,96 @@ func runSourceSync(cfg *models.Config, ssc sourceSyncConfig) error {
 	}
 
 	// Apply output_subdir: use the common subdir if all sources agree, else warn and use base dir.
-	effectiveOutputDir := ssc.OutputDir
-	if len(entries) == 1 {
-		effectiveOutputDir = getSourceOutputDirectory(ssc.OutputDir, cfg.Sources[entries[0].Name])
-	} else {
-		first := getSourceOutputDirectory(ssc.OutputDir, cfg.Sources[entries[0].Name])
-		allSame := true
-
-		for _, e := range entries[1:] {
-			if getSourceOutputDirectory(ssc.OutputDir, cfg.Sources[e.Name]) != first {
-				allSame = false
-
-				break
-			}
-		}
+	entryNames := make([]string, len(entries))
+	for i, e := range entries {
+		entryNames[i] = e.Name
+	}
 
-		if allSame {
-			effectiveOutputDir = first
-		} else {
-			fmt.Printf("Warning: sources have different output_subdir settings; using base output dir %s\n", ssc.OutputDir)
+	effectiveOutputDir, agreed := groupOutputDirectory(ssc.OutputDir, cfg, entryNames)
+	if !agreed {
+		fmt.Printf("Warning: sources have different output_subdir settings; using base output dir %s\n", ssc.OutputDir)
+	}
+
+	// Gmail uses archive sinks only; Slack exports to the vault with export_to_vault.
+	var (
+		fileSink  *sinks.FileSink
+		jsonlSink *sinks.JSONLSink
+	)
+
+	writesFiles := writesVaultFiles(cfg, ssc.SourceType, entryNames) && !ssc.NoFiles
+	if ssc.SourceType == "slack" && !writesFiles && !ssc.NoFiles {
+		if exporters := slackVaultExporters(cfg, entryNames); len(exporters) > 0 {
+			fmt.Printf("Warning: only Slack sources [%s] set export_to_vault; sync them on their own to write notes\n",
+				strings.Join(exporters, ", "))
 		}
 	}
 
-	// Slack and Gmail use archive sinks only — no file export to vault.
-	var fileSink *sinks.FileSink
-	if ssc.SourceType != "slack" && ssc.SourceType != "gmail" {
+	if writesFiles && isJSONLTarget(cfg, ssc.TargetName) {
+		jsonlSink = sinks.NewJSONLSink(effectiveOutputDir, cfg.Targets[ssc.TargetName].JSONL)
+	} else if writesFiles {
 		fileSink, err = createFileSinkWithConfig(ssc.TargetName, effectiveOutputDir, cfg)
 		if err != nil {
 			return fmt.Errorf("failed to create sink: %w", err)
 		}
+
+		closeArchive, err := maybeAttachArchiveTruncation(fileSink, ssc.TargetName, cfg)
+		if err != nil {
+			return err
+		}
+		defer closeArchive()
 	}
 
-	var sinksSlice []interfaces.Sink
+	var (
+		sinksSlice []interfaces.Sink
+		vaultSink  interfaces.Sink
+		previewer  filePreviewer
+	)
+
 	if fileSink != nil {
-		sinksSlice = append(sinksSlice, fileSink)
+		vaultSink = fileSink
+	} else if jsonlSink != nil {
+		vaultSink = jsonlSink
+	}
+
+	// Sources with their own output_target write through that target instead.
+	var router *targetRouter
+
+	if vaultSink != nil {
+		bySource, closeTargets, err := sourceTargetSinks(cfg, ssc.TargetName, effectiveOutputDir, entryNames)
+		if err != nil {
+			return err
+		}
+		defer closeTargets()
+
+		if len(bySource) > 0 {
+			router = &targetRouter{group: vaultSink, bySource: bySource}
+			vaultSink = router
+		}
+
+		sinksSlice = append(sinksSlice, vaultSink)
+		previewer, _ = vaultSink.(filePreviewer)
+	}
+
+	digest := ssc.Digest
+	if digest == nil && cfg.Sync.Digest.Enabled && !ssc.NoFiles {
+		digest, err = sinks.NewDigestSink(ssc.TargetName, ssc.OutputDir, cfg.Sync.Digest)
+		if err != nil {
+			return fmt.Errorf("failed to create digest sink: %w", err)
+		}
+	}
+
+	if digest != nil {
+		if fileSink != nil {
+			sinksSlice = append(sinksSlice, digest.For(fileSink))
+		} else {
+			sinksSlice = append(sinksSlice, digest)
+		}
 	}
 
 	// Use a shared VectorSink when one is provided (concurrent sync command),
 	// otherwise create a dedicated one for single-source commands.
 	vectorSink := ssc.SharedVectorSink
-	if vectorSink == nil {
+	if vectorSink == nil && !ssc.NoIndex {
 		vectorSink, err = createVectorSink(cfg)
 		if err != nil {
 			return fmt.Errorf("failed to create vector sink: %w", err)
@@ -568,9 +1336,9 @@ func runSourceSync(cfg *models.Config, ssc sourceSyncConfig) error {
 		sinksSlice = append(sinksSlice, vectorSink)
 	}
 
-	// Wire ArchiveSink for Gmail sources when archive is enabled.
-	if ssc.SourceType == "gmail" && cfg.Archive.Enabled {
-		archiveSink, archiveErr := maybeCreateArchiveSink(cfg, gmailFetcherFromEntries(entries))
+	// Wire ArchiveSink for sources that provide raw messages when archive is enabled.
+	if cfg.Archive.Enabled {
+		archiveSink, archiveErr := maybeCreateArchiveSink(cfg, rawMessageFetcherFromEntries(entries))
 		if archiveErr != nil {
 			return fmt.Errorf("failed to create archive sink: %w", archiveErr)
 		}
@@ -582,8 +1350,12 @@ func runSourceSync(cfg *models.Config, ssc sourceSyncConfig) error {
 		}
 	}
 
+	if ssc.SourceType == "google_calendar" && cfg.Sync.ICSExport.Enabled {
+		sinksSlice = append(sinksSlice, sinks.NewICSSink(ssc.OutputDir, cfg.Sync.ICSExport))
+	}
+
 	// Wire SlackArchiveSink for Slack sources.
-	if ssc.SourceType == "slack" {
+	if ssc.SourceType == "slack" && !ssc.NoArchive {
 		slackArchiveSink, slackErr := maybeCreateSlackArchiveSink(ssc.SlackDBPath, cfg)
 		if slackErr != nil {
 			return fmt.Errorf("failed to create slack archive sink: %w", slackErr)
@@ -596,6 +1368,10 @@ func runSourceSync(cfg *models.Config, ssc sourceSyncConfig) error {
 
 	pipeline := transform.NewPipeline()
 	for _, t := range transform.GetAllContentProcessingTransformers() {
+		if links, ok := t.(*transform.DriveAttachmentLinksTransformer); ok && ssc.DriveDocIndex != nil {
+			links.SetDriveDocIndex(ssc.DriveDocIndex)
+		}
+
 		if err := pipeline.AddTransformer(t); err != nil {
 			return fmt.Errorf("failed to add transformer %s: %w", t.Name(), err)
 		}
@@ -603,37 +1379,72 @@ func runSourceSync(cfg *models.Config, ssc sourceSyncConfig) error {
 
 	s := syncer.NewMultiSyncer(pipeline)
 
-	// Enable source tags when auto-indexing so VectorSink can extract source names for dedup
-	sourceTags := cfg.Sync.SourceTags || vectorSink != nil
+	// Source tags stay on whenever the VectorSink runs, as before; the sink
+	// itself reads source names from metadata, so the tag format is free.
+	// Skipping the index for one run leaves the notes' tags unchanged.
+	sourceTags := cfg.Sync.SourceTags || vectorSink != nil || ssc.NoIndex
+	tagPrefix, tagSeparator := sourceTagFormat(cfg, ssc.TargetName)
+
+	syncStarted := time.Now()
 
 	syncResult, err := s.SyncAll(
-		context.Background(),
+		ctx,
 		entries,
 		sinksSlice,
 		syncer.MultiSyncOptions{
 			DefaultSince: defaultSinceTime,
+			DefaultUntil: untilTime,
 			DefaultLimit: ssc.DefaultLimit,
 			SourceTags:   sourceTags,
-			TransformCfg: cfg.Transformers,
+			TransformCfg: transformConfig(cfg, ssc.TargetName),
 			DryRun:       ssc.DryRun,
+			Concurrency:  cfg.Sync.FetchConcurrency,
+
+			SourceTagPrefix:    tagPrefix,
+			SourceTagSeparator: tagSeparator,
+
+			UseState: syncState != nil && ssc.recordsState(),
+			State:    syncState,
 		},
 	)
 	if err != nil {
+		if !ssc.DryRun && configDirErr == nil {
+			recordFailedSyncHistory(configDir, entries, syncStarted, err)
+		}
+
 		return fmt.Errorf("sync failed: %w", err)
 	}
 
+	if ssc.Summary != nil {
+		ssc.Summary.AddResult(ssc.SourceKind, syncResult)
+	}
+
+	if notes := notePather(fileSink, router); ssc.SyncedPaths != nil && notes != nil {
+		for _, item := range syncResult.Items {
+			if path, err := notes.PathFor(item); err == nil {
+				ssc.SyncedPaths.Add(path)
+			}
+		}
+	}
+
+	if !ssc.DryRun && configDirErr == nil {
+		recordSyncHistory(configDir, syncResult.SourceResults)
+	}
+
+	if ssc.DryRun && ssc.ItemID != "" {
+		return printFetchedItems(syncResult.Items)
+	}
+
 	if ssc.DryRun {
-		return handleDryRun(ssc, fileSink, syncResult.Items, cfg)
+		return handleDryRun(ssc, previewer, syncResult.Items, cfg)
 	}
 
 	// Update sub-item membership in state for each successfully synced source.
-	// Timestamps are NOT stored here — they are inferred at the next sync by
-	// querying vectors.db (MAX(updated_at) per source_name), which is always
-	// written by the VectorSink.
-	if syncState == nil {
+	// SyncAll already recorded the high-water marks (MultiSyncOptions.UseState).
+	if syncState == nil || !ssc.recordsState() {
 		fmt.Printf("Successfully exported %d %s\n", len(syncResult.Items), ssc.ItemKind)
 
-		return nil
+		return emptyResultError(syncResult.SourceResults)
 	}
 
 	for _, r := range syncResult.SourceResults {
@@ -644,6 +1455,16 @@ func runSourceSync(cfg *models.Config, ssc sourceSyncConfig) error {
 		if subItems, ok := sourceSubItems[r.Name]; ok {
 			syncState.UpdateSubItems(r.Name, subItems)
 		}
+
+		if tracker, ok := historyTrackers[r.Name]; ok {
+			syncState.SetHistoryID(r.Name, tracker.HistoryID())
+		}
+
+		if idx, ok := fileIndexes[r.Name]; ok {
+			if saveErr := idx.Save(configDir, r.Name); saveErr != nil {
+				fmt.Printf("Warning: failed to save file index for %s: %v\n", r.Name, saveErr)
+			}
+		}
 	}
 
 	// Save only when we own the state (individual command path).
@@ -656,11 +1477,52 @@ func runSourceSync(cfg *models.Config, ssc sourceSyncConfig) error {
 
 	fmt.Printf("Successfully exported %d %s\n", len(syncResult.Items), ssc.ItemKind)
 
+	return emptyResultError(syncResult.SourceResults)
+}
+
+// emptyResultError fails the run when a source with on_empty_result: error
+// returned nothing. It is checked only after the other sources were written.
+func emptyResultError(results []syncer.SourceResult) error {
+	var errs []error
+
+	for _, r := range results {
+		if errors.Is(r.Err, syncer.ErrEmptyResult) {
+			errs = append(errs, r.Err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// notePather returns what resolves the note paths a group writes: the router
+// when sources override the target, else the group's FileSink, else nil.
+func notePather(fileSink *sinks.FileSink, router *targetRouter) interface {
+	PathFor(item models.FullItem) (string, error)
+} {
+	if router != nil {
+		return router
+	}
+
+	if fileSink != nil {
+		return fileSink
+	}
+
 	return nil
 }
 
+// filePreviewer is a sink that can describe the files a write would change.
+type filePreviewer interface {
+	Preview(items []models.FullItem) ([]*interfaces.FilePreview, error)
+}
+
+// previewLineSetter is a filePreviewer that can include the first lines of
+// each file's content in its previews.
+type previewLineSetter interface {
+	SetPreviewLines(n int)
+}
+
 // handleDryRun prints a dry-run summary appropriate for the source type.
-func handleDryRun(ssc sourceSyncConfig, fileSink *sinks.FileSink, items []models.FullItem, cfg *models.Config) error {
+func handleDryRun(ssc sourceSyncConfig, previewer filePreviewer, items []models.FullItem, cfg *models.Config) error {
 	if ssc.SourceType == "slack" {
 		dbPath := ssc.SlackDBPath
 		if dbPath == "" && cfg != nil {
@@ -674,7 +1536,10 @@ func handleDryRun(ssc sourceSyncConfig, fileSink *sinks.FileSink, items []models
 
 		printSlackDryRunSummary(items, dbPath)
 
-		return nil
+		// With export_to_vault the notes are previewed as for other sources.
+		if previewer == nil {
+			return nil
+		}
 	}
 
 	if ssc.SourceType == "gmail" {
@@ -685,7 +1550,19 @@ func handleDryRun(ssc sourceSyncConfig, fileSink *sinks.FileSink, items []models
 		return nil
 	}
 
-	previews, err := fileSink.Preview(items)
+	if previewer == nil {
+		fmt.Printf("Would write no files for %d %s (--no-files)\n", len(items), ssc.ItemKind)
+
+		return nil
+	}
+
+	items = sortedForPreview(items)
+
+	if setter, ok := previewer.(previewLineSetter); ok && ssc.OutputFormat == "summary" {
+		setter.SetPreviewLines(ssc.PreviewLines)
+	}
+
+	previews, err := previewer.Preview(items)
 	if err != nil {
 		return fmt.Errorf("failed to generate preview: %w", err)
 	}
@@ -700,6 +1577,29 @@ func handleDryRun(ssc sourceSyncConfig, fileSink *sinks.FileSink, items []models
 	}
 }
 
+// sortedForPreview returns items ordered by source name, then item ID, so a
+// dry-run preview reads the same however the sources' fetches interleaved.
+func sortedForPreview(items []models.FullItem) []models.FullItem {
+	sorted := append([]models.FullItem(nil), items...)
+
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if a, b := itemSourceName(sorted[i]), itemSourceName(sorted[j]); a != b {
+			return a < b
+		}
+
+		return sorted[i].GetID() < sorted[j].GetID()
+	})
+
+	return sorted
+}
+
+// itemSourceName is the configured source name SyncAll stamped on item.
+func itemSourceName(item models.FullItem) string {
+	name, _ := item.GetMetadata()[models.MetadataSourceName].(string)
+
+	return name
+}
+
 // DryRunOutput is the complete JSON output structure for dry-run mode.
 type DryRunOutput struct {
 	Target       string                    `json:"target"`
@@ -716,6 +1616,7 @@ type DryRunSummary struct {
 	CreateCount   int `json:"create_count"`
 	UpdateCount   int `json:"update_count"`
 	SkipCount     int `json:"skip_count"`
+	AppendCount   int `json:"append_count"`
 	ConflictCount int `json:"conflict_count"`
 }
 
@@ -753,6 +1654,10 @@ func outputDryRunSummary(items []models.FullItem, previews []*interfaces.FilePre
 	fmt.Printf("  ✏️  %d files would be updated\n", summary.UpdateCount)
 	fmt.Printf("  ⏭️  %d files would be skipped (no changes)\n", summary.SkipCount)
 
+	if summary.AppendCount > 0 {
+		fmt.Printf("  ➕ %d files would be appended to\n", summary.AppendCount)
+	}
+
 	if summary.ConflictCount > 0 {
 		fmt.Printf("  ⚠️  %d files have potential conflicts\n", summary.ConflictCount)
 	}
@@ -769,6 +1674,8 @@ func outputDryRunSummary(items []models.FullItem, previews []*interfaces.FilePre
 			emoji = "✏️"
 		case "skip":
 			emoji = "⏭️"
+		case "append":
+			emoji = "➕"
 		default:
 			emoji = "📝"
 		}
@@ -780,7 +1687,26 @@ func outputDryRunSummary(items []models.FullItem, previews []*interfaces.FilePre
 		fmt.Printf("  %s %s %s\n", emoji, preview.Action, preview.FilePath)
 	}
 
-	fmt.Printf("\nWould you like to see content previews? This will show the first few lines of each file that would be created/updated.\n")
+	shown := false
+
+	for _, preview := range previews {
+		if preview.ContentPreview == "" {
+			continue
+		}
+
+		if !shown {
+			fmt.Printf("\nContent previews:\n")
+
+			shown = true
+		}
+
+		fmt.Printf("\n--- %s (%s) ---\n%s\n", preview.FilePath, preview.Action, preview.ContentPreview)
+	}
+
+	if !shown {
+		fmt.Printf("\nUse --preview-lines N to show the first N lines of each file that would be created or updated.\n")
+	}
+
 	fmt.Printf("Note: Use --format json to see complete data model including full content\n")
 
 	return nil
@@ -797,6 +1723,8 @@ func calculateSummary(previews []*interfaces.FilePreview) DryRunSummary {
 			summary.UpdateCount++
 		case "skip":
 			summary.SkipCount++
+		case "append":
+			summary.AppendCount++
 		}
 
 		if preview.Conflict {
@@ -0,0 +1,119 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"pkm-sync/internal/config"
+	"pkm-sync/internal/state"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	historySource string
+	historyLimit  int
+	historyFormat string
+)
+
+var historyCmd = &cobra.Command{
+	Use:   "history",
+	Short: "Show when each source was synced and what it fetched",
+	Long: `Show the sync history: one line per source per sync run, oldest first,
+with the items fetched, how long the fetch took and any error.
+
+The history is kept in sync-history.jsonl in the config directory.
+
+Examples:
+  pkm-sync history
+  pkm-sync history --source gmail_work --limit 10
+  pkm-sync history --format json`,
+	Args: cobra.NoArgs,
+	RunE: runHistoryCommand,
+}
+
+func init() {
+	rootCmd.AddCommand(historyCmd)
+	historyCmd.Flags().StringVar(&historySource, "source", "", "Only show runs of this source")
+	historyCmd.Flags().IntVar(&historyLimit, "limit", 20, "Show only the most recent N runs (0 = all)")
+	historyCmd.Flags().StringVar(&historyFormat, "format", "table", "Output format (table, json)")
+}
+
+func runHistoryCommand(_ *cobra.Command, _ []string) error {
+	configDir, err := config.GetConfigDir()
+	if err != nil {
+		return fmt.Errorf("failed to get config directory: %w", err)
+	}
+
+	records, err := state.LoadHistory(configDir)
+	if err != nil {
+		return err
+	}
+
+	return printHistory(os.Stdout, filterHistory(records, historySource, historyLimit), historyFormat)
+}
+
+// filterHistory keeps the records of source (all when empty), then the most
+// recent limit of them (all when limit <= 0), still oldest first.
+func filterHistory(records []state.HistoryRecord, source string, limit int) []state.HistoryRecord {
+	if source != "" {
+		kept := make([]state.HistoryRecord, 0, len(records))
+
+		for _, r := range records {
+			if r.Source == source {
+				kept = append(kept, r)
+			}
+		}
+
+		records = kept
+	}
+
+	if limit > 0 && len(records) > limit {
+		records = records[len(records)-limit:]
+	}
+
+	return records
+}
+
+// printHistory writes records as a table or as a JSON array.
+func printHistory(w io.Writer, records []state.HistoryRecord, format string) error {
+	switch format {
+	case "json":
+		if records == nil {
+			records = []state.HistoryRecord{}
+		}
+
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+
+		return enc.Encode(records)
+	case "table":
+	default:
+		return fmt.Errorf("unknown format '%s': supported formats are 'table' and 'json'", format)
+	}
+
+	if len(records) == 0 {
+		_, err := fmt.Fprintln(w, "No sync history recorded yet.")
+
+		return err
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "STARTED\tSOURCE\tITEMS\tDURATION\tSTATUS")
+
+	for _, r := range records {
+		status := "ok"
+		if r.Error != "" {
+			status = "error: " + r.Error
+		}
+
+		fmt.Fprintf(tw, "%s\t%s\t%d\t%s\t%s\n",
+			r.StartedAt.Local().Format("2006-01-02 15:04:05"), r.Source, r.Items, r.Duration.Round(time.Millisecond), status)
+	}
+
+	return tw.Flush()
+}
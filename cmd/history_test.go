@@ -0,0 +1,77 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"pkm-sync/internal/state"
+	syncer "pkm-sync/internal/sync"
+)
+
+func TestRecordSyncHistory_AppendsRunsReadBackInOrder(t *testing.T) {
+	dir := t.TempDir()
+	first := time.Date(2026, 5, 1, 9, 0, 0, 0, time.UTC)
+
+	recordSyncHistory(dir, []syncer.SourceResult{
+		{Name: "gmail_work", ItemCount: 7, Started: first, Duration: 1500 * time.Millisecond},
+		{Name: "slack", Err: errors.New("token expired"), Started: first, Duration: time.Second},
+	})
+	recordSyncHistory(dir, []syncer.SourceResult{
+		{Name: "gmail_work", ItemCount: 2, Started: first.Add(time.Hour), Duration: time.Second},
+	})
+
+	records, err := state.LoadHistory(dir)
+	if err != nil {
+		t.Fatalf("LoadHistory: %v", err)
+	}
+
+	if len(records) != 3 {
+		t.Fatalf("got %d records, want one per source per run", len(records))
+	}
+
+	if records[1].Error != "token expired" || records[2].Items != 2 {
+		t.Errorf("unexpected records: %+v", records)
+	}
+
+	gmail := filterHistory(records, "gmail_work", 0)
+	if len(gmail) != 2 || gmail[0].Items != 7 || gmail[1].Items != 2 {
+		t.Errorf("gmail_work history = %+v, want both runs oldest first", gmail)
+	}
+
+	if latest := filterHistory(records, "", 1); len(latest) != 1 || latest[0].Items != 2 {
+		t.Errorf("--limit 1 = %+v, want only the latest run", latest)
+	}
+
+	var out bytes.Buffer
+	if err := printHistory(&out, records, "table"); err != nil {
+		t.Fatalf("printHistory: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 4 || !strings.HasPrefix(lines[0], "STARTED") {
+		t.Fatalf("table output:\n%s", out.String())
+	}
+
+	if !strings.Contains(lines[1], "gmail_work") || !strings.Contains(lines[1], "1.5s") ||
+		!strings.Contains(lines[2], "error: token expired") {
+		t.Errorf("table rows out of order or incomplete:\n%s", out.String())
+	}
+}
+
+func TestPrintHistory_Empty(t *testing.T) {
+	var out bytes.Buffer
+	if err := printHistory(&out, nil, "json"); err != nil {
+		t.Fatalf("printHistory: %v", err)
+	}
+
+	if strings.TrimSpace(out.String()) != "[]" {
+		t.Errorf("json output = %q, want []", out.String())
+	}
+
+	if err := printHistory(&out, nil, "yaml"); err == nil {
+		t.Error("expected an error for an unknown format")
+	}
+}
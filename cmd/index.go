@@ -3,8 +3,8 @@ package main
 import (
 	"context"
 	"fmt"
-
 	"path/filepath"
+	"time"
 
 	"pkm-sync/internal/config"
 	"pkm-sync/internal/sinks"
@@ -27,6 +27,7 @@ var (
 	indexDelay         int
 	indexMaxContentLen int
 	indexBatchSize     int
+	indexConcurrency   int
 )
 
 var indexCmd = &cobra.Command{
@@ -35,11 +36,19 @@ var indexCmd = &cobra.Command{
 	Long: `Index items from any source into a vector database for semantic search.
 Items are grouped by thread/document and embedded together for better context.
 
+Without --since, each source is indexed from the newest document already in
+the vector database for it (or the last 30 days when it has none), so repeated
+runs only fetch new items. An explicit --since overrides this. Threads that
+are already indexed are skipped unless their content changed (e.g. a thread
+gained messages); --reindex re-embeds everything.
+
 Examples:
+  pkm-sync index --source gmail_work  # Only items newer than what is indexed
   pkm-sync index --source gmail_work --since 30d
   pkm-sync index --type gmail --since 7d --limit 500
   pkm-sync index --type google_calendar --since 30d
-  pkm-sync index --reindex  # Re-index all items from all sources`,
+  pkm-sync index --reindex  # Re-index all items from all sources
+  pkm-sync index --concurrency 4 --delay 0  # Faster indexing with a remote embedding backend`,
 	RunE: runIndexCommand,
 }
 
@@ -47,17 +56,22 @@ func init() {
 	rootCmd.AddCommand(indexCmd)
 	indexCmd.Flags().StringVar(&indexSourceName, "source", "", "Source to index (gmail_work, my_calendar, etc.)")
 	indexCmd.Flags().StringVar(&indexTypeFilter, "type", "", "Filter to source type (gmail, google_calendar, google_drive)")
-	indexCmd.Flags().StringVar(&indexSince, "since", "30d", "Index items since (7d, 2006-01-02, today)")
+	indexCmd.Flags().StringVar(&indexSince, "since", "30d", "Index items since (7d, 2006-01-02, today); default: newest indexed item per source, else 30d")
 	indexCmd.Flags().IntVar(&indexLimit, "limit", 1000, "Maximum number of items to fetch per source")
 	indexCmd.Flags().BoolVar(&indexReindex, "reindex", false, "Re-index already indexed items")
 	indexCmd.Flags().IntVar(&indexDelay, "delay", 200, "Delay between embeddings in milliseconds (prevents Ollama overload)")
 	indexCmd.Flags().IntVar(&indexMaxContentLen, "max-content-length", 30000, "Truncate content to this many characters (0 = no limit)")
-	indexCmd.Flags().IntVar(&indexBatchSize, "batch-size", 1, "Number of documents to embed per batch (>1 uses EmbedBatch for throughput)")
+	indexCmd.Flags().IntVar(&indexBatchSize, "batch-size", 0, "Documents embedded per request (default: 16 for openai, 1 for ollama)")
+	indexCmd.Flags().IntVar(&indexConcurrency, "concurrency", 1, "Number of parallel embedding workers (--delay applies per worker; keep 1 for local Ollama)")
 }
 
 func runIndexCommand(cmd *cobra.Command, args []string) error {
 	ctx := context.Background()
 
+	if indexConcurrency < 1 {
+		return fmt.Errorf("--concurrency must be at least 1, got %d", indexConcurrency)
+	}
+
 	cfg, err := config.LoadConfig()
 	if err != nil {
 		return fmt.Errorf("failed to load config: %w", err)
@@ -97,6 +111,7 @@ func runIndexCommand(cmd *cobra.Command, args []string) error {
 		Delay:         indexDelay,
 		MaxContentLen: indexMaxContentLen,
 		BatchSize:     indexBatchSize,
+		Concurrency:   indexConcurrency,
 		EmbeddingsCfg: cfg.Embeddings,
 	})
 	if err != nil {
@@ -159,6 +174,8 @@ func runIndexCommand(cmd *cobra.Command, args []string) error {
 			if err != nil {
 				return fmt.Errorf("failed to configure source '%s': %w", sourceName, err)
 			}
+
+			attachGmailCache(cfg, sourceName, src)
 		}
 
 		entries = append(entries, syncer.SourceEntry{
@@ -173,14 +190,15 @@ func runIndexCommand(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("no valid sources to index")
 	}
 
-	// Tighten per-source since to the newest already-indexed document so we only
-	// fetch items newer than what's already in vectors.db. Skipped when --reindex
-	// is set (which forces a full re-embed of everything).
-	if !indexReindex {
+	// Without an explicit --since, start each source at its newest
+	// already-indexed document so only newer items are fetched. Skipped when
+	// --reindex is set (which forces a full re-embed of everything).
+	if !indexReindex && !cmd.Flags().Changed("since") {
 		if store, err := vectorstore.NewStore(dbPath, cfg.Embeddings.Dimensions); err == nil {
 			for i, entry := range entries {
-				if newest, err := store.NewestDocumentTimeBySource(entry.Name); err == nil && !newest.IsZero() && newest.After(entry.Since) {
-					entries[i].Since = newest
+				entries[i].Since = incrementalSince(store, entry.Name, entry.Since)
+				if !entries[i].Since.Equal(entry.Since) {
+					fmt.Printf("Indexing %s incrementally since %s\n", entry.Name, entries[i].Since.Format(time.RFC3339))
 				}
 			}
 
@@ -189,8 +207,8 @@ func runIndexCommand(cmd *cobra.Command, args []string) error {
 	}
 
 	// Run sync pipeline: fetch → (no transform) → vector sink
-	// Source tags are required so VectorSink can extract source names
 	s := syncer.NewMultiSyncer(nil) // no transformer pipeline for indexing
+	tagPrefix, tagSeparator := sourceTagFormat(cfg, cfg.Sync.DefaultTarget)
 
 	_, err = s.SyncAll(
 		ctx,
@@ -198,8 +216,11 @@ func runIndexCommand(cmd *cobra.Command, args []string) error {
 		[]interfaces.Sink{vectorSink},
 		syncer.MultiSyncOptions{
 			DefaultLimit: indexLimit,
-			SourceTags:   true, // VectorSink needs "source:<name>" tags for dedup
+			SourceTags:   true,
 			TransformCfg: models.TransformConfig{Enabled: false},
+
+			SourceTagPrefix:    tagPrefix,
+			SourceTagSeparator: tagSeparator,
 		},
 	)
 	if err != nil {
@@ -233,3 +254,21 @@ func runIndexCommand(cmd *cobra.Command, args []string) error {
 
 	return nil
 }
+
+// newestIndexedLookup is the part of vectorstore.Store incremental indexing
+// needs.
+type newestIndexedLookup interface {
+	NewestDocumentTimeBySource(sourceName string) (time.Time, error)
+}
+
+// incrementalSince returns the time to index sourceName from when --since is
+// not given: the newest document already indexed for it, or fallback when it
+// has none (or the lookup fails).
+func incrementalSince(store newestIndexedLookup, sourceName string, fallback time.Time) time.Time {
+	newest, err := store.NewestDocumentTimeBySource(sourceName)
+	if err != nil || newest.IsZero() {
+		return fallback
+	}
+
+	return newest
+}
@@ -0,0 +1,135 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"pkm-sync/internal/config"
+	"pkm-sync/internal/vectorstore"
+
+	"github.com/spf13/cobra"
+)
+
+var indexCompactBackupDir string
+
+var indexCompactCmd = &cobra.Command{
+	Use:   "compact",
+	Short: "Back up and compact the vector database",
+	Long: `Rebuild the vector database with VACUUM to reclaim the space left by
+deleted and re-indexed documents. A timestamped backup copy is written first
+(next to the database unless --backup-dir is given), and the size before and
+after is reported.
+
+Examples:
+  pkm-sync index compact
+  pkm-sync index compact --backup-dir ~/backups`,
+	Args: cobra.NoArgs,
+	RunE: runIndexCompactCommand,
+}
+
+func init() {
+	indexCmd.AddCommand(indexCompactCmd)
+	indexCompactCmd.Flags().StringVar(&indexCompactBackupDir, "backup-dir", "",
+		"Directory for the backup copy (default: the database's directory)")
+}
+
+func runIndexCompactCommand(_ *cobra.Command, _ []string) error {
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	dbPath, err := resolveVectorDBPath(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to resolve vector DB path: %w", err)
+	}
+
+	result, err := compactVectorDB(dbPath, cfg.Embeddings.Dimensions, indexCompactBackupDir, time.Now())
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Backup written to %s\n", result.backupPath)
+	fmt.Printf("Compacted %s: %s -> %s\n", dbPath, formatByteSize(result.before), formatByteSize(result.after))
+
+	return nil
+}
+
+// compactResult reports what compactVectorDB did.
+type compactResult struct {
+	backupPath    string
+	before, after int64
+}
+
+// compactVectorDB backs dbPath up to backupDir (default: its own directory)
+// as <name>-<timestamp>.db and then compacts it in place.
+func compactVectorDB(dbPath string, dimensions int, backupDir string, now time.Time) (compactResult, error) {
+	if _, err := os.Stat(dbPath); err != nil {
+		return compactResult{}, fmt.Errorf("vector database not available: %w", err)
+	}
+
+	if backupDir == "" {
+		backupDir = filepath.Dir(dbPath)
+	}
+
+	if err := os.MkdirAll(backupDir, 0755); err != nil {
+		return compactResult{}, fmt.Errorf("failed to create backup directory %s: %w", backupDir, err)
+	}
+
+	base := strings.TrimSuffix(filepath.Base(dbPath), filepath.Ext(dbPath))
+	result := compactResult{
+		backupPath: filepath.Join(backupDir, fmt.Sprintf("%s-%s.db", base, now.Format("20060102-150405"))),
+		before:     sqliteFileSize(dbPath),
+	}
+
+	store, err := vectorstore.NewStore(dbPath, dimensions)
+	if err != nil {
+		return compactResult{}, fmt.Errorf("failed to open vector database: %w", err)
+	}
+	defer store.Close()
+
+	if err := store.Backup(result.backupPath); err != nil {
+		return compactResult{}, err
+	}
+
+	if err := store.Compact(); err != nil {
+		return compactResult{}, fmt.Errorf("backup kept at %s: %w", result.backupPath, err)
+	}
+
+	result.after = sqliteFileSize(dbPath)
+
+	return result, nil
+}
+
+// sqliteFileSize returns the size of a SQLite database and its WAL file.
+func sqliteFileSize(path string) int64 {
+	var total int64
+
+	for _, p := range []string{path, path + "-wal"} {
+		if info, err := os.Stat(p); err == nil {
+			total += info.Size()
+		}
+	}
+
+	return total
+}
+
+// formatByteSize renders n bytes with a binary unit (e.g. "4.2 MiB").
+func formatByteSize(n int64) string {
+	const unit = 1024
+
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+
+	div, exp := int64(unit), 0
+	for v := n / unit; v >= unit; v /= unit {
+		div *= unit
+		exp++
+	}
+
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
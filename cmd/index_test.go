@@ -0,0 +1,116 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"pkm-sync/internal/vectorstore"
+)
+
+func TestIncrementalSince_NewestDocumentPerSource(t *testing.T) {
+	store, err := vectorstore.NewStore(":memory:", 3)
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+	defer store.Close()
+
+	older := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	newest := time.Date(2026, 3, 20, 17, 30, 0, 0, time.UTC)
+	otherSource := time.Date(2026, 4, 2, 8, 0, 0, 0, time.UTC)
+
+	docs := []vectorstore.Document{
+		{SourceID: "a", ThreadID: "t1", SourceType: "gmail", SourceName: "gmail_work", CreatedAt: older, UpdatedAt: older},
+		{SourceID: "b", ThreadID: "t2", SourceType: "gmail", SourceName: "gmail_work", CreatedAt: older, UpdatedAt: newest},
+		{
+			SourceID: "c", ThreadID: "t3", SourceType: "gmail", SourceName: "gmail_home",
+			CreatedAt: otherSource, UpdatedAt: otherSource,
+		},
+	}
+
+	for _, d := range docs {
+		d.Metadata = map[string]interface{}{}
+		if err := store.UpsertDocument(d, []float32{0.1, 0.2, 0.3}); err != nil {
+			t.Fatalf("upsert failed: %v", err)
+		}
+	}
+
+	// Far older than the newest document: the stored date wins, so a source
+	// not indexed for a while is caught up rather than left with a gap.
+	fallback := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	if got := incrementalSince(store, "gmail_work", fallback); !got.Equal(newest) {
+		t.Errorf("gmail_work: expected %v, got %v", newest, got)
+	}
+
+	if got := incrementalSince(store, "gmail_home", fallback); !got.Equal(otherSource) {
+		t.Errorf("gmail_home: expected %v, got %v", otherSource, got)
+	}
+}
+
+func TestIncrementalSince_FallbackWhenNothingIndexed(t *testing.T) {
+	store, err := vectorstore.NewStore(":memory:", 3)
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+	defer store.Close()
+
+	fallback := time.Now().AddDate(0, 0, -30).Truncate(time.Second)
+
+	if got := incrementalSince(store, "gmail_work", fallback); !got.Equal(fallback) {
+		t.Errorf("expected fallback %v, got %v", fallback, got)
+	}
+}
+
+func TestCompactVectorDB_WritesTimestampedBackup(t *testing.T) {
+	dir := t.TempDir()
+	dbPath := filepath.Join(dir, "vectors.db")
+
+	store, err := vectorstore.NewStore(dbPath, 3)
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+
+	doc := vectorstore.Document{
+		SourceID: "a", ThreadID: "t1", SourceName: "gmail_work",
+		CreatedAt: time.Now(), UpdatedAt: time.Now(),
+	}
+	if err := store.UpsertDocument(doc, []float32{1, 0, 0}); err != nil {
+		t.Fatal(err)
+	}
+
+	store.Close()
+
+	backupDir := filepath.Join(dir, "backups")
+	now := time.Date(2026, 5, 1, 8, 15, 0, 0, time.UTC)
+
+	result, err := compactVectorDB(dbPath, 3, backupDir, now)
+	if err != nil {
+		t.Fatalf("compactVectorDB failed: %v", err)
+	}
+
+	if want := filepath.Join(backupDir, "vectors-20260501-081500.db"); result.backupPath != want {
+		t.Errorf("backup path = %q, want %q", result.backupPath, want)
+	}
+
+	if _, err := os.Stat(result.backupPath); err != nil {
+		t.Errorf("backup not written: %v", err)
+	}
+
+	if result.before == 0 || result.after == 0 {
+		t.Errorf("expected sizes to be reported, got %+v", result)
+	}
+
+	if _, err := compactVectorDB(filepath.Join(dir, "missing.db"), 3, "", now); err == nil {
+		t.Error("expected an error for a missing database")
+	}
+}
+
+func TestFormatByteSize(t *testing.T) {
+	for n, want := range map[int64]string{512: "512 B", 1536: "1.5 KiB", 5 << 20: "5.0 MiB"} {
+		if got := formatByteSize(n); got != want {
+			t.Errorf("formatByteSize(%d) = %q, want %q", n, got, want)
+		}
+	}
+}
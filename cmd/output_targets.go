@@ -0,0 +1,183 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"pkm-sync/internal/sinks"
+	"pkm-sync/pkg/interfaces"
+	"pkm-sync/pkg/models"
+)
+
+// sourceTargetSinks returns the FileSink of each source in sources whose
+// output_target names a target other than groupTarget, keyed by source name.
+// Sources sharing a target share its sink, so each target is built once. An
+// output_target that cannot be built (an unknown name, or a JSONL target) is
+// reported and the source falls back to groupTarget. The returned func closes
+// what the sinks opened.
+func sourceTargetSinks(
+	cfg *models.Config, groupTarget, outputDir string, sources []string,
+) (map[string]*sinks.FileSink, func(), error) {
+	bySource := make(map[string]*sinks.FileSink)
+	byTarget := make(map[string]*sinks.FileSink)
+
+	var closers []func()
+
+	closeAll := func() {
+		for _, c := range closers {
+			c()
+		}
+	}
+
+	for _, name := range sources {
+		targetName := cfg.Sources[name].OutputTarget
+		if targetName == "" || targetName == groupTarget {
+			continue
+		}
+
+		if fileSink, ok := byTarget[targetName]; ok {
+			bySource[name] = fileSink
+
+			continue
+		}
+
+		fileSink, err := createFileSinkWithConfig(targetName, outputDir, cfg)
+		if err != nil {
+			fmt.Printf("Warning: source '%s' has output_target '%s': %v; writing to %s instead\n",
+				name, targetName, err, groupTarget)
+
+			continue
+		}
+
+		closeArchive, err := maybeAttachArchiveTruncation(fileSink, targetName, cfg)
+		if err != nil {
+			closeAll()
+
+			return nil, nil, err
+		}
+
+		closers = append(closers, closeArchive)
+		byTarget[targetName] = fileSink
+		bySource[name] = fileSink
+	}
+
+	return bySource, closeAll, nil
+}
+
+// targetRouter is the vault sink of a group in which some sources set their
+// own output_target: their items go to that target's FileSink, every other
+// item to the group's sink.
+type targetRouter struct {
+	group    interfaces.Sink // the group target's FileSink or JSONLSink
+	bySource map[string]*sinks.FileSink
+}
+
+func (r *targetRouter) Name() string {
+	return r.group.Name()
+}
+
+// Write writes each item through the sink of its source's target.
+func (r *targetRouter) Write(ctx context.Context, items []models.FullItem) error {
+	rest, routed := r.split(items)
+
+	if err := r.group.Write(ctx, rest); err != nil {
+		return err
+	}
+
+	for _, part := range routed {
+		if err := part.sink.Write(ctx, part.items); err != nil {
+			return fmt.Errorf("%s: %w", part.sink.Name(), err)
+		}
+	}
+
+	return nil
+}
+
+// Preview describes the files Write would change, in item order per sink.
+func (r *targetRouter) Preview(items []models.FullItem) ([]*interfaces.FilePreview, error) {
+	rest, routed := r.split(items)
+
+	previewer, ok := r.group.(filePreviewer)
+	if !ok {
+		return nil, fmt.Errorf("sink %s cannot preview files", r.group.Name())
+	}
+
+	previews, err := previewer.Preview(rest)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, part := range routed {
+		partPreviews, err := part.sink.Preview(part.items)
+		if err != nil {
+			return nil, err
+		}
+
+		previews = append(previews, partPreviews...)
+	}
+
+	return previews, nil
+}
+
+// SetPreviewLines sets the preview line count of every sink Preview uses.
+func (r *targetRouter) SetPreviewLines(n int) {
+	if setter, ok := r.group.(previewLineSetter); ok {
+		setter.SetPreviewLines(n)
+	}
+
+	for _, fileSink := range r.bySource {
+		fileSink.SetPreviewLines(n)
+	}
+}
+
+// PathFor returns the note path the item's sink writes it to.
+func (r *targetRouter) PathFor(item models.FullItem) (string, error) {
+	if fileSink, ok := r.bySource[itemSourceName(item)]; ok {
+		return fileSink.PathFor(item)
+	}
+
+	if fileSink, ok := r.group.(*sinks.FileSink); ok {
+		return fileSink.PathFor(item)
+	}
+
+	return "", fmt.Errorf("sink %s writes no note files", r.group.Name())
+}
+
+// routedItems are the items one output_target sink writes.
+type routedItems struct {
+	sink  *sinks.FileSink
+	items []models.FullItem
+}
+
+// split separates the items of sources with their own output_target, grouped
+// by sink in order of first appearance, from the rest.
+func (r *targetRouter) split(items []models.FullItem) ([]models.FullItem, []routedItems) {
+	var (
+		rest   []models.FullItem
+		routed []routedItems
+	)
+
+	index := make(map[*sinks.FileSink]int)
+
+	for _, item := range items {
+		fileSink, ok := r.bySource[itemSourceName(item)]
+		if !ok {
+			rest = append(rest, item)
+
+			continue
+		}
+
+		i, seen := index[fileSink]
+		if !seen {
+			i = len(routed)
+			index[fileSink] = i
+			routed = append(routed, routedItems{sink: fileSink})
+		}
+
+		routed[i].items = append(routed[i].items, item)
+	}
+
+	return rest, routed
+}
+
+var _ interfaces.Sink = (*targetRouter)(nil)
@@ -0,0 +1,121 @@
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"pkm-sync/pkg/models"
+)
+
+func outputTargetConfig(t *testing.T) *models.Config {
+	t.Helper()
+
+	return &models.Config{
+		Sync: models.SyncConfig{DeadLetterPath: filepath.Join(t.TempDir(), "dead-letter.jsonl")},
+		Sources: map[string]models.SourceConfig{
+			"work":     {Type: "google_drive"},
+			"personal": {Type: "google_drive", OutputTarget: "logseq"},
+			"archive":  {Type: "google_drive", OutputTarget: "logseq"},
+			"same":     {Type: "google_drive", OutputTarget: "obsidian"},
+			"typo":     {Type: "google_drive", OutputTarget: "nonexistent_target"},
+		},
+	}
+}
+
+func TestSourceTargetSinks(t *testing.T) {
+	cfg := outputTargetConfig(t)
+
+	bySource, closeTargets, err := sourceTargetSinks(
+		cfg, "obsidian", t.TempDir(), []string{"work", "personal", "archive", "same", "typo"})
+	if err != nil {
+		t.Fatalf("sourceTargetSinks: %v", err)
+	}
+	defer closeTargets()
+
+	if len(bySource) != 2 || bySource["personal"] == nil || bySource["archive"] == nil {
+		t.Fatalf("bySource = %v, want personal and archive only", bySource)
+	}
+
+	if bySource["personal"] != bySource["archive"] {
+		t.Error("sources with the same output_target got separate sinks")
+	}
+
+	if name := bySource["personal"].Name(); name != "logseq" {
+		t.Errorf("personal sink = %s, want logseq", name)
+	}
+}
+
+func TestTargetRouter_WritesEachSourceToItsTarget(t *testing.T) {
+	cfg := outputTargetConfig(t)
+	outputDir := t.TempDir()
+
+	group, err := createFileSinkWithConfig("obsidian", outputDir, cfg)
+	if err != nil {
+		t.Fatalf("createFileSinkWithConfig: %v", err)
+	}
+
+	bySource, closeTargets, err := sourceTargetSinks(cfg, "obsidian", outputDir, []string{"work", "personal"})
+	if err != nil {
+		t.Fatalf("sourceTargetSinks: %v", err)
+	}
+	defer closeTargets()
+
+	router := &targetRouter{group: group, bySource: bySource}
+
+	item := func(source, id string) models.FullItem {
+		it := models.NewBasicItem(id, "Note "+id)
+		it.SetSourceType("google_drive")
+		it.SetMetadata(map[string]any{models.MetadataSourceName: source})
+
+		return it
+	}
+
+	items := []models.FullItem{item("work", "w1"), item("personal", "p1")}
+
+	router.SetPreviewLines(1)
+
+	previews, err := router.Preview(items)
+	if err != nil {
+		t.Fatalf("Preview: %v", err)
+	}
+
+	if len(previews) != 2 {
+		t.Fatalf("got %d previews, want 2", len(previews))
+	}
+
+	for _, preview := range previews {
+		if preview.ContentPreview == "" || strings.Contains(preview.ContentPreview, "\n") {
+			t.Errorf("%s: ContentPreview = %q, want its first line", preview.FilePath, preview.ContentPreview)
+		}
+	}
+
+	if err := router.Write(context.Background(), items); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+
+	for _, it := range items {
+		path, err := router.PathFor(it)
+		if err != nil {
+			t.Fatalf("PathFor(%s): %v", it.GetID(), err)
+		}
+
+		if _, err := os.Stat(path); err != nil {
+			t.Errorf("note for %s not written at %s: %v", it.GetID(), path, err)
+		}
+	}
+
+	workPath, _ := group.PathFor(items[0])
+	personalPath, _ := router.PathFor(items[1])
+	groupPersonalPath, _ := group.PathFor(items[1])
+
+	if got, _ := router.PathFor(items[0]); got != workPath {
+		t.Errorf("work note path = %s, want the obsidian path %s", got, workPath)
+	}
+
+	if personalPath == groupPersonalPath {
+		t.Errorf("personal note path %s is the obsidian path, want the logseq one", personalPath)
+	}
+}
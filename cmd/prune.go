@@ -0,0 +1,309 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"pkm-sync/internal/config"
+	"pkm-sync/internal/sinks"
+	"pkm-sync/pkg/models"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	pruneOutputDir string
+	pruneMaxAge    string
+	pruneDryRun    bool
+	pruneDelete    bool
+)
+
+var pruneCmd = &cobra.Command{
+	Use:   "prune",
+	Short: "Move or delete synced notes older than max_file_age",
+	Long: `Prune the vault: every note pkm-sync wrote (one with an id in its
+frontmatter, or a Logseq id:: property) that has not been modified within
+max_file_age is moved to <app.backup_dir>/pruned/, keeping its path relative
+to the output directory. Notes you wrote yourself are never touched.
+
+Ages accept days, weeks, months and years: "30d", "8w", "6m", "1y".
+
+With sync.archive_old_files set, sync runs the same step after each sync,
+skipping the notes that run synced.
+
+Examples:
+  pkm-sync prune --dry-run
+  pkm-sync prune --max-age 6m
+  pkm-sync prune --max-age 1y --delete`,
+	Args: cobra.NoArgs,
+	RunE: runPruneCommand,
+}
+
+func init() {
+	rootCmd.AddCommand(pruneCmd)
+	pruneCmd.Flags().StringVarP(&pruneOutputDir, "output", "o", "", "Vault directory to prune (default: sync.default_output_dir)")
+	pruneCmd.Flags().StringVar(&pruneMaxAge, "max-age", "", "Prune notes older than this (default: sync.max_file_age)")
+	pruneCmd.Flags().BoolVar(&pruneDryRun, "dry-run", false, "List the notes that would be pruned without touching them")
+	pruneCmd.Flags().BoolVar(&pruneDelete, "delete", false, "Delete old notes instead of moving them to the backup directory")
+}
+
+func runPruneCommand(_ *cobra.Command, _ []string) error {
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	outputDir := pruneOutputDir
+	if outputDir == "" {
+		outputDir = cfg.Sync.DefaultOutputDir
+	}
+
+	maxAge := pruneMaxAge
+	if maxAge == "" {
+		maxAge = cfg.Sync.MaxFileAge
+	}
+
+	if maxAge == "" {
+		return fmt.Errorf("no maximum age: set sync.max_file_age or pass --max-age")
+	}
+
+	cutoff, err := fileAgeCutoff(maxAge, time.Now())
+	if err != nil {
+		return err
+	}
+
+	opts := pruneOptions{Dir: outputDir, Cutoff: cutoff, DryRun: pruneDryRun}
+
+	if !pruneDelete {
+		if opts.BackupDir, err = pruneBackupDir(cfg); err != nil {
+			return err
+		}
+	}
+
+	pruned, err := pruneVault(opts)
+	printPruneResult(opts, pruned)
+
+	return err
+}
+
+// pruneAfterSync moves the notes under outputDir older than sync.max_file_age
+// to the backup directory, except those synced this run. It is a no-op when
+// max_file_age is unset.
+func pruneAfterSync(cfg *models.Config, outputDir string, synced *pathSet, dryRun bool) error {
+	if cfg.Sync.MaxFileAge == "" {
+		return nil
+	}
+
+	cutoff, err := fileAgeCutoff(cfg.Sync.MaxFileAge, time.Now())
+	if err != nil {
+		return err
+	}
+
+	backupDir, err := pruneBackupDir(cfg)
+	if err != nil {
+		return err
+	}
+
+	opts := pruneOptions{Dir: outputDir, Cutoff: cutoff, BackupDir: backupDir, DryRun: dryRun, Keep: synced}
+
+	pruned, err := pruneVault(opts)
+	if len(pruned) > 0 {
+		printPruneResult(opts, pruned)
+	}
+
+	return err
+}
+
+// pruneOptions controls pruneVault.
+type pruneOptions struct {
+	// Dir is the vault directory scanned for old notes.
+	Dir string
+	// Cutoff is the modification time notes must be older than.
+	Cutoff time.Time
+	// BackupDir receives moved notes; empty deletes them instead.
+	BackupDir string
+	// DryRun only lists the notes that would be pruned.
+	DryRun bool
+	// Keep holds notes synced in the current run, which are never pruned
+	// even when their content (and so their mtime) did not change.
+	Keep *pathSet
+}
+
+// pruneVault moves or deletes the synced notes under opts.Dir last modified
+// before opts.Cutoff and returns their paths. Hidden directories (.obsidian,
+// .digest, .trash) are skipped. On error the notes pruned so far are returned.
+func pruneVault(opts pruneOptions) ([]string, error) {
+	var pruned []string
+
+	err := filepath.WalkDir(opts.Dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if d.IsDir() {
+			if path != opts.Dir && strings.HasPrefix(d.Name(), ".") {
+				return filepath.SkipDir
+			}
+
+			return nil
+		}
+
+		if !strings.EqualFold(filepath.Ext(path), ".md") || opts.Keep.Has(path) {
+			return nil
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+
+		if !info.ModTime().Before(opts.Cutoff) || sinks.SyncedNoteID(path) == "" {
+			return nil
+		}
+
+		if !opts.DryRun {
+			if err := pruneFile(opts, path); err != nil {
+				return err
+			}
+		}
+
+		pruned = append(pruned, path)
+
+		return nil
+	})
+	if errors.Is(err, fs.ErrNotExist) && len(pruned) == 0 {
+		return nil, nil
+	}
+
+	return pruned, err
+}
+
+// pruneFile deletes path, or moves it to the same relative path under
+// opts.BackupDir.
+func pruneFile(opts pruneOptions, path string) error {
+	if opts.BackupDir == "" {
+		return os.Remove(path)
+	}
+
+	rel, err := filepath.Rel(opts.Dir, path)
+	if err != nil {
+		return err
+	}
+
+	dest := filepath.Join(opts.BackupDir, rel)
+	if err := os.MkdirAll(filepath.Dir(dest), 0755); err != nil {
+		return fmt.Errorf("failed to create backup directory: %w", err)
+	}
+
+	if err := os.Rename(path, dest); err != nil {
+		return fmt.Errorf("failed to move %s: %w", path, err)
+	}
+
+	return nil
+}
+
+// pruneBackupDir is where pruned notes are moved: <app.backup_dir>/pruned,
+// with the backup directory defaulting to <config dir>/backups.
+func pruneBackupDir(cfg *models.Config) (string, error) {
+	backupDir := cfg.App.BackupDir
+	if backupDir == "" {
+		configDir, err := config.GetConfigDir()
+		if err != nil {
+			return "", fmt.Errorf("failed to get config directory: %w", err)
+		}
+
+		backupDir = filepath.Join(configDir, "backups")
+	}
+
+	return filepath.Join(backupDir, "pruned"), nil
+}
+
+// printPruneResult reports what pruneVault did or, in a dry run, would do.
+func printPruneResult(opts pruneOptions, pruned []string) {
+	verb := "Moved"
+
+	switch {
+	case opts.DryRun:
+		verb = "Would prune"
+	case opts.BackupDir == "":
+		verb = "Deleted"
+	}
+
+	for _, path := range pruned {
+		fmt.Printf("  %s\n", path)
+	}
+
+	if opts.BackupDir != "" && !opts.DryRun {
+		fmt.Printf("%s %d note(s) older than %s to %s\n",
+			verb, len(pruned), opts.Cutoff.Format("2006-01-02"), opts.BackupDir)
+
+		return
+	}
+
+	fmt.Printf("%s %d note(s) older than %s\n", verb, len(pruned), opts.Cutoff.Format("2006-01-02"))
+}
+
+// fileAgeCutoff turns a max_file_age such as "30d", "8w", "6m" or "1y" into
+// the time before which files count as old. Months and years are calendar
+// months and years.
+func fileAgeCutoff(age string, now time.Time) (time.Time, error) {
+	if len(age) < 2 {
+		return time.Time{}, fmt.Errorf("invalid max file age %q: use e.g. 30d, 8w, 6m or 1y", age)
+	}
+
+	n, err := strconv.Atoi(age[:len(age)-1])
+	if err != nil || n <= 0 {
+		return time.Time{}, fmt.Errorf("invalid max file age %q: use e.g. 30d, 8w, 6m or 1y", age)
+	}
+
+	switch age[len(age)-1] {
+	case 'd':
+		return now.AddDate(0, 0, -n), nil
+	case 'w':
+		return now.AddDate(0, 0, -7*n), nil
+	case 'm':
+		return now.AddDate(0, -n, 0), nil
+	case 'y':
+		return now.AddDate(-n, 0, 0), nil
+	default:
+		return time.Time{}, fmt.Errorf("invalid max file age %q: use e.g. 30d, 8w, 6m or 1y", age)
+	}
+}
+
+// pathSet is a set of file paths safe for concurrent use. A nil *pathSet is
+// an empty set.
+type pathSet struct {
+	mu    sync.Mutex
+	paths map[string]bool
+}
+
+func newPathSet() *pathSet {
+	return &pathSet{paths: make(map[string]bool)}
+}
+
+// Add records path.
+func (p *pathSet) Add(path string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.paths[filepath.Clean(path)] = true
+}
+
+// Has reports whether path was recorded.
+func (p *pathSet) Has(path string) bool {
+	if p == nil {
+		return false
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.paths[filepath.Clean(path)]
+}
@@ -0,0 +1,154 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+)
+
+// writeNote writes content to dir/rel with the given modification time.
+func writeNote(t *testing.T, dir, rel, content string, modTime time.Time) string {
+	t.Helper()
+
+	path := filepath.Join(dir, rel)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.Chtimes(path, modTime, modTime); err != nil {
+		t.Fatal(err)
+	}
+
+	return path
+}
+
+func TestPruneVault(t *testing.T) {
+	now := time.Now()
+	old := now.AddDate(-2, 0, 0)
+	synced := "---\nid: item-1\n---\n# Note\n"
+
+	newVault := func(t *testing.T) (string, map[string]string) {
+		t.Helper()
+
+		dir := t.TempDir()
+		paths := map[string]string{
+			"old":     writeNote(t, dir, "2022/old.md", synced, old),
+			"logseq":  writeNote(t, dir, "pages/page.md", "- id:: item-2\n- body\n", old),
+			"recent":  writeNote(t, dir, "recent.md", synced, now),
+			"user":    writeNote(t, dir, "mine.md", "# My own note\n", old),
+			"kept":    writeNote(t, dir, "kept.md", synced, old),
+			"hidden":  writeNote(t, dir, ".trash/gone.md", synced, old),
+			"nonnote": writeNote(t, dir, "data.json", "{}", old),
+		}
+
+		return dir, paths
+	}
+
+	t.Run("dry run lists without touching", func(t *testing.T) {
+		dir, paths := newVault(t)
+		keep := newPathSet()
+		keep.Add(paths["kept"])
+
+		pruned, err := pruneVault(pruneOptions{Dir: dir, Cutoff: now.AddDate(-1, 0, 0), DryRun: true, Keep: keep})
+		if err != nil {
+			t.Fatalf("pruneVault: %v", err)
+		}
+
+		slices.Sort(pruned)
+
+		want := []string{paths["old"], paths["logseq"]}
+		slices.Sort(want)
+
+		if !slices.Equal(pruned, want) {
+			t.Errorf("pruned = %v, want %v", pruned, want)
+		}
+
+		if _, err := os.Stat(paths["old"]); err != nil {
+			t.Errorf("dry run removed %s", paths["old"])
+		}
+	})
+
+	t.Run("moves to backup dir", func(t *testing.T) {
+		dir, paths := newVault(t)
+		backup := t.TempDir()
+
+		if _, err := pruneVault(pruneOptions{Dir: dir, Cutoff: now.AddDate(-1, 0, 0), BackupDir: backup}); err != nil {
+			t.Fatalf("pruneVault: %v", err)
+		}
+
+		if _, err := os.Stat(paths["old"]); !os.IsNotExist(err) {
+			t.Errorf("%s still in the vault", paths["old"])
+		}
+
+		if _, err := os.Stat(filepath.Join(backup, "2022", "old.md")); err != nil {
+			t.Errorf("moved note missing from backup: %v", err)
+		}
+
+		for _, name := range []string{"recent", "user", "hidden", "nonnote"} {
+			if _, err := os.Stat(paths[name]); err != nil {
+				t.Errorf("%s note was pruned: %v", name, err)
+			}
+		}
+	})
+
+	t.Run("deletes without backup dir", func(t *testing.T) {
+		dir, paths := newVault(t)
+
+		pruned, err := pruneVault(pruneOptions{Dir: dir, Cutoff: now.AddDate(-1, 0, 0)})
+		if err != nil {
+			t.Fatalf("pruneVault: %v", err)
+		}
+
+		if len(pruned) != 3 {
+			t.Errorf("pruned %d notes, want 3 (old, logseq, kept)", len(pruned))
+		}
+
+		if _, err := os.Stat(paths["logseq"]); !os.IsNotExist(err) {
+			t.Errorf("%s was not deleted", paths["logseq"])
+		}
+	})
+
+	t.Run("missing vault", func(t *testing.T) {
+		pruned, err := pruneVault(pruneOptions{Dir: filepath.Join(t.TempDir(), "none"), Cutoff: now})
+		if err != nil || len(pruned) != 0 {
+			t.Errorf("got %v, %v; want nothing pruned and no error", pruned, err)
+		}
+	})
+}
+
+func TestFileAgeCutoff(t *testing.T) {
+	now := time.Date(2024, 8, 31, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		age     string
+		want    time.Time
+		wantErr bool
+	}{
+		{age: "30d", want: time.Date(2024, 8, 1, 12, 0, 0, 0, time.UTC)},
+		{age: "2w", want: time.Date(2024, 8, 17, 12, 0, 0, 0, time.UTC)},
+		{age: "6m", want: time.Date(2024, 3, 2, 12, 0, 0, 0, time.UTC)}, // Feb 31 normalizes
+		{age: "1y", want: time.Date(2023, 8, 31, 12, 0, 0, 0, time.UTC)},
+		{age: "0d", wantErr: true},
+		{age: "12h", wantErr: true},
+		{age: "d", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := fileAgeCutoff(tt.age, now)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("fileAgeCutoff(%q) error = %v, wantErr %v", tt.age, err, tt.wantErr)
+
+			continue
+		}
+
+		if !tt.wantErr && !got.Equal(tt.want) {
+			t.Errorf("fileAgeCutoff(%q) = %v, want %v", tt.age, got, tt.want)
+		}
+	}
+}
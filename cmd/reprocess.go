@@ -0,0 +1,232 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"pkm-sync/internal/archive"
+	"pkm-sync/internal/config"
+	"pkm-sync/internal/reprocess"
+	"pkm-sync/internal/sinks"
+	syncer "pkm-sync/internal/sync"
+	"pkm-sync/internal/transform"
+	"pkm-sync/pkg/interfaces"
+	"pkm-sync/pkg/models"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	reprocessArchive    bool
+	reprocessSourceName string
+	reprocessTargetName string
+	reprocessOutputDir  string
+	reprocessLimit      int
+	reprocessDryRun     bool
+	reprocessFormat     string
+)
+
+var reprocessCmd = &cobra.Command{
+	Use:   "reprocess [path]",
+	Short: "Rerun the transformer pipeline over previously exported items",
+	Long: `Re-render previously exported items with the current transformer settings,
+without fetching anything from the source APIs.
+
+Items are read from one of:
+  - a JSONL file (.jsonl/.json), e.g. saved from 'pkm-sync fetch --format json'
+  - a vault directory or note written by the obsidian or logseq target
+  - the Gmail archive (--archive), optionally limited to one --source
+
+The items run through the transformer pipeline from your config and are written
+with the chosen target. Vault input is rewritten in place unless --output is set.
+The vector index is not updated; run 'pkm-sync index' afterwards if needed.
+
+Examples:
+  pkm-sync reprocess ./vault/Gmail
+  pkm-sync reprocess items.jsonl --target obsidian --output ./vault
+  pkm-sync reprocess --archive --source work_gmail --output ./vault/Gmail --dry-run`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runReprocessCommand,
+}
+
+func init() {
+	rootCmd.AddCommand(reprocessCmd)
+	reprocessCmd.Flags().BoolVar(&reprocessArchive, "archive", false, "Read items from the Gmail archive")
+	reprocessCmd.Flags().StringVar(&reprocessSourceName, "source", "", "With --archive, only messages from this source")
+	reprocessCmd.Flags().StringVar(&reprocessTargetName, "target", "", "PKM target (obsidian, logseq)")
+	reprocessCmd.Flags().StringVarP(&reprocessOutputDir, "output", "o", "",
+		"Output directory (default: the vault path, or sync.default_output_dir)")
+	reprocessCmd.Flags().IntVar(&reprocessLimit, "limit", 0, "Maximum number of items to reprocess (0 = all)")
+	reprocessCmd.Flags().BoolVar(&reprocessDryRun, "dry-run", false, "Show what would be rewritten without making changes")
+	reprocessCmd.Flags().StringVar(&reprocessFormat, "format", "summary", "Output format for dry-run (summary, json)")
+}
+
+func runReprocessCommand(_ *cobra.Command, args []string) error {
+	if reprocessArchive == (len(args) == 1) {
+		return fmt.Errorf("specify either a path or --archive")
+	}
+
+	cfg, err := config.LoadConfigOrDefault()
+	if err != nil {
+		return err
+	}
+
+	var (
+		items       []models.FullItem
+		inputName   string
+		inputOutDir string
+	)
+
+	if reprocessArchive {
+		items, err = loadArchiveItems(cfg, reprocessSourceName, reprocessLimit)
+		inputName = "archive"
+	} else {
+		inputName = args[0]
+		items, inputOutDir, err = loadExportedItems(inputName)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if len(items) == 0 {
+		fmt.Println("No previously exported items found")
+
+		return nil
+	}
+
+	targetName := cfg.Sync.DefaultTarget
+	if reprocessTargetName != "" {
+		targetName = reprocessTargetName
+	}
+
+	outputDir := cfg.Sync.DefaultOutputDir
+	if inputOutDir != "" {
+		outputDir = inputOutDir
+	}
+
+	if reprocessOutputDir != "" {
+		outputDir = reprocessOutputDir
+	}
+
+	fileSink, err := createFileSinkWithConfig(targetName, outputDir, cfg)
+	if err != nil {
+		return fmt.Errorf("failed to create sink: %w", err)
+	}
+
+	closeArchive, err := maybeAttachArchiveTruncation(fileSink, targetName, cfg)
+	if err != nil {
+		return err
+	}
+	defer closeArchive()
+
+	ctx, stop := newSignalContext(context.Background())
+	defer stop()
+
+	processed, err := reprocessItems(ctx, cfg, items, fileSink, reprocessLimit, reprocessDryRun)
+	if err != nil {
+		return err
+	}
+
+	if reprocessDryRun {
+		return handleDryRun(sourceSyncConfig{
+			SourceType:   "reprocess",
+			Sources:      []string{inputName},
+			TargetName:   targetName,
+			OutputDir:    outputDir,
+			OutputFormat: reprocessFormat,
+		}, fileSink, processed, cfg)
+	}
+
+	fmt.Printf("Successfully re-exported %d items to %s\n", len(processed), outputDir)
+
+	return nil
+}
+
+// loadExportedItems reads a JSONL file or vault notes. For vault input it also
+// returns the directory the notes live in, so they are rewritten in place.
+func loadExportedItems(path string) ([]models.FullItem, string, error) {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".jsonl", ".json":
+		items, err := reprocess.LoadJSONLFile(path)
+
+		return items, "", err
+	}
+
+	items, err := reprocess.LoadVault(path)
+	if err != nil {
+		return nil, "", err
+	}
+
+	dir := path
+	if info, statErr := os.Stat(path); statErr == nil && !info.IsDir() {
+		dir = filepath.Dir(path)
+	}
+
+	return items, dir, nil
+}
+
+// loadArchiveItems rebuilds Gmail items from the archive database.
+func loadArchiveItems(cfg *models.Config, sourceName string, limit int) ([]models.FullItem, error) {
+	dbPath, err := resolveArchiveDBPath(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := os.Stat(dbPath); err != nil {
+		return nil, fmt.Errorf("archive database not found at %s: %w", dbPath, err)
+	}
+
+	store, err := archive.NewStore(dbPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open archive: %w", err)
+	}
+	defer store.Close()
+
+	messages, err := store.ListMessages(sourceName, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	return reprocess.FromArchive(messages), nil
+}
+
+// reprocessItems runs items through the configured transformer pipeline and,
+// unless dryRun is set, writes them to sink. It returns the transformed items.
+func reprocessItems(
+	ctx context.Context,
+	cfg *models.Config,
+	items []models.FullItem,
+	sink *sinks.FileSink,
+	limit int,
+	dryRun bool,
+) ([]models.FullItem, error) {
+	pipeline := transform.NewPipeline()
+	for _, t := range transform.GetAllContentProcessingTransformers() {
+		if err := pipeline.AddTransformer(t); err != nil {
+			return nil, fmt.Errorf("failed to add transformer %s: %w", t.Name(), err)
+		}
+	}
+
+	if limit <= 0 {
+		limit = len(items)
+	}
+
+	result, err := syncer.NewMultiSyncer(pipeline).SyncAll(
+		ctx,
+		[]syncer.SourceEntry{{Name: "reprocess", Src: reprocess.NewSource("reprocess", items), Limit: limit}},
+		[]interfaces.Sink{sink},
+		syncer.MultiSyncOptions{
+			TransformCfg: transformConfig(cfg, sink.Name()),
+			DryRun:       dryRun,
+		},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("reprocess failed: %w", err)
+	}
+
+	return result.Items, nil
+}
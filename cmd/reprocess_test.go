@@ -0,0 +1,262 @@
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"pkm-sync/internal/archive"
+	"pkm-sync/pkg/models"
+)
+
+const signedNote = `---
+id: msg-1
+source: gmail
+type: email
+created: 2026-04-01T10:00:00Z
+---
+
+# Launch plan
+
+Let's ship on Friday.
+
+QA signs off on the release candidate on Thursday.
+
+--
+Jane Smith
+jane@example.com
+`
+
+// reprocessVault loads the notes in dir and re-exports them in place.
+func reprocessVault(t *testing.T, dir string, transformCfg models.TransformConfig) string {
+	t.Helper()
+
+	items, outDir, err := loadExportedItems(dir)
+	if err != nil {
+		t.Fatalf("loadExportedItems() failed: %v", err)
+	}
+
+	if outDir != dir || len(items) != 1 {
+		t.Fatalf("expected 1 item rewritten in %s, got %d items for %s", dir, len(items), outDir)
+	}
+
+	cfg := &models.Config{Transformers: transformCfg}
+
+	sink, err := createFileSinkWithConfig("obsidian", outDir, cfg)
+	if err != nil {
+		t.Fatalf("createFileSinkWithConfig() failed: %v", err)
+	}
+
+	if _, err := reprocessItems(context.Background(), cfg, items, sink, 0, false); err != nil {
+		t.Fatalf("reprocessItems() failed: %v", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, "launch-plan.md"))
+	if err != nil {
+		t.Fatalf("failed to read re-exported note: %v", err)
+	}
+
+	return string(data)
+}
+
+func TestReprocess_TransformerSettingChangesOutput(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "launch-plan.md"), []byte(signedNote), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	before := reprocessVault(t, dir, models.TransformConfig{Enabled: false})
+	if !strings.Contains(before, "jane@example.com") {
+		t.Fatalf("expected signature to survive with transformers disabled, got:\n%s", before)
+	}
+
+	after := reprocessVault(t, dir, models.TransformConfig{
+		Enabled:       true,
+		PipelineOrder: []string{"signature_removal"},
+		ErrorStrategy: "fail_fast",
+	})
+
+	if strings.Contains(after, "jane@example.com") || strings.Contains(after, "Jane Smith") {
+		t.Errorf("expected signature_removal to strip the signature, got:\n%s", after)
+	}
+
+	if !strings.Contains(after, "Let's ship on Friday.") || !strings.Contains(after, "id: msg-1") {
+		t.Errorf("expected body and frontmatter to be kept, got:\n%s", after)
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(entries) != 1 {
+		t.Errorf("expected the note to be rewritten in place, found %d entries", len(entries))
+	}
+}
+
+func TestReprocess_JSONLUsesConfiguredOutput(t *testing.T) {
+	dir := t.TempDir()
+	jsonl := filepath.Join(dir, "items.jsonl")
+
+	item := models.NewBasicItem("doc-1", "Design Notes")
+	item.SetSourceType("google_drive")
+	item.SetItemType("document")
+	item.SetCreatedAt(time.Date(2026, 4, 2, 0, 0, 0, 0, time.UTC))
+	item.SetContent("Draft")
+
+	data, err := item.MarshalJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.WriteFile(jsonl, append(data, '\n'), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	items, outDir, err := loadExportedItems(jsonl)
+	if err != nil {
+		t.Fatalf("loadExportedItems() failed: %v", err)
+	}
+
+	if outDir != "" || len(items) != 1 || items[0].GetTitle() != "Design Notes" {
+		t.Errorf("expected one JSONL item and no implied output dir, got %d items, dir %q", len(items), outDir)
+	}
+}
+
+func TestRunReprocessCommand_RequiresOneInput(t *testing.T) {
+	reprocessArchive = true
+
+	defer func() { reprocessArchive = false }()
+
+	if err := runReprocessCommand(nil, []string{"vault"}); err == nil {
+		t.Error("expected error when both a path and --archive are given")
+	}
+
+	reprocessArchive = false
+
+	if err := runReprocessCommand(nil, nil); err == nil {
+		t.Error("expected error when neither a path nor --archive is given")
+	}
+}
+
+func TestReprocess_ArchiveTruncationLinksArchivedCopy(t *testing.T) {
+	dir := t.TempDir()
+	dbPath := filepath.Join(dir, "archive.db")
+	emlPath := filepath.Join(dir, "eml", "work", "m1.eml")
+	body := strings.Repeat("Quarterly numbers are in. ", 40)
+
+	store, err := archive.NewStore(dbPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	msg := archive.Message{GmailID: "m1", Subject: "Quarterly report", EMLPath: emlPath, SourceName: "work"}
+	if err := store.IndexMessage(msg, body); err != nil {
+		t.Fatal(err)
+	}
+
+	store.Close()
+
+	cfg := &models.Config{
+		Archive: models.ArchiveConfig{Enabled: true, DBPath: dbPath},
+		Targets: map[string]models.TargetConfig{"obsidian": {TruncateAt: 200}},
+	}
+
+	items, err := loadArchiveItems(cfg, "", 0)
+	if err != nil {
+		t.Fatalf("loadArchiveItems() failed: %v", err)
+	}
+
+	outDir := filepath.Join(dir, "vault")
+
+	sink, err := createFileSinkWithConfig("obsidian", outDir, cfg)
+	if err != nil {
+		t.Fatalf("createFileSinkWithConfig() failed: %v", err)
+	}
+
+	closeArchive, err := maybeAttachArchiveTruncation(sink, "obsidian", cfg)
+	if err != nil {
+		t.Fatalf("maybeAttachArchiveTruncation() failed: %v", err)
+	}
+	defer closeArchive()
+
+	if _, err := reprocessItems(context.Background(), cfg, items, sink, 0, false); err != nil {
+		t.Fatalf("reprocessItems() failed: %v", err)
+	}
+
+	notes, _ := filepath.Glob(filepath.Join(outDir, "*.md"))
+	if len(notes) != 1 {
+		t.Fatalf("expected one note, found %v", notes)
+	}
+
+	data, err := os.ReadFile(notes[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	note := string(data)
+	if strings.Contains(note, body) || !strings.Contains(note, "(file://"+filepath.ToSlash(emlPath)+")") {
+		t.Errorf("expected a truncated body linking to %s, got:\n%s", emlPath, note)
+	}
+}
+
+func TestReprocess_AttachmentLinksPointAtVaultCopies(t *testing.T) {
+	vault := t.TempDir()
+	download := filepath.Join(t.TempDir(), "F123-report.pdf")
+
+	if err := os.WriteFile(download, []byte("%PDF-1.4"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	const permalink = "https://example.slack.com/files/U1/F123/report.pdf"
+
+	item := models.NewBasicItem("slack-1", "Quarterly report")
+	item.SetSourceType("slack")
+	item.SetCreatedAt(time.Date(2026, 4, 2, 0, 0, 0, 0, time.UTC))
+	item.SetContent("Report is up: " + permalink)
+	item.SetAttachments([]models.Attachment{{ID: "F123", Name: "report.pdf", URL: permalink, LocalPath: download}})
+
+	cfg := &models.Config{
+		Sync: models.SyncConfig{DeadLetterPath: filepath.Join(t.TempDir(), "dead-letter.jsonl")},
+		Targets: map[string]models.TargetConfig{
+			"obsidian": {Obsidian: models.ObsidianTargetConfig{AttachmentFolder: "Files"}},
+		},
+		Transformers: models.TransformConfig{
+			Enabled:       true,
+			PipelineOrder: []string{"attachment_link_rewrite"},
+			ErrorStrategy: "fail_fast",
+		},
+	}
+
+	sink, err := createFileSinkWithConfig("obsidian", vault, cfg)
+	if err != nil {
+		t.Fatalf("createFileSinkWithConfig() failed: %v", err)
+	}
+
+	items, err := reprocessItems(context.Background(), cfg, []models.FullItem{item}, sink, 0, false)
+	if err != nil {
+		t.Fatalf("reprocessItems() failed: %v", err)
+	}
+
+	notePath, err := sink.PathFor(items[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	note, err := os.ReadFile(notePath)
+	if err != nil {
+		t.Fatalf("note not written: %v", err)
+	}
+
+	if strings.Contains(string(note), permalink) || !strings.Contains(string(note), "Files/F123-report.pdf") {
+		t.Errorf("expected the permalink to point at Files/F123-report.pdf, got:\n%s", note)
+	}
+
+	copied, err := os.ReadFile(filepath.Join(vault, "Files", "F123-report.pdf"))
+	if err != nil || string(copied) != "%PDF-1.4" {
+		t.Errorf("attachment not copied into the vault: %q, %v", copied, err)
+	}
+}
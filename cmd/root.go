@@ -6,6 +6,7 @@ import (
 	"os"
 
 	"pkm-sync/internal/config"
+	"pkm-sync/internal/httpclient"
 	"pkm-sync/internal/keystore"
 	"pkm-sync/internal/sources/google/auth"
 	servicenow "pkm-sync/internal/sources/servicenow"
@@ -27,7 +28,7 @@ var rootCmd = &cobra.Command{
 	Short: "Synchronize data between various sources and PKM systems",
 	Long: `pkm-sync integrates data sources (Google Calendar, Gmail, Drive, etc.)
 with Personal Knowledge Management systems (Obsidian, Logseq, etc.).`,
-	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
 		// Set up logging based on debug flag
 		if debugMode {
 			// Set debug level logging
@@ -60,10 +61,20 @@ with Personal Knowledge Management systems (Obsidian, Logseq, etc.).`,
 			}
 		}
 
-		// Determine storage mode from config if available.
+		// Determine storage mode and HTTP transport from config if available.
 		storageMode := keystore.ModeAuto
-		if cfg, err := config.LoadConfig(); err == nil && cfg.Auth.SecretStorage != "" {
-			storageMode = cfg.Auth.SecretStorage
+
+		if cfg, err := config.LoadConfig(); err == nil {
+			if cfg.Auth.SecretStorage != "" {
+				storageMode = cfg.Auth.SecretStorage
+			}
+
+			transport, err := httpclient.NewBaseTransport(cfg.App.HTTP)
+			if err != nil {
+				return fmt.Errorf("invalid app.http config: %w", err)
+			}
+
+			httpclient.SetSharedTransport(transport)
 		}
 
 		if store, err := keystore.New(storageMode, effectiveConfigDir); err != nil {
@@ -73,6 +84,8 @@ with Personal Knowledge Management systems (Obsidian, Logseq, etc.).`,
 			slack.SetStore(store)
 			servicenow.SetStore(store)
 		}
+
+		return nil
 	},
 }
 
@@ -22,6 +22,7 @@ var (
 	searchSourceType string
 	searchSourceName string
 	searchFormat     string
+	searchJSON       bool
 	searchMinScore   float64
 )
 
@@ -50,7 +51,8 @@ Examples:
   pkm-sync search gmail "rosa boundary"
   pkm-sync search gmail/work_gmail "rosa boundary"
   pkm-sync search slack "deploy failed" --limit 5
-  pkm-sync search "project status" --format json`,
+  pkm-sync search "project status" --format json
+  pkm-sync search "rosa boundary" --source gmail_work --json`,
 	Args: cobra.RangeArgs(1, 2),
 	RunE: runSearchCommand,
 }
@@ -60,13 +62,19 @@ func init() {
 	searchCmd.Flags().IntVar(&searchLimit, "limit", 10, "Maximum number of results to return")
 	searchCmd.Flags().StringVar(&searchSourceType, "source-type", "", "Filter by source type (gmail)")
 	searchCmd.Flags().StringVar(&searchSourceName, "source-name", "", "Filter by source name (gmail_work, etc.)")
+	searchCmd.Flags().StringVar(&searchSourceName, "source", "", "Alias for --source-name")
 	searchCmd.Flags().StringVar(&searchFormat, "format", "text", "Output format (text, json)")
-	searchCmd.Flags().Float64Var(&searchMinScore, "min-score", 0.0, "Minimum similarity score (0.0-1.0)")
+	searchCmd.Flags().BoolVar(&searchJSON, "json", false, "Shorthand for --format json")
+	searchCmd.Flags().Float64Var(&searchMinScore, "min-score", 0.0, "Minimum cosine similarity score (default: vector_db.min_score)")
 }
 
 func runSearchCommand(cmd *cobra.Command, args []string) error {
 	ctx := context.Background()
 
+	if searchJSON {
+		searchFormat = "json"
+	}
+
 	// Two-arg form: search <type[/source]> <query>
 	// One-arg form: search <query>
 	var query string
@@ -89,7 +97,7 @@ func runSearchCommand(cmd *cobra.Command, args []string) error {
 	}
 
 	// Default path: vector (semantic) search.
-	return runVectorSearch(ctx, query, sourceTypeFilter, sourceName)
+	return runVectorSearch(ctx, query, sourceTypeFilter, sourceName, cmd.Flags().Changed("min-score"))
 }
 
 // parseSearchSpecifier parses the optional first positional argument of the
@@ -166,13 +174,19 @@ func runGmailFTSSearch(query, sourceName string) (bool, error) {
 	return true, outputArchiveResults(query, results, searchFormat)
 }
 
-// runVectorSearch performs semantic (KNN) search against vectors.db.
-func runVectorSearch(ctx context.Context, query, sourceTypeFilter, sourceName string) error {
+// runVectorSearch performs semantic (KNN) search against vectors.db. Unless
+// --min-score was given, results below vector_db.min_score are dropped.
+func runVectorSearch(ctx context.Context, query, sourceTypeFilter, sourceName string, minScoreSet bool) error {
 	cfg, err := config.LoadConfig()
 	if err != nil {
 		return fmt.Errorf("failed to load config: %w", err)
 	}
 
+	minScore := searchMinScore
+	if !minScoreSet {
+		minScore = cfg.VectorDB.MinScore
+	}
+
 	vectorSink, err := createVectorSink(cfg)
 	if err != nil {
 		return fmt.Errorf("failed to create vector sink: %w", err)
@@ -182,7 +196,7 @@ func runVectorSearch(ctx context.Context, query, sourceTypeFilter, sourceName st
 	filters := vectorstore.SearchFilters{
 		SourceType: sourceTypeFilter,
 		SourceName: sourceName,
-		MinScore:   searchMinScore,
+		MinScore:   minScore,
 	}
 
 	results, err := vectorSink.Search(ctx, query, searchLimit, filters)
@@ -5,32 +5,42 @@ import (
 	"errors"
 	"fmt"
 	"log/slog"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
 	"path/filepath"
+	"strings"
 	"syscall"
 	"time"
 
 	"pkm-sync/internal/config"
 	"pkm-sync/internal/embeddings"
 	"pkm-sync/internal/server"
+	syncer "pkm-sync/internal/sync"
 
 	"github.com/spf13/cobra"
 )
 
+// defaultServeAddr keeps the API on the loopback interface unless asked otherwise.
+const defaultServeAddr = "127.0.0.1:8080"
+
 var serveAddr string
 
 var serveCmd = &cobra.Command{
 	Use:   "serve",
 	Short: "Run the pkm-sync HTTP API server",
-	Long: `Run a long-lived HTTP server exposing read-only access to the data
-pkm-sync maintains: semantic search (vectors.db), Gmail full-text search
+	Long: `Run a long-lived HTTP server exposing the data pkm-sync maintains —
+semantic search and item lookup (vectors.db), Gmail full-text search
 (archive.db), Slack message search (slack.db), pipeline status, and
-Prometheus metrics.
+Prometheus metrics — plus an endpoint that triggers a sync, so editor
+plugins and scripts can use pkm-sync as a local backend.
 
 Endpoints:
+  POST /api/sync           run a sync; JSON body {"sources", "since", "limit", "dry_run", "full"}, all optional
+                           (Content-Type: application/json required)
   GET /api/search          semantic vector search (q, source_type, source_name, limit, min_score)
+  GET /api/items/{id}      one indexed item by thread or source ID (source_name)
   GET /api/emails          Gmail FTS search (q, from, since, limit, body)
   GET /api/slack/messages  Slack keyword search (q, channel, author, since, limit)
   GET /api/status          pipeline-status JSON written by sync jobs
@@ -39,6 +49,15 @@ Endpoints:
 
 Authentication: set PKM_API_TOKEN to require "Authorization: Bearer <token>"
 on /api/* routes. When unset, the API is open (intended for local use only).
+The server listens on 127.0.0.1:8080 by default; binding another interface
+without a token logs a warning.
+
+POST /api/sync reuses 'pkm-sync sync': it reloads config.yaml for each run,
+responds with the run summary JSON once the run finishes (500 if a source
+group failed, 503 if config.yaml no longer loads), and answers 409 while
+another sync is in progress. Requests without a JSON Content-Type, or from
+a browser page on another origin, are rejected; without a token, so are
+requests whose Host is not localhost, 127.0.0.1 or [::1].
 
 Data paths come from config.yaml (vectordb.db_path, archive.db_path,
 slack.db_path) with env overrides PKM_ARCHIVE_DB, PKM_SLACK_DB,
@@ -48,7 +67,7 @@ PKM_SLACK_USER_CACHE, PKM_STATUS_DIR, and PKM_SYNC_METRICS_DB.`,
 
 func init() {
 	rootCmd.AddCommand(serveCmd)
-	serveCmd.Flags().StringVar(&serveAddr, "addr", ":8080", "Listen address (host:port)")
+	serveCmd.Flags().StringVar(&serveAddr, "addr", defaultServeAddr, "Listen address (host:port)")
 }
 
 func runServeCommand(cmd *cobra.Command, _ []string) error {
@@ -57,7 +76,7 @@ func runServeCommand(cmd *cobra.Command, _ []string) error {
 		return fmt.Errorf("failed to load config: %w", err)
 	}
 
-	provider, err := embeddings.NewProvider(cfg.Embeddings)
+	provider, err := embeddings.NewProvider(&cfg.Embeddings)
 	if err != nil {
 		return fmt.Errorf("failed to create embedding provider: %w", err)
 	}
@@ -87,10 +106,17 @@ func runServeCommand(cmd *cobra.Command, _ []string) error {
 		SlackDBPath:   firstNonEmpty(os.Getenv("PKM_SLACK_DB"), cfg.Slack.DBPath, filepath.Join(cfgDir, "slack.db")),
 		UserCachePath: firstNonEmpty(os.Getenv("PKM_SLACK_USER_CACHE"), filepath.Join(cfgDir, "slack-user-cache.json")),
 		Dimensions:    cfg.Embeddings.Dimensions,
+		MinScore:      cfg.VectorDB.MinScore,
+		Sync:          serveSync,
 	}
 
 	if srvCfg.Token == "" {
 		slog.Warn("PKM_API_TOKEN not set; API routes are unauthenticated")
+
+		if !isLoopbackAddr(serveAddr) {
+			slog.Warn("Listening beyond localhost without a token; anyone who can reach the port can read data and run syncs",
+				"addr", serveAddr)
+		}
 	}
 
 	httpServer := &http.Server{
@@ -126,6 +152,51 @@ func runServeCommand(cmd *cobra.Command, _ []string) error {
 	return nil
 }
 
+// serveSync runs a POST /api/sync request through the same code path as
+// `pkm-sync sync`. The config is reloaded per run so edits apply without a
+// restart; when it no longer loads the run fails rather than syncing with
+// the defaults.
+func serveSync(ctx context.Context, req server.SyncRequest) (*syncer.RunSummary, error) {
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		slog.Error("Sync request rejected: config.yaml failed to load", "error", err)
+
+		return nil, fmt.Errorf("%w: failed to load config: %w", server.ErrSyncUnavailable, err)
+	}
+
+	limit := req.Limit
+	if limit == 0 {
+		limit = defaultSyncLimit
+	}
+
+	return runSync(ctx, cfg, syncRunOptions{
+		Sources:      strings.Join(req.Sources, ","),
+		Since:        req.Since,
+		Limit:        limit,
+		DryRun:       req.DryRun,
+		Full:         req.Full,
+		OutputFormat: "summary",
+		SummaryPath:  cfg.Sync.SummaryPath,
+	})
+}
+
+// isLoopbackAddr reports whether a listen address binds only the loopback
+// interface. An empty host (":8080") listens on every interface.
+func isLoopbackAddr(addr string) bool {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return false
+	}
+
+	if host == "localhost" {
+		return true
+	}
+
+	ip := net.ParseIP(host)
+
+	return ip != nil && ip.IsLoopback()
+}
+
 // envOrDefault returns the environment variable's value, or def when unset.
 func envOrDefault(name, def string) string {
 	if v := os.Getenv(name); v != "" {
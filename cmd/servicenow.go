@@ -38,9 +38,9 @@ func init() {
 }
 
 func runServiceNowCommand(_ *cobra.Command, _ []string) error {
-	cfg, err := config.LoadConfig()
+	cfg, err := config.LoadConfigOrDefault()
 	if err != nil {
-		cfg = config.GetDefaultConfig()
+		return err
 	}
 
 	var sourcesToSync []string
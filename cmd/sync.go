@@ -1,13 +1,20 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"slices"
 	"strings"
+	"time"
 
 	"golang.org/x/sync/errgroup"
 
 	"pkm-sync/internal/config"
+	"pkm-sync/internal/sinks"
 	"pkm-sync/internal/state"
+	syncer "pkm-sync/internal/sync"
+	"pkm-sync/internal/transform"
 	"pkm-sync/pkg/models"
 	"pkm-sync/pkg/routing"
 
@@ -15,19 +22,35 @@ import (
 )
 
 var (
-	syncSourceName   string
-	syncTargetName   string
-	syncOutputDir    string
-	syncSince        string
-	syncDryRun       bool
-	syncLimit        int
-	syncOutputFormat string
+	syncSourceName     string
+	syncTargetName     string
+	syncOutputDir      string
+	syncSince          string
+	syncUntil          string
+	syncDryRun         bool
+	syncLimit          int
+	syncOutputFormat   string
+	syncPreviewLines   int
+	syncMaxThreadItems int
+	syncSummaryPath    string
+	syncTagPrefix      string
+	syncSources        string
+	syncDedupeAttach   bool
+	syncIncludeBody    bool
+	syncExcludeBody    bool
+	syncNoIndex        bool
+	syncNoArchive      bool
+	syncNoFiles        bool
+	syncStrictPaths    bool
+	syncFull           bool
+	syncAll            bool
 )
 
 var syncCmd = &cobra.Command{
 	Use:   "sync [source]",
 	Short: "Sync all enabled sources to PKM systems",
-	Long: `Sync all enabled sources (Gmail, Google Calendar, Drive, Slack, Jira) to PKM targets in a single operation.
+	Long: `Sync all enabled sources (Gmail, Google Calendar, Drive, Slack, Jira, JSONL) to PKM targets
+in a single operation.
 
 An optional positional argument can filter to a specific source type or source
 name. Source type aliases like "gmail", "drive", "jira", "slack" are accepted:
@@ -36,7 +59,12 @@ name. Source type aliases like "gmail", "drive", "jira", "slack" are accepted:
   pkm-sync sync gmail_work      # specific source by name
   pkm-sync sync drive           # all enabled Drive sources
 
-The --source flag is also accepted for backward compatibility.
+The --source flag is also accepted for backward compatibility. --sources takes
+a comma-separated list, or "all" for every configured source including
+disabled ones:
+
+  pkm-sync sync --sources gmail_work,slack_eng
+  pkm-sync sync --sources all
 
 Examples:
   pkm-sync sync
@@ -45,7 +73,12 @@ Examples:
   pkm-sync sync --source gmail_work
   pkm-sync sync --target obsidian --output ./vault
   pkm-sync sync --since 7d --dry-run
-  pkm-sync sync gmail --dry-run --format json`,
+  pkm-sync sync gmail --since 2023-01-01 --until 2024-01-01   # backfill 2023
+  pkm-sync sync gmail --dry-run --format json
+  pkm-sync sync --summary ./last-run.json
+  pkm-sync sync calendar --exclude-body
+  pkm-sync sync --no-files        # re-index without touching the vault
+  pkm-sync sync gmail --full      # ignore the incremental window and re-fetch`,
 	Args: cobra.MaximumNArgs(1),
 	RunE: runSyncCommand,
 }
@@ -53,72 +86,225 @@ Examples:
 func init() {
 	rootCmd.AddCommand(syncCmd)
 	syncCmd.Flags().StringVar(&syncSourceName, "source", "", "Filter to a specific source by name")
-	syncCmd.Flags().StringVar(&syncTargetName, "target", "", "PKM target (obsidian, logseq)")
+	syncCmd.Flags().StringVar(&syncSources, "sources", "",
+		`Comma-separated sources or types to sync, or "all" for every configured source (including disabled)`)
+	syncCmd.Flags().StringVar(&syncTargetName, "target", "", "PKM target (obsidian, logseq, roam, jsonl)")
 	syncCmd.Flags().StringVarP(&syncOutputDir, "output", "o", "", "Output directory")
 	syncCmd.Flags().StringVar(&syncSince, "since", "", "Sync items since (7d, 2006-01-02, today)")
+	syncCmd.Flags().StringVar(&syncUntil, "until", "",
+		"Sync items before this date, to backfill a past window (2006-01-02); skips the incremental window")
 	syncCmd.Flags().BoolVar(&syncDryRun, "dry-run", false, "Show what would be synced without making changes")
-	syncCmd.Flags().IntVar(&syncLimit, "limit", 1000, "Maximum number of items per source")
+	syncCmd.Flags().IntVar(&syncLimit, "limit", defaultSyncLimit, "Maximum number of items per source")
 	syncCmd.Flags().StringVar(&syncOutputFormat, "format", "summary", "Output format for dry-run (summary, json)")
+	syncCmd.Flags().IntVar(&syncPreviewLines, "preview-lines", 0,
+		"With --dry-run, show the first N lines of each note that would be created or updated")
+	syncCmd.Flags().IntVar(&syncMaxThreadItems, "max-thread-items", 0,
+		"Cap messages per consolidated thread note (overrides thread_grouping max_consolidated_items; 0 = no cap)")
+	syncCmd.Flags().StringVar(&syncSummaryPath, "summary", "",
+		"Write a JSON run summary to this path (overrides sync.summary_path)")
+	syncCmd.Flags().StringVar(&syncTagPrefix, "tag-prefix", "",
+		`Namespace for source tags, e.g. "sync/source" gives sync/source/<name> (overrides sync.source_tag_prefix)`)
+	syncCmd.Flags().BoolVar(&syncDedupeAttach, "dedupe-attachments-global", false,
+		"Reference attachment files stored by earlier runs instead of writing duplicates (sets sync.dedupe_attachments_global)")
+	syncCmd.Flags().BoolVar(&syncIncludeBody, "include-body", false,
+		"Write full note bodies for every source (overrides omit_body)")
+	syncCmd.Flags().BoolVar(&syncExcludeBody, "exclude-body", false,
+		"Write stub notes without content bodies, keeping title, metadata, tags and links (sets omit_body)")
+	syncCmd.Flags().BoolVar(&syncNoIndex, "no-index", false,
+		"Skip the vector index for this run (overrides vector_db.auto_index)")
+	syncCmd.Flags().BoolVar(&syncNoArchive, "no-archive", false,
+		"Skip the raw message archive for this run (overrides archive.enabled)")
+	syncCmd.Flags().BoolVar(&syncNoFiles, "no-files", false,
+		"Skip writing notes and the digest to the vault for this run")
+	syncCmd.Flags().BoolVar(&syncStrictPaths, "strict-output-paths", false,
+		"Fail instead of warning when several sources write notes to the same directory")
+	syncCmd.Flags().BoolVar(&syncFull, "full", false,
+		"Re-fetch each source's whole since window instead of resuming from its newest indexed item")
+	syncCmd.Flags().BoolVar(&syncAll, "all", false,
+		"Re-read every local file, including those unchanged since the last sync (implies --full)")
+}
+
+// defaultSyncLimit is the per-source item cap when --limit is not given.
+const defaultSyncLimit = 1000
+
+// syncRunOptions carries the per-run settings of a sync, whether they come
+// from CLI flags or a POST /api/sync request.
+type syncRunOptions struct {
+	Source       string // source name or type alias; empty means all enabled sources
+	Sources      string // comma-separated list or "all"; exclusive with Source
+	Target       string
+	Output       string
+	Since        string
+	Until        string // end of the fetch window; empty means now
+	Limit        int
+	DryRun       bool
+	OutputFormat string
+	PreviewLines int    // summary dry-run content preview lines
+	SummaryPath  string // write the run summary here when set
+
+	// NoIndex, NoArchive and NoFiles disable the vector, archive and vault
+	// sinks for this run only.
+	NoIndex   bool
+	NoArchive bool
+	NoFiles   bool
+
+	// StrictOutputPaths fails the run when sources share an output directory.
+	StrictOutputPaths bool
+
+	// Full re-fetches every source's whole window instead of resuming from
+	// the newest indexed item.
+	Full bool
+
+	// All re-reads every local file, ignoring the per-source file index.
+	All bool
 }
 
 func runSyncCommand(cmd *cobra.Command, args []string) error {
-	cfg, err := config.LoadConfig()
+	cfg, err := config.LoadConfigOrDefault()
 	if err != nil {
-		cfg = config.GetDefaultConfig()
+		return err
+	}
+
+	if syncTagPrefix != "" {
+		cfg.Sync.SourceTagPrefix = syncTagPrefix
+	}
+
+	if syncDedupeAttach {
+		cfg.Sync.DedupeAttachmentsGlobal = true
+	}
+
+	if syncIncludeBody && syncExcludeBody {
+		return fmt.Errorf("--include-body and --exclude-body cannot be combined")
+	}
+
+	if syncIncludeBody || syncExcludeBody {
+		setOmitBody(cfg, syncExcludeBody)
+	}
+
+	if cmd.Flags().Changed("max-thread-items") {
+		setTransformerOption(cfg, "thread_grouping", "max_consolidated_items", syncMaxThreadItems)
+	}
+
+	if syncSources != "" && (syncSourceName != "" || len(args) == 1) {
+		return fmt.Errorf("--sources cannot be combined with --source or a source argument")
 	}
 
 	// The optional positional arg can be a source name ("gmail_work") or a source
 	// type alias ("gmail", "drive"). Resolve into a local to avoid mutating the
 	// flag-backed global (which persists across in-process invocations).
-	resolvedSource := syncSourceName
-	if len(args) == 1 && resolvedSource == "" {
-		resolvedSource = resolveSyncPositionalArg(cfg, args[0])
+	sourceArg := syncSourceName
+	if len(args) == 1 && sourceArg == "" {
+		sourceArg = args[0]
 	}
 
+	summaryPath := cfg.Sync.SummaryPath
+	if syncSummaryPath != "" {
+		summaryPath = syncSummaryPath
+	}
+
+	// Ctrl-C cancels in-flight API requests across all groups.
+	ctx, stop := newSignalContext(cmd.Context())
+	defer stop()
+
+	_, err = runSync(ctx, cfg, syncRunOptions{
+		Source:       sourceArg,
+		Sources:      syncSources,
+		Target:       syncTargetName,
+		Output:       syncOutputDir,
+		Since:        syncSince,
+		Until:        syncUntil,
+		Limit:        syncLimit,
+		DryRun:       syncDryRun,
+		OutputFormat: syncOutputFormat,
+		PreviewLines: syncPreviewLines,
+		SummaryPath:  summaryPath,
+		NoIndex:      syncNoIndex,
+		NoArchive:    syncNoArchive,
+		NoFiles:      syncNoFiles,
+
+		StrictOutputPaths: syncStrictPaths,
+		Full:              syncFull || syncAll,
+		All:               syncAll,
+	})
+
+	return err
+}
+
+// runSync selects the sources named by opts and syncs them, one concurrent
+// group per source type. It is shared by the sync command and the API
+// server. The returned summary is nil when the run was rejected before any
+// source was synced; otherwise it records the run even when err is non-nil.
+func runSync(ctx context.Context, cfg *models.Config, opts syncRunOptions) (*syncer.RunSummary, error) {
+	startedAt := time.Now()
+
 	// Determine which sources to sync.
 	// resolvedSource may be a source name ("gmail_work") or a canonical type
-	// ("gmail", "google_drive") when set via the positional arg.
-	var sourcesToSync []string
+	// ("gmail", "google_drive").
+	var (
+		sourcesToSync []string
+		err           error
+	)
 
 	switch {
-	case resolvedSource == "":
+	case opts.Sources != "":
+		if sourcesToSync, err = selectSyncSources(cfg, opts.Sources); err != nil {
+			return nil, err
+		}
+	case opts.Source == "":
 		sourcesToSync = getEnabledSources(cfg)
-	case routing.IsCanonicalType(resolvedSource):
-		// Filter all enabled sources that match this canonical type.
-		for _, name := range getEnabledSources(cfg) {
-			if sc, ok := cfg.Sources[name]; ok && sc.Type == resolvedSource {
-				sourcesToSync = append(sourcesToSync, name)
-			}
+	default:
+		resolvedSource, err := resolveSourceArg(cfg, opts.Source)
+		if err != nil {
+			return nil, err
+		}
+
+		if _, isName := cfg.Sources[resolvedSource]; isName {
+			sourcesToSync = []string{resolvedSource}
+
+			break
 		}
 
+		// Filter all enabled sources that match this canonical type.
+		sourcesToSync = getEnabledSourcesByType(cfg, resolvedSource)
 		if len(sourcesToSync) == 0 {
-			return fmt.Errorf("no enabled sources of type %q found", resolvedSource)
+			return nil, noEnabledSourcesError(cfg, resolvedSource)
 		}
-	default:
-		// Treat as a specific source name.
-		sourcesToSync = []string{resolvedSource}
 	}
 
 	if len(sourcesToSync) == 0 {
-		return fmt.Errorf("no enabled sources found. Configure sources in your config file or use --source flag")
+		return nil, noEnabledSourcesError(cfg, "")
 	}
 
-	// Resolve target, output, since from CLI flags with config fallbacks
+	// Resolve target, output, since from options with config fallbacks
 	finalTargetName := cfg.Sync.DefaultTarget
-	if syncTargetName != "" {
-		finalTargetName = syncTargetName
+	if opts.Target != "" {
+		finalTargetName = opts.Target
 	}
 
 	finalOutputDir := cfg.Sync.DefaultOutputDir
-	if syncOutputDir != "" {
-		finalOutputDir = syncOutputDir
+	if opts.Output != "" {
+		finalOutputDir = opts.Output
 	}
 
 	finalSince := cfg.Sync.DefaultSince
-	if syncSince != "" {
-		finalSince = syncSince
+	if opts.Since != "" {
+		finalSince = opts.Since
+	}
+
+	// Reject a bad window once rather than in every group.
+	if opts.Until != "" {
+		sinceTime, err := parseSinceTime(finalSince)
+		if err != nil {
+			return nil, fmt.Errorf("invalid since parameter: %w", err)
+		}
+
+		if _, err := parseUntilTime(opts.Until, sinceTime); err != nil {
+			return nil, fmt.Errorf("invalid until parameter: %w", err)
+		}
 	}
 
+	summary := syncer.NewRunSummary(startedAt, opts.DryRun)
+
 	// Group enabled sources by type for dispatch to runSourceSync.
 	typeGroups := map[string][]string{}
 
@@ -126,20 +312,28 @@ func runSyncCommand(cmd *cobra.Command, args []string) error {
 		sourceConfig, exists := cfg.Sources[srcName]
 		if !exists {
 			fmt.Printf("Warning: source '%s' not configured, skipping\n", srcName)
+			summary.AddSkipped(srcName, "not configured")
 
 			continue
 		}
 
 		switch sourceConfig.Type {
-		case "gmail", "google_calendar", "google_drive", "slack", "jira", "servicenow":
+		case "gmail", "google_calendar", "google_drive", "slack", "jira", "servicenow", "jsonl", "notion", "rss", "localfs", "github":
 			typeGroups[sourceConfig.Type] = append(typeGroups[sourceConfig.Type], srcName)
 		default:
 			fmt.Printf("Warning: source '%s' has unsupported type '%s', skipping\n", srcName, sourceConfig.Type)
+			summary.AddSkipped(srcName, fmt.Sprintf("unsupported type %q", sourceConfig.Type))
 		}
 	}
 
 	if len(typeGroups) == 0 {
-		return fmt.Errorf("no valid sources could be initialized")
+		return nil, fmt.Errorf("no valid sources could be initialized")
+	}
+
+	if !opts.NoFiles {
+		if err := checkOutputPaths(cfg, typeGroups, finalOutputDir, opts.StrictOutputPaths); err != nil {
+			return nil, err
+		}
 	}
 
 	type typeGroupCfg struct {
@@ -155,6 +349,11 @@ func runSyncCommand(cmd *cobra.Command, args []string) error {
 		{"slack", "Slack", "messages"},
 		{"jira", "Jira", "issues"},
 		{"servicenow", "ServiceNow", "tickets"},
+		{"jsonl", "JSONL", "items"},
+		{"notion", "Notion", "pages"},
+		{"rss", "RSS", "articles"},
+		{"localfs", "Local files", "notes"},
+		{"github", "GitHub", "issues"},
 	}
 
 	// Filter to groups that have at least one configured source.
@@ -176,38 +375,67 @@ func runSyncCommand(cmd *cobra.Command, args []string) error {
 	}
 
 	// Create a single shared VectorSink for all concurrent type-group goroutines.
-	// The VectorSink is always active: it writes document metadata (timestamps,
-	// source name) unconditionally, enabling data-inferred incremental syncs,
-	// and additionally stores embeddings when a provider is configured.
-	sharedVectorSink, vsErr := createVectorSink(cfg)
-	if vsErr != nil {
-		return fmt.Errorf("failed to create vector sink: %w", vsErr)
+	// The VectorSink is active unless --no-index is given: it writes document
+	// metadata (timestamps, source name) unconditionally, enabling
+	// data-inferred incremental syncs, and additionally stores embeddings when
+	// a provider is configured.
+	var sharedVectorSink *sinks.VectorSink
+
+	if !opts.NoIndex {
+		sharedVectorSink, err = createVectorSink(cfg)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create vector sink: %w", err)
+		}
+
+		defer sharedVectorSink.Close()
 	}
 
-	defer sharedVectorSink.Close()
+	// One inbox review note collects the items of every group.
+	var digest *sinks.DigestSink
+
+	if cfg.Sync.Digest.Enabled && !opts.NoFiles {
+		digest, err = sinks.NewDigestSink(finalTargetName, finalOutputDir, cfg.Sync.Digest)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create digest sink: %w", err)
+		}
+	}
+
+	// One attachment index serves every group, so identical files from
+	// different sources are stored once.
+	sharedAttachments := openAttachmentStore(cfg)
+	if sharedAttachments != nil {
+		defer sharedAttachments.Close()
+	}
 
 	// Load a single shared SyncState so all concurrent goroutines update the
 	// same in-memory object (its mutex keeps it safe). We save once after all
-	// groups finish to avoid concurrent writes to the same file.
+	// groups finish to avoid concurrent writes to the same file. It is loaded
+	// even with --since, which only stops runSourceSync from using it for the
+	// window: the save must keep the other sources' entries.
 	var sharedSyncState *state.SyncState
 
 	stateConfigDir, stateConfigDirErr := config.GetConfigDir()
 	if stateConfigDirErr == nil {
-		if syncSince == "" {
-			// Only read persisted state when --since was not explicitly set.
-			var loadErr error
+		var loadErr error
 
-			sharedSyncState, loadErr = state.Load(stateConfigDir)
-			if loadErr != nil {
-				fmt.Printf("Warning: failed to load sync state: %v; using default since window\n", loadErr)
-			}
-		}
+		sharedSyncState, loadErr = state.Load(stateConfigDir)
+		if loadErr != nil {
+			fmt.Printf("Warning: failed to load sync state: %v; using default since window\n", loadErr)
 
-		if sharedSyncState == nil {
 			sharedSyncState = state.New()
 		}
 	}
 
+	// The post-sync prune must not touch notes this run synced.
+	var syncedPaths *pathSet
+	if cfg.Sync.ArchiveOldFiles && !opts.NoFiles {
+		syncedPaths = newPathSet()
+	}
+
+	// When drive_attachment_links is enabled, the Calendar group waits for the
+	// Drive group so event attachments can link to Drive docs synced this run.
+	driveDocIndex, driveDone := newDriveLinkCoordination(cfg, typeGroups)
+
 	// Run each type group concurrently. Goroutines always return nil so that
 	// one failing group does not cancel the others.
 	groupErrs := make([]error, len(active))
@@ -215,20 +443,41 @@ func runSyncCommand(cmd *cobra.Command, args []string) error {
 
 	for i, ag := range active {
 		eg.Go(func() error {
+			switch {
+			case driveDone == nil:
+			case ag.sourceType == "google_drive":
+				defer close(driveDone)
+			case ag.sourceType == "google_calendar":
+				<-driveDone
+			}
+
 			if err := runSourceSync(cfg, sourceSyncConfig{
 				SourceType:       ag.sourceType,
 				Sources:          ag.sources,
 				TargetName:       finalTargetName,
 				OutputDir:        finalOutputDir,
 				Since:            finalSince,
-				SinceFlag:        syncSince,
-				DefaultLimit:     syncLimit,
-				DryRun:           syncDryRun,
-				OutputFormat:     syncOutputFormat,
+				SinceFlag:        opts.Since,
+				Until:            opts.Until,
+				DefaultLimit:     opts.Limit,
+				DryRun:           opts.DryRun,
+				OutputFormat:     opts.OutputFormat,
+				PreviewLines:     opts.PreviewLines,
 				SourceKind:       ag.sourceKind,
 				ItemKind:         ag.itemKind,
 				SharedVectorSink: sharedVectorSink,
+				NoIndex:          opts.NoIndex,
+				NoArchive:        opts.NoArchive,
+				NoFiles:          opts.NoFiles,
+				Full:             opts.Full,
+				All:              opts.All,
+				Digest:           digest,
+				SyncedPaths:      syncedPaths,
+				AttachmentStore:  sharedAttachments,
 				SyncState:        sharedSyncState,
+				DriveDocIndex:    driveDocIndex,
+				Summary:          summary,
+				Context:          ctx,
 			}); err != nil {
 				fmt.Printf("Warning: %s sync failed: %v\n", ag.sourceKind, err)
 				groupErrs[i] = err
@@ -241,12 +490,42 @@ func runSyncCommand(cmd *cobra.Command, args []string) error {
 	eg.Wait() //nolint:errcheck // goroutines always return nil
 
 	// Save the shared sync state after all groups have finished updating it.
-	if !syncDryRun && sharedSyncState != nil && stateConfigDirErr == nil {
+	if !opts.DryRun && sharedSyncState != nil && stateConfigDirErr == nil {
 		if saveErr := sharedSyncState.Save(stateConfigDir); saveErr != nil {
 			fmt.Printf("Warning: failed to save sync state: %v\n", saveErr)
 		}
 	}
 
+	// A failed group's notes are missing from syncedPaths, so prune only after
+	// a clean run.
+	if syncedPaths != nil && ctx.Err() == nil && errors.Join(groupErrs...) == nil {
+		if err := pruneAfterSync(cfg, finalOutputDir, syncedPaths, opts.DryRun); err != nil {
+			fmt.Printf("Warning: pruning old notes failed: %v\n", err)
+		}
+	}
+
+	for i, ag := range active {
+		summary.AddError(ag.sourceKind, groupErrs[i])
+	}
+
+	if ctx.Err() != nil {
+		summary.AddError("run", fmt.Errorf("sync interrupted: %w", ctx.Err()))
+	}
+
+	summary.Finish(time.Now())
+
+	if opts.SummaryPath != "" {
+		if err := summary.WriteFile(opts.SummaryPath); err != nil {
+			fmt.Printf("Warning: %v\n", err)
+		} else {
+			fmt.Printf("Run summary written to %s\n", opts.SummaryPath)
+		}
+	}
+
+	if ctx.Err() != nil {
+		return summary, fmt.Errorf("sync interrupted: %w", ctx.Err())
+	}
+
 	var failedGroups []string
 
 	for i, ag := range active {
@@ -256,10 +535,45 @@ func runSyncCommand(cmd *cobra.Command, args []string) error {
 	}
 
 	if len(failedGroups) > 0 {
-		return fmt.Errorf("sync failed for: %s", strings.Join(failedGroups, ", "))
+		return summary, fmt.Errorf("sync failed for: %s", strings.Join(failedGroups, ", "))
+	}
+
+	return summary, nil
+}
+
+// setTransformerOption overrides one transformer setting from a CLI flag.
+func setTransformerOption(cfg *models.Config, transformer, key string, value any) {
+	if cfg.Transformers.Transformers == nil {
+		cfg.Transformers.Transformers = make(map[string]map[string]interface{})
+	}
+
+	if cfg.Transformers.Transformers[transformer] == nil {
+		cfg.Transformers.Transformers[transformer] = make(map[string]interface{})
+	}
+
+	cfg.Transformers.Transformers[transformer][key] = value
+}
+
+// newDriveLinkCoordination returns a shared DriveDocIndex when the
+// drive_attachment_links transformer is enabled, plus a channel the Drive group
+// closes when done. The channel is nil unless both Drive and Calendar sources
+// are being synced.
+func newDriveLinkCoordination(
+	cfg *models.Config,
+	typeGroups map[string][]string,
+) (*transform.DriveDocIndex, chan struct{}) {
+	selected := transform.SelectedTransformers(cfg.Transformers)
+	if !cfg.Transformers.Enabled || !slices.Contains(selected, "drive_attachment_links") {
+		return nil, nil
+	}
+
+	index := transform.NewDriveDocIndex()
+
+	if len(typeGroups["google_drive"]) == 0 || len(typeGroups["google_calendar"]) == 0 {
+		return index, nil
 	}
 
-	return nil
+	return index, make(chan struct{})
 }
 
 // resolveSyncPositionalArg maps a positional arg to a source name or type.
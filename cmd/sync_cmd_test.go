@@ -2,8 +2,13 @@ package main
 
 import (
 	"fmt"
+	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
+	"pkm-sync/internal/config"
+	"pkm-sync/internal/state"
 	"pkm-sync/pkg/models"
 )
 
@@ -267,3 +272,210 @@ func TestSyncCmd_PerSourceSinceResolution(t *testing.T) {
 		t.Errorf("Expected CLI since '%s' to take precedence, got '%s'", cliSince, expectedSince)
 	}
 }
+
+func TestRunSourceSync_NoSinkFlags(t *testing.T) {
+	dir := t.TempDir()
+	config.SetCustomConfigDir(dir)
+
+	defer config.SetCustomConfigDir("")
+
+	itemsPath := filepath.Join(dir, "items.jsonl")
+
+	item := models.NewBasicItem("doc-1", "Design Notes")
+	item.SetSourceType("google_drive")
+	item.SetItemType("document")
+	item.SetCreatedAt(time.Now().Add(-time.Hour))
+	item.SetContent("Draft")
+
+	data, err := item.MarshalJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.WriteFile(itemsPath, append(data, '\n'), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name      string
+		ssc       sourceSyncConfig
+		wantNotes bool
+		wantIndex bool
+	}{
+		{name: "all sinks", wantNotes: true, wantIndex: true},
+		{name: "no-files", ssc: sourceSyncConfig{NoFiles: true}, wantIndex: true},
+		{name: "no-index", ssc: sourceSyncConfig{NoIndex: true}, wantNotes: true},
+		{name: "no-archive", ssc: sourceSyncConfig{NoArchive: true}, wantNotes: true, wantIndex: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			runDir := t.TempDir()
+			vectorDB := filepath.Join(runDir, "vectors.db")
+			archiveDB := filepath.Join(runDir, "archive.db")
+
+			cfg := &models.Config{
+				Sources: map[string]models.SourceConfig{
+					"notes": {Enabled: true, Type: "jsonl", JSONL: models.JSONLSourceConfig{Path: itemsPath}},
+				},
+				VectorDB: models.VectorDBConfig{DBPath: vectorDB, AutoIndex: true},
+				Archive:  models.ArchiveConfig{Enabled: true, DBPath: archiveDB},
+			}
+
+			ssc := tt.ssc
+			ssc.SourceType = "jsonl"
+			ssc.Sources = []string{"notes"}
+			ssc.TargetName = "obsidian"
+			ssc.OutputDir = filepath.Join(runDir, "vault")
+			ssc.Since = "30d"
+			ssc.DefaultLimit = 10
+			ssc.SourceKind = "JSONL"
+			ssc.ItemKind = "items"
+
+			if err := runSourceSync(cfg, ssc); err != nil {
+				t.Fatalf("runSourceSync failed: %v", err)
+			}
+
+			notes, _ := filepath.Glob(filepath.Join(ssc.OutputDir, "*.md"))
+			if gotNotes := len(notes) > 0; gotNotes != tt.wantNotes {
+				t.Errorf("notes written = %v, want %v", gotNotes, tt.wantNotes)
+			}
+
+			lastSynced, _ := inferLastSynced(vectorDB, "notes")
+			if gotIndex := !lastSynced.IsZero(); gotIndex != tt.wantIndex {
+				t.Errorf("vector index written = %v, want %v", gotIndex, tt.wantIndex)
+			}
+
+			if !cfg.Archive.Enabled {
+				t.Error("Expected --no-archive to leave the shared config unchanged")
+			}
+		})
+	}
+}
+
+func TestRunSync_SinceKeepsOtherSourcesState(t *testing.T) {
+	dir := t.TempDir()
+	config.SetCustomConfigDir(dir)
+
+	defer config.SetCustomConfigDir("")
+
+	mark := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	saved := state.New()
+	saved.SetHistoryID("gmail_work", "12345")
+	saved.SetHighWater("gmail_work", mark)
+
+	if err := saved.Save(dir); err != nil {
+		t.Fatal(err)
+	}
+
+	itemsPath := filepath.Join(dir, "items.jsonl")
+
+	item := models.NewBasicItem("doc-1", "Design Notes")
+	item.SetCreatedAt(time.Now().Add(-time.Hour))
+	item.SetContent("Draft")
+
+	data, err := item.MarshalJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.WriteFile(itemsPath, append(data, '\n'), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := &models.Config{
+		Sources: map[string]models.SourceConfig{
+			"notes": {Enabled: true, Type: "jsonl", JSONL: models.JSONLSourceConfig{Path: itemsPath}},
+		},
+		Targets:  map[string]models.TargetConfig{"obsidian": {Type: "obsidian"}},
+		VectorDB: models.VectorDBConfig{DBPath: filepath.Join(dir, "vectors.db")},
+	}
+
+	if _, err := runSync(t.Context(), cfg, syncRunOptions{
+		Source: "notes",
+		Target: "obsidian",
+		Output: filepath.Join(dir, "vault"),
+		Since:  "7d",
+		Limit:  10,
+	}); err != nil {
+		t.Fatalf("runSync failed: %v", err)
+	}
+
+	loaded, err := state.Load(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := loaded.HistoryID("gmail_work"); got != "12345" {
+		t.Errorf("gmail_work history ID = %q after a --since run, want 12345", got)
+	}
+
+	if got := loaded.HighWater("gmail_work"); !got.Equal(mark) {
+		t.Errorf("gmail_work high-water = %v after a --since run, want %v", got, mark)
+	}
+}
+
+func TestRunSourceSync_NoFilesLeavesStateUntouched(t *testing.T) {
+	dir := t.TempDir()
+	config.SetCustomConfigDir(dir)
+
+	defer config.SetCustomConfigDir("")
+
+	itemsPath := filepath.Join(dir, "items.jsonl")
+
+	item := models.NewBasicItem("doc-1", "Design Notes")
+	item.SetCreatedAt(time.Now().Add(-time.Hour))
+	item.SetContent("Draft")
+
+	data, err := item.MarshalJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.WriteFile(itemsPath, append(data, '\n'), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := &models.Config{
+		Sources: map[string]models.SourceConfig{
+			"notes": {Enabled: true, Type: "jsonl", JSONL: models.JSONLSourceConfig{Path: itemsPath}},
+		},
+		VectorDB: models.VectorDBConfig{DBPath: filepath.Join(dir, "vectors.db")},
+	}
+
+	run := func(noFiles bool) time.Time {
+		t.Helper()
+
+		if err := runSourceSync(cfg, sourceSyncConfig{
+			SourceType:   "jsonl",
+			Sources:      []string{"notes"},
+			TargetName:   "obsidian",
+			OutputDir:    filepath.Join(dir, "vault"),
+			Since:        "30d",
+			DefaultLimit: 10,
+			NoFiles:      noFiles,
+			SourceKind:   "JSONL",
+			ItemKind:     "items",
+		}); err != nil {
+			t.Fatalf("runSourceSync failed: %v", err)
+		}
+
+		loaded, err := state.Load(dir)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		return loaded.HighWater("notes")
+	}
+
+	// Re-indexing without the vault must not move the mark past notes that
+	// were never written.
+	if got := run(true); !got.IsZero() {
+		t.Errorf("high-water after a --no-files run = %v, want none", got)
+	}
+
+	if got := run(false); got.IsZero() {
+		t.Error("expected a regular run to record a high-water mark")
+	}
+}
@@ -0,0 +1,271 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"pkm-sync/pkg/models"
+	"pkm-sync/pkg/routing"
+)
+
+// allSourcesKeyword makes --sources select every configured source, enabled or not.
+const allSourcesKeyword = "all"
+
+// maxSourceSuggestions caps the "did you mean" list for a mistyped source.
+const maxSourceSuggestions = 3
+
+// disabledSource is a configured source that a plain `sync` leaves out.
+type disabledSource struct {
+	Name   string
+	Type   string
+	Reason string
+}
+
+// selectSyncSources resolves --sources: "all" is every configured source,
+// otherwise a comma-separated list of source names or type aliases.
+func selectSyncSources(cfg *models.Config, spec string) ([]string, error) {
+	if strings.EqualFold(strings.TrimSpace(spec), allSourcesKeyword) {
+		if len(cfg.Sources) == 0 {
+			return nil, noEnabledSourcesError(cfg, "")
+		}
+
+		names := make([]string, 0, len(cfg.Sources))
+		for name := range cfg.Sources {
+			names = append(names, name)
+		}
+
+		sort.Strings(names)
+
+		return names, nil
+	}
+
+	var selected []string
+
+	seen := make(map[string]bool)
+	add := func(name string) {
+		if !seen[name] {
+			seen[name] = true
+			selected = append(selected, name)
+		}
+	}
+
+	for _, part := range strings.Split(spec, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		resolved, err := resolveSourceArg(cfg, part)
+		if err != nil {
+			return nil, err
+		}
+
+		if _, isName := cfg.Sources[resolved]; isName {
+			add(resolved)
+
+			continue
+		}
+
+		matches := getEnabledSourcesByType(cfg, resolved)
+		if len(matches) == 0 {
+			return nil, noEnabledSourcesError(cfg, resolved)
+		}
+
+		sort.Strings(matches)
+
+		for _, name := range matches {
+			add(name)
+		}
+	}
+
+	if len(selected) == 0 {
+		return nil, fmt.Errorf("--sources is empty; pass %q or a comma-separated list of sources", allSourcesKeyword)
+	}
+
+	return selected, nil
+}
+
+// resolveSourceArg maps a source argument to a configured source name or a
+// canonical type, returning an error with close matches when it is neither.
+func resolveSourceArg(cfg *models.Config, arg string) (string, error) {
+	resolved := resolveSyncPositionalArg(cfg, arg)
+
+	if _, ok := cfg.Sources[resolved]; ok || routing.IsCanonicalType(resolved) {
+		return resolved, nil
+	}
+
+	return "", unknownSourceError(cfg, arg)
+}
+
+// unknownSourceError explains that arg names no configured source or type,
+// suggesting close matches for typos.
+func unknownSourceError(cfg *models.Config, arg string) error {
+	candidates := routing.SourceTypeNames()
+	for name := range cfg.Sources {
+		candidates = append(candidates, name)
+	}
+
+	var msg strings.Builder
+
+	fmt.Fprintf(&msg, "unknown source %q", arg)
+
+	if suggestions := suggestSourceNames(arg, candidates); len(suggestions) > 0 {
+		fmt.Fprintf(&msg, "; did you mean %s?", quoteJoin(suggestions, " or "))
+	}
+
+	if len(cfg.Sources) == 0 {
+		msg.WriteString("\nNo sources are configured; run 'pkm-sync config init' and add entries under 'sources:'")
+	} else {
+		names := make([]string, 0, len(cfg.Sources))
+		for name := range cfg.Sources {
+			names = append(names, name)
+		}
+
+		sort.Strings(names)
+		fmt.Fprintf(&msg, "\nConfigured sources: %s", strings.Join(names, ", "))
+	}
+
+	return errors.New(msg.String())
+}
+
+// suggestSourceNames returns up to maxSourceSuggestions candidates within a
+// small edit distance of arg, closest first.
+func suggestSourceNames(arg string, candidates []string) []string {
+	arg = strings.ToLower(arg)
+	maxDistance := max(2, len(arg)/3)
+
+	type scored struct {
+		name     string
+		distance int
+	}
+
+	var matches []scored
+
+	seen := make(map[string]bool)
+
+	for _, c := range candidates {
+		if seen[c] {
+			continue
+		}
+
+		seen[c] = true
+
+		if d := levenshtein(arg, strings.ToLower(c)); d <= maxDistance {
+			matches = append(matches, scored{c, d})
+		}
+	}
+
+	sort.Slice(matches, func(i, j int) bool {
+		if matches[i].distance != matches[j].distance {
+			return matches[i].distance < matches[j].distance
+		}
+
+		return matches[i].name < matches[j].name
+	})
+
+	suggestions := make([]string, 0, min(len(matches), maxSourceSuggestions))
+	for _, m := range matches[:min(len(matches), maxSourceSuggestions)] {
+		suggestions = append(suggestions, m.name)
+	}
+
+	return suggestions
+}
+
+// levenshtein is the edit distance between a and b, counted in runes.
+func levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+
+		prev, curr = curr, prev
+	}
+
+	return prev[len(rb)]
+}
+
+// disabledSources lists configured sources of sourceType (all types when
+// empty) that a plain `sync` skips, with the reason, sorted by name.
+func disabledSources(cfg *models.Config, sourceType string) []disabledSource {
+	var disabled []disabledSource
+
+	for name, sc := range cfg.Sources {
+		if sourceType != "" && sc.Type != sourceType {
+			continue
+		}
+
+		switch {
+		case !sc.Enabled:
+			disabled = append(disabled, disabledSource{name, sc.Type, "enabled: false"})
+		case len(cfg.Sync.EnabledSources) > 0 && !slices.Contains(cfg.Sync.EnabledSources, name):
+			disabled = append(disabled, disabledSource{name, sc.Type, "not listed in sync.enabled_sources"})
+		}
+	}
+
+	sort.Slice(disabled, func(i, j int) bool { return disabled[i].Name < disabled[j].Name })
+
+	return disabled
+}
+
+// noEnabledSourcesError explains why nothing would be synced: either no
+// sources are configured, or which ones are configured but disabled and how
+// to turn them on.
+func noEnabledSourcesError(cfg *models.Config, sourceType string) error {
+	if len(cfg.Sources) == 0 {
+		return errors.New("no sources configured. Run 'pkm-sync config init' to create a config file, " +
+			"then add entries under 'sources:'")
+	}
+
+	var msg strings.Builder
+
+	if sourceType == "" {
+		msg.WriteString("no enabled sources found")
+	} else {
+		fmt.Fprintf(&msg, "no enabled sources of type %q found", sourceType)
+	}
+
+	disabled := disabledSources(cfg, sourceType)
+	if len(disabled) == 0 {
+		return errors.New(msg.String())
+	}
+
+	msg.WriteString(". Configured but not enabled:")
+
+	for _, d := range disabled {
+		fmt.Fprintf(&msg, "\n  %s (%s): %s", d.Name, d.Type, d.Reason)
+	}
+
+	fmt.Fprintf(&msg, "\nTo enable one, set 'enabled: true' under sources.%s and add it to sync.enabled_sources "+
+		"if that list is set.\nOr sync it once with 'pkm-sync sync %s', or every configured source with "+
+		"'pkm-sync sync --sources %s'", disabled[0].Name, disabled[0].Name, allSourcesKeyword)
+
+	return errors.New(msg.String())
+}
+
+func quoteJoin(values []string, sep string) string {
+	quoted := make([]string, 0, len(values))
+	for _, v := range values {
+		quoted = append(quoted, fmt.Sprintf("%q", v))
+	}
+
+	return strings.Join(quoted, sep)
+}
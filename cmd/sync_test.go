@@ -1,10 +1,15 @@
 package main
 
 import (
+	"errors"
+	"fmt"
 	"net/http"
+	"strings"
 	"testing"
 	"time"
 
+	syncer "pkm-sync/internal/sync"
+	"pkm-sync/pkg/interfaces"
 	"pkm-sync/pkg/models"
 )
 
@@ -340,6 +345,25 @@ func TestParseSinceTime_InvalidInputs(t *testing.T) {
 	}
 }
 
+func TestParseUntilTime(t *testing.T) {
+	since := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	if got, err := parseUntilTime("", since); err != nil || !got.IsZero() {
+		t.Errorf("empty until = %v, %v; want the zero time", got, err)
+	}
+
+	got, err := parseUntilTime("2024-01-01", since)
+	if err != nil || !got.Equal(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("parseUntilTime(2024-01-01) = %v, %v", got, err)
+	}
+
+	for _, until := range []string{"2023-01-01", "2022-06-01", "not a date"} {
+		if _, err := parseUntilTime(until, since); err == nil {
+			t.Errorf("parseUntilTime(%q) succeeded, want an error", until)
+		}
+	}
+}
+
 func TestParseSinceTime_EdgeCases(t *testing.T) {
 	testCases := []struct {
 		input    string
@@ -402,6 +426,67 @@ func TestParseSinceTime_NaturalLanguage(t *testing.T) {
 	}
 }
 
+func TestParseSinceTime_CalendarPeriods(t *testing.T) {
+	testCases := []struct {
+		input    string
+		expected bool
+		desc     string
+	}{
+		{"this week", true, "calendar period - this week"},
+		{"this month", true, "calendar period - this month"},
+		{"this quarter", true, "calendar period - this quarter"},
+		{"This Year", true, "calendar period - this year, any case"},
+		{"this fortnight", false, "unknown calendar period"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.input, func(t *testing.T) {
+			result, err := parseSinceTime(tc.input)
+			if tc.expected && err != nil {
+				t.Errorf("Expected %s to parse successfully (%s), got error: %v", tc.input, tc.desc, err)
+			}
+
+			if tc.expected && (result.IsZero() || result.After(time.Now())) {
+				t.Errorf("Expected %s to return a past period start (%s), got %v", tc.input, tc.desc, result)
+			}
+
+			if !tc.expected && err == nil {
+				t.Errorf("Expected %s to fail parsing (%s), but it succeeded", tc.input, tc.desc)
+			}
+		})
+	}
+}
+
+func TestPeriodStart(t *testing.T) {
+	// Thursday, 14 August 2025.
+	now := time.Date(2025, time.August, 14, 15, 30, 0, 0, time.Local)
+
+	testCases := []struct {
+		input    string
+		expected time.Time
+	}{
+		{"this week", time.Date(2025, time.August, 11, 0, 0, 0, 0, time.Local)},
+		{"this month", time.Date(2025, time.August, 1, 0, 0, 0, 0, time.Local)},
+		{"this quarter", time.Date(2025, time.July, 1, 0, 0, 0, 0, time.Local)},
+		{"this year", time.Date(2025, time.January, 1, 0, 0, 0, 0, time.Local)},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.input, func(t *testing.T) {
+			got, ok := periodStart(tc.input, now)
+			if !ok || !got.Equal(tc.expected) {
+				t.Errorf("periodStart(%q) = %v, %v; want %v", tc.input, got, ok, tc.expected)
+			}
+		})
+	}
+
+	// A Sunday belongs to the week that started the Monday before.
+	sunday := time.Date(2025, time.August, 17, 9, 0, 0, 0, time.Local)
+	if got, _ := periodStart("this week", sunday); !got.Equal(time.Date(2025, time.August, 11, 0, 0, 0, 0, time.Local)) {
+		t.Errorf("periodStart(this week) on a Sunday = %v, want Monday 11 August", got)
+	}
+}
+
 func TestCreateSource_Google(t *testing.T) {
 	source, err := createSource("google_calendar", &http.Client{})
 	if err != nil {
@@ -419,14 +504,15 @@ func TestCreateSource_Unknown(t *testing.T) {
 		t.Error("Expected error for unknown source")
 	}
 
-	expectedError := "unknown source 'unknown': supported sources are 'google_calendar' (others like slack, gmail, jira are planned for future releases)"
+	expectedError := "unknown source 'unknown': only 'google_calendar' can be created without config; " +
+		"configure other types (gmail, google_drive, slack, jira, servicenow, jsonl, notion, rss, localfs, github) under sources"
 	if err.Error() != expectedError {
 		t.Errorf("Expected error message %q, got %q", expectedError, err.Error())
 	}
 }
 
 func TestCreateSource_FutureSources(t *testing.T) {
-	// slack and gmail still route through createSource (deprecated path) and should error
+	// slack and gmail need a source config, so createSource (deprecated path) rejects them
 	futureSources := []string{"slack", "gmail"}
 
 	for _, sourceName := range futureSources {
@@ -467,12 +553,29 @@ func TestCreateFileSink_Unknown(t *testing.T) {
 		t.Error("Expected error for unknown sink")
 	}
 
-	expectedError := "unknown formatter 'unknown': supported formatters are 'obsidian' and 'logseq'"
+	expectedError := "unknown target 'unknown': supported targets are 'obsidian', 'logseq', 'roam' and 'jsonl'"
 	if err.Error() != expectedError {
 		t.Errorf("Expected error message %q, got %q", expectedError, err.Error())
 	}
 }
 
+func TestIsJSONLTarget(t *testing.T) {
+	cfg := &models.Config{Targets: map[string]models.TargetConfig{
+		"export":   {Type: "jsonl"},
+		"obsidian": {Type: "obsidian"},
+	}}
+
+	for name, want := range map[string]bool{"export": true, "jsonl": true, "obsidian": false, "logseq": false} {
+		if got := isJSONLTarget(cfg, name); got != want {
+			t.Errorf("isJSONLTarget(%q) = %v, want %v", name, got, want)
+		}
+	}
+
+	if _, err := createFileSinkWithConfig("export", t.TempDir(), cfg); err == nil {
+		t.Error("expected createFileSinkWithConfig to reject a jsonl target")
+	}
+}
+
 func TestCreateSourceWithConfig_GoogleAttendeeAllowListValidation(t *testing.T) {
 	tests := []struct {
 		name              string
@@ -721,3 +824,488 @@ func TestCreateSourceWithConfig_SourceNotInConfig(t *testing.T) {
 		t.Error("Expected non-nil source even when not in config")
 	}
 }
+
+func TestNewDriveLinkCoordination(t *testing.T) {
+	enabled := &models.Config{Transformers: models.TransformConfig{
+		Enabled:       true,
+		PipelineOrder: []string{"drive_attachment_links"},
+	}}
+	both := map[string][]string{"google_drive": {"drive"}, "google_calendar": {"cal"}}
+
+	index, done := newDriveLinkCoordination(enabled, both)
+	if index == nil || done == nil {
+		t.Fatal("expected shared index and ordering channel when both Drive and Calendar sync")
+	}
+
+	if index, done := newDriveLinkCoordination(enabled, map[string][]string{"google_drive": {"drive"}}); index == nil || done != nil {
+		t.Error("expected index without ordering channel when only Drive syncs")
+	}
+
+	if index, done := newDriveLinkCoordination(&models.Config{}, both); index != nil || done != nil {
+		t.Error("expected no coordination when the transformer is not enabled")
+	}
+}
+
+func TestSetTransformerOption_CreatesMaps(t *testing.T) {
+	cfg := &models.Config{}
+
+	setTransformerOption(cfg, "thread_grouping", "max_consolidated_items", 25)
+
+	if got := cfg.Transformers.Transformers["thread_grouping"]["max_consolidated_items"]; got != 25 {
+		t.Errorf("expected max_consolidated_items 25, got %v", got)
+	}
+
+	cfg.Transformers.Transformers["thread_grouping"]["mode"] = "consolidated"
+	setTransformerOption(cfg, "thread_grouping", "max_consolidated_items", 0)
+
+	if cfg.Transformers.Transformers["thread_grouping"]["mode"] != "consolidated" {
+		t.Error("existing settings should be kept")
+	}
+}
+
+func TestSourceOmitsBody(t *testing.T) {
+	omit, keep := true, false
+	cfg := &models.Config{
+		Sync: models.SyncConfig{OmitBody: true},
+		Sources: map[string]models.SourceConfig{
+			"calendar": {},
+			"gmail":    {OmitBody: &keep},
+			"drive":    {OmitBody: &omit},
+		},
+	}
+
+	if !sourceOmitsBody(cfg, cfg.Sources["calendar"]) {
+		t.Error("expected calendar to inherit sync.omit_body")
+	}
+
+	if sourceOmitsBody(cfg, cfg.Sources["gmail"]) {
+		t.Error("expected gmail's omit_body: false to override sync.omit_body")
+	}
+
+	setOmitBody(cfg, false)
+
+	for name, sourceConfig := range cfg.Sources {
+		if sourceOmitsBody(cfg, sourceConfig) {
+			t.Errorf("expected --include-body to keep bodies for %s", name)
+		}
+	}
+}
+
+func TestEmptyResultError(t *testing.T) {
+	results := []syncer.SourceResult{
+		{Name: "gmail_work", ItemCount: 3},
+		{Name: "gmail_broken", Err: errors.New("token expired")},
+	}
+
+	if err := emptyResultError(results); err != nil {
+		t.Errorf("expected no error without an empty result, got %v", err)
+	}
+
+	results = append(results, syncer.SourceResult{
+		Name: "gmail_cron", Err: fmt.Errorf("source 'gmail_cron': %w", syncer.ErrEmptyResult),
+	})
+
+	err := emptyResultError(results)
+	if err == nil || !strings.Contains(err.Error(), "gmail_cron") || strings.Contains(err.Error(), "token expired") {
+		t.Errorf("expected only the empty-result error for gmail_cron, got %v", err)
+	}
+}
+
+func TestSourceTagFormat(t *testing.T) {
+	obsidianTargets := map[string]models.TargetConfig{
+		"obsidian": {Obsidian: models.ObsidianTargetConfig{TagPrefix: "sync/"}},
+	}
+
+	tests := []struct {
+		name       string
+		cfg        *models.Config
+		target     string
+		wantPrefix string
+		wantSep    string
+	}{
+		{"defaults", &models.Config{}, "obsidian", "source", ":"},
+		{"obsidian tag_prefix namespaces", &models.Config{Targets: obsidianTargets}, "obsidian", "sync/source", "/"},
+		{"tag_prefix ignored for logseq", &models.Config{Targets: obsidianTargets}, "logseq", "source", ":"},
+		{
+			"explicit prefix wins",
+			&models.Config{Targets: obsidianTargets, Sync: models.SyncConfig{SourceTagPrefix: "pkm/src"}},
+			"obsidian", "pkm/src", "/",
+		},
+		{
+			"explicit separator",
+			&models.Config{Sync: models.SyncConfig{SourceTagPrefix: "from", SourceTagSeparator: "-"}},
+			"obsidian", "from", "-",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			prefix, sep := sourceTagFormat(tt.cfg, tt.target)
+			if prefix != tt.wantPrefix || sep != tt.wantSep {
+				t.Errorf("sourceTagFormat() = (%q, %q), want (%q, %q)", prefix, sep, tt.wantPrefix, tt.wantSep)
+			}
+		})
+	}
+}
+
+func sourceSelectionConfig() *models.Config {
+	return &models.Config{
+		Sources: map[string]models.SourceConfig{
+			"gmail_work":  {Enabled: true, Type: "gmail"},
+			"gmail_home":  {Enabled: false, Type: "gmail"},
+			"slack_eng":   {Enabled: true, Type: "slack"},
+			"jira_legacy": {Enabled: false, Type: "jira"},
+		},
+	}
+}
+
+func TestLevenshtein(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"", "", 0},
+		{"gmail", "gmail", 0},
+		{"", "slack", 5},
+		{"gmial", "gmail", 2},
+		{"kitten", "sitting", 3},
+		{"grüße", "gruße", 1},
+	}
+
+	for _, tt := range tests {
+		if got := levenshtein(tt.a, tt.b); got != tt.want {
+			t.Errorf("levenshtein(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestSuggestSourceNames(t *testing.T) {
+	candidates := []string{"gmail_work", "gmail_home", "slack_eng", "drive", "gmail", "slack"}
+
+	tests := []struct {
+		arg  string
+		want []string
+	}{
+		{"gmial_work", []string{"gmail_work"}},
+		{"slak_eng", []string{"slack_eng"}},
+		{"drvie", []string{"drive"}},
+		{"GMAIL_WROK", []string{"gmail_work"}},
+		{"confluence", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.arg, func(t *testing.T) {
+			got := suggestSourceNames(tt.arg, candidates)
+			if len(got) != len(tt.want) {
+				t.Fatalf("suggestSourceNames(%q) = %v, want %v", tt.arg, got, tt.want)
+			}
+
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("suggestSourceNames(%q) = %v, want %v", tt.arg, got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestResolveSourceArg_UnknownSuggestsCloseMatch(t *testing.T) {
+	cfg := sourceSelectionConfig()
+
+	if got, err := resolveSourceArg(cfg, "drive"); err != nil || got != "google_drive" {
+		t.Errorf("resolveSourceArg(drive) = %q, %v; want google_drive", got, err)
+	}
+
+	_, err := resolveSourceArg(cfg, "gmial_work")
+	if err == nil {
+		t.Fatal("expected an error for an unknown source")
+	}
+
+	msg := err.Error()
+	if !strings.Contains(msg, `did you mean "gmail_work"`) {
+		t.Errorf("error should suggest gmail_work: %s", msg)
+	}
+
+	if !strings.Contains(msg, "Configured sources: gmail_home, gmail_work, jira_legacy, slack_eng") {
+		t.Errorf("error should list configured sources: %s", msg)
+	}
+}
+
+func TestDisabledSources(t *testing.T) {
+	cfg := sourceSelectionConfig()
+	cfg.Sync.EnabledSources = []string{"gmail_work"}
+
+	got := disabledSources(cfg, "")
+	want := []disabledSource{
+		{"gmail_home", "gmail", "enabled: false"},
+		{"jira_legacy", "jira", "enabled: false"},
+		{"slack_eng", "slack", "not listed in sync.enabled_sources"},
+	}
+
+	if len(got) != len(want) {
+		t.Fatalf("disabledSources() = %+v, want %+v", got, want)
+	}
+
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("disabledSources()[%d] = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+
+	if gmail := disabledSources(cfg, "gmail"); len(gmail) != 1 || gmail[0].Name != "gmail_home" {
+		t.Errorf("disabledSources(gmail) = %+v, want only gmail_home", gmail)
+	}
+}
+
+func TestNoEnabledSourcesError(t *testing.T) {
+	cfg := sourceSelectionConfig()
+	for name, sc := range cfg.Sources {
+		sc.Enabled = false
+		cfg.Sources[name] = sc
+	}
+
+	msg := noEnabledSourcesError(cfg, "").Error()
+	for _, want := range []string{
+		"no enabled sources found",
+		"gmail_home (gmail): enabled: false",
+		"slack_eng (slack): enabled: false",
+		"enabled: true",
+		"pkm-sync sync --sources all",
+	} {
+		if !strings.Contains(msg, want) {
+			t.Errorf("error missing %q:\n%s", want, msg)
+		}
+	}
+
+	empty := noEnabledSourcesError(&models.Config{}, "").Error()
+	if !strings.Contains(empty, "no sources configured") || !strings.Contains(empty, "config init") {
+		t.Errorf("unexpected error for an empty config: %s", empty)
+	}
+}
+
+func TestSelectSyncSources(t *testing.T) {
+	cfg := sourceSelectionConfig()
+
+	all, err := selectSyncSources(cfg, "all")
+	if err != nil || strings.Join(all, ",") != "gmail_home,gmail_work,jira_legacy,slack_eng" {
+		t.Errorf("--sources all = %v, %v; want every configured source", all, err)
+	}
+
+	list, err := selectSyncSources(cfg, "slack_eng, gmail,gmail_work")
+	if err != nil || strings.Join(list, ",") != "slack_eng,gmail_work" {
+		t.Errorf("--sources list = %v, %v; want [slack_eng gmail_work]", list, err)
+	}
+
+	if _, err := selectSyncSources(cfg, "slack_eng,jirra"); err == nil || !strings.Contains(err.Error(), `"jira"`) {
+		t.Errorf("expected unknown-source error suggesting jira, got %v", err)
+	}
+
+	if _, err := selectSyncSources(cfg, "drive"); err == nil || !strings.Contains(err.Error(), "google_drive") {
+		t.Errorf("expected no-enabled-sources error for drive, got %v", err)
+	}
+}
+
+// fakeRawSource declares ProvidesRawMessages according to provides.
+type fakeRawSource struct {
+	fakeSource
+	provides bool
+	fetcher  interfaces.RawMessageFetcher
+}
+
+func (f *fakeRawSource) Capabilities() interfaces.SourceCapabilities {
+	return interfaces.SourceCapabilities{ProvidesRawMessages: f.provides}
+}
+
+func (f *fakeRawSource) RawMessageFetcher() interfaces.RawMessageFetcher { return f.fetcher }
+
+type fakeRawFetcher struct{ name string }
+
+func (f *fakeRawFetcher) GetMessageRaw(_ string) ([]byte, error) { return []byte(f.name), nil }
+
+func TestRawMessageFetcherFromEntries(t *testing.T) {
+	undeclared := &fakeRawFetcher{name: "undeclared"}
+	declared := &fakeRawFetcher{name: "declared"}
+
+	entries := []syncer.SourceEntry{
+		{Name: "calendar", Src: &fakeSource{name: "calendar"}},
+		{Name: "unconfigured", Src: &fakeRawSource{provides: true}},
+		{Name: "undeclared", Src: &fakeRawSource{fetcher: undeclared}},
+		{Name: "mail", Src: &fakeRawSource{provides: true, fetcher: declared}},
+	}
+
+	if got := rawMessageFetcherFromEntries(entries); got != declared {
+		t.Errorf("expected fetcher of the source declaring raw messages, got %v", got)
+	}
+
+	if got := rawMessageFetcherFromEntries(entries[:3]); got != nil {
+		t.Errorf("expected nil without a configured raw message source, got %v", got)
+	}
+}
+
+func TestOutputPathCollisions(t *testing.T) {
+	cfg := &models.Config{
+		Sources: map[string]models.SourceConfig{
+			"jira_work":   {Enabled: true, Type: "jira"},
+			"drive_notes": {Enabled: true, Type: "google_drive"},
+			"calendar":    {Enabled: true, Type: "google_calendar", OutputSubdir: "calendar"},
+			"gmail_work":  {Enabled: true, Type: "gmail"},
+			"slack_eng":   {Enabled: true, Type: "slack"},
+		},
+	}
+	typeGroups := map[string][]string{
+		"jira":            {"jira_work"},
+		"google_drive":    {"drive_notes"},
+		"google_calendar": {"calendar"},
+		"gmail":           {"gmail_work"},
+		"slack":           {"slack_eng"},
+	}
+
+	collisions := outputPathCollisions(cfg, typeGroups, "/vault")
+	if len(collisions) != 1 {
+		t.Fatalf("expected 1 collision, got %v", collisions)
+	}
+
+	if collisions[0].Dir != "/vault" || strings.Join(collisions[0].Sources, ",") != "drive_notes,jira_work" {
+		t.Errorf("unexpected collision: %+v", collisions[0])
+	}
+
+	if err := checkOutputPaths(cfg, typeGroups, "/vault", false); err != nil {
+		t.Errorf("expected only a warning without strict, got %v", err)
+	}
+
+	err := checkOutputPaths(cfg, typeGroups, "/vault", true)
+	if err == nil || !strings.Contains(err.Error(), "drive_notes, jira_work") ||
+		!strings.Contains(err.Error(), "output_subdir") {
+		t.Errorf("expected a strict error naming both sources, got %v", err)
+	}
+
+	cfg.Sources["jira_work"] = models.SourceConfig{Enabled: true, Type: "jira", OutputSubdir: "jira"}
+	if got := outputPathCollisions(cfg, typeGroups, "/vault"); len(got) != 0 {
+		t.Errorf("expected no collision with distinct output_subdirs, got %v", got)
+	}
+}
+
+func TestOutputPathCollisions_SlackExportToVault(t *testing.T) {
+	cfg := &models.Config{
+		Sources: map[string]models.SourceConfig{
+			"jira_work": {Enabled: true, Type: "jira"},
+			"slack_eng": {Enabled: true, Type: "slack", Slack: models.SlackSourceConfig{ExportToVault: true}},
+		},
+	}
+
+	collisions := outputPathCollisions(cfg, map[string][]string{"jira": {"jira_work"}, "slack": {"slack_eng"}}, "/vault")
+	if len(collisions) != 1 || strings.Join(collisions[0].Sources, ",") != "jira_work,slack_eng" {
+		t.Errorf("expected an exporting Slack source to collide with jira_work, got %v", collisions)
+	}
+}
+
+func TestWritesVaultFiles(t *testing.T) {
+	cfg := &models.Config{
+		Sources: map[string]models.SourceConfig{
+			"slack_eng":  {Type: "slack", Slack: models.SlackSourceConfig{ExportToVault: true}},
+			"slack_ops":  {Type: "slack", Slack: models.SlackSourceConfig{ExportToVault: true}},
+			"slack_chat": {Type: "slack"},
+			"gmail_work": {Type: "gmail"},
+			"jira_work":  {Type: "jira"},
+		},
+	}
+
+	tests := []struct {
+		sourceType string
+		sources    []string
+		want       bool
+	}{
+		{"jira", []string{"jira_work"}, true},
+		{"gmail", []string{"gmail_work"}, false},
+		{"slack", []string{"slack_chat"}, false},
+		{"slack", []string{"slack_eng", "slack_ops"}, true},
+		{"slack", []string{"slack_eng", "slack_chat"}, false},
+		{"slack", nil, false},
+	}
+
+	for _, tt := range tests {
+		if got := writesVaultFiles(cfg, tt.sourceType, tt.sources); got != tt.want {
+			t.Errorf("writesVaultFiles(%s, %v) = %v, want %v", tt.sourceType, tt.sources, got, tt.want)
+		}
+	}
+}
+
+func TestOutputPathCollisions_GroupWithMixedSubdirs(t *testing.T) {
+	// Sources of one type that disagree on output_subdir all fall back to the
+	// base directory, where they collide with each other.
+	cfg := &models.Config{
+		Sources: map[string]models.SourceConfig{
+			"jira_a": {Enabled: true, Type: "jira", OutputSubdir: "a"},
+			"jira_b": {Enabled: true, Type: "jira", OutputSubdir: "b"},
+		},
+	}
+
+	collisions := outputPathCollisions(cfg, map[string][]string{"jira": {"jira_a", "jira_b"}}, "/vault")
+	if len(collisions) != 1 || collisions[0].Dir != "/vault" {
+		t.Errorf("expected the mixed group to collide in the base dir, got %v", collisions)
+	}
+}
+
+func TestSourceSyncConfigIncremental(t *testing.T) {
+	tests := []struct {
+		name string
+		ssc  sourceSyncConfig
+		want bool
+	}{
+		{"defaults", sourceSyncConfig{}, true},
+		{"explicit since", sourceSyncConfig{SinceFlag: "7d"}, false},
+		{"full", sourceSyncConfig{Full: true}, false},
+		{"until", sourceSyncConfig{Until: "2024-01-01"}, false},
+	}
+
+	for _, tt := range tests {
+		if got := tt.ssc.incremental(); got != tt.want {
+			t.Errorf("%s: incremental() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestSortedForPreview(t *testing.T) {
+	item := func(source, id string) models.FullItem {
+		it := models.AsFullItem(&models.Item{ID: id})
+		it.SetMetadata(map[string]any{models.MetadataSourceName: source})
+
+		return it
+	}
+
+	items := []models.FullItem{item("slack", "b"), item("gmail", "z"), item("slack", "a")}
+
+	got := sortedForPreview(items)
+
+	want := []string{"z", "a", "b"}
+	for i, id := range want {
+		if got[i].GetID() != id {
+			t.Errorf("position %d: got %s, want %s", i, got[i].GetID(), id)
+		}
+	}
+
+	if items[0].GetID() != "b" {
+		t.Error("sortedForPreview reordered its input")
+	}
+}
+
+func TestSourceSyncConfigRecordsState(t *testing.T) {
+	if !(sourceSyncConfig{}).recordsState() {
+		t.Error("a regular sync should record state")
+	}
+
+	// fetch --id resyncs one item, which must not move the high-water mark.
+	if (sourceSyncConfig{ItemID: "thread-42"}).recordsState() {
+		t.Error("a single-item resync should not record state")
+	}
+
+	for name, ssc := range map[string]sourceSyncConfig{
+		"no-files":   {NoFiles: true},
+		"no-index":   {NoIndex: true},
+		"no-archive": {NoArchive: true},
+	} {
+		if ssc.recordsState() {
+			t.Errorf("a %s run should not record state", name)
+		}
+	}
+}
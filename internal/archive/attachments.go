@@ -0,0 +1,197 @@
+package archive
+
+import (
+	"encoding/base64"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Attachment is a file attached to an archived message.
+type Attachment struct {
+	Name     string
+	MimeType string
+	Data     []byte
+}
+
+// AttachmentPolicy limits which attachments are extracted, mirroring the
+// Gmail attachment_types and max_attachment_size settings.
+type AttachmentPolicy struct {
+	// Types lists allowed file extensions without the dot; empty allows all.
+	Types []string
+	// MaxBytes is the largest attachment kept; 0 means no limit.
+	MaxBytes int64
+}
+
+// Allows reports whether the policy keeps att, and why not when it does not.
+// An attachment without an extension is matched by its MIME type.
+func (p AttachmentPolicy) Allows(att Attachment) (bool, string) {
+	if p.MaxBytes > 0 && int64(len(att.Data)) > p.MaxBytes {
+		return false, fmt.Sprintf("%d bytes exceeds max_attachment_size (%d)", len(att.Data), p.MaxBytes)
+	}
+
+	if len(p.Types) == 0 {
+		return true, ""
+	}
+
+	ext := strings.TrimPrefix(strings.ToLower(path.Ext(att.Name)), ".")
+	if ext == "" {
+		if exts, _ := mime.ExtensionsByType(att.MimeType); len(exts) > 0 {
+			ext = strings.TrimPrefix(exts[0], ".")
+		}
+	}
+
+	for _, allowed := range p.Types {
+		if ext != "" && strings.EqualFold(strings.TrimPrefix(allowed, "."), ext) {
+			return true, ""
+		}
+	}
+
+	return false, fmt.Sprintf("type %q not in attachment_types", ext)
+}
+
+// ParseByteSize parses sizes such as "10MB", "512KB", "1.5 GB" or "2048"
+// (bytes) using binary multiples. An empty string is 0 (no limit).
+func ParseByteSize(s string) (int64, error) {
+	s = strings.ToUpper(strings.TrimSpace(s))
+	if s == "" {
+		return 0, nil
+	}
+
+	split := strings.IndexFunc(s, func(r rune) bool { return unicode.IsLetter(r) })
+	number, unit := s, ""
+
+	if split >= 0 {
+		number, unit = strings.TrimSpace(s[:split]), s[split:]
+	}
+
+	value, err := strconv.ParseFloat(number, 64)
+	if err != nil || value < 0 {
+		return 0, fmt.Errorf("invalid size %q", s)
+	}
+
+	multipliers := map[string]float64{
+		"": 1, "B": 1,
+		"K": 1 << 10, "KB": 1 << 10, "KIB": 1 << 10,
+		"M": 1 << 20, "MB": 1 << 20, "MIB": 1 << 20,
+		"G": 1 << 30, "GB": 1 << 30, "GIB": 1 << 30,
+	}
+
+	multiplier, ok := multipliers[unit]
+	if !ok {
+		return 0, fmt.Errorf("invalid size unit in %q (use B, KB, MB or GB)", s)
+	}
+
+	return int64(value * multiplier), nil
+}
+
+// ReadEMLAttachments parses the archived .eml file at emlPath and returns its
+// attachments in message order.
+func ReadEMLAttachments(emlPath string) ([]Attachment, error) {
+	f, err := os.Open(emlPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s: %w", emlPath, err)
+	}
+	defer f.Close()
+
+	msg, err := mail.ReadMessage(f)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", emlPath, err)
+	}
+
+	var attachments []Attachment
+	if err := collectAttachments(mimeHeader(msg.Header), msg.Body, &attachments); err != nil {
+		return nil, fmt.Errorf("failed to read attachments of %s: %w", emlPath, err)
+	}
+
+	return attachments, nil
+}
+
+// mimeHeader is the part of a message or multipart part header used here.
+type mimeHeader interface {
+	Get(key string) string
+}
+
+// collectAttachments walks a MIME entity, descending into multipart
+// containers. A part is an attachment when its disposition is "attachment"
+// or it carries a filename.
+func collectAttachments(header mimeHeader, body io.Reader, out *[]Attachment) error {
+	mediaType, params, err := mime.ParseMediaType(header.Get("Content-Type"))
+	if err != nil {
+		mediaType = "text/plain"
+	}
+
+	if strings.HasPrefix(mediaType, "multipart/") {
+		reader := multipart.NewReader(body, params["boundary"])
+
+		for {
+			part, err := reader.NextPart()
+			if err == io.EOF {
+				return nil
+			}
+
+			if err != nil {
+				return err
+			}
+
+			if err := collectAttachments(part.Header, part, out); err != nil {
+				return err
+			}
+		}
+	}
+
+	disposition, dispParams, _ := mime.ParseMediaType(header.Get("Content-Disposition"))
+
+	name := dispParams["filename"]
+	if name == "" {
+		name = params["name"]
+	}
+
+	if disposition != "attachment" && name == "" {
+		return nil
+	}
+
+	if name == "" {
+		name = "attachment"
+		if exts, _ := mime.ExtensionsByType(mediaType); len(exts) > 0 {
+			name += exts[0]
+		}
+	}
+
+	switch strings.ToLower(header.Get("Content-Transfer-Encoding")) {
+	case "base64":
+		body = base64.NewDecoder(base64.StdEncoding, body)
+	case "quoted-printable":
+		body = quotedprintable.NewReader(body)
+	}
+
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return fmt.Errorf("failed to decode attachment %q: %w", name, err)
+	}
+
+	*out = append(*out, Attachment{Name: decodeWords(name), MimeType: mediaType, Data: data})
+
+	return nil
+}
+
+var wordDecoder = new(mime.WordDecoder)
+
+// decodeWords decodes RFC 2047 encoded words in a filename, keeping the raw
+// value when they are malformed.
+func decodeWords(s string) string {
+	decoded, err := wordDecoder.DecodeHeader(s)
+	if err != nil {
+		return s
+	}
+
+	return decoded
+}
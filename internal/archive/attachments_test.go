@@ -0,0 +1,71 @@
+package archive
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadEMLAttachments(t *testing.T) {
+	attachments, err := ReadEMLAttachments("testdata/report.eml")
+	require.NoError(t, err)
+	require.Len(t, attachments, 3)
+
+	assert.Equal(t, "report.pdf", attachments[0].Name)
+	assert.Equal(t, "application/pdf", attachments[0].MimeType)
+	assert.Equal(t, "%PDF-1.4 fake report\n", string(attachments[0].Data))
+
+	assert.Equal(t, "grafik-ü.png", attachments[1].Name)
+	assert.Equal(t, "image/png", attachments[1].MimeType)
+	require.Len(t, attachments[1].Data, 64)
+	assert.Equal(t, byte(63), attachments[1].Data[63])
+
+	assert.Equal(t, "notes.txt", attachments[2].Name)
+	assert.Equal(t, "café notes", string(attachments[2].Data))
+}
+
+func TestReadEMLAttachments_MissingFile(t *testing.T) {
+	_, err := ReadEMLAttachments("testdata/missing.eml")
+	assert.Error(t, err)
+}
+
+func TestAttachmentPolicy_Allows(t *testing.T) {
+	pdf := Attachment{Name: "report.pdf", MimeType: "application/pdf", Data: make([]byte, 100)}
+	noExt := Attachment{Name: "scan", MimeType: "application/pdf", Data: make([]byte, 10)}
+
+	allowed, _ := AttachmentPolicy{}.Allows(pdf)
+	assert.True(t, allowed)
+
+	allowed, _ = AttachmentPolicy{Types: []string{"PDF"}}.Allows(noExt)
+	assert.True(t, allowed)
+
+	allowed, reason := AttachmentPolicy{Types: []string{"jpg"}}.Allows(pdf)
+	assert.False(t, allowed)
+	assert.Contains(t, reason, "attachment_types")
+
+	allowed, reason = AttachmentPolicy{MaxBytes: 50}.Allows(pdf)
+	assert.False(t, allowed)
+	assert.Contains(t, reason, "max_attachment_size")
+}
+
+func TestParseByteSize(t *testing.T) {
+	tests := map[string]int64{
+		"":       0,
+		"2048":   2048,
+		"10MB":   10 << 20,
+		"512 kb": 512 << 10,
+		"1.5GB":  3 << 29,
+	}
+
+	for in, want := range tests {
+		got, err := ParseByteSize(in)
+		require.NoError(t, err, in)
+		assert.Equal(t, want, got, in)
+	}
+
+	for _, in := range []string{"ten MB", "5TB", "-1"} {
+		_, err := ParseByteSize(in)
+		assert.Error(t, err, in)
+	}
+}
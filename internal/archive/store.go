@@ -3,6 +3,7 @@ package archive
 import (
 	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"time"
 
@@ -124,6 +125,21 @@ func (s *Store) HasMessage(gmailID string) (bool, error) {
 	return count > 0, nil
 }
 
+// EMLPath returns the .eml file a message was archived to. found is false
+// when the message is not in the archive.
+func (s *Store) EMLPath(gmailID string) (path string, found bool, err error) {
+	err = s.db.QueryRow("SELECT eml_path FROM messages WHERE gmail_id = ?", gmailID).Scan(&path)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", false, nil
+	}
+
+	if err != nil {
+		return "", false, fmt.Errorf("failed to look up message %s: %w", gmailID, err)
+	}
+
+	return path, true, nil
+}
+
 // GetArchivedIDs returns a set of all archived Gmail IDs for a given source.
 // This mirrors vectorstore.GetIndexedThreadIDs for batch dedup.
 func (s *Store) GetArchivedIDs(sourceName string) (map[string]bool, error) {
@@ -321,6 +337,75 @@ func (s *Store) Search(query string, limit int) ([]FTSResult, error) {
 	return results, rows.Err()
 }
 
+// StoredMessage is an archived message together with the body text indexed for search.
+type StoredMessage struct {
+	Message
+	Body string
+}
+
+// ListMessages returns archived messages, newest first, with their indexed body
+// text. An empty sourceName lists every source; a limit of zero or less means no limit.
+func (s *Store) ListMessages(sourceName string, limit int) ([]StoredMessage, error) {
+	query := `
+		SELECT m.gmail_id, m.thread_id, m.rfc822_message_id, m.subject, m.from_addr,
+			m.to_addrs, m.cc_addrs, m.date_sent, m.labels, m.eml_path,
+			m.size_bytes, m.has_attachments, m.source_name, COALESCE(f.body, '')
+		FROM messages m
+		LEFT JOIN messages_fts f ON f.rowid = m.rowid
+		WHERE ? = '' OR m.source_name = ?
+		ORDER BY m.date_sent DESC
+	`
+	args := []any{sourceName, sourceName}
+
+	if limit > 0 {
+		query += " LIMIT ?"
+
+		args = append(args, limit)
+	}
+
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list archived messages: %w", err)
+	}
+	defer rows.Close()
+
+	var messages []StoredMessage
+
+	for rows.Next() {
+		var (
+			m                          StoredMessage
+			toJSON, ccJSON, labelsJSON string
+			sentStr                    string
+		)
+
+		if err := rows.Scan(
+			&m.GmailID, &m.ThreadID, &m.RFC822MessageID, &m.Subject, &m.FromAddr,
+			&toJSON, &ccJSON, &sentStr, &labelsJSON, &m.EMLPath,
+			&m.SizeBytes, &m.HasAttachments, &m.SourceName, &m.Body,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan archived message: %w", err)
+		}
+
+		m.DateSent, _ = time.Parse(time.RFC3339, sentStr)
+
+		if err := json.Unmarshal([]byte(toJSON), &m.ToAddrs); err != nil {
+			return nil, fmt.Errorf("failed to parse to_addrs for %s: %w", m.GmailID, err)
+		}
+
+		if err := json.Unmarshal([]byte(ccJSON), &m.CCAddrs); err != nil {
+			return nil, fmt.Errorf("failed to parse cc_addrs for %s: %w", m.GmailID, err)
+		}
+
+		if err := json.Unmarshal([]byte(labelsJSON), &m.Labels); err != nil {
+			return nil, fmt.Errorf("failed to parse labels for %s: %w", m.GmailID, err)
+		}
+
+		messages = append(messages, m)
+	}
+
+	return messages, rows.Err()
+}
+
 // Close closes the database connection.
 func (s *Store) Close() error {
 	return s.db.Close()
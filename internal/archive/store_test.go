@@ -53,6 +53,22 @@ func TestHasMessage(t *testing.T) {
 	assert.True(t, ok)
 }
 
+func TestEMLPath(t *testing.T) {
+	store := newTestStore(t)
+
+	_, found, err := store.EMLPath("msg1")
+	require.NoError(t, err)
+	assert.False(t, found)
+
+	msg := testMessage("msg1")
+	require.NoError(t, store.IndexMessage(msg, "test body"))
+
+	path, found, err := store.EMLPath("msg1")
+	require.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, msg.EMLPath, path)
+}
+
 func TestGetArchivedIDs(t *testing.T) {
 	store := newTestStore(t)
 
@@ -119,6 +135,30 @@ func TestSearch_FTS(t *testing.T) {
 	assert.Equal(t, "fts2", results[0].GmailID)
 }
 
+func TestListMessages(t *testing.T) {
+	store := newTestStore(t)
+
+	older := testMessageForSource("l1", "work")
+	older.DateSent = time.Now().Add(-48 * time.Hour)
+
+	require.NoError(t, store.IndexMessage(older, "older body"))
+	require.NoError(t, store.IndexMessage(testMessageForSource("l2", "work"), "newer body"))
+	require.NoError(t, store.IndexMessage(testMessageForSource("l3", "personal"), "personal body"))
+
+	msgs, err := store.ListMessages("work", 0)
+	require.NoError(t, err)
+	require.Len(t, msgs, 2)
+	assert.Equal(t, "l2", msgs[0].GmailID)
+	assert.Equal(t, "newer body", msgs[0].Body)
+	assert.Equal(t, []string{"recipient@example.com"}, msgs[0].ToAddrs)
+	assert.Equal(t, []string{"INBOX"}, msgs[0].Labels)
+	assert.Equal(t, "older body", msgs[1].Body)
+
+	all, err := store.ListMessages("", 1)
+	require.NoError(t, err)
+	assert.Len(t, all, 1)
+}
+
 func TestStats(t *testing.T) {
 	store := newTestStore(t)
 
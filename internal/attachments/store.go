@@ -0,0 +1,127 @@
+// Package attachments keeps a persistent content-hash index of downloaded
+// attachment files, so a file already stored by an earlier sync run is
+// referenced instead of written to disk again.
+package attachments
+
+import (
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+// DefaultDBFile is the index file name under the config directory.
+const DefaultDBFile = "attachments.db"
+
+// Store is a SQLite-backed index from SHA-256 content hash to the path the
+// content was first stored at. It is safe for concurrent use; one Store is
+// shared by every source of a sync run.
+type Store struct {
+	mu sync.Mutex
+	db *sql.DB
+}
+
+// NewStore opens or creates the attachment index at dbPath.
+func NewStore(dbPath string) (*Store, error) {
+	if err := os.MkdirAll(filepath.Dir(dbPath), 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create attachment index directory: %w", err)
+	}
+
+	db, err := sql.Open("sqlite3", dbPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open attachment index: %w", err)
+	}
+
+	if _, err := db.Exec("PRAGMA journal_mode=WAL"); err != nil {
+		db.Close()
+
+		return nil, fmt.Errorf("failed to enable WAL mode: %w", err)
+	}
+
+	schema := `
+		CREATE TABLE IF NOT EXISTS attachments (
+			sha256     TEXT PRIMARY KEY,
+			path       TEXT NOT NULL,
+			size_bytes INTEGER NOT NULL,
+			stored_at  DATETIME NOT NULL
+		);
+	`
+	if _, err := db.Exec(schema); err != nil {
+		db.Close()
+
+		return nil, fmt.Errorf("failed to create attachment index schema: %w", err)
+	}
+
+	return &Store{db: db}, nil
+}
+
+// Save stores data at dest unless identical content was stored before, in
+// which case nothing is written and the earlier path is returned. An indexed
+// file that has since been deleted or changed size is replaced by dest.
+func (s *Store) Save(dest string, data []byte) (string, error) {
+	sum := sha256.Sum256(data)
+	hash := hex.EncodeToString(sum[:])
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	existing, err := s.lookup(hash, int64(len(data)))
+	if err != nil {
+		return "", err
+	}
+
+	if existing != "" {
+		return existing, nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(dest), 0o755); err != nil {
+		return "", fmt.Errorf("failed to create attachment directory: %w", err)
+	}
+
+	if err := os.WriteFile(dest, data, 0o644); err != nil {
+		return "", fmt.Errorf("failed to save attachment: %w", err)
+	}
+
+	_, err = s.db.Exec(
+		`INSERT OR REPLACE INTO attachments (sha256, path, size_bytes, stored_at) VALUES (?, ?, ?, ?)`,
+		hash, dest, len(data), time.Now().UTC(),
+	)
+	if err != nil {
+		return "", fmt.Errorf("failed to index attachment: %w", err)
+	}
+
+	return dest, nil
+}
+
+// lookup returns the indexed path for hash when that file still exists with
+// the expected size, and "" otherwise.
+func (s *Store) lookup(hash string, size int64) (string, error) {
+	var path string
+
+	err := s.db.QueryRow(`SELECT path FROM attachments WHERE sha256 = ?`, hash).Scan(&path)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", nil
+	}
+
+	if err != nil {
+		return "", fmt.Errorf("failed to query attachment index: %w", err)
+	}
+
+	if info, err := os.Stat(path); err != nil || info.Size() != size {
+		return "", nil
+	}
+
+	return path, nil
+}
+
+// Close closes the index database.
+func (s *Store) Close() error {
+	return s.db.Close()
+}
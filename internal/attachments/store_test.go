@@ -0,0 +1,82 @@
+package attachments
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func openStore(t *testing.T, dbPath string) *Store {
+	t.Helper()
+
+	store, err := NewStore(dbPath)
+	require.NoError(t, err)
+
+	t.Cleanup(func() { store.Close() })
+
+	return store
+}
+
+func TestSave_SecondRunReusesStoredFile(t *testing.T) {
+	dir := t.TempDir()
+	dbPath := filepath.Join(dir, DefaultDBFile)
+	content := []byte("%PDF-1.7 quarterly report")
+
+	first := openStore(t, dbPath)
+	path, err := first.Save(filepath.Join(dir, "run1", "report.pdf"), content)
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(dir, "run1", "report.pdf"), path)
+	require.NoError(t, first.Close())
+
+	// A later run opens the index afresh and receives the same content under
+	// another name.
+	second := openStore(t, dbPath)
+	dest := filepath.Join(dir, "run2", "report (1).pdf")
+
+	reused, err := second.Save(dest, content)
+	require.NoError(t, err)
+	assert.Equal(t, path, reused)
+
+	_, err = os.Stat(dest)
+	assert.True(t, os.IsNotExist(err), "no new file should be written for known content")
+}
+
+func TestSave_DifferentContentIsWritten(t *testing.T) {
+	dir := t.TempDir()
+	store := openStore(t, filepath.Join(dir, DefaultDBFile))
+
+	a, err := store.Save(filepath.Join(dir, "a.txt"), []byte("alpha"))
+	require.NoError(t, err)
+
+	b, err := store.Save(filepath.Join(dir, "b.txt"), []byte("beta"))
+	require.NoError(t, err)
+
+	assert.NotEqual(t, a, b)
+
+	data, err := os.ReadFile(b)
+	require.NoError(t, err)
+	assert.Equal(t, "beta", string(data))
+}
+
+func TestSave_ReplacesMissingIndexedFile(t *testing.T) {
+	dir := t.TempDir()
+	store := openStore(t, filepath.Join(dir, DefaultDBFile))
+
+	original, err := store.Save(filepath.Join(dir, "old.bin"), []byte("payload"))
+	require.NoError(t, err)
+	require.NoError(t, os.Remove(original))
+
+	dest := filepath.Join(dir, "new.bin")
+
+	path, err := store.Save(dest, []byte("payload"))
+	require.NoError(t, err)
+	assert.Equal(t, dest, path)
+
+	// The index now points at the new copy.
+	again, err := store.Save(filepath.Join(dir, "third.bin"), []byte("payload"))
+	require.NoError(t, err)
+	assert.Equal(t, dest, again)
+}
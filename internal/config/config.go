@@ -1,10 +1,16 @@
 package config
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
 
+	"pkm-sync/internal/transform"
 	"pkm-sync/pkg/models"
 
 	"gopkg.in/yaml.v3"
@@ -12,6 +18,9 @@ import (
 
 const ConfigFileName = "config.yaml"
 
+// ErrNoConfigFile is returned by LoadConfig when no search path holds a config file.
+var ErrNoConfigFile = errors.New("no config file found")
+
 // Source and target type constants used throughout the config package.
 const (
 	sourceTypeGoogleCalendar = "google_calendar"
@@ -19,6 +28,8 @@ const (
 	sourceTypeGoogleDrive    = "google_drive"
 	targetTypeObsidian       = "obsidian"
 	targetTypeLogseq         = "logseq"
+	targetTypeRoam           = "roam"
+	targetTypeJSONL          = "jsonl"
 	exportFormatHTML         = "html"
 )
 
@@ -36,7 +47,20 @@ func LoadConfig() (*models.Config, error) {
 		}
 	}
 
-	return nil, fmt.Errorf("no config file found in search paths: %v", configPaths)
+	return nil, fmt.Errorf("%w in search paths: %v", ErrNoConfigFile, configPaths)
+}
+
+// LoadConfigOrDefault loads the configuration like LoadConfig, falling back
+// to GetDefaultConfig only when there is no config file. A config file that
+// exists but cannot be loaded is an error, so its settings are never dropped
+// silently.
+func LoadConfigOrDefault() (*models.Config, error) {
+	cfg, err := LoadConfig()
+	if errors.Is(err, ErrNoConfigFile) {
+		return GetDefaultConfig(), nil
+	}
+
+	return cfg, err
 }
 
 // SaveConfig saves configuration to the appropriate location.
@@ -113,6 +137,7 @@ func GetDefaultConfig() *models.Config {
 					Recursive:       true,
 					WorkspaceTypes:  []string{},
 					DocExportFormat: "md",
+					BinaryFileTypes: []string{},
 				},
 			},
 		},
@@ -225,6 +250,11 @@ func loadConfigFromFile(configPath string) (*models.Config, error) {
 
 	applyEnvOverrides(&cfg)
 
+	// Catch transformer typos before a sync makes any network calls.
+	if err := transform.ValidateConfig(cfg.Transformers); err != nil {
+		return nil, fmt.Errorf("invalid transformers in config file %s: %w", configPath, err)
+	}
+
 	return &cfg, nil
 }
 
@@ -243,12 +273,31 @@ func expandConfigPaths(cfg *models.Config) error {
 		&cfg.App.LogFile,
 		&cfg.App.BackupDir,
 		&cfg.App.CacheDir,
+		&cfg.App.HTTP.CABundle,
+		&cfg.App.HTTP.ClientCert,
+		&cfg.App.HTTP.ClientKey,
+		&cfg.Sync.DeadLetterPath,
+		&cfg.Sync.SummaryPath,
 	} {
 		if *field, err = ExpandPath(*field); err != nil {
 			return err
 		}
 	}
 
+	for name, src := range cfg.Sources {
+		if src.JSONL.Path != "" && src.JSONL.Path != "-" {
+			if src.JSONL.Path, err = ExpandPath(src.JSONL.Path); err != nil {
+				return err
+			}
+		}
+
+		if src.LocalFS.RootDir, err = ExpandPath(src.LocalFS.RootDir); err != nil {
+			return err
+		}
+
+		cfg.Sources[name] = src
+	}
+
 	return nil
 }
 
@@ -281,6 +330,20 @@ func ValidateConfig(cfg *models.Config) error {
 		return fmt.Errorf("targets configuration error: %w", err)
 	}
 
+	if err := transform.ValidateConfig(cfg.Transformers); err != nil {
+		return fmt.Errorf("transformers configuration error: %w", err)
+	}
+
+	if cfg.VectorDB.MinScore < -1 || cfg.VectorDB.MinScore > 1 {
+		return fmt.Errorf("vector_db configuration error: min_score must be between -1 and 1, got %g", cfg.VectorDB.MinScore)
+	}
+
+	if cfg.App.Timezone != "" {
+		if _, err := time.LoadLocation(cfg.App.Timezone); err != nil {
+			return fmt.Errorf("app configuration error: invalid timezone %q: %w", cfg.App.Timezone, err)
+		}
+	}
+
 	// Validate enabled sources exist and are configured
 	for _, sourceName := range cfg.Sync.EnabledSources {
 		if sourceConfig, exists := cfg.Sources[sourceName]; !exists {
@@ -316,6 +379,18 @@ func validateSyncConfig(sync *models.SyncConfig) error {
 		return fmt.Errorf("at least one source must be enabled")
 	}
 
+	switch sync.SubdirFormat {
+	case "", "yyyy/mm", "yyyy-mm", "source", "flat":
+	default:
+		return fmt.Errorf("subdir_format must be 'yyyy/mm', 'yyyy-mm', 'source' or 'flat', got '%s'", sync.SubdirFormat)
+	}
+
+	switch sync.DeduplicateBy {
+	case "", "id", "title", "content", "none":
+	default:
+		return fmt.Errorf("deduplicate_by must be 'id', 'title', 'content' or 'none', got '%s'", sync.DeduplicateBy)
+	}
+
 	return nil
 }
 
@@ -334,22 +409,77 @@ func validateSources(sources map[string]models.SourceConfig) error {
 	return nil
 }
 
+// ValidateSource validates one source's settings, as ValidateConfig does for
+// every configured source.
+func ValidateSource(config models.SourceConfig) error {
+	return validateSourceConfig("", config)
+}
+
 // validateSourceConfig validates an individual source configuration.
 func validateSourceConfig(_ string, config models.SourceConfig) error {
 	if config.Type == "" {
 		return fmt.Errorf("type is required")
 	}
 
+	switch config.OnEmptyResult {
+	case "", "ignore", "warn", "error":
+	default:
+		return fmt.Errorf("invalid on_empty_result %q (supported: ignore, warn, error)", config.OnEmptyResult)
+	}
+
 	// Validate type-specific configurations
 	switch config.Type {
 	case sourceTypeGoogleCalendar:
 		if config.Google.CalendarID == "" {
 			return fmt.Errorf("calendar_id is required for google_calendar sources")
 		}
+
+		for _, status := range config.Google.ResponseStatuses {
+			switch status {
+			case "accepted", "tentative", "declined", "needsAction":
+			default:
+				return fmt.Errorf(
+					"invalid response_statuses entry %q for google_calendar (supported: accepted, tentative, declined, needsAction)",
+					status)
+			}
+		}
 	case sourceTypeGmail:
 		if config.Gmail.Name == "" {
 			return fmt.Errorf("name is required for gmail sources")
 		}
+
+		switch config.Gmail.UndatedMessages {
+		case "", "skip", "sentinel", "now":
+		default:
+			return fmt.Errorf("invalid undated_messages %q for gmail (supported: skip, sentinel, now)",
+				config.Gmail.UndatedMessages)
+		}
+
+		switch config.Gmail.EmbeddedMessages {
+		case "", "off", "inline", "item":
+		default:
+			return fmt.Errorf("invalid embedded_messages %q for gmail (supported: off, inline, item)",
+				config.Gmail.EmbeddedMessages)
+		}
+
+		if config.Gmail.MinConcurrency < 0 || config.Gmail.MaxConcurrency < 0 {
+			return fmt.Errorf("min_concurrency and max_concurrency for gmail must not be negative")
+		}
+
+		if config.Gmail.MaxConcurrency > 0 && config.Gmail.MinConcurrency > config.Gmail.MaxConcurrency {
+			return fmt.Errorf("min_concurrency (%d) for gmail exceeds max_concurrency (%d)",
+				config.Gmail.MinConcurrency, config.Gmail.MaxConcurrency)
+		}
+
+		if err := validateFilenameTemplate(config.Gmail.FilenameTemplate, gmailFilenameTokens); err != nil {
+			return err
+		}
+
+		if d := config.Gmail.UndatedSentinelDate; d != "" {
+			if _, err := time.Parse(time.DateOnly, d); err != nil {
+				return fmt.Errorf("invalid undated_sentinel_date %q for gmail (want YYYY-MM-DD)", d)
+			}
+		}
 	case sourceTypeGoogleDrive:
 		if config.Drive.Name == "" {
 			return fmt.Errorf("name is required for google_drive sources")
@@ -405,6 +535,28 @@ func validateSourceConfig(_ string, config models.SourceConfig) error {
 		if config.ServiceNow.InstanceURL == "" {
 			return fmt.Errorf("instance_url is required for servicenow sources")
 		}
+	case "notion":
+		if len(config.Notion.DatabaseIDs) == 0 && len(config.Notion.PageIDs) == 0 {
+			return fmt.Errorf("notion source requires 'database_ids' or 'page_ids' to be set")
+		}
+	case "github":
+		for _, repo := range config.GitHub.Repos {
+			if owner, name, ok := strings.Cut(repo, "/"); !ok || owner == "" || name == "" {
+				return fmt.Errorf("github repo %q must be in owner/name form", repo)
+			}
+		}
+	case "localfs":
+		if config.LocalFS.RootDir == "" {
+			return fmt.Errorf("root_dir is required for localfs sources")
+		}
+	case "rss":
+		if len(config.RSS.FeedURLs) == 0 {
+			return fmt.Errorf("feed_urls is required for rss sources")
+		}
+
+		if config.RSS.MaxItemsPerFeed < 0 {
+			return fmt.Errorf("max_items_per_feed must be non-negative for rss sources")
+		}
 	default:
 		return fmt.Errorf("unsupported source type: %s", config.Type)
 	}
@@ -427,6 +579,27 @@ func validateTargets(targets map[string]models.TargetConfig) error {
 	return nil
 }
 
+// filenameTemplateToken matches a {{token}} in a filename_template.
+var filenameTemplateToken = regexp.MustCompile(`\{\{([^}]*)\}\}`)
+
+// Tokens expanded in Obsidian target and Gmail source filename templates.
+var (
+	obsidianFilenameTokens = []string{"date", "title", "source", "id", "type"}
+	gmailFilenameTokens    = []string{"date", "from", "subject", "id"}
+)
+
+// validateFilenameTemplate rejects filename_template tokens not in tokens.
+func validateFilenameTemplate(template string, tokens []string) error {
+	for _, match := range filenameTemplateToken.FindAllStringSubmatch(template, -1) {
+		if !slices.Contains(tokens, match[1]) {
+			return fmt.Errorf("filename_template: unknown token %s (supported: {{%s}})",
+				match[0], strings.Join(tokens, "}}, {{"))
+		}
+	}
+
+	return nil
+}
+
 // validateTargetConfig validates an individual target configuration.
 func validateTargetConfig(_ string, config models.TargetConfig) error {
 	if config.Type == "" {
@@ -436,9 +609,17 @@ func validateTargetConfig(_ string, config models.TargetConfig) error {
 	// Validate supported target types
 	switch config.Type {
 	case targetTypeObsidian:
-		// Obsidian-specific validations could go here
+		if err := validateFilenameTemplate(config.Obsidian.FilenameTemplate, obsidianFilenameTokens); err != nil {
+			return err
+		}
 	case targetTypeLogseq:
 		// Logseq-specific validations could go here
+	case targetTypeRoam:
+		if config.ContentPrefix != "" || config.ContentSuffix != "" {
+			return fmt.Errorf("content_prefix and content_suffix are not supported by roam targets")
+		}
+	case targetTypeJSONL:
+		// Items are written as JSON; note formatting settings do not apply.
 	default:
 		return fmt.Errorf("unsupported target type: %s", config.Type)
 	}
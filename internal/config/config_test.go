@@ -62,6 +62,47 @@ func TestLoadConfig_NoFileError(t *testing.T) {
 	assert.Contains(t, err.Error(), "no config file found", "Error message should indicate that no file was found")
 }
 
+// TestLoadConfig_InvalidTransformers tests that transformer typos are all reported at load time.
+func TestLoadConfig_InvalidTransformers(t *testing.T) {
+	tempDir := t.TempDir()
+	originalCustomConfigDir := customConfigDir
+	customConfigDir = tempDir
+
+	defer func() { customConfigDir = originalCustomConfigDir }()
+
+	data := []byte(`transformers:
+  enabled: true
+  pipeline_order: ["content_cleanup", "link_extractor"]
+  error_strategy: "ignore"
+`)
+	require.NoError(t, os.WriteFile(filepath.Join(tempDir, ConfigFileName), data, 0600))
+
+	_, err := LoadConfig()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `unknown transformer "link_extractor"`)
+	assert.Contains(t, err.Error(), `error_strategy must be`)
+
+	// Commands must not fall back to the defaults and drop the user's config.
+	_, err = LoadConfigOrDefault()
+	require.Error(t, err)
+}
+
+// TestLoadConfigOrDefault_NoFile tests that the defaults are used only when no config file exists.
+func TestLoadConfigOrDefault_NoFile(t *testing.T) {
+	tempDir := t.TempDir()
+	originalCustomConfigDir := customConfigDir
+	customConfigDir = tempDir
+
+	defer func() { customConfigDir = originalCustomConfigDir }()
+
+	_, err := LoadConfig()
+	require.ErrorIs(t, err, ErrNoConfigFile)
+
+	cfg, err := LoadConfigOrDefault()
+	require.NoError(t, err)
+	assert.Equal(t, GetDefaultConfig().Sync.DefaultTarget, cfg.Sync.DefaultTarget)
+}
+
 // TestApplyEnvOverrides_EmbeddingsAPIKey tests that PKM_SYNC_EMBEDDINGS_API_KEY overrides the config value.
 func TestApplyEnvOverrides_EmbeddingsAPIKey(t *testing.T) {
 	tempDir := t.TempDir()
@@ -110,3 +151,11 @@ func TestGetDefaultConfig(t *testing.T) {
 	assert.NotEmpty(t, defaultConfig.Sources, "Default config should have sources defined")
 	assert.NotEmpty(t, defaultConfig.Targets, "Default config should have targets defined")
 }
+
+func TestValidateFilenameTemplate(t *testing.T) {
+	assert.NoError(t, validateFilenameTemplate("", obsidianFilenameTokens))
+	assert.NoError(t, validateFilenameTemplate("{{date}} - {{title}} {{source}} {{type}} {{id}}", obsidianFilenameTokens))
+	assert.ErrorContains(t, validateFilenameTemplate("{{date}} - {{subject}}", obsidianFilenameTokens), "{{subject}}")
+	assert.NoError(t, validateFilenameTemplate("{{date}}-newsletter-{{from}}-{{subject}}", gmailFilenameTokens))
+	assert.ErrorContains(t, validateFilenameTemplate("{{title}}", gmailFilenameTokens), "{{title}}")
+}
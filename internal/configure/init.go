@@ -0,0 +1,157 @@
+package configure
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/charmbracelet/huh"
+	"golang.org/x/term"
+)
+
+// InitAnswers holds what `pkm-sync config init` needs to write a minimal config.
+type InitAnswers struct {
+	SourceType string
+	SourceName string
+	Fields     map[string]string // RequiredField values keyed by RequiredField.Key
+	Target     string
+	OutputDir  string
+}
+
+// fieldProjectKeys is the extra field collected for jira, which needs a project
+// (or a JQL query) to pass validation.
+const fieldProjectKeys = "project_keys"
+
+// IsInteractive reports whether stdin is a terminal the init wizard can prompt on.
+func IsInteractive() bool {
+	return term.IsTerminal(int(os.Stdin.Fd()))
+}
+
+// PromptInit walks the user through choosing a source, its required fields, a
+// target and an output directory. Non-empty fields in defaults pre-fill the
+// answers. Returns nil when the user aborts.
+func PromptInit(defaults InitAnswers) (*InitAnswers, error) {
+	answers := defaults
+	if answers.Fields == nil {
+		answers.Fields = make(map[string]string)
+	}
+
+	if answers.SourceType == "" {
+		sourceType, err := promptSourceType()
+		if err != nil || sourceType == "" {
+			return nil, err
+		}
+
+		answers.SourceType = sourceType
+	}
+
+	provider, err := getProvider(answers.SourceType)
+	if err != nil {
+		return nil, err
+	}
+
+	if answers.SourceName == "" {
+		answers.SourceName = "my_" + answers.SourceType
+	}
+
+	fields := provider.RequiredFields()
+	if answers.SourceType == sourceTypeJira {
+		fields = append(fields, RequiredField{
+			Key:         fieldProjectKeys,
+			Prompt:      "Jira project key",
+			Placeholder: "PROJ",
+			Validate: func(s string) error {
+				if strings.TrimSpace(s) == "" {
+					return fmt.Errorf("project key is required")
+				}
+
+				return nil
+			},
+		})
+	}
+
+	fieldPtrs := make(map[string]*string, len(fields))
+	inputs := make([]huh.Field, 0, len(fields)+1)
+
+	inputs = append(inputs, huh.NewInput().
+		Title("Source name (config key)").
+		Description("A unique identifier for this source, e.g. gmail_work").
+		Validate(func(s string) error {
+			if strings.TrimSpace(s) == "" {
+				return fmt.Errorf("source name is required")
+			}
+
+			return nil
+		}).
+		Value(&answers.SourceName))
+
+	for i := range fields {
+		f := &fields[i]
+		val := new(string)
+		*val = answers.Fields[f.Key]
+		fieldPtrs[f.Key] = val
+
+		inp := huh.NewInput().
+			Title(f.Prompt).
+			Placeholder(f.Placeholder).
+			Value(val)
+
+		if f.Validate != nil {
+			inp = inp.Validate(f.Validate)
+		}
+
+		inputs = append(inputs, inp)
+	}
+
+	if answers.Target == "" {
+		answers.Target = "obsidian"
+	}
+
+	if answers.OutputDir == "" {
+		answers.OutputDir = "./output"
+	}
+
+	form := huh.NewForm(
+		huh.NewGroup(inputs...),
+		huh.NewGroup(
+			huh.NewSelect[string]().
+				Title("Where should notes be written?").
+				Options(
+					huh.NewOption("Obsidian vault", "obsidian"),
+					huh.NewOption("Logseq graph", "logseq"),
+					huh.NewOption("Roam Research import files", "roam"),
+				).
+				Value(&answers.Target),
+			huh.NewInput().
+				Title("Output directory (vault or graph path)").
+				Validate(func(s string) error {
+					if strings.TrimSpace(s) == "" {
+						return fmt.Errorf("output directory is required")
+					}
+
+					return nil
+				}).
+				Value(&answers.OutputDir),
+		),
+	)
+
+	if err := form.Run(); err != nil {
+		if errors.Is(err, huh.ErrUserAborted) {
+			fmt.Println("Canceled.")
+
+			return nil, nil
+		}
+
+		return nil, fmt.Errorf("config init form error: %w", err)
+	}
+
+	for key, ptr := range fieldPtrs {
+		answers.Fields[key] = strings.TrimSpace(*ptr)
+	}
+
+	answers.SourceName = strings.TrimSpace(answers.SourceName)
+	answers.OutputDir = strings.TrimSpace(answers.OutputDir)
+
+	return &answers, nil
+}
@@ -1,7 +1,10 @@
 package embeddings
 
 import (
+	"context"
 	"fmt"
+	"log/slog"
+	"time"
 
 	"pkm-sync/pkg/models"
 )
@@ -9,12 +12,102 @@ import (
 const (
 	providerOllama = "ollama"
 	providerOpenAI = "openai"
+
+	// Dimension mismatch policies (embeddings.dimension_mismatch).
+	dimensionMismatchFail    = "fail"
+	dimensionMismatchCorrect = "correct"
+
+	// probeTimeout bounds the embedding request that checks the dimensions.
+	probeTimeout = 30 * time.Second
+
+	// openAIBatchSize is how many texts an OpenAI-compatible request embeds
+	// by default; the API accepts many more, but smaller batches keep one
+	// failure from discarding much work.
+	openAIBatchSize = 16
 )
 
+// DefaultBatchSize returns how many texts to pass to EmbedBatch at once for
+// the named provider: openAIBatchSize for OpenAI-compatible APIs, which embed
+// a whole batch in one request, and 1 for providers whose EmbedBatch loops.
+func DefaultBatchSize(provider string) int {
+	if provider == providerOpenAI {
+		return openAIBatchSize
+	}
+
+	return 1
+}
+
 // NewProvider creates a new embedding provider based on the configuration.
 // Returns nil, nil when cfg.Provider is empty — callers treat a nil provider
 // as "metadata-only mode" (document rows are still written; embeddings are not).
-func NewProvider(cfg models.EmbeddingsConfig) (Provider, error) {
+//
+// The provider is probed with one embedding to check cfg.Dimensions against
+// what the model actually returns (see checkDimensions); with
+// dimension_mismatch: correct, cfg.Dimensions is updated in place so callers
+// open the vector store with the right dimension.
+func NewProvider(cfg *models.EmbeddingsConfig) (Provider, error) {
+	provider, err := newProvider(*cfg)
+	if err != nil || provider == nil {
+		return provider, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), probeTimeout)
+	defer cancel()
+
+	configured := cfg.Dimensions
+
+	if err := checkDimensions(ctx, provider, cfg); err != nil {
+		provider.Close()
+
+		return nil, err
+	}
+
+	if cfg.Dimensions == configured {
+		return provider, nil
+	}
+
+	provider.Close()
+
+	return newProvider(*cfg)
+}
+
+// checkDimensions embeds a probe text and compares the embedding's length with
+// cfg.Dimensions. A mismatch fails with the dimension to configure, or with
+// dimension_mismatch: correct is logged and written to cfg.Dimensions. A
+// failing probe (e.g. the server is down) only logs a warning, leaving the
+// error to surface when items are embedded.
+func checkDimensions(ctx context.Context, provider Provider, cfg *models.EmbeddingsConfig) error {
+	embedding, err := provider.Embed(ctx, "pkm-sync dimension probe")
+	if err != nil {
+		slog.Warn("Could not verify embedding dimensions", "provider", cfg.Provider, "model", cfg.Model, "error", err)
+
+		return nil
+	}
+
+	actual := len(embedding)
+	if actual == cfg.Dimensions {
+		return nil
+	}
+
+	switch cfg.DimensionMismatch {
+	case dimensionMismatchCorrect:
+		slog.Warn("Embedding dimensions do not match the model; using the model's dimensions",
+			"model", cfg.Model, "configured", cfg.Dimensions, "actual", actual)
+
+		cfg.Dimensions = actual
+
+		return nil
+	case "", dimensionMismatchFail:
+		return fmt.Errorf("%s model %q returns %d-dimensional embeddings but embeddings.dimensions is %d: "+
+			"set dimensions: %d (or dimension_mismatch: correct) and re-index if vectors.db was built with %d",
+			cfg.Provider, cfg.Model, actual, cfg.Dimensions, actual, cfg.Dimensions)
+	default:
+		return fmt.Errorf("unknown dimension_mismatch policy %q (must be fail or correct)", cfg.DimensionMismatch)
+	}
+}
+
+// newProvider builds the configured provider without probing it.
+func newProvider(cfg models.EmbeddingsConfig) (Provider, error) {
 	switch cfg.Provider {
 	case providerOllama:
 		if cfg.APIURL == "" {
@@ -9,6 +9,8 @@ import (
 	"net/http"
 	"strings"
 	"time"
+
+	"pkm-sync/internal/httpclient"
 )
 
 // OllamaProvider implements the Provider interface for Ollama.
@@ -25,7 +27,7 @@ func NewOllamaProvider(apiURL, model string, dimensions int) *OllamaProvider {
 		apiURL:     apiURL,
 		model:      model,
 		dimensions: dimensions,
-		client:     &http.Client{},
+		client:     httpclient.NewSharedClient(),
 	}
 }
 
@@ -141,7 +143,8 @@ func isRetriableError(err error) bool {
 		strings.Contains(errStr, "status 500")
 }
 
-// EmbedBatch generates embeddings for multiple text inputs.
+// EmbedBatch generates embeddings for multiple text inputs, one request per
+// text, so callers can batch without knowing which provider they have.
 func (p *OllamaProvider) EmbedBatch(ctx context.Context, texts []string) ([][]float32, error) {
 	embeddings := make([][]float32, len(texts))
 	for i, text := range texts {
@@ -7,6 +7,8 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+
+	"pkm-sync/internal/httpclient"
 )
 
 // OpenAIProvider implements the Provider interface for OpenAI.
@@ -25,7 +27,7 @@ func NewOpenAIProvider(apiURL, apiKey, model string, dimensions int) *OpenAIProv
 		apiKey:     apiKey,
 		model:      model,
 		dimensions: dimensions,
-		client:     &http.Client{},
+		client:     httpclient.NewSharedClient(),
 	}
 }
 
@@ -56,7 +58,8 @@ func (p *OpenAIProvider) Embed(ctx context.Context, text string) ([]float32, err
 	return embeddings[0], nil
 }
 
-// EmbedBatch generates embeddings for multiple text inputs.
+// EmbedBatch generates embeddings for multiple text inputs in one request,
+// returned in input order.
 func (p *OpenAIProvider) EmbedBatch(ctx context.Context, texts []string) ([][]float32, error) {
 	reqBody := openAIEmbedRequest{
 		Model:      p.model,
@@ -98,9 +101,17 @@ func (p *OpenAIProvider) EmbedBatch(ctx context.Context, texts []string) ([][]fl
 		return nil, fmt.Errorf("empty embeddings returned from OpenAI")
 	}
 
+	if len(embedResp.Data) != len(texts) {
+		return nil, fmt.Errorf("openai returned %d embeddings for %d inputs", len(embedResp.Data), len(texts))
+	}
+
 	// Convert to [][]float32 and sort by index
-	embeddings := make([][]float32, len(embedResp.Data))
+	embeddings := make([][]float32, len(texts))
 	for _, item := range embedResp.Data {
+		if item.Index < 0 || item.Index >= len(texts) || embeddings[item.Index] != nil {
+			return nil, fmt.Errorf("openai returned an embedding for unexpected input index %d", item.Index)
+		}
+
 		embedding := make([]float32, len(item.Embedding))
 		for i, v := range item.Embedding {
 			embedding[i] = float32(v)
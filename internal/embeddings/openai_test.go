@@ -126,6 +126,45 @@ func TestOpenAIProvider_EmbedBatch(t *testing.T) {
 	}
 }
 
+func TestOpenAIProvider_EmbedBatch_MalformedResponse(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+	}{
+		{"too few embeddings", `[{"embedding":[0.1],"index":0}]`},
+		{"index out of range", `[{"embedding":[0.1],"index":0},{"embedding":[0.2],"index":5}]`},
+		{"duplicate index", `[{"embedding":[0.1],"index":1},{"embedding":[0.2],"index":1}]`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				_, _ = w.Write([]byte(`{"data":` + tt.data + `}`))
+			}))
+			defer server.Close()
+
+			provider := NewOpenAIProvider(server.URL, "test-key", "test-model", 1)
+
+			if _, err := provider.EmbedBatch(context.Background(), []string{"a", "b"}); err == nil {
+				t.Fatal("expected an error for a malformed response")
+			}
+		})
+	}
+}
+
+func TestDefaultBatchSize(t *testing.T) {
+	if got := DefaultBatchSize("openai"); got != openAIBatchSize {
+		t.Errorf("DefaultBatchSize(openai) = %d, want %d", got, openAIBatchSize)
+	}
+
+	for _, provider := range []string{"ollama", ""} {
+		if got := DefaultBatchSize(provider); got != 1 {
+			t.Errorf("DefaultBatchSize(%q) = %d, want 1", provider, got)
+		}
+	}
+}
+
 func TestOpenAIProvider_Embed_Error(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusUnauthorized)
@@ -1,20 +1,89 @@
 package embeddings
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"pkm-sync/pkg/models"
 )
 
+// embeddingServer answers Ollama and OpenAI embedding requests with vectors of
+// the given length.
+func embeddingServer(t *testing.T, dimensions int) *httptest.Server {
+	t.Helper()
+
+	vector := make([]float64, dimensions)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var resp any
+
+		switch r.URL.Path {
+		case "/api/embed":
+			resp = ollamaEmbedResponse{Embeddings: [][]float64{vector}}
+		case "/v1/embeddings":
+			resp = map[string]any{"data": []map[string]any{{"embedding": vector, "index": 0}}}
+		default:
+			http.NotFound(w, r)
+
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+
+		if err := json.NewEncoder(w).Encode(resp); err != nil {
+			t.Errorf("failed to encode response: %v", err)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+// fixedProvider returns embeddings of a fixed length, or err.
+type fixedProvider struct {
+	dimensions int
+	err        error
+}
+
+func (p *fixedProvider) Embed(_ context.Context, _ string) ([]float32, error) {
+	if p.err != nil {
+		return nil, p.err
+	}
+
+	return make([]float32, p.dimensions), nil
+}
+
+func (p *fixedProvider) EmbedBatch(ctx context.Context, texts []string) ([][]float32, error) {
+	result := make([][]float32, len(texts))
+	for i, text := range texts {
+		embedding, err := p.Embed(ctx, text)
+		if err != nil {
+			return nil, err
+		}
+
+		result[i] = embedding
+	}
+
+	return result, nil
+}
+
+func (p *fixedProvider) Dimensions() int { return p.dimensions }
+func (p *fixedProvider) Close() error    { return nil }
+
 func TestNewProvider_Ollama(t *testing.T) {
 	cfg := models.EmbeddingsConfig{
 		Provider:   "ollama",
 		Model:      "nomic-embed-text",
-		APIURL:     "http://localhost:11434",
+		APIURL:     embeddingServer(t, 768).URL,
 		Dimensions: 768,
 	}
 
-	provider, err := NewProvider(cfg)
+	provider, err := NewProvider(&cfg)
 	if err != nil {
 		t.Fatalf("expected no error, got %v", err)
 	}
@@ -32,12 +101,12 @@ func TestNewProvider_OpenAI(t *testing.T) {
 	cfg := models.EmbeddingsConfig{
 		Provider:   "openai",
 		Model:      "text-embedding-3-small",
-		APIURL:     "https://api.openai.com",
+		APIURL:     embeddingServer(t, 1536).URL,
 		APIKey:     "test-key",
 		Dimensions: 1536,
 	}
 
-	provider, err := NewProvider(cfg)
+	provider, err := NewProvider(&cfg)
 	if err != nil {
 		t.Fatalf("expected no error, got %v", err)
 	}
@@ -58,7 +127,7 @@ func TestNewProvider_UnsupportedProvider(t *testing.T) {
 		Dimensions: 768,
 	}
 
-	_, err := NewProvider(cfg)
+	_, err := NewProvider(&cfg)
 	if err == nil {
 		t.Fatal("expected error for unsupported provider")
 	}
@@ -70,7 +139,7 @@ func TestNewProvider_MissingModel(t *testing.T) {
 		Dimensions: 768,
 	}
 
-	_, err := NewProvider(cfg)
+	_, err := NewProvider(&cfg)
 	if err == nil {
 		t.Fatal("expected error for missing model")
 	}
@@ -82,7 +151,7 @@ func TestNewProvider_MissingDimensions(t *testing.T) {
 		Model:    "nomic-embed-text",
 	}
 
-	_, err := NewProvider(cfg)
+	_, err := NewProvider(&cfg)
 	if err == nil {
 		t.Fatal("expected error for missing dimensions")
 	}
@@ -95,8 +164,71 @@ func TestNewProvider_OpenAIMissingAPIKey(t *testing.T) {
 		Dimensions: 1536,
 	}
 
-	_, err := NewProvider(cfg)
+	_, err := NewProvider(&cfg)
 	if err == nil {
 		t.Fatal("expected error for missing API key")
 	}
 }
+
+func TestCheckDimensions_MismatchFails(t *testing.T) {
+	cfg := models.EmbeddingsConfig{Provider: "ollama", Model: "nomic-embed-text", Dimensions: 1536}
+
+	err := checkDimensions(context.Background(), &fixedProvider{dimensions: 768}, &cfg)
+	if err == nil {
+		t.Fatal("expected error for a dimension mismatch")
+	}
+
+	if !strings.Contains(err.Error(), "returns 768-dimensional embeddings but embeddings.dimensions is 1536") {
+		t.Errorf("expected the error to name both dimensions, got %v", err)
+	}
+
+	if cfg.Dimensions != 1536 {
+		t.Errorf("expected dimensions to stay 1536, got %d", cfg.Dimensions)
+	}
+}
+
+func TestCheckDimensions_MismatchCorrected(t *testing.T) {
+	cfg := models.EmbeddingsConfig{
+		Provider: "ollama", Model: "nomic-embed-text", Dimensions: 1536, DimensionMismatch: "correct",
+	}
+
+	if err := checkDimensions(context.Background(), &fixedProvider{dimensions: 768}, &cfg); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if cfg.Dimensions != 768 {
+		t.Errorf("expected dimensions corrected to 768, got %d", cfg.Dimensions)
+	}
+}
+
+func TestCheckDimensions_ProbeFailureIsNotFatal(t *testing.T) {
+	cfg := models.EmbeddingsConfig{Provider: "ollama", Model: "nomic-embed-text", Dimensions: 768}
+
+	err := checkDimensions(context.Background(), &fixedProvider{err: errors.New("connection refused")}, &cfg)
+	if err != nil {
+		t.Fatalf("expected an unreachable provider to be tolerated, got %v", err)
+	}
+
+	if cfg.Dimensions != 768 {
+		t.Errorf("expected dimensions to stay 768, got %d", cfg.Dimensions)
+	}
+}
+
+func TestNewProvider_CorrectsDimensions(t *testing.T) {
+	cfg := models.EmbeddingsConfig{
+		Provider:          "ollama",
+		Model:             "nomic-embed-text",
+		APIURL:            embeddingServer(t, 768).URL,
+		Dimensions:        1536,
+		DimensionMismatch: "correct",
+	}
+
+	provider, err := NewProvider(&cfg)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if provider.Dimensions() != 768 || cfg.Dimensions != 768 {
+		t.Errorf("expected provider and config dimensions 768, got %d and %d", provider.Dimensions(), cfg.Dimensions)
+	}
+}
@@ -3,8 +3,10 @@
 // referenced by name from a target's Formatters map.
 //
 // All three template kinds (directory, filename, content) receive the same
-// [ItemData] struct as their dot value.  The following template functions are
-// available:
+// [ItemData] struct as their dot value.  Its times are in the zone set with
+// [Registry.SetDateRendering] (app.timezone), and Date is the creation date in
+// the configured layout (app.date_format).  The following template functions
+// are available:
 //
 //   - formatDate "layout"   – format a time.Time with the given Go layout
 //   - sanitize              – sanitize a string for use in a filename
@@ -19,17 +21,19 @@ import (
 	"text/template"
 	"time"
 
-	"pkm-sync/internal/utils"
+	"pkm-sync/internal/naming"
 	"pkm-sync/pkg/models"
 )
 
 // ItemData is the template context passed to every formatter template.
 type ItemData struct {
-	ID          string
-	Title       string
-	Content     string
-	SourceType  string
-	ItemType    string
+	ID         string
+	Title      string
+	Content    string
+	SourceType string
+	ItemType   string
+	// Date is CreatedAt's date, e.g. "2026-03-02" (app.date_format).
+	Date        string
 	CreatedAt   time.Time
 	UpdatedAt   time.Time
 	Tags        []string
@@ -38,16 +42,30 @@ type ItemData struct {
 	Links       []models.Link
 }
 
-// itemDataFromFullItem converts a FullItem into an ItemData for template rendering.
-func itemDataFromFullItem(item models.FullItem) ItemData {
+// itemDataFromFullItem converts a FullItem into an ItemData for template
+// rendering, with times in loc (nil keeps their own zone).
+func itemDataFromFullItem(item models.FullItem, loc *time.Location, dateLayout string) ItemData {
+	in := func(t time.Time) time.Time {
+		if loc == nil || t.IsZero() {
+			return t
+		}
+
+		return t.In(loc)
+	}
+
+	if dateLayout == "" {
+		dateLayout = defaultDateLayout
+	}
+
 	return ItemData{
 		ID:          item.GetID(),
 		Title:       item.GetTitle(),
 		Content:     item.GetContent(),
 		SourceType:  item.GetSourceType(),
 		ItemType:    item.GetItemType(),
-		CreatedAt:   item.GetCreatedAt(),
-		UpdatedAt:   item.GetUpdatedAt(),
+		Date:        in(item.GetCreatedAt()).Format(dateLayout),
+		CreatedAt:   in(item.GetCreatedAt()),
+		UpdatedAt:   in(item.GetUpdatedAt()),
 		Tags:        item.GetTags(),
 		Attachments: item.GetAttachments(),
 		Metadata:    item.GetMetadata(),
@@ -55,6 +73,9 @@ func itemDataFromFullItem(item models.FullItem) ItemData {
 	}
 }
 
+// defaultDateLayout renders ItemData.Date when no date layout is configured.
+const defaultDateLayout = "2006-01-02"
+
 // templateFuncs returns the template.FuncMap available to all formatter templates.
 func templateFuncs() template.FuncMap {
 	return template.FuncMap{
@@ -66,7 +87,7 @@ func templateFuncs() template.FuncMap {
 		// sanitize converts a string to a safe filename component.
 		// Usage: {{.Title | sanitize}}
 		"sanitize": func(s string) string {
-			return utils.SanitizeFilename(s)
+			return naming.Slug(s)
 		},
 		// truncate limits a string to at most n runes.
 		// Usage: {{.Title | truncate 50}}
@@ -94,6 +115,9 @@ type TemplateFormatter struct {
 	dirTmpl  *template.Template // may be nil when DirectoryPattern is empty
 	fileTmpl *template.Template // may be nil when FilenamePattern is empty
 	contTmpl *template.Template // may be nil when ContentTemplate is empty
+
+	loc        *time.Location // zone for rendered times; nil keeps their own
+	dateLayout string         // layout of ItemData.Date
 }
 
 // New compiles a TemplateFormatter from a [models.FormatterConfig].
@@ -182,7 +206,7 @@ func (tf *TemplateFormatter) FormatContent(item models.FullItem) (string, error)
 }
 
 func (tf *TemplateFormatter) render(t *template.Template, item models.FullItem) (string, error) {
-	data := itemDataFromFullItem(item)
+	data := itemDataFromFullItem(item, tf.loc, tf.dateLayout)
 
 	var buf bytes.Buffer
 	if err := t.Execute(&buf, data); err != nil {
@@ -218,6 +242,19 @@ func BuildRegistry(cfgs []models.FormatterConfig) (*Registry, error) {
 	return r, nil
 }
 
+// SetDateRendering makes every formatter render times in loc (nil keeps each
+// time's own zone) and ItemData.Date with dateLayout (empty: "2006-01-02").
+func (r *Registry) SetDateRendering(loc *time.Location, dateLayout string) {
+	if r == nil {
+		return
+	}
+
+	for _, tf := range r.byName {
+		tf.loc = loc
+		tf.dateLayout = dateLayout
+	}
+}
+
 // Lookup returns the named formatter, or (nil, false) if it does not exist.
 func (r *Registry) Lookup(name string) (*TemplateFormatter, bool) {
 	if r == nil {
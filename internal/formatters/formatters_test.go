@@ -234,3 +234,41 @@ func TestNilRegistry_Lookup(t *testing.T) {
 		t.Error("nil Registry.Lookup should return (nil, false)")
 	}
 }
+
+func TestSetDateRendering_LocalDate(t *testing.T) {
+	reg, err := formatters.BuildRegistry([]models.FormatterConfig{
+		cfg("daily", "note", "{{.CreatedAt | formatDate \"2006/01\"}}", "{{.Date}} {{.Title}}", ""),
+	})
+	if err != nil {
+		t.Fatalf("BuildRegistry: %v", err)
+	}
+
+	tokyo, err := time.LoadLocation("Asia/Tokyo")
+	if err != nil {
+		t.Skipf("tz database unavailable: %v", err)
+	}
+
+	reg.SetDateRendering(tokyo, "")
+
+	tf, _ := reg.Lookup("daily")
+	item := makeItem("1", "Standup", "note")
+	item.SetCreatedAt(time.Date(2024, 3, 31, 23, 30, 0, 0, time.UTC))
+
+	got, err := tf.FormatFilename(item)
+	if err != nil {
+		t.Fatalf("FormatFilename: %v", err)
+	}
+
+	if got != "2024-04-01 Standup" {
+		t.Errorf("FormatFilename = %q, want the Tokyo date 2024-04-01", got)
+	}
+
+	dir, err := tf.FormatDirectory(item)
+	if err != nil {
+		t.Fatalf("FormatDirectory: %v", err)
+	}
+
+	if dir != "2024/04" {
+		t.Errorf("FormatDirectory = %q, want 2024/04", dir)
+	}
+}
@@ -0,0 +1,118 @@
+package httpclient
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"sync"
+
+	"pkm-sync/pkg/models"
+
+	"golang.org/x/net/http/httpproxy"
+)
+
+var (
+	sharedMu        sync.RWMutex
+	sharedTransport http.RoundTripper
+)
+
+// SetSharedTransport installs the transport every API client is built on.
+// Call it once in PersistentPreRun, before any client is created.
+func SetSharedTransport(rt http.RoundTripper) {
+	sharedMu.Lock()
+	defer sharedMu.Unlock()
+
+	sharedTransport = rt
+}
+
+// SharedTransport returns the transport installed by SetSharedTransport, or
+// http.DefaultTransport when none is.
+func SharedTransport() http.RoundTripper {
+	sharedMu.RLock()
+	defer sharedMu.RUnlock()
+
+	if sharedTransport == nil {
+		return http.DefaultTransport
+	}
+
+	return sharedTransport
+}
+
+// NewSharedClient returns a client on SharedTransport for callers that do not
+// go through NewClient.
+func NewSharedClient() *http.Client {
+	return &http.Client{Transport: SharedTransport()}
+}
+
+// NewBaseTransport builds an http.Transport from the app's http settings:
+// a proxy (else the HTTP_PROXY/HTTPS_PROXY/NO_PROXY environment), CAs trusted
+// in addition to the system pool, a client certificate for mTLS, and, for
+// testing only, no certificate verification.
+func NewBaseTransport(cfg models.HTTPConfig) (*http.Transport, error) {
+	base, ok := http.DefaultTransport.(*http.Transport)
+	if !ok {
+		return nil, fmt.Errorf("http.DefaultTransport is %T, not *http.Transport", http.DefaultTransport)
+	}
+
+	transport := base.Clone()
+
+	if cfg.ProxyURL != "" {
+		proxyURL, err := url.Parse(cfg.ProxyURL)
+		if err != nil || proxyURL.Scheme == "" || proxyURL.Host == "" {
+			return nil, fmt.Errorf("invalid http.proxy_url %q", cfg.ProxyURL)
+		}
+
+		proxy := (&httpproxy.Config{
+			HTTPProxy:  cfg.ProxyURL,
+			HTTPSProxy: cfg.ProxyURL,
+			NoProxy:    cfg.NoProxy,
+		}).ProxyFunc()
+
+		transport.Proxy = func(req *http.Request) (*url.URL, error) {
+			return proxy(req.URL)
+		}
+	}
+
+	tlsConfig := &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		InsecureSkipVerify: cfg.InsecureSkipVerify, //nolint:gosec // opt-in, for testing against self-signed endpoints
+	}
+
+	if cfg.CABundle != "" {
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+
+		pem, err := os.ReadFile(cfg.CABundle)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read http.ca_bundle: %w", err)
+		}
+
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates found in http.ca_bundle %s", cfg.CABundle)
+		}
+
+		tlsConfig.RootCAs = pool
+	}
+
+	if cfg.ClientCert != "" || cfg.ClientKey != "" {
+		if cfg.ClientCert == "" || cfg.ClientKey == "" {
+			return nil, fmt.Errorf("http.client_cert and http.client_key must be set together")
+		}
+
+		cert, err := tls.LoadX509KeyPair(cfg.ClientCert, cfg.ClientKey)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load http client certificate: %w", err)
+		}
+
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	transport.TLSClientConfig = tlsConfig
+
+	return transport, nil
+}
@@ -0,0 +1,159 @@
+package httpclient
+
+import (
+	"encoding/pem"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"pkm-sync/pkg/models"
+)
+
+// writeServerCA writes server's certificate as a PEM CA bundle.
+func writeServerCA(t *testing.T, server *httptest.Server) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "ca.pem")
+	block := &pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw}
+
+	if err := os.WriteFile(path, pem.EncodeToMemory(block), 0o600); err != nil {
+		t.Fatalf("failed to write CA bundle: %v", err)
+	}
+
+	return path
+}
+
+func TestNewBaseTransport_UsesConfiguredProxy(t *testing.T) {
+	var proxied []string
+
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// A proxied request carries the absolute target URL.
+		proxied = append(proxied, r.URL.String())
+		_, _ = w.Write([]byte("via proxy"))
+	}))
+	t.Cleanup(proxy.Close)
+
+	transport, err := NewBaseTransport(models.HTTPConfig{ProxyURL: proxy.URL, NoProxy: "internal.example"})
+	if err != nil {
+		t.Fatalf("NewBaseTransport: %v", err)
+	}
+
+	client := &http.Client{Transport: transport}
+
+	resp, err := client.Get("http://api.example.test/v1/items")
+	if err != nil {
+		t.Fatalf("request through proxy failed: %v", err)
+	}
+
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+
+	if string(body) != "via proxy" {
+		t.Errorf("expected response from proxy, got %q", body)
+	}
+
+	if len(proxied) != 1 || proxied[0] != "http://api.example.test/v1/items" {
+		t.Errorf("expected proxy to receive the target URL, got %v", proxied)
+	}
+
+	// Hosts listed in no_proxy are dialed directly.
+	req, _ := http.NewRequest(http.MethodGet, "http://internal.example/", nil)
+
+	if proxyURL, err := transport.Proxy(req); err != nil || proxyURL != nil {
+		t.Errorf("expected no proxy for no_proxy host, got %v (err %v)", proxyURL, err)
+	}
+}
+
+func TestNewBaseTransport_TrustsCABundle(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte("ok"))
+	}))
+	t.Cleanup(server.Close)
+
+	// Without the bundle the test server's self-signed certificate is rejected.
+	plain, err := NewBaseTransport(models.HTTPConfig{})
+	if err != nil {
+		t.Fatalf("NewBaseTransport: %v", err)
+	}
+
+	if resp, err := (&http.Client{Transport: plain}).Get(server.URL); err == nil {
+		resp.Body.Close()
+		t.Fatal("expected certificate error without ca_bundle")
+	}
+
+	trusted, err := NewBaseTransport(models.HTTPConfig{CABundle: writeServerCA(t, server)})
+	if err != nil {
+		t.Fatalf("NewBaseTransport: %v", err)
+	}
+
+	resp, err := (&http.Client{Transport: trusted}).Get(server.URL)
+	if err != nil {
+		t.Fatalf("expected request to succeed with ca_bundle, got %v", err)
+	}
+
+	resp.Body.Close()
+}
+
+func TestNewBaseTransport_InvalidSettings(t *testing.T) {
+	missing := filepath.Join(t.TempDir(), "missing.pem")
+	empty := filepath.Join(t.TempDir(), "empty.pem")
+
+	if err := os.WriteFile(empty, []byte("not a certificate"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		cfg  models.HTTPConfig
+		want string
+	}{
+		{"proxy without scheme", models.HTTPConfig{ProxyURL: "proxy.corp:3128"}, "proxy_url"},
+		{"unreadable CA bundle", models.HTTPConfig{CABundle: missing}, "ca_bundle"},
+		{"CA bundle without certificates", models.HTTPConfig{CABundle: empty}, "no certificates"},
+		{"client cert without key", models.HTTPConfig{ClientCert: missing}, "set together"},
+		{"unreadable client cert", models.HTTPConfig{ClientCert: missing, ClientKey: missing}, "client certificate"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewBaseTransport(tt.cfg)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("expected error containing %q, got %v", tt.want, err)
+			}
+		})
+	}
+}
+
+func TestTransport_DefaultsToSharedTransport(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte("ok"))
+	}))
+	t.Cleanup(server.Close)
+
+	shared, err := NewBaseTransport(models.HTTPConfig{CABundle: writeServerCA(t, server)})
+	if err != nil {
+		t.Fatalf("NewBaseTransport: %v", err)
+	}
+
+	SetSharedTransport(shared)
+	t.Cleanup(func() { SetSharedTransport(nil) })
+
+	// Source clients wrap a bare http.Client; requests must still use the
+	// shared transport's CA.
+	client, _ := NewClient(&http.Client{}, 0)
+
+	resp, err := client.Get(server.URL)
+	if err != nil {
+		t.Fatalf("expected request over shared transport to succeed, got %v", err)
+	}
+
+	resp.Body.Close()
+
+	if NewSharedClient().Transport != shared {
+		t.Error("expected NewSharedClient to use the shared transport")
+	}
+}
@@ -0,0 +1,130 @@
+// Package httpclient provides the HTTP plumbing shared by API-backed sources:
+// a per-request timeout and cancellation bound to the caller's sync context,
+// on top of a base transport carrying the app's proxy and TLS settings.
+package httpclient
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// DefaultRequestTimeout bounds a single API request when neither the source nor
+// the app config sets request_timeout.
+const DefaultRequestTimeout = 2 * time.Minute
+
+// Transport wraps a base RoundTripper so that every request is bounded by
+// Timeout and aborted as soon as the context passed to Bind is canceled.
+//
+// Google API clients issue requests with context.Background(), so binding the
+// sync context at the transport is what lets Ctrl-C reach in-flight calls.
+type Transport struct {
+	Base    http.RoundTripper
+	Timeout time.Duration
+
+	mu  sync.RWMutex
+	ctx context.Context //nolint:containedctx // bound per Fetch, see Bind
+}
+
+// NewClient returns a copy of client whose transport is wrapped in a Transport,
+// together with that Transport so callers can Bind a context later. A nil
+// client is treated as http.DefaultClient. timeout <= 0 disables the
+// per-request bound.
+func NewClient(client *http.Client, timeout time.Duration) (*http.Client, *Transport) {
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	t := &Transport{Base: client.Transport, Timeout: timeout}
+	wrapped := *client
+	wrapped.Transport = t
+
+	return &wrapped, t
+}
+
+// Bind sets the context that governs subsequent requests. Passing nil unbinds,
+// after which requests are bounded only by Timeout.
+func (t *Transport) Bind(ctx context.Context) {
+	t.mu.Lock()
+	t.ctx = ctx
+	t.mu.Unlock()
+}
+
+func (t *Transport) bound() context.Context {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	return t.ctx
+}
+
+// RoundTrip implements http.RoundTripper. The derived context stays alive until
+// the response body is closed so that reading the body is covered as well.
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := t.Base
+	if base == nil {
+		base = SharedTransport()
+	}
+
+	ctx, cancel := context.WithCancel(req.Context())
+	release := cancel
+
+	if bound := t.bound(); bound != nil {
+		stop := context.AfterFunc(bound, cancel)
+		release = func() {
+			stop()
+			cancel()
+		}
+	}
+
+	if t.Timeout > 0 {
+		var cancelTimeout context.CancelFunc
+
+		ctx, cancelTimeout = context.WithTimeout(ctx, t.Timeout)
+		outer := release
+		release = func() {
+			cancelTimeout()
+			outer()
+		}
+	}
+
+	resp, err := base.RoundTrip(req.WithContext(ctx))
+	if err != nil {
+		release()
+
+		return nil, err
+	}
+
+	resp.Body = &releasingBody{ReadCloser: resp.Body, release: release}
+
+	return resp, nil
+}
+
+// releasingBody cancels the request context once the caller closes the body.
+type releasingBody struct {
+	io.ReadCloser
+
+	once    sync.Once
+	release func()
+}
+
+func (b *releasingBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.once.Do(b.release)
+
+	return err
+}
+
+// ResolveTimeout picks the effective per-request timeout: the source override
+// when set, then the app-wide value, then DefaultRequestTimeout.
+func ResolveTimeout(sourceTimeout, appTimeout time.Duration) time.Duration {
+	switch {
+	case sourceTimeout > 0:
+		return sourceTimeout
+	case appTimeout > 0:
+		return appTimeout
+	default:
+		return DefaultRequestTimeout
+	}
+}
@@ -0,0 +1,145 @@
+package httpclient
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// slowServer responds after delay, or as soon as the client goes away.
+func slowServer(t *testing.T, delay time.Duration) *httptest.Server {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-time.After(delay):
+			_, _ = w.Write([]byte("ok"))
+		case <-r.Context().Done():
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+func TestTransport_RequestTimesOut(t *testing.T) {
+	server := slowServer(t, 2*time.Second)
+	client, _ := NewClient(nil, 50*time.Millisecond)
+
+	start := time.Now()
+
+	resp, err := client.Get(server.URL)
+	if err == nil {
+		resp.Body.Close()
+		t.Fatal("expected timeout error, got nil")
+	}
+
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected context.DeadlineExceeded, got %v", err)
+	}
+
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("request took %v, expected it to be cut off near the 50ms timeout", elapsed)
+	}
+}
+
+func TestTransport_FastRequestSucceeds(t *testing.T) {
+	server := slowServer(t, 0)
+	client, _ := NewClient(nil, time.Second)
+
+	resp, err := client.Get(server.URL)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("failed to read body: %v", err)
+	}
+
+	if string(body) != "ok" {
+		t.Errorf("expected body %q, got %q", "ok", string(body))
+	}
+}
+
+func TestTransport_BoundContextCancelsInFlightRequest(t *testing.T) {
+	server := slowServer(t, 2*time.Second)
+	client, transport := NewClient(nil, 0)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	transport.Bind(ctx)
+
+	time.AfterFunc(50*time.Millisecond, cancel)
+
+	resp, err := client.Get(server.URL)
+	if err == nil {
+		resp.Body.Close()
+		t.Fatal("expected cancellation error, got nil")
+	}
+
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+}
+
+func TestTransport_UnbindRestoresRequests(t *testing.T) {
+	server := slowServer(t, 0)
+	client, transport := NewClient(nil, time.Second)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	transport.Bind(ctx)
+	transport.Bind(nil)
+
+	resp, err := client.Get(server.URL)
+	if err != nil {
+		t.Fatalf("expected no error after unbinding, got %v", err)
+	}
+
+	resp.Body.Close()
+}
+
+func TestNewClient_PreservesBaseTransport(t *testing.T) {
+	base := &http.Transport{}
+	original := &http.Client{Transport: base}
+
+	wrapped, transport := NewClient(original, time.Second)
+
+	if wrapped == original {
+		t.Error("expected NewClient to return a copy, got the original client")
+	}
+
+	if transport.Base != base {
+		t.Error("expected wrapped transport to delegate to the original transport")
+	}
+
+	if original.Transport != base {
+		t.Error("expected original client to be left unmodified")
+	}
+}
+
+func TestResolveTimeout(t *testing.T) {
+	tests := []struct {
+		name   string
+		source time.Duration
+		app    time.Duration
+		want   time.Duration
+	}{
+		{"source override wins", 5 * time.Second, 10 * time.Second, 5 * time.Second},
+		{"app value when source unset", 0, 10 * time.Second, 10 * time.Second},
+		{"default when both unset", 0, 0, DefaultRequestTimeout},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ResolveTimeout(tt.source, tt.app); got != tt.want {
+				t.Errorf("ResolveTimeout(%v, %v) = %v, want %v", tt.source, tt.app, got, tt.want)
+			}
+		})
+	}
+}
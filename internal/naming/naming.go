@@ -0,0 +1,190 @@
+// Package naming turns titles into filenames that are valid on Linux, macOS
+// and Windows. Every source and sink that writes files should name them here.
+package naming
+
+import (
+	"path/filepath"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+const (
+	// MaxSlugLength bounds Slug output in bytes, leaving room for an extension.
+	MaxSlugLength = 80
+
+	// MaxNameLength bounds Clean output in bytes. Most filesystems cap a single
+	// name at 255 bytes; the margin leaves room for extensions and suffixes.
+	MaxNameLength = 200
+
+	// FallbackName is used when nothing usable survives sanitization.
+	FallbackName = "safe-filename"
+
+	// EmptyName is returned by Slug for empty input.
+	EmptyName = "default-filename"
+
+	untitledName = "untitled"
+)
+
+// windowsReserved are device names Windows refuses as a file's base name,
+// with or without an extension and regardless of case.
+var windowsReserved = map[string]bool{
+	"CON": true, "PRN": true, "AUX": true, "NUL": true,
+	"COM0": true, "COM1": true, "COM2": true, "COM3": true, "COM4": true,
+	"COM5": true, "COM6": true, "COM7": true, "COM8": true, "COM9": true,
+	"LPT0": true, "LPT1": true, "LPT2": true, "LPT3": true, "LPT4": true,
+	"LPT5": true, "LPT6": true, "LPT7": true, "LPT8": true, "LPT9": true,
+}
+
+// slugReplacer maps a title onto a hyphenated, dot-free slug. Path traversal
+// sequences are removed first (longer patterns before shorter ones).
+var slugReplacer = strings.NewReplacer(
+	"../", "",
+	"./", "",
+	"..", "",
+	"~", "",
+	"\n", "",
+	"\r", "",
+	"\t", "",
+	"\x00", "",
+	" ", "-",
+	"/", "-",
+	"\\", "-",
+	":", "-",
+	"*", "",
+	"?", "",
+	"\"", "",
+	"<", "",
+	">", "",
+	"|", "-",
+	"[", "",
+	"]", "",
+	"(", "",
+	")", "",
+	"@", "-at-",
+	"#", "-",
+	"!", "",
+	"&", "-and-",
+	".", "", // Remove dots to handle .hidden files
+)
+
+// cleanReplacer replaces only the characters that are invalid on some platform,
+// keeping spaces (Logseq page names and Drive exports rely on them).
+var cleanReplacer = strings.NewReplacer(
+	"/", "-",
+	"\\", "-",
+	":", "-",
+	"*", "",
+	"?", "",
+	"\"", "",
+	"<", "",
+	">", "",
+	"|", "-",
+)
+
+// Slug converts a title into a command-line friendly filename: spaces become
+// hyphens, punctuation and dots are dropped, and the result is at most
+// MaxSlugLength bytes. It is the naming used for Obsidian notes.
+func Slug(title string) string {
+	if title == "" {
+		return EmptyName
+	}
+
+	cleaned := slugReplacer.Replace(norm.NFC.String(title))
+	cleaned = collapse(cleaned, '-')
+	cleaned = strings.Trim(cleaned, "-")
+	cleaned = strings.Trim(truncate(cleaned, MaxSlugLength), "-")
+
+	// Security: Use filepath.Clean to prevent path traversal and validate result
+	cleaned = filepath.Base(filepath.Clean(cleaned))
+	if cleaned == "." || cleaned == ".." || cleaned == "" || cleaned == "-" ||
+		strings.Contains(cleaned, string(filepath.Separator)) {
+		return FallbackName
+	}
+
+	return Portable(cleaned, MaxSlugLength)
+}
+
+// Clean keeps a title readable, spaces included, and only replaces characters
+// that are invalid in filenames on some platform. The result is at most
+// MaxNameLength bytes.
+func Clean(title string) string {
+	cleaned := cleanReplacer.Replace(norm.NFC.String(title))
+	cleaned = collapse(strings.TrimSpace(cleaned), ' ')
+
+	return Portable(cleaned, MaxNameLength)
+}
+
+// Portable applies the rules every filename needs regardless of style: NFC
+// normalization, no control characters, no trailing dots or spaces (Windows
+// strips them), no reserved device names, and at most maxLen bytes without
+// splitting a UTF-8 sequence. An empty result becomes "untitled".
+func Portable(name string, maxLen int) string {
+	name = strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) {
+			return -1
+		}
+
+		return r
+	}, norm.NFC.String(name))
+
+	if maxLen > 0 {
+		name = truncate(name, maxLen)
+	}
+
+	name = strings.TrimRight(strings.TrimSpace(name), ". ")
+	if name == "" {
+		return untitledName
+	}
+
+	base, ext, _ := strings.Cut(name, ".")
+	if windowsReserved[strings.ToUpper(strings.TrimRight(base, " "))] {
+		name = base + "_"
+		if ext != "" {
+			name += "." + ext
+		}
+	}
+
+	return name
+}
+
+// truncate shortens s to at most maxLen bytes on a rune boundary.
+func truncate(s string, maxLen int) string {
+	if len(s) <= maxLen {
+		return s
+	}
+
+	cut := maxLen
+	for cut > 0 && !utf8.RuneStart(s[cut]) {
+		cut--
+	}
+
+	return s[:cut]
+}
+
+// collapse squeezes runs of sep into a single sep.
+func collapse(s string, sep rune) string {
+	var b strings.Builder
+
+	b.Grow(len(s))
+
+	prevWasSep := false
+
+	for _, r := range s {
+		if r == sep {
+			if prevWasSep {
+				continue
+			}
+
+			prevWasSep = true
+		} else {
+			prevWasSep = false
+		}
+
+		b.WriteRune(r)
+	}
+
+	return b.String()
+}
@@ -0,0 +1,79 @@
+package naming
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestSlug(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{"spaces become hyphens", "Weekly Sync Notes", "Weekly-Sync-Notes"},
+		{"empty input", "", EmptyName},
+		{"only unsafe characters", "???", FallbackName},
+		{"reserved device name", "CON", "CON_"},
+		{"reserved name is case-insensitive", "lpt1", "lpt1_"},
+		{"reserved prefix is not reserved", "Console", "Console"},
+		{"emoji kept", "Meeting 📅 Tomorrow", "Meeting-📅-Tomorrow"},
+		{"decomposed unicode normalized to NFC", "Cafe\u0301", "Caf\u00e9"},
+		{"control characters dropped", "Line\x01Break\x7f", "LineBreak"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Slug(tt.input); got != tt.want {
+				t.Errorf("Slug(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestClean(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{"spaces kept", "Project  Plan: Q3", "Project Plan- Q3"},
+		{"invalid characters removed", `a*b?c"d<e>f|g`, "abcdef-g"},
+		{"trailing dots and spaces trimmed", "Draft... ", "Draft"},
+		{"reserved name with extension", "nul.txt", "nul_.txt"},
+		{"empty becomes untitled", "  ", "untitled"},
+		{"emoji kept", "🎉 Launch Party", "🎉 Launch Party"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Clean(tt.input); got != tt.want {
+				t.Errorf("Clean(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOverlongNamesStayValidUTF8(t *testing.T) {
+	// Each emoji is four bytes, so a byte-based cut would land mid-rune.
+	title := strings.Repeat("a", 79) + strings.Repeat("🚀", 100)
+
+	slug := Slug(title)
+	if len(slug) > MaxSlugLength {
+		t.Errorf("Slug length = %d, want <= %d", len(slug), MaxSlugLength)
+	}
+
+	if !utf8.ValidString(slug) {
+		t.Errorf("Slug produced invalid UTF-8: %q", slug)
+	}
+
+	clean := Clean(strings.Repeat("🚀 ", 200))
+	if len(clean) > MaxNameLength {
+		t.Errorf("Clean length = %d, want <= %d", len(clean), MaxNameLength)
+	}
+
+	if !utf8.ValidString(clean) || strings.HasSuffix(clean, " ") {
+		t.Errorf("Clean produced an invalid name: %q", clean)
+	}
+}
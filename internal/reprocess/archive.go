@@ -0,0 +1,34 @@
+package reprocess
+
+import (
+	"pkm-sync/internal/archive"
+	"pkm-sync/pkg/models"
+)
+
+// FromArchive rebuilds Gmail items from archived messages. The body is the
+// text indexed at archive time, so it reflects the pipeline that ran then.
+func FromArchive(messages []archive.StoredMessage) []models.FullItem {
+	items := make([]models.FullItem, 0, len(messages))
+
+	for _, msg := range messages {
+		item := models.NewBasicItem(msg.GmailID, msg.Subject)
+		item.SetSourceType("gmail")
+		item.SetItemType("email")
+		item.SetContent(msg.Body)
+		item.SetCreatedAt(msg.DateSent)
+		item.SetUpdatedAt(msg.DateSent)
+		item.SetMetadata(map[string]interface{}{
+			"from":        msg.FromAddr,
+			"to":          msg.ToAddrs,
+			"cc":          msg.CCAddrs,
+			"labels":      msg.Labels,
+			"thread_id":   msg.ThreadID,
+			"message_id":  msg.RFC822MessageID,
+			"source_name": msg.SourceName,
+		})
+
+		items = append(items, item)
+	}
+
+	return items
+}
@@ -0,0 +1,58 @@
+package reprocess
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+
+	"pkm-sync/internal/sources/jsonl"
+	"pkm-sync/pkg/models"
+)
+
+// LoadJSONLFile reads items from a JSONL file such as the output of
+// `pkm-sync fetch --format json`.
+func LoadJSONLFile(path string) ([]models.FullItem, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	defer f.Close()
+
+	return LoadJSONL(f)
+}
+
+// LoadJSONL reads one serialized item per line. Lines carrying a "messages"
+// field are decoded as threads, dead-letter entries (an "item" object without
+// an id of their own) are unwrapped, and blank lines are skipped.
+func LoadJSONL(r io.Reader) ([]models.FullItem, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), jsonl.MaxLineBytes)
+
+	var items []models.FullItem
+
+	lineNum := 0
+
+	for scanner.Scan() {
+		lineNum++
+
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+
+		item, err := jsonl.DecodeItem(line)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", lineNum, err)
+		}
+
+		items = append(items, item)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read JSONL: %w", err)
+	}
+
+	return items, nil
+}
@@ -0,0 +1,120 @@
+package reprocess
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"pkm-sync/internal/archive"
+	"pkm-sync/pkg/models"
+)
+
+func TestLoadJSONL_ItemsAndThreads(t *testing.T) {
+	item := models.NewBasicItem("m1", "Hello")
+	item.SetSourceType("gmail")
+	item.SetContent("Hi there")
+
+	thread := models.NewThread("t1", "Thread")
+	thread.SetSourceType("gmail")
+	thread.AddMessage(models.NewBasicItem("m2", "Re: Thread"))
+
+	itemJSON, err := item.MarshalJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	threadJSON, err := thread.MarshalJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	items, err := LoadJSONL(strings.NewReader(string(itemJSON) + "\n\n" + string(threadJSON) + "\n"))
+	if err != nil {
+		t.Fatalf("LoadJSONL() failed: %v", err)
+	}
+
+	if len(items) != 2 {
+		t.Fatalf("expected 2 items, got %d", len(items))
+	}
+
+	if items[0].GetID() != "m1" || items[0].GetContent() != "Hi there" || models.IsThread(items[0]) {
+		t.Errorf("unexpected first item: %+v", items[0])
+	}
+
+	got, ok := models.AsThread(items[1])
+	if !ok || len(got.GetMessages()) != 1 {
+		t.Errorf("expected second item to be a thread with one message, got %+v", items[1])
+	}
+}
+
+func TestLoadJSONL_ReportsBadLine(t *testing.T) {
+	_, err := LoadJSONL(strings.NewReader("{\"id\":\"ok\"}\nnot json\n"))
+	if err == nil || !strings.Contains(err.Error(), "line 2") {
+		t.Errorf("expected error naming line 2, got %v", err)
+	}
+}
+
+func TestFromArchive(t *testing.T) {
+	sent := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	items := FromArchive([]archive.StoredMessage{{
+		Message: archive.Message{
+			GmailID:    "g1",
+			ThreadID:   "t1",
+			Subject:    "Invoice",
+			FromAddr:   "billing@example.com",
+			ToAddrs:    []string{"me@example.com"},
+			DateSent:   sent,
+			SourceName: "work",
+		},
+		Body: "Please pay",
+	}})
+
+	if len(items) != 1 {
+		t.Fatalf("expected 1 item, got %d", len(items))
+	}
+
+	item := items[0]
+	if item.GetID() != "g1" || item.GetTitle() != "Invoice" || item.GetContent() != "Please pay" {
+		t.Errorf("unexpected item: %+v", item)
+	}
+
+	if item.GetSourceType() != "gmail" || !item.GetCreatedAt().Equal(sent) {
+		t.Errorf("got source %q created %v", item.GetSourceType(), item.GetCreatedAt())
+	}
+
+	if item.GetMetadata()["from"] != "billing@example.com" {
+		t.Errorf("metadata = %v", item.GetMetadata())
+	}
+}
+
+func TestLoadJSONL_UnwrapsDeadLetterEntries(t *testing.T) {
+	item := models.NewBasicItem("m1", "Hello")
+	item.SetContent("Hi there")
+
+	itemJSON, err := item.MarshalJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	line := `{"failed_at":"2026-03-03T10:00:00Z","sink":"obsidian","error":"permission denied","item":` +
+		string(itemJSON) + "}\n"
+
+	items, err := LoadJSONL(strings.NewReader(line))
+	if err != nil {
+		t.Fatalf("LoadJSONL() failed: %v", err)
+	}
+
+	if len(items) != 1 || items[0].GetID() != "m1" || items[0].GetContent() != "Hi there" {
+		t.Fatalf("dead-letter entry not unwrapped: %+v", items)
+	}
+}
+
+func TestSource_Capabilities(t *testing.T) {
+	caps := NewSource("reprocess", nil).Capabilities()
+
+	// Reloaded exports are returned whole, so since must not narrow them.
+	if caps.SupportsIncremental || !caps.ProducesThreads {
+		t.Errorf("unexpected capabilities %+v", caps)
+	}
+}
@@ -0,0 +1,55 @@
+// Package reprocess loads items that were already exported — a JSONL dump, the
+// Gmail archive, or notes in a vault — so the current transformer pipeline can
+// be rerun over them without fetching from any API.
+package reprocess
+
+import (
+	"net/http"
+	"time"
+
+	"pkm-sync/pkg/interfaces"
+	"pkm-sync/pkg/models"
+)
+
+// Source serves previously exported items through interfaces.Source, letting
+// the reprocess command reuse the normal Transform → Sink path of MultiSyncer.
+type Source struct {
+	name  string
+	items []models.FullItem
+}
+
+// NewSource returns a Source that yields items as loaded.
+func NewSource(name string, items []models.FullItem) *Source {
+	return &Source{name: name, items: items}
+}
+
+func (s *Source) Name() string {
+	return s.name
+}
+
+func (s *Source) Configure(_ map[string]interface{}, _ *http.Client) error {
+	return nil
+}
+
+// Fetch returns the loaded items. since is ignored because the items were
+// already selected when they were first exported; limit still applies.
+func (s *Source) Fetch(_ time.Time, limit int) ([]models.FullItem, error) {
+	if limit > 0 && len(s.items) > limit {
+		return s.items[:limit], nil
+	}
+
+	return s.items, nil
+}
+
+func (s *Source) SupportsRealtime() bool {
+	return false
+}
+
+// Capabilities implements interfaces.Source. Reloaded exports are returned
+// whole and may include threads.
+func (s *Source) Capabilities() interfaces.SourceCapabilities {
+	return interfaces.SourceCapabilities{ProducesThreads: true}
+}
+
+// Ensure interface compliance.
+var _ interfaces.Source = (*Source)(nil)
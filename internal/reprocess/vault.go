@@ -0,0 +1,366 @@
+package reprocess
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"pkm-sync/pkg/models"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Keys the file sink writes itself; everything else in the header is metadata.
+const (
+	noteKeyID      = "id"
+	noteKeySource  = "source"
+	noteKeyType    = "type"
+	noteKeyCreated = "created"
+	noteKeyTags    = "tags"
+	// noteKeyFingerprint guards user edits (see sinks.FileSink.SetConflictPolicy).
+	noteKeyFingerprint = "pkm_sync_fingerprint"
+)
+
+var (
+	// markdownListLink matches "- [name](url)" lines in Attachments/Links sections.
+	markdownListLink = regexp.MustCompile(`^- \[(.*)\]\((.*)\)$`)
+	// logseqOrdinal strips the ordinal suffix from Logseq journal dates ("Jan 2nd, 2006").
+	logseqOrdinal = regexp.MustCompile(`(\d+)(st|nd|rd|th),`)
+)
+
+// LoadVault reads notes written by the Obsidian or Logseq sink from path, which
+// may be a single markdown file or a directory searched recursively. Markdown
+// files without a pkm-sync header (no id) are skipped.
+func LoadVault(path string) ([]models.FullItem, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("cannot access %s: %w", path, err)
+	}
+
+	var files []string
+
+	if info.IsDir() {
+		err = filepath.WalkDir(path, func(p string, d fs.DirEntry, walkErr error) error {
+			if walkErr != nil {
+				return walkErr
+			}
+
+			if d.IsDir() && strings.HasPrefix(d.Name(), ".") && p != path {
+				return filepath.SkipDir
+			}
+
+			if !d.IsDir() && strings.HasSuffix(d.Name(), ".md") {
+				files = append(files, p)
+			}
+
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan %s: %w", path, err)
+		}
+	} else {
+		files = []string{path}
+	}
+
+	var items []models.FullItem
+
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", file, err)
+		}
+
+		item, ok, err := ParseNote(data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", file, err)
+		}
+
+		if ok {
+			items = append(items, item)
+		}
+	}
+
+	return items, nil
+}
+
+// ParseNote rebuilds an item from a note written by the Obsidian (YAML
+// frontmatter) or Logseq (property block) formatter. ok is false when the note
+// was not written by pkm-sync. Thread notes come back as a single item whose
+// content holds the rendered messages.
+func ParseNote(data []byte) (item models.FullItem, ok bool, err error) {
+	text := strings.ReplaceAll(string(data), "\r\n", "\n")
+
+	var (
+		props map[string]interface{}
+		body  string
+	)
+
+	switch {
+	case strings.HasPrefix(text, "---\n"):
+		props, body, err = splitFrontmatter(text)
+	case strings.HasPrefix(text, "- "+noteKeyID+":: "):
+		props, body = splitLogseqProperties(text)
+	default:
+		return nil, false, nil
+	}
+
+	if err != nil {
+		return nil, false, err
+	}
+
+	id := propString(props, noteKeyID)
+	if id == "" {
+		return nil, false, nil
+	}
+
+	basic := models.NewBasicItem(id, "")
+	basic.SetSourceType(propString(props, noteKeySource))
+	basic.SetItemType(propString(props, noteKeyType))
+	basic.SetCreatedAt(propTime(props[noteKeyCreated]))
+	basic.SetUpdatedAt(basic.GetCreatedAt())
+	basic.SetTags(propTags(props[noteKeyTags]))
+
+	metadata := make(map[string]interface{})
+
+	for key, value := range props {
+		switch key {
+		case noteKeyID, noteKeySource, noteKeyType, noteKeyCreated, noteKeyTags, noteKeyFingerprint:
+			continue
+		case "attendees":
+			metadata[key] = unwrapWikilinks(value)
+		default:
+			metadata[key] = normalizeValue(value)
+		}
+	}
+
+	basic.SetMetadata(metadata)
+
+	title, content, attachments, links := splitBody(body)
+	basic.SetTitle(title)
+	basic.SetContent(content)
+	basic.SetAttachments(attachments)
+	basic.SetLinks(links)
+
+	return basic, true, nil
+}
+
+func splitFrontmatter(text string) (map[string]interface{}, string, error) {
+	rest := text[len("---\n"):]
+
+	end := strings.Index(rest, "\n---\n")
+	if end < 0 {
+		return nil, "", fmt.Errorf("unterminated frontmatter")
+	}
+
+	props := make(map[string]interface{})
+	if err := yaml.Unmarshal([]byte(rest[:end+1]), &props); err != nil {
+		return nil, "", fmt.Errorf("invalid frontmatter: %w", err)
+	}
+
+	return props, rest[end+len("\n---\n"):], nil
+}
+
+func splitLogseqProperties(text string) (map[string]interface{}, string) {
+	props := make(map[string]interface{})
+	lines := strings.Split(text, "\n")
+
+	i := 0
+	for ; i < len(lines); i++ {
+		key, value, found := strings.Cut(strings.TrimPrefix(lines[i], "- "), ":: ")
+		if !strings.HasPrefix(lines[i], "- ") || !found {
+			break
+		}
+
+		props[key] = value
+	}
+
+	return props, strings.Join(lines[i:], "\n")
+}
+
+// splitBody separates the "# Title" heading and the trailing Attachments and
+// Links sections the formatters append from the note's content.
+func splitBody(body string) (string, string, []models.Attachment, []models.Link) {
+	lines := strings.Split(strings.TrimSpace(body), "\n")
+
+	var title string
+
+	if len(lines) > 0 && strings.HasPrefix(lines[0], "# ") {
+		title = strings.TrimPrefix(lines[0], "# ")
+		lines = lines[1:]
+	}
+
+	var (
+		attachments []models.Attachment
+		links       []models.Link
+	)
+
+	for {
+		heading, entries, remaining, found := trailingListSection(lines)
+		if !found {
+			break
+		}
+
+		switch heading {
+		case "## Links":
+			for _, entry := range entries {
+				if m := markdownListLink.FindStringSubmatch(entry); m != nil {
+					links = append(links, models.Link{Title: m[1], URL: m[2]})
+				}
+			}
+		case "## Attachments":
+			for _, entry := range entries {
+				attachments = append(attachments, parseAttachment(entry))
+			}
+		}
+
+		lines = remaining
+	}
+
+	return title, strings.TrimSpace(strings.Join(lines, "\n")), attachments, links
+}
+
+// trailingListSection reports the last "## Links" or "## Attachments" section
+// when it ends the note and holds only list items.
+func trailingListSection(lines []string) (string, []string, []string, bool) {
+	end := len(lines)
+	for end > 0 && strings.TrimSpace(lines[end-1]) == "" {
+		end--
+	}
+
+	var entries []string
+
+	for i := end - 1; i >= 0; i-- {
+		line := lines[i]
+
+		switch {
+		case line == "## Links" || line == "## Attachments":
+			if len(entries) == 0 {
+				return "", nil, nil, false
+			}
+
+			return line, entries, lines[:i], true
+		case strings.HasPrefix(line, "- "):
+			entries = append([]string{line}, entries...)
+		case strings.TrimSpace(line) == "":
+			continue
+		default:
+			return "", nil, nil, false
+		}
+	}
+
+	return "", nil, nil, false
+}
+
+func parseAttachment(entry string) models.Attachment {
+	if m := markdownListLink.FindStringSubmatch(entry); m != nil {
+		return models.Attachment{Name: m[1], URL: m[2]}
+	}
+
+	name := strings.TrimPrefix(entry, "- ")
+	name = strings.TrimSuffix(strings.TrimPrefix(name, "[["), "]]")
+
+	return models.Attachment{Name: name}
+}
+
+// propString reads a scalar property; YAML decodes numeric-looking IDs as numbers.
+func propString(props map[string]interface{}, key string) string {
+	switch v := props[key].(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+func propTime(value interface{}) time.Time {
+	switch v := value.(type) {
+	case time.Time:
+		return v
+	case string:
+		if t, err := time.Parse(time.RFC3339, v); err == nil {
+			return t
+		}
+
+		date := logseqOrdinal.ReplaceAllString(strings.Trim(v, "[]"), "$1,")
+		if t, err := time.Parse("Jan 2, 2006", date); err == nil {
+			return t
+		}
+	}
+
+	return time.Time{}
+}
+
+// propTags accepts the YAML list Obsidian writes and the "#a, #b" Logseq form.
+func propTags(value interface{}) []string {
+	switch v := value.(type) {
+	case []interface{}:
+		tags := make([]string, 0, len(v))
+
+		for _, tag := range v {
+			tags = append(tags, fmt.Sprint(tag))
+		}
+
+		return tags
+	case string:
+		var tags []string
+
+		for _, tag := range strings.Split(v, ",") {
+			if tag = strings.TrimPrefix(strings.TrimSpace(tag), "#"); tag != "" {
+				tags = append(tags, tag)
+			}
+		}
+
+		return tags
+	}
+
+	return nil
+}
+
+// normalizeValue turns YAML string lists back into []string, which is the form
+// the formatters render as lists rather than Go slice syntax.
+func normalizeValue(value interface{}) interface{} {
+	list, ok := value.([]interface{})
+	if !ok {
+		return value
+	}
+
+	strs := make([]string, 0, len(list))
+
+	for _, elem := range list {
+		s, isString := elem.(string)
+		if !isString {
+			return value
+		}
+
+		strs = append(strs, s)
+	}
+
+	return strs
+}
+
+// unwrapWikilinks strips the [[...]] the Obsidian formatter puts around attendee
+// names, so re-exporting does not nest them.
+func unwrapWikilinks(value interface{}) interface{} {
+	list, ok := value.([]interface{})
+	if !ok {
+		return value
+	}
+
+	names := make([]interface{}, 0, len(list))
+
+	for _, elem := range list {
+		if s, isString := elem.(string); isString {
+			elem = strings.TrimSuffix(strings.TrimPrefix(s, "[["), "]]")
+		}
+
+		names = append(names, elem)
+	}
+
+	return names
+}
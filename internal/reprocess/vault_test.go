@@ -0,0 +1,165 @@
+package reprocess
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"pkm-sync/internal/sinks"
+	"pkm-sync/pkg/models"
+)
+
+func TestParseNote_ObsidianRoundTrip(t *testing.T) {
+	created := time.Date(2026, 3, 4, 9, 30, 0, 0, time.UTC)
+
+	original := models.NewBasicItem("12345", "Quarterly Review")
+	original.SetSourceType("google_calendar")
+	original.SetItemType("event")
+	original.SetCreatedAt(created)
+	original.SetTags([]string{"work", "review"})
+	original.SetContent("Agenda:\n\n- Budget\n- Hiring")
+	original.SetMetadata(map[string]interface{}{
+		"location":  "Room 4",
+		"attendees": []interface{}{"Ada", "Grace"},
+		"labels":    []string{"INBOX", "IMPORTANT"},
+	})
+	original.SetAttachments([]models.Attachment{{Name: "Slides", URL: "https://example.com/slides"}})
+	original.SetLinks([]models.Link{{Title: "Tracker", URL: "https://example.com/tracker"}})
+
+	note := sinks.NewObsidianFormatterPublic().FormatItemContent(original)
+
+	item, ok, err := ParseNote([]byte(note))
+	if err != nil || !ok {
+		t.Fatalf("ParseNote() = ok %v, err %v", ok, err)
+	}
+
+	if item.GetID() != "12345" || item.GetTitle() != "Quarterly Review" {
+		t.Errorf("got id %q title %q", item.GetID(), item.GetTitle())
+	}
+
+	if item.GetSourceType() != "google_calendar" || item.GetItemType() != "event" {
+		t.Errorf("got source %q type %q", item.GetSourceType(), item.GetItemType())
+	}
+
+	if !item.GetCreatedAt().Equal(created) {
+		t.Errorf("created = %v, want %v", item.GetCreatedAt(), created)
+	}
+
+	if item.GetContent() != original.GetContent() {
+		t.Errorf("content = %q, want %q", item.GetContent(), original.GetContent())
+	}
+
+	if !reflect.DeepEqual(item.GetTags(), original.GetTags()) {
+		t.Errorf("tags = %v", item.GetTags())
+	}
+
+	if !reflect.DeepEqual(item.GetAttachments(), original.GetAttachments()) {
+		t.Errorf("attachments = %+v", item.GetAttachments())
+	}
+
+	if !reflect.DeepEqual(item.GetLinks(), original.GetLinks()) {
+		t.Errorf("links = %+v", item.GetLinks())
+	}
+
+	metadata := item.GetMetadata()
+	if metadata["location"] != "Room 4" {
+		t.Errorf("location = %v", metadata["location"])
+	}
+
+	if !reflect.DeepEqual(metadata["labels"], []string{"INBOX", "IMPORTANT"}) {
+		t.Errorf("labels = %#v", metadata["labels"])
+	}
+
+	if !reflect.DeepEqual(metadata["attendees"], []interface{}{"Ada", "Grace"}) {
+		t.Errorf("attendees = %#v", metadata["attendees"])
+	}
+
+	// Re-rendering the parsed item reproduces the note.
+	if again := sinks.NewObsidianFormatterPublic().FormatItemContent(item); len(again) != len(note) {
+		t.Errorf("re-rendered note differs:\n%s\nwant:\n%s", again, note)
+	}
+}
+
+func TestParseNote_Logseq(t *testing.T) {
+	note := "- id:: doc1\n- source:: google_drive\n- type:: document\n- created:: [[Mar 3rd, 2026]]\n" +
+		"- owner:: ada@example.com\n- tags:: #drive, #plans\n\n# Roadmap\n\nBody text\n\n" +
+		"## Attachments\n- [[notes.pdf]]\n\n"
+
+	item, ok, err := ParseNote([]byte(note))
+	if err != nil || !ok {
+		t.Fatalf("ParseNote() = ok %v, err %v", ok, err)
+	}
+
+	if item.GetTitle() != "Roadmap" || item.GetContent() != "Body text" {
+		t.Errorf("got title %q content %q", item.GetTitle(), item.GetContent())
+	}
+
+	if want := time.Date(2026, 3, 3, 0, 0, 0, 0, time.UTC); !item.GetCreatedAt().Equal(want) {
+		t.Errorf("created = %v, want %v", item.GetCreatedAt(), want)
+	}
+
+	if !reflect.DeepEqual(item.GetTags(), []string{"drive", "plans"}) {
+		t.Errorf("tags = %v", item.GetTags())
+	}
+
+	if item.GetMetadata()["owner"] != "ada@example.com" {
+		t.Errorf("metadata = %v", item.GetMetadata())
+	}
+
+	if atts := item.GetAttachments(); len(atts) != 1 || atts[0].Name != "notes.pdf" {
+		t.Errorf("attachments = %+v", atts)
+	}
+}
+
+func TestParseNote_IgnoresFingerprint(t *testing.T) {
+	note := "---\npkm_sync_fingerprint: 0123abcd\nid: n1\nsource: gmail\ntype: email\n---\n\n# Hello\n\nBody\n"
+
+	item, ok, err := ParseNote([]byte(note))
+	if err != nil || !ok {
+		t.Fatalf("ParseNote() = ok %v, err %v", ok, err)
+	}
+
+	if _, found := item.GetMetadata()["pkm_sync_fingerprint"]; found {
+		t.Errorf("metadata = %v, want the fingerprint left out", item.GetMetadata())
+	}
+}
+
+func TestParseNote_SkipsForeignNotes(t *testing.T) {
+	for _, note := range []string{"# Just a note\n\nText", "---\ntitle: Mine\n---\n\nText"} {
+		if _, ok, err := ParseNote([]byte(note)); ok || err != nil {
+			t.Errorf("ParseNote(%q) = ok %v, err %v; want skipped", note, ok, err)
+		}
+	}
+}
+
+func TestLoadVault_WalksDirectory(t *testing.T) {
+	dir := t.TempDir()
+
+	write := func(rel, content string) {
+		path := filepath.Join(dir, rel)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	write("a.md", "---\nid: a\nsource: gmail\ntype: email\n---\n\n# A\n\nBody A\n")
+	write("sub/b.md", "---\nid: b\nsource: gmail\ntype: email\n---\n\n# B\n\nBody B\n")
+	write("personal.md", "# Mine\n")
+	write(".obsidian/c.md", "---\nid: c\n---\n\n# C\n")
+	write("notes.txt", "---\nid: d\n---\n")
+
+	items, err := LoadVault(dir)
+	if err != nil {
+		t.Fatalf("LoadVault() failed: %v", err)
+	}
+
+	if len(items) != 2 || items[0].GetID() != "a" || items[1].GetID() != "b" {
+		t.Errorf("expected notes a and b, got %d items", len(items))
+	}
+}
@@ -25,7 +25,8 @@ const (
 )
 
 // handleSearch performs semantic (vector KNN) search over vectors.db.
-// Query params: q (required), source_type, source_name, limit, min_score.
+// Query params: q (required), source_type, source_name, limit, min_score
+// (cosine similarity; defaults to Config.MinScore).
 // The response shape matches `pkm-sync search --format json`.
 func (s *Server) handleSearch(w http.ResponseWriter, r *http.Request) {
 	query := r.URL.Query().Get("q")
@@ -41,7 +42,7 @@ func (s *Server) handleSearch(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	minScore := 0.0
+	minScore := s.cfg.MinScore
 
 	if raw := r.URL.Query().Get("min_score"); raw != "" {
 		if v, err := strconv.ParseFloat(raw, 64); err == nil {
@@ -112,6 +113,60 @@ func (s *Server) handleSearch(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
+// handleItem returns one indexed item from vectors.db by thread or source ID.
+// Query param source_name disambiguates an ID present in several sources;
+// without it such a request gets 409 listing the candidates.
+func (s *Server) handleItem(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+
+	store, err := s.vectors()
+	if err != nil {
+		writeError(w, http.StatusServiceUnavailable, "vector store unavailable: "+err.Error())
+
+		return
+	}
+
+	docs, err := store.GetDocuments(id, r.URL.Query().Get("source_name"))
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "item lookup failed: "+err.Error())
+
+		return
+	}
+
+	switch {
+	case len(docs) == 0:
+		writeError(w, http.StatusNotFound, "item not found: "+id)
+
+		return
+	case len(docs) > 1:
+		names := make([]string, len(docs))
+		for i, doc := range docs {
+			names[i] = doc.SourceName
+		}
+
+		writeError(w, http.StatusConflict, "item "+id+" exists in several sources ("+
+			strings.Join(names, ", ")+"); pass source_name to choose one")
+
+		return
+	}
+
+	doc := docs[0]
+
+	writeJSON(w, http.StatusOK, map[string]any{
+		"thread_id":     doc.ThreadID,
+		"source_id":     doc.SourceID,
+		"title":         doc.Title,
+		"content":       doc.Content,
+		"source_type":   doc.SourceType,
+		"source_name":   doc.SourceName,
+		"message_count": doc.MessageCount,
+		"created_at":    doc.CreatedAt.Format(time.RFC3339),
+		"updated_at":    doc.UpdatedAt.Format(time.RFC3339),
+		"indexed_at":    doc.IndexedAt.Format(time.RFC3339),
+		"metadata":      doc.Metadata,
+	})
+}
+
 // handleEmails searches the Gmail archive (archive.db). Query params: q
 // (FTS4 MATCH), from (sender substring), since (date_sent >= YYYY-MM-DD),
 // limit, body (include body text). At least one of q/from/since is required.
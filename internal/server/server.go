@@ -1,6 +1,7 @@
-// Package server implements the pkm-sync HTTP API: read-only search and
-// status endpoints over the SQLite databases maintained by sync/index runs,
-// plus a Prometheus /metrics endpoint (a port of the former Python exporter).
+// Package server implements the pkm-sync HTTP API: read-only search, item and
+// status endpoints over the SQLite databases maintained by sync/index runs, an
+// endpoint that triggers a sync, plus a Prometheus /metrics endpoint (a port
+// of the former Python exporter).
 package server
 
 import (
@@ -13,6 +14,7 @@ import (
 	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 
 	"pkm-sync/internal/embeddings"
 	"pkm-sync/internal/vectorstore"
@@ -30,6 +32,12 @@ type Config struct {
 	SlackDBPath   string // slack.db (Slack message archive)
 	UserCachePath string // slack-user-cache.json (user ID -> display name)
 	Dimensions    int    // embedding dimensions, must match vectors.db
+
+	// MinScore is the default /api/search min_score (cosine similarity).
+	MinScore float64
+
+	// Sync runs POST /api/sync; nil disables the endpoint.
+	Sync SyncFunc
 }
 
 // Server is the pkm-sync HTTP API server.
@@ -44,6 +52,9 @@ type Server struct {
 	mu       sync.Mutex
 	dbs      map[string]*sql.DB
 	vecStore *vectorstore.Store
+
+	// syncing is set while a POST /api/sync run is in progress.
+	syncing atomic.Bool
 }
 
 // New creates a Server. The embedder may be nil, in which case /api/search
@@ -68,6 +79,8 @@ func (s *Server) Handler() http.Handler {
 	mux.Handle("GET /api/emails", s.auth(http.HandlerFunc(s.handleEmails)))
 	mux.Handle("GET /api/slack/messages", s.auth(http.HandlerFunc(s.handleSlackMessages)))
 	mux.Handle("GET /api/status", s.auth(http.HandlerFunc(s.handleStatus)))
+	mux.Handle("GET /api/items/{id}", s.auth(http.HandlerFunc(s.handleItem)))
+	mux.Handle("POST /api/sync", s.auth(http.HandlerFunc(s.handleSync)))
 
 	return mux
 }
@@ -452,3 +452,21 @@ func TestQueryStore(t *testing.T) {
 	_, err = vectorstore.NewQueryStore(filepath.Join(dir, "missing.db"), 3)
 	assert.Error(t, err)
 }
+
+func TestItem(t *testing.T) {
+	srv, token := newTestServer(t)
+
+	rec := get(t, srv, token, "/api/items/t1")
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	body := decodeBody(t, rec)
+	assert.Equal(t, "Deploy failed in prod", body["title"])
+	assert.Equal(t, "m1", body["source_id"])
+	assert.Equal(t, "slack_redhat", body["source_name"])
+
+	// Source IDs resolve too, and source_name narrows the lookup.
+	assert.Equal(t, http.StatusOK, get(t, srv, token, "/api/items/m2?source_name=slack_redhat").Code)
+	assert.Equal(t, http.StatusNotFound, get(t, srv, token, "/api/items/t1?source_name=gmail_work").Code)
+	assert.Equal(t, http.StatusNotFound, get(t, srv, token, "/api/items/missing").Code)
+	assert.Equal(t, http.StatusUnauthorized, get(t, srv, "", "/api/items/t1").Code)
+}
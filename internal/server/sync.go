@@ -0,0 +1,142 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"mime"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+
+	syncer "pkm-sync/internal/sync"
+)
+
+// maxSyncRequestBytes bounds the POST /api/sync body.
+const maxSyncRequestBytes = 64 * 1024
+
+// SyncRequest is the JSON body of POST /api/sync. Every field is optional:
+// an empty body syncs all enabled sources, like a plain `pkm-sync sync`.
+type SyncRequest struct {
+	Sources []string `json:"sources"` // source names or type aliases, or ["all"]
+	Since   string   `json:"since"`   // same formats as --since
+	Limit   int      `json:"limit"`   // items per source; 0 uses the CLI default
+	DryRun  bool     `json:"dry_run"`
+	Full    bool     `json:"full"` // like --full: ignore the incremental window
+}
+
+// ErrSyncUnavailable marks a SyncFunc error that is the server's fault, such
+// as a config.yaml that no longer loads; handleSync answers it with 503
+// instead of blaming the request.
+var ErrSyncUnavailable = errors.New("sync unavailable")
+
+// SyncFunc runs one sync. It returns a nil summary when the request is
+// rejected before anything is synced (unknown source, nothing enabled), and
+// otherwise the run summary alongside any error.
+type SyncFunc func(ctx context.Context, req SyncRequest) (*syncer.RunSummary, error)
+
+// handleSync triggers a sync and responds with its run summary once it
+// finishes: 200 on success, 500 when a group failed. Only one sync runs at a
+// time; a request arriving meanwhile gets 409. The run is detached from the
+// request context so a client disconnect does not abort it half-way.
+//
+// Without a token, loopback binding alone does not stop a web page from
+// posting here, so the body must be declared as JSON (which browsers only
+// send cross-origin after a CORS preflight this server never grants) and a
+// browser Origin must be the server's own. A DNS-rebinding page is
+// same-origin with a name that resolves to loopback, so without a token the
+// Host must also be a loopback name or address.
+func (s *Server) handleSync(w http.ResponseWriter, r *http.Request) {
+	if s.cfg.Sync == nil {
+		writeError(w, http.StatusServiceUnavailable, "sync is not enabled on this server")
+
+		return
+	}
+
+	if mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type")); err != nil || mediaType != "application/json" {
+		writeError(w, http.StatusUnsupportedMediaType, "sync request must have Content-Type: application/json")
+
+		return
+	}
+
+	if !sameOrigin(r) {
+		writeError(w, http.StatusForbidden, "cross-origin sync requests are not allowed")
+
+		return
+	}
+
+	if s.cfg.Token == "" && !loopbackHost(r.Host) {
+		writeError(w, http.StatusForbidden, "sync requests without a token must address the server as localhost")
+
+		return
+	}
+
+	var req SyncRequest
+
+	dec := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxSyncRequestBytes))
+	dec.DisallowUnknownFields()
+
+	if err := dec.Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		writeError(w, http.StatusBadRequest, "invalid sync request: "+err.Error())
+
+		return
+	}
+
+	if req.Limit < 0 {
+		writeError(w, http.StatusBadRequest, "limit must not be negative")
+
+		return
+	}
+
+	if !s.syncing.CompareAndSwap(false, true) {
+		writeError(w, http.StatusConflict, "a sync is already running")
+
+		return
+	}
+	defer s.syncing.Store(false)
+
+	summary, err := s.cfg.Sync(context.WithoutCancel(r.Context()), req)
+
+	switch {
+	case err == nil:
+		writeJSON(w, http.StatusOK, summary)
+	case errors.Is(err, ErrSyncUnavailable):
+		writeError(w, http.StatusServiceUnavailable, err.Error())
+	case summary == nil:
+		writeError(w, http.StatusBadRequest, err.Error())
+	default:
+		writeJSON(w, http.StatusInternalServerError, summary)
+	}
+}
+
+// loopbackHost reports whether a request Host header, with or without a
+// port, names the loopback interface: localhost, 127.0.0.1 or [::1].
+func loopbackHost(host string) bool {
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+
+	host = strings.TrimSuffix(strings.TrimPrefix(host, "["), "]")
+	if strings.EqualFold(host, "localhost") {
+		return true
+	}
+
+	ip := net.ParseIP(host)
+
+	return ip != nil && ip.IsLoopback()
+}
+
+// sameOrigin reports whether r carries no Origin header (curl, scripts) or
+// one naming the host the request was sent to.
+func sameOrigin(r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return true
+	}
+
+	u, err := url.Parse(origin)
+
+	return err == nil && u.Host != "" && u.Host == r.Host
+}
@@ -0,0 +1,227 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	syncer "pkm-sync/internal/sync"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// postSync performs an authenticated POST /api/sync with the given body.
+func postSync(t *testing.T, srv *Server, token, body string) *httptest.ResponseRecorder {
+	t.Helper()
+
+	req := httptest.NewRequest(http.MethodPost, "/api/sync", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	rec := httptest.NewRecorder()
+	srv.Handler().ServeHTTP(rec, req)
+
+	return rec
+}
+
+func finishedSummary(dryRun bool, groupErr error) *syncer.RunSummary {
+	summary := syncer.NewRunSummary(time.Now(), dryRun)
+	summary.AddError("Slack", groupErr)
+	summary.Finish(time.Now())
+
+	return summary
+}
+
+func TestSync(t *testing.T) {
+	srv, token := newTestServer(t)
+
+	var got SyncRequest
+
+	srv.cfg.Sync = func(_ context.Context, req SyncRequest) (*syncer.RunSummary, error) {
+		got = req
+
+		return finishedSummary(req.DryRun, nil), nil
+	}
+
+	rec := postSync(t, srv, token, `{"sources": ["slack_redhat"], "since": "7d", "limit": 5, "dry_run": true}`)
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	assert.Equal(t, SyncRequest{Sources: []string{"slack_redhat"}, Since: "7d", Limit: 5, DryRun: true}, got)
+
+	body := decodeBody(t, rec)
+	assert.Equal(t, true, body["success"])
+	assert.Equal(t, true, body["dry_run"])
+
+	// An empty body syncs every enabled source with defaults.
+	require.Equal(t, http.StatusOK, postSync(t, srv, token, "").Code)
+	assert.Equal(t, SyncRequest{}, got)
+}
+
+func TestSyncErrors(t *testing.T) {
+	srv, token := newTestServer(t)
+
+	srv.cfg.Sync = func(_ context.Context, req SyncRequest) (*syncer.RunSummary, error) {
+		if len(req.Sources) > 0 && req.Sources[0] == "gmial" {
+			return nil, errors.New(`unknown source "gmial"`)
+		}
+
+		groupErr := errors.New("token expired")
+
+		return finishedSummary(false, groupErr), groupErr
+	}
+
+	assert.Equal(t, http.StatusUnauthorized, postSync(t, srv, "", "").Code)
+	assert.Equal(t, http.StatusBadRequest, postSync(t, srv, token, `{"sources": "gmail"}`).Code)
+	assert.Equal(t, http.StatusBadRequest, postSync(t, srv, token, `{"unknown": 1}`).Code)
+	assert.Equal(t, http.StatusBadRequest, postSync(t, srv, token, `{"limit": -1}`).Code)
+
+	rec := postSync(t, srv, token, `{"sources": ["gmial"]}`)
+	require.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Contains(t, decodeBody(t, rec)["error"], "unknown source")
+
+	// A failed group still reports the run summary.
+	rec = postSync(t, srv, token, "{}")
+	require.Equal(t, http.StatusInternalServerError, rec.Code)
+
+	body := decodeBody(t, rec)
+	assert.Equal(t, false, body["success"])
+	assert.NotEmpty(t, body["errors"])
+}
+
+func TestSyncRejectsBrowserRequests(t *testing.T) {
+	srv, _ := newTestServer(t)
+	srv.cfg.Token = "" // the default: loopback only, no token
+
+	var calls int
+
+	srv.cfg.Sync = func(_ context.Context, _ SyncRequest) (*syncer.RunSummary, error) {
+		calls++
+
+		return finishedSummary(false, nil), nil
+	}
+
+	post := func(contentType, origin string) int {
+		req := httptest.NewRequest(http.MethodPost, "http://127.0.0.1:8080/api/sync", strings.NewReader(`{}`))
+		if contentType != "" {
+			req.Header.Set("Content-Type", contentType)
+		}
+
+		if origin != "" {
+			req.Header.Set("Origin", origin)
+		}
+
+		rec := httptest.NewRecorder()
+		srv.Handler().ServeHTTP(rec, req)
+
+		return rec.Code
+	}
+
+	// A no-cors form or fetch post needs no preflight with these types.
+	assert.Equal(t, http.StatusUnsupportedMediaType, post("text/plain", "https://evil.example"))
+	assert.Equal(t, http.StatusUnsupportedMediaType, post("", ""))
+	assert.Equal(t, http.StatusForbidden, post("application/json", "https://evil.example"))
+	assert.Equal(t, http.StatusForbidden, post("application/json", "null"))
+	assert.Equal(t, 0, calls)
+
+	assert.Equal(t, http.StatusOK, post("application/json; charset=utf-8", ""))
+	assert.Equal(t, http.StatusOK, post("application/json", "http://127.0.0.1:8080"))
+	assert.Equal(t, 2, calls)
+}
+
+func TestSyncRejectsRebindingHostWithoutToken(t *testing.T) {
+	srv, _ := newTestServer(t)
+	srv.cfg.Token = ""
+
+	var calls int
+
+	srv.cfg.Sync = func(_ context.Context, _ SyncRequest) (*syncer.RunSummary, error) {
+		calls++
+
+		return finishedSummary(false, nil), nil
+	}
+
+	post := func(host string) int {
+		req := httptest.NewRequest(http.MethodPost, "/api/sync", strings.NewReader(`{}`))
+		req.Host = host
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Origin", "http://"+host)
+
+		rec := httptest.NewRecorder()
+		srv.Handler().ServeHTTP(rec, req)
+
+		return rec.Code
+	}
+
+	// A rebound name is same-origin with itself but is not loopback.
+	assert.Equal(t, http.StatusForbidden, post("evil.example"))
+	assert.Equal(t, http.StatusForbidden, post("evil.example:8080"))
+	assert.Equal(t, 0, calls)
+
+	for _, host := range []string{"localhost", "localhost:8080", "127.0.0.1", "127.0.0.1:8080", "[::1]", "[::1]:8080"} {
+		assert.Equal(t, http.StatusOK, post(host), host)
+	}
+
+	// With a token the caller authenticates, so any Host is fine.
+	srv.cfg.Token = "test-token"
+
+	req := httptest.NewRequest(http.MethodPost, "/api/sync", strings.NewReader(`{}`))
+	req.Host = "pkm.lan:8080"
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer test-token")
+
+	rec := httptest.NewRecorder()
+	srv.Handler().ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
+
+func TestSyncUnavailable(t *testing.T) {
+	srv, token := newTestServer(t)
+
+	srv.cfg.Sync = func(_ context.Context, _ SyncRequest) (*syncer.RunSummary, error) {
+		return nil, fmt.Errorf("%w: failed to load config: invalid transformer", ErrSyncUnavailable)
+	}
+
+	rec := postSync(t, srv, token, "{}")
+	require.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	assert.Contains(t, decodeBody(t, rec)["error"], "failed to load config")
+}
+
+func TestSyncRejectsConcurrentRuns(t *testing.T) {
+	srv, token := newTestServer(t)
+
+	started, release := make(chan struct{}), make(chan struct{})
+
+	srv.cfg.Sync = func(_ context.Context, _ SyncRequest) (*syncer.RunSummary, error) {
+		close(started)
+		<-release
+
+		return finishedSummary(false, nil), nil
+	}
+
+	first := make(chan int)
+
+	go func() {
+		first <- postSync(t, srv, token, "").Code
+	}()
+
+	<-started
+	assert.Equal(t, http.StatusConflict, postSync(t, srv, token, "").Code)
+
+	close(release)
+	assert.Equal(t, http.StatusOK, <-first)
+}
+
+func TestSyncDisabled(t *testing.T) {
+	srv, token := newTestServer(t)
+
+	assert.Equal(t, http.StatusServiceUnavailable, postSync(t, srv, token, "").Code)
+}
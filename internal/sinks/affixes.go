@@ -0,0 +1,100 @@
+package sinks
+
+import (
+	"fmt"
+	"strings"
+
+	"pkm-sync/internal/formatters"
+	"pkm-sync/pkg/models"
+)
+
+// contentAffixes renders the target's content_prefix and content_suffix
+// templates around every note body. The templates get the same fields and
+// functions as formatter templates ({{.Title}}, {{.Date}}, formatDate, ...).
+type contentAffixes struct {
+	prefix *formatters.TemplateFormatter
+	suffix *formatters.TemplateFormatter
+}
+
+// newContentAffixes compiles the content_prefix and content_suffix formatter
+// config keys. It returns nil when neither is set.
+func newContentAffixes(config map[string]any, dates dateRendering) (*contentAffixes, error) {
+	prefix, _ := config["content_prefix"].(string)
+	suffix, _ := config["content_suffix"].(string)
+
+	if prefix == "" && suffix == "" {
+		return nil, nil
+	}
+
+	reg, err := formatters.BuildRegistry([]models.FormatterConfig{
+		{Name: "content_prefix", Spec: models.FormatterSpec{ContentTemplate: prefix}},
+		{Name: "content_suffix", Spec: models.FormatterSpec{ContentTemplate: suffix}},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	reg.SetDateRendering(dates.loc, dates.layout)
+
+	a := &contentAffixes{}
+	a.prefix, _ = reg.Lookup("content_prefix")
+	a.suffix, _ = reg.Lookup("content_suffix")
+
+	return a, nil
+}
+
+// apply inserts the rendered prefix after the note's frontmatter or Logseq
+// property block, so those stay first, and appends the rendered suffix. A
+// template that renders empty adds nothing.
+func (a *contentAffixes) apply(item models.FullItem, content string) (string, error) {
+	if a == nil {
+		return content, nil
+	}
+
+	prefix, err := a.prefix.FormatContent(item)
+	if err != nil {
+		return "", fmt.Errorf("content_prefix: %w", err)
+	}
+
+	suffix, err := a.suffix.FormatContent(item)
+	if err != nil {
+		return "", fmt.Errorf("content_suffix: %w", err)
+	}
+
+	if prefix != "" {
+		header, body := splitNoteHeader(content)
+		if header != "" {
+			header += "\n"
+		}
+
+		content = header + prefix + "\n\n" + strings.TrimLeft(body, "\n")
+	}
+
+	if suffix != "" {
+		content = strings.TrimRight(content, "\n") + "\n\n" + suffix + "\n"
+	}
+
+	return content, nil
+}
+
+// splitNoteHeader splits a note into its leading YAML frontmatter or Logseq
+// property block (ending in a newline) and the body after it. Content with
+// neither has an empty header.
+func splitNoteHeader(content string) (header, body string) {
+	switch {
+	case strings.HasPrefix(content, "---\n"):
+		if end := strings.Index(content[len("---\n"):], "\n---\n"); end >= 0 {
+			cut := len("---\n") + end + len("\n---\n")
+
+			return content[:cut], content[cut:]
+		}
+	case strings.HasPrefix(content, "- "):
+		if end := strings.Index(content, "\n\n"); end >= 0 {
+			return content[:end+1], content[end+1:]
+		}
+
+		return content, ""
+	}
+
+	return "", content
+}
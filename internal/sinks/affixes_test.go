@@ -0,0 +1,69 @@
+package sinks
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"pkm-sync/pkg/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var affixConfig = map[string]any{
+	"content_prefix": "Synced from {{.SourceType}} on {{formatDate \"2006-01-02\" .CreatedAt}}",
+	"content_suffix": "---\n*{{.Title}} ({{.ID}}) by pkm-sync*",
+}
+
+func writeAffixedNote(t *testing.T, target string) string {
+	t.Helper()
+
+	dir := t.TempDir()
+	sink, err := NewFileSink(target, dir, affixConfig)
+	require.NoError(t, err)
+
+	item := makeTestItem("PROJ-1", "Fix login", "The login page is broken.")
+	require.NoError(t, sink.Write(context.Background(), []models.FullItem{item}))
+
+	data, err := os.ReadFile(filepath.Join(dir, sink.fmt.formatFilename("Fix login")))
+	require.NoError(t, err)
+
+	return string(data)
+}
+
+func TestContentAffixes_ObsidianKeepsFrontmatterFirst(t *testing.T) {
+	note := writeAffixedNote(t, "obsidian")
+
+	require.True(t, strings.HasPrefix(note, "---\n"), "frontmatter must open the note:\n%s", note)
+
+	header, body := splitNoteHeader(note)
+	assert.Contains(t, header, "id: PROJ-1")
+	assert.NotContains(t, header, "Synced from")
+	assert.True(t, strings.HasPrefix(body, "\nSynced from jira on 2026-04-16\n\n"), "body:\n%s", body)
+	assert.Contains(t, body, "The login page is broken.")
+	assert.True(t, strings.HasSuffix(note, "\n\n---\n*Fix login (PROJ-1) by pkm-sync*\n"), "note:\n%s", note)
+}
+
+func TestContentAffixes_LogseqKeepsPropertiesFirst(t *testing.T) {
+	note := writeAffixedNote(t, "logseq")
+
+	header, body := splitNoteHeader(note)
+	require.NotEmpty(t, header, "note:\n%s", note)
+	assert.NotContains(t, header, "Synced from")
+	assert.True(t, strings.HasPrefix(body, "\nSynced from jira on 2026-04-16\n\n"), "body:\n%s", body)
+	assert.True(t, strings.HasSuffix(note, "*Fix login (PROJ-1) by pkm-sync*\n"), "note:\n%s", note)
+}
+
+func TestContentAffixes_InvalidTemplate(t *testing.T) {
+	_, err := NewFileSink("obsidian", t.TempDir(), map[string]any{"content_prefix": "{{.Title"})
+	assert.Error(t, err)
+}
+
+func TestSplitNoteHeader_NoHeader(t *testing.T) {
+	header, body := splitNoteHeader("# Title\n\nBody")
+	assert.Empty(t, header)
+	assert.Equal(t, "# Title\n\nBody", body)
+}
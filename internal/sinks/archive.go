@@ -17,9 +17,7 @@ import (
 
 // RawMessageFetcher fetches raw RFC 5322 bytes for a Gmail message ID.
 // The Gmail *Service type satisfies this interface.
-type RawMessageFetcher interface {
-	GetMessageRaw(messageID string) ([]byte, error)
-}
+type RawMessageFetcher = interfaces.RawMessageFetcher
 
 // ArchiveSinkConfig holds configuration for ArchiveSink.
 type ArchiveSinkConfig struct {
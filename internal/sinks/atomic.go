@@ -0,0 +1,58 @@
+package sinks
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// writeFileAtomic replaces path with data so that readers, and the next run
+// after a crash, see either the old file or the new one, never a partial
+// write. Vaults are often watched by git or cloud sync clients, which would
+// otherwise pick up a truncated note.
+func writeFileAtomic(path string, data []byte, perm os.FileMode) error {
+	return writeAtomic(path, perm, func(w io.Writer) error {
+		_, err := w.Write(data)
+
+		return err
+	})
+}
+
+// writeAtomic streams write into a temporary file next to path, syncs it to
+// disk and renames it into place. When write fails the temporary file is
+// removed and path is left untouched. The temporary name starts with a dot
+// and does not end in .md, so note indexing skips one left by a killed run.
+func writeAtomic(path string, perm os.FileMode, write func(w io.Writer) error) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("creating temporary file for %s: %w", path, err)
+	}
+
+	tmpPath := tmp.Name()
+
+	err = write(tmp)
+	if err == nil {
+		err = tmp.Sync()
+	}
+
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+
+	if err == nil {
+		err = os.Chmod(tmpPath, perm)
+	}
+
+	if err == nil {
+		err = os.Rename(tmpPath, path)
+	}
+
+	if err != nil {
+		_ = os.Remove(tmpPath)
+
+		return fmt.Errorf("writing %s: %w", path, err)
+	}
+
+	return nil
+}
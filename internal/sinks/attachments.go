@@ -0,0 +1,63 @@
+package sinks
+
+import (
+	"log/slog"
+	"os"
+	"path/filepath"
+
+	"pkm-sync/pkg/models"
+)
+
+// SetAttachmentFolder makes Write copy every attachment a source downloaded
+// (LocalPath set) into folder under the output directory, where the
+// attachment_link_rewrite transformer links it. An empty folder disables
+// copying.
+func (s *FileSink) SetAttachmentFolder(folder string) {
+	s.attachmentFolder = folder
+}
+
+// copyAttachments copies item's downloaded attachments into the attachment
+// folder. A copy already there with the same size is kept. Attachments that
+// cannot be copied are logged; the note is still written.
+func (s *FileSink) copyAttachments(item models.FullItem) {
+	if s.attachmentFolder == "" {
+		return
+	}
+
+	dir := filepath.Join(s.outputDir, s.attachmentFolder)
+
+	for _, attachment := range item.GetAttachments() {
+		if attachment.LocalPath == "" {
+			continue
+		}
+
+		dest := filepath.Join(dir, filepath.Base(attachment.LocalPath))
+		if err := copyAttachment(attachment.LocalPath, dest); err != nil {
+			slog.Warn("Failed to copy attachment into the vault",
+				"id", item.GetID(), "attachment", attachment.Name, "path", attachment.LocalPath, "error", err)
+		}
+	}
+}
+
+// copyAttachment copies src to dest unless dest already has src's size.
+func copyAttachment(src, dest string) error {
+	info, err := os.Stat(src)
+	if err != nil {
+		return err
+	}
+
+	if existing, err := os.Stat(dest); err == nil && existing.Size() == info.Size() {
+		return nil
+	}
+
+	data, err := os.ReadFile(src)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(dest), 0755); err != nil {
+		return err
+	}
+
+	return writeFileAtomic(dest, data, 0644)
+}
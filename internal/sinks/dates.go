@@ -0,0 +1,49 @@
+package sinks
+
+import (
+	"log/slog"
+	"time"
+)
+
+// defaultDateFormat is the Go layout for rendered dates when date_format is
+// unset.
+const defaultDateFormat = "2006-01-02"
+
+// dateRendering renders item timestamps in the user's zone (app.timezone)
+// and date layout (app.date_format), so a note's date does not depend on the
+// zone the source reported the time in.
+type dateRendering struct {
+	// loc is the zone to render in; nil keeps each time's own zone.
+	loc    *time.Location
+	layout string
+}
+
+// configureDateRendering reads the "timezone" (IANA name) and "date_format"
+// formatter config keys. An unknown zone is logged and ignored.
+func configureDateRendering(config map[string]any) dateRendering {
+	dates := dateRendering{layout: defaultDateFormat}
+
+	if layout, ok := config["date_format"].(string); ok && layout != "" {
+		dates.layout = layout
+	}
+
+	if name, ok := config["timezone"].(string); ok && name != "" {
+		loc, err := time.LoadLocation(name)
+		if err != nil {
+			slog.Warn("Unknown timezone; rendering dates in their original zone", "timezone", name, "error", err)
+		} else {
+			dates.loc = loc
+		}
+	}
+
+	return dates
+}
+
+// in converts t to the configured zone.
+func (d dateRendering) in(t time.Time) time.Time {
+	if d.loc == nil || t.IsZero() {
+		return t
+	}
+
+	return t.In(d.loc)
+}
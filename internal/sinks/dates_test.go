@@ -0,0 +1,67 @@
+package sinks
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"pkm-sync/pkg/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// lateUTCItem is created at 23:30 UTC, already the next day in UTC+9.
+func lateUTCItem() models.FullItem {
+	item := models.NewBasicItem("evt-1", "Late Call")
+	item.SetSourceType("google_calendar")
+	item.SetItemType("event")
+	item.SetCreatedAt(time.Date(2026, 3, 1, 23, 30, 0, 0, time.UTC))
+	item.SetMetadata(map[string]interface{}{"start_time": time.Date(2026, 3, 1, 23, 30, 0, 0, time.UTC)})
+
+	return item
+}
+
+func TestFileSink_TimezoneRendersLocalDate(t *testing.T) {
+	if _, err := time.LoadLocation("Asia/Tokyo"); err != nil {
+		t.Skipf("tz database unavailable: %v", err)
+	}
+
+	dir := t.TempDir()
+	sink, err := NewFileSink("obsidian", dir, map[string]any{"timezone": "Asia/Tokyo"})
+	require.NoError(t, err)
+	require.NoError(t, sink.Write(context.Background(), []models.FullItem{lateUTCItem()}))
+
+	path := filepath.Join(dir, "2026", "03-March", "02-Monday", sink.fmt.formatFilename("Late Call"))
+	content, err := os.ReadFile(path)
+	require.NoError(t, err, "event should be filed under its Tokyo date")
+	assert.Contains(t, string(content), "created: 2026-03-02T08:30:00+09:00\n")
+}
+
+func TestLogseq_TimezoneRendersLocalJournalDate(t *testing.T) {
+	if _, err := time.LoadLocation("Asia/Tokyo"); err != nil {
+		t.Skipf("tz database unavailable: %v", err)
+	}
+
+	f := newLogseqFormatter()
+	f.configure(map[string]any{"timezone": "Asia/Tokyo"})
+
+	assert.Contains(t, f.formatContent(lateUTCItem()), "- created:: [[Mar 2nd, 2026]]\n")
+}
+
+func TestFileSink_NoTimezoneKeepsOriginalZone(t *testing.T) {
+	f := newObsidianFormatter()
+	f.configure(map[string]any{})
+
+	assert.Contains(t, f.formatContent(lateUTCItem()), "created: 2026-03-01T23:30:00Z\n")
+	assert.Equal(t, filepath.Join("2026", "03-March", "01-Sunday"), dateSubdirForItem(lateUTCItem(), f.dates))
+}
+
+func TestConfigureDateRendering_UnknownZoneIgnored(t *testing.T) {
+	dates := configureDateRendering(map[string]any{"timezone": "Mars/Olympus_Mons", "date_format": "02.01.2006"})
+
+	assert.Nil(t, dates.loc)
+	assert.Equal(t, "02.01.2006", dates.layout)
+}
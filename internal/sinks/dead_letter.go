@@ -0,0 +1,82 @@
+package sinks
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"pkm-sync/pkg/models"
+)
+
+// deadLetterMu serializes appends across sinks, since concurrent source groups
+// each own a FileSink but share one dead-letter file.
+var deadLetterMu sync.Mutex
+
+// DeadLetterEntry is one line of the dead-letter file: the item that could not
+// be written and why. `pkm-sync reprocess <file>` reads these lines directly.
+type DeadLetterEntry struct {
+	FailedAt time.Time       `json:"failed_at"`
+	Sink     string          `json:"sink"`
+	Error    string          `json:"error"`
+	Item     json.RawMessage `json:"item"`
+}
+
+// DeadLetter appends items that a sink failed to write to a JSONL file, so a
+// write error never silently drops synced data.
+type DeadLetter struct {
+	path string
+	now  func() time.Time
+}
+
+// NewDeadLetter returns a DeadLetter writing to path. The file and its
+// directory are created on the first failure.
+func NewDeadLetter(path string) *DeadLetter {
+	return &DeadLetter{path: path, now: time.Now}
+}
+
+// Path returns the dead-letter file location.
+func (d *DeadLetter) Path() string {
+	return d.path
+}
+
+// Record appends item with the error that prevented sinkName from writing it.
+func (d *DeadLetter) Record(sinkName string, item models.FullItem, writeErr error) error {
+	data, err := item.MarshalJSON()
+	if err != nil {
+		return fmt.Errorf("failed to serialize item %s: %w", item.GetID(), err)
+	}
+
+	line, err := json.Marshal(DeadLetterEntry{
+		FailedAt: d.now(),
+		Sink:     sinkName,
+		Error:    writeErr.Error(),
+		Item:     data,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to encode dead-letter entry: %w", err)
+	}
+
+	deadLetterMu.Lock()
+	defer deadLetterMu.Unlock()
+
+	if err := os.MkdirAll(filepath.Dir(d.path), 0o755); err != nil {
+		return fmt.Errorf("failed to create dead-letter directory: %w", err)
+	}
+
+	f, err := os.OpenFile(d.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("failed to open dead-letter file: %w", err)
+	}
+
+	_, writeErr = f.Write(append(line, '\n'))
+	closeErr := f.Close()
+
+	if writeErr != nil {
+		return fmt.Errorf("failed to append to dead-letter file: %w", writeErr)
+	}
+
+	return closeErr
+}
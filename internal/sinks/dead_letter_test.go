@@ -0,0 +1,82 @@
+package sinks
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"pkm-sync/pkg/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWrite_DeadLettersRejectedItem(t *testing.T) {
+	sink, dir := newTestFileSink(t)
+	dlPath := filepath.Join(t.TempDir(), "state", "dead-letter.jsonl")
+	sink.SetDeadLetter(NewDeadLetter(dlPath))
+
+	good1 := makeTestItem("OK-1", "First", "first body")
+	blocked := makeTestItem("BAD-1", "Blocked", "blocked body")
+	good2 := makeTestItem("OK-2", "Second", "second body")
+
+	// A directory where the note should go makes the write fail for that item only.
+	subdir, filename, _, err := sink.renderItem(blocked)
+	require.NoError(t, err)
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, subdir, filename), 0o755))
+
+	err = sink.Write(context.Background(), []models.FullItem{good1, blocked, good2})
+	require.NoError(t, err)
+	assert.Equal(t, 1, sink.LastWriteFailures())
+
+	for _, item := range []models.FullItem{good1, good2} {
+		subdir, filename, _, err := sink.renderItem(item)
+		require.NoError(t, err)
+		assert.FileExists(t, filepath.Join(dir, subdir, filename))
+	}
+
+	data, err := os.ReadFile(dlPath)
+	require.NoError(t, err)
+
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	require.Len(t, lines, 1)
+
+	var entry DeadLetterEntry
+	require.NoError(t, json.Unmarshal([]byte(lines[0]), &entry))
+	assert.Equal(t, "obsidian", entry.Sink)
+	assert.NotEmpty(t, entry.Error)
+	assert.False(t, entry.FailedAt.IsZero())
+
+	var item models.BasicItem
+	require.NoError(t, item.UnmarshalJSON(entry.Item))
+	assert.Equal(t, "BAD-1", item.GetID())
+	assert.Equal(t, "blocked body", item.GetContent())
+}
+
+func TestWrite_WithoutDeadLetterFailsFast(t *testing.T) {
+	sink, dir := newTestFileSink(t)
+	blocked := makeTestItem("BAD-1", "Blocked", "blocked body")
+
+	subdir, filename, _, err := sink.renderItem(blocked)
+	require.NoError(t, err)
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, subdir, filename), 0o755))
+
+	err = sink.Write(context.Background(), []models.FullItem{blocked})
+	assert.Error(t, err)
+}
+
+func TestDeadLetter_RecordAppends(t *testing.T) {
+	dl := NewDeadLetter(filepath.Join(t.TempDir(), "dead-letter.jsonl"))
+
+	require.NoError(t, dl.Record("logseq", makeTestItem("A", "A", "a"), errors.New("boom")))
+	require.NoError(t, dl.Record("logseq", makeTestItem("B", "B", "b"), errors.New("bang")))
+
+	data, err := os.ReadFile(dl.Path())
+	require.NoError(t, err)
+	assert.Equal(t, 2, strings.Count(string(data), "\n"))
+	assert.Contains(t, string(data), `"error":"bang"`)
+}
@@ -0,0 +1,497 @@
+package sinks
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"pkm-sync/pkg/interfaces"
+	"pkm-sync/pkg/models"
+)
+
+const (
+	digestGroupSource = "source"
+	digestGroupType   = "type"
+	digestGroupNone   = "none"
+
+	digestSectionStats = "stats"
+	digestSectionItems = "items"
+
+	defaultDigestFolder     = "Reviews"
+	defaultDigestTopSenders = 5
+
+	// digestStateDir holds the per-day entry lists. It is a dot-directory so
+	// vault scans (e.g. `reprocess`) skip it.
+	digestStateDir = ".digest"
+)
+
+// DigestEntry is one item listed in a day's review note.
+type DigestEntry struct {
+	ID     string `json:"id"`
+	Title  string `json:"title"`
+	Source string `json:"source"`
+	Type   string `json:"type"`
+	Sender string `json:"sender,omitempty"`
+	// Link is the item's note relative to the output directory, without ".md".
+	Link string `json:"link,omitempty"`
+	URL  string `json:"url,omitempty"`
+}
+
+// DigestSink maintains one "inbox review" note per day listing every item
+// synced that day, grouped and with checkboxes for working through them.
+// Entries accumulate across runs on the same day, and ticked checkboxes
+// survive re-rendering. It is safe for concurrent use, so one DigestSink can
+// be shared by all source groups of a sync run.
+type DigestSink struct {
+	mu        sync.Mutex
+	target    string
+	outputDir string
+	cfg       models.DigestConfig
+	now       func() time.Time
+}
+
+// NewDigestSink returns a DigestSink writing review notes for target
+// ("obsidian" or "logseq") under outputDir/cfg.Folder.
+func NewDigestSink(target, outputDir string, cfg models.DigestConfig) (*DigestSink, error) {
+	if cfg.Folder == "" {
+		cfg.Folder = defaultDigestFolder
+	}
+
+	switch cfg.GroupBy {
+	case "":
+		cfg.GroupBy = digestGroupSource
+	case digestGroupSource, digestGroupType, digestGroupNone:
+	default:
+		return nil, fmt.Errorf("invalid digest group_by %q (want source, type or none)", cfg.GroupBy)
+	}
+
+	if len(cfg.Sections) == 0 {
+		cfg.Sections = []string{digestSectionStats, digestSectionItems}
+	}
+
+	for _, section := range cfg.Sections {
+		if section != digestSectionStats && section != digestSectionItems {
+			return nil, fmt.Errorf("invalid digest section %q (want stats or items)", section)
+		}
+	}
+
+	if cfg.TopSenders == 0 {
+		cfg.TopSenders = defaultDigestTopSenders
+	}
+
+	return &DigestSink{target: target, outputDir: outputDir, cfg: cfg, now: time.Now}, nil
+}
+
+// Name implements interfaces.Sink.
+func (d *DigestSink) Name() string {
+	return "digest"
+}
+
+// Write implements interfaces.Sink. Items are listed without links; use For
+// to link entries to the notes a FileSink writes.
+func (d *DigestSink) Write(_ context.Context, items []models.FullItem) error {
+	return d.Record(items, nil)
+}
+
+// For returns a sink that records items with links to the notes files writes
+// for them. Add it after files in the same sink list.
+func (d *DigestSink) For(files *FileSink) interfaces.Sink {
+	return &linkedDigestSink{digest: d, files: files}
+}
+
+// NotePath returns the review note for the given day.
+func (d *DigestSink) NotePath(day time.Time) string {
+	return filepath.Join(d.outputDir, d.cfg.Folder, day.Format("2006-01-02")+" Inbox Review.md")
+}
+
+// Record adds items to today's review note. When files is non-nil, entries
+// link to the note files writes for each item.
+func (d *DigestSink) Record(items []models.FullItem, files *FileSink) error {
+	if len(items) == 0 {
+		return nil
+	}
+
+	day := d.now()
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	entries, err := d.loadEntries(day)
+	if err != nil {
+		return err
+	}
+
+	index := make(map[string]int, len(entries))
+	for i, e := range entries {
+		index[e.ID] = i
+	}
+
+	for _, item := range items {
+		entry := d.newEntry(item, files)
+
+		if i, ok := index[entry.ID]; ok {
+			entries[i] = entry
+		} else {
+			index[entry.ID] = len(entries)
+			entries = append(entries, entry)
+		}
+	}
+
+	if err := d.saveEntries(day, entries); err != nil {
+		return err
+	}
+
+	notePath := d.NotePath(day)
+
+	checked, err := d.checkedLines(notePath)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(notePath), 0755); err != nil {
+		return err
+	}
+
+	return writeFileAtomic(notePath, []byte(d.render(day, entries, checked)), 0644)
+}
+
+func (d *DigestSink) newEntry(item models.FullItem, files *FileSink) DigestEntry {
+	entry := DigestEntry{
+		ID:     item.GetID(),
+		Title:  item.GetTitle(),
+		Source: item.GetSourceType(),
+		Type:   item.GetItemType(),
+		Sender: digestSender(item.GetMetadata()),
+	}
+
+	if entry.Title == "" {
+		entry.Title = entry.ID
+	}
+
+	if links := item.GetLinks(); len(links) > 0 {
+		entry.URL = links[0].URL
+	}
+
+	if files != nil {
+		if path, err := files.PathFor(item); err == nil {
+			entry.Link = d.linkTarget(path)
+		}
+	}
+
+	return entry
+}
+
+// linkTarget turns a note path into a wikilink target: the vault-relative path
+// for Obsidian, the page name for Logseq.
+func (d *DigestSink) linkTarget(path string) string {
+	name := strings.TrimSuffix(path, ".md")
+
+	if d.target == "logseq" {
+		return filepath.Base(name)
+	}
+
+	rel, err := filepath.Rel(d.outputDir, name)
+	if err != nil || strings.HasPrefix(rel, "..") {
+		return filepath.Base(name)
+	}
+
+	return filepath.ToSlash(rel)
+}
+
+func (d *DigestSink) statePath(day time.Time) string {
+	return filepath.Join(d.outputDir, d.cfg.Folder, digestStateDir, day.Format("2006-01-02")+".json")
+}
+
+func (d *DigestSink) loadEntries(day time.Time) ([]DigestEntry, error) {
+	data, err := os.ReadFile(d.statePath(day))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("reading digest state: %w", err)
+	}
+
+	var entries []DigestEntry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, fmt.Errorf("parsing digest state: %w", err)
+	}
+
+	return entries, nil
+}
+
+func (d *DigestSink) saveEntries(day time.Time, entries []DigestEntry) error {
+	path := d.statePath(day)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encoding digest state: %w", err)
+	}
+
+	return writeFileAtomic(path, data, 0644)
+}
+
+// checkboxes returns the open and done markers for the target's task syntax.
+func (d *DigestSink) checkboxes() (string, string) {
+	if d.target == "logseq" {
+		return "- TODO ", "- DONE "
+	}
+
+	return "- [ ] ", "- [x] "
+}
+
+// checkedLines returns the text of entries already ticked in an existing note,
+// keyed without the checkbox so they stay ticked after re-rendering.
+func (d *DigestSink) checkedLines(notePath string) (map[string]bool, error) {
+	data, err := os.ReadFile(notePath)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("reading digest note: %w", err)
+	}
+
+	_, done := d.checkboxes()
+	checked := make(map[string]bool)
+
+	for _, line := range strings.Split(string(data), "\n") {
+		if rest, ok := strings.CutPrefix(line, done); ok {
+			checked[rest] = true
+		} else if rest, ok := strings.CutPrefix(line, "- [X] "); ok && d.target != "logseq" {
+			checked[rest] = true
+		}
+	}
+
+	return checked, nil
+}
+
+func (d *DigestSink) render(day time.Time, entries []DigestEntry, checked map[string]bool) string {
+	var sb strings.Builder
+
+	date := day.Format("2006-01-02")
+
+	if d.target == "logseq" {
+		fmt.Fprintf(&sb, "type:: inbox_review\ndate:: %s\ntotal:: %d\n\n", date, len(entries))
+	} else {
+		fmt.Fprintf(&sb, "---\ntype: inbox_review\ndate: %s\ntotal: %d\n---\n\n", date, len(entries))
+	}
+
+	fmt.Fprintf(&sb, "# Inbox Review %s\n", date)
+
+	groups := d.groupEntries(entries)
+
+	for _, section := range d.cfg.Sections {
+		switch section {
+		case digestSectionStats:
+			d.renderStats(&sb, entries, groups)
+		case digestSectionItems:
+			d.renderItems(&sb, groups, checked)
+		}
+	}
+
+	return sb.String()
+}
+
+type digestGroup struct {
+	name    string
+	entries []DigestEntry
+}
+
+// groupEntries groups entries by the configured key, largest group first.
+func (d *DigestSink) groupEntries(entries []DigestEntry) []digestGroup {
+	if d.cfg.GroupBy == digestGroupNone {
+		return []digestGroup{{name: "Items", entries: entries}}
+	}
+
+	var groups []digestGroup
+
+	index := make(map[string]int)
+
+	for _, e := range entries {
+		key := e.Source
+		if d.cfg.GroupBy == digestGroupType {
+			key = e.Type
+		}
+
+		if key == "" {
+			key = "other"
+		}
+
+		i, ok := index[key]
+		if !ok {
+			i = len(groups)
+			index[key] = i
+			groups = append(groups, digestGroup{name: key})
+		}
+
+		groups[i].entries = append(groups[i].entries, e)
+	}
+
+	sort.SliceStable(groups, func(i, j int) bool {
+		if len(groups[i].entries) != len(groups[j].entries) {
+			return len(groups[i].entries) > len(groups[j].entries)
+		}
+
+		return groups[i].name < groups[j].name
+	})
+
+	return groups
+}
+
+func (d *DigestSink) renderStats(sb *strings.Builder, entries []DigestEntry, groups []digestGroup) {
+	sb.WriteString("\n## Stats\n\n")
+	fmt.Fprintf(sb, "- Total: %d\n", len(entries))
+
+	if d.cfg.GroupBy != digestGroupNone {
+		for _, g := range groups {
+			fmt.Fprintf(sb, "- %s: %d\n", g.name, len(g.entries))
+		}
+	}
+
+	senders := topSenders(entries, d.cfg.TopSenders)
+	if len(senders) == 0 {
+		return
+	}
+
+	sb.WriteString("\n### Top senders\n\n")
+
+	for _, s := range senders {
+		fmt.Fprintf(sb, "- %s (%d)\n", s.name, s.count)
+	}
+}
+
+func (d *DigestSink) renderItems(sb *strings.Builder, groups []digestGroup, checked map[string]bool) {
+	open, done := d.checkboxes()
+
+	for _, g := range groups {
+		if len(g.entries) == 0 {
+			continue
+		}
+
+		fmt.Fprintf(sb, "\n## %s (%d)\n\n", g.name, len(g.entries))
+
+		for _, e := range g.entries {
+			text := d.entryText(e)
+
+			marker := open
+			if checked[text] {
+				marker = done
+			}
+
+			sb.WriteString(marker + text + "\n")
+		}
+	}
+}
+
+// entryText renders an entry after its checkbox: a link to the item's note (or
+// its source URL when it has no note) followed by the sender.
+func (d *DigestSink) entryText(e DigestEntry) string {
+	title := strings.NewReplacer("[", "(", "]", ")", "|", "-", "\n", " ").Replace(e.Title)
+
+	var text string
+
+	switch {
+	case e.Link != "" && d.target == "logseq":
+		text = "[[" + e.Link + "]]"
+	case e.Link != "":
+		text = "[[" + e.Link + "|" + title + "]]"
+	case e.URL != "":
+		text = "[" + title + "](" + e.URL + ")"
+	default:
+		text = title
+	}
+
+	if e.Sender != "" {
+		text += " · " + e.Sender
+	}
+
+	return text
+}
+
+type senderCount struct {
+	name  string
+	count int
+}
+
+// topSenders returns the n most frequent senders; n < 0 disables the list.
+func topSenders(entries []DigestEntry, n int) []senderCount {
+	if n < 0 {
+		return nil
+	}
+
+	counts := make(map[string]int)
+
+	for _, e := range entries {
+		if e.Sender != "" {
+			counts[e.Sender]++
+		}
+	}
+
+	senders := make([]senderCount, 0, len(counts))
+	for name, count := range counts {
+		senders = append(senders, senderCount{name: name, count: count})
+	}
+
+	slices.SortFunc(senders, func(a, b senderCount) int {
+		if a.count != b.count {
+			return b.count - a.count
+		}
+
+		return strings.Compare(a.name, b.name)
+	})
+
+	return senders[:min(n, len(senders))]
+}
+
+// digestSender reads who an item came from: the email sender, Slack author or
+// Jira reporter.
+func digestSender(metadata map[string]interface{}) string {
+	for _, key := range []string{"from", "author", "reporter"} {
+		switch v := metadata[key].(type) {
+		case string:
+			if v != "" {
+				return v
+			}
+		case interface{ Address() string }:
+			if addr := v.Address(); addr != "" {
+				return addr
+			}
+		case map[string]interface{}:
+			if email, ok := v["email"].(string); ok && email != "" {
+				return email
+			}
+		}
+	}
+
+	return ""
+}
+
+type linkedDigestSink struct {
+	digest *DigestSink
+	files  *FileSink
+}
+
+func (l *linkedDigestSink) Name() string {
+	return l.digest.Name()
+}
+
+func (l *linkedDigestSink) Write(_ context.Context, items []models.FullItem) error {
+	return l.digest.Record(items, l.files)
+}
+
+var (
+	_ interfaces.Sink = (*DigestSink)(nil)
+	_ interfaces.Sink = (*linkedDigestSink)(nil)
+)
@@ -0,0 +1,151 @@
+package sinks
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"pkm-sync/pkg/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var digestDay = time.Date(2026, 3, 3, 18, 0, 0, 0, time.UTC)
+
+func newTestDigest(t *testing.T, target, dir string, cfg models.DigestConfig) *DigestSink {
+	t.Helper()
+
+	d, err := NewDigestSink(target, dir, cfg)
+	require.NoError(t, err)
+
+	d.now = func() time.Time { return digestDay }
+
+	return d
+}
+
+func makeEmailItem(id, title, from string) models.FullItem {
+	item := makeTestItem(id, title, "body")
+	item.SetSourceType("gmail")
+	item.SetItemType("email")
+	item.SetMetadata(map[string]interface{}{"from": from})
+
+	return item
+}
+
+func readDigest(t *testing.T, d *DigestSink) string {
+	t.Helper()
+
+	data, err := os.ReadFile(d.NotePath(digestDay))
+	require.NoError(t, err)
+
+	return string(data)
+}
+
+func TestDigest_LinksToItemNotesAndCounts(t *testing.T) {
+	files, dir := newTestFileSink(t)
+	d := newTestDigest(t, "obsidian", dir, models.DigestConfig{})
+
+	items := []models.FullItem{
+		makeTestItem("PROJ-1", "Fix login", "a"),
+		makeTestItem("PROJ-2", "Add search", "b"),
+		makeEmailItem("m1", "Launch plan", "alice@example.com"),
+		makeEmailItem("m2", "Re: Launch plan", "alice@example.com"),
+		makeEmailItem("m3", "Lunch?", "bob@example.com"),
+	}
+
+	require.NoError(t, files.Write(context.Background(), items))
+	require.NoError(t, d.For(files).Write(context.Background(), items))
+
+	note := readDigest(t, d)
+
+	assert.Contains(t, note, "date: 2026-03-03\ntotal: 5\n")
+	assert.Contains(t, note, "- Total: 5\n- gmail: 3\n- jira: 2\n")
+	assert.Contains(t, note, "### Top senders\n\n- alice@example.com (2)\n- bob@example.com (1)\n")
+	assert.Contains(t, note, "## gmail (3)\n")
+	assert.Contains(t, note, "## jira (2)\n")
+	assert.Less(t, strings.Index(note, "## gmail"), strings.Index(note, "## jira"), "larger group first")
+
+	// Every entry links to the note the file sink wrote for it.
+	for _, item := range items {
+		path, err := files.PathFor(item)
+		require.NoError(t, err)
+		require.FileExists(t, path)
+
+		rel, err := filepath.Rel(dir, strings.TrimSuffix(path, ".md"))
+		require.NoError(t, err)
+		assert.Contains(t, note, "- [ ] [["+filepath.ToSlash(rel)+"|"+item.GetTitle()+"]]")
+	}
+
+	assert.Equal(t, 5, strings.Count(note, "- [ ] "))
+}
+
+func TestDigest_AccumulatesAndKeepsCheckedEntries(t *testing.T) {
+	dir := t.TempDir()
+	d := newTestDigest(t, "obsidian", dir, models.DigestConfig{})
+
+	first := makeEmailItem("m1", "Launch plan", "alice@example.com")
+	require.NoError(t, d.Write(context.Background(), []models.FullItem{first}))
+
+	// The user ticks the first entry before the next sync run.
+	note := readDigest(t, d)
+	require.NoError(t, os.WriteFile(d.NotePath(digestDay),
+		[]byte(strings.Replace(note, "- [ ] Launch plan", "- [x] Launch plan", 1)), 0644))
+
+	second := makeEmailItem("m2", "Budget", "carol@example.com")
+	require.NoError(t, d.Write(context.Background(), []models.FullItem{second, first}))
+
+	note = readDigest(t, d)
+	assert.Contains(t, note, "- Total: 2\n")
+	assert.Contains(t, note, "- [x] Launch plan · alice@example.com\n")
+	assert.Contains(t, note, "- [ ] Budget · carol@example.com\n")
+	assert.Equal(t, 1, strings.Count(note, "Launch plan"), "re-synced item listed once")
+}
+
+func TestDigest_GroupingAndSections(t *testing.T) {
+	dir := t.TempDir()
+	d := newTestDigest(t, "obsidian", dir, models.DigestConfig{
+		GroupBy:    "type",
+		Sections:   []string{"items"},
+		TopSenders: -1,
+	})
+
+	require.NoError(t, d.Write(context.Background(), []models.FullItem{
+		makeTestItem("PROJ-1", "Fix login", "a"),
+		makeEmailItem("m1", "Launch plan", "alice@example.com"),
+	}))
+
+	note := readDigest(t, d)
+	assert.NotContains(t, note, "## Stats")
+	assert.Contains(t, note, "## email (1)\n")
+	assert.Contains(t, note, "## issue (1)\n")
+
+	_, err := NewDigestSink("obsidian", dir, models.DigestConfig{GroupBy: "sender"})
+	assert.Error(t, err)
+
+	_, err = NewDigestSink("obsidian", dir, models.DigestConfig{Sections: []string{"summary"}})
+	assert.Error(t, err)
+}
+
+func TestDigest_LogseqSyntax(t *testing.T) {
+	dir := t.TempDir()
+	files, err := NewFileSink("logseq", dir, nil)
+	require.NoError(t, err)
+
+	d := newTestDigest(t, "logseq", dir, models.DigestConfig{GroupBy: "none"})
+	item := makeTestItem("PROJ-1", "Fix login", "a")
+
+	require.NoError(t, files.Write(context.Background(), []models.FullItem{item}))
+	require.NoError(t, d.For(files).Write(context.Background(), []models.FullItem{item}))
+
+	path, err := files.PathFor(item)
+	require.NoError(t, err)
+
+	note := readDigest(t, d)
+	assert.True(t, strings.HasPrefix(note, "type:: inbox_review\n"))
+	assert.Contains(t, note, "## Items (1)\n")
+	assert.Contains(t, note, "- TODO [["+strings.TrimSuffix(filepath.Base(path), ".md")+"]]\n")
+}
@@ -16,7 +16,7 @@ import (
 )
 
 // FileSink writes items to the file system using a PKM-specific formatter
-// (Obsidian or Logseq). It implements the Sink interface.
+// (Obsidian, Logseq or Roam). It implements the Sink interface.
 type FileSink struct {
 	fmt       formatter
 	outputDir string
@@ -26,6 +26,38 @@ type FileSink struct {
 	// typeFormatters maps item type (e.g. "event") to a formatter name.
 	typeFormatters map[string]string
 	idIndex        map[string]string // id → existing file path
+	// deadLetter, when set, receives items that fail to write instead of
+	// aborting the whole batch.
+	deadLetter *DeadLetter
+	// lastFailures is how many items the last Write sent to the dead letter.
+	lastFailures int
+	// truncation, when set, shortens archived items' bodies (may be nil).
+	truncation *archiveTruncation
+	// dates is the zone and layout dates are rendered in.
+	dates dateRendering
+	// onConflict is the policy for notes edited since they were last
+	// written; empty overwrites them without fingerprinting.
+	onConflict string
+	// lastConflicts lists the edited notes the last Write left unchanged
+	// under the prompt policy.
+	lastConflicts []string
+	// affixes, when set, wraps every note body in the target's content
+	// prefix and suffix (may be nil).
+	affixes *contentAffixes
+	// labelFolders maps a Gmail source name to its label folder mappings,
+	// highest precedence first.
+	labelFolders map[string][]models.GmailFolderMapping
+	// gmailFilenames maps a Gmail source name to its filename_template.
+	gmailFilenames map[string]string
+	// subdirFormat is the sync.subdir_format layout (see SetSubdirFormat);
+	// empty or "flat" keeps the formatter's directories.
+	subdirFormat string
+	// previewLines is how many content lines Preview puts in each
+	// ContentPreview; 0 leaves it empty.
+	previewLines int
+	// attachmentFolder, when set, is the output-relative folder Write copies
+	// downloaded attachments into (see SetAttachmentFolder).
+	attachmentFolder string
 }
 
 // NewFileSink creates a FileSink for the given formatter name and output directory.
@@ -38,7 +70,16 @@ func NewFileSink(formatterName string, outputDir string, config map[string]any)
 
 	f.configure(config)
 
-	sink := &FileSink{fmt: f, outputDir: outputDir}
+	sink := &FileSink{fmt: f, outputDir: outputDir, dates: configureDateRendering(config)}
+
+	if sink.affixes, err = newContentAffixes(config, sink.dates); err != nil {
+		return nil, err
+	}
+
+	if sink.affixes != nil && f.name() == "roam" {
+		return nil, fmt.Errorf("content_prefix and content_suffix are not supported by the roam target")
+	}
+
 	sink.buildIDIndex()
 
 	return sink, nil
@@ -48,12 +89,43 @@ func NewFileSink(formatterName string, outputDir string, config map[string]any)
 // mapping to the sink.  When an item's ItemType matches a key in typeMap the
 // corresponding named formatter in reg is used for directory, filename and
 // content rendering (falling back to the PKM-specific formatter for any field
-// whose template is empty).
+// whose template is empty).  The formatters render dates in the sink's zone
+// and date layout.
 func (s *FileSink) WithFormatters(reg *formatters.Registry, typeMap map[string]string) {
+	reg.SetDateRendering(s.dates.loc, s.dates.layout)
 	s.registry = reg
 	s.typeFormatters = typeMap
 }
 
+// SetDeadLetter makes Write record items it cannot write in dl and carry on
+// with the rest of the batch. Without one, the first failure aborts Write.
+func (s *FileSink) SetDeadLetter(dl *DeadLetter) {
+	s.deadLetter = dl
+}
+
+// SetArchiveTruncation cuts note bodies longer than maxChars runes and links to
+// the item's archived copy instead. Items locator cannot find are written in
+// full. A maxChars of zero or less disables truncation.
+func (s *FileSink) SetArchiveTruncation(maxChars int, locator ArchiveLocator) {
+	s.truncation = &archiveTruncation{maxChars: maxChars, locator: locator}
+}
+
+// SetConflictPolicy protects notes the user edited since pkm-sync last wrote
+// them. Written notes carry a fingerprint of their generated content; an
+// existing note whose content no longer matches it is kept under "skip" (the
+// default for an empty policy) and kept and reported by LastWriteConflicts
+// under "prompt". "overwrite" replaces it.
+func (s *FileSink) SetConflictPolicy(policy string) error {
+	policy, err := validateConflictPolicy(policy)
+	if err != nil {
+		return err
+	}
+
+	s.onConflict = policy
+
+	return nil
+}
+
 // Name returns the name of the underlying formatter.
 func (s *FileSink) Name() string {
 	return s.fmt.name()
@@ -61,33 +133,139 @@ func (s *FileSink) Name() string {
 
 // Write exports items to the file system.
 func (s *FileSink) Write(_ context.Context, items []models.FullItem) error {
+	failed := 0
+	s.lastConflicts = nil
+
 	for _, item := range items {
-		if err := s.writeItem(item); err != nil {
+		err := s.writeItem(item)
+		if err == nil {
+			continue
+		}
+
+		if s.deadLetter == nil {
 			return fmt.Errorf("failed to write item %s: %w", item.GetID(), err)
 		}
+
+		if dlErr := s.deadLetter.Record(s.Name(), item, err); dlErr != nil {
+			return fmt.Errorf("failed to write item %s (%w) and to dead-letter it: %w", item.GetID(), err, dlErr)
+		}
+
+		slog.Warn("Failed to write item; saved to dead-letter file",
+			"id", item.GetID(), "title", item.GetTitle(), "path", s.deadLetter.Path(), "error", err)
+
+		failed++
+	}
+
+	s.lastFailures = failed
+
+	if failed > 0 {
+		fmt.Printf("Warning: %d item(s) could not be written; saved to %s (retry with 'pkm-sync reprocess %s')\n",
+			failed, s.deadLetter.Path(), s.deadLetter.Path())
+	}
+
+	if len(s.lastConflicts) > 0 {
+		fmt.Printf("Warning: %d note(s) edited since the last sync were not updated (set sync.on_conflict "+
+			"to overwrite to replace them):\n", len(s.lastConflicts))
+
+		for _, path := range s.lastConflicts {
+			fmt.Printf("  %s\n", path)
+		}
 	}
 
 	return nil
 }
 
-func (s *FileSink) writeItem(item models.FullItem) error {
-	dir, filename, content, err := s.renderItem(item)
+// LastWriteConflicts returns the edited notes the most recent Write left
+// unchanged under the prompt conflict policy.
+func (s *FileSink) LastWriteConflicts() []string {
+	return s.lastConflicts
+}
+
+// LastWriteFailures returns how many items the most recent Write saved to the
+// dead-letter file instead of writing.
+func (s *FileSink) LastWriteFailures() int {
+	return s.lastFailures
+}
+
+// PathFor returns the file an item is (or would be) written to: its existing
+// note when one with the same ID was indexed, else the rendered default path.
+func (s *FileSink) PathFor(item models.FullItem) (string, error) {
+	dir, filename, _, err := s.renderItem(item)
 	if err != nil {
-		return err
+		return "", err
 	}
 
-	defaultPath := filepath.Join(s.outputDir, dir, filename)
+	return s.itemPath(item, dir, filename, nil), nil
+}
+
+// OutputDir returns the directory the sink writes into.
+func (s *FileSink) OutputDir() string {
+	return s.outputDir
+}
 
+// itemPath returns the file an item is written to: its indexed note, else
+// dir/filename under the output directory, suffixed when that name belongs to
+// another item (see uniquePath). claimed, when not nil, records the paths
+// taken by earlier items that are not yet on disk.
+func (s *FileSink) itemPath(item models.FullItem, dir, filename string, claimed map[string]string) string {
 	// Use existing path if a file with this ID was found during indexing.
-	filePath := defaultPath
 	if existing, ok := s.idIndex[item.GetID()]; ok {
-		filePath = existing
+		return existing
 	}
 
+	return uniquePath(filepath.Join(s.outputDir, dir, filename), item.GetID(), claimed)
+}
+
+// uniquePath returns path, or the first of "name-2.ext", "name-3.ext", ...
+// not taken by another item, so two items rendering to the same name do not
+// overwrite each other. A path is taken when claimed maps it to a different
+// ID or it holds a synced note with a different ID; files without an ID, such
+// as notes the user wrote, are not considered.
+func uniquePath(path, id string, claimed map[string]string) string {
+	taken := func(candidate string) bool {
+		if owner, ok := claimed[candidate]; ok {
+			return owner != id
+		}
+
+		owner := SyncedNoteID(candidate)
+
+		return owner != "" && owner != id
+	}
+
+	ext := filepath.Ext(path)
+	base := strings.TrimSuffix(path, ext)
+	candidate := path
+
+	for n := 2; taken(candidate); n++ {
+		candidate = fmt.Sprintf("%s-%d%s", base, n, ext)
+	}
+
+	if claimed != nil {
+		claimed[candidate] = id
+	}
+
+	return candidate
+}
+
+func (s *FileSink) writeItem(item models.FullItem) error {
+	dir, filename, content, err := s.renderItem(item)
+	if err != nil {
+		return err
+	}
+
+	filePath := s.itemPath(item, dir, filename, nil)
+
 	if err := os.MkdirAll(filepath.Dir(filePath), 0755); err != nil {
 		return err
 	}
 
+	s.copyAttachments(item)
+
+	protect := s.protectsEdits()
+	if protect {
+		content = stampFingerprint(content)
+	}
+
 	// Skip writing if file content is unchanged to avoid bumping mtime.
 	ondisk, err := os.ReadFile(filePath)
 	if err == nil && string(ondisk) == content {
@@ -96,7 +274,23 @@ func (s *FileSink) writeItem(item models.FullItem) error {
 		return nil
 	}
 
-	return os.WriteFile(filePath, []byte(content), 0644)
+	if err == nil && protect && editedSinceSync(string(ondisk)) {
+		slog.Info("Keeping note edited since the last sync", "path", filePath, "on_conflict", s.onConflict)
+
+		if s.onConflict == conflictPrompt {
+			s.lastConflicts = append(s.lastConflicts, filePath)
+		}
+
+		return nil
+	}
+
+	return writeFileAtomic(filePath, []byte(content), 0644)
+}
+
+// protectsEdits reports whether the conflict policy keeps notes edited since
+// the last sync, which means written notes carry a fingerprint.
+func (s *FileSink) protectsEdits() bool {
+	return s.onConflict == conflictSkip || s.onConflict == conflictPrompt
 }
 
 // renderItem returns the (directory, filename, content) triple for an item.
@@ -104,6 +298,8 @@ func (s *FileSink) writeItem(item models.FullItem) error {
 // item's type, falling back to the built-in PKM formatter for any field whose
 // template is empty.
 func (s *FileSink) renderItem(item models.FullItem) (dir, filename, content string, err error) {
+	item = s.truncation.apply(item)
+
 	// Resolve the optional template formatter for this item type.
 	var tf *formatters.TemplateFormatter
 
@@ -128,7 +324,11 @@ func (s *FileSink) renderItem(item models.FullItem) (dir, filename, content stri
 			return "", "", "", fmt.Errorf("template formatter directory: %w", err)
 		}
 	} else {
-		dir = dateSubdirForItem(item)
+		dir = s.layoutSubdir(item, dateSubdirForItem(item, s.dates))
+
+		if folder, ok := s.labelFolder(item); ok {
+			dir = filepath.Join(folder, dir)
+		}
 	}
 
 	// --- filename ---
@@ -141,6 +341,10 @@ func (s *FileSink) renderItem(item models.FullItem) (dir, filename, content stri
 		if ext := s.fmt.fileExtension(); ext != "" && !hasExtension(filename, ext) {
 			filename += ext
 		}
+	} else if name, ok := s.gmailFilename(item); ok {
+		filename = name
+	} else if namer, ok := s.fmt.(itemFilenamer); ok {
+		filename = namer.formatItemFilename(item)
 	} else {
 		filename = s.fmt.formatFilename(item.GetTitle())
 	}
@@ -155,6 +359,11 @@ func (s *FileSink) renderItem(item models.FullItem) (dir, filename, content stri
 		content = s.fmt.formatContent(item)
 	}
 
+	content, err = s.affixes.apply(item, content)
+	if err != nil {
+		return "", "", "", err
+	}
+
 	return dir, filename, content, nil
 }
 
@@ -229,10 +438,36 @@ func extractFrontmatterID(path string) string {
 	return ""
 }
 
+// SyncedNoteID returns the item ID of a note pkm-sync wrote: the Obsidian
+// frontmatter "id:" or the Logseq "id::" property on its first line. It
+// returns "" for any other file, such as notes the user wrote.
+func SyncedNoteID(path string) string {
+	if id := extractFrontmatterID(path); id != "" {
+		return id
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return ""
+	}
+
+	defer func() { _ = f.Close() }()
+
+	scanner := bufio.NewScanner(f)
+	if scanner.Scan() {
+		if id, ok := strings.CutPrefix(scanner.Text(), "- id:: "); ok {
+			return strings.TrimSpace(id)
+		}
+	}
+
+	return ""
+}
+
 // Preview generates a description of what files would be created/modified
 // without actually writing them.
 func (s *FileSink) Preview(items []models.FullItem) ([]*interfaces.FilePreview, error) {
 	previews := make([]*interfaces.FilePreview, 0, len(items))
+	claimed := make(map[string]string, len(items))
 
 	for _, item := range items {
 		dir, filename, content, err := s.renderItem(item)
@@ -240,19 +475,31 @@ func (s *FileSink) Preview(items []models.FullItem) ([]*interfaces.FilePreview,
 			return nil, fmt.Errorf("failed to render item %s: %w", item.GetID(), err)
 		}
 
-		filePath := filepath.Join(s.outputDir, dir, filename)
+		filePath := s.itemPath(item, dir, filename, claimed)
+
+		// Compare what Write would put on disk, fingerprint included.
+		protect := s.protectsEdits()
+		if protect {
+			content = stampFingerprint(content)
+		}
 
 		action, existingContent, err := logseqDetermineFileAction(filePath, content)
 		if err != nil {
 			return nil, fmt.Errorf("could not determine action for %s: %w", filePath, err)
 		}
 
-		conflict := action == "update"
+		// A note edited since the last sync is a conflict; Write keeps it
+		// unless the policy overwrites edits.
+		conflict := action == "update" && editedSinceSync(existingContent)
+		if conflict && protect {
+			action = "skip"
+		}
 
 		previews = append(previews, &interfaces.FilePreview{
 			FilePath:        filePath,
 			Action:          action,
 			Content:         content,
+			ContentPreview:  contentPreview(action, content, s.previewLines),
 			ExistingContent: existingContent,
 			Conflict:        conflict,
 		})
@@ -261,10 +508,16 @@ func (s *FileSink) Preview(items []models.FullItem) ([]*interfaces.FilePreview,
 	return previews, nil
 }
 
+// SetPreviewLines makes Preview fill in the first n lines of each created or
+// changed file's content; 0 turns it off.
+func (s *FileSink) SetPreviewLines(n int) {
+	s.previewLines = n
+}
+
 // dateSubdirForItem returns a YYYY/MM-Month/DD-Weekday path component when the
 // item has a parseable start_time metadata field (calendar events), and an
-// empty string for all other items.
-func dateSubdirForItem(item models.FullItem) string {
+// empty string for all other items. The date is taken in the configured zone.
+func dateSubdirForItem(item models.FullItem, dates dateRendering) string {
 	meta := item.GetMetadata()
 	if meta == nil {
 		return ""
@@ -301,6 +554,8 @@ func dateSubdirForItem(item models.FullItem) string {
 		return ""
 	}
 
+	t = dates.in(t)
+
 	return filepath.Join(
 		t.Format("2006"),
 		t.Format("01-January"),
@@ -0,0 +1,92 @@
+package sinks
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
+
+// Conflict policies for notes edited since pkm-sync last wrote them
+// (sync.on_conflict).
+const (
+	conflictSkip      = "skip"
+	conflictOverwrite = "overwrite"
+	conflictPrompt    = "prompt"
+)
+
+// fingerprintKey names the frontmatter field (Logseq: page property) holding
+// the fingerprint of the content pkm-sync last generated for a note.
+const fingerprintKey = "pkm_sync_fingerprint"
+
+// validateConflictPolicy normalizes an on_conflict value; empty means skip.
+func validateConflictPolicy(policy string) (string, error) {
+	switch policy {
+	case "":
+		return conflictSkip, nil
+	case conflictSkip, conflictOverwrite, conflictPrompt:
+		return policy, nil
+	default:
+		return "", fmt.Errorf("unknown on_conflict policy %q (must be skip, overwrite or prompt)", policy)
+	}
+}
+
+// contentFingerprint hashes note content, ignoring trailing whitespace so an
+// editor adding or dropping the final newline is not an edit.
+func contentFingerprint(content string) string {
+	sum := sha256.Sum256([]byte(strings.TrimRight(content, " \t\r\n")))
+
+	return hex.EncodeToString(sum[:8])
+}
+
+// stampFingerprint records content's fingerprint in its YAML frontmatter or
+// Logseq property block (after the leading id property, which identifies
+// pkm-sync notes). Content with neither (e.g. a custom template) is returned
+// unchanged and is never treated as edited.
+func stampFingerprint(content string) string {
+	fingerprint := contentFingerprint(content)
+
+	switch {
+	case strings.HasPrefix(content, "---\n"):
+		return "---\n" + fingerprintKey + ": " + fingerprint + "\n" + content[len("---\n"):]
+	case strings.HasPrefix(content, "- "):
+		first, rest, _ := strings.Cut(content, "\n")
+
+		return first + "\n- " + fingerprintKey + ":: " + fingerprint + "\n" + rest
+	default:
+		return content
+	}
+}
+
+// splitFingerprint returns the fingerprint stamped in a note and the note
+// without the fingerprint line. The fingerprint is empty for notes written
+// before fingerprints existed or without one.
+func splitFingerprint(note string) (fingerprint, rest string) {
+	for _, prefix := range []string{fingerprintKey + ": ", "- " + fingerprintKey + ":: "} {
+		start := strings.Index(note, "\n"+prefix)
+		if start < 0 {
+			continue
+		}
+
+		start++
+
+		end := strings.IndexByte(note[start:], '\n')
+		if end < 0 {
+			continue
+		}
+
+		line := note[start : start+end]
+
+		return strings.TrimSpace(strings.TrimPrefix(line, prefix)), note[:start] + note[start+end+1:]
+	}
+
+	return "", note
+}
+
+// editedSinceSync reports whether a note on disk no longer matches the
+// fingerprint pkm-sync stamped when it last wrote the note.
+func editedSinceSync(note string) bool {
+	fingerprint, rest := splitFingerprint(note)
+
+	return fingerprint != "" && contentFingerprint(rest) != fingerprint
+}
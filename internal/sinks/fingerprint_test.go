@@ -0,0 +1,148 @@
+package sinks
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"pkm-sync/pkg/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeProtected writes item through a fresh obsidian sink with the given
+// conflict policy and returns the sink and the note's path.
+func writeProtected(t *testing.T, dir, policy string, item models.FullItem) (*FileSink, string) {
+	t.Helper()
+
+	sink, err := NewFileSink("obsidian", dir, nil)
+	require.NoError(t, err)
+	require.NoError(t, sink.SetConflictPolicy(policy))
+	require.NoError(t, sink.Write(context.Background(), []models.FullItem{item}))
+
+	return sink, filepath.Join(dir, sink.fmt.formatFilename(item.GetTitle()))
+}
+
+func TestConflictPolicy_UneditedNoteUpdates(t *testing.T) {
+	dir := t.TempDir()
+	_, path := writeProtected(t, dir, "skip", makeTestItem("TEST-1", "Test Issue", "Original content"))
+
+	written, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Contains(t, string(written), "\npkm_sync_fingerprint: ")
+
+	_, _ = writeProtected(t, dir, "skip", makeTestItem("TEST-1", "Test Issue", "Updated content"))
+
+	updated, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Contains(t, string(updated), "Updated content")
+	assert.False(t, editedSinceSync(string(updated)))
+}
+
+func TestConflictPolicy_SkipPreservesEditedNote(t *testing.T) {
+	dir := t.TempDir()
+	_, path := writeProtected(t, dir, "skip", makeTestItem("TEST-1", "Test Issue", "Original content"))
+
+	written, err := os.ReadFile(path)
+	require.NoError(t, err)
+
+	edited := string(written) + "My own notes.\n"
+	require.NoError(t, os.WriteFile(path, []byte(edited), 0644))
+
+	sink, _ := writeProtected(t, dir, "skip", makeTestItem("TEST-1", "Test Issue", "Updated content"))
+
+	kept, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, edited, string(kept))
+	assert.Empty(t, sink.LastWriteConflicts())
+}
+
+func TestConflictPolicy_PromptFlagsEditedNote(t *testing.T) {
+	dir := t.TempDir()
+	_, path := writeProtected(t, dir, "prompt", makeTestItem("TEST-1", "Test Issue", "Original content"))
+
+	written, err := os.ReadFile(path)
+	require.NoError(t, err)
+
+	edited := strings.Replace(string(written), "Original content", "Original content, annotated", 1)
+	require.NoError(t, os.WriteFile(path, []byte(edited), 0644))
+
+	sink, _ := writeProtected(t, dir, "prompt", makeTestItem("TEST-1", "Test Issue", "Updated content"))
+
+	kept, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, edited, string(kept))
+	assert.Equal(t, []string{path}, sink.LastWriteConflicts())
+}
+
+func TestConflictPolicy_OverwriteReplacesEditedNote(t *testing.T) {
+	dir := t.TempDir()
+	_, path := writeProtected(t, dir, "overwrite", makeTestItem("TEST-1", "Test Issue", "Original content"))
+
+	require.NoError(t, os.WriteFile(path, []byte("my rewrite\n"), 0644))
+
+	_, _ = writeProtected(t, dir, "overwrite", makeTestItem("TEST-1", "Test Issue", "Updated content"))
+
+	updated, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Contains(t, string(updated), "Updated content")
+}
+
+func TestConflictPolicy_NoteWithoutFingerprintUpdates(t *testing.T) {
+	dir := t.TempDir()
+	// A note written before fingerprints existed.
+	_, path := writeProtected(t, dir, "overwrite", makeTestItem("TEST-1", "Test Issue", "Original content"))
+
+	_, _ = writeProtected(t, dir, "skip", makeTestItem("TEST-1", "Test Issue", "Updated content"))
+
+	updated, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Contains(t, string(updated), "Updated content")
+	assert.Contains(t, string(updated), "pkm_sync_fingerprint: ")
+}
+
+func TestFingerprint_LogseqPropertyRoundTrip(t *testing.T) {
+	content := "- id:: TEST-1\n- source:: jira\n\n# Test Issue\n\nBody\n"
+
+	stamped := stampFingerprint(content)
+	require.True(t, strings.HasPrefix(stamped, "- id:: TEST-1\n- pkm_sync_fingerprint:: "))
+
+	fingerprint, rest := splitFingerprint(stamped)
+	assert.Equal(t, contentFingerprint(content), fingerprint)
+	assert.Equal(t, content, rest)
+	assert.False(t, editedSinceSync(stamped))
+	assert.True(t, editedSinceSync(stamped+"- my block\n"))
+}
+
+func TestSetConflictPolicy_RejectsUnknownPolicy(t *testing.T) {
+	sink, _ := newTestFileSink(t)
+
+	require.Error(t, sink.SetConflictPolicy("merge"))
+	require.NoError(t, sink.SetConflictPolicy(""))
+	assert.Equal(t, "skip", sink.onConflict)
+}
+
+func TestConflictPolicy_PreviewMatchesWrite(t *testing.T) {
+	dir := t.TempDir()
+	item := makeTestItem("TEST-1", "Test Issue", "Original content")
+	sink, path := writeProtected(t, dir, "skip", item)
+
+	previews, err := sink.Preview([]models.FullItem{item})
+	require.NoError(t, err)
+	require.Len(t, previews, 1)
+	assert.Equal(t, "skip", previews[0].Action)
+	assert.False(t, previews[0].Conflict)
+
+	written, err := os.ReadFile(path)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(path, append(written, "My own notes.\n"...), 0644))
+
+	previews, err = sink.Preview([]models.FullItem{makeTestItem("TEST-1", "Test Issue", "Updated content")})
+	require.NoError(t, err)
+	require.Len(t, previews, 1)
+	assert.Equal(t, "skip", previews[0].Action)
+	assert.True(t, previews[0].Conflict)
+}
@@ -15,16 +15,27 @@ type formatter interface {
 	formatFilename(title string) string
 	fileExtension() string
 	formatMetadata(metadata map[string]any) string
+	// formatTags renders item tags in the target's tag syntax.
+	formatTags(tags []string) []string
 }
 
-// newFormatter creates the named formatter ("obsidian" or "logseq").
+// itemFilenamer is implemented by formatters that name notes from more than
+// the title (the Obsidian filename_template).
+type itemFilenamer interface {
+	formatItemFilename(item models.FullItem) string
+}
+
+// newFormatter creates the named formatter ("obsidian", "logseq" or "roam").
 func newFormatter(n string) (formatter, error) {
 	switch n {
 	case "obsidian":
 		return newObsidianFormatter(), nil
 	case "logseq":
 		return newLogseqFormatter(), nil
+	case "roam":
+		return newRoamFormatter(), nil
 	default:
-		return nil, fmt.Errorf("unknown formatter '%s': supported formatters are 'obsidian' and 'logseq'", n)
+		// The jsonl target writes through JSONLSink rather than a formatter.
+		return nil, fmt.Errorf("unknown target '%s': supported targets are 'obsidian', 'logseq', 'roam' and 'jsonl'", n)
 	}
 }
@@ -0,0 +1,107 @@
+package sinks
+
+import (
+	"net/mail"
+	"strings"
+
+	gmail "pkm-sync/internal/sources/google/gmail"
+	"pkm-sync/pkg/models"
+)
+
+// maxFilenameSubjectRunes bounds {{subject}} in Gmail filename templates, so a
+// long subject does not crowd the date and sender out of the name.
+const maxFilenameSubjectRunes = 50
+
+// SetGmailFilenameTemplate names the notes of the Gmail source sourceName
+// (items whose source_name metadata matches) from template, whatever the
+// target. Tokens are {{date}} (in the sink's zone and date_format), {{from}}
+// (the sender's name, else address), {{subject}} (at most 50 characters) and
+// {{id}}; an empty token is replaced by the item ID. The result is sanitized
+// the way the target sanitizes titles. An empty template turns it off.
+func (s *FileSink) SetGmailFilenameTemplate(sourceName, template string) {
+	if template == "" {
+		delete(s.gmailFilenames, sourceName)
+
+		return
+	}
+
+	if s.gmailFilenames == nil {
+		s.gmailFilenames = make(map[string]string)
+	}
+
+	s.gmailFilenames[sourceName] = template
+}
+
+// gmailFilename returns the templated filename for an item of a Gmail source
+// with a filename template, and false for any other item.
+func (s *FileSink) gmailFilename(item models.FullItem) (string, bool) {
+	if len(s.gmailFilenames) == 0 {
+		return "", false
+	}
+
+	sourceName, _ := item.GetMetadata()["source_name"].(string)
+
+	template, ok := s.gmailFilenames[sourceName]
+	if !ok {
+		return "", false
+	}
+
+	date := ""
+	if created := item.GetCreatedAt(); !created.IsZero() {
+		date = s.dates.in(created).Format(s.dates.layout)
+	}
+
+	orID := func(value string) string {
+		if value = strings.TrimSpace(value); value != "" {
+			return value
+		}
+
+		return item.GetID()
+	}
+
+	name := strings.NewReplacer(
+		"{{date}}", orID(date),
+		"{{from}}", orID(filenameSender(item.GetMetadata())),
+		"{{subject}}", orID(truncateRunes(item.GetTitle(), maxFilenameSubjectRunes)),
+		"{{id}}", item.GetID(),
+	).Replace(template)
+
+	return s.fmt.formatFilename(name), true
+}
+
+// filenameSender returns the sender's display name, or their address when the
+// message has no name, from the typed, string or JSON map form of "from".
+func filenameSender(metadata map[string]interface{}) string {
+	var name, email string
+
+	switch v := metadata["from"].(type) {
+	case gmail.EmailRecipient:
+		name, email = v.Name, v.Email
+	case map[string]interface{}:
+		name, _ = v["name"].(string)
+		email, _ = v["email"].(string)
+	case string:
+		addr, err := mail.ParseAddress(v)
+		if err != nil {
+			return v
+		}
+
+		name, email = addr.Name, addr.Address
+	}
+
+	if name != "" {
+		return name
+	}
+
+	return email
+}
+
+// truncateRunes cuts s to at most n runes.
+func truncateRunes(s string, n int) string {
+	runes := []rune(s)
+	if len(runes) <= n {
+		return s
+	}
+
+	return strings.TrimSpace(string(runes[:n]))
+}
@@ -0,0 +1,95 @@
+package sinks
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	gmail "pkm-sync/internal/sources/google/gmail"
+	"pkm-sync/pkg/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func gmailMessage(id, subject string, from any) models.FullItem {
+	item := makeTestItem(id, subject, "Body")
+	item.SetSourceType("gmail")
+	item.SetCreatedAt(time.Date(2024, 3, 5, 9, 0, 0, 0, time.UTC))
+	item.SetMetadata(map[string]interface{}{"source_name": "gmail_work", "from": from})
+
+	return item
+}
+
+func TestGmailFilenameTemplate(t *testing.T) {
+	tests := []struct {
+		name   string
+		target string
+		from   any
+		want   string
+	}{
+		{"obsidian recipient", "obsidian", gmail.EmailRecipient{Name: "Jane Doe", Email: "jane@example.com"},
+			"2024-03-05-Jane-Doe-Quarterly-report.md"},
+		{"logseq recipient", "logseq", gmail.EmailRecipient{Name: "Jane Doe", Email: "jane@example.com"},
+			"2024-03-05-Jane Doe-Quarterly report.md"},
+		{"string address", "obsidian", "Jane Doe <jane@example.com>", "2024-03-05-Jane-Doe-Quarterly-report.md"},
+		{"json map without name", "logseq", map[string]interface{}{"email": "jane@example.com"},
+			"2024-03-05-jane@example.com-Quarterly report.md"},
+		{"missing sender", "logseq", nil, "2024-03-05-msg-1-Quarterly report.md"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			sink, err := NewFileSink(tt.target, dir, map[string]any{"filename_template": "{{title}}"})
+			require.NoError(t, err)
+			sink.SetGmailFilenameTemplate("gmail_work", "{{date}}-{{from}}-{{subject}}")
+
+			path, err := sink.PathFor(gmailMessage("msg-1", "Quarterly report", tt.from))
+			require.NoError(t, err)
+			assert.Equal(t, filepath.Join(dir, tt.want), path)
+		})
+	}
+}
+
+func TestGmailFilenameTemplate_LongSubjectTruncated(t *testing.T) {
+	sink, dir := newTestFileSink(t)
+	sink.SetGmailFilenameTemplate("gmail_work", "{{subject}}")
+
+	path, err := sink.PathFor(gmailMessage("msg-1", strings.Repeat("word ", 30), "a@example.com"))
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(dir, strings.TrimSuffix(strings.Repeat("word-", 10), "-")+".md"), path)
+}
+
+func TestGmailFilenameTemplate_OtherSourcesUnaffected(t *testing.T) {
+	sink, dir := newTestFileSink(t)
+	sink.SetGmailFilenameTemplate("gmail_work", "{{date}}-{{subject}}")
+
+	item := gmailMessage("msg-1", "Hello", "a@example.com")
+	item.SetMetadata(map[string]interface{}{"source_name": "gmail_personal"})
+
+	path, err := sink.PathFor(item)
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(dir, "Hello.md"), path)
+
+	sink.SetGmailFilenameTemplate("gmail_work", "")
+
+	path, err = sink.PathFor(gmailMessage("msg-2", "Hello", "a@example.com"))
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(dir, "Hello.md"), path, "an empty template turns naming off")
+}
+
+func TestGmailFilenameTemplate_PreviewSuffixesCollisions(t *testing.T) {
+	sink, dir := newTestFileSink(t)
+	sink.SetGmailFilenameTemplate("gmail_work", "{{date}}-{{from}}")
+
+	previews, err := sink.Preview([]models.FullItem{
+		gmailMessage("msg-1", "First", "a@example.com"),
+		gmailMessage("msg-2", "Second", "a@example.com"),
+	})
+	require.NoError(t, err)
+	require.Len(t, previews, 2)
+	assert.Equal(t, filepath.Join(dir, "2024-03-05-a-at-examplecom.md"), previews[0].FilePath)
+	assert.Equal(t, filepath.Join(dir, "2024-03-05-a-at-examplecom-2.md"), previews[1].FilePath)
+}
@@ -0,0 +1,586 @@
+package sinks
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"pkm-sync/pkg/interfaces"
+	"pkm-sync/pkg/models"
+)
+
+const (
+	defaultICSFile         = "calendar.ics"
+	defaultICSCalendarName = "pkm-sync"
+	icsProdID              = "-//pkm-sync//Calendar Export//EN"
+
+	// icsMaxLineOctets is the RFC 5545 content line limit; longer lines are folded.
+	icsMaxLineOctets = 75
+
+	icsDateTimeFormat = "20060102T150405"
+	icsDateFormat     = "20060102"
+
+	// defaultEventDuration is used for timed events without a usable end.
+	defaultEventDuration = time.Hour
+)
+
+// icsEvent is one VEVENT, held as unfolded content lines without the
+// BEGIN/END markers so events read back from the file round-trip unchanged.
+type icsEvent struct {
+	uid   string
+	lines []string
+}
+
+// ICSSink maintains a combined iCalendar (.ics) file of the calendar events
+// it is given, for importing the synced (filtered and tagged) subset of a
+// calendar into another app. Events accumulate across runs: an event whose
+// UID is already in the file replaces the old copy. Timed events keep the
+// time zone they were scheduled in, with a VTIMEZONE generated for each zone.
+type ICSSink struct {
+	mu           sync.Mutex
+	path         string
+	calendarName string
+	now          func() time.Time
+}
+
+// NewICSSink returns an ICSSink writing cfg.Path, resolved against outputDir
+// when relative (default: outputDir/calendar.ics).
+func NewICSSink(outputDir string, cfg models.ICSExportConfig) *ICSSink {
+	path := cfg.Path
+	if path == "" {
+		path = defaultICSFile
+	}
+
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(outputDir, path)
+	}
+
+	name := cfg.CalendarName
+	if name == "" {
+		name = defaultICSCalendarName
+	}
+
+	return &ICSSink{path: path, calendarName: name, now: time.Now}
+}
+
+// Name implements interfaces.Sink.
+func (s *ICSSink) Name() string {
+	return "ics"
+}
+
+// Path returns the .ics file the sink maintains.
+func (s *ICSSink) Path() string {
+	return s.path
+}
+
+// Write implements interfaces.Sink. Items that are not calendar events are
+// ignored.
+func (s *ICSSink) Write(_ context.Context, items []models.FullItem) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stamp := s.now().UTC()
+
+	var events []icsEvent
+
+	for _, item := range items {
+		if event, ok := icsEventFromItem(item, stamp); ok {
+			events = append(events, event)
+		}
+	}
+
+	if len(events) == 0 {
+		return nil
+	}
+
+	existing, err := readICSEvents(s.path)
+	if err != nil {
+		return err
+	}
+
+	data := renderICS(s.calendarName, mergeICSEvents(existing, events))
+
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o755); err != nil {
+		return fmt.Errorf("failed to create ics directory: %w", err)
+	}
+
+	if err := writeFileAtomic(s.path, []byte(data), 0o644); err != nil {
+		return fmt.Errorf("failed to write ics file: %w", err)
+	}
+
+	return nil
+}
+
+// icsEventFromItem builds a VEVENT from the metadata the calendar converter
+// records (models.FromCalendarEvent). Metadata decoded from JSON holds
+// strings and maps instead of typed values; both forms are accepted.
+func icsEventFromItem(item models.FullItem, stamp time.Time) (icsEvent, bool) {
+	if item.GetItemType() != "event" {
+		return icsEvent{}, false
+	}
+
+	meta := item.GetMetadata()
+
+	start, ok := metadataTime(meta, "start_time")
+	if !ok {
+		start = item.GetCreatedAt()
+	}
+
+	if start.IsZero() {
+		return icsEvent{}, false
+	}
+
+	end, _ := metadataTime(meta, "end_time")
+	allDay, _ := meta["all_day"].(bool)
+	zone, _ := meta["time_zone"].(string)
+
+	if zone != "" {
+		if loc, err := time.LoadLocation(zone); err == nil {
+			start, end = start.In(loc), end.In(loc)
+		} else {
+			zone = ""
+		}
+	}
+
+	switch {
+	case allDay && !end.After(start):
+		end = start.AddDate(0, 0, 1)
+	case !end.After(start):
+		end = start.Add(defaultEventDuration)
+	}
+
+	uid := icsEscape(item.GetID())
+	lines := []string{
+		"UID:" + uid,
+		"DTSTAMP:" + stamp.Format(icsDateTimeFormat) + "Z",
+		icsTimeProperty("DTSTART", start, allDay, zone),
+		icsTimeProperty("DTEND", end, allDay, zone),
+		"SUMMARY:" + icsEscape(item.GetTitle()),
+	}
+
+	if content := strings.TrimSpace(item.GetContent()); content != "" {
+		lines = append(lines, "DESCRIPTION:"+icsEscape(content))
+	}
+
+	if location, _ := meta["location"].(string); location != "" {
+		lines = append(lines, "LOCATION:"+icsEscape(location))
+	}
+
+	for _, link := range item.GetLinks() {
+		if link.Type == "meeting_url" && link.URL != "" {
+			lines = append(lines, "URL:"+link.URL)
+
+			break
+		}
+	}
+
+	if tags := item.GetTags(); len(tags) > 0 {
+		escaped := make([]string, len(tags))
+		for i, tag := range tags {
+			escaped[i] = icsEscape(tag)
+		}
+
+		lines = append(lines, "CATEGORIES:"+strings.Join(escaped, ","))
+	}
+
+	for _, attendee := range metadataAttendees(meta["attendees"]) {
+		lines = append(lines, icsAttendee(attendee))
+	}
+
+	return icsEvent{uid: uid, lines: lines}, true
+}
+
+// icsTimeProperty formats DTSTART/DTEND: a DATE for all-day events, local
+// time with TZID for events in a named zone, and UTC otherwise.
+func icsTimeProperty(name string, t time.Time, allDay bool, zone string) string {
+	switch {
+	case allDay:
+		return name + ";VALUE=DATE:" + t.Format(icsDateFormat)
+	case zone != "":
+		return name + ";TZID=" + zone + ":" + t.Format(icsDateTimeFormat)
+	default:
+		return name + ":" + t.UTC().Format(icsDateTimeFormat) + "Z"
+	}
+}
+
+func icsAttendee(a models.Attendee) string {
+	var params strings.Builder
+
+	if a.DisplayName != "" {
+		fmt.Fprintf(&params, ";CN=\"%s\"", strings.ReplaceAll(a.DisplayName, `"`, ""))
+	}
+
+	partstat := map[string]string{
+		"accepted":    "ACCEPTED",
+		"declined":    "DECLINED",
+		"tentative":   "TENTATIVE",
+		"needsAction": "NEEDS-ACTION",
+	}[a.ResponseStatus]
+	if partstat != "" {
+		params.WriteString(";PARTSTAT=" + partstat)
+	}
+
+	return "ATTENDEE" + params.String() + ":mailto:" + a.Email
+}
+
+// metadataTime reads a time stored as time.Time or, after a JSON round trip,
+// as an RFC 3339 string.
+func metadataTime(meta map[string]interface{}, key string) (time.Time, bool) {
+	switch v := meta[key].(type) {
+	case time.Time:
+		return v, !v.IsZero()
+	case string:
+		t, err := time.Parse(time.RFC3339, v)
+
+		return t, err == nil && !t.IsZero()
+	default:
+		return time.Time{}, false
+	}
+}
+
+// metadataAttendees reads attendees stored as []models.Attendee or, after a
+// JSON round trip, as a list of objects with the same field names.
+func metadataAttendees(v any) []models.Attendee {
+	switch list := v.(type) {
+	case []models.Attendee:
+		return list
+	case []any:
+		attendees := make([]models.Attendee, 0, len(list))
+
+		for _, entry := range list {
+			m, ok := entry.(map[string]any)
+			if !ok {
+				continue
+			}
+
+			email, _ := m["Email"].(string)
+			if email == "" {
+				continue
+			}
+
+			name, _ := m["DisplayName"].(string)
+			status, _ := m["ResponseStatus"].(string)
+			attendees = append(attendees, models.Attendee{Email: email, DisplayName: name, ResponseStatus: status})
+		}
+
+		return attendees
+	default:
+		return nil
+	}
+}
+
+// icsEscape escapes a TEXT value (RFC 5545 section 3.3.11).
+func icsEscape(s string) string {
+	return strings.NewReplacer(
+		`\`, `\\`,
+		";", `\;`,
+		",", `\,`,
+		"\r\n", `\n`,
+		"\n", `\n`,
+		"\r", "",
+	).Replace(s)
+}
+
+// mergeICSEvents replaces existing events that share a UID with an update
+// and appends the rest, keeping the file's order stable across runs.
+func mergeICSEvents(existing, updates []icsEvent) []icsEvent {
+	index := make(map[string]int, len(existing))
+	for i, event := range existing {
+		index[event.uid] = i
+	}
+
+	merged := existing
+
+	for _, event := range updates {
+		if i, ok := index[event.uid]; ok {
+			merged[i] = event
+
+			continue
+		}
+
+		index[event.uid] = len(merged)
+		merged = append(merged, event)
+	}
+
+	return merged
+}
+
+// readICSEvents loads the VEVENTs of an existing export. Other components
+// (VTIMEZONE) are regenerated on every write and are not kept.
+func readICSEvents(path string) ([]icsEvent, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to read ics file: %w", err)
+	}
+
+	return parseICSEvents(string(data)), nil
+}
+
+func parseICSEvents(data string) []icsEvent {
+	var (
+		events  []icsEvent
+		current *icsEvent
+	)
+
+	for _, line := range unfoldICS(data) {
+		switch {
+		case line == "BEGIN:VEVENT":
+			current = &icsEvent{}
+		case line == "END:VEVENT" && current != nil:
+			if current.uid != "" {
+				events = append(events, *current)
+			}
+
+			current = nil
+		case current != nil:
+			if uid, ok := strings.CutPrefix(line, "UID:"); ok {
+				current.uid = uid
+			}
+
+			current.lines = append(current.lines, line)
+		}
+	}
+
+	return events
+}
+
+// unfoldICS splits data into content lines, joining folded continuations.
+func unfoldICS(data string) []string {
+	var lines []string
+
+	for _, raw := range strings.Split(strings.ReplaceAll(data, "\r\n", "\n"), "\n") {
+		if (strings.HasPrefix(raw, " ") || strings.HasPrefix(raw, "\t")) && len(lines) > 0 {
+			lines[len(lines)-1] += raw[1:]
+
+			continue
+		}
+
+		if raw != "" {
+			lines = append(lines, raw)
+		}
+	}
+
+	return lines
+}
+
+// foldICS folds a content line at icsMaxLineOctets without splitting UTF-8
+// sequences; continuation lines start with a space.
+func foldICS(line string) string {
+	if len(line) <= icsMaxLineOctets {
+		return line
+	}
+
+	var sb strings.Builder
+
+	limit := icsMaxLineOctets
+
+	for len(line) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(line[cut]) {
+			cut--
+		}
+
+		sb.WriteString(line[:cut])
+		sb.WriteString("\r\n ")
+
+		line = line[cut:]
+		limit = icsMaxLineOctets - 1
+	}
+
+	sb.WriteString(line)
+
+	return sb.String()
+}
+
+func renderICS(calendarName string, events []icsEvent) string {
+	lines := []string{
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"PRODID:" + icsProdID,
+		"CALSCALE:GREGORIAN",
+		"X-WR-CALNAME:" + icsEscape(calendarName),
+	}
+
+	for _, zone := range icsZones(events) {
+		lines = append(lines, zone...)
+	}
+
+	for _, event := range events {
+		lines = append(lines, "BEGIN:VEVENT")
+		lines = append(lines, event.lines...)
+		lines = append(lines, "END:VEVENT")
+	}
+
+	lines = append(lines, "END:VCALENDAR")
+
+	var sb strings.Builder
+
+	for _, line := range lines {
+		sb.WriteString(foldICS(line))
+		sb.WriteString("\r\n")
+	}
+
+	return sb.String()
+}
+
+// icsZones returns a VTIMEZONE for every TZID the events reference, covering
+// the years their times fall in. Zones unknown to the tz database are left
+// out; importers then fall back to their own definition of the TZID.
+func icsZones(events []icsEvent) [][]string {
+	years := make(map[string][2]int)
+
+	for _, event := range events {
+		for _, line := range event.lines {
+			zone, year, ok := icsTZIDAndYear(line)
+			if !ok {
+				continue
+			}
+
+			span, seen := years[zone]
+			if !seen {
+				span = [2]int{year, year}
+			}
+
+			years[zone] = [2]int{min(span[0], year), max(span[1], year)}
+		}
+	}
+
+	zones := make([]string, 0, len(years))
+	for zone := range years {
+		zones = append(zones, zone)
+	}
+
+	sort.Strings(zones)
+
+	var blocks [][]string
+
+	for _, zone := range zones {
+		loc, err := time.LoadLocation(zone)
+		if err != nil {
+			continue
+		}
+
+		blocks = append(blocks, vtimezone(zone, loc, years[zone][0], years[zone][1]))
+	}
+
+	return blocks
+}
+
+// icsTZIDAndYear extracts the TZID and year of a DTSTART/DTEND line.
+func icsTZIDAndYear(line string) (string, int, bool) {
+	if !strings.HasPrefix(line, "DTSTART;") && !strings.HasPrefix(line, "DTEND;") {
+		return "", 0, false
+	}
+
+	params, value, ok := strings.Cut(line, ":")
+	if !ok {
+		return "", 0, false
+	}
+
+	for _, param := range strings.Split(params, ";")[1:] {
+		if zone, ok := strings.CutPrefix(param, "TZID="); ok {
+			t, err := time.Parse(icsDateTimeFormat, value)
+			if err != nil {
+				return "", 0, false
+			}
+
+			return zone, t.Year(), true
+		}
+	}
+
+	return "", 0, false
+}
+
+// vtimezone describes loc from the start of fromYear to the end of toYear:
+// the offset in effect at the start, then one observance per transition.
+func vtimezone(name string, loc *time.Location, fromYear, toYear int) []string {
+	start := time.Date(fromYear, time.January, 1, 0, 0, 0, 0, loc)
+	end := time.Date(toYear+1, time.January, 1, 0, 0, 0, 0, loc)
+
+	abbrev, offset := start.Zone()
+
+	lines := []string{"BEGIN:VTIMEZONE", "TZID:" + name}
+	lines = append(lines, icsObservance(start.IsDST(), start.UTC(), offset, offset, abbrev)...)
+
+	for day := start; day.Before(end); {
+		next := day.Add(24 * time.Hour)
+
+		if _, nextOffset := next.Zone(); nextOffset != offset {
+			transition := findTransition(day, next)
+			abbrev, _ = transition.Zone()
+
+			lines = append(lines, icsObservance(transition.IsDST(), transition, offset, nextOffset, abbrev)...)
+			offset = nextOffset
+		}
+
+		day = next
+	}
+
+	return append(lines, "END:VTIMEZONE")
+}
+
+// findTransition returns the first second in (before, after] whose UTC offset
+// differs from before's.
+func findTransition(before, after time.Time) time.Time {
+	_, offset := before.Zone()
+
+	for after.Sub(before) > time.Second {
+		mid := before.Add(after.Sub(before) / 2)
+		if _, o := mid.Zone(); o == offset {
+			before = mid
+		} else {
+			after = mid
+		}
+	}
+
+	return after.Truncate(time.Second)
+}
+
+// icsObservance renders a STANDARD or DAYLIGHT sub-component. Its DTSTART is
+// the local time of the change, expressed in the offset before it.
+func icsObservance(dst bool, at time.Time, fromOffset, toOffset int, abbrev string) []string {
+	kind := "STANDARD"
+	if dst {
+		kind = "DAYLIGHT"
+	}
+
+	local := at.UTC().Add(time.Duration(fromOffset) * time.Second)
+
+	return []string{
+		"BEGIN:" + kind,
+		"DTSTART:" + local.Format(icsDateTimeFormat),
+		"TZOFFSETFROM:" + icsOffset(fromOffset),
+		"TZOFFSETTO:" + icsOffset(toOffset),
+		"TZNAME:" + icsEscape(abbrev),
+		"END:" + kind,
+	}
+}
+
+// icsOffset formats a UTC offset in seconds as ±HHMM (±HHMMSS when needed).
+func icsOffset(seconds int) string {
+	sign := "+"
+	if seconds < 0 {
+		sign, seconds = "-", -seconds
+	}
+
+	formatted := fmt.Sprintf("%s%02d%02d", sign, seconds/3600, seconds%3600/60)
+	if s := seconds % 60; s != 0 {
+		formatted += fmt.Sprintf("%02d", s)
+	}
+
+	return formatted
+}
+
+// Ensure interface compliance.
+var _ interfaces.Sink = (*ICSSink)(nil)
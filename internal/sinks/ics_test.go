@@ -0,0 +1,229 @@
+package sinks
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"pkm-sync/pkg/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestICSSink(t *testing.T) *ICSSink {
+	t.Helper()
+
+	s := NewICSSink(t.TempDir(), models.ICSExportConfig{CalendarName: "Work"})
+	s.now = func() time.Time { return time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC) }
+
+	return s
+}
+
+func makeCalendarItem(t *testing.T, event *models.CalendarEvent) models.FullItem {
+	t.Helper()
+
+	return models.AsFullItem(models.FromCalendarEvent(event))
+}
+
+func readICS(t *testing.T, s *ICSSink) string {
+	t.Helper()
+
+	data, err := os.ReadFile(s.Path())
+	require.NoError(t, err)
+
+	return string(data)
+}
+
+// icsProperty returns the unfolded value of the first property named name
+// inside the VEVENT with the given UID, including any parameters.
+func icsProperty(t *testing.T, data, uid, name string) string {
+	t.Helper()
+
+	for _, event := range parseICSEvents(data) {
+		if event.uid != uid {
+			continue
+		}
+
+		for _, line := range event.lines {
+			if strings.HasPrefix(line, name+":") || strings.HasPrefix(line, name+";") {
+				return line[len(name):]
+			}
+		}
+	}
+
+	t.Fatalf("no %s in event %s", name, uid)
+
+	return ""
+}
+
+func TestICSSink_WellFormedOutput(t *testing.T) {
+	s := newTestICSSink(t)
+	ny, err := time.LoadLocation("America/New_York")
+	require.NoError(t, err)
+
+	start := time.Date(2026, 3, 10, 9, 30, 0, 0, ny)
+	item := makeCalendarItem(t, &models.CalendarEvent{
+		ID:          "evt-1",
+		Summary:     "Planning; Q2, roadmap",
+		Description: strings.Repeat("Long agenda line with ünïcödé text. ", 10) + "\nSecond line",
+		Start:       start,
+		End:         start.Add(time.Hour),
+		TimeZone:    "America/New_York",
+		Location:    "Room 4",
+		MeetingURL:  "https://meet.example.com/abc",
+		Attendees: []models.Attendee{
+			{Email: "alice@example.com", DisplayName: "Alice", ResponseStatus: "accepted"},
+		},
+	})
+	item.SetTags([]string{"work", "planning"})
+
+	require.NoError(t, s.Write(context.Background(), []models.FullItem{item}))
+
+	data := readICS(t, s)
+	assert.True(t, strings.HasSuffix(data, "END:VCALENDAR\r\n"))
+	assert.NotContains(t, strings.ReplaceAll(data, "\r\n", ""), "\n", "all line breaks must be CRLF")
+
+	depth := 0
+
+	for _, line := range strings.Split(strings.TrimSuffix(data, "\r\n"), "\r\n") {
+		assert.LessOrEqual(t, len(line), icsMaxLineOctets, "line too long: %q", line)
+
+		switch {
+		case strings.HasPrefix(line, "BEGIN:"):
+			depth++
+		case strings.HasPrefix(line, "END:"):
+			depth--
+		}
+
+		assert.GreaterOrEqual(t, depth, 0)
+	}
+
+	assert.Equal(t, 0, depth, "unbalanced BEGIN/END")
+	assert.Contains(t, data, "X-WR-CALNAME:Work\r\n")
+
+	assert.Equal(t, `:Planning\; Q2\, roadmap`, icsProperty(t, data, "evt-1", "SUMMARY"))
+	assert.Contains(t, icsProperty(t, data, "evt-1", "DESCRIPTION"), `ünïcödé text. \nSecond line`)
+	assert.Equal(t, ":Room 4", icsProperty(t, data, "evt-1", "LOCATION"))
+	assert.Equal(t, ":https://meet.example.com/abc", icsProperty(t, data, "evt-1", "URL"))
+	assert.Equal(t, ":work,planning", icsProperty(t, data, "evt-1", "CATEGORIES"))
+	assert.Equal(t, `;CN="Alice";PARTSTAT=ACCEPTED:mailto:alice@example.com`, icsProperty(t, data, "evt-1", "ATTENDEE"))
+}
+
+func TestICSSink_TimeZonesRoundTrip(t *testing.T) {
+	s := newTestICSSink(t)
+	ny, err := time.LoadLocation("America/New_York")
+	require.NoError(t, err)
+
+	// Stored in UTC, as it would be after a JSON round trip of the metadata.
+	start := time.Date(2026, 7, 1, 14, 0, 0, 0, ny).UTC()
+	item := makeCalendarItem(t, &models.CalendarEvent{
+		ID: "evt-tz", Summary: "Standup", Start: start, End: start.Add(15 * time.Minute), TimeZone: "America/New_York",
+	})
+
+	require.NoError(t, s.Write(context.Background(), []models.FullItem{item}))
+
+	data := readICS(t, s)
+	assert.Equal(t, ";TZID=America/New_York:20260701T140000", icsProperty(t, data, "evt-tz", "DTSTART"))
+	assert.Equal(t, ";TZID=America/New_York:20260701T141500", icsProperty(t, data, "evt-tz", "DTEND"))
+
+	// Reading DTSTART back in its zone gives the original instant.
+	parsed, err := time.ParseInLocation(icsDateTimeFormat, "20260701T140000", ny)
+	require.NoError(t, err)
+	assert.True(t, parsed.Equal(start))
+
+	assert.Contains(t, data, "BEGIN:VTIMEZONE\r\nTZID:America/New_York\r\n")
+	assert.Contains(t, data, "BEGIN:DAYLIGHT\r\nDTSTART:20260308T020000\r\nTZOFFSETFROM:-0500\r\nTZOFFSETTO:-0400\r\n")
+	assert.Contains(t, data, "BEGIN:STANDARD\r\nDTSTART:20261101T020000\r\nTZOFFSETFROM:-0400\r\nTZOFFSETTO:-0500\r\n")
+}
+
+func TestICSSink_AllDayAndUTCEvents(t *testing.T) {
+	s := newTestICSSink(t)
+
+	allDay := makeCalendarItem(t, &models.CalendarEvent{
+		ID: "evt-day", Summary: "Offsite", IsAllDay: true,
+		Start: time.Date(2026, 4, 2, 0, 0, 0, 0, time.UTC), End: time.Date(2026, 4, 4, 0, 0, 0, 0, time.UTC),
+	})
+	utc := makeCalendarItem(t, &models.CalendarEvent{
+		ID: "evt-utc", Summary: "Call",
+		Start: time.Date(2026, 4, 2, 15, 0, 0, 0, time.UTC), End: time.Date(2026, 4, 2, 15, 30, 0, 0, time.UTC),
+	})
+
+	require.NoError(t, s.Write(context.Background(), []models.FullItem{allDay, utc}))
+
+	data := readICS(t, s)
+	assert.Equal(t, ";VALUE=DATE:20260402", icsProperty(t, data, "evt-day", "DTSTART"))
+	assert.Equal(t, ";VALUE=DATE:20260404", icsProperty(t, data, "evt-day", "DTEND"))
+	assert.Equal(t, ":20260402T150000Z", icsProperty(t, data, "evt-utc", "DTSTART"))
+	assert.NotContains(t, data, "VTIMEZONE")
+}
+
+func TestICSSink_MergesByUID(t *testing.T) {
+	s := newTestICSSink(t)
+	start := time.Date(2026, 5, 5, 10, 0, 0, 0, time.UTC)
+
+	event := func(id, summary string) models.FullItem {
+		return makeCalendarItem(t, &models.CalendarEvent{ID: id, Summary: summary, Start: start, End: start.Add(time.Hour)})
+	}
+
+	first, other := event("a", "Old title"), event("b", "Other")
+	require.NoError(t, s.Write(context.Background(), []models.FullItem{first, other}))
+
+	require.NoError(t, s.Write(context.Background(), []models.FullItem{event("a", "New title")}))
+
+	data := readICS(t, s)
+	events := parseICSEvents(data)
+	require.Len(t, events, 2)
+	assert.Equal(t, "a", events[0].uid)
+	assert.Equal(t, "b", events[1].uid)
+	assert.Equal(t, ":New title", icsProperty(t, data, "a", "SUMMARY"))
+	assert.NotContains(t, data, "Old title")
+}
+
+func TestICSSink_MetadataFromJSON(t *testing.T) {
+	s := newTestICSSink(t)
+
+	event := makeCalendarItem(t, &models.CalendarEvent{
+		ID: "evt-json", Summary: "Review",
+		Start:     time.Date(2026, 6, 1, 8, 0, 0, 0, time.UTC),
+		End:       time.Date(2026, 6, 1, 9, 0, 0, 0, time.UTC),
+		TimeZone:  "Europe/Berlin",
+		Attendees: []models.Attendee{{Email: "bob@example.com", ResponseStatus: "declined"}},
+	})
+
+	// Round-trip the metadata through JSON, as items read back from disk or a
+	// JSONL export carry it.
+	raw, err := json.Marshal(event.GetMetadata())
+	require.NoError(t, err)
+
+	var meta map[string]interface{}
+	require.NoError(t, json.Unmarshal(raw, &meta))
+	event.SetMetadata(meta)
+
+	require.NoError(t, s.Write(context.Background(), []models.FullItem{event}))
+
+	data := readICS(t, s)
+	assert.Equal(t, ";TZID=Europe/Berlin:20260601T100000", icsProperty(t, data, "evt-json", "DTSTART"))
+	assert.Equal(t, ";PARTSTAT=DECLINED:mailto:bob@example.com", icsProperty(t, data, "evt-json", "ATTENDEE"))
+	assert.Contains(t, data, "TZID:Europe/Berlin\r\n")
+}
+
+func TestICSSink_IgnoresNonEvents(t *testing.T) {
+	s := newTestICSSink(t)
+
+	require.NoError(t, s.Write(context.Background(), []models.FullItem{makeTestItem("1", "Issue", "body")}))
+
+	_, err := os.Stat(s.Path())
+	assert.True(t, os.IsNotExist(err), "no file should be written without events")
+}
+
+func TestNewICSSink_Path(t *testing.T) {
+	assert.Equal(t, filepath.Join("/vault", "calendar.ics"), NewICSSink("/vault", models.ICSExportConfig{}).Path())
+	assert.Equal(t, filepath.Join("/vault", "exports", "work.ics"),
+		NewICSSink("/vault", models.ICSExportConfig{Path: "exports/work.ics"}).Path())
+	assert.Equal(t, "/tmp/cal.ics", NewICSSink("/vault", models.ICSExportConfig{Path: "/tmp/cal.ics"}).Path())
+}
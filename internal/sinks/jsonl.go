@@ -0,0 +1,136 @@
+package sinks
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"pkm-sync/pkg/interfaces"
+	"pkm-sync/pkg/models"
+)
+
+// defaultJSONLFile is the file the JSONL target appends to.
+const defaultJSONLFile = "items.jsonl"
+
+// JSONLSink appends every item it is given to one newline-delimited JSON file,
+// one serialized models.FullItem per line, for scripts that want structured
+// data instead of notes. Lines use the format the jsonl source reads, so the
+// file can be fed back into pkm-sync. Items accumulate across runs; nothing
+// is deduplicated.
+type JSONLSink struct {
+	mu   sync.Mutex
+	path string
+	// previewLines is how many lines Preview puts in ContentPreview.
+	previewLines int
+}
+
+// NewJSONLSink returns a JSONLSink writing cfg.Path, resolved against
+// outputDir when relative (default: outputDir/items.jsonl).
+func NewJSONLSink(outputDir string, cfg models.JSONLTargetConfig) *JSONLSink {
+	path := cfg.Path
+	if path == "" {
+		path = defaultJSONLFile
+	}
+
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(outputDir, path)
+	}
+
+	return &JSONLSink{path: path}
+}
+
+// Name implements interfaces.Sink.
+func (s *JSONLSink) Name() string {
+	return "jsonl"
+}
+
+// Path returns the file the sink appends to.
+func (s *JSONLSink) Path() string {
+	return s.path
+}
+
+// Write implements interfaces.Sink by appending one line per item.
+func (s *JSONLSink) Write(_ context.Context, items []models.FullItem) error {
+	if len(items) == 0 {
+		return nil
+	}
+
+	data, err := encodeJSONLItems(items)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o755); err != nil {
+		return fmt.Errorf("failed to create JSONL directory: %w", err)
+	}
+
+	f, err := os.OpenFile(s.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return fmt.Errorf("failed to open JSONL file: %w", err)
+	}
+
+	_, writeErr := f.Write(data)
+	closeErr := f.Close()
+
+	if writeErr != nil {
+		return fmt.Errorf("failed to append to JSONL file: %w", writeErr)
+	}
+
+	return closeErr
+}
+
+// Preview describes the lines Write would add: a single "create" preview when
+// the file does not exist yet, else an "append" preview.
+func (s *JSONLSink) Preview(items []models.FullItem) ([]*interfaces.FilePreview, error) {
+	data, err := encodeJSONLItems(items)
+	if err != nil {
+		return nil, err
+	}
+
+	action := "append"
+
+	if _, err := os.Stat(s.path); errors.Is(err, os.ErrNotExist) {
+		action = "create"
+	} else if err != nil {
+		return nil, fmt.Errorf("could not determine action for %s: %w", s.path, err)
+	}
+
+	return []*interfaces.FilePreview{{
+		FilePath:       s.path,
+		Action:         action,
+		Content:        string(data),
+		ContentPreview: contentPreview(action, string(data), s.previewLines),
+	}}, nil
+}
+
+// SetPreviewLines makes Preview fill in the first n lines it would append;
+// 0 turns it off.
+func (s *JSONLSink) SetPreviewLines(n int) {
+	s.previewLines = n
+}
+
+func encodeJSONLItems(items []models.FullItem) ([]byte, error) {
+	var buf bytes.Buffer
+
+	for _, item := range items {
+		line, err := item.MarshalJSON()
+		if err != nil {
+			return nil, fmt.Errorf("failed to serialize item %s: %w", item.GetID(), err)
+		}
+
+		buf.Write(line)
+		buf.WriteByte('\n')
+	}
+
+	return buf.Bytes(), nil
+}
+
+// Ensure JSONLSink implements Sink.
+var _ interfaces.Sink = (*JSONLSink)(nil)
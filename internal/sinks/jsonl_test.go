@@ -0,0 +1,62 @@
+package sinks
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"pkm-sync/internal/sources/jsonl"
+	"pkm-sync/pkg/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJSONLSink_AppendsOneLinePerItem(t *testing.T) {
+	dir := t.TempDir()
+	sink := NewJSONLSink(dir, models.JSONLTargetConfig{})
+	assert.Equal(t, filepath.Join(dir, "items.jsonl"), sink.Path())
+
+	thread := models.NewThread("thread_1", "Launch")
+	thread.AddMessage(makeTestItem("m1", "Kickoff", "Let's go."))
+
+	require.NoError(t, sink.Write(context.Background(), []models.FullItem{makeTestItem("a", "First", "one"), thread}))
+	require.NoError(t, sink.Write(context.Background(), []models.FullItem{makeTestItem("b", "Second", "two")}))
+
+	f, err := os.Open(sink.Path())
+	require.NoError(t, err)
+
+	defer f.Close()
+
+	items, malformed, err := jsonl.Read(f, 0)
+	require.NoError(t, err)
+	assert.Empty(t, malformed)
+	require.Len(t, items, 3)
+	assert.Equal(t, "First", items[0].GetTitle())
+	assert.True(t, models.IsThread(items[1]))
+	assert.Equal(t, "Second", items[2].GetTitle())
+}
+
+func TestJSONLSink_Preview(t *testing.T) {
+	dir := t.TempDir()
+	sink := NewJSONLSink(dir, models.JSONLTargetConfig{Path: "export/out.jsonl"})
+	items := []models.FullItem{makeTestItem("a", "First", "one"), makeTestItem("b", "Second", "two")}
+
+	previews, err := sink.Preview(items)
+	require.NoError(t, err)
+	require.Len(t, previews, 1)
+	assert.Equal(t, "create", previews[0].Action)
+	assert.Equal(t, filepath.Join(dir, "export", "out.jsonl"), previews[0].FilePath)
+	assert.Equal(t, 2, strings.Count(previews[0].Content, "\n"))
+
+	_, err = os.Stat(sink.Path())
+	assert.True(t, os.IsNotExist(err), "Preview must not write")
+
+	require.NoError(t, sink.Write(context.Background(), items))
+
+	previews, err = sink.Preview(items)
+	require.NoError(t, err)
+	assert.Equal(t, "append", previews[0].Action)
+}
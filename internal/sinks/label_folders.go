@@ -0,0 +1,80 @@
+package sinks
+
+import (
+	"strings"
+
+	"pkm-sync/pkg/models"
+)
+
+// DefaultSystemLabelFolders are the folders Gmail system labels map to when a
+// source sets folders but does not list them, in precedence order.
+var DefaultSystemLabelFolders = []models.GmailFolderMapping{
+	{Label: "STARRED", Folder: "Starred"},
+	{Label: "IMPORTANT", Folder: "Important"},
+	{Label: "SENT", Folder: "Sent"},
+}
+
+// SetLabelFolders routes the notes of the Gmail source sourceName (items whose
+// source_name metadata matches) into vault folders by label. The first mapping
+// whose label the item carries wins; labels compare case-insensitively with the
+// stored label IDs. System labels not in mappings follow, with the folders of
+// DefaultSystemLabelFolders. An empty mappings list turns routing off.
+func (s *FileSink) SetLabelFolders(sourceName string, mappings []models.GmailFolderMapping) {
+	if len(mappings) == 0 {
+		delete(s.labelFolders, sourceName)
+
+		return
+	}
+
+	resolved := append([]models.GmailFolderMapping(nil), mappings...)
+
+	for _, def := range DefaultSystemLabelFolders {
+		listed := false
+
+		for _, m := range mappings {
+			if strings.EqualFold(m.Label, def.Label) {
+				listed = true
+
+				break
+			}
+		}
+
+		if !listed {
+			resolved = append(resolved, def)
+		}
+	}
+
+	if s.labelFolders == nil {
+		s.labelFolders = make(map[string][]models.GmailFolderMapping)
+	}
+
+	s.labelFolders[sourceName] = resolved
+}
+
+// labelFolder returns the folder of the highest-precedence mapping matching
+// one of the item's labels.
+func (s *FileSink) labelFolder(item models.FullItem) (string, bool) {
+	if len(s.labelFolders) == 0 {
+		return "", false
+	}
+
+	metadata := item.GetMetadata()
+	sourceName, _ := metadata["source_name"].(string)
+
+	mappings, ok := s.labelFolders[sourceName]
+	if !ok {
+		return "", false
+	}
+
+	labels := extractLabels(metadata)
+
+	for _, m := range mappings {
+		for _, label := range labels {
+			if strings.EqualFold(label, m.Label) {
+				return m.Folder, m.Folder != ""
+			}
+		}
+	}
+
+	return "", false
+}
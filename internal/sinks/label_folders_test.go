@@ -0,0 +1,65 @@
+package sinks
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"pkm-sync/pkg/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func labeledEmail(sourceName string, labels ...string) models.FullItem {
+	item := makeTestItem("msg-1", "Quarterly plan", "See attached.")
+	item.SetSourceType("gmail")
+	item.SetItemType("email")
+	item.SetMetadata(map[string]interface{}{"source_name": sourceName, "labels": labels})
+
+	return item
+}
+
+func TestLabelFolders_HighestPrecedenceWins(t *testing.T) {
+	sink, dir := newTestFileSink(t)
+	sink.SetLabelFolders("work", []models.GmailFolderMapping{
+		{Label: "IMPORTANT", Folder: "Mail/Priority"},
+		{Label: "STARRED", Folder: "Mail/Starred"},
+	})
+
+	path, err := sink.PathFor(labeledEmail("work", "INBOX", "STARRED", "IMPORTANT"))
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(dir, "Mail/Priority", "Quarterly-plan.md"), path)
+
+	path, err = sink.PathFor(labeledEmail("work", "INBOX", "STARRED"))
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(dir, "Mail/Starred", "Quarterly-plan.md"), path)
+}
+
+func TestLabelFolders_SystemLabelDefaults(t *testing.T) {
+	sink, dir := newTestFileSink(t)
+	sink.SetLabelFolders("work", []models.GmailFolderMapping{{Label: "Label_7", Folder: "Clients"}})
+
+	path, err := sink.PathFor(labeledEmail("work", "sent", "IMPORTANT"))
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(dir, "Important", "Quarterly-plan.md"), path)
+
+	path, err = sink.PathFor(labeledEmail("work", "IMPORTANT", "Label_7"))
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(dir, "Clients", "Quarterly-plan.md"), path)
+}
+
+func TestLabelFolders_OtherSourceAndUnmatchedUnchanged(t *testing.T) {
+	sink, dir := newTestFileSink(t)
+	sink.SetLabelFolders("work", []models.GmailFolderMapping{{Label: "STARRED", Folder: "Starred"}})
+
+	for _, item := range []models.FullItem{
+		labeledEmail("personal", "STARRED"),
+		labeledEmail("work", "INBOX"),
+	} {
+		path, err := sink.PathFor(item)
+		require.NoError(t, err)
+		assert.False(t, strings.Contains(path, "Starred"), "path %s", path)
+		assert.Equal(t, dir, filepath.Dir(path))
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"os"
 	"strings"
 
+	"pkm-sync/internal/naming"
 	"pkm-sync/pkg/models"
 )
 
@@ -12,10 +13,16 @@ type logseqFormatter struct {
 	graphPath   string
 	journalPath string
 	pagesPath   string
+	metadata    metadataFilter
+	tags        tagHierarchy
+	dates       dateRendering
 }
 
 func newLogseqFormatter() *logseqFormatter {
-	return &logseqFormatter{}
+	return &logseqFormatter{
+		metadata: newMetadataFilter(nil, nil),
+		tags:     tagHierarchy{separator: defaultTagHierarchySeparator},
+	}
 }
 
 func (l *logseqFormatter) name() string {
@@ -32,6 +39,10 @@ func (l *logseqFormatter) configure(config map[string]any) {
 		l.journalPath = graphPath + "/journals"
 		l.pagesPath = graphPath + "/pages"
 	}
+
+	l.metadata = configureMetadataFilter(config)
+	l.tags = configureTagHierarchy(config)
+	l.dates = configureDateRendering(config)
 }
 
 func (l *logseqFormatter) formatContent(item models.FullItem) string {
@@ -40,24 +51,12 @@ func (l *logseqFormatter) formatContent(item models.FullItem) string {
 	sb.WriteString("- id:: " + item.GetID() + "\n")
 	sb.WriteString("- source:: " + item.GetSourceType() + "\n")
 	sb.WriteString("- type:: " + item.GetItemType() + "\n")
-	sb.WriteString("- created:: [[" + item.GetCreatedAt().Format("Jan 2nd, 2006") + "]]\n")
-
-	for key, value := range item.GetMetadata() {
-		fmt.Fprintf(&sb, "- %s:: %v\n", key, value)
-	}
+	sb.WriteString("- created:: [[" + l.dates.in(item.GetCreatedAt()).Format("Jan 2nd, 2006") + "]]\n")
 
-	if len(item.GetTags()) > 0 {
-		sb.WriteString("- tags:: ")
+	sb.WriteString(l.formatMetadata(item.GetMetadata()))
 
-		for i, tag := range item.GetTags() {
-			if i > 0 {
-				sb.WriteString(", ")
-			}
-
-			sb.WriteString("#" + tag)
-		}
-
-		sb.WriteString("\n")
+	if tags := l.formatTags(item.GetTags()); len(tags) > 0 {
+		sb.WriteString("- tags:: " + strings.Join(tags, ", ") + "\n")
 	}
 
 	sb.WriteString("\n")
@@ -94,51 +93,28 @@ func (l *logseqFormatter) formatContent(item models.FullItem) string {
 }
 
 func (l *logseqFormatter) formatFilename(title string) string {
-	return logseqSanitizeFilename(title) + l.fileExtension()
+	return naming.Clean(title) + l.fileExtension()
 }
 
 func (l *logseqFormatter) fileExtension() string {
 	return ".md"
 }
 
+// formatTags renders tags as Logseq tags, with hierarchy as "/" namespaces.
+func (l *logseqFormatter) formatTags(tags []string) []string {
+	return renderTags(tags, l.tags.logseqTag)
+}
+
 func (l *logseqFormatter) formatMetadata(metadata map[string]any) string {
 	var sb strings.Builder
 
-	for key, value := range metadata {
+	for key, value := range l.metadata.apply(metadata) {
 		fmt.Fprintf(&sb, "- %s:: %v\n", key, value)
 	}
 
 	return sb.String()
 }
 
-// logseqSanitizeFilename removes or replaces characters invalid in filenames
-// while preserving spaces (Logseq's preferred page reference format).
-func logseqSanitizeFilename(filename string) string {
-	replacements := map[string]string{
-		"/":  "-",
-		"\\": "-",
-		":":  "-",
-		"*":  "",
-		"?":  "",
-		"\"": "",
-		"<":  "",
-		">":  "",
-		"|":  "-",
-	}
-
-	for old, newVal := range replacements {
-		filename = strings.ReplaceAll(filename, old, newVal)
-	}
-
-	filename = strings.TrimSpace(filename)
-
-	for strings.Contains(filename, "  ") {
-		filename = strings.ReplaceAll(filename, "  ", " ")
-	}
-
-	return filename
-}
-
 // logseqDetermineFileAction determines whether to create, update, or skip a file.
 func logseqDetermineFileAction(filePath, newContent string) (string, string, error) {
 	existingData, err := os.ReadFile(filePath)
@@ -0,0 +1,85 @@
+package sinks
+
+import (
+	"path"
+)
+
+// defaultMetadataExclude lists keys sources record for their own processing
+// that rarely belong in a note's frontmatter. They are hidden unless a target
+// configures its own include or exclude list.
+var defaultMetadataExclude = []string{
+	"headers",
+	"snippet",
+	"size",
+	"history_id",
+	"internal_date",
+	"thread_mode",
+	"thread_summary_length",
+	"thread_consolidated",
+	"source_name",
+}
+
+// metadataFilter decides which metadata keys a formatter renders. Patterns use
+// path.Match syntax, so "gmail_*" or "*" work as well as exact keys.
+type metadataFilter struct {
+	include []string
+	exclude []string
+}
+
+// newMetadataFilter keeps only keys matching include (all when empty) and then
+// drops keys matching exclude. With neither set, defaultMetadataExclude applies.
+func newMetadataFilter(include, exclude []string) metadataFilter {
+	if len(include) == 0 && len(exclude) == 0 {
+		exclude = defaultMetadataExclude
+	}
+
+	return metadataFilter{include: include, exclude: exclude}
+}
+
+func (f metadataFilter) allows(key string) bool {
+	if len(f.include) > 0 && !matchesAny(f.include, key) {
+		return false
+	}
+
+	return !matchesAny(f.exclude, key)
+}
+
+// apply returns the subset of metadata the filter allows.
+func (f metadataFilter) apply(metadata map[string]any) map[string]any {
+	if len(metadata) == 0 {
+		return metadata
+	}
+
+	filtered := make(map[string]any, len(metadata))
+
+	for key, value := range metadata {
+		if f.allows(key) {
+			filtered[key] = value
+		}
+	}
+
+	return filtered
+}
+
+// configureMetadataFilter reads the metadata_include and metadata_exclude
+// formatter settings.
+func configureMetadataFilter(config map[string]any) metadataFilter {
+	include, _ := config["metadata_include"].([]string)
+	exclude, _ := config["metadata_exclude"].([]string)
+
+	return newMetadataFilter(include, exclude)
+}
+
+func matchesAny(patterns []string, key string) bool {
+	for _, pattern := range patterns {
+		if pattern == key {
+			return true
+		}
+
+		if ok, err := path.Match(pattern, key); err == nil && ok {
+			return true
+		}
+	}
+
+	return false
+}
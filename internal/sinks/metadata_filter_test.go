@@ -0,0 +1,97 @@
+package sinks
+
+import (
+	"testing"
+	"time"
+
+	"pkm-sync/pkg/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func makeNoisyItem() models.FullItem {
+	return &models.BasicItem{
+		ID:         "msg-1",
+		Title:      "Quarterly planning",
+		Content:    "Body",
+		SourceType: "gmail",
+		ItemType:   "email",
+		CreatedAt:  time.Date(2026, 4, 16, 12, 0, 0, 0, time.UTC),
+		UpdatedAt:  time.Date(2026, 4, 16, 12, 0, 0, 0, time.UTC),
+		Metadata: map[string]interface{}{
+			"from":        "alice@example.com",
+			"to":          "bob@example.com",
+			"labels":      "INBOX",
+			"thread_id":   "t-1",
+			"headers":     "Received: ...",
+			"snippet":     "Body",
+			"size":        1234,
+			"thread_mode": "individual",
+		},
+	}
+}
+
+func TestMetadataFilter_Defaults(t *testing.T) {
+	f := newMetadataFilter(nil, nil)
+
+	assert.True(t, f.allows("from"))
+	assert.True(t, f.allows("thread_id"))
+	assert.False(t, f.allows("headers"))
+	assert.False(t, f.allows("snippet"))
+	assert.False(t, f.allows("thread_mode"))
+}
+
+func TestMetadataFilter_IncludeAndExclude(t *testing.T) {
+	f := newMetadataFilter([]string{"from", "to", "gmail_*"}, []string{"gmail_raw"})
+
+	assert.True(t, f.allows("from"))
+	assert.True(t, f.allows("gmail_labels"))
+	assert.False(t, f.allows("gmail_raw"))
+	assert.False(t, f.allows("thread_id"))
+
+	// An explicit list replaces the default denylist.
+	all := newMetadataFilter([]string{"*"}, nil)
+	assert.True(t, all.allows("headers"))
+
+	custom := newMetadataFilter(nil, []string{"labels"})
+	assert.False(t, custom.allows("labels"))
+	assert.True(t, custom.allows("snippet"))
+}
+
+func TestObsidianFrontmatter_OnlyAllowedKeys(t *testing.T) {
+	o := newObsidianFormatter()
+	o.configure(map[string]any{"metadata_include": []string{"from", "to"}})
+
+	content := o.formatContent(makeNoisyItem())
+
+	assert.Contains(t, content, `from: "alice@example.com"`)
+	assert.Contains(t, content, `to: "bob@example.com"`)
+
+	for _, key := range []string{"labels:", "thread_id:", "headers:", "snippet:", "size:", "thread_mode:"} {
+		assert.NotContains(t, content, key)
+	}
+}
+
+func TestObsidianFrontmatter_DefaultDenylist(t *testing.T) {
+	content := newObsidianFormatter().formatContent(makeNoisyItem())
+
+	assert.Contains(t, content, "labels: INBOX")
+	assert.Contains(t, content, "thread_id: t-1")
+
+	for _, key := range []string{"headers:", "snippet:", "size:", "thread_mode:"} {
+		assert.NotContains(t, content, key)
+	}
+}
+
+func TestLogseqProperties_OnlyAllowedKeys(t *testing.T) {
+	l := newLogseqFormatter()
+	l.configure(map[string]any{"metadata_exclude": []string{"labels", "thread_*"}})
+
+	content := l.formatContent(makeNoisyItem())
+
+	assert.Contains(t, content, "- from:: alice@example.com")
+	assert.Contains(t, content, "- snippet:: Body")
+	assert.NotContains(t, content, "labels::")
+	assert.NotContains(t, content, "thread_id::")
+	assert.NotContains(t, content, "thread_mode::")
+}
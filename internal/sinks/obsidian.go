@@ -2,10 +2,11 @@ package sinks
 
 import (
 	"fmt"
+	"slices"
 	"strings"
 	"time"
 
-	"pkm-sync/internal/utils"
+	"pkm-sync/internal/naming"
 	"pkm-sync/pkg/models"
 )
 
@@ -19,11 +20,27 @@ type obsidianFormatter struct {
 	vaultPath        string
 	templateDir      string
 	dailyNotesFormat string
+	// filenameTemplate names notes from item fields (see formatItemFilename);
+	// empty names them after the title.
+	filenameTemplate string
+	metadata         metadataFilter
+	tags             tagHierarchy
+	dates            dateRendering
+
+	// tagPrefix nests every tag under it ("calendar" renders "meeting" as
+	// "calendar/meeting"); empty leaves tags alone.
+	tagPrefix string
+	// customFields are metadata keys written to frontmatter as plain values
+	// (see formatCustomFields).
+	customFields []string
 }
 
 func newObsidianFormatter() *obsidianFormatter {
 	return &obsidianFormatter{
 		dailyNotesFormat: "2006-01-02",
+		metadata:         newMetadataFilter(nil, nil),
+		tags:             tagHierarchy{separator: defaultTagHierarchySeparator},
+		dates:            dateRendering{layout: defaultDateFormat},
 	}
 }
 
@@ -63,6 +80,22 @@ func (o *obsidianFormatter) configure(config map[string]any) {
 	if format, ok := config["daily_notes_format"].(string); ok {
 		o.dailyNotesFormat = format
 	}
+
+	if template, ok := config["filename_template"].(string); ok {
+		o.filenameTemplate = template
+	}
+
+	if prefix, ok := config["tag_prefix"].(string); ok {
+		o.tagPrefix = strings.Trim(prefix, "#/ ")
+	}
+
+	if fields, ok := config["custom_fields"].([]string); ok {
+		o.customFields = fields
+	}
+
+	o.metadata = configureMetadataFilter(config)
+	o.tags = configureTagHierarchy(config)
+	o.dates = configureDateRendering(config)
 }
 
 func (o *obsidianFormatter) formatContent(item models.FullItem) string {
@@ -78,15 +111,16 @@ func (o *obsidianFormatter) formatBasicItemContent(item models.FullItem) string
 
 	sb.WriteString("---\n")
 	sb.WriteString(o.formatMetadata(item.GetMetadata()))
+	sb.WriteString(o.formatCustomFields(item.GetMetadata()))
 	fmt.Fprintf(&sb, "id: %s\n", item.GetID())
 	fmt.Fprintf(&sb, "source: %s\n", item.GetSourceType())
 	fmt.Fprintf(&sb, "type: %s\n", item.GetItemType())
-	fmt.Fprintf(&sb, "created: %s\n", item.GetCreatedAt().Format(time.RFC3339))
+	fmt.Fprintf(&sb, "created: %s\n", o.dates.in(item.GetCreatedAt()).Format(time.RFC3339))
 
-	if len(item.GetTags()) > 0 {
+	if tags := o.formatTags(item.GetTags()); len(tags) > 0 {
 		sb.WriteString("tags:\n")
 
-		for _, tag := range item.GetTags() {
+		for _, tag := range tags {
 			fmt.Fprintf(&sb, "  - %s\n", tag)
 		}
 	}
@@ -136,16 +170,17 @@ func (o *obsidianFormatter) formatThreadContent(item models.FullItem) string {
 
 	sb.WriteString("---\n")
 	sb.WriteString(o.formatMetadata(thread.GetMetadata()))
+	sb.WriteString(o.formatCustomFields(thread.GetMetadata()))
 	fmt.Fprintf(&sb, "id: %s\n", thread.GetID())
 	fmt.Fprintf(&sb, "source: %s\n", thread.GetSourceType())
 	fmt.Fprintf(&sb, "type: %s\n", thread.GetItemType())
-	fmt.Fprintf(&sb, "created: %s\n", thread.GetCreatedAt().Format(time.RFC3339))
+	fmt.Fprintf(&sb, "created: %s\n", o.dates.in(thread.GetCreatedAt()).Format(time.RFC3339))
 	fmt.Fprintf(&sb, "message_count: %d\n", len(thread.GetMessages()))
 
-	if len(thread.GetTags()) > 0 {
+	if tags := o.formatTags(thread.GetTags()); len(tags) > 0 {
 		sb.WriteString("tags:\n")
 
-		for _, tag := range thread.GetTags() {
+		for _, tag := range tags {
 			fmt.Fprintf(&sb, "  - %s\n", tag)
 		}
 	}
@@ -173,10 +208,10 @@ func (o *obsidianFormatter) formatThreadContent(item models.FullItem) string {
 func (o *obsidianFormatter) formatThreadMessage(sb *strings.Builder, messageNum int, message models.FullItem) {
 	fmt.Fprintf(sb, "### Message %d: %s\n\n", messageNum, message.GetTitle())
 	fmt.Fprintf(sb, "**From:** %s  \n", message.GetSourceType())
-	fmt.Fprintf(sb, "**Created:** %s  \n", message.GetCreatedAt().Format(time.RFC3339))
+	fmt.Fprintf(sb, "**Created:** %s  \n", o.dates.in(message.GetCreatedAt()).Format(time.RFC3339))
 
-	if len(message.GetTags()) > 0 {
-		fmt.Fprintf(sb, "**Tags:** %s  \n", strings.Join(message.GetTags(), ", "))
+	if tags := o.formatTags(message.GetTags()); len(tags) > 0 {
+		fmt.Fprintf(sb, "**Tags:** #%s  \n", strings.Join(tags, " #"))
 	}
 
 	sb.WriteString("\n")
@@ -204,14 +239,136 @@ func (o *obsidianFormatter) formatThreadMessage(sb *strings.Builder, messageNum
 }
 
 func (o *obsidianFormatter) formatFilename(title string) string {
-	return utils.SanitizeFilename(title) + o.fileExtension()
+	return naming.Slug(title) + o.fileExtension()
+}
+
+// formatItemFilename expands the filename template's {{date}}, {{title}},
+// {{source}}, {{id}} and {{type}} tokens and makes the result a safe filename.
+// {{date}} is the creation date in the sink's zone, in the target's
+// date_format, and empty for items without one. Without a template, or when
+// the template renders to nothing, the note is named after its title.
+func (o *obsidianFormatter) formatItemFilename(item models.FullItem) string {
+	if o.filenameTemplate == "" {
+		return o.formatFilename(item.GetTitle())
+	}
+
+	date := ""
+	if created := item.GetCreatedAt(); !created.IsZero() {
+		layout := o.dailyNotesFormat
+		if layout == "" {
+			layout = defaultDateFormat
+		}
+
+		date = o.dates.in(created).Format(layout)
+	}
+
+	name := strings.NewReplacer(
+		"{{date}}", date,
+		"{{title}}", item.GetTitle(),
+		"{{source}}", item.GetSourceType(),
+		"{{id}}", item.GetID(),
+		"{{type}}", item.GetItemType(),
+	).Replace(o.filenameTemplate)
+
+	// Drop separators left dangling by empty tokens, and leading dots that
+	// would hide the note.
+	name = strings.Trim(name, " -_.")
+	if name == "" {
+		return o.formatFilename(item.GetTitle())
+	}
+
+	return naming.Clean(name) + o.fileExtension()
 }
 
 func (o *obsidianFormatter) fileExtension() string {
 	return ".md"
 }
 
+// formatTags renders tags as frontmatter lists them: nested with "/", without
+// the leading "#" and under the tag prefix.
+func (o *obsidianFormatter) formatTags(tags []string) []string {
+	return renderTags(tags, func(tag string) string {
+		return o.prefixTag(o.tags.obsidianTag(tag))
+	})
+}
+
+// prefixTag nests a rendered tag under tagPrefix, unless it is already there:
+// source tags reuse the prefix as their namespace.
+func (o *obsidianFormatter) prefixTag(tag string) string {
+	if tag == "" || o.tagPrefix == "" || tag == o.tagPrefix || strings.HasPrefix(tag, o.tagPrefix+"/") {
+		return tag
+	}
+
+	return o.tagPrefix + "/" + tag
+}
+
+// obsidianReservedFields are the frontmatter keys the formatter writes itself,
+// which custom_fields cannot override.
+var obsidianReservedFields = map[string]bool{
+	"id": true, "source": true, "type": true, "created": true, "tags": true, "message_count": true,
+}
+
+// formatCustomFields writes each custom field found in metadata as one
+// frontmatter line: scalars as text, slices joined with ", ". Fields missing
+// from the item, or empty, are left out.
+func (o *obsidianFormatter) formatCustomFields(metadata map[string]any) string {
+	var sb strings.Builder
+
+	for _, key := range o.customFields {
+		if obsidianReservedFields[key] {
+			continue
+		}
+
+		value := customFieldValue(metadata[key])
+		if value == "" {
+			continue
+		}
+
+		if needsYAMLQuoting(value) {
+			fmt.Fprintf(&sb, "%s: %q\n", key, value)
+		} else {
+			fmt.Fprintf(&sb, "%s: %s\n", key, value)
+		}
+	}
+
+	return sb.String()
+}
+
+// customFieldValue renders a metadata value on one line; nil and empty
+// values give "".
+func customFieldValue(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return strings.TrimSpace(v)
+	case []string:
+		return strings.Join(v, ", ")
+	case []any:
+		parts := make([]string, 0, len(v))
+
+		for _, elem := range v {
+			if part := customFieldValue(elem); part != "" {
+				parts = append(parts, part)
+			}
+		}
+
+		return strings.Join(parts, ", ")
+	case time.Time:
+		if v.IsZero() {
+			return ""
+		}
+
+		return v.Format(time.RFC3339)
+	default:
+		return strings.TrimSpace(fmt.Sprintf("%v", v))
+	}
+}
+
+// formatMetadata writes the metadata kept by the metadata filter, except the
+// custom fields, which formatCustomFields writes.
 func (o *obsidianFormatter) formatMetadata(metadata map[string]any) string {
+	metadata = o.metadata.apply(metadata)
 	if len(metadata) == 0 {
 		return ""
 	}
@@ -219,6 +376,10 @@ func (o *obsidianFormatter) formatMetadata(metadata map[string]any) string {
 	var sb strings.Builder
 
 	for key, value := range metadata {
+		if slices.Contains(o.customFields, key) && !obsidianReservedFields[key] {
+			continue
+		}
+
 		if key == metaKeyAttendees {
 			sb.WriteString(o.formatAttendees(value))
 		} else if arr, ok := value.([]string); ok {
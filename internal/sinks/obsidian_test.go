@@ -0,0 +1,155 @@
+package sinks
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"pkm-sync/pkg/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTemplatedSink(t *testing.T, template string) (*FileSink, string) {
+	t.Helper()
+
+	dir := t.TempDir()
+	sink, err := NewFileSink("obsidian", dir, map[string]any{
+		"filename_template":  template,
+		"daily_notes_format": "2006.01.02",
+		"timezone":           "Asia/Tokyo",
+	})
+	require.NoError(t, err)
+
+	return sink, dir
+}
+
+func TestObsidianFilenameTemplate(t *testing.T) {
+	tests := []struct {
+		template string
+		want     string
+	}{
+		{"", "Fix-login-bug.md"},
+		// 2026-04-16 12:00 UTC is the 16th in Tokyo; ":" is not portable.
+		{"{{date}} - {{title}}", "2026.04.16 - Fix login- bug.md"},
+		{"{{source}}_{{type}}_{{id}}", "jira_issue_PROJ-1.md"},
+		{"{{title}}/{{id}}", "Fix login- bug-PROJ-1.md"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.template, func(t *testing.T) {
+			sink, dir := newTemplatedSink(t, tt.template)
+
+			path, err := sink.PathFor(makeTestItem("PROJ-1", "Fix login: bug", "Body"))
+			require.NoError(t, err)
+			assert.Equal(t, filepath.Join(dir, tt.want), path)
+		})
+	}
+}
+
+func TestObsidianFilenameTemplate_EmptyTokens(t *testing.T) {
+	sink, dir := newTemplatedSink(t, "{{date}} - {{title}}")
+
+	undated := makeTestItem("n-1", "Loose note", "Body")
+	undated.SetCreatedAt(time.Time{})
+
+	path, err := sink.PathFor(undated)
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(dir, "Loose note.md"), path, "dangling separator is dropped")
+
+	hidden := makeTestItem("n-2", ".env", "Body")
+	hidden.SetCreatedAt(time.Time{})
+
+	path, err = sink.PathFor(hidden)
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(dir, "env.md"), path, "leading dot would hide the note")
+}
+
+func TestFileSink_CollidingNamesGetSuffix(t *testing.T) {
+	sink, dir := newTemplatedSink(t, "{{title}}")
+	items := []models.FullItem{
+		makeTestItem("a", "Standup", "First"),
+		makeTestItem("b", "Standup", "Second"),
+		makeTestItem("c", "Standup", "Third"),
+	}
+	want := []string{
+		filepath.Join(dir, "Standup.md"),
+		filepath.Join(dir, "Standup-2.md"),
+		filepath.Join(dir, "Standup-3.md"),
+	}
+
+	previews, err := sink.Preview(items)
+	require.NoError(t, err)
+	require.Len(t, previews, 3)
+
+	for i, preview := range previews {
+		assert.Equal(t, want[i], preview.FilePath)
+	}
+
+	require.NoError(t, sink.Write(context.Background(), items))
+
+	for i, item := range items {
+		data, err := os.ReadFile(want[i])
+		require.NoError(t, err)
+		assert.Contains(t, string(data), item.GetContent())
+
+		path, err := sink.PathFor(item)
+		require.NoError(t, err)
+		assert.Equal(t, want[i], path, "each item keeps its suffixed note")
+	}
+
+	// Re-syncing updates the same notes instead of adding more suffixes.
+	require.NoError(t, sink.Write(context.Background(), items))
+
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Len(t, entries, 3)
+}
+
+func TestFileSink_UserNoteIsNotACollision(t *testing.T) {
+	sink, dir := newTemplatedSink(t, "{{title}}")
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "Standup.md"), []byte("# My notes\n"), 0644))
+
+	path, err := sink.PathFor(makeTestItem("a", "Standup", "Body"))
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(dir, "Standup.md"), path)
+}
+
+func TestObsidianCustomFields(t *testing.T) {
+	o := newObsidianFormatter()
+	o.configure(map[string]any{"custom_fields": []string{"project", "labels", "points", "missing", "blank", "id"}})
+
+	item := makeTestItem("PROJ-1", "Fix bug", "Body")
+	item.SetMetadata(map[string]any{
+		"project": "PROJ",
+		"labels":  []any{"backend", "urgent"},
+		"points":  3,
+		"blank":   "",
+		"status":  "Open",
+	})
+
+	content := o.formatContent(item)
+
+	assert.Contains(t, content, "project: PROJ\n")
+	assert.Contains(t, content, "labels: backend, urgent\n")
+	assert.Contains(t, content, "points: 3\n")
+	assert.Contains(t, content, "status: Open\n", "other metadata is still written")
+	assert.NotContains(t, content, "missing")
+	assert.NotContains(t, content, "blank:")
+	assert.Equal(t, 1, strings.Count(content, "\nid: "), "custom fields cannot repeat a reserved key")
+	assert.Equal(t, 1, strings.Count(content, "labels"), "a custom field is written once, flattened")
+}
+
+func TestObsidianTagPrefix(t *testing.T) {
+	o := newObsidianFormatter()
+	o.configure(map[string]any{"tag_prefix": "calendar/"})
+
+	got := o.formatTags([]string{"meeting", "priority:high", "calendar/work", "#urgent"})
+	assert.Equal(t, []string{"calendar/meeting", "calendar/priority/high", "calendar/work", "calendar/urgent"}, got)
+
+	assert.Equal(t, []string{"meeting"}, newObsidianFormatter().formatTags([]string{"meeting"}), "no prefix by default")
+}
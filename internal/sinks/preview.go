@@ -0,0 +1,18 @@
+package sinks
+
+import "strings"
+
+// contentPreview returns the first n lines of content for a preview whose
+// file would be created or changed, and "" for skipped files or n <= 0.
+func contentPreview(action, content string, n int) string {
+	if n <= 0 || action == "skip" {
+		return ""
+	}
+
+	lines := strings.SplitN(content, "\n", n+1)
+	if len(lines) > n {
+		lines = lines[:n]
+	}
+
+	return strings.TrimRight(strings.Join(lines, "\n"), "\n")
+}
@@ -0,0 +1,48 @@
+package sinks
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"pkm-sync/pkg/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestContentPreview(t *testing.T) {
+	content := "---\ntitle: Note\n---\n\nBody\n"
+
+	assert.Equal(t, "---\ntitle: Note", contentPreview("create", content, 2))
+	assert.Equal(t, "---\ntitle: Note\n---\n\nBody", contentPreview("update", content, 10))
+	assert.Empty(t, contentPreview("skip", content, 2))
+	assert.Empty(t, contentPreview("create", content, 0))
+}
+
+func TestPreviewLines(t *testing.T) {
+	item := models.NewBasicItem("id-1", "Preview me")
+	item.SetContent("line one\nline two\nline three")
+
+	fileSink, err := NewFileSink("obsidian", t.TempDir(), nil)
+	require.NoError(t, err)
+
+	previews, err := fileSink.Preview([]models.FullItem{item})
+	require.NoError(t, err)
+	assert.Empty(t, previews[0].ContentPreview, "no preview lines by default")
+
+	fileSink.SetPreviewLines(3)
+
+	previews, err = fileSink.Preview([]models.FullItem{item})
+	require.NoError(t, err)
+	assert.Equal(t, contentPreview("create", previews[0].Content, 3), previews[0].ContentPreview)
+	assert.Len(t, strings.Split(previews[0].ContentPreview, "\n"), 3)
+
+	jsonlSink := NewJSONLSink(t.TempDir(), models.JSONLTargetConfig{Path: filepath.Join(t.TempDir(), "out.jsonl")})
+	jsonlSink.SetPreviewLines(1)
+
+	previews, err = jsonlSink.Preview([]models.FullItem{item, item})
+	require.NoError(t, err)
+	assert.Contains(t, previews[0].ContentPreview, `"id-1"`)
+	assert.Len(t, strings.Split(previews[0].ContentPreview, "\n"), 1)
+}
@@ -0,0 +1,257 @@
+package sinks
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"pkm-sync/internal/naming"
+	"pkm-sync/pkg/models"
+)
+
+// roamBlock is a block of Roam Research's JSON import format.
+type roamBlock struct {
+	String   string      `json:"string"`
+	Children []roamBlock `json:"children,omitempty"`
+}
+
+// roamPage is a page of Roam Research's JSON import format.
+type roamPage struct {
+	Title      string      `json:"title"`
+	CreateTime int64       `json:"create-time,omitempty"`
+	EditTime   int64       `json:"edit-time,omitempty"`
+	Children   []roamBlock `json:"children"`
+}
+
+// roamFormatter writes each item as a Roam JSON import file holding one page:
+// property blocks ("source:: gmail") first, then the body as a single block,
+// then attachments, links and, for threads, one nested block per message.
+type roamFormatter struct {
+	dailyNoteRefs bool
+	metadata      metadataFilter
+	tags          tagHierarchy
+	dates         dateRendering
+}
+
+func newRoamFormatter() *roamFormatter {
+	return &roamFormatter{
+		metadata: newMetadataFilter(nil, nil),
+		tags:     tagHierarchy{separator: defaultTagHierarchySeparator},
+	}
+}
+
+func (r *roamFormatter) name() string {
+	return "roam"
+}
+
+func (r *roamFormatter) configure(config map[string]any) {
+	if config == nil {
+		return
+	}
+
+	if refs, ok := config["daily_note_refs"].(bool); ok {
+		r.dailyNoteRefs = refs
+	}
+
+	r.metadata = configureMetadataFilter(config)
+	r.tags = configureTagHierarchy(config)
+	r.dates = configureDateRendering(config)
+}
+
+func (r *roamFormatter) formatContent(item models.FullItem) string {
+	page := roamPage{
+		Title:      item.GetTitle(),
+		CreateTime: roamTime(item.GetCreatedAt()),
+		EditTime:   roamTime(item.GetUpdatedAt()),
+		Children:   r.itemBlocks(item),
+	}
+
+	if page.EditTime == 0 {
+		page.EditTime = page.CreateTime
+	}
+
+	data, err := json.MarshalIndent([]roamPage{page}, "", "  ")
+	if err != nil {
+		// Blocks only hold strings, so this cannot happen.
+		return "[]\n"
+	}
+
+	return string(data) + "\n"
+}
+
+// itemBlocks returns the top-level blocks of an item's page.
+func (r *roamFormatter) itemBlocks(item models.FullItem) []roamBlock {
+	blocks := []roamBlock{
+		{String: "id:: " + item.GetID()},
+		{String: "source:: " + item.GetSourceType()},
+		{String: "type:: " + item.GetItemType()},
+		{String: "created:: " + r.formatDate(item.GetCreatedAt())},
+	}
+
+	for _, line := range strings.Split(strings.TrimSuffix(r.formatMetadata(item.GetMetadata()), "\n"), "\n") {
+		if line != "" {
+			blocks = append(blocks, roamBlock{String: line})
+		}
+	}
+
+	if tags := r.formatTags(item.GetTags()); len(tags) > 0 {
+		blocks = append(blocks, roamBlock{String: "tags:: " + strings.Join(tags, " ")})
+	}
+
+	if content := strings.TrimSpace(item.GetContent()); content != "" {
+		blocks = append(blocks, roamBlock{String: content})
+	}
+
+	blocks = append(blocks, r.attachmentBlocks(item)...)
+
+	if links := item.GetLinks(); len(links) > 0 {
+		parent := roamBlock{String: "Links"}
+		for _, link := range links {
+			parent.Children = append(parent.Children, roamBlock{String: fmt.Sprintf("[%s](%s)", link.Title, link.URL)})
+		}
+
+		blocks = append(blocks, parent)
+	}
+
+	if thread, ok := models.AsThread(item); ok && len(thread.GetMessages()) > 0 {
+		parent := roamBlock{String: fmt.Sprintf("Messages (%d)", len(thread.GetMessages()))}
+		for i, message := range thread.GetMessages() {
+			parent.Children = append(parent.Children, r.messageBlock(i+1, message))
+		}
+
+		blocks = append(blocks, parent)
+	}
+
+	return blocks
+}
+
+// messageBlock renders one thread message as a block whose children hold its
+// date, tags, body and attachments.
+func (r *roamFormatter) messageBlock(messageNum int, message models.FullItem) roamBlock {
+	block := roamBlock{
+		String: fmt.Sprintf("**Message %d: %s**", messageNum, message.GetTitle()),
+		Children: []roamBlock{
+			{String: "created:: " + r.formatDate(message.GetCreatedAt())},
+		},
+	}
+
+	if tags := r.formatTags(message.GetTags()); len(tags) > 0 {
+		block.Children = append(block.Children, roamBlock{String: "tags:: " + strings.Join(tags, " ")})
+	}
+
+	if content := strings.TrimSpace(message.GetContent()); content != "" {
+		block.Children = append(block.Children, roamBlock{String: content})
+	}
+
+	block.Children = append(block.Children, r.attachmentBlocks(message)...)
+
+	return block
+}
+
+func (r *roamFormatter) attachmentBlocks(item models.FullItem) []roamBlock {
+	attachments := item.GetAttachments()
+	if len(attachments) == 0 {
+		return nil
+	}
+
+	parent := roamBlock{String: "Attachments"}
+
+	for _, attachment := range attachments {
+		text := attachment.Name
+		if attachment.URL != "" {
+			text = fmt.Sprintf("[%s](%s)", attachment.Name, attachment.URL)
+		}
+
+		parent.Children = append(parent.Children, roamBlock{String: text})
+	}
+
+	return []roamBlock{parent}
+}
+
+// formatDate renders t as a Roam daily note reference ("[[October 1st,
+// 2026]]") when daily_note_refs is set, else as RFC 3339.
+func (r *roamFormatter) formatDate(t time.Time) string {
+	t = r.dates.in(t)
+	if !r.dailyNoteRefs {
+		return t.Format(time.RFC3339)
+	}
+
+	return "[[" + t.Format("January") + " " + ordinalDay(t.Day()) + ", " + t.Format("2006") + "]]"
+}
+
+// ordinalDay renders a day of the month as Roam daily note titles do: "1st",
+// "2nd", "11th", "23rd".
+func ordinalDay(day int) string {
+	suffix := "th"
+
+	switch {
+	case day%100 >= 11 && day%100 <= 13:
+	case day%10 == 1:
+		suffix = "st"
+	case day%10 == 2:
+		suffix = "nd"
+	case day%10 == 3:
+		suffix = "rd"
+	}
+
+	return fmt.Sprintf("%d%s", day, suffix)
+}
+
+func (r *roamFormatter) formatFilename(title string) string {
+	return naming.Slug(title) + r.fileExtension()
+}
+
+func (r *roamFormatter) fileExtension() string {
+	return ".json"
+}
+
+// formatTags renders tags as Roam page references, which share Logseq's
+// syntax: "#tag", or "#[[multi word]]", with levels as "/" namespaces.
+func (r *roamFormatter) formatTags(tags []string) []string {
+	return renderTags(tags, r.tags.logseqTag)
+}
+
+// formatMetadata renders metadata as "key:: value" lines in key order, so
+// unchanged items produce identical files. Attendees become page references.
+func (r *roamFormatter) formatMetadata(metadata map[string]any) string {
+	metadata = r.metadata.apply(metadata)
+
+	keys := make([]string, 0, len(metadata))
+	for key := range metadata {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	var sb strings.Builder
+
+	for _, key := range keys {
+		switch value := metadata[key].(type) {
+		case []models.Attendee:
+			refs := make([]string, 0, len(value))
+			for _, attendee := range value {
+				refs = append(refs, "[["+attendee.GetDisplayName()+"]]")
+			}
+
+			fmt.Fprintf(&sb, "%s:: %s\n", key, strings.Join(refs, " "))
+		case []string:
+			fmt.Fprintf(&sb, "%s:: %s\n", key, strings.Join(value, ", "))
+		default:
+			fmt.Fprintf(&sb, "%s:: %s\n", key, strings.ReplaceAll(fmt.Sprint(value), "\n", " "))
+		}
+	}
+
+	return sb.String()
+}
+
+// roamTime returns t in the Unix milliseconds Roam timestamps use, or 0 for
+// the zero time.
+func roamTime(t time.Time) int64 {
+	if t.IsZero() {
+		return 0
+	}
+
+	return t.UnixMilli()
+}
@@ -0,0 +1,132 @@
+package sinks
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"pkm-sync/pkg/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func decodeRoamPages(t *testing.T, content string) []roamPage {
+	t.Helper()
+
+	var pages []roamPage
+	require.NoError(t, json.Unmarshal([]byte(content), &pages))
+
+	return pages
+}
+
+func blockStrings(blocks []roamBlock) []string {
+	out := make([]string, len(blocks))
+	for i, b := range blocks {
+		out[i] = b.String
+	}
+
+	return out
+}
+
+func TestRoamFormatter_Item(t *testing.T) {
+	f := newRoamFormatter()
+	f.configure(map[string]any{"daily_note_refs": true})
+
+	item := makeTestItem("jira_PROJ-1", "PROJ-1", "Fix the build.")
+	item.SetTags([]string{"priority:high", "needs review"})
+	item.SetMetadata(map[string]any{"status": "Open", "components": []string{"api", "cli"}})
+	item.SetLinks([]models.Link{{URL: "https://jira.example.com/PROJ-1", Title: "PROJ-1"}})
+
+	pages := decodeRoamPages(t, f.formatContent(item))
+	require.Len(t, pages, 1)
+
+	page := pages[0]
+	assert.Equal(t, "PROJ-1", page.Title)
+	assert.Equal(t, item.GetCreatedAt().UnixMilli(), page.CreateTime)
+	assert.Equal(t, []string{
+		"id:: jira_PROJ-1",
+		"source:: jira",
+		"type:: issue",
+		"created:: [[April 16th, 2026]]",
+		"components:: api, cli",
+		"status:: Open",
+		"tags:: #priority/high #[[needs review]]",
+		"Fix the build.",
+		"Links",
+	}, blockStrings(page.Children))
+	assert.Equal(t, []string{"[PROJ-1](https://jira.example.com/PROJ-1)"}, blockStrings(page.Children[8].Children))
+}
+
+func TestRoamFormatter_ThreadMessagesNested(t *testing.T) {
+	thread := models.NewThread("thread_1", "Launch plan")
+	thread.SetSourceType("gmail")
+	thread.SetItemType("email_thread")
+	thread.SetCreatedAt(time.Date(2026, 10, 1, 9, 0, 0, 0, time.UTC))
+	thread.AddMessage(&models.BasicItem{
+		ID: "m1", Title: "Kickoff", Content: "Let's go.",
+		CreatedAt:   time.Date(2026, 10, 1, 9, 0, 0, 0, time.UTC),
+		Attachments: []models.Attachment{{Name: "plan.pdf"}},
+	})
+	thread.AddMessage(&models.BasicItem{ID: "m2", Title: "Re: Kickoff", Content: "Agreed."})
+
+	pages := decodeRoamPages(t, newRoamFormatter().formatContent(thread))
+	require.Len(t, pages, 1)
+
+	children := pages[0].Children
+	messages := children[len(children)-1]
+	assert.Equal(t, "Messages (2)", messages.String)
+	require.Len(t, messages.Children, 2)
+
+	first := messages.Children[0]
+	assert.Equal(t, "**Message 1: Kickoff**", first.String)
+	assert.Equal(t, []string{"created:: 2026-10-01T09:00:00Z", "Let's go.", "Attachments"}, blockStrings(first.Children))
+	assert.Equal(t, []string{"plan.pdf"}, blockStrings(first.Children[2].Children))
+}
+
+func TestOrdinalDay(t *testing.T) {
+	for day, want := range map[int]string{
+		1: "1st", 2: "2nd", 3: "3rd", 4: "4th", 11: "11th", 12: "12th", 13: "13th", 21: "21st", 22: "22nd", 31: "31st",
+	} {
+		assert.Equal(t, want, ordinalDay(day))
+	}
+}
+
+func TestRoamSink_WriteAndPreview(t *testing.T) {
+	dir := t.TempDir()
+
+	sink, err := NewFileSink("roam", dir, nil)
+	require.NoError(t, err)
+
+	item := makeTestItem("jira_PROJ-2", "PROJ 2", "Body")
+
+	previews, err := sink.Preview([]models.FullItem{item})
+	require.NoError(t, err)
+	require.Len(t, previews, 1)
+	assert.Equal(t, "create", previews[0].Action)
+	assert.Equal(t, ".json", filepath.Ext(previews[0].FilePath))
+
+	require.NoError(t, sink.Write(context.Background(), []models.FullItem{item}))
+
+	data, err := os.ReadFile(previews[0].FilePath)
+	require.NoError(t, err)
+	assert.Equal(t, "PROJ 2", decodeRoamPages(t, string(data))[0].Title)
+
+	previews, err = sink.Preview([]models.FullItem{item})
+	require.NoError(t, err)
+	assert.Equal(t, "skip", previews[0].Action)
+
+	item.SetContent("Changed body")
+
+	previews, err = sink.Preview([]models.FullItem{item})
+	require.NoError(t, err)
+	assert.Equal(t, "update", previews[0].Action)
+}
+
+func TestRoamSink_RejectsContentAffixes(t *testing.T) {
+	_, err := NewFileSink("roam", t.TempDir(), map[string]any{"content_prefix": "Synced"})
+	assert.Error(t, err)
+}
@@ -0,0 +1,74 @@
+package sinks
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"pkm-sync/internal/utils"
+	"pkm-sync/pkg/models"
+)
+
+// Subdirectory layouts (sync.subdir_format).
+const (
+	SubdirYearMonth     = "yyyy/mm"
+	SubdirYearDashMonth = "yyyy-mm"
+	SubdirSource        = "source"
+	SubdirFlat          = "flat"
+)
+
+// validateSubdirFormat normalizes a subdir_format value; empty means flat.
+func validateSubdirFormat(format string) (string, error) {
+	switch format {
+	case "":
+		return SubdirFlat, nil
+	case SubdirYearMonth, SubdirYearDashMonth, SubdirSource, SubdirFlat:
+		return format, nil
+	default:
+		return "", fmt.Errorf("unknown subdir_format %q (must be yyyy/mm, yyyy-mm, source or flat)", format)
+	}
+}
+
+// SetSubdirFormat files notes under a subdirectory of the output directory:
+// the item's creation month ("yyyy/mm" → 2024/01, "yyyy-mm" → 2024-01, in the
+// sink's zone) or its source type ("source"). "flat" (the default for an empty
+// format) keeps the formatter's own layout. A template formatter's directory
+// pattern still takes precedence.
+func (s *FileSink) SetSubdirFormat(format string) error {
+	format, err := validateSubdirFormat(format)
+	if err != nil {
+		return err
+	}
+
+	s.subdirFormat = format
+
+	return nil
+}
+
+// layoutSubdir returns the directory an item is written to under the subdir
+// format, given the formatter's default directory for it. The month layouts
+// replace the calendar date directory; items without a creation time, or
+// without a source type under "source", keep the default.
+func (s *FileSink) layoutSubdir(item models.FullItem, defaultDir string) string {
+	switch s.subdirFormat {
+	case SubdirYearMonth, SubdirYearDashMonth:
+		created := item.GetCreatedAt()
+		if created.IsZero() {
+			return defaultDir
+		}
+
+		created = s.dates.in(created)
+		if s.subdirFormat == SubdirYearMonth {
+			return filepath.Join(created.Format("2006"), created.Format("01"))
+		}
+
+		return created.Format("2006-01")
+	case SubdirSource:
+		if item.GetSourceType() == "" {
+			return defaultDir
+		}
+
+		return filepath.Join(utils.SanitizeFilename(item.GetSourceType()), defaultDir)
+	default:
+		return defaultDir
+	}
+}
@@ -0,0 +1,67 @@
+package sinks
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"pkm-sync/pkg/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func januaryEvent() models.FullItem {
+	item := makeTestItem("evt-1", "Planning", "Agenda")
+	item.SetSourceType("google_calendar")
+	item.SetCreatedAt(time.Date(2024, 1, 20, 15, 0, 0, 0, time.UTC))
+	item.SetMetadata(map[string]interface{}{"start_time": "2024-02-05T10:00:00Z"})
+
+	return item
+}
+
+func TestSubdirFormat_Paths(t *testing.T) {
+	tests := []struct {
+		format string
+		want   string
+	}{
+		{SubdirYearMonth, filepath.Join("2024", "01", "Planning.md")},
+		{SubdirYearDashMonth, filepath.Join("2024-01", "Planning.md")},
+		{SubdirSource, filepath.Join("google_calendar", "2024", "02-February", "05-Monday", "Planning.md")},
+		{SubdirFlat, filepath.Join("2024", "02-February", "05-Monday", "Planning.md")},
+		{"", filepath.Join("2024", "02-February", "05-Monday", "Planning.md")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			sink, dir := newTestFileSink(t)
+			require.NoError(t, sink.SetSubdirFormat(tt.format))
+
+			path, err := sink.PathFor(januaryEvent())
+			require.NoError(t, err)
+			assert.Equal(t, filepath.Join(dir, tt.want), path)
+
+			previews, err := sink.Preview([]models.FullItem{januaryEvent()})
+			require.NoError(t, err)
+			require.Len(t, previews, 1)
+			assert.Equal(t, path, previews[0].FilePath, "preview must match the written path")
+		})
+	}
+}
+
+func TestSubdirFormat_UndatedItemKeepsDefault(t *testing.T) {
+	sink, dir := newTestFileSink(t)
+	require.NoError(t, sink.SetSubdirFormat(SubdirYearMonth))
+
+	item := makeTestItem("n-1", "Note", "Body")
+	item.SetCreatedAt(time.Time{})
+
+	path, err := sink.PathFor(item)
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(dir, "Note.md"), path)
+}
+
+func TestSubdirFormat_Invalid(t *testing.T) {
+	sink, _ := newTestFileSink(t)
+	assert.Error(t, sink.SetSubdirFormat("yyyy"))
+}
@@ -0,0 +1,112 @@
+package sinks
+
+import (
+	"strings"
+	"unicode"
+)
+
+// defaultTagHierarchySeparator is the separator sources and transformers use
+// between the levels of a tag ("priority:high", "source:gmail").
+const defaultTagHierarchySeparator = ":"
+
+// tagHierarchy splits item tags into their levels, so each formatter can join
+// them with its target's nesting syntax. "/" always nests, as it does in both
+// Obsidian and Logseq; separator (default ":") nests as well.
+type tagHierarchy struct {
+	separator string
+}
+
+// configureTagHierarchy reads the tag_hierarchy_separator formatter config
+// key. "/" keeps other characters, such as ":", inside a level.
+func configureTagHierarchy(config map[string]any) tagHierarchy {
+	if separator, ok := config["tag_hierarchy_separator"].(string); ok && separator != "" {
+		return tagHierarchy{separator: separator}
+	}
+
+	return tagHierarchy{separator: defaultTagHierarchySeparator}
+}
+
+// levels returns the trimmed, non-empty levels of tag.
+func (h tagHierarchy) levels(tag string) []string {
+	tag = strings.TrimPrefix(strings.TrimSpace(tag), "#")
+	if h.separator != "" && h.separator != "/" {
+		tag = strings.ReplaceAll(tag, h.separator, "/")
+	}
+
+	var levels []string
+
+	for _, level := range strings.Split(tag, "/") {
+		if level = strings.TrimSpace(level); level != "" {
+			levels = append(levels, level)
+		}
+	}
+
+	return levels
+}
+
+// obsidianTag renders tag as an Obsidian tag without the leading "#", as
+// frontmatter lists it. Obsidian tags may only hold letters, digits, "_", "-"
+// and "/", so spaces become "-" and other characters are dropped. It returns
+// "" when nothing usable is left.
+func (h tagHierarchy) obsidianTag(tag string) string {
+	var levels []string
+
+	for _, level := range h.levels(tag) {
+		level = strings.Join(strings.Fields(level), "-")
+		level = strings.Map(func(r rune) rune {
+			if unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' || r == '-' {
+				return r
+			}
+
+			return -1
+		}, level)
+
+		if level != "" {
+			levels = append(levels, level)
+		}
+	}
+
+	return strings.Join(levels, "/")
+}
+
+// logseqTag renders tag as a Logseq tag, with levels as "/" namespaces. Tags
+// Logseq would cut short (spaces, commas, brackets) use the #[[...]] form.
+func (h tagHierarchy) logseqTag(tag string) string {
+	levels := h.levels(tag)
+	for i, level := range levels {
+		levels[i] = strings.Join(strings.Fields(level), " ")
+	}
+
+	name := strings.Join(levels, "/")
+	if name == "" {
+		return ""
+	}
+
+	if strings.ContainsAny(name, " \t,;[]#\"'") {
+		name = strings.NewReplacer("[[", "", "]]", "").Replace(name)
+
+		return "#[[" + name + "]]"
+	}
+
+	return "#" + name
+}
+
+// renderTags renders each tag with render, dropping empty results and
+// duplicates that only differed in characters the target cannot show.
+func renderTags(tags []string, render func(string) string) []string {
+	var rendered []string
+
+	seen := make(map[string]bool, len(tags))
+
+	for _, tag := range tags {
+		out := render(tag)
+		if out == "" || seen[out] {
+			continue
+		}
+
+		seen[out] = true
+		rendered = append(rendered, out)
+	}
+
+	return rendered
+}
@@ -0,0 +1,57 @@
+package sinks
+
+import (
+	"testing"
+	"time"
+
+	"pkm-sync/pkg/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var sharedTags = []string{"gmail", "priority:high", "source/work", "Project Apollo", "status:In Review", "a, b"}
+
+func TestFormatTags_SameTagsDifferPerTarget(t *testing.T) {
+	obsidian := newObsidianFormatter().formatTags(sharedTags)
+	logseq := newLogseqFormatter().formatTags(sharedTags)
+
+	assert.Equal(t, []string{
+		"gmail", "priority/high", "source/work", "Project-Apollo", "status/In-Review", "a-b",
+	}, obsidian)
+	assert.Equal(t, []string{
+		"#gmail", "#priority/high", "#source/work", "#[[Project Apollo]]", "#[[status/In Review]]", "#[[a, b]]",
+	}, logseq)
+}
+
+func TestFormatTags_HierarchySeparator(t *testing.T) {
+	o := newObsidianFormatter()
+	o.configure(map[string]any{"tag_hierarchy_separator": "."})
+
+	l := newLogseqFormatter()
+	l.configure(map[string]any{"tag_hierarchy_separator": "/"})
+
+	assert.Equal(t, []string{"team/infra/oncall", "priorityhigh"},
+		o.formatTags([]string{"team.infra.oncall", "priority:high"}))
+	assert.Equal(t, []string{"#team.infra", "#priority:high"}, l.formatTags([]string{"team.infra", "priority:high"}))
+}
+
+func TestFormatTags_DropsEmptyAndDuplicates(t *testing.T) {
+	tags := []string{"#urgent", "urgent", "???", " : ", "work:", "work"}
+
+	assert.Equal(t, []string{"urgent", "work"}, newObsidianFormatter().formatTags(tags))
+	assert.Equal(t, []string{"#urgent", "#???", "#work"}, newLogseqFormatter().formatTags(tags))
+}
+
+func TestFormatContent_RendersTargetTags(t *testing.T) {
+	item := &models.BasicItem{
+		ID:         "msg-1",
+		Title:      "Launch review",
+		SourceType: "gmail",
+		ItemType:   "email",
+		CreatedAt:  time.Date(2026, 4, 16, 12, 0, 0, 0, time.UTC),
+		Tags:       []string{"priority:high", "Project Apollo"},
+	}
+
+	assert.Contains(t, newObsidianFormatter().formatContent(item), "tags:\n  - priority/high\n  - Project-Apollo\n")
+	assert.Contains(t, newLogseqFormatter().formatContent(item), "- tags:: #priority/high, #[[Project Apollo]]\n")
+}
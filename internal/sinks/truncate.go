@@ -0,0 +1,110 @@
+package sinks
+
+import (
+	"log/slog"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"unicode/utf8"
+
+	"pkm-sync/internal/archive"
+	"pkm-sync/pkg/models"
+)
+
+// archiveLinkText labels the link appended to truncated notes.
+const archiveLinkText = "Full content in archive"
+
+// ArchiveLocator finds the archived copy of an item by its ID.
+type ArchiveLocator interface {
+	ArchivedPath(itemID string) (path string, ok bool)
+}
+
+// storeLocator looks items up in the Gmail archive index.
+type storeLocator struct {
+	store *archive.Store
+}
+
+// NewArchiveLocator returns an ArchiveLocator backed by the archive store.
+func NewArchiveLocator(store *archive.Store) ArchiveLocator {
+	return storeLocator{store: store}
+}
+
+// ArchivedPath returns the .eml file the item was archived to.
+func (l storeLocator) ArchivedPath(itemID string) (string, bool) {
+	path, found, err := l.store.EMLPath(itemID)
+	if err != nil {
+		slog.Warn("Failed to look up archived item; exporting full content", "id", itemID, "error", err)
+
+		return "", false
+	}
+
+	return path, found
+}
+
+// archiveTruncation cuts note bodies longer than maxChars, linking to the
+// archived original. Items without an archived copy are exported in full.
+type archiveTruncation struct {
+	maxChars int
+	locator  ArchiveLocator
+}
+
+// apply returns item unchanged, or a shallow copy whose content is truncated
+// with an archive link. The item passed in is never modified, so sinks that
+// run after the file sink still see the full content.
+func (t *archiveTruncation) apply(item models.FullItem) models.FullItem {
+	if t == nil || t.maxChars <= 0 || t.locator == nil {
+		return item
+	}
+
+	content := item.GetContent()
+	if utf8.RuneCountInString(content) <= t.maxChars {
+		return item
+	}
+
+	// Threads are rendered from their messages and never archived whole.
+	basic, ok := item.(*models.BasicItem)
+	if !ok {
+		return item
+	}
+
+	path, ok := t.locator.ArchivedPath(item.GetID())
+	if !ok {
+		return item
+	}
+
+	truncated := *basic
+	truncated.Content = truncateContent(content, t.maxChars) + "\n\n…\n\n" + archiveLink(path)
+
+	return &truncated
+}
+
+// truncateContent keeps at most maxChars runes of content, backing up to the
+// last line break or space in the second half so words are not split.
+func truncateContent(content string, maxChars int) string {
+	cut := len(content)
+
+	for i := range content {
+		if maxChars == 0 {
+			cut = i
+
+			break
+		}
+
+		maxChars--
+	}
+
+	kept := content[:cut]
+
+	if i := strings.LastIndexAny(kept, "\n "); i > len(kept)/2 {
+		kept = kept[:i]
+	}
+
+	return strings.TrimRight(kept, " \t\n")
+}
+
+// archiveLink renders a markdown link to an archived file.
+func archiveLink(path string) string {
+	link := url.URL{Scheme: "file", Path: filepath.ToSlash(path)}
+
+	return "[" + archiveLinkText + "](" + link.String() + ")"
+}
@@ -0,0 +1,114 @@
+package sinks
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"pkm-sync/internal/archive"
+	"pkm-sync/pkg/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mapLocator is an ArchiveLocator backed by a map of item ID to path.
+type mapLocator map[string]string
+
+func (m mapLocator) ArchivedPath(itemID string) (string, bool) {
+	path, ok := m[itemID]
+
+	return path, ok
+}
+
+func TestArchiveTruncation_TruncatesArchivedItemWithLink(t *testing.T) {
+	sink, dir := newTestFileSink(t)
+	sink.SetArchiveTruncation(40, mapLocator{"msg-1": "/data/archive/eml/work/msg-1.eml"})
+
+	body := strings.Repeat("lorem ipsum ", 20)
+	item := makeTestItem("msg-1", "Long Mail", body)
+
+	require.NoError(t, sink.Write(context.Background(), []models.FullItem{item}))
+
+	data, err := os.ReadFile(filepath.Join(dir, sink.fmt.formatFilename("Long Mail")))
+	require.NoError(t, err)
+
+	note := string(data)
+	assert.Contains(t, note, "lorem ipsum lorem ipsum lorem ipsum\n\n…\n\n")
+	assert.Contains(t, note, "[Full content in archive](file:///data/archive/eml/work/msg-1.eml)")
+	assert.NotContains(t, note, body)
+
+	// Later sinks (vector, archive) must still see the full body.
+	assert.Equal(t, body, item.GetContent())
+}
+
+func TestArchiveTruncation_KeepsItemsWithoutArchiveOrShortBody(t *testing.T) {
+	sink, dir := newTestFileSink(t)
+	sink.SetArchiveTruncation(40, mapLocator{"short": "/archive/short.eml"})
+
+	long := strings.Repeat("not archived ", 10)
+	items := []models.FullItem{
+		makeTestItem("unarchived", "Unarchived", long),
+		makeTestItem("short", "Short", "A brief note."),
+	}
+
+	require.NoError(t, sink.Write(context.Background(), items))
+
+	for _, item := range items {
+		data, err := os.ReadFile(filepath.Join(dir, sink.fmt.formatFilename(item.GetTitle())))
+		require.NoError(t, err)
+		assert.Contains(t, string(data), item.GetContent())
+		assert.NotContains(t, string(data), archiveLinkText)
+	}
+}
+
+func TestArchiveTruncation_SkipsThreads(t *testing.T) {
+	truncation := &archiveTruncation{maxChars: 5, locator: mapLocator{"t1": "/archive/t1.eml"}}
+
+	thread := models.NewThread("t1", "Thread")
+	thread.SetContent("a thread summary that is long")
+
+	assert.Same(t, thread, truncation.apply(thread))
+}
+
+func TestTruncateContent(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		maxChars int
+		want     string
+	}{
+		{"breaks at word", "alpha beta gamma delta", 13, "alpha beta"},
+		{"breaks at line", "first line\nsecond line", 15, "first line"},
+		{"multibyte runes kept whole", "ééééééééé", 4, "éééé"},
+		{"no break in second half", "abcdefghij klm", 8, "abcdefgh"},
+		{"shorter than limit", "short", 10, "short"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, truncateContent(tt.content, tt.maxChars))
+		})
+	}
+}
+
+func TestArchiveLocator_UsesStorePaths(t *testing.T) {
+	store, err := archive.NewStore(filepath.Join(t.TempDir(), "archive.db"))
+	require.NoError(t, err)
+
+	t.Cleanup(func() { store.Close() })
+
+	emlPath := filepath.Join(t.TempDir(), "work", "m1.eml")
+	require.NoError(t, store.IndexMessage(archive.Message{GmailID: "m1", EMLPath: emlPath, SourceName: "work"}, "body"))
+
+	locator := NewArchiveLocator(store)
+
+	path, ok := locator.ArchivedPath("m1")
+	assert.True(t, ok)
+	assert.Equal(t, emlPath, path)
+
+	_, ok = locator.ArchivedPath("missing")
+	assert.False(t, ok)
+}
@@ -6,6 +6,7 @@ import (
 	"log/slog"
 	"sort"
 	"strings"
+	"sync"
 	"time"
 
 	"pkm-sync/internal/embeddings"
@@ -17,9 +18,10 @@ import (
 type VectorSinkConfig struct {
 	DBPath        string
 	Reindex       bool
-	Delay         int // milliseconds between embeddings (or between batches when BatchSize > 1)
+	Delay         int // milliseconds between embeddings (or batches) per worker
 	MaxContentLen int // 0 = no limit
-	BatchSize     int // documents per EmbedBatch call; 0 or 1 = single-embed mode
+	BatchSize     int // documents per EmbedBatch call; 0 = provider default (16 for openai, else 1)
+	Concurrency   int // parallel embedding workers; 0 or 1 = sequential
 	EmbeddingsCfg models.EmbeddingsConfig
 }
 
@@ -29,6 +31,7 @@ type VectorSink struct {
 	store    *vectorstore.Store
 	provider embeddings.Provider
 	cfg      VectorSinkConfig
+	mu       sync.Mutex // serializes store upserts across embedding workers
 }
 
 // NewVectorSink creates a VectorSink, opening the store and (optionally) the
@@ -38,7 +41,7 @@ type VectorSink struct {
 // allows timestamp-based incremental sync inference even without embeddings.
 // The caller is responsible for calling Close() when done.
 func NewVectorSink(cfg VectorSinkConfig) (*VectorSink, error) {
-	provider, err := embeddings.NewProvider(cfg.EmbeddingsCfg)
+	provider, err := embeddings.NewProvider(&cfg.EmbeddingsCfg)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create embedding provider: %w", err)
 	}
@@ -134,31 +137,31 @@ func (s *VectorSink) indexSource(
 	slog.Info("Source grouped", "source", sourceName, "items", len(items), "groups", len(groups))
 
 	// Get already-indexed threads unless reindex is requested
-	var indexedThreads map[string]bool
+	var indexedThreads map[string]vectorstore.IndexedThread
 
 	if !s.cfg.Reindex {
-		indexedThreads, err = s.store.GetIndexedThreadIDs(sourceName)
+		indexedThreads, err = s.store.GetIndexedThreads(sourceName)
 		if err != nil {
 			return 0, 0, 0, 0, fmt.Errorf("failed to get indexed threads: %w", err)
 		}
 
 		slog.Info("Source already indexed", "source", sourceName, "count", len(indexedThreads))
-	} else {
-		indexedThreads = make(map[string]bool)
 	}
 
-	// Build list of documents to process, skipping already-indexed ones.
+	// Build list of documents to process, skipping threads whose content has
+	// not changed since they were indexed.
 	pending := make([]pendingDoc, 0, len(groups))
 
 	for threadID, group := range groups {
-		if indexedThreads[threadID] && !s.cfg.Reindex {
+		content := builder.buildContent(group)
+		hash := contentFingerprint(content)
+
+		if indexed, ok := indexedThreads[threadID]; ok && threadUnchanged(indexed, hash, len(group.messages)) {
 			skipped++
 
 			continue
 		}
 
-		content := builder.buildContent(group)
-
 		originalLen := len(content)
 		if s.cfg.MaxContentLen > 0 && len(content) > s.cfg.MaxContentLen {
 			content = content[:s.cfg.MaxContentLen] + "\n\n[Content truncated for indexing]"
@@ -182,6 +185,7 @@ func (s *VectorSink) indexSource(
 			Metadata:     metadata,
 			CreatedAt:    group.startTime,
 			UpdatedAt:    group.endTime,
+			ContentHash:  hash,
 		}
 
 		pending = append(pending, pendingDoc{
@@ -194,59 +198,124 @@ func (s *VectorSink) indexSource(
 	}
 
 	batchSize := s.cfg.BatchSize
+	if batchSize == 0 {
+		batchSize = embeddings.DefaultBatchSize(s.cfg.EmbeddingsCfg.Provider)
+	}
+
 	if batchSize <= 1 || s.provider == nil {
 		batchSize = 1
 	}
 
-	for i := 0; i < len(pending); i += batchSize {
-		end := i + batchSize
-		if end > len(pending) {
-			end = len(pending)
-		}
+	counts := s.embedAndUpsert(ctx, pending, batchSize)
 
-		batch := pending[i:end]
+	return counts.indexed, counts.metadataOnly, skipped, counts.failed, nil
+}
 
-		// Apply rate limiting between batches (not before the first batch).
-		if s.provider != nil && s.cfg.Delay > 0 && i > 0 {
-			time.Sleep(time.Duration(s.cfg.Delay) * time.Millisecond)
-		}
+// threadUnchanged reports whether an indexed thread still matches its content.
+// Rows indexed before content hashes were stored are re-indexed only when the
+// thread has gained messages, so upgrading does not re-embed everything.
+func threadUnchanged(indexed vectorstore.IndexedThread, hash string, messageCount int) bool {
+	if indexed.ContentHash == "" {
+		return indexed.MessageCount >= messageCount
+	}
 
-		// Log progress every 10 documents processed.
-		if i > 0 && i%10 == 0 {
-			slog.Info("Indexing progress",
-				"indexed", indexed,
-				"metadata_only", metadataOnly,
-				"skipped", skipped,
-				"failed", failed)
-		}
+	return indexed.ContentHash == hash
+}
 
-		// Generate embeddings for the batch.
-		batchEmbeddings := s.embedBatch(ctx, batch, i)
+// indexCounts tallies upsert outcomes; guarded by VectorSink.mu during concurrent indexing.
+type indexCounts struct {
+	indexed      int
+	metadataOnly int
+	failed       int
+}
 
-		// Upsert each document in the batch.
-		for j, p := range batch {
-			var embedding []float32
-			if j < len(batchEmbeddings) {
-				embedding = batchEmbeddings[j]
-			}
+// embedAndUpsert embeds pending documents in batches across up to
+// cfg.Concurrency workers. Each worker sleeps cfg.Delay between its own
+// batches, so the delay acts as a per-worker rate limit. Upserts are
+// serialized because SQLite allows a single writer.
+func (s *VectorSink) embedAndUpsert(ctx context.Context, pending []pendingDoc, batchSize int) indexCounts {
+	var counts indexCounts
 
-			if upsertErr := s.store.UpsertDocument(p.doc, embedding); upsertErr != nil {
-				slog.Warn("Failed to index document", "thread_id", p.threadID, "error", upsertErr)
+	workers := s.cfg.Concurrency
+	if workers < 1 || s.provider == nil {
+		workers = 1
+	}
 
-				failed++
+	starts := make(chan int)
 
-				continue
-			}
+	var wg sync.WaitGroup
+
+	for range workers {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			first := true
+
+			for start := range starts {
+				// Apply rate limiting between this worker's batches (not before its first).
+				if s.provider != nil && s.cfg.Delay > 0 && !first {
+					time.Sleep(time.Duration(s.cfg.Delay) * time.Millisecond)
+				}
+
+				first = false
 
-			if len(embedding) > 0 {
-				indexed++
-			} else {
-				metadataOnly++
+				batch := pending[start:min(start+batchSize, len(pending))]
+
+				// Generate embeddings for the batch.
+				batchEmbeddings := s.embedBatch(ctx, batch, start)
+
+				s.upsertBatch(batch, batchEmbeddings, &counts)
 			}
+		}()
+	}
+
+	for start := 0; start < len(pending); start += batchSize {
+		starts <- start
+	}
+
+	close(starts)
+	wg.Wait()
+
+	return counts
+}
+
+// upsertBatch writes one embedded batch to the store and updates counts.
+func (s *VectorSink) upsertBatch(batch []pendingDoc, batchEmbeddings [][]float32, counts *indexCounts) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	before := counts.indexed + counts.metadataOnly + counts.failed
+
+	for j, p := range batch {
+		var embedding []float32
+		if j < len(batchEmbeddings) {
+			embedding = batchEmbeddings[j]
+		}
+
+		if upsertErr := s.store.UpsertDocument(p.doc, embedding); upsertErr != nil {
+			slog.Warn("Failed to index document", "thread_id", p.threadID, "error", upsertErr)
+
+			counts.failed++
+
+			continue
+		}
+
+		if len(embedding) > 0 {
+			counts.indexed++
+		} else {
+			counts.metadataOnly++
 		}
 	}
 
-	return indexed, metadataOnly, skipped, failed, nil
+	// Log progress every 10 documents processed.
+	if after := counts.indexed + counts.metadataOnly + counts.failed; after/10 > before/10 {
+		slog.Info("Indexing progress",
+			"indexed", counts.indexed,
+			"metadata_only", counts.metadataOnly,
+			"failed", counts.failed)
+	}
 }
 
 // embedBatch generates embeddings for a batch of pending documents.
@@ -257,18 +326,7 @@ func (s *VectorSink) embedBatch(ctx context.Context, batch []pendingDoc, batchId
 	}
 
 	if len(batch) == 1 {
-		embedding, embedErr := s.provider.Embed(ctx, batch[0].content)
-		if embedErr != nil {
-			slog.Warn("Failed to embed document",
-				"thread_id", batch[0].threadID,
-				"subject", batch[0].group.subject,
-				"chars", batch[0].originalLen,
-				"error", embedErr)
-
-			return [][]float32{nil}
-		}
-
-		return [][]float32{embedding}
+		return [][]float32{s.embedOne(ctx, batch[0])}
 	}
 
 	texts := make([]string, len(batch))
@@ -276,17 +334,40 @@ func (s *VectorSink) embedBatch(ctx context.Context, batch []pendingDoc, batchId
 		texts[j] = p.content
 	}
 
-	embeddings, embedErr := s.provider.EmbedBatch(ctx, texts)
-	if embedErr != nil {
-		slog.Warn("Failed to batch embed",
-			"batch_start", batchIdx,
-			"batch_size", len(batch),
-			"error", embedErr)
+	batchEmbeddings, embedErr := s.provider.EmbedBatch(ctx, texts)
+	if embedErr == nil {
+		return batchEmbeddings
+	}
+
+	// One oversized or rejected document fails the whole request; embed the
+	// batch one by one so only the documents that fail go metadata-only.
+	slog.Warn("Failed to batch embed; embedding documents one at a time",
+		"batch_start", batchIdx,
+		"batch_size", len(batch),
+		"error", embedErr)
+
+	batchEmbeddings = make([][]float32, len(batch))
+	for j, p := range batch {
+		batchEmbeddings[j] = s.embedOne(ctx, p)
+	}
+
+	return batchEmbeddings
+}
+
+// embedOne embeds a single document, returning nil (metadata-only) on error.
+func (s *VectorSink) embedOne(ctx context.Context, p pendingDoc) []float32 {
+	embedding, err := s.provider.Embed(ctx, p.content)
+	if err != nil {
+		slog.Warn("Failed to embed document",
+			"thread_id", p.threadID,
+			"subject", p.group.subject,
+			"chars", p.originalLen,
+			"error", err)
 
-		return make([][]float32, len(batch)) // all nil — fall back to metadata-only
+		return nil
 	}
 
-	return embeddings
+	return embedding
 }
 
 // Search performs a semantic search query against the vector store.
@@ -332,7 +413,7 @@ func (s *VectorSink) Close() error {
 	return nil
 }
 
-// groupBySource groups items by their source name (extracted from "source:" tags).
+// groupBySource groups items by their source name (see extractSourceName).
 func groupBySource(items []models.FullItem) map[string][]models.FullItem {
 	result := make(map[string][]models.FullItem)
 
@@ -344,8 +425,14 @@ func groupBySource(items []models.FullItem) map[string][]models.FullItem {
 	return result
 }
 
-// extractSourceName extracts the source name from item tags or falls back to source type.
+// extractSourceName returns the source name the syncer stamped in metadata.
+// Items without it (e.g. reprocessed from older exports) fall back to a
+// default-format "source:<name>" tag, then to the source type.
 func extractSourceName(item models.FullItem) string {
+	if name, ok := item.GetMetadata()[models.MetadataSourceName].(string); ok && name != "" {
+		return name
+	}
+
 	for _, tag := range item.GetTags() {
 		if rest, ok := strings.CutPrefix(tag, "source:"); ok {
 			return rest
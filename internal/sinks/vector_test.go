@@ -1,10 +1,16 @@
 package sinks
 
 import (
+	"context"
+	"fmt"
 	"os"
+	"path/filepath"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"pkm-sync/internal/vectorstore"
+	"pkm-sync/pkg/models"
 )
 
 // TestVectorSinkCloseNilProvider verifies that Close() does not panic when the
@@ -32,3 +38,245 @@ func TestVectorSinkCloseNilProvider(t *testing.T) {
 		t.Errorf("Close() returned unexpected error: %v", err)
 	}
 }
+
+// countingProvider returns fixed-size embeddings and counts concurrent calls.
+type countingProvider struct {
+	dims     int
+	calls    atomic.Int64
+	inFlight atomic.Int64
+	peak     atomic.Int64
+}
+
+func (p *countingProvider) Embed(_ context.Context, _ string) ([]float32, error) {
+	p.calls.Add(1)
+
+	n := p.inFlight.Add(1)
+	defer p.inFlight.Add(-1)
+
+	for {
+		peak := p.peak.Load()
+		if n <= peak || p.peak.CompareAndSwap(peak, n) {
+			break
+		}
+	}
+
+	time.Sleep(5 * time.Millisecond)
+
+	return make([]float32, p.dims), nil
+}
+
+func (p *countingProvider) EmbedBatch(ctx context.Context, texts []string) ([][]float32, error) {
+	result := make([][]float32, len(texts))
+
+	for i, text := range texts {
+		embedding, err := p.Embed(ctx, text)
+		if err != nil {
+			return nil, err
+		}
+
+		result[i] = embedding
+	}
+
+	return result, nil
+}
+
+func (p *countingProvider) Dimensions() int { return p.dims }
+
+func (p *countingProvider) Close() error { return nil }
+
+// TestVectorSinkConcurrentUpsertCounts verifies that every thread is upserted
+// exactly once when embeddings run across several workers.
+func TestVectorSinkConcurrentUpsertCounts(t *testing.T) {
+	const (
+		dims    = 4
+		threads = 37
+	)
+
+	tests := []struct {
+		name        string
+		concurrency int
+		batchSize   int
+	}{
+		{"sequential", 1, 1},
+		{"concurrent single embeds", 4, 1},
+		{"concurrent batches", 3, 5},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store, err := vectorstore.NewStore(filepath.Join(t.TempDir(), "vectors.db"), dims)
+			if err != nil {
+				t.Fatalf("failed to open store: %v", err)
+			}
+
+			provider := &countingProvider{dims: dims}
+			sink := &VectorSink{
+				store:    store,
+				provider: provider,
+				cfg:      VectorSinkConfig{Concurrency: tt.concurrency, BatchSize: tt.batchSize},
+			}
+			defer sink.Close()
+
+			items := make([]models.FullItem, 0, threads)
+
+			for i := range threads {
+				item := models.NewBasicItem(fmt.Sprintf("msg-%d", i), fmt.Sprintf("Subject %d", i))
+				item.SetContent(fmt.Sprintf("Body of message %d", i))
+				item.SetSourceType("gmail")
+				item.SetTags([]string{"source:gmail_test"})
+				item.SetMetadata(map[string]interface{}{"thread_id": fmt.Sprintf("thread-%d", i)})
+				items = append(items, item)
+			}
+
+			if err := sink.Write(context.Background(), items); err != nil {
+				t.Fatalf("Write failed: %v", err)
+			}
+
+			stats, err := sink.Stats()
+			if err != nil {
+				t.Fatalf("Stats failed: %v", err)
+			}
+
+			if stats.TotalDocuments != threads {
+				t.Errorf("expected %d documents, got %d", threads, stats.TotalDocuments)
+			}
+
+			if calls := provider.calls.Load(); calls != threads {
+				t.Errorf("expected %d embeddings, got %d", threads, calls)
+			}
+
+			if peak := provider.peak.Load(); peak > int64(tt.concurrency) {
+				t.Errorf("expected at most %d concurrent embeddings, saw %d", tt.concurrency, peak)
+			}
+		})
+	}
+}
+
+// rejectingProvider fails every batch request and single embeds of "bad".
+type rejectingProvider struct {
+	countingProvider
+	batches atomic.Int64
+}
+
+func (p *rejectingProvider) Embed(ctx context.Context, text string) ([]float32, error) {
+	if text == "bad" {
+		return nil, fmt.Errorf("input too long")
+	}
+
+	return p.countingProvider.Embed(ctx, text)
+}
+
+func (p *rejectingProvider) EmbedBatch(_ context.Context, _ []string) ([][]float32, error) {
+	p.batches.Add(1)
+
+	return nil, fmt.Errorf("input too long")
+}
+
+// TestVectorSinkFailedBatchFallsBackToSingleEmbeds verifies that one document
+// the provider rejects does not leave the rest of its batch unembedded.
+func TestVectorSinkFailedBatchFallsBackToSingleEmbeds(t *testing.T) {
+	provider := &rejectingProvider{countingProvider: countingProvider{dims: 2}}
+	sink := &VectorSink{provider: provider}
+
+	batch := []pendingDoc{
+		{threadID: "t1", group: &itemGroup{}, content: "good"},
+		{threadID: "t2", group: &itemGroup{}, content: "bad"},
+		{threadID: "t3", group: &itemGroup{}, content: "also good"},
+	}
+
+	got := sink.embedBatch(context.Background(), batch, 0)
+
+	if provider.batches.Load() != 1 {
+		t.Errorf("expected one batch request, got %d", provider.batches.Load())
+	}
+
+	if len(got) != 3 || len(got[0]) != 2 || got[1] != nil || len(got[2]) != 2 {
+		t.Errorf("expected embeddings for t1 and t3 only, got %v", got)
+	}
+}
+
+// TestVectorSinkReindexesGrownThread verifies that an unchanged thread is
+// skipped on the next run while a thread that gained a message is re-embedded
+// without Reindex.
+func TestVectorSinkReindexesGrownThread(t *testing.T) {
+	const dims = 4
+
+	store, err := vectorstore.NewStore(filepath.Join(t.TempDir(), "vectors.db"), dims)
+	if err != nil {
+		t.Fatalf("failed to open store: %v", err)
+	}
+
+	provider := &countingProvider{dims: dims}
+	sink := &VectorSink{store: store, provider: provider}
+	defer sink.Close()
+
+	message := func(i int) models.FullItem {
+		item := models.NewBasicItem(fmt.Sprintf("msg-%d", i), "Launch plan")
+		item.SetContent(fmt.Sprintf("Reply number %d", i))
+		item.SetSourceType("gmail")
+		item.SetCreatedAt(time.Date(2024, 5, 1, i, 0, 0, 0, time.UTC))
+		item.SetMetadata(map[string]interface{}{
+			"thread_id":               "thread-1",
+			models.MetadataSourceName: "gmail_work",
+		})
+
+		return item
+	}
+
+	runs := []struct {
+		name      string
+		items     []models.FullItem
+		wantCalls int64
+	}{
+		{"first index", []models.FullItem{message(1), message(2)}, 1},
+		{"unchanged thread is skipped", []models.FullItem{message(1), message(2)}, 1},
+		{"grown thread is re-embedded", []models.FullItem{message(1), message(2), message(3)}, 2},
+	}
+
+	for _, run := range runs {
+		if err := sink.Write(context.Background(), run.items); err != nil {
+			t.Fatalf("%s: Write failed: %v", run.name, err)
+		}
+
+		if calls := provider.calls.Load(); calls != run.wantCalls {
+			t.Errorf("%s: expected %d embeddings in total, got %d", run.name, run.wantCalls, calls)
+		}
+	}
+
+	indexed, err := store.GetIndexedThreads("gmail_work")
+	if err != nil {
+		t.Fatalf("GetIndexedThreads failed: %v", err)
+	}
+
+	if got := indexed["thread-1"]; got.MessageCount != 3 || got.ContentHash == "" {
+		t.Errorf("expected thread-1 stored with 3 messages and a content hash, got %+v", got)
+	}
+}
+
+func TestExtractSourceName_IndependentOfTagFormat(t *testing.T) {
+	tests := []struct {
+		name string
+		item *models.BasicItem
+		want string
+	}{
+		{
+			"metadata wins over namespaced tag",
+			&models.BasicItem{
+				SourceType: "gmail",
+				Tags:       []string{"sync/source/gmail_work"},
+				Metadata:   map[string]interface{}{models.MetadataSourceName: "gmail_work"},
+			},
+			"gmail_work",
+		},
+		{"legacy source tag", &models.BasicItem{SourceType: "gmail", Tags: []string{"source:gmail_home"}}, "gmail_home"},
+		{"namespaced tag without metadata", &models.BasicItem{SourceType: "slack", Tags: []string{"sync/source/x"}}, "slack"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := extractSourceName(tt.item); got != tt.want {
+				t.Errorf("extractSourceName() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,284 @@
+package github
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"pkm-sync/internal/httpclient"
+)
+
+const (
+	// defaultBaseURL is the GitHub REST API.
+	defaultBaseURL = "https://api.github.com"
+	// apiVersion is the X-GitHub-Api-Version header the client speaks.
+	apiVersion = "2022-11-28"
+	// defaultRequestTimeout bounds a single GitHub API request when no
+	// request_timeout is configured.
+	defaultRequestTimeout = 30 * time.Second
+	// perPage is the largest page the issues endpoints return.
+	perPage = 100
+	// maxRetries and maxRetryDelay bound the backoff on rate limits and
+	// server errors.
+	maxRetries    = 3
+	maxRetryDelay = 30 * time.Second
+)
+
+// Client is an HTTP client for the GitHub REST API.
+type Client struct {
+	token      string
+	baseURL    string
+	httpClient *http.Client
+	// retryBaseDelay is the first backoff delay; tests shorten it.
+	retryBaseDelay time.Duration
+}
+
+// NewClient creates a GitHub API client. An empty token makes anonymous
+// requests, which only see public repositories.
+func NewClient(token string) *Client {
+	return &Client{
+		token:          token,
+		baseURL:        defaultBaseURL,
+		httpClient:     &http.Client{Timeout: defaultRequestTimeout, Transport: httpclient.SharedTransport()},
+		retryBaseDelay: time.Second,
+	}
+}
+
+// issue is a GitHub issue or pull request as returned by the issues
+// endpoints; PullRequest is set for pull requests.
+type issue struct {
+	Number      int       `json:"number"`
+	Title       string    `json:"title"`
+	Body        string    `json:"body"`
+	State       string    `json:"state"`
+	HTMLURL     string    `json:"html_url"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+	ClosedAt    time.Time `json:"closed_at"`
+	Comments    int       `json:"comments"`
+	User        user      `json:"user"`
+	Assignees   []user    `json:"assignees"`
+	Labels      []label   `json:"labels"`
+	PullRequest *struct {
+		MergedAt *time.Time `json:"merged_at"`
+	} `json:"pull_request"`
+	Repository *struct {
+		FullName string `json:"full_name"`
+	} `json:"repository"`
+}
+
+type user struct {
+	Login string `json:"login"`
+}
+
+type label struct {
+	Name string `json:"name"`
+}
+
+// IssueQuery filters an issues listing.
+type IssueQuery struct {
+	Since    time.Time
+	Assignee string
+	Labels   []string
+}
+
+func (q IssueQuery) values() url.Values {
+	params := url.Values{}
+	params.Set("state", "all")
+	params.Set("sort", "updated")
+	params.Set("direction", "desc")
+	params.Set("per_page", strconv.Itoa(perPage))
+
+	if !q.Since.IsZero() {
+		params.Set("since", q.Since.UTC().Format(time.RFC3339))
+	}
+
+	if len(q.Labels) > 0 {
+		params.Set("labels", strings.Join(q.Labels, ","))
+	}
+
+	return params
+}
+
+// RepoIssues returns the issues and pull requests of repo ("owner/name")
+// updated at or after q.Since, most recently updated first, up to limit
+// (0 = all).
+func (c *Client) RepoIssues(repo string, q IssueQuery, limit int) ([]issue, error) {
+	params := q.values()
+	if q.Assignee != "" {
+		params.Set("assignee", q.Assignee)
+	}
+
+	return c.listIssues("/repos/"+repo+"/issues?"+params.Encode(), limit)
+}
+
+// AssignedIssues returns the issues and pull requests assigned to the
+// authenticated user across all repositories they can see.
+func (c *Client) AssignedIssues(q IssueQuery, limit int) ([]issue, error) {
+	params := q.values()
+	params.Set("filter", "assigned")
+
+	return c.listIssues("/issues?"+params.Encode(), limit)
+}
+
+// listIssues follows page numbers until a short page or limit is reached.
+func (c *Client) listIssues(path string, limit int) ([]issue, error) {
+	var issues []issue
+
+	for pageNum := 1; limit <= 0 || len(issues) < limit; pageNum++ {
+		var page []issue
+		if err := c.get(path+"&page="+strconv.Itoa(pageNum), &page); err != nil {
+			return nil, err
+		}
+
+		issues = append(issues, page...)
+
+		if len(page) < perPage {
+			break
+		}
+	}
+
+	if limit > 0 && len(issues) > limit {
+		issues = issues[:limit]
+	}
+
+	return issues, nil
+}
+
+// apiError is a non-2xx GitHub response.
+type apiError struct {
+	StatusCode int
+	Message    string
+	// RetryAfter is how long GitHub asked the client to wait, when it said.
+	RetryAfter time.Duration
+	rateLimit  bool
+}
+
+func (e *apiError) Error() string {
+	if e.rateLimit {
+		return fmt.Sprintf("GitHub API rate limit exceeded (HTTP %d): %s", e.StatusCode, e.Message)
+	}
+
+	return fmt.Sprintf("GitHub API returned HTTP %d: %s", e.StatusCode, e.Message)
+}
+
+// get sends a GET request, retrying rate limits and server errors, and
+// decodes the JSON response into out.
+func (c *Client) get(path string, out any) error {
+	return c.executeWithRetry(func() error {
+		return c.doGet(path, out)
+	})
+}
+
+// executeWithRetry runs fn with exponential backoff on rate limits (HTTP 403
+// with no remaining quota, 429) and server errors. A Retry-After or
+// X-RateLimit-Reset the response carried replaces the backoff delay, capped
+// at 30 seconds.
+func (c *Client) executeWithRetry(fn func() error) error {
+	var lastErr error
+
+	for attempt := 0; attempt < maxRetries; attempt++ {
+		if attempt > 0 {
+			delay := c.retryBaseDelay * time.Duration(1<<uint(attempt-1))
+
+			var apiErr *apiError
+			if errors.As(lastErr, &apiErr) && apiErr.RetryAfter > 0 {
+				delay = apiErr.RetryAfter
+			}
+
+			delay = min(delay, maxRetryDelay)
+
+			slog.Info("Retrying GitHub API call", "delay", delay, "attempt", attempt+1, "max_retries", maxRetries)
+			time.Sleep(delay)
+		}
+
+		err := fn()
+		if err == nil {
+			return nil
+		}
+
+		lastErr = err
+
+		var apiErr *apiError
+		if !errors.As(err, &apiErr) || !apiErr.retryable() {
+			return err
+		}
+	}
+
+	return fmt.Errorf("max retries (%d) exceeded, last error: %w", maxRetries, lastErr)
+}
+
+func (e *apiError) retryable() bool {
+	return e.rateLimit || e.StatusCode >= 500
+}
+
+func (c *Client) doGet(path string, out any) error {
+	req, err := http.NewRequest(http.MethodGet, strings.TrimRight(c.baseURL, "/")+path, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("X-GitHub-Api-Version", apiVersion)
+
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("request failed: %w", err)
+	}
+
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return newAPIError(resp, data)
+	}
+
+	if err := json.Unmarshal(data, out); err != nil {
+		return fmt.Errorf("failed to parse GitHub response: %w", err)
+	}
+
+	return nil
+}
+
+// newAPIError describes a failed response. GitHub signals an exhausted rate
+// limit with 429, or with 403 and X-RateLimit-Remaining: 0.
+func newAPIError(resp *http.Response, body []byte) *apiError {
+	var payload struct {
+		Message string `json:"message"`
+	}
+
+	message := strings.TrimSpace(string(body))
+	if json.Unmarshal(body, &payload) == nil && payload.Message != "" {
+		message = payload.Message
+	}
+
+	e := &apiError{StatusCode: resp.StatusCode, Message: message}
+
+	e.rateLimit = resp.StatusCode == http.StatusTooManyRequests ||
+		(resp.StatusCode == http.StatusForbidden && resp.Header.Get("X-RateLimit-Remaining") == "0")
+
+	if secs, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && secs > 0 {
+		e.RetryAfter = time.Duration(secs) * time.Second
+	} else if reset, err := strconv.ParseInt(resp.Header.Get("X-RateLimit-Reset"), 10, 64); err == nil && e.rateLimit {
+		if wait := time.Until(time.Unix(reset, 0)); wait > 0 {
+			e.RetryAfter = wait
+		}
+	}
+
+	return e
+}
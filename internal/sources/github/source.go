@@ -0,0 +1,230 @@
+// Package github implements a source that syncs GitHub issues and pull
+// requests, from listed repositories or everything assigned to the token's
+// user, as notes.
+package github
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"pkm-sync/internal/httpclient"
+	"pkm-sync/pkg/interfaces"
+	"pkm-sync/pkg/models"
+)
+
+const (
+	// SourceType is the config type of the GitHub source.
+	SourceType = "github"
+	// tokenEnvVar supplies the token when the config has none.
+	tokenEnvVar = "GITHUB_TOKEN"
+
+	itemTypeIssue       = "issue"
+	itemTypePullRequest = "pull_request"
+)
+
+// GitHubSource implements interfaces.Source for GitHub issues and pull
+// requests.
+type GitHubSource struct {
+	sourceID       string
+	cfg            models.GitHubSourceConfig
+	client         *Client
+	transport      *httpclient.Transport
+	requestTimeout time.Duration
+}
+
+// NewGitHubSource creates a new GitHubSource from a SourceConfig.
+func NewGitHubSource(sourceID string, sourceCfg models.SourceConfig) *GitHubSource {
+	return &GitHubSource{
+		sourceID:       sourceID,
+		cfg:            sourceCfg.GitHub,
+		requestTimeout: sourceCfg.RequestTimeout,
+	}
+}
+
+// Name implements interfaces.Source.
+func (s *GitHubSource) Name() string {
+	return s.sourceID
+}
+
+// SupportsRealtime implements interfaces.Source.
+func (s *GitHubSource) SupportsRealtime() bool {
+	return false
+}
+
+// Capabilities implements interfaces.Source. Issues are filtered on their
+// updated time.
+func (s *GitHubSource) Capabilities() interfaces.SourceCapabilities {
+	return interfaces.SourceCapabilities{SupportsIncremental: true}
+}
+
+// Configure implements interfaces.Source. The token comes from token, else
+// the GITHUB_TOKEN environment variable; it may be omitted only when repos
+// are listed, which then must be public.
+func (s *GitHubSource) Configure(_ map[string]any, _ *http.Client) error {
+	token := s.cfg.Token
+	if token == "" {
+		token = os.Getenv(tokenEnvVar)
+	}
+
+	if token == "" && len(s.cfg.Repos) == 0 {
+		return fmt.Errorf("no GitHub token for %s: set token or %s, or list public repos", s.sourceID, tokenEnvVar)
+	}
+
+	for _, repo := range s.cfg.Repos {
+		if owner, name, ok := strings.Cut(repo, "/"); !ok || owner == "" || name == "" || strings.Contains(name, "/") {
+			return fmt.Errorf("github source %s: repo %q must be owner/name", s.sourceID, repo)
+		}
+	}
+
+	s.client = NewClient(token)
+	s.client.httpClient, s.transport = httpclient.NewClient(
+		&http.Client{}, httpclient.ResolveTimeout(s.requestTimeout, defaultRequestTimeout),
+	)
+
+	return nil
+}
+
+// FetchContext implements interfaces.ContextSource by binding ctx to the API
+// client's transport for the duration of the fetch.
+func (s *GitHubSource) FetchContext(ctx context.Context, since time.Time, limit int) ([]models.FullItem, error) {
+	if s.transport != nil {
+		s.transport.Bind(ctx)
+		defer s.transport.Bind(nil)
+	}
+
+	return s.Fetch(since, limit)
+}
+
+// Fetch implements interfaces.Source. It returns the issues (and, with
+// include_prs, pull requests) of the configured repos updated at or after
+// since, most recently updated first. Without repos it returns those assigned
+// to the authenticated user.
+func (s *GitHubSource) Fetch(since time.Time, limit int) ([]models.FullItem, error) {
+	if s.client == nil {
+		return nil, fmt.Errorf("github source %s is not configured", s.sourceID)
+	}
+
+	query := IssueQuery{Since: since, Assignee: s.cfg.Assignee, Labels: s.cfg.Labels}
+
+	type repoIssue struct {
+		repo  string
+		issue issue
+	}
+
+	var found []repoIssue
+
+	add := func(repo string, issues []issue) {
+		for _, is := range issues {
+			if is.PullRequest != nil && !s.cfg.IncludePRs {
+				continue
+			}
+
+			if is.Repository != nil && is.Repository.FullName != "" {
+				repo = is.Repository.FullName
+			}
+
+			found = append(found, repoIssue{repo: repo, issue: is})
+		}
+	}
+
+	if len(s.cfg.Repos) == 0 {
+		issues, err := s.client.AssignedIssues(query, limit)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list assigned issues: %w", err)
+		}
+
+		add("", issues)
+	}
+
+	for _, repo := range s.cfg.Repos {
+		issues, err := s.client.RepoIssues(repo, query, limit)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list issues of %s: %w", repo, err)
+		}
+
+		add(repo, issues)
+	}
+
+	sort.SliceStable(found, func(i, j int) bool {
+		return found[i].issue.UpdatedAt.After(found[j].issue.UpdatedAt)
+	})
+
+	if limit > 0 && len(found) > limit {
+		found = found[:limit]
+	}
+
+	items := make([]models.FullItem, 0, len(found))
+	for _, f := range found {
+		items = append(items, issueToItem(f.repo, f.issue))
+	}
+
+	return items, nil
+}
+
+// issueToItem converts an issue or pull request to an item. The title is
+// prefixed with "name#number" so notes from different repos stay apart.
+func issueToItem(repo string, is issue) models.FullItem {
+	itemType := itemTypeIssue
+	if is.PullRequest != nil {
+		itemType = itemTypePullRequest
+	}
+
+	repoName := repo[strings.LastIndex(repo, "/")+1:]
+
+	item := &models.BasicItem{
+		ID:         fmt.Sprintf("github_%s_%d", strings.ReplaceAll(repo, "/", "_"), is.Number),
+		Title:      fmt.Sprintf("%s#%d %s", repoName, is.Number, is.Title),
+		Content:    strings.TrimSpace(is.Body),
+		SourceType: SourceType,
+		ItemType:   itemType,
+		CreatedAt:  is.CreatedAt,
+		UpdatedAt:  is.UpdatedAt,
+		Tags:       make([]string, 0, len(is.Labels)),
+		Metadata: map[string]any{
+			"repo":     repo,
+			"number":   is.Number,
+			"state":    is.State,
+			"author":   is.User.Login,
+			"comments": is.Comments,
+		},
+		Links: make([]models.Link, 0, 1),
+	}
+
+	for _, l := range is.Labels {
+		item.Tags = append(item.Tags, l.Name)
+	}
+
+	if len(is.Assignees) > 0 {
+		assignees := make([]string, len(is.Assignees))
+		for i, a := range is.Assignees {
+			assignees[i] = a.Login
+		}
+
+		item.Metadata["assignees"] = assignees
+	}
+
+	if !is.ClosedAt.IsZero() {
+		item.Metadata["closed_at"] = is.ClosedAt
+	}
+
+	if is.PullRequest != nil && is.PullRequest.MergedAt != nil {
+		item.Metadata["state"] = "merged"
+	}
+
+	if is.HTMLURL != "" {
+		item.Links = append(item.Links, models.Link{URL: is.HTMLURL, Title: item.Title, Type: "external"})
+	}
+
+	return item
+}
+
+// Ensure interface compliance.
+var (
+	_ interfaces.Source        = (*GitHubSource)(nil)
+	_ interfaces.ContextSource = (*GitHubSource)(nil)
+)
@@ -0,0 +1,214 @@
+package github
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"pkm-sync/pkg/models"
+)
+
+func issueJSON(number int, title, updated string, pr bool) map[string]any {
+	is := map[string]any{
+		"number": number, "title": title, "body": "Details for " + title, "state": "open",
+		"html_url":   "https://github.com/acme/widgets/issues/" + strconv.Itoa(number),
+		"created_at": "2026-10-01T09:00:00Z", "updated_at": updated,
+		"user":      map[string]any{"login": "sam"},
+		"assignees": []any{map[string]any{"login": "ada"}},
+		"labels":    []any{map[string]any{"name": "bug"}},
+	}
+
+	if pr {
+		is["pull_request"] = map[string]any{"merged_at": nil}
+	}
+
+	return is
+}
+
+func newTestSource(t *testing.T, srv *httptest.Server, cfg models.GitHubSourceConfig) *GitHubSource {
+	t.Helper()
+
+	s := NewGitHubSource("gh", models.SourceConfig{Type: SourceType, GitHub: cfg})
+	if err := s.Configure(nil, nil); err != nil {
+		t.Fatalf("Configure: %v", err)
+	}
+
+	s.client.baseURL = srv.URL
+	s.client.retryBaseDelay = time.Millisecond
+
+	return s
+}
+
+func TestFetch_RepoIssues(t *testing.T) {
+	var query atomic.Value
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/repos/acme/widgets/issues" || r.Header.Get("Authorization") != "Bearer secret" {
+			http.NotFound(w, r)
+
+			return
+		}
+
+		query.Store(r.URL.Query())
+		_ = json.NewEncoder(w).Encode([]any{
+			issueJSON(7, "Crash on save", "2026-10-05T10:00:00Z", false),
+			issueJSON(9, "Fix save", "2026-10-06T10:00:00Z", true),
+		})
+	}))
+	defer srv.Close()
+
+	s := newTestSource(t, srv, models.GitHubSourceConfig{
+		Token: "secret", Repos: []string{"acme/widgets"}, Assignee: "ada", Labels: []string{"bug", "p1"},
+	})
+
+	since := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+
+	items, err := s.Fetch(since, 0)
+	if err != nil {
+		t.Fatalf("Fetch: %v", err)
+	}
+
+	q := query.Load().(url.Values)
+	if q.Get("since") != "2026-10-01T00:00:00Z" || q.Get("assignee") != "ada" || q.Get("labels") != "bug,p1" {
+		t.Errorf("unexpected query %v", q)
+	}
+
+	if len(items) != 1 {
+		t.Fatalf("expected pull requests to be skipped, got %d items", len(items))
+	}
+
+	item := items[0]
+	if item.GetID() != "github_acme_widgets_7" || item.GetTitle() != "widgets#7 Crash on save" {
+		t.Errorf("unexpected ID/title %q %q", item.GetID(), item.GetTitle())
+	}
+
+	if item.GetItemType() != "issue" || item.GetContent() != "Details for Crash on save" {
+		t.Errorf("unexpected type/content %q %q", item.GetItemType(), item.GetContent())
+	}
+
+	meta := item.GetMetadata()
+	if meta["number"] != 7 || meta["state"] != "open" || meta["repo"] != "acme/widgets" {
+		t.Errorf("unexpected metadata %v", meta)
+	}
+
+	if !reflect.DeepEqual(item.GetTags(), []string{"bug"}) {
+		t.Errorf("tags = %v", item.GetTags())
+	}
+
+	if links := item.GetLinks(); len(links) != 1 || links[0].URL != "https://github.com/acme/widgets/issues/7" {
+		t.Errorf("links = %+v", links)
+	}
+}
+
+func TestFetch_IncludePRsAndAssigned(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/issues" || r.URL.Query().Get("filter") != "assigned" {
+			http.NotFound(w, r)
+
+			return
+		}
+
+		issue := issueJSON(3, "Old", "2026-10-02T10:00:00Z", false)
+		issue["repository"] = map[string]any{"full_name": "acme/api"}
+		pr := issueJSON(4, "New", "2026-10-07T10:00:00Z", true)
+		pr["repository"] = map[string]any{"full_name": "acme/web"}
+
+		_ = json.NewEncoder(w).Encode([]any{issue, pr})
+	}))
+	defer srv.Close()
+
+	s := newTestSource(t, srv, models.GitHubSourceConfig{Token: "secret", IncludePRs: true})
+
+	items, err := s.Fetch(time.Time{}, 0)
+	if err != nil {
+		t.Fatalf("Fetch: %v", err)
+	}
+
+	if len(items) != 2 {
+		t.Fatalf("expected 2 items, got %d", len(items))
+	}
+
+	if items[0].GetItemType() != "pull_request" || items[0].GetID() != "github_acme_web_4" {
+		t.Errorf("expected the newer pull request first, got %q %q", items[0].GetItemType(), items[0].GetID())
+	}
+
+	if items[1].GetMetadata()["repo"] != "acme/api" {
+		t.Errorf("repo metadata = %v", items[1].GetMetadata()["repo"])
+	}
+}
+
+func TestFetch_RetriesRateLimit(t *testing.T) {
+	var calls atomic.Int32
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		if calls.Add(1) == 1 {
+			w.Header().Set("X-RateLimit-Remaining", "0")
+			http.Error(w, `{"message":"API rate limit exceeded"}`, http.StatusForbidden)
+
+			return
+		}
+
+		_ = json.NewEncoder(w).Encode([]any{issueJSON(1, "One", "2026-10-05T10:00:00Z", false)})
+	}))
+	defer srv.Close()
+
+	s := newTestSource(t, srv, models.GitHubSourceConfig{Repos: []string{"acme/widgets"}})
+
+	items, err := s.Fetch(time.Time{}, 0)
+	if err != nil {
+		t.Fatalf("Fetch: %v", err)
+	}
+
+	if len(items) != 1 || calls.Load() != 2 {
+		t.Errorf("expected one retry, got %d calls and %d items", calls.Load(), len(items))
+	}
+}
+
+func TestFetch_DoesNotRetryForbidden(t *testing.T) {
+	var calls atomic.Int32
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		calls.Add(1)
+		http.Error(w, `{"message":"Resource not accessible"}`, http.StatusForbidden)
+	}))
+	defer srv.Close()
+
+	s := newTestSource(t, srv, models.GitHubSourceConfig{Repos: []string{"acme/widgets"}})
+
+	if _, err := s.Fetch(time.Time{}, 0); err == nil {
+		t.Fatal("expected an error")
+	}
+
+	if calls.Load() != 1 {
+		t.Errorf("expected no retries, got %d calls", calls.Load())
+	}
+}
+
+func TestConfigure(t *testing.T) {
+	t.Setenv(tokenEnvVar, "")
+
+	tests := []struct {
+		name    string
+		cfg     models.GitHubSourceConfig
+		wantErr bool
+	}{
+		{"assigned issues need a token", models.GitHubSourceConfig{}, true},
+		{"public repos without a token", models.GitHubSourceConfig{Repos: []string{"acme/widgets"}}, false},
+		{"malformed repo", models.GitHubSourceConfig{Token: "t", Repos: []string{"widgets"}}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := NewGitHubSource("gh", models.SourceConfig{GitHub: tt.cfg}).Configure(nil, nil)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Configure() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -10,6 +10,7 @@ import (
 	"strings"
 
 	"pkm-sync/internal/config"
+	"pkm-sync/internal/httpclient"
 	"pkm-sync/internal/keystore"
 
 	"golang.org/x/oauth2"
@@ -46,7 +47,33 @@ func GetClient() (*http.Client, error) {
 		return nil, fmt.Errorf("unable to get token: %w", err)
 	}
 
-	return config.Client(context.Background(), token), nil
+	return config.Client(oauthContext(), token), nil
+}
+
+// GetStoredClient is GetClient without the authorization flow: it fails when
+// no usable token is stored, so non-interactive checks never open a browser.
+func GetStoredClient() (*http.Client, error) {
+	config, err := getOAuthConfig()
+	if err != nil {
+		return nil, fmt.Errorf("unable to get OAuth config: %w", err)
+	}
+
+	token, err := tokenFromFile()
+	if err != nil {
+		return nil, fmt.Errorf("no stored token: %w", err)
+	}
+
+	if token.AccessToken == "" && token.RefreshToken == "" {
+		return nil, fmt.Errorf("stored token is invalid")
+	}
+
+	return config.Client(oauthContext(), token), nil
+}
+
+// oauthContext makes oauth2 send token and API requests over the shared
+// transport, so proxy and TLS settings apply to Google as well.
+func oauthContext() context.Context {
+	return context.WithValue(context.Background(), oauth2.HTTPClient, httpclient.NewSharedClient())
 }
 
 func getOAuthConfig() (*oauth2.Config, error) {
@@ -146,7 +173,7 @@ func getTokenFromWebManual(config *oauth2.Config) (*oauth2.Token, error) {
 		return nil, fmt.Errorf("could not extract authorization code from input")
 	}
 
-	token, err := config.Exchange(context.TODO(), authCode)
+	token, err := config.Exchange(oauthContext(), authCode)
 	if err != nil {
 		return nil, fmt.Errorf("unable to retrieve token from web: %w", err)
 	}
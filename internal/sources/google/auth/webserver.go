@@ -217,7 +217,7 @@ func getTokenFromWebServer(config *oauth2.Config) (*oauth2.Token, error) {
 		return nil, fmt.Errorf("failed to get authorization code: %w", err)
 	}
 
-	token, err := config.Exchange(context.TODO(), authCode)
+	token, err := config.Exchange(oauthContext(), authCode)
 	if err != nil {
 		return nil, fmt.Errorf("unable to retrieve token from web: %w", err)
 	}
@@ -18,6 +18,8 @@ type Service struct {
 	attendeeAllowList        []string
 	requireMultipleAttendees bool
 	includeSelfOnlyEvents    bool
+	responseStatuses         []string
+	includePrivate           bool
 }
 
 func NewService(client *http.Client) (*Service, error) {
@@ -33,6 +35,7 @@ func NewService(client *http.Client) (*Service, error) {
 		calendarService:          calendarService,
 		requireMultipleAttendees: true,  // Default: filter out 0-1 attendee events
 		includeSelfOnlyEvents:    false, // Default: don't include solo events
+		includePrivate:           true,  // Default: keep private events' details
 	}, nil
 }
 
@@ -51,7 +54,20 @@ func (s *Service) SetIncludeSelfOnlyEvents(include bool) {
 	s.includeSelfOnlyEvents = include
 }
 
-// shouldIncludeEvent applies two-step filtering: 1) attendee allow list, 2) self-only rules.
+// SetResponseStatuses configures which of your responses ("accepted",
+// "tentative", "declined", "needsAction") an event must have to be included.
+func (s *Service) SetResponseStatuses(statuses []string) {
+	s.responseStatuses = statuses
+}
+
+// SetIncludePrivate configures whether events marked private or confidential
+// keep their details. When false they are reduced to their title and time.
+func (s *Service) SetIncludePrivate(include bool) {
+	s.includePrivate = include
+}
+
+// shouldIncludeEvent applies three-step filtering: 1) attendee allow list, 2) self-only rules,
+// 3) your response status.
 func (s *Service) shouldIncludeEvent(event *calendar.Event) bool {
 	// Step 1: Apply attendee allow list filtering
 	if !s.passesAttendeeAllowListFilter(event) {
@@ -59,7 +75,12 @@ func (s *Service) shouldIncludeEvent(event *calendar.Event) bool {
 	}
 
 	// Step 2: Apply self-only event filtering
-	return s.passesSelfOnlyEventFilter(event)
+	if !s.passesSelfOnlyEventFilter(event) {
+		return false
+	}
+
+	// Step 3: Apply response status filtering
+	return s.passesResponseStatusFilter(event)
 }
 
 // passesAttendeeAllowListFilter checks if event passes the attendee allow list filter.
@@ -104,6 +125,33 @@ func (s *Service) passesSelfOnlyEventFilter(event *calendar.Event) bool {
 	return true
 }
 
+// passesResponseStatusFilter checks your attendee entry's responseStatus
+// against the configured statuses. Events without an entry for you (ones you
+// created without inviting anyone) count as accepted.
+func (s *Service) passesResponseStatusFilter(event *calendar.Event) bool {
+	if len(s.responseStatuses) == 0 {
+		return true
+	}
+
+	status := "accepted"
+
+	for _, attendee := range event.Attendees {
+		if attendee.Self {
+			status = attendee.ResponseStatus
+
+			break
+		}
+	}
+
+	for _, allowed := range s.responseStatuses {
+		if strings.EqualFold(strings.TrimSpace(allowed), status) {
+			return true
+		}
+	}
+
+	return false
+}
+
 // filterEvents applies the attendee allow list filter to a slice of events.
 func (s *Service) filterEvents(events []*calendar.Event) []*calendar.Event {
 	// Always apply filtering, even if allow list is empty (for attendee count filtering)
@@ -118,6 +166,16 @@ func (s *Service) filterEvents(events []*calendar.Event) []*calendar.Event {
 	return filteredEvents
 }
 
+// GetCalendarSummary returns the title of a calendar, without listing events.
+func (s *Service) GetCalendarSummary(calendarID string) (string, error) {
+	cal, err := s.calendarService.Calendars.Get(calendarID).Do()
+	if err != nil {
+		return "", fmt.Errorf("unable to retrieve calendar: %w", err)
+	}
+
+	return cal.Summary, nil
+}
+
 func (s *Service) GetUpcomingEvents(calendarID string, maxResults int64) ([]*calendar.Event, error) {
 	t := time.Now().Format(time.RFC3339)
 
@@ -168,16 +226,16 @@ func (s *Service) ConvertToModel(event *calendar.Event) *models.CalendarEvent {
 		Location:    event.Location,
 	}
 
-	if event.Start.DateTime != "" {
-		if startTime, err := time.Parse(time.RFC3339, event.Start.DateTime); err == nil {
-			modelEvent.Start = startTime
-		}
-	}
+	modelEvent.Start, modelEvent.IsAllDay, modelEvent.TimeZone = parseEventTime(event.Start)
+	modelEvent.End, _, _ = parseEventTime(event.End)
 
-	if event.End.DateTime != "" {
-		if endTime, err := time.Parse(time.RFC3339, event.End.DateTime); err == nil {
-			modelEvent.End = endTime
-		}
+	if !s.includePrivate && isPrivateEvent(event) {
+		modelEvent.Description = ""
+		modelEvent.Location = ""
+		modelEvent.MyResponseStatus = myResponseStatus(event)
+		modelEvent.Redacted = true
+
+		return modelEvent
 	}
 
 	for _, attendee := range event.Attendees {
@@ -221,6 +279,57 @@ func (s *Service) ConvertToModel(event *calendar.Event) *models.CalendarEvent {
 	return modelEvent
 }
 
+// isPrivateEvent reports whether the event's visibility hides its details
+// from others.
+func isPrivateEvent(event *calendar.Event) bool {
+	return event.Visibility == "private" || event.Visibility == "confidential"
+}
+
+func myResponseStatus(event *calendar.Event) string {
+	for _, attendee := range event.Attendees {
+		if attendee.Self {
+			return attendee.ResponseStatus
+		}
+	}
+
+	return ""
+}
+
+// parseEventTime reads a start or end time. Timed events are expressed in the
+// event's own time zone when Calendar names one, so exports keep the zone the
+// event was scheduled in rather than a bare UTC offset. All-day events carry
+// only a date, returned as midnight in that zone (UTC when none is set).
+func parseEventTime(edt *calendar.EventDateTime) (t time.Time, allDay bool, timeZone string) {
+	if edt == nil {
+		return time.Time{}, false, ""
+	}
+
+	loc := time.UTC
+
+	if edt.TimeZone != "" {
+		if l, err := time.LoadLocation(edt.TimeZone); err == nil {
+			loc, timeZone = l, edt.TimeZone
+		}
+	}
+
+	switch {
+	case edt.DateTime != "":
+		if parsed, err := time.Parse(time.RFC3339, edt.DateTime); err == nil {
+			if timeZone != "" {
+				parsed = parsed.In(loc)
+			}
+
+			return parsed, false, timeZone
+		}
+	case edt.Date != "":
+		if parsed, err := time.ParseInLocation(time.DateOnly, edt.Date, loc); err == nil {
+			return parsed, true, timeZone
+		}
+	}
+
+	return time.Time{}, false, timeZone
+}
+
 // ConvertToModelWithDrive converts a calendar event to a model with drive file attachments populated.
 func (s *Service) ConvertToModelWithDrive(event *calendar.Event) *models.CalendarEvent {
 	// Now that we use native Calendar API attachments, just use the base conversion
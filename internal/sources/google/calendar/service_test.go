@@ -2,6 +2,9 @@ package calendar
 
 import (
 	"testing"
+	"time"
+
+	"pkm-sync/pkg/models"
 
 	"google.golang.org/api/calendar/v3"
 )
@@ -471,6 +474,67 @@ func TestService_filterEvents(t *testing.T) {
 	}
 }
 
+func TestService_passesResponseStatusFilter(t *testing.T) {
+	withSelf := func(status string) *calendar.Event {
+		return &calendar.Event{
+			Attendees: []*calendar.EventAttendee{
+				{Email: "organizer@example.com", ResponseStatus: "accepted"},
+				{Email: "me@example.com", Self: true, ResponseStatus: status},
+			},
+		}
+	}
+
+	tests := []struct {
+		name     string
+		statuses []string
+		event    *calendar.Event
+		expected bool
+	}{
+		{"no statuses configured - declined passes", nil, withSelf("declined"), true},
+		{"declined excluded when not in allow-list", []string{"accepted", "tentative"}, withSelf("declined"), false},
+		{"accepted included", []string{"accepted"}, withSelf("accepted"), true},
+		{"tentative only", []string{"tentative"}, withSelf("accepted"), false},
+		{"needsAction included", []string{"needsAction"}, withSelf("needsAction"), true},
+		{"own event without attendees counts as accepted", []string{"accepted"}, &calendar.Event{}, true},
+		{"own event excluded from declined-only", []string{"declined"}, &calendar.Event{}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := &Service{responseStatuses: tt.statuses}
+
+			if result := service.passesResponseStatusFilter(tt.event); result != tt.expected {
+				t.Errorf("passesResponseStatusFilter() = %v, expected %v", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestService_filterEvents_ResponseStatuses(t *testing.T) {
+	service := &Service{responseStatuses: []string{"accepted"}}
+
+	declined := &calendar.Event{
+		Id: "declined",
+		Attendees: []*calendar.EventAttendee{
+			{Email: "a@example.com"},
+			{Email: "me@example.com", Self: true, ResponseStatus: "declined"},
+		},
+	}
+	accepted := &calendar.Event{
+		Id: "accepted",
+		Attendees: []*calendar.EventAttendee{
+			{Email: "a@example.com"},
+			{Email: "me@example.com", Self: true, ResponseStatus: "accepted"},
+		},
+	}
+
+	result := service.filterEvents([]*calendar.Event{declined, accepted})
+
+	if len(result) != 1 || result[0].Id != "accepted" {
+		t.Errorf("filterEvents() kept %d events, expected only the accepted one", len(result))
+	}
+}
+
 func TestService_SetAttendeeAllowList(t *testing.T) {
 	service := &Service{}
 
@@ -618,3 +682,83 @@ func TestService_SetIncludeSelfOnlyEvents(t *testing.T) {
 		t.Errorf("SetIncludeSelfOnlyEvents(false) = %v, expected false", service.includeSelfOnlyEvents)
 	}
 }
+
+func TestService_ConvertToModel_PrivateEvents(t *testing.T) {
+	privateEvent := func(visibility string) *calendar.Event {
+		return &calendar.Event{
+			Id:          "evt-private",
+			Summary:     "Doctor appointment",
+			Description: "Bring test results",
+			Location:    "Clinic, 2nd floor",
+			Visibility:  visibility,
+			Start:       &calendar.EventDateTime{DateTime: "2024-06-01T10:00:00Z"},
+			End:         &calendar.EventDateTime{DateTime: "2024-06-01T11:00:00Z"},
+			Attendees: []*calendar.EventAttendee{
+				{Email: "me@example.com", Self: true, ResponseStatus: "accepted"},
+				{Email: "dr@example.com", ResponseStatus: "accepted"},
+			},
+			ConferenceData: &calendar.ConferenceData{EntryPoints: []*calendar.EntryPoint{
+				{EntryPointType: "video", Uri: "https://meet.example.com/abc"},
+			}},
+			Attachments: []*calendar.EventAttachment{{FileId: "f1", Title: "Referral"}},
+		}
+	}
+
+	wantStart := time.Date(2024, 6, 1, 10, 0, 0, 0, time.UTC)
+
+	for _, visibility := range []string{"private", "confidential"} {
+		t.Run(visibility+" redacted", func(t *testing.T) {
+			service := &Service{includePrivate: false}
+
+			got := service.ConvertToModel(privateEvent(visibility))
+
+			if !got.Redacted {
+				t.Error("expected event to be marked redacted")
+			}
+
+			if got.Summary != "Doctor appointment" || !got.Start.Equal(wantStart) || got.End.IsZero() {
+				t.Errorf("title and time should be kept, got %q %v-%v", got.Summary, got.Start, got.End)
+			}
+
+			if got.Description != "" || got.Location != "" || len(got.Attendees) != 0 ||
+				got.MeetingURL != "" || len(got.Attachments) != 0 {
+				t.Errorf("details should be redacted, got %+v", got)
+			}
+
+			if got.MyResponseStatus != "accepted" {
+				t.Errorf("own response should be kept, got %q", got.MyResponseStatus)
+			}
+		})
+	}
+
+	t.Run("private included", func(t *testing.T) {
+		service := &Service{includePrivate: true}
+
+		got := service.ConvertToModel(privateEvent("private"))
+
+		if got.Redacted || got.Description != "Bring test results" || got.Location != "Clinic, 2nd floor" ||
+			len(got.Attendees) != 2 || got.MeetingURL == "" || len(got.Attachments) != 1 {
+			t.Errorf("private event should be fully included, got %+v", got)
+		}
+	})
+
+	t.Run("default visibility never redacted", func(t *testing.T) {
+		service := &Service{includePrivate: false}
+
+		got := service.ConvertToModel(privateEvent("default"))
+
+		if got.Redacted || got.Description == "" || len(got.Attendees) != 2 {
+			t.Errorf("non-private event should be fully included, got %+v", got)
+		}
+	})
+
+	t.Run("redacted flag reaches item metadata", func(t *testing.T) {
+		service := &Service{includePrivate: false}
+
+		item := models.FromCalendarEvent(service.ConvertToModel(privateEvent("private")))
+
+		if item.Metadata["redacted"] != true || item.Content != "" || len(item.Links) != 0 {
+			t.Errorf("unexpected item for redacted event: %+v", item)
+		}
+	})
+}
@@ -15,6 +15,7 @@ import (
 	"sync"
 	"time"
 
+	"pkm-sync/internal/naming"
 	"pkm-sync/pkg/models"
 
 	mdconverter "github.com/JohannesKaufmann/html-to-markdown/v2"
@@ -29,6 +30,8 @@ type Service struct {
 	maxRequests  int
 	mu           sync.Mutex
 	requestCount int
+	// retryDelay is the first backoff interval in executeWithRetry (default 1s).
+	retryDelay time.Duration
 }
 
 func NewService(httpClient *http.Client) (*Service, error) {
@@ -81,10 +84,12 @@ func (s *Service) rateLimit() error {
 // rateLimit() is called before every attempt (including retries) so that request
 // pacing and the total request cap are enforced consistently.
 func (s *Service) executeWithRetry(fn func() (interface{}, error)) (interface{}, error) {
-	const (
-		maxRetries = 3
-		baseDelay  = time.Second
-	)
+	const maxRetries = 3
+
+	baseDelay := s.retryDelay
+	if baseDelay <= 0 {
+		baseDelay = time.Second
+	}
 
 	var lastErr error
 
@@ -202,6 +207,10 @@ func (s *Service) GetFileMetadata(fileID string) (*models.DriveFile, error) {
 		WebViewLink: file.WebViewLink,
 	}
 
+	if modified, err := time.Parse(time.RFC3339, file.ModifiedTime); err == nil {
+		driveFile.ModifiedTime = modified
+	}
+
 	for _, owner := range file.Owners {
 		driveFile.Owners = append(driveFile.Owners, owner.DisplayName)
 	}
@@ -428,7 +437,7 @@ func (s *Service) ExportAttachedDocsFromEvent(eventDescription, outputDir string
 		}
 
 		// Generate output filename
-		filename := sanitizeFilename(metadata.Name)
+		filename := naming.Clean(metadata.Name)
 		if !strings.HasSuffix(filename, ".md") {
 			filename += ".md"
 		}
@@ -449,34 +458,6 @@ func (s *Service) ExportAttachedDocsFromEvent(eventDescription, outputDir string
 	return exportedFiles, nil
 }
 
-// sanitizeFilename removes or replaces characters that are invalid in filenames.
-func sanitizeFilename(filename string) string {
-	// Replace common problematic characters
-	replacements := map[string]string{
-		"/":  "-",
-		"\\": "-",
-		":":  "-",
-		"*":  "",
-		"?":  "",
-		"\"": "",
-		"<":  "",
-		">":  "",
-		"|":  "-",
-	}
-
-	for old, new := range replacements {
-		filename = strings.ReplaceAll(filename, old, new)
-	}
-
-	// Remove multiple consecutive spaces and trim
-	filename = strings.TrimSpace(filename)
-	for strings.Contains(filename, "  ") {
-		filename = strings.ReplaceAll(filename, "  ", " ")
-	}
-
-	return filename
-}
-
 // Google Workspace MIME types.
 const (
 	MimeTypeGoogleDoc          = "application/vnd.google-apps.document"
@@ -550,6 +531,56 @@ func (s *Service) ExportDocument(fileID, exportMimeType string) (io.ReadCloser,
 	return raw.(*http.Response).Body, nil
 }
 
+// DownloadFile downloads the stored content of a regular (non-Workspace) file such as
+// an uploaded PDF or image. The caller is responsible for closing the returned body.
+func (s *Service) DownloadFile(fileID string) (io.ReadCloser, error) {
+	// Body ownership is transferred to the caller via the returned ReadCloser;
+	// bodyclose cannot trace through interface{}.
+	raw, err := s.executeWithRetry(func() (interface{}, error) {
+		return s.client.Files.Get(fileID).SupportsAllDrives(true).Download() //nolint:bodyclose
+	})
+	if err != nil {
+		return nil, fmt.Errorf("unable to download file: %w", err)
+	}
+
+	return raw.(*http.Response).Body, nil
+}
+
+// DownloadAsBytes downloads a regular file's content. maxBytes limits how many bytes are
+// read; 0 means no limit.
+func (s *Service) DownloadAsBytes(fileID string, maxBytes int64) ([]byte, error) {
+	body, err := s.DownloadFile(fileID)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		_ = body.Close()
+	}()
+
+	var reader io.Reader = body
+	if maxBytes > 0 {
+		reader = io.LimitReader(body, maxBytes+1)
+	}
+
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read downloaded content: %w", err)
+	}
+
+	if maxBytes > 0 && int64(len(data)) > maxBytes {
+		return nil, fmt.Errorf("downloaded content exceeds size limit of %d bytes", maxBytes)
+	}
+
+	return data, nil
+}
+
+// IsGoogleAppsType returns true for any Google-native MIME type (Docs, folders, Forms,
+// shortcuts, ...). Such files have no stored content and cannot be downloaded directly.
+func IsGoogleAppsType(mimeType string) bool {
+	return strings.HasPrefix(mimeType, "application/vnd.google-apps.")
+}
+
 // IsGoogleWorkspaceFile returns true if the MIME type is one of the three supported Workspace types.
 func IsGoogleWorkspaceFile(mimeType string) bool {
 	switch mimeType {
@@ -580,6 +611,10 @@ func buildQuery(opts ListFilesOptions) string {
 		parts = append(parts, fmt.Sprintf("modifiedTime > '%s'", opts.ModifiedAfter.UTC().Format(time.RFC3339)))
 	}
 
+	if !opts.ModifiedBefore.IsZero() {
+		parts = append(parts, fmt.Sprintf("modifiedTime < '%s'", opts.ModifiedBefore.UTC().Format(time.RFC3339)))
+	}
+
 	if len(opts.MimeTypes) == 1 {
 		parts = append(parts, fmt.Sprintf("mimeType = '%s'", opts.MimeTypes[0]))
 	} else if len(opts.MimeTypes) > 1 {
@@ -604,7 +639,38 @@ func buildQuery(opts ListFilesOptions) string {
 	return query
 }
 
+// PartialListError reports a listing that failed part-way through. The files
+// gathered before the failure are returned alongside it; PageToken is the page
+// that could not be fetched, so setting ListFilesOptions.PageToken resumes there.
+type PartialListError struct {
+	Files     int
+	Pages     int
+	PageToken string
+	Err       error
+}
+
+func (e *PartialListError) Error() string {
+	return fmt.Sprintf("drive listing incomplete: returning %d file(s) from %d page(s) before failure: %v",
+		e.Files, e.Pages, e.Err)
+}
+
+func (e *PartialListError) Unwrap() error {
+	return e.Err
+}
+
+// asPartialListError marks err as partial when files were already collected.
+func asPartialListError(files []*DriveFileInfo, err error) error {
+	var partial *PartialListError
+	if len(files) == 0 || errors.As(err, &partial) {
+		return err
+	}
+
+	return &PartialListError{Files: len(files), Err: err}
+}
+
 // ListFiles lists files matching the given options, handling pagination automatically.
+// Each page is retried with backoff; if a page still fails after earlier pages
+// succeeded, the files collected so far are returned with a *PartialListError.
 func (s *Service) ListFiles(opts ListFilesOptions) ([]*DriveFileInfo, error) {
 	pageSize := int64(100)
 	if opts.PageSize > 0 {
@@ -615,7 +681,8 @@ func (s *Service) ListFiles(opts ListFilesOptions) ([]*DriveFileInfo, error) {
 
 	var files []*DriveFileInfo
 
-	pageToken := ""
+	pageToken := opts.PageToken
+	pages := 0
 
 	for {
 		const fields = "nextPageToken, " +
@@ -636,9 +703,14 @@ func (s *Service) ListFiles(opts ListFilesOptions) ([]*DriveFileInfo, error) {
 
 		raw, err := s.executeWithRetry(func() (interface{}, error) { return req.Do() })
 		if err != nil {
-			return nil, fmt.Errorf("failed to list drive files: %w", err)
+			if pages == 0 {
+				return nil, fmt.Errorf("failed to list drive files: %w", err)
+			}
+
+			return files, &PartialListError{Files: len(files), Pages: pages, PageToken: pageToken, Err: err}
 		}
 
+		pages++
 		result := raw.(*drive.FileList)
 
 		for _, f := range result.Files {
@@ -662,6 +734,8 @@ func (s *Service) ListFiles(opts ListFilesOptions) ([]*DriveFileInfo, error) {
 
 // ListFilesInFolder lists files in a specific folder. If recursive is true, subfolders are
 // traversed and their contents included. folderID "root" refers to the Drive root.
+// On failure the files gathered before it are returned with the error, which is a
+// *PartialListError whenever that slice is non-empty.
 func (s *Service) ListFilesInFolder(
 	folderID string,
 	since time.Time,
@@ -676,7 +750,7 @@ func (s *Service) ListFilesInFolder(
 
 	files, err := s.ListFiles(folderOpts)
 	if err != nil {
-		return nil, err
+		return files, err
 	}
 
 	if !recursive {
@@ -692,7 +766,7 @@ func (s *Service) ListFilesInFolder(
 
 	subfolders, err := s.ListFiles(subfolderOpts)
 	if err != nil {
-		return nil, fmt.Errorf("failed to list subfolders in %s: %w", folderID, err)
+		return files, asPartialListError(files, fmt.Errorf("failed to list subfolders in %s: %w", folderID, err))
 	}
 
 	seen := make(map[string]bool, len(files))
@@ -702,9 +776,6 @@ func (s *Service) ListFilesInFolder(
 
 	for _, subfolder := range subfolders {
 		subFiles, err := s.ListFilesInFolder(subfolder.ID, since, recursive, opts)
-		if err != nil {
-			return nil, fmt.Errorf("failed to list files in subfolder %s: %w", subfolder.ID, err)
-		}
 
 		for _, f := range subFiles {
 			if !seen[f.ID] {
@@ -712,6 +783,10 @@ func (s *Service) ListFilesInFolder(
 				files = append(files, f)
 			}
 		}
+
+		if err != nil {
+			return files, asPartialListError(files, fmt.Errorf("failed to list files in subfolder %s: %w", subfolder.ID, err))
+		}
 	}
 
 	return files, nil
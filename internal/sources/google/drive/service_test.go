@@ -1,9 +1,18 @@
 package drive
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"strings"
+	"sync"
 	"testing"
 	"time"
+
+	"google.golang.org/api/drive/v3"
+	"google.golang.org/api/option"
 )
 
 func TestIsGoogleWorkspaceFile(t *testing.T) {
@@ -31,6 +40,26 @@ func TestIsGoogleWorkspaceFile(t *testing.T) {
 	}
 }
 
+func TestIsGoogleAppsType(t *testing.T) {
+	tests := []struct {
+		mimeType string
+		want     bool
+	}{
+		{MimeTypeGoogleDoc, true},
+		{MimeTypeGoogleFolder, true},
+		{"application/vnd.google-apps.form", true},
+		{"application/pdf", false},
+		{"image/png", false},
+		{"", false},
+	}
+
+	for _, tt := range tests {
+		if got := IsGoogleAppsType(tt.mimeType); got != tt.want {
+			t.Errorf("IsGoogleAppsType(%q) = %v, want %v", tt.mimeType, got, tt.want)
+		}
+	}
+}
+
 func TestBuildQuery(t *testing.T) {
 	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
 
@@ -60,6 +89,11 @@ func TestBuildQuery(t *testing.T) {
 			opts:     ListFilesOptions{ModifiedAfter: now},
 			wantPart: "modifiedTime > '2025-06-01T12:00:00Z'",
 		},
+		{
+			name:     "modified before filter",
+			opts:     ListFilesOptions{ModifiedBefore: now},
+			wantPart: "modifiedTime < '2025-06-01T12:00:00Z'",
+		},
 		{
 			name:    "no modified after when zero",
 			opts:    ListFilesOptions{},
@@ -150,3 +184,110 @@ func TestGetExportMimeType(t *testing.T) {
 		})
 	}
 }
+
+// newPagedTestService serves three pages of two files each. failPage2 decides,
+// given how many times page 2 has been requested, whether to answer with a 503.
+func newPagedTestService(t *testing.T, failPage2 func(attempt int) bool) (*Service, *int) {
+	t.Helper()
+
+	var (
+		mu        sync.Mutex
+		page2Hits int
+	)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token := r.URL.Query().Get("pageToken")
+
+		next := map[string]string{"": "p2", "p2": "p3", "p3": ""}[token]
+		page := map[string]int{"": 1, "p2": 2, "p3": 3}[token]
+
+		if page == 2 {
+			mu.Lock()
+			page2Hits++
+			fail := failPage2(page2Hits)
+			mu.Unlock()
+
+			if fail {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				_, _ = w.Write([]byte(`{"error":{"code":503,"message":"backend unavailable"}}`))
+
+				return
+			}
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = fmt.Fprintf(w, `{"nextPageToken":%q,"files":[{"id":"f%d-a","name":"a"},{"id":"f%d-b","name":"b"}]}`,
+			next, page, page)
+	}))
+	t.Cleanup(srv.Close)
+
+	client, err := drive.NewService(context.Background(),
+		option.WithHTTPClient(srv.Client()), option.WithEndpoint(srv.URL+"/"))
+	if err != nil {
+		t.Fatalf("drive.NewService: %v", err)
+	}
+
+	return &Service{client: client, retryDelay: time.Millisecond}, &page2Hits
+}
+
+func TestListFiles_RetriesFailedPage(t *testing.T) {
+	svc, page2Hits := newPagedTestService(t, func(attempt int) bool { return attempt == 1 })
+
+	files, err := svc.ListFiles(ListFilesOptions{})
+	if err != nil {
+		t.Fatalf("ListFiles() error = %v", err)
+	}
+
+	if len(files) != 6 {
+		t.Errorf("ListFiles() returned %d files, want 6", len(files))
+	}
+
+	if *page2Hits != 2 {
+		t.Errorf("page 2 requested %d times, want 2", *page2Hits)
+	}
+}
+
+func TestListFiles_PartialResultsOnPermanentPageFailure(t *testing.T) {
+	svc, _ := newPagedTestService(t, func(int) bool { return true })
+
+	files, err := svc.ListFiles(ListFilesOptions{})
+	if err == nil {
+		t.Fatal("ListFiles() error = nil, want partial-result error")
+	}
+
+	var partial *PartialListError
+	if !errors.As(err, &partial) {
+		t.Fatalf("ListFiles() error = %T %v, want *PartialListError", err, err)
+	}
+
+	if len(files) != 2 || files[0].ID != "f1-a" || files[1].ID != "f1-b" {
+		t.Errorf("ListFiles() files = %v, want the two files from page 1", files)
+	}
+
+	if partial.Files != 2 || partial.Pages != 1 || partial.PageToken != "p2" {
+		t.Errorf("PartialListError = %+v, want Files=2 Pages=1 PageToken=p2", partial)
+	}
+
+	if !strings.Contains(err.Error(), "incomplete") {
+		t.Errorf("error message %q should say the listing is incomplete", err.Error())
+	}
+}
+
+func TestListFiles_FirstPageFailureReturnsNoFiles(t *testing.T) {
+	svc, _ := newPagedTestService(t, func(int) bool { return true })
+
+	// Resuming at the failing page means nothing has been collected yet.
+	files, err := svc.ListFiles(ListFilesOptions{PageToken: "p2"})
+	if err == nil {
+		t.Fatal("ListFiles() error = nil, want error")
+	}
+
+	var partial *PartialListError
+	if errors.As(err, &partial) {
+		t.Errorf("ListFiles() error = %v, want a plain error when no page succeeded", err)
+	}
+
+	if files != nil {
+		t.Errorf("ListFiles() files = %v, want nil", files)
+	}
+}
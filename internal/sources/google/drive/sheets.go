@@ -0,0 +1,244 @@
+package drive
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// MimeTypeZip is the export type that holds a spreadsheet as one HTML file
+// per tab, in tab order.
+const MimeTypeZip = "application/zip"
+
+// sheetTab is one tab of a spreadsheet's zip export.
+type sheetTab struct {
+	name string
+	html string
+}
+
+// ExportSheetTabs exports the spreadsheet tabs selected by name or 1-based
+// position, in tab order, each under a "## <tab>" heading and rendered as
+// format (csv or html). maxBytes limits the size of the export; 0 means no
+// limit.
+func (s *Service) ExportSheetTabs(fileID string, selectors []string, format string, maxBytes int64) (string, error) {
+	body, err := s.ExportDocument(fileID, MimeTypeZip)
+	if err != nil {
+		return "", err
+	}
+
+	defer func() {
+		_ = body.Close()
+	}()
+
+	var reader io.Reader = body
+	if maxBytes > 0 {
+		reader = io.LimitReader(body, maxBytes+1)
+	}
+
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return "", fmt.Errorf("failed to read exported content: %w", err)
+	}
+
+	if maxBytes > 0 && int64(len(data)) > maxBytes {
+		return "", fmt.Errorf("exported content exceeds size limit of %d bytes", maxBytes)
+	}
+
+	return renderSheetTabs(data, selectors, format)
+}
+
+// renderSheetTabs picks the selected tabs out of a spreadsheet zip export.
+func renderSheetTabs(zipData []byte, selectors []string, format string) (string, error) {
+	tabs, err := sheetTabsFromZip(zipData)
+	if err != nil {
+		return "", err
+	}
+
+	selected, err := selectSheetTabs(tabs, selectors)
+	if err != nil {
+		return "", err
+	}
+
+	sections := make([]string, 0, len(selected))
+
+	for _, tab := range selected {
+		content := tab.html
+
+		if format != FormatHTML {
+			if content, err = sheetHTMLToCSV(tab.html); err != nil {
+				return "", fmt.Errorf("failed to convert tab %q: %w", tab.name, err)
+			}
+		}
+
+		sections = append(sections, "## "+tab.name+"\n\n"+strings.TrimSpace(content))
+	}
+
+	return strings.Join(sections, "\n\n"), nil
+}
+
+// sheetTabsFromZip returns the tabs of a spreadsheet zip export in order.
+// Shared stylesheets and images live under resources/ and are skipped.
+func sheetTabsFromZip(zipData []byte) ([]sheetTab, error) {
+	zr, err := zip.NewReader(bytes.NewReader(zipData), int64(len(zipData)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to open spreadsheet export: %w", err)
+	}
+
+	var tabs []sheetTab
+
+	for _, f := range zr.File {
+		if path.Ext(f.Name) != ".html" || strings.HasPrefix(f.Name, "resources/") {
+			continue
+		}
+
+		rc, err := f.Open()
+		if err != nil {
+			return nil, fmt.Errorf("failed to open %s in spreadsheet export: %w", f.Name, err)
+		}
+
+		data, err := io.ReadAll(rc)
+		_ = rc.Close()
+
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s in spreadsheet export: %w", f.Name, err)
+		}
+
+		tabs = append(tabs, sheetTab{
+			name: strings.TrimSuffix(path.Base(f.Name), ".html"),
+			html: string(data),
+		})
+	}
+
+	return tabs, nil
+}
+
+// selectSheetTabs returns the tabs matching selectors, each a tab name
+// (case-insensitive) or a 1-based position, in tab order. A selector that
+// matches no tab is an error listing the tabs there are.
+func selectSheetTabs(tabs []sheetTab, selectors []string) ([]sheetTab, error) {
+	wanted := make([]bool, len(tabs))
+
+	for _, selector := range selectors {
+		selector = strings.TrimSpace(selector)
+		found := false
+
+		for i, tab := range tabs {
+			if strings.EqualFold(tab.name, selector) {
+				wanted[i], found = true, true
+			}
+		}
+
+		if pos, err := strconv.Atoi(selector); !found && err == nil && pos >= 1 && pos <= len(tabs) {
+			wanted[pos-1], found = true, true
+		}
+
+		if !found {
+			names := make([]string, len(tabs))
+			for i, tab := range tabs {
+				names[i] = tab.name
+			}
+
+			return nil, fmt.Errorf("sheet tab %q not found (tabs: %s)", selector, strings.Join(names, ", "))
+		}
+	}
+
+	var selected []sheetTab
+
+	for i, tab := range tabs {
+		if wanted[i] {
+			selected = append(selected, tab)
+		}
+	}
+
+	return selected, nil
+}
+
+// sheetHTMLToCSV converts the table of an exported tab to CSV. Only <td>
+// cells are data: the exported grid labels its columns (A, B, ...) and rows
+// (1, 2, ...) with <th> cells, so rows without data cells are dropped.
+func sheetHTMLToCSV(tabHTML string) (string, error) {
+	doc, err := html.Parse(strings.NewReader(tabHTML))
+	if err != nil {
+		return "", err
+	}
+
+	var (
+		buf bytes.Buffer
+		w   = csv.NewWriter(&buf)
+	)
+
+	var walk func(n *html.Node) error
+
+	walk = func(n *html.Node) error {
+		if n.Type == html.ElementNode && n.DataAtom == atom.Tr {
+			var row []string
+
+			for c := n.FirstChild; c != nil; c = c.NextSibling {
+				if c.Type == html.ElementNode && c.DataAtom == atom.Td {
+					row = append(row, strings.TrimSpace(nodeText(c)))
+				}
+			}
+
+			if len(row) > 0 {
+				return w.Write(row)
+			}
+
+			return nil
+		}
+
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			if err := walk(c); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+
+	if err := walk(doc); err != nil {
+		return "", err
+	}
+
+	w.Flush()
+
+	return buf.String(), w.Error()
+}
+
+func nodeText(n *html.Node) string {
+	if n.Type == html.TextNode {
+		return n.Data
+	}
+
+	if n.Type == html.ElementNode && n.DataAtom == atom.Br {
+		return "\n"
+	}
+
+	var sb strings.Builder
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		sb.WriteString(nodeText(c))
+	}
+
+	return sb.String()
+}
+
+var (
+	htmlImagePattern     = regexp.MustCompile(`(?i)<img\b[^>]*>`)
+	markdownImagePattern = regexp.MustCompile(`!\[[^\]]*\]\([^)]*\)`)
+)
+
+// StripImages removes embedded images, as HTML <img> tags or Markdown image
+// syntax, from exported document content.
+func StripImages(content string) string {
+	content = htmlImagePattern.ReplaceAllString(content, "")
+
+	return markdownImagePattern.ReplaceAllString(content, "")
+}
@@ -0,0 +1,106 @@
+package drive
+
+import (
+	"archive/zip"
+	"bytes"
+	"strings"
+	"testing"
+)
+
+// sheetTabHTML mimics a tab of the zip export: a grid labelled with <th>
+// column letters and row numbers around the <td> data cells.
+func sheetTabHTML(rows ...[]string) string {
+	var sb strings.Builder
+
+	sb.WriteString(`<html><body><table class="waffle"><thead><tr><th></th><th>A</th><th>B</th></tr></thead><tbody>`)
+
+	for i, row := range rows {
+		sb.WriteString(`<tr><th class="row-headers-background">` + string(rune('1'+i)) + `</th>`)
+
+		for _, cell := range row {
+			sb.WriteString(`<td class="s0">` + cell + `</td>`)
+		}
+
+		sb.WriteString(`</tr>`)
+	}
+
+	sb.WriteString(`</tbody></table></body></html>`)
+
+	return sb.String()
+}
+
+func sheetZip(t *testing.T, files map[string]string, order []string) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+
+	zw := zip.NewWriter(&buf)
+
+	for _, name := range order {
+		w, err := zw.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err := w.Write([]byte(files[name])); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	return buf.Bytes()
+}
+
+func TestRenderSheetTabs_SelectsSubset(t *testing.T) {
+	files := map[string]string{
+		"Summary.html":        sheetTabHTML([]string{"Total", "42"}),
+		"Raw Data.html":       sheetTabHTML([]string{"a", "1"}, []string{"b", "2"}),
+		"Archive 2023.html":   sheetTabHTML([]string{"old", "0"}),
+		"resources/sheet.css": "td { color: red }",
+	}
+	data := sheetZip(t, files, []string{"Summary.html", "Raw Data.html", "Archive 2023.html", "resources/sheet.css"})
+
+	got, err := renderSheetTabs(data, []string{"3", "summary"}, FormatCSV)
+	if err != nil {
+		t.Fatalf("renderSheetTabs: %v", err)
+	}
+
+	want := "## Summary\n\nTotal,42\n\n## Archive 2023\n\nold,0"
+	if got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+
+	if strings.Contains(got, "Raw Data") {
+		t.Error("expected the unselected tab to be left out")
+	}
+}
+
+func TestRenderSheetTabs_HTMLAndUnknownTab(t *testing.T) {
+	files := map[string]string{"Summary.html": sheetTabHTML([]string{"Total", "42"})}
+	data := sheetZip(t, files, []string{"Summary.html"})
+
+	got, err := renderSheetTabs(data, []string{"Summary"}, FormatHTML)
+	if err != nil {
+		t.Fatalf("renderSheetTabs: %v", err)
+	}
+
+	if !strings.HasPrefix(got, "## Summary\n\n<html>") {
+		t.Errorf("expected the tab's HTML under its heading, got %q", got)
+	}
+
+	_, err = renderSheetTabs(data, []string{"Budget"}, FormatCSV)
+	if err == nil || !strings.Contains(err.Error(), "tabs: Summary") {
+		t.Errorf("expected an error listing the available tabs, got %v", err)
+	}
+}
+
+func TestStripImages(t *testing.T) {
+	content := "Intro ![chart](https://lh7.googleusercontent.com/x) text <img src=\"a.png\" alt=\"a\"> end"
+
+	if got, want := StripImages(content), "Intro  text  end"; got != want {
+		t.Errorf("StripImages() = %q, want %q", got, want)
+	}
+}
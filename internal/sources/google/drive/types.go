@@ -8,6 +8,8 @@ type ListFilesOptions struct {
 	FolderID string
 	// ModifiedAfter filters files to those modified after this time (zero = no filter).
 	ModifiedAfter time.Time
+	// ModifiedBefore filters files to those modified before this time (zero = no filter).
+	ModifiedBefore time.Time
 	// MimeTypes restricts results to these MIME types (empty = no filter).
 	MimeTypes []string
 	// IncludeSharedWithMe adds "sharedWithMe = true" to the query.
@@ -20,6 +22,8 @@ type ListFilesOptions struct {
 	MaxResults int
 	// ExtraQuery is appended with AND to the generated query.
 	ExtraQuery string
+	// PageToken resumes a listing at this page (see PartialListError).
+	PageToken string
 }
 
 // DriveFileInfo holds metadata for a Google Drive file.
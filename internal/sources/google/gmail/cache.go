@@ -0,0 +1,201 @@
+package gmail
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"regexp"
+	"time"
+
+	"google.golang.org/api/gmail/v1"
+)
+
+// DefaultCacheTTL is used when the app config enables caching without a TTL.
+const DefaultCacheTTL = 24 * time.Hour
+
+const (
+	cacheKindThread  = "thread"
+	cacheKindMessage = "message"
+)
+
+var safeCacheID = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+// cacheEntry is the on-disk form of one cached thread or message.
+type cacheEntry struct {
+	HistoryID uint64          `json:"history_id"`
+	CachedAt  time.Time       `json:"cached_at"`
+	Data      json.RawMessage `json:"data"`
+}
+
+// FetchCache is a disk-backed cache of fully fetched Gmail threads and messages,
+// so a sync followed by an index within the TTL does not fetch every thread
+// twice. Entries are keyed by ID and historyId: a thread whose historyId
+// changed (new reply, label change) misses the cache, and so does a message
+// cached before the change the caller knows about (its labels or read state
+// are out of date). Entries older than the TTL are refetched.
+// Safe for concurrent use: each entry is written to a temp file and renamed.
+type FetchCache struct {
+	dir string
+	ttl time.Duration
+	now func() time.Time
+}
+
+// NewFetchCache creates the cache directory if needed and removes expired
+// entries. A ttl of zero means DefaultCacheTTL.
+func NewFetchCache(dir string, ttl time.Duration) (*FetchCache, error) {
+	if ttl <= 0 {
+		ttl = DefaultCacheTTL
+	}
+
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("creating gmail cache dir: %w", err)
+	}
+
+	c := &FetchCache{dir: dir, ttl: ttl, now: time.Now}
+	c.prune()
+
+	return c, nil
+}
+
+// Thread returns the cached thread when it is fresh and, if historyID is
+// non-zero, unchanged since it was cached.
+func (c *FetchCache) Thread(id string, historyID uint64) (*gmail.Thread, bool) {
+	var thread gmail.Thread
+
+	cachedHistoryID, ok := c.get(cacheKindThread, id, &thread)
+	if !ok || (historyID != 0 && cachedHistoryID != historyID) {
+		return nil, false
+	}
+
+	return &thread, true
+}
+
+// PutThread stores a fully fetched thread.
+func (c *FetchCache) PutThread(thread *gmail.Thread) {
+	c.put(cacheKindThread, thread.Id, thread.HistoryId, thread)
+}
+
+// Message returns the cached message when it is fresh and, if historyID is
+// non-zero, was cached no earlier than that history ID: the message's own
+// historyId from a thread listing, or the ID of a history record that changed
+// it. A copy cached before the change is refetched.
+func (c *FetchCache) Message(id string, historyID uint64) (*gmail.Message, bool) {
+	var msg gmail.Message
+
+	cachedHistoryID, ok := c.get(cacheKindMessage, id, &msg)
+	if !ok || cachedHistoryID < historyID {
+		return nil, false
+	}
+
+	return &msg, true
+}
+
+// PutMessage stores a fully fetched message.
+func (c *FetchCache) PutMessage(msg *gmail.Message) {
+	c.put(cacheKindMessage, msg.Id, msg.HistoryId, msg)
+}
+
+// get decodes a fresh entry into out and returns the history ID it was
+// stored with.
+func (c *FetchCache) get(kind, id string, out any) (uint64, bool) {
+	data, err := os.ReadFile(c.path(kind, id))
+	if err != nil {
+		return 0, false
+	}
+
+	var entry cacheEntry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		slog.Debug("Ignoring unreadable gmail cache entry", "kind", kind, "id", id, "error", err)
+
+		return 0, false
+	}
+
+	if c.now().Sub(entry.CachedAt) > c.ttl {
+		return 0, false
+	}
+
+	if err := json.Unmarshal(entry.Data, out); err != nil {
+		return 0, false
+	}
+
+	return entry.HistoryID, true
+}
+
+// put writes an entry; failures only cost a future cache miss, so they are logged.
+func (c *FetchCache) put(kind, id string, historyID uint64, value any) {
+	if id == "" {
+		return
+	}
+
+	data, err := json.Marshal(value)
+	if err != nil {
+		slog.Debug("Failed to encode gmail cache entry", "kind", kind, "id", id, "error", err)
+
+		return
+	}
+
+	entry, err := json.Marshal(cacheEntry{HistoryID: historyID, CachedAt: c.now(), Data: data})
+	if err != nil {
+		return
+	}
+
+	tmp, err := os.CreateTemp(c.dir, kind+"-*.tmp")
+	if err != nil {
+		slog.Debug("Failed to write gmail cache entry", "kind", kind, "id", id, "error", err)
+
+		return
+	}
+
+	_, writeErr := tmp.Write(entry)
+	closeErr := tmp.Close()
+
+	if writeErr != nil || closeErr != nil {
+		removeCacheFile(tmp.Name())
+
+		return
+	}
+
+	if err := os.Rename(tmp.Name(), c.path(kind, id)); err != nil {
+		removeCacheFile(tmp.Name())
+	}
+}
+
+// prune removes entries whose file is older than the TTL, keeping the cache
+// directory from growing without bound.
+func (c *FetchCache) prune() {
+	entries, err := os.ReadDir(c.dir)
+	if err != nil {
+		return
+	}
+
+	cutoff := c.now().Add(-c.ttl)
+
+	for _, e := range entries {
+		info, err := e.Info()
+		if err != nil || e.IsDir() || !info.ModTime().Before(cutoff) {
+			continue
+		}
+
+		removeCacheFile(filepath.Join(c.dir, e.Name()))
+	}
+}
+
+func removeCacheFile(path string) {
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		slog.Debug("Failed to remove gmail cache file", "path", path, "error", err)
+	}
+}
+
+// path maps a Gmail ID to a file name, hashing IDs that are not filename-safe.
+func (c *FetchCache) path(kind, id string) string {
+	if !safeCacheID.MatchString(id) {
+		sum := sha256.Sum256([]byte(id))
+		id = hex.EncodeToString(sum[:])
+	}
+
+	return filepath.Join(c.dir, kind+"-"+id+".json")
+}
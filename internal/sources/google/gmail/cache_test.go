@@ -0,0 +1,196 @@
+package gmail
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"google.golang.org/api/gmail/v1"
+	"google.golang.org/api/option"
+)
+
+// newCountingGmailService returns a Service backed by a fake Gmail API that
+// serves any thread or message ID and counts the get requests it receives.
+func newCountingGmailService(t *testing.T, historyID *atomic.Uint64) (*Service, *atomic.Int32) {
+	t.Helper()
+
+	var calls atomic.Int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+
+		id := r.URL.Path[strings.LastIndex(r.URL.Path, "/")+1:]
+
+		var body any
+		if strings.Contains(r.URL.Path, "/threads/") {
+			body = &gmail.Thread{Id: id, HistoryId: historyID.Load(), Messages: []*gmail.Message{{Id: "m1", Snippet: "hi"}}}
+		} else {
+			body = &gmail.Message{Id: id, HistoryId: historyID.Load(), Snippet: "hello"}
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(body)
+	}))
+	t.Cleanup(server.Close)
+
+	api, err := gmail.NewService(t.Context(), option.WithHTTPClient(server.Client()), option.WithEndpoint(server.URL+"/"))
+	if err != nil {
+		t.Fatalf("failed to create gmail client: %v", err)
+	}
+
+	return &Service{service: api}, &calls
+}
+
+func TestFetchCache_ThreadHitAvoidsAPICall(t *testing.T) {
+	var historyID atomic.Uint64
+	historyID.Store(100)
+
+	svc, calls := newCountingGmailService(t, &historyID)
+
+	cache, err := NewFetchCache(t.TempDir(), time.Hour)
+	if err != nil {
+		t.Fatalf("NewFetchCache failed: %v", err)
+	}
+
+	svc.SetCache(cache)
+
+	for range 3 {
+		thread, err := svc.getThreadCached("t1", 100)
+		if err != nil {
+			t.Fatalf("getThreadCached failed: %v", err)
+		}
+
+		if thread.Id != "t1" || len(thread.Messages) != 1 {
+			t.Fatalf("unexpected thread: %+v", thread)
+		}
+	}
+
+	if got := calls.Load(); got != 1 {
+		t.Errorf("API calls = %d, want 1 (later fetches served from cache)", got)
+	}
+
+	// A new historyId means the thread changed and must be refetched.
+	historyID.Store(101)
+
+	if _, err := svc.getThreadCached("t1", 101); err != nil {
+		t.Fatalf("getThreadCached failed: %v", err)
+	}
+
+	if got := calls.Load(); got != 2 {
+		t.Errorf("API calls = %d, want 2 after historyId changed", got)
+	}
+}
+
+func TestFetchCache_MessageRefetchedAfterHistoryChange(t *testing.T) {
+	var historyID atomic.Uint64
+	historyID.Store(100)
+
+	svc, calls := newCountingGmailService(t, &historyID)
+
+	cache, err := NewFetchCache(t.TempDir(), time.Hour)
+	if err != nil {
+		t.Fatalf("NewFetchCache failed: %v", err)
+	}
+
+	svc.SetCache(cache)
+
+	for range 2 {
+		if _, err := svc.getMessageCached("m1", 100); err != nil {
+			t.Fatalf("getMessageCached failed: %v", err)
+		}
+	}
+
+	if got := calls.Load(); got != 1 {
+		t.Fatalf("API calls = %d, want 1 while the history ID is unchanged", got)
+	}
+
+	// A label or read-state change moves the history ID; the cached copy
+	// predates it and must be refetched.
+	historyID.Store(105)
+
+	msg, err := svc.getMessageCached("m1", 105)
+	if err != nil {
+		t.Fatalf("getMessageCached failed: %v", err)
+	}
+
+	if msg.HistoryId != 105 {
+		t.Errorf("HistoryId = %d, want 105 from the refetch", msg.HistoryId)
+	}
+
+	if got := calls.Load(); got != 2 {
+		t.Errorf("API calls = %d, want 2 after the history ID changed", got)
+	}
+
+	// Without a history ID to check against, the fresh copy is served.
+	if _, err := svc.getMessageCached("m1", 0); err != nil {
+		t.Fatalf("getMessageCached failed: %v", err)
+	}
+
+	if got := calls.Load(); got != 2 {
+		t.Errorf("API calls = %d, want 2 when no history ID is given", got)
+	}
+}
+
+func TestFetchCache_StaleEntriesRefetched(t *testing.T) {
+	var historyID atomic.Uint64
+
+	svc, calls := newCountingGmailService(t, &historyID)
+
+	cache, err := NewFetchCache(t.TempDir(), time.Hour)
+	if err != nil {
+		t.Fatalf("NewFetchCache failed: %v", err)
+	}
+
+	now := time.Now()
+	cache.now = func() time.Time { return now }
+	svc.SetCache(cache)
+
+	if _, err := svc.getMessageCached("m1", 0); err != nil {
+		t.Fatalf("getMessageCached failed: %v", err)
+	}
+
+	now = now.Add(30 * time.Minute)
+
+	if _, err := svc.getMessageCached("m1", 0); err != nil {
+		t.Fatalf("getMessageCached failed: %v", err)
+	}
+
+	if got := calls.Load(); got != 1 {
+		t.Fatalf("API calls = %d, want 1 within TTL", got)
+	}
+
+	now = now.Add(2 * time.Hour)
+
+	msg, err := svc.getMessageCached("m1", 0)
+	if err != nil {
+		t.Fatalf("getMessageCached failed: %v", err)
+	}
+
+	if msg.Snippet != "hello" {
+		t.Errorf("Snippet = %q, want %q", msg.Snippet, "hello")
+	}
+
+	if got := calls.Load(); got != 2 {
+		t.Errorf("API calls = %d, want 2 after the entry expired", got)
+	}
+}
+
+func TestFetchCache_DisabledWithoutCache(t *testing.T) {
+	var historyID atomic.Uint64
+
+	svc, calls := newCountingGmailService(t, &historyID)
+
+	for range 2 {
+		if _, err := svc.getThreadCached("t1", 0); err != nil {
+			t.Fatalf("getThreadCached failed: %v", err)
+		}
+	}
+
+	if got := calls.Load(); got != 2 {
+		t.Errorf("API calls = %d, want 2 with no cache configured", got)
+	}
+}
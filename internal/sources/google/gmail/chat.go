@@ -0,0 +1,82 @@
+package gmail
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"pkm-sync/pkg/models"
+
+	"google.golang.org/api/gmail/v1"
+)
+
+const (
+	// labelChat is the system label Gmail puts on Google Chat (and legacy
+	// Hangouts/SMS) messages.
+	labelChat = "CHAT"
+
+	// itemTypeChat is the item type of chat messages and conversations.
+	itemTypeChat = "chat"
+)
+
+// ErrChatMessage is returned for a Google Chat message when include_chats is
+// off; callers skip the message.
+var ErrChatMessage = errors.New("google chat message (set gmail.include_chats to sync it)")
+
+// isChatMessage reports whether msg carries the CHAT label.
+func isChatMessage(msg *gmail.Message) bool {
+	return msg != nil && slices.Contains(msg.LabelIds, labelChat)
+}
+
+// chatParticipants returns the display names (else addresses) of the senders
+// and recipients of messages, in order of first appearance.
+func chatParticipants(messages ...*gmail.Message) []string {
+	var participants []string
+
+	for _, msg := range messages {
+		people := append([]EmailRecipient{extractSender(msg)}, extractRecipients(msg, "to")...)
+
+		for _, person := range people {
+			name := person.Name
+			if name == "" {
+				name = person.Email
+			}
+
+			if name != "" && !slices.Contains(participants, name) {
+				participants = append(participants, name)
+			}
+		}
+	}
+
+	return participants
+}
+
+// chatTitle names a chat by its participants and time; chats have no subject.
+func chatTitle(participants []string, at time.Time) string {
+	who := "Google Chat"
+	if len(participants) > 0 {
+		who = "Chat with " + strings.Join(participants, ", ")
+	}
+
+	if at.IsZero() {
+		return who
+	}
+
+	return fmt.Sprintf("%s (%s)", who, at.Format("2006-01-02 15:04"))
+}
+
+// markChat turns an email item built from chat messages into a chat item:
+// participant/time title, chat type and tag, and the participants list.
+func markChat(item *models.Item, messages ...*gmail.Message) {
+	participants := chatParticipants(messages...)
+
+	item.Title = chatTitle(participants, item.CreatedAt)
+	item.ItemType = itemTypeChat
+	item.Metadata["participants"] = participants
+
+	if !slices.Contains(item.Tags, itemTypeChat) {
+		item.Tags = append(item.Tags, itemTypeChat)
+	}
+}
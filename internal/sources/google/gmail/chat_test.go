@@ -0,0 +1,121 @@
+package gmail
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"pkm-sync/pkg/models"
+
+	"google.golang.org/api/gmail/v1"
+)
+
+// createChatMessage returns a Google Chat message as the Gmail API serves it:
+// CHAT label, no Subject header.
+func createChatMessage(id, from, to string, sent time.Time) *gmail.Message {
+	return &gmail.Message{
+		Id:           id,
+		ThreadId:     "chat-thread",
+		LabelIds:     []string{labelChat},
+		InternalDate: sent.UnixMilli(),
+		Payload: &gmail.MessagePart{
+			MimeType: "text/plain",
+			Headers: []*gmail.MessagePartHeader{
+				{Name: "From", Value: from},
+				{Name: "To", Value: to},
+			},
+			Body: &gmail.MessagePartBody{Data: "bHVuY2g_"}, // "lunch?"
+		},
+	}
+}
+
+func TestFromGmailMessage_ChatSkippedByDefault(t *testing.T) {
+	msg := createChatMessage("c1", "Alice Smith <alice@example.com>", "bob@example.com",
+		time.Date(2026, 3, 2, 12, 30, 0, 0, time.UTC))
+
+	_, err := FromGmailMessage(msg, models.GmailSourceConfig{})
+	if !errors.Is(err, ErrChatMessage) {
+		t.Fatalf("expected ErrChatMessage, got %v", err)
+	}
+}
+
+func TestFromGmailMessage_ChatRenderedWhenIncluded(t *testing.T) {
+	msg := createChatMessage("c1", "Alice Smith <alice@example.com>", "bob@example.com",
+		time.Date(2026, 3, 2, 12, 30, 0, 0, time.UTC))
+
+	item, err := FromGmailMessage(msg, models.GmailSourceConfig{IncludeChats: true})
+	if err != nil {
+		t.Fatalf("FromGmailMessage: %v", err)
+	}
+
+	if item.ItemType != "chat" {
+		t.Errorf("ItemType = %q, want chat", item.ItemType)
+	}
+
+	if want := "Chat with Alice Smith, bob@example.com (2026-03-02 12:30)"; item.Title != want {
+		t.Errorf("Title = %q, want %q", item.Title, want)
+	}
+
+	participants, _ := item.Metadata["participants"].([]string)
+	if !slices.Equal(participants, []string{"Alice Smith", "bob@example.com"}) {
+		t.Errorf("participants = %v", item.Metadata["participants"])
+	}
+
+	if !slices.Contains(item.Tags, "chat") {
+		t.Errorf("tags = %v, want chat tag", item.Tags)
+	}
+
+	if !strings.Contains(item.Content, "lunch?") {
+		t.Errorf("content = %q, want the chat text", item.Content)
+	}
+}
+
+func TestFromGmailMessage_EmailNotTreatedAsChat(t *testing.T) {
+	item, err := FromGmailMessage(createSimpleTextMessage(), models.GmailSourceConfig{IncludeChats: true})
+	if err != nil {
+		t.Fatalf("FromGmailMessage: %v", err)
+	}
+
+	if item.ItemType != "email" || item.Title != "Test Subject" {
+		t.Errorf("email converted as %q %q, want email with its subject", item.ItemType, item.Title)
+	}
+}
+
+func TestFromGmailThread_ChatConversation(t *testing.T) {
+	start := time.Date(2026, 3, 2, 12, 30, 0, 0, time.UTC)
+	thread := &gmail.Thread{
+		Id: "chat-thread",
+		Messages: []*gmail.Message{
+			createChatMessage("c1", "Alice Smith <alice@example.com>", "Bob Jones <bob@example.com>", start),
+			createChatMessage("c2", "Bob Jones <bob@example.com>", "Alice Smith <alice@example.com>",
+				start.Add(time.Minute)),
+		},
+	}
+
+	if _, err := FromGmailThread(thread, models.GmailSourceConfig{}, nil); !errors.Is(err, ErrChatMessage) {
+		t.Fatalf("expected ErrChatMessage by default, got %v", err)
+	}
+
+	item, err := FromGmailThread(thread, models.GmailSourceConfig{IncludeChats: true}, nil)
+	if err != nil {
+		t.Fatalf("FromGmailThread: %v", err)
+	}
+
+	if item.ItemType != "chat" || item.Title != "Chat with Alice Smith, Bob Jones (2026-03-02 12:30)" {
+		t.Errorf("got %q %q, want a chat titled by participants and start time", item.ItemType, item.Title)
+	}
+}
+
+func TestBuildQuery_ChatExclusion(t *testing.T) {
+	since := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	if got := buildQuery(models.GmailSourceConfig{}, since); !strings.Contains(got, "-in:chats") {
+		t.Errorf("buildQuery() = %q, want chats excluded by default", got)
+	}
+
+	if got := buildQuery(models.GmailSourceConfig{IncludeChats: true}, since); strings.Contains(got, "chats") {
+		t.Errorf("buildQuery() = %q, want no chat exclusion with include_chats", got)
+	}
+}
@@ -0,0 +1,156 @@
+package gmail
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strings"
+	"sync"
+
+	"google.golang.org/api/googleapi"
+)
+
+// maxRateLimitRequeues is how often one item is put back in the queue after
+// a rate-limit error before it is given up as skipped.
+const maxRateLimitRequeues = 5
+
+// minRampWindow is the fewest consecutive successes that raise the limit, so
+// a low limit does not climb straight back into the quota that lowered it.
+const minRampWindow = 10
+
+// concurrencyBounds are the limits an adaptiveLimiter moves between.
+type concurrencyBounds struct {
+	min int
+	max int
+}
+
+// concurrencyBounds resolves min_concurrency and max_concurrency. The ceiling
+// defaults to defaultConcurrentWorkers (throttledConcurrentWorkers when
+// request_delay is high) and never exceeds max_requests, which stays the hard
+// cap on calls per sync.
+func (s *Service) concurrencyBounds() concurrencyBounds {
+	bounds := concurrencyBounds{min: s.config.MinConcurrency, max: s.config.MaxConcurrency}
+
+	if bounds.max <= 0 {
+		bounds.max = defaultConcurrentWorkers
+		if s.config.RequestDelay > highDelayThreshold {
+			bounds.max = throttledConcurrentWorkers
+		}
+	}
+
+	if s.config.MaxRequests > 0 {
+		bounds.max = min(bounds.max, s.config.MaxRequests)
+	}
+
+	bounds.min = min(max(bounds.min, 1), bounds.max)
+
+	return bounds
+}
+
+// adaptiveLimiter bounds the number of in-flight API calls with an AIMD
+// policy: a rate-limit error halves the limit, and each window of successes
+// (as many calls as the current limit, at least minRampWindow) raises it by
+// one. It starts at the ceiling, so a mailbox that never hits a quota keeps
+// full throughput.
+type adaptiveLimiter struct {
+	mu        sync.Mutex
+	bounds    concurrencyBounds
+	limit     int
+	inFlight  int
+	successes int
+	// wake is closed and replaced whenever a slot frees up or the limit changes.
+	wake chan struct{}
+}
+
+func newAdaptiveLimiter(bounds concurrencyBounds) *adaptiveLimiter {
+	return &adaptiveLimiter{
+		bounds: bounds,
+		limit:  bounds.max,
+		wake:   make(chan struct{}),
+	}
+}
+
+// acquire waits for a free slot. It returns false when ctx is done first.
+func (l *adaptiveLimiter) acquire(ctx context.Context) bool {
+	for {
+		l.mu.Lock()
+
+		if l.inFlight < l.limit {
+			l.inFlight++
+			l.mu.Unlock()
+
+			return true
+		}
+
+		wake := l.wake
+		l.mu.Unlock()
+
+		select {
+		case <-ctx.Done():
+			return false
+		case <-wake:
+		}
+	}
+}
+
+// release frees a slot and adjusts the limit for the call's outcome.
+func (l *adaptiveLimiter) release(rateLimited bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.inFlight--
+
+	switch {
+	case rateLimited:
+		l.successes = 0
+
+		if reduced := max(l.limit/2, l.bounds.min); reduced < l.limit {
+			l.limit = reduced
+			slog.Info("Gmail rate limit hit, reducing concurrency", "workers", l.limit)
+		}
+	case l.limit < l.bounds.max:
+		l.successes++
+
+		if l.successes >= max(l.limit, minRampWindow) {
+			l.successes = 0
+			l.limit++
+			slog.Debug("Gmail calls succeeding, raising concurrency", "workers", l.limit)
+		}
+	}
+
+	close(l.wake)
+	l.wake = make(chan struct{})
+}
+
+// currentLimit returns the current concurrency limit.
+func (l *adaptiveLimiter) currentLimit() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.limit
+}
+
+// isRateLimitError reports whether err is Gmail quota feedback: 429, or a
+// 403 whose reason names a rate limit (other 403s are permission errors).
+func isRateLimitError(err error) bool {
+	var googleErr *googleapi.Error
+	if !errors.As(err, &googleErr) {
+		return false
+	}
+
+	switch googleErr.Code {
+	case http.StatusTooManyRequests:
+		return true
+	case http.StatusForbidden:
+		for _, item := range googleErr.Errors {
+			if strings.Contains(strings.ToLower(item.Reason), "ratelimitexceeded") {
+				return true
+			}
+		}
+
+		return strings.Contains(strings.ToLower(googleErr.Message), "rate limit")
+	default:
+		return false
+	}
+}
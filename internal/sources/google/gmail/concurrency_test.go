@@ -0,0 +1,212 @@
+package gmail
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"pkm-sync/pkg/models"
+
+	"google.golang.org/api/googleapi"
+)
+
+func TestAdaptiveLimiter_AIMD(t *testing.T) {
+	limiter := newAdaptiveLimiter(concurrencyBounds{min: 1, max: 8})
+	ctx := context.Background()
+
+	call := func(rateLimited bool) {
+		if !limiter.acquire(ctx) {
+			t.Fatal("acquire failed")
+		}
+
+		limiter.release(rateLimited)
+	}
+
+	for _, want := range []int{4, 2, 1, 1} {
+		call(true)
+
+		if got := limiter.currentLimit(); got != want {
+			t.Fatalf("after rate limit: limit = %d, want %d", got, want)
+		}
+	}
+
+	// One window of successes adds one worker.
+	for _, want := range []int{2, 3} {
+		for range minRampWindow - 1 {
+			call(false)
+		}
+
+		if got := limiter.currentLimit(); got != want-1 {
+			t.Fatalf("before the window ends: limit = %d, want %d", got, want-1)
+		}
+
+		call(false)
+
+		if got := limiter.currentLimit(); got != want {
+			t.Fatalf("after a window of successes: limit = %d, want %d", got, want)
+		}
+	}
+
+	for range 200 {
+		call(false)
+	}
+
+	if got := limiter.currentLimit(); got != 8 {
+		t.Errorf("limit = %d, want the ceiling 8", got)
+	}
+}
+
+func TestAdaptiveLimiter_AcquireHonorsContext(t *testing.T) {
+	limiter := newAdaptiveLimiter(concurrencyBounds{min: 1, max: 1})
+
+	if !limiter.acquire(context.Background()) {
+		t.Fatal("first acquire should succeed")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	if limiter.acquire(ctx) {
+		t.Error("acquire beyond the limit should wait and give up when ctx is done")
+	}
+}
+
+func TestFetchConcurrently_BacksOffOnRateLimit(t *testing.T) {
+	// The mock quota allows two calls at a time and answers 429 beyond that.
+	const allowed = 2
+
+	var (
+		inFlight    int32
+		rateLimited int32
+		mu          sync.Mutex
+		calls       int
+		lateCalls   int // calls past the first half
+		lateLimited int
+	)
+
+	items := make([]string, 80)
+	for i := range items {
+		items[i] = fmt.Sprintf("t%d", i)
+	}
+
+	fetch := func(id string) (string, error) {
+		n := atomic.AddInt32(&inFlight, 1)
+		defer atomic.AddInt32(&inFlight, -1)
+
+		time.Sleep(2 * time.Millisecond)
+
+		mu.Lock()
+		calls++
+		late := calls > len(items)/2
+
+		if late {
+			lateCalls++
+		}
+		mu.Unlock()
+
+		if n > allowed {
+			atomic.AddInt32(&rateLimited, 1)
+
+			if late {
+				mu.Lock()
+				lateLimited++
+				mu.Unlock()
+			}
+
+			return "", &googleapi.Error{Code: http.StatusTooManyRequests, Message: "Too many concurrent requests"}
+		}
+
+		return id, nil
+	}
+
+	results, skipped := fetchConcurrently(context.Background(), concurrencyBounds{min: 1, max: 10},
+		0, items, func(s string) string { return s }, fetch, "thread")
+
+	if len(results) != len(items) || skipped != 0 {
+		t.Fatalf("got %d results, %d skipped; want all %d fetched", len(results), skipped, len(items))
+	}
+
+	// At a fixed 10 workers nearly every call would be rejected; backing off
+	// keeps 429s rare once the limit has adapted.
+	if lateLimited*4 > lateCalls {
+		t.Errorf("%d of %d calls in the second half were rate limited; fetcher did not back off",
+			lateLimited, lateCalls)
+	}
+
+	if rateLimited == 0 {
+		t.Error("mock never rate limited; test does not exercise backoff")
+	}
+}
+
+func TestFetchConcurrently_SkipsOtherErrors(t *testing.T) {
+	fetch := func(id string) (string, error) {
+		if id == "bad" {
+			return "", &googleapi.Error{Code: http.StatusNotFound}
+		}
+
+		return id, nil
+	}
+
+	results, skipped := fetchConcurrently(context.Background(), concurrencyBounds{min: 1, max: 3},
+		0, []string{"a", "bad", "b"}, func(s string) string { return s }, fetch, "message")
+
+	if len(results) != 2 || skipped != 1 {
+		t.Errorf("got %d results, %d skipped; want 2 and 1", len(results), skipped)
+	}
+}
+
+func TestIsRateLimitError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"429", &googleapi.Error{Code: 429}, true},
+		{"wrapped 429", fmt.Errorf("thread t1: %w", &googleapi.Error{Code: 429}), true},
+		{"403 user rate limit", &googleapi.Error{
+			Code: 403, Errors: []googleapi.ErrorItem{{Reason: "userRateLimitExceeded"}},
+		}, true},
+		{"403 permission", &googleapi.Error{
+			Code: 403, Errors: []googleapi.ErrorItem{{Reason: "forbidden"}}, Message: "Forbidden",
+		}, false},
+		{"500", &googleapi.Error{Code: 500}, false},
+		{"plain", errors.New("rate limit"), false},
+		{"nil", nil, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isRateLimitError(tt.err); got != tt.want {
+				t.Errorf("isRateLimitError() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestService_ConcurrencyBounds(t *testing.T) {
+	tests := []struct {
+		name   string
+		config models.GmailSourceConfig
+		want   concurrencyBounds
+	}{
+		{"defaults", models.GmailSourceConfig{}, concurrencyBounds{1, defaultConcurrentWorkers}},
+		{"high delay", models.GmailSourceConfig{RequestDelay: time.Second}, concurrencyBounds{1, throttledConcurrentWorkers}},
+		{"configured", models.GmailSourceConfig{MinConcurrency: 2, MaxConcurrency: 12}, concurrencyBounds{2, 12}},
+		{"max_requests caps", models.GmailSourceConfig{MaxConcurrency: 12, MaxRequests: 3}, concurrencyBounds{1, 3}},
+		{"min above max", models.GmailSourceConfig{MinConcurrency: 9, MaxConcurrency: 4}, concurrencyBounds{4, 4}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &Service{config: tt.config}
+			if got := s.concurrencyBounds(); got != tt.want {
+				t.Errorf("concurrencyBounds() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -1,6 +1,7 @@
 package gmail
 
 import (
+	"errors"
 	"fmt"
 	"log/slog"
 	"net/mail"
@@ -37,12 +38,33 @@ const (
 	hasAttachmentCondition = "has:attachment"
 )
 
+// Values of GmailSourceConfig.UndatedMessages.
+const (
+	UndatedSkip     = "skip"
+	UndatedSentinel = "sentinel"
+	UndatedNow      = "now"
+)
+
+// defaultUndatedSentinel is the date given to undated messages under the
+// "sentinel" policy when undated_sentinel_date is not set.
+var defaultUndatedSentinel = time.Unix(0, 0).UTC()
+
+// ErrUndatedMessage is returned for a message that has no usable date under
+// the default "skip" policy; callers skip the message and report it.
+var ErrUndatedMessage = errors.New("message has no parseable Date header or internal date")
+
 // EmailRecipient represents an email recipient with name and email.
 type EmailRecipient struct {
 	Name  string `json:"name"`
 	Email string `json:"email"`
 }
 
+// Address returns the bare email address. Transformers read recipients through
+// this method so they need not import the gmail package.
+func (r EmailRecipient) Address() string {
+	return r.Email
+}
+
 // FromGmailMessage converts a Gmail message to the universal Item format.
 func FromGmailMessage(msg *gmail.Message, config models.GmailSourceConfig) (*models.Item, error) {
 	return FromGmailMessageWithService(msg, config, nil)
@@ -59,17 +81,21 @@ func FromGmailMessageWithService(
 		return nil, fmt.Errorf("message is nil")
 	}
 
+	if isChatMessage(msg) && !config.IncludeChats {
+		return nil, fmt.Errorf("message %s: %w", msg.Id, ErrChatMessage)
+	}
+
 	// Extract basic information
 	subject := getSubject(msg)
 
-	content, err := getProcessedBody(msg, config)
+	content, err := getProcessedBody(msg, config, service)
 	if err != nil {
 		return nil, fmt.Errorf("failed to process email body: %w", err)
 	}
 
-	createdAt, err := getDate(msg)
+	createdAt, err := resolveDate(config, getDate(msg))
 	if err != nil {
-		return nil, fmt.Errorf("failed to parse email date: %w", err)
+		return nil, fmt.Errorf("message %s: %w", msg.Id, err)
 	}
 
 	// Build the universal item
@@ -87,6 +113,7 @@ func FromGmailMessageWithService(
 
 	// Extract comprehensive metadata
 	addBasicMetadata(item, msg)
+	addThreadModeMetadata(item, config)
 
 	// Add recipient information if enabled
 	if config.ExtractRecipients {
@@ -98,6 +125,10 @@ func FromGmailMessageWithService(
 		addHeaderMetadata(item, msg)
 	}
 
+	if isChatMessage(msg) {
+		markChat(item, msg)
+	}
+
 	// Links extraction is now handled by LinkExtractionTransformer
 
 	// Process attachments
@@ -130,20 +161,55 @@ func getSubject(msg *gmail.Message) string {
 	return ""
 }
 
-// getDate extracts and parses the date from Gmail message.
-func getDate(msg *gmail.Message) (time.Time, error) {
+// getDate extracts and parses the date from a Gmail message, falling back to
+// the internal date. It returns the zero time when neither is usable.
+func getDate(msg *gmail.Message) time.Time {
 	if msg.Payload != nil {
 		if date, err := parseDateFromHeaders(msg.Payload.Headers); err == nil {
-			return date, nil
+			return date
 		}
 	}
 
 	// Fallback to internal date (timestamp in milliseconds)
 	if msg.InternalDate > 0 {
-		return time.Unix(msg.InternalDate/1000, (msg.InternalDate%1000)*1000000), nil
+		return time.Unix(msg.InternalDate/1000, (msg.InternalDate%1000)*1000000)
+	}
+
+	return time.Time{}
+}
+
+// resolveDate applies the undated_messages policy when date is zero. Under
+// the default "skip" policy it returns ErrUndatedMessage rather than guessing,
+// since dating a message at sync time would file it under today's notes.
+func resolveDate(config models.GmailSourceConfig, date time.Time) (time.Time, error) {
+	if !date.IsZero() {
+		return date, nil
 	}
 
-	return time.Now(), fmt.Errorf("could not parse date from message")
+	switch config.UndatedMessages {
+	case UndatedNow:
+		return time.Now(), nil
+	case UndatedSentinel:
+		return UndatedSentinelDate(config)
+	default:
+		return time.Time{}, ErrUndatedMessage
+	}
+}
+
+// UndatedSentinelDate parses undated_sentinel_date (YYYY-MM-DD, UTC),
+// defaulting to the Unix epoch.
+func UndatedSentinelDate(config models.GmailSourceConfig) (time.Time, error) {
+	if config.UndatedSentinelDate == "" {
+		return defaultUndatedSentinel, nil
+	}
+
+	date, err := time.Parse(time.DateOnly, config.UndatedSentinelDate)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid undated_sentinel_date %q (want YYYY-MM-DD): %w",
+			config.UndatedSentinelDate, err)
+	}
+
+	return date, nil
 }
 
 func parseDateFromHeaders(headers []*gmail.MessagePartHeader) (time.Time, error) {
@@ -169,8 +235,8 @@ func parseDateFromHeaders(headers []*gmail.MessagePartHeader) (time.Time, error)
 }
 
 // getProcessedBody extracts and processes the email body based on configuration.
-func getProcessedBody(msg *gmail.Message, config models.GmailSourceConfig) (string, error) {
-	processor := NewContentProcessor(config)
+func getProcessedBody(msg *gmail.Message, config models.GmailSourceConfig, service *Service) (string, error) {
+	processor := NewContentProcessorWithService(config, service)
 
 	return processor.ProcessEmailBody(msg)
 }
@@ -189,6 +255,21 @@ func addBasicMetadata(item *models.Item, msg *gmail.Message) {
 	}
 }
 
+// addThreadModeMetadata records the source's thread mode on the item so the
+// thread_grouping transformer groups it the way this source was configured.
+// Grouping itself happens only in the transformer.
+func addThreadModeMetadata(item *models.Item, config models.GmailSourceConfig) {
+	if config.ThreadMode == "" {
+		return
+	}
+
+	item.Metadata["thread_mode"] = config.ThreadMode
+
+	if config.ThreadSummaryLength > 0 {
+		item.Metadata["thread_summary_length"] = config.ThreadSummaryLength
+	}
+}
+
 // addRecipientMetadata extracts and adds recipient information to metadata.
 func addRecipientMetadata(item *models.Item, msg *gmail.Message) {
 	item.Metadata["from"] = extractSender(msg)
@@ -440,7 +521,8 @@ func hasAttachments(msg *gmail.Message) bool {
 }
 
 // FromGmailThread converts a Gmail thread to the universal Item format.
-// It aggregates all messages in the thread chronologically into a single item.
+// It aggregates all messages in the thread chronologically into a single item,
+// marked with thread_consolidated so the thread_grouping transformer leaves it alone.
 func FromGmailThread(thread *gmail.Thread, config models.GmailSourceConfig, service *Service) (*models.Item, error) {
 	if thread == nil {
 		return nil, fmt.Errorf("thread is nil")
@@ -468,25 +550,41 @@ func FromGmailThread(thread *gmail.Thread, config models.GmailSourceConfig, serv
 	}
 
 	firstMsg := messages[0]
+	if isChatMessage(firstMsg) && !config.IncludeChats {
+		return nil, fmt.Errorf("thread %s: %w", thread.Id, ErrChatMessage)
+	}
+
 	subject := getSubject(firstMsg)
 
-	createdAt, err := getDate(firstMsg)
-	if err != nil {
-		return nil, fmt.Errorf("failed to parse thread start date: %w", err)
-	}
+	// The thread spans its dated messages; undated ones are kept but do not
+	// move its start or end.
+	var createdAt, updatedAt time.Time
+
+	for _, msg := range messages {
+		if date := getDate(msg); !date.IsZero() {
+			if createdAt.IsZero() {
+				createdAt = date
+			}
 
-	lastMsg := messages[len(messages)-1]
+			updatedAt = date
+		}
+	}
 
-	updatedAt, err := getDate(lastMsg)
+	createdAt, err := resolveDate(config, createdAt)
 	if err != nil {
+		return nil, fmt.Errorf("thread %s: %w", thread.Id, err)
+	}
+
+	if updatedAt.IsZero() {
 		updatedAt = createdAt
 	}
 
 	// Build aggregated content from all messages.
 	var contentBuilder strings.Builder
 
+	processor := NewContentProcessorWithService(config, service)
+
 	for i, msg := range messages {
-		processor := NewContentProcessor(config)
 
 		msgContent, err := processor.ProcessEmailBody(msg)
 		if err != nil {
@@ -498,9 +596,13 @@ func FromGmailThread(thread *gmail.Thread, config models.GmailSourceConfig, serv
 			contentBuilder.WriteString("\n\n---\n\n")
 		}
 
-		msgDate, _ := getDate(msg)
+		msgDate := "unknown"
+		if date := getDate(msg); !date.IsZero() {
+			msgDate = date.Format("2006-01-02 15:04:05")
+		}
+
 		contentBuilder.WriteString(fmt.Sprintf("**From:** %s  \n", getHeader(msg, "from")))
-		contentBuilder.WriteString(fmt.Sprintf("**Date:** %s  \n\n", msgDate.Format("2006-01-02 15:04:05")))
+		contentBuilder.WriteString(fmt.Sprintf("**Date:** %s  \n\n", msgDate))
 		contentBuilder.WriteString(msgContent)
 	}
 
@@ -534,6 +636,23 @@ func FromGmailThread(thread *gmail.Thread, config models.GmailSourceConfig, serv
 	item.Metadata["message_count"] = len(messages)
 	item.Metadata["labels"] = labels
 	item.Metadata["snippet"] = thread.Snippet
+	item.Metadata["thread_consolidated"] = true
+
+	if isChatMessage(firstMsg) {
+		markChat(item, messages...)
+	}
+
+	if service != nil {
+		if missing := service.MissingThreadMessages(thread.Id); len(missing) > 0 {
+			item.Metadata["partial_thread"] = true
+			item.Metadata["missing_message_ids"] = missing
+		}
+
+		if omitted := service.TruncatedThreadMessages(thread.Id); omitted > 0 {
+			item.Metadata["thread_truncated"] = true
+			item.Metadata["omitted_message_count"] = omitted
+		}
+	}
 
 	// Process attachments if enabled.
 	if config.DownloadAttachments {
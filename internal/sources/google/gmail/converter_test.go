@@ -1,6 +1,7 @@
 package gmail
 
 import (
+	"errors"
 	"strings"
 	"testing"
 	"time"
@@ -298,6 +299,132 @@ func TestMatchesCondition(t *testing.T) {
 	}
 }
 
+// undatedMessage has an unparseable Date header and the given internal date.
+func undatedMessage(id string, internalDate int64) *gmail.Message {
+	msg := createSimpleTextMessage()
+	msg.Id = id
+	msg.InternalDate = internalDate
+
+	for _, h := range msg.Payload.Headers {
+		if h.Name == "Date" {
+			h.Value = "sometime last spring"
+		}
+	}
+
+	return msg
+}
+
+func TestGetDate(t *testing.T) {
+	internal := time.Date(2019, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	if got := getDate(undatedMessage("m1", internal.UnixMilli())); !got.Equal(internal) {
+		t.Errorf("unparseable Date header should fall back to the internal date, got %v", got)
+	}
+
+	if got := getDate(undatedMessage("m2", 0)); !got.IsZero() {
+		t.Errorf("no usable date should give the zero time, got %v", got)
+	}
+
+	if got := getDate(&gmail.Message{Id: "m3"}); !got.IsZero() {
+		t.Errorf("message without payload or internal date should give the zero time, got %v", got)
+	}
+}
+
+func TestFromGmailMessage_UndatedPolicies(t *testing.T) {
+	msg := undatedMessage("undated", 0)
+
+	t.Run("skip by default", func(t *testing.T) {
+		_, err := FromGmailMessage(msg, models.GmailSourceConfig{})
+		if !errors.Is(err, ErrUndatedMessage) {
+			t.Fatalf("expected ErrUndatedMessage, got %v", err)
+		}
+
+		if !strings.Contains(err.Error(), "undated") {
+			t.Errorf("error should name the message: %v", err)
+		}
+	})
+
+	t.Run("sentinel defaults to the epoch", func(t *testing.T) {
+		item, err := FromGmailMessage(msg, models.GmailSourceConfig{UndatedMessages: UndatedSentinel})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if !item.CreatedAt.Equal(time.Unix(0, 0)) || !item.UpdatedAt.Equal(item.CreatedAt) {
+			t.Errorf("CreatedAt = %v, UpdatedAt = %v; want the epoch", item.CreatedAt, item.UpdatedAt)
+		}
+	})
+
+	t.Run("custom sentinel", func(t *testing.T) {
+		item, err := FromGmailMessage(msg, models.GmailSourceConfig{
+			UndatedMessages:     UndatedSentinel,
+			UndatedSentinelDate: "2000-01-01",
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if want := time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC); !item.CreatedAt.Equal(want) {
+			t.Errorf("CreatedAt = %v, want %v", item.CreatedAt, want)
+		}
+	})
+
+	t.Run("now", func(t *testing.T) {
+		before := time.Now()
+
+		item, err := FromGmailMessage(msg, models.GmailSourceConfig{UndatedMessages: UndatedNow})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if item.CreatedAt.Before(before) || item.CreatedAt.After(time.Now()) {
+			t.Errorf("CreatedAt = %v, want the sync time", item.CreatedAt)
+		}
+	})
+
+	t.Run("internal date is used first", func(t *testing.T) {
+		internal := time.Date(2019, 6, 1, 12, 0, 0, 0, time.UTC)
+
+		item, err := FromGmailMessage(undatedMessage("dated", internal.UnixMilli()), models.GmailSourceConfig{})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if !item.CreatedAt.Equal(internal) {
+			t.Errorf("CreatedAt = %v, want the internal date %v", item.CreatedAt, internal)
+		}
+	})
+}
+
+func TestFromGmailThread_UndatedMessages(t *testing.T) {
+	first := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+
+	dated := undatedMessage("dated", first.UnixMilli())
+	undated := undatedMessage("undated", 0)
+
+	// Sorting by internal date puts the undated message first; the thread
+	// still starts at its first dated message.
+	item, err := FromGmailThread(&gmail.Thread{Id: "t1", Messages: []*gmail.Message{dated, undated}},
+		models.GmailSourceConfig{}, nil)
+	if err != nil {
+		t.Fatalf("a thread with some dated messages should convert: %v", err)
+	}
+
+	if !item.CreatedAt.Equal(first) || !item.UpdatedAt.Equal(first) {
+		t.Errorf("CreatedAt = %v, UpdatedAt = %v; want %v", item.CreatedAt, item.UpdatedAt, first)
+	}
+
+	if !strings.Contains(item.Content, "**Date:** unknown") {
+		t.Errorf("undated message should be shown with an unknown date:\n%s", item.Content)
+	}
+
+	_, err = FromGmailThread(&gmail.Thread{Id: "t2", Messages: []*gmail.Message{undated}},
+		models.GmailSourceConfig{}, nil)
+	if !errors.Is(err, ErrUndatedMessage) {
+		t.Errorf("a thread with no dated messages should be skipped, got %v", err)
+	}
+}
+
 // Helper functions for creating test data
 
 func createSimpleTextMessage() *gmail.Message {
@@ -0,0 +1,407 @@
+package gmail
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"log/slog"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
+	"strings"
+	"time"
+
+	"pkm-sync/pkg/models"
+
+	"google.golang.org/api/gmail/v1"
+)
+
+// Values of GmailSourceConfig.EmbeddedMessages.
+const (
+	EmbeddedMessagesOff    = "off"
+	EmbeddedMessagesInline = "inline"
+	EmbeddedMessagesItem   = "item"
+)
+
+const mimeTypeRFC822 = "message/rfc822"
+
+// EmbeddedMessage is an email attached to another one as a message/rfc822
+// part, typically a message forwarded "as attachment".
+type EmbeddedMessage struct {
+	Filename  string
+	MessageID string
+	Subject   string
+	From      string
+	To        string
+	Date      time.Time
+	Body      string
+}
+
+// headerGetter is satisfied by both mail.Header and textproto.MIMEHeader.
+type headerGetter interface {
+	Get(key string) string
+}
+
+var headerDecoder = new(mime.WordDecoder)
+
+// embeddedMessagesEnabled reports whether embedded_messages asks for
+// message/rfc822 parts to be parsed.
+func (p *ContentProcessor) embeddedMessagesEnabled() bool {
+	mode := p.config.EmbeddedMessages
+
+	return mode == EmbeddedMessagesInline || mode == EmbeddedMessagesItem
+}
+
+// ExtractEmbeddedMessages parses the message/rfc822 parts of msg. Parts that
+// cannot be parsed are logged and skipped.
+func (p *ContentProcessor) ExtractEmbeddedMessages(msg *gmail.Message) []EmbeddedMessage {
+	if msg == nil || msg.Payload == nil {
+		return nil
+	}
+
+	var embedded []EmbeddedMessage
+
+	p.collectEmbeddedMessages(msg.Payload, msg.Id, &embedded)
+
+	return embedded
+}
+
+// collectEmbeddedMessages walks the part tree. Messages nested inside an
+// embedded message stay part of that message's body and are not collected.
+func (p *ContentProcessor) collectEmbeddedMessages(part *gmail.MessagePart, messageID string, out *[]EmbeddedMessage) {
+	if part == nil {
+		return
+	}
+
+	if part.MimeType == mimeTypeRFC822 {
+		em, err := p.parseEmbeddedPart(part, messageID)
+		if err != nil {
+			slog.Warn("Failed to parse embedded message",
+				"message_id", messageID,
+				"filename", part.Filename,
+				"error", err)
+
+			return
+		}
+
+		*out = append(*out, em)
+
+		return
+	}
+
+	for _, subPart := range part.Parts {
+		p.collectEmbeddedMessages(subPart, messageID, out)
+	}
+}
+
+// parseEmbeddedPart reads one message/rfc822 part. Gmail usually returns the
+// embedded message already parsed into sub-parts; otherwise the raw message
+// is in the part body, inline or as an attachment to download.
+func (p *ContentProcessor) parseEmbeddedPart(part *gmail.MessagePart, messageID string) (EmbeddedMessage, error) {
+	if len(part.Parts) > 0 {
+		em := p.embeddedFromParts(part)
+		em.Filename = part.Filename
+
+		return em, nil
+	}
+
+	raw, err := p.embeddedRawData(part, messageID)
+	if err != nil {
+		return EmbeddedMessage{}, err
+	}
+
+	em, err := parseRawMessage(raw)
+	if err != nil {
+		return EmbeddedMessage{}, err
+	}
+
+	em.Filename = part.Filename
+
+	return em, nil
+}
+
+// embeddedFromParts builds an EmbeddedMessage from the sub-parts Gmail
+// parsed out of a message/rfc822 part. The embedded message's headers sit on
+// its root part; the rfc822 part itself only carries MIME headers.
+func (p *ContentProcessor) embeddedFromParts(part *gmail.MessagePart) EmbeddedMessage {
+	root := part.Parts[0]
+	headers := append(append([]*gmail.MessagePartHeader{}, root.Headers...), part.Headers...)
+
+	get := func(name string) string {
+		for _, h := range headers {
+			if strings.EqualFold(h.Name, name) {
+				return h.Value
+			}
+		}
+
+		return ""
+	}
+
+	em := EmbeddedMessage{
+		MessageID: get("Message-ID"),
+		Subject:   get("Subject"),
+		From:      get("From"),
+		To:        get("To"),
+	}
+
+	if date, err := parseDateFromHeaders(headers); err == nil {
+		em.Date = date
+	}
+
+	// Plain text reads better than raw HTML once inlined into another note.
+	em.Body = p.extractBodyPart(root, "text/plain")
+	if em.Body == "" {
+		em.Body = p.extractBodyPart(root, "text/html")
+	}
+
+	return em
+}
+
+// embeddedRawData returns the raw RFC 822 bytes of a part that Gmail did not
+// parse, downloading them when they are stored as an attachment.
+func (p *ContentProcessor) embeddedRawData(part *gmail.MessagePart, messageID string) ([]byte, error) {
+	if part.Body == nil {
+		return nil, fmt.Errorf("embedded message has no body")
+	}
+
+	data := part.Body.Data
+
+	if data == "" && part.Body.AttachmentId != "" {
+		if p.service == nil {
+			return nil, fmt.Errorf("service not available to download embedded message")
+		}
+
+		body, err := p.service.GetAttachment(messageID, part.Body.AttachmentId)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch embedded message: %w", err)
+		}
+
+		data = body.Data
+	}
+
+	if data == "" {
+		return nil, fmt.Errorf("embedded message has no body")
+	}
+
+	decoded, err := base64.URLEncoding.DecodeString(data)
+	if err != nil {
+		decoded, err = base64.StdEncoding.DecodeString(data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode embedded message: %w", err)
+		}
+	}
+
+	return decoded, nil
+}
+
+// parseRawMessage parses an RFC 822 message, keeping its text body.
+func parseRawMessage(raw []byte) (EmbeddedMessage, error) {
+	msg, err := mail.ReadMessage(bytes.NewReader(raw))
+	if err != nil {
+		return EmbeddedMessage{}, fmt.Errorf("invalid embedded message: %w", err)
+	}
+
+	em := EmbeddedMessage{
+		MessageID: msg.Header.Get("Message-ID"),
+		Subject:   decodeHeader(msg.Header.Get("Subject")),
+		From:      decodeHeader(msg.Header.Get("From")),
+		To:        decodeHeader(msg.Header.Get("To")),
+	}
+
+	if date, err := msg.Header.Date(); err == nil {
+		em.Date = date
+	}
+
+	plain, html, err := readTextBody(msg.Header, msg.Body)
+	if err != nil {
+		return EmbeddedMessage{}, fmt.Errorf("failed to read embedded message body: %w", err)
+	}
+
+	em.Body = plain
+	if em.Body == "" {
+		em.Body = html
+	}
+
+	return em, nil
+}
+
+// readTextBody returns the first text/plain and text/html bodies of a MIME
+// entity, descending into multipart containers and skipping attachments.
+func readTextBody(header headerGetter, body io.Reader) (string, string, error) {
+	mediaType, params, err := mime.ParseMediaType(header.Get("Content-Type"))
+	if err != nil {
+		mediaType = "text/plain"
+	}
+
+	if strings.HasPrefix(mediaType, "multipart/") {
+		var plain, html string
+
+		reader := multipart.NewReader(body, params["boundary"])
+
+		for {
+			part, err := reader.NextPart()
+			if err == io.EOF {
+				break
+			}
+
+			if err != nil {
+				return plain, html, err
+			}
+
+			if strings.HasPrefix(part.Header.Get("Content-Disposition"), "attachment") {
+				continue
+			}
+
+			p, h, err := readTextBody(part.Header, part)
+			if err != nil {
+				return plain, html, err
+			}
+
+			plain, html = firstNonEmpty(plain, p), firstNonEmpty(html, h)
+		}
+
+		return plain, html, nil
+	}
+
+	if mediaType != "text/plain" && mediaType != "text/html" {
+		return "", "", nil
+	}
+
+	switch strings.ToLower(header.Get("Content-Transfer-Encoding")) {
+	case "base64":
+		body = base64.NewDecoder(base64.StdEncoding, body)
+	case "quoted-printable":
+		body = quotedprintable.NewReader(body)
+	}
+
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return "", "", err
+	}
+
+	if mediaType == "text/html" {
+		return "", string(data), nil
+	}
+
+	return string(data), "", nil
+}
+
+// decodeHeader decodes RFC 2047 encoded words, keeping the raw value when
+// they are malformed.
+func decodeHeader(value string) string {
+	decoded, err := headerDecoder.DecodeHeader(value)
+	if err != nil {
+		return value
+	}
+
+	return decoded
+}
+
+func firstNonEmpty(a, b string) string {
+	if a != "" {
+		return a
+	}
+
+	return b
+}
+
+// formatEmbeddedMessages renders embedded messages for inlining below the
+// body of the message that carries them.
+func formatEmbeddedMessages(embedded []EmbeddedMessage) string {
+	var sb strings.Builder
+
+	for _, em := range embedded {
+		sb.WriteString("\n\n---\n\n")
+		fmt.Fprintf(&sb, "**Attached message:** %s  \n", embeddedTitle(em))
+
+		if em.From != "" {
+			fmt.Fprintf(&sb, "**From:** %s  \n", em.From)
+		}
+
+		if em.To != "" {
+			fmt.Fprintf(&sb, "**To:** %s  \n", em.To)
+		}
+
+		if !em.Date.IsZero() {
+			fmt.Fprintf(&sb, "**Date:** %s  \n", em.Date.Format("2006-01-02 15:04:05"))
+		}
+
+		sb.WriteString("\n")
+		sb.WriteString(strings.TrimSpace(em.Body))
+	}
+
+	return sb.String()
+}
+
+func embeddedTitle(em EmbeddedMessage) string {
+	switch {
+	case em.Subject != "":
+		return em.Subject
+	case em.Filename != "":
+		return em.Filename
+	default:
+		return "(no subject)"
+	}
+}
+
+// EmbeddedMessageItems returns one item per message/rfc822 part of msgs when
+// embedded_messages is "item", and nil otherwise. Each item links back to
+// parent through metadata, and parent lists their IDs under
+// embedded_message_ids. Items are left out of thread grouping because they
+// belong to a different conversation than the message carrying them.
+func EmbeddedMessageItems(
+	config models.GmailSourceConfig,
+	service *Service,
+	parent *models.Item,
+	msgs ...*gmail.Message,
+) []*models.Item {
+	if config.EmbeddedMessages != EmbeddedMessagesItem {
+		return nil
+	}
+
+	processor := NewContentProcessorWithService(config, service)
+
+	var (
+		items []*models.Item
+		ids   []string
+	)
+
+	for _, msg := range msgs {
+		for i, em := range processor.ExtractEmbeddedMessages(msg) {
+			createdAt := em.Date
+			if createdAt.IsZero() {
+				createdAt = parent.CreatedAt
+			}
+
+			item := &models.Item{
+				ID:         fmt.Sprintf("%s-embedded-%d", msg.Id, i+1),
+				Title:      embeddedTitle(em),
+				Content:    em.Body,
+				SourceType: sourceTypeGmail,
+				ItemType:   "email",
+				CreatedAt:  createdAt,
+				UpdatedAt:  createdAt,
+				Tags:       append(append([]string{}, parent.Tags...), "embedded-email"),
+				Metadata: map[string]interface{}{
+					"parent_id":         parent.ID,
+					"parent_message_id": msg.Id,
+					"message_id":        em.MessageID,
+					"from":              parseEmailAddress(em.From),
+					"to":                parseEmailAddressList(em.To),
+					"filename":          em.Filename,
+				},
+			}
+
+			items = append(items, item)
+			ids = append(ids, item.ID)
+		}
+	}
+
+	if len(ids) > 0 {
+		parent.Metadata["embedded_message_ids"] = ids
+	}
+
+	return items
+}
@@ -0,0 +1,196 @@
+package gmail
+
+import (
+	"encoding/base64"
+	"strings"
+	"testing"
+
+	"pkm-sync/pkg/models"
+
+	"google.golang.org/api/gmail/v1"
+)
+
+func b64(s string) string {
+	return base64.URLEncoding.EncodeToString([]byte(s))
+}
+
+// forwardedAsAttachment returns a message whose body says "See attached" and
+// which carries rfc822 as a message/rfc822 attachment.
+func forwardedAsAttachment(rfc822 *gmail.MessagePart) *gmail.Message {
+	return &gmail.Message{
+		Id:           "outer-1",
+		ThreadId:     "thread-1",
+		InternalDate: 1767607200000,
+		Payload: &gmail.MessagePart{
+			MimeType: "multipart/mixed",
+			Headers: []*gmail.MessagePartHeader{
+				{Name: "Subject", Value: "Fwd: Contract renewal"},
+				{Name: "From", Value: "Bob <bob@example.com>"},
+				{Name: "Date", Value: "Mon, 5 Jan 2026 10:00:00 +0000"},
+			},
+			Parts: []*gmail.MessagePart{
+				{MimeType: "text/plain", Body: &gmail.MessagePartBody{Data: b64("See attached.")}},
+				rfc822,
+			},
+		},
+	}
+}
+
+// parsedRFC822Part is a message/rfc822 part as Gmail returns it: already
+// parsed, with the embedded message's headers on its root part.
+func parsedRFC822Part() *gmail.MessagePart {
+	return &gmail.MessagePart{
+		MimeType: "message/rfc822",
+		Filename: "Contract renewal.eml",
+		Parts: []*gmail.MessagePart{{
+			MimeType: "multipart/alternative",
+			Headers: []*gmail.MessagePartHeader{
+				{Name: "Subject", Value: "Contract renewal"},
+				{Name: "From", Value: "Carol <carol@vendor.example>"},
+				{Name: "To", Value: "bob@example.com"},
+				{Name: "Date", Value: "Fri, 2 Jan 2026 09:30:00 +0000"},
+				{Name: "Message-ID", Value: "<renewal@vendor.example>"},
+			},
+			Parts: []*gmail.MessagePart{
+				{MimeType: "text/plain", Body: &gmail.MessagePartBody{Data: b64("The renewal price is 10% higher.")}},
+				{MimeType: "text/html", Body: &gmail.MessagePartBody{Data: b64("<p>The renewal price is 10% higher.</p>")}},
+			},
+		}},
+	}
+}
+
+const rawEmbeddedMessage = "From: Carol <carol@vendor.example>\r\n" +
+	"To: bob@example.com\r\n" +
+	"Subject: =?UTF-8?Q?Vertrags=C3=BCbersicht?=\r\n" +
+	"Date: Fri, 2 Jan 2026 09:30:00 +0000\r\n" +
+	"MIME-Version: 1.0\r\n" +
+	"Content-Type: multipart/mixed; boundary=\"b1\"\r\n" +
+	"\r\n" +
+	"--b1\r\n" +
+	"Content-Type: text/plain; charset=utf-8\r\n" +
+	"Content-Transfer-Encoding: quoted-printable\r\n" +
+	"\r\n" +
+	"Die Verl=C3=A4ngerung ist genehmigt.\r\n" +
+	"--b1\r\n" +
+	"Content-Type: text/plain\r\n" +
+	"Content-Disposition: attachment; filename=\"notes.txt\"\r\n" +
+	"\r\n" +
+	"attachment text\r\n" +
+	"--b1--\r\n"
+
+func TestExtractEmbeddedMessages_ParsedPart(t *testing.T) {
+	processor := NewContentProcessor(models.GmailSourceConfig{EmbeddedMessages: EmbeddedMessagesInline})
+
+	embedded := processor.ExtractEmbeddedMessages(forwardedAsAttachment(parsedRFC822Part()))
+	if len(embedded) != 1 {
+		t.Fatalf("got %d embedded messages, want 1", len(embedded))
+	}
+
+	em := embedded[0]
+	if em.Subject != "Contract renewal" || em.From != "Carol <carol@vendor.example>" {
+		t.Errorf("headers = %q from %q", em.Subject, em.From)
+	}
+
+	if em.Body != "The renewal price is 10% higher." {
+		t.Errorf("Body = %q, want the plain-text part", em.Body)
+	}
+
+	if em.Date.IsZero() || em.Filename != "Contract renewal.eml" {
+		t.Errorf("Date = %v, Filename = %q", em.Date, em.Filename)
+	}
+}
+
+func TestExtractEmbeddedMessages_RawPart(t *testing.T) {
+	processor := NewContentProcessor(models.GmailSourceConfig{EmbeddedMessages: EmbeddedMessagesInline})
+
+	msg := forwardedAsAttachment(&gmail.MessagePart{
+		MimeType: "message/rfc822",
+		Filename: "forward.eml",
+		Body:     &gmail.MessagePartBody{Data: b64(rawEmbeddedMessage)},
+	})
+
+	embedded := processor.ExtractEmbeddedMessages(msg)
+	if len(embedded) != 1 {
+		t.Fatalf("got %d embedded messages, want 1", len(embedded))
+	}
+
+	if got := embedded[0].Subject; got != "Vertragsübersicht" {
+		t.Errorf("Subject = %q, want the decoded subject", got)
+	}
+
+	if got := strings.TrimSpace(embedded[0].Body); got != "Die Verlängerung ist genehmigt." {
+		t.Errorf("Body = %q, want the decoded text without the attachment", got)
+	}
+}
+
+func TestFromGmailMessage_EmbeddedMessages(t *testing.T) {
+	msg := forwardedAsAttachment(parsedRFC822Part())
+
+	t.Run("off", func(t *testing.T) {
+		item, err := FromGmailMessage(msg, models.GmailSourceConfig{})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if strings.Contains(item.Content, "**Attached message:**") {
+			t.Errorf("Content inlines the attachment although embedded_messages is off:\n%s", item.Content)
+		}
+
+		if items := EmbeddedMessageItems(models.GmailSourceConfig{}, nil, item, msg); items != nil {
+			t.Errorf("expected no embedded items, got %d", len(items))
+		}
+	})
+
+	t.Run("inline", func(t *testing.T) {
+		item, err := FromGmailMessage(msg, models.GmailSourceConfig{EmbeddedMessages: EmbeddedMessagesInline})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for _, want := range []string{
+			"See attached.",
+			"**Attached message:** Contract renewal",
+			"**From:** Carol <carol@vendor.example>",
+			"The renewal price is 10% higher.",
+		} {
+			if !strings.Contains(item.Content, want) {
+				t.Errorf("Content missing %q:\n%s", want, item.Content)
+			}
+		}
+	})
+
+	t.Run("item", func(t *testing.T) {
+		config := models.GmailSourceConfig{EmbeddedMessages: EmbeddedMessagesItem}
+
+		parent, err := FromGmailMessage(msg, config)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if parent.Content != "See attached." {
+			t.Errorf("parent Content = %q, want the outer body only", parent.Content)
+		}
+
+		items := EmbeddedMessageItems(config, nil, parent, msg)
+		if len(items) != 1 {
+			t.Fatalf("got %d embedded items, want 1", len(items))
+		}
+
+		child := items[0]
+		if child.ID != "outer-1-embedded-1" || child.Title != "Contract renewal" {
+			t.Errorf("child = %q %q", child.ID, child.Title)
+		}
+
+		if child.Content != "The renewal price is 10% higher." || child.Metadata["parent_id"] != "outer-1" {
+			t.Errorf("child content %q, parent_id %v", child.Content, child.Metadata["parent_id"])
+		}
+
+		if from, ok := child.Metadata["from"].(EmailRecipient); !ok || from.Email != "carol@vendor.example" {
+			t.Errorf("child from = %#v", child.Metadata["from"])
+		}
+
+		if ids, ok := parent.Metadata["embedded_message_ids"].([]string); !ok || len(ids) != 1 || ids[0] != child.ID {
+			t.Errorf("parent embedded_message_ids = %v", parent.Metadata["embedded_message_ids"])
+		}
+	})
+}
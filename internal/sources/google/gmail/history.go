@@ -0,0 +1,355 @@
+package gmail
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"pkm-sync/pkg/models"
+
+	"google.golang.org/api/gmail/v1"
+	"google.golang.org/api/googleapi"
+)
+
+// ErrHistoryExpired is returned by GetHistory when Gmail no longer holds the
+// requested start history ID (it keeps roughly a week of history). Callers
+// fall back to the date-bounded query.
+var ErrHistoryExpired = errors.New("gmail history ID expired")
+
+// historyTypes are the changes GetHistory asks for: new mail, and mail that
+// gained a label after it arrived (e.g. filed into a synced label later).
+var historyTypes = []string{"messageAdded", "labelAdded"}
+
+// historyExcludedLabels are never synced from history. Messages.list skips
+// them by default, so the date query never returns them either.
+var historyExcludedLabels = []string{"SPAM", "TRASH"}
+
+// HistoryFilterable reports whether every configured filter can be checked on
+// a message's label IDs, which is all the History API gives us. Search-only
+// filters (query, domains, attachments, min_email_age) need the date query.
+func (s *Service) HistoryFilterable() bool {
+	c := s.config
+
+	return c.Query == "" &&
+		len(c.FromDomains) == 0 &&
+		len(c.ToDomains) == 0 &&
+		len(c.ExcludeFromDomains) == 0 &&
+		!c.RequireAttachments &&
+		c.MinEmailAge == ""
+}
+
+// LatestHistoryID returns the newest history ID seen by GetHistory or
+// RecordHistoryID, or "" when neither has run.
+func (s *Service) LatestHistoryID() string {
+	if s.historyID == 0 {
+		return ""
+	}
+
+	return strconv.FormatUint(s.historyID, 10)
+}
+
+// RecordHistoryID remembers the mailbox's current history ID. Call it before
+// a date-bounded fetch so the next run's GetHistory starts no later than it.
+func (s *Service) RecordHistoryID() error {
+	profile, err := s.GetProfile()
+	if err != nil {
+		return err
+	}
+
+	s.historyID = profile.HistoryId
+
+	return nil
+}
+
+// GetHistory returns the messages added or labeled since startHistoryID,
+// fetched in full and filtered by the configured labels, read state and
+// include_chats setting. It returns ErrHistoryExpired when Gmail answers 404
+// for the start ID. The newest history ID is available from LatestHistoryID;
+// when a changed message fails to fetch for any reason other than having been
+// deleted, it stays at startHistoryID so the next run retries the delta.
+//
+// A positive limit caps the changed messages fetched. Changes are taken
+// oldest first, whole history records at a time, and LatestHistoryID stops
+// at the last record taken, so each run makes progress through a large
+// delta; more reports that changes remain for the next run. The first record
+// is always taken, even when it alone changed more than limit messages.
+func (s *Service) GetHistory(startHistoryID string, limit int) (messages []*gmail.Message, more bool, err error) {
+	start, err := strconv.ParseUint(startHistoryID, 10, 64)
+	if err != nil {
+		return nil, false, fmt.Errorf("invalid history ID %q: %w", startHistoryID, err)
+	}
+
+	var (
+		changed   []*gmail.Message
+		seen      = make(map[string]*gmail.Message)
+		pageToken string
+		latest    = start
+		current   uint64 // the mailbox's current history ID
+	)
+
+	for !more {
+		req := s.service.Users.History.List("me").StartHistoryId(start).HistoryTypes(historyTypes...).MaxResults(500)
+		if pageToken != "" {
+			req = req.PageToken(pageToken)
+		}
+
+		resp, err := s.executeWithRetry(func() (interface{}, error) {
+			return req.Do()
+		})
+		if err != nil {
+			var apiErr *googleapi.Error
+			if errors.As(err, &apiErr) && apiErr.Code == http.StatusNotFound {
+				return nil, false, ErrHistoryExpired
+			}
+
+			return nil, false, fmt.Errorf("unable to list history: %w", err)
+		}
+
+		page := resp.(*gmail.ListHistoryResponse)
+
+		for _, h := range page.History {
+			if limit > 0 && len(changed) > 0 && len(changed)+countUnseen(h, seen) > limit {
+				more = true
+
+				break
+			}
+
+			changed = addHistoryRecord(h, seen, changed)
+			latest = max(latest, h.Id)
+		}
+
+		current = max(current, page.HistoryId)
+
+		if pageToken = page.NextPageToken; pageToken == "" {
+			break
+		}
+	}
+
+	// The mailbox's current history ID is reached only when no change was
+	// left for the next run.
+	if !more {
+		latest = max(latest, current)
+	}
+
+	slog.Info("Gmail History API response",
+		"source_id", s.sourceID,
+		"start_history_id", startHistoryID,
+		"messages_changed", len(changed),
+		"more", more)
+
+	if len(changed) == 0 {
+		s.historyID = max(s.historyID, latest)
+
+		return []*gmail.Message{}, false, nil
+	}
+
+	wanted, err := s.historyLabelIDs()
+	if err != nil {
+		return nil, false, err
+	}
+
+	// Messages deleted since the change was recorded fail to fetch and are
+	// counted as skipped; any other failure keeps the cursor at the start.
+	fetched, skippedCount, failed := s.fetchHistoryMessages(changed)
+	if skippedCount > 0 {
+		slog.Info("Message retrieval completed", "retrieved", len(fetched), "skipped", skippedCount)
+	}
+
+	if failed > 0 {
+		slog.Warn("Some changed messages could not be fetched; keeping the history cursor for a retry",
+			"source_id", s.sourceID, "failed", failed, "start_history_id", startHistoryID)
+
+		latest = start
+	}
+
+	s.historyID = max(s.historyID, latest)
+
+	messages = make([]*gmail.Message, 0, len(fetched))
+
+	for _, msg := range fetched {
+		if matchesHistoryFilters(s.config, wanted, msg) {
+			messages = append(messages, msg)
+		}
+	}
+
+	return messages, more, nil
+}
+
+// addHistoryRecord appends the messages of h not yet in seen to changed.
+// Each message's HistoryId holds the newest record that changed it, so a
+// cached copy from before that change is refetched.
+func addHistoryRecord(h *gmail.History, seen map[string]*gmail.Message, changed []*gmail.Message) []*gmail.Message {
+	for _, msg := range historyMessages(h) {
+		if msg == nil {
+			continue
+		}
+
+		if prev, ok := seen[msg.Id]; ok {
+			prev.HistoryId = max(prev.HistoryId, h.Id)
+
+			continue
+		}
+
+		seen[msg.Id] = &gmail.Message{Id: msg.Id, ThreadId: msg.ThreadId, HistoryId: h.Id}
+		changed = append(changed, seen[msg.Id])
+	}
+
+	return changed
+}
+
+// countUnseen returns how many distinct messages of h are not in seen.
+func countUnseen(h *gmail.History, seen map[string]*gmail.Message) int {
+	unseen := make(map[string]bool)
+
+	for _, msg := range historyMessages(h) {
+		if msg != nil && seen[msg.Id] == nil {
+			unseen[msg.Id] = true
+		}
+	}
+
+	return len(unseen)
+}
+
+// fetchHistoryMessages fetches changed messages like fetchMessagesConcurrently
+// and also returns how many failed for a reason other than being deleted. A
+// message that fails and is then fetched on a requeue does not count. Cached
+// copies older than the history record that changed the message are skipped,
+// since their labels predate the change.
+func (s *Service) fetchHistoryMessages(changed []*gmail.Message) ([]*gmail.Message, int, int) {
+	var (
+		mu         sync.Mutex
+		failed     = make(map[string]bool)
+		historyIDs = make(map[string]uint64, len(changed))
+	)
+
+	for _, msg := range changed {
+		historyIDs[msg.Id] = msg.HistoryId
+	}
+
+	fetch := func(id string) (*gmail.Message, error) {
+		msg, err := s.getMessageCached(id, historyIDs[id])
+
+		mu.Lock()
+		defer mu.Unlock()
+
+		var apiErr *googleapi.Error
+		if err != nil && !(errors.As(err, &apiErr) && apiErr.Code == http.StatusNotFound) {
+			failed[id] = true
+		} else {
+			delete(failed, id)
+		}
+
+		return msg, err
+	}
+
+	fetched, skippedCount := fetchConcurrently(
+		context.Background(),
+		s.concurrencyBounds(),
+		s.config.RequestDelay,
+		changed,
+		func(msg *gmail.Message) string { return msg.Id },
+		fetch,
+		"message",
+	)
+
+	return fetched, skippedCount, len(failed)
+}
+
+// historyMessages returns the messages referenced by one history record.
+func historyMessages(h *gmail.History) []*gmail.Message {
+	messages := make([]*gmail.Message, 0, len(h.MessagesAdded)+len(h.LabelsAdded))
+
+	for _, added := range h.MessagesAdded {
+		messages = append(messages, added.Message)
+	}
+
+	for _, labeled := range h.LabelsAdded {
+		messages = append(messages, labeled.Message)
+	}
+
+	return messages
+}
+
+// historyLabelIDs resolves the configured labels to label IDs, since history
+// messages carry IDs rather than names. Returns nil when no labels are
+// configured, meaning every label matches.
+func (s *Service) historyLabelIDs() (map[string]bool, error) {
+	if len(s.config.Labels) == 0 {
+		return nil, nil
+	}
+
+	labels, err := s.GetLabels()
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch labels: %w", err)
+	}
+
+	wanted, unresolved := labelIDsFromList(s.config.Labels, labels)
+	if len(unresolved) > 0 {
+		slog.Warn("Configured labels not found in mailbox", "source_id", s.sourceID, "labels", unresolved)
+	}
+
+	return wanted, nil
+}
+
+// labelIDsFromList maps configured labels (IDs, names, or query-form names)
+// to the IDs of the matching labels. Names compare case-insensitively, like
+// Gmail's label: operator.
+func labelIDsFromList(configLabels []string, labels []*gmail.Label) (wanted map[string]bool, unresolved []string) {
+	wanted = make(map[string]bool, len(configLabels))
+
+	for _, configured := range configLabels {
+		found := false
+
+		for _, label := range labels {
+			if label.Id == configured ||
+				strings.EqualFold(label.Name, configured) ||
+				strings.EqualFold(labelNameToQuery(label.Name), configured) {
+				wanted[label.Id] = true
+				found = true
+
+				break
+			}
+		}
+
+		if !found {
+			unresolved = append(unresolved, configured)
+		}
+	}
+
+	return wanted, unresolved
+}
+
+// matchesHistoryFilters applies the label-based equivalents of buildQuery's
+// label, read/unread and chat filters to a fetched message.
+func matchesHistoryFilters(config models.GmailSourceConfig, wanted map[string]bool, msg *gmail.Message) bool {
+	for _, excluded := range historyExcludedLabels {
+		if slices.Contains(msg.LabelIds, excluded) {
+			return false
+		}
+	}
+
+	if !config.IncludeChats && slices.Contains(msg.LabelIds, "CHAT") {
+		return false
+	}
+
+	unread := slices.Contains(msg.LabelIds, "UNREAD")
+	if config.IncludeUnread && !config.IncludeRead && !unread {
+		return false
+	}
+
+	if config.IncludeRead && !config.IncludeUnread && unread {
+		return false
+	}
+
+	if wanted == nil {
+		return true
+	}
+
+	return slices.ContainsFunc(msg.LabelIds, func(id string) bool { return wanted[id] })
+}
@@ -0,0 +1,321 @@
+package gmail
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"pkm-sync/pkg/models"
+
+	"google.golang.org/api/gmail/v1"
+	"google.golang.org/api/option"
+)
+
+// newHistoryService serves two pages of history (m1, m2 and m3 changed, m1
+// twice) and the labels INBOX and Label_7 "Work Stuff". handler, when set,
+// answers history requests instead.
+func newHistoryService(t *testing.T, config models.GmailSourceConfig, handler http.HandlerFunc) *Service {
+	t.Helper()
+
+	return newHistoryServiceWithFailures(t, config, handler, nil)
+}
+
+// newHistoryServiceWithFailures is newHistoryService answering message
+// requests for the IDs in failures with that HTTP status.
+func newHistoryServiceWithFailures(
+	t *testing.T, config models.GmailSourceConfig, handler http.HandlerFunc, failures map[string]int,
+) *Service {
+	t.Helper()
+
+	labelIDs := map[string][]string{
+		"m1": {"INBOX", "UNREAD"},
+		"m2": {"Label_7"},
+		"m3": {"SPAM"},
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body any
+
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/history") && handler != nil:
+			handler(w, r)
+
+			return
+		case strings.HasSuffix(r.URL.Path, "/history") && r.URL.Query().Get("pageToken") == "":
+			if r.URL.Query().Get("startHistoryId") != "100" {
+				t.Errorf("startHistoryId = %q, want 100", r.URL.Query().Get("startHistoryId"))
+			}
+
+			body = &gmail.ListHistoryResponse{
+				HistoryId:     150,
+				NextPageToken: "p2",
+				History: []*gmail.History{
+					{Id: 110, MessagesAdded: []*gmail.HistoryMessageAdded{{Message: &gmail.Message{Id: "m1"}}}},
+					{Id: 120, LabelsAdded: []*gmail.HistoryLabelAdded{{Message: &gmail.Message{Id: "m2"}}}},
+				},
+			}
+		case strings.HasSuffix(r.URL.Path, "/history"):
+			body = &gmail.ListHistoryResponse{
+				HistoryId: 160,
+				History: []*gmail.History{
+					{Id: 155, MessagesAdded: []*gmail.HistoryMessageAdded{
+						{Message: &gmail.Message{Id: "m1"}},
+						{Message: &gmail.Message{Id: "m3"}},
+					}},
+				},
+			}
+		case strings.HasSuffix(r.URL.Path, "/labels"):
+			body = &gmail.ListLabelsResponse{Labels: []*gmail.Label{
+				{Id: "INBOX", Name: "INBOX"},
+				{Id: "Label_7", Name: "Work Stuff"},
+			}}
+		default:
+			id := r.URL.Path[strings.LastIndex(r.URL.Path, "/")+1:]
+			if status, ok := failures[id]; ok {
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(status)
+				_, _ = fmt.Fprintf(w, `{"error":{"code":%d,"message":"failed"}}`, status)
+
+				return
+			}
+
+			body = &gmail.Message{Id: id, HistoryId: 160, LabelIds: labelIDs[id]}
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(body)
+	}))
+	t.Cleanup(server.Close)
+
+	api, err := gmail.NewService(t.Context(), option.WithHTTPClient(server.Client()), option.WithEndpoint(server.URL+"/"))
+	if err != nil {
+		t.Fatalf("failed to create gmail client: %v", err)
+	}
+
+	return &Service{service: api, config: config, sourceID: "gmail_test"}
+}
+
+func TestGetHistory_ReturnsChangedMessages(t *testing.T) {
+	svc := newHistoryService(t, models.GmailSourceConfig{}, nil)
+
+	messages, _, err := svc.GetHistory("100", 0)
+	if err != nil {
+		t.Fatalf("GetHistory: %v", err)
+	}
+
+	// m1 is listed twice and m3 is spam: only m1 and m2 remain.
+	got := messageIDs(messages)
+	slices.Sort(got)
+
+	if len(got) != 2 || got[0] != "m1" || got[1] != "m2" {
+		t.Errorf("messages = %v, want [m1 m2]", got)
+	}
+
+	if got := svc.LatestHistoryID(); got != "160" {
+		t.Errorf("LatestHistoryID = %q, want 160", got)
+	}
+}
+
+func TestGetHistory_FailedFetchKeepsCursor(t *testing.T) {
+	tests := []struct {
+		name   string
+		status int
+		want   string
+	}{
+		// A message deleted since the change was recorded is gone for good.
+		{name: "deleted", status: http.StatusNotFound, want: "160"},
+		{name: "failed", status: http.StatusBadRequest, want: "100"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := newHistoryServiceWithFailures(t, models.GmailSourceConfig{}, nil, map[string]int{"m2": tt.status})
+
+			messages, _, err := svc.GetHistory("100", 0)
+			if err != nil {
+				t.Fatalf("GetHistory: %v", err)
+			}
+
+			if got := messageIDs(messages); len(got) != 1 || got[0] != "m1" {
+				t.Errorf("messages = %v, want [m1]", got)
+			}
+
+			if got := svc.LatestHistoryID(); got != tt.want {
+				t.Errorf("LatestHistoryID = %q, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetHistory_FiltersByConfiguredLabels(t *testing.T) {
+	svc := newHistoryService(t, models.GmailSourceConfig{Labels: []string{"work-stuff"}}, nil)
+
+	messages, _, err := svc.GetHistory("100", 0)
+	if err != nil {
+		t.Fatalf("GetHistory: %v", err)
+	}
+
+	if got := messageIDs(messages); len(got) != 1 || got[0] != "m2" {
+		t.Errorf("messages = %v, want [m2]", got)
+	}
+}
+
+func TestGetHistory_RefetchesCachedCopyOlderThanChange(t *testing.T) {
+	svc := newHistoryService(t, models.GmailSourceConfig{Labels: []string{"work-stuff"}}, nil)
+
+	cache, err := NewFetchCache(t.TempDir(), time.Hour)
+	if err != nil {
+		t.Fatalf("NewFetchCache failed: %v", err)
+	}
+
+	svc.SetCache(cache)
+
+	// m2 was cached before history record 120 added Label_7 to it.
+	cache.PutMessage(&gmail.Message{Id: "m2", HistoryId: 90, LabelIds: []string{"INBOX"}})
+
+	messages, _, err := svc.GetHistory("100", 0)
+	if err != nil {
+		t.Fatalf("GetHistory: %v", err)
+	}
+
+	if got := messageIDs(messages); len(got) != 1 || got[0] != "m2" {
+		t.Errorf("messages = %v, want [m2] with its new label", got)
+	}
+}
+
+func TestGetHistory_LimitStepsThroughOldestChanges(t *testing.T) {
+	tests := []struct {
+		name       string
+		limit      int
+		want       []string
+		wantCursor string
+		wantMore   bool
+	}{
+		{name: "first record", limit: 1, want: []string{"m1"}, wantCursor: "110", wantMore: true},
+		{name: "first page", limit: 2, want: []string{"m1", "m2"}, wantCursor: "120", wantMore: true},
+		{name: "whole delta", limit: 3, want: []string{"m1", "m2"}, wantCursor: "160"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := newHistoryService(t, models.GmailSourceConfig{}, nil)
+
+			messages, more, err := svc.GetHistory("100", tt.limit)
+			if err != nil {
+				t.Fatalf("GetHistory: %v", err)
+			}
+
+			got := messageIDs(messages)
+			slices.Sort(got)
+
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("messages = %v, want %v", got, tt.want)
+			}
+
+			if more != tt.wantMore {
+				t.Errorf("more = %v, want %v", more, tt.wantMore)
+			}
+
+			if got := svc.LatestHistoryID(); got != tt.wantCursor {
+				t.Errorf("LatestHistoryID = %q, want %s", got, tt.wantCursor)
+			}
+		})
+	}
+}
+
+func TestGetHistory_ExpiredHistoryID(t *testing.T) {
+	svc := newHistoryService(t, models.GmailSourceConfig{}, func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(`{"error":{"code":404,"message":"Requested entity was not found."}}`))
+	})
+
+	_, _, err := svc.GetHistory("100", 0)
+	if !errors.Is(err, ErrHistoryExpired) {
+		t.Fatalf("err = %v, want ErrHistoryExpired", err)
+	}
+}
+
+func TestGetHistory_InvalidHistoryID(t *testing.T) {
+	svc := &Service{}
+
+	if _, _, err := svc.GetHistory("not-a-number", 0); err == nil {
+		t.Fatal("expected an error for a non-numeric history ID")
+	}
+}
+
+func TestHistoryFilterable(t *testing.T) {
+	tests := []struct {
+		name   string
+		config models.GmailSourceConfig
+		want   bool
+	}{
+		{"labels only", models.GmailSourceConfig{Labels: []string{"INBOX"}, IncludeUnread: true}, true},
+		{"custom query", models.GmailSourceConfig{Query: "subject:report"}, false},
+		{"from domains", models.GmailSourceConfig{FromDomains: []string{"example.com"}}, false},
+		{"attachments", models.GmailSourceConfig{RequireAttachments: true}, false},
+		{"min age", models.GmailSourceConfig{MinEmailAge: "1d"}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := &Service{config: tt.config}
+			if got := svc.HistoryFilterable(); got != tt.want {
+				t.Errorf("HistoryFilterable() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMatchesHistoryFilters(t *testing.T) {
+	work := map[string]bool{"Label_7": true}
+
+	tests := []struct {
+		name   string
+		config models.GmailSourceConfig
+		wanted map[string]bool
+		labels []string
+		want   bool
+	}{
+		{"no filters", models.GmailSourceConfig{}, nil, []string{"INBOX"}, true},
+		{"trash", models.GmailSourceConfig{}, nil, []string{"TRASH"}, false},
+		{"chat excluded", models.GmailSourceConfig{}, nil, []string{"CHAT"}, false},
+		{"chat included", models.GmailSourceConfig{IncludeChats: true}, nil, []string{"CHAT"}, true},
+		{"unread only, read message", models.GmailSourceConfig{IncludeUnread: true}, nil, []string{"INBOX"}, false},
+		{"read only, unread message", models.GmailSourceConfig{IncludeRead: true}, nil, []string{"UNREAD"}, false},
+		{"wanted label", models.GmailSourceConfig{}, work, []string{"Label_7"}, true},
+		{"other label", models.GmailSourceConfig{}, work, []string{"INBOX"}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			msg := &gmail.Message{LabelIds: tt.labels}
+			if got := matchesHistoryFilters(tt.config, tt.wanted, msg); got != tt.want {
+				t.Errorf("matchesHistoryFilters() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLabelIDsFromList(t *testing.T) {
+	labels := []*gmail.Label{
+		{Id: "INBOX", Name: "INBOX"},
+		{Id: "Label_7", Name: "Work Stuff"},
+	}
+
+	wanted, unresolved := labelIDsFromList([]string{"inbox", "Work-Stuff", "Label_7", "missing"}, labels)
+
+	if len(wanted) != 2 || !wanted["INBOX"] || !wanted["Label_7"] {
+		t.Errorf("wanted = %v, want INBOX and Label_7", wanted)
+	}
+
+	if len(unresolved) != 1 || unresolved[0] != "missing" {
+		t.Errorf("unresolved = %v, want [missing]", unresolved)
+	}
+}
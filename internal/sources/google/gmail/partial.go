@@ -0,0 +1,150 @@
+package gmail
+
+import (
+	"fmt"
+	"log/slog"
+
+	"google.golang.org/api/gmail/v1"
+)
+
+// getThreadWithFallback fetches a thread whole and, when that fails for a
+// reason other than quota, message by message, so one message Gmail cannot
+// return does not drop the whole conversation. Rate-limit errors are passed
+// through for fetchConcurrently to requeue.
+func (s *Service) getThreadWithFallback(threadID string, historyID uint64) (*gmail.Thread, error) {
+	if s.config.MaxThreadMessages > 0 {
+		if thread, capped, err := s.getCappedThread(threadID, historyID); capped || err != nil {
+			return thread, err
+		}
+	}
+
+	thread, err := s.getThreadCached(threadID, historyID)
+	if err == nil || isRateLimitError(err) {
+		return thread, err
+	}
+
+	partial, partialErr := s.getThreadByMessage(threadID)
+	if partialErr != nil {
+		if isRateLimitError(partialErr) {
+			return nil, partialErr
+		}
+
+		return nil, err
+	}
+
+	return partial, nil
+}
+
+// getThreadByMessage lists a thread's message IDs and fetches each message on
+// its own, keeping the thread's order. Messages that fail are left out and
+// recorded, so the converter can flag the thread as partial. It fails only
+// when no message could be fetched.
+func (s *Service) getThreadByMessage(threadID string) (*gmail.Thread, error) {
+	stub, err := s.getThreadStub(threadID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.fetchThreadMessages(stub, stub.Messages)
+}
+
+// getThreadStub fetches a thread in the minimal format: message IDs without
+// headers or bodies.
+func (s *Service) getThreadStub(threadID string) (*gmail.Thread, error) {
+	if s.service == nil {
+		return nil, fmt.Errorf("gmail service is not initialized")
+	}
+
+	req := s.service.Users.Threads.Get("me", threadID).Format("minimal")
+
+	resp, err := s.executeWithRetry(func() (interface{}, error) {
+		return req.Do()
+	})
+	if err != nil {
+		return nil, handleThreadError(threadID, err)
+	}
+
+	return resp.(*gmail.Thread), nil
+}
+
+// fetchThreadMessages fetches messages, a subset of stub's, one by one into a
+// copy of stub. Messages that fail are left out and recorded as missing.
+func (s *Service) fetchThreadMessages(stub *gmail.Thread, messages []*gmail.Message) (*gmail.Thread, error) {
+	threadID := stub.Id
+	thread := &gmail.Thread{Id: stub.Id, HistoryId: stub.HistoryId, Snippet: stub.Snippet}
+
+	var missing []string
+
+	for _, m := range messages {
+		msg, err := s.getMessageCached(m.Id, m.HistoryId)
+		if err != nil {
+			if isRateLimitError(err) {
+				return nil, err
+			}
+
+			slog.Warn("Failed to get message in thread",
+				"source_id", s.sourceID,
+				"thread_id", threadID,
+				"message_id", m.Id,
+				"error", err)
+
+			missing = append(missing, m.Id)
+
+			continue
+		}
+
+		thread.Messages = append(thread.Messages, msg)
+	}
+
+	if len(thread.Messages) == 0 {
+		return nil, fmt.Errorf("no message of thread %s could be fetched", threadID)
+	}
+
+	if len(missing) > 0 {
+		s.partialMu.Lock()
+
+		if s.partialThreads == nil {
+			s.partialThreads = make(map[string][]string)
+		}
+
+		s.partialThreads[threadID] = missing
+		s.partialMu.Unlock()
+	}
+
+	return thread, nil
+}
+
+// MissingThreadMessages returns the IDs of the messages the last GetThreads
+// call could not fetch for threadID, or nil when the thread is complete.
+func (s *Service) MissingThreadMessages(threadID string) []string {
+	s.partialMu.Lock()
+	defer s.partialMu.Unlock()
+
+	return s.partialThreads[threadID]
+}
+
+// resetPartialThreads clears the record of partially fetched and truncated
+// threads.
+func (s *Service) resetPartialThreads() {
+	s.partialMu.Lock()
+	defer s.partialMu.Unlock()
+
+	s.partialThreads = nil
+	s.truncatedThreads = nil
+}
+
+// partialThreadCount reports how many of threads are missing messages.
+func (s *Service) partialThreadCount(threads []*gmail.Thread) int {
+	s.partialMu.Lock()
+	defer s.partialMu.Unlock()
+
+	count := 0
+
+	for _, thread := range threads {
+		if len(s.partialThreads[thread.Id]) > 0 {
+			count++
+		}
+	}
+
+	return count
+}
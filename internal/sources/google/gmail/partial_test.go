@@ -0,0 +1,128 @@
+package gmail
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"pkm-sync/pkg/models"
+
+	"google.golang.org/api/gmail/v1"
+	"google.golang.org/api/option"
+)
+
+// newPartialThreadService serves thread t1 with messages m1..m3. Fetching the
+// thread in full fails, as does message m2; everything else succeeds.
+func newPartialThreadService(t *testing.T) *Service {
+	t.Helper()
+
+	badRequest := func(w http.ResponseWriter) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte(`{"error": {"code": 400, "message": "Invalid message"}}`))
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.URL.Path[strings.LastIndex(r.URL.Path, "/")+1:]
+
+		var body any
+
+		switch {
+		case strings.Contains(r.URL.Path, "/threads/") && r.URL.Query().Get("format") == "full":
+			badRequest(w)
+
+			return
+		case strings.Contains(r.URL.Path, "/threads/"):
+			body = &gmail.Thread{Id: id, Messages: []*gmail.Message{{Id: "m1"}, {Id: "m2"}, {Id: "m3"}}}
+		case id == "m2":
+			badRequest(w)
+
+			return
+		default:
+			body = &gmail.Message{
+				Id:           id,
+				ThreadId:     "t1",
+				InternalDate: map[string]int64{"m1": 1000, "m3": 3000}[id],
+				Payload: &gmail.MessagePart{
+					MimeType: "text/plain",
+					Headers: []*gmail.MessagePartHeader{
+						{Name: "Subject", Value: "Quarterly plan"},
+						{Name: "From", Value: "alice@example.com"},
+					},
+					Body: &gmail.MessagePartBody{Data: "Ym9keQ"}, // "body"
+				},
+			}
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(body)
+	}))
+	t.Cleanup(server.Close)
+
+	api, err := gmail.NewService(t.Context(), option.WithHTTPClient(server.Client()), option.WithEndpoint(server.URL+"/"))
+	if err != nil {
+		t.Fatalf("failed to create gmail client: %v", err)
+	}
+
+	return &Service{service: api}
+}
+
+func TestFetchThreads_KeepsThreadWhenMiddleMessageFails(t *testing.T) {
+	svc := newPartialThreadService(t)
+
+	threads, skipped := svc.fetchThreadsConcurrently([]*gmail.Thread{{Id: "t1"}})
+	if skipped != 0 || len(threads) != 1 {
+		t.Fatalf("got %d threads, %d skipped; want the thread kept", len(threads), skipped)
+	}
+
+	thread := threads[0]
+	if len(thread.Messages) != 2 || thread.Messages[0].Id != "m1" || thread.Messages[1].Id != "m3" {
+		t.Fatalf("messages = %v, want [m1 m3] in order", messageIDs(thread.Messages))
+	}
+
+	if got := svc.partialThreadCount(threads); got != 1 {
+		t.Errorf("partialThreadCount = %d, want 1", got)
+	}
+
+	item, err := FromGmailThread(thread, models.GmailSourceConfig{}, svc)
+	if err != nil {
+		t.Fatalf("FromGmailThread: %v", err)
+	}
+
+	if item.Metadata["partial_thread"] != true {
+		t.Errorf("partial_thread = %v, want true", item.Metadata["partial_thread"])
+	}
+
+	if missing, _ := item.Metadata["missing_message_ids"].([]string); len(missing) != 1 || missing[0] != "m2" {
+		t.Errorf("missing_message_ids = %v, want [m2]", item.Metadata["missing_message_ids"])
+	}
+
+	if item.Metadata["message_count"] != 2 {
+		t.Errorf("message_count = %v, want 2", item.Metadata["message_count"])
+	}
+}
+
+func TestFetchThreads_CompleteThreadNotFlagged(t *testing.T) {
+	svc, _ := newCountingGmailService(t, new(atomic.Uint64))
+
+	threads, _ := svc.fetchThreadsConcurrently([]*gmail.Thread{{Id: "t1"}})
+	if len(threads) != 1 {
+		t.Fatalf("got %d threads, want 1", len(threads))
+	}
+
+	if missing := svc.MissingThreadMessages("t1"); missing != nil {
+		t.Errorf("MissingThreadMessages = %v, want nil", missing)
+	}
+}
+
+func messageIDs(messages []*gmail.Message) []string {
+	ids := make([]string, 0, len(messages))
+	for _, m := range messages {
+		ids = append(ids, m.Id)
+	}
+
+	return ids
+}
@@ -1,11 +1,19 @@
 package gmail
 
 import (
+	"crypto/sha256"
 	"encoding/base64"
+	"encoding/hex"
 	"fmt"
 	"log/slog"
+	"mime"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
 	"strings"
 
+	"pkm-sync/internal/naming"
 	"pkm-sync/pkg/models"
 
 	"google.golang.org/api/gmail/v1"
@@ -37,7 +45,8 @@ func NewContentProcessorWithService(
 }
 
 // ProcessEmailBody extracts raw email body without processing.
-// Content processing is now handled by transformers.
+// Content processing is now handled by transformers. With embedded_messages
+// set to "inline", attached messages are appended below the body.
 func (p *ContentProcessor) ProcessEmailBody(msg *gmail.Message) (string, error) {
 	if msg.Payload == nil {
 		return "", nil
@@ -59,6 +68,10 @@ func (p *ContentProcessor) ProcessEmailBody(msg *gmail.Message) (string, error)
 		content = msg.Snippet
 	}
 
+	if p.config.EmbeddedMessages == EmbeddedMessagesInline {
+		content += formatEmbeddedMessages(p.ExtractEmbeddedMessages(msg))
+	}
+
 	return content, nil
 }
 
@@ -82,8 +95,13 @@ func (p *ContentProcessor) extractBodyPart(part *gmail.MessagePart, mimeType str
 		}
 	}
 
-	// Recursively check parts
+	// Recursively check parts. Attached messages are handled separately when
+	// embedded_messages is on, so their text is not taken for this body.
 	for _, subPart := range part.Parts {
+		if subPart.MimeType == mimeTypeRFC822 && p.embeddedMessagesEnabled() {
+			continue
+		}
+
 		if content := p.extractBodyPart(subPart, mimeType); content != "" {
 			return content
 		}
@@ -98,23 +116,7 @@ func (p *ContentProcessor) ProcessEmailAttachments(msg *gmail.Message) []models.
 		return []models.Attachment{}
 	}
 
-	var attachments []models.Attachment
-
-	p.extractAttachmentsFromPart(msg.Payload, msg.Id, &attachments)
-
-	filtered := p.filterAttachments(attachments)
-
-	// If we have a service, fetch the actual attachment data
-	if p.service != nil {
-		for i := range filtered {
-			if err := p.fetchAttachmentData(msg.Id, &filtered[i]); err != nil {
-				// Log error but continue with other attachments
-				slog.Warn("Failed to fetch attachment data", "attachment_name", filtered[i].Name, "error", err)
-			}
-		}
-	}
-
-	return filtered
+	return p.collectMessageAttachments(msg)
 }
 
 // ProcessThreadAttachments aggregates attachments across all messages in a thread.
@@ -130,27 +132,46 @@ func (p *ContentProcessor) ProcessThreadAttachments(thread *gmail.Thread) []mode
 			continue
 		}
 
-		var msgAttachments []models.Attachment
+		allAttachments = append(allAttachments, p.collectMessageAttachments(msg)...)
+	}
 
-		p.extractAttachmentsFromPart(msg.Payload, msg.Id, &msgAttachments)
+	return dedupeAttachments(allAttachments)
+}
 
-		filtered := p.filterAttachments(msgAttachments)
+// collectMessageAttachments extracts, filters and (with a service) downloads the
+// attachments of a single message. Attachments whose declared type is generic
+// are downloaded before filtering so the filter sees their sniffed type.
+func (p *ContentProcessor) collectMessageAttachments(msg *gmail.Message) []models.Attachment {
+	var attachments []models.Attachment
 
-		if p.service != nil {
-			for i := range filtered {
-				if err := p.fetchAttachmentData(msg.Id, &filtered[i]); err != nil {
-					slog.Warn("Failed to fetch thread attachment data",
-						"message_id", msg.Id,
-						"attachment_name", filtered[i].Name,
-						"error", err)
-				}
-			}
+	p.extractAttachmentsFromPart(msg.Payload, msg.Id, &attachments)
+
+	if p.service == nil {
+		return dedupeAttachments(p.filterAttachments(attachments))
+	}
+
+	var collected []models.Attachment
+
+	for _, attachment := range attachments {
+		allowed := len(p.config.AttachmentTypes) == 0 || p.isAllowedAttachmentType(attachment)
+		if !allowed && !needsContentSniffing(attachment.MimeType) {
+			continue
+		}
+
+		if err := p.fetchAttachmentData(msg.Id, &attachment); err != nil {
+			// Log error but continue with other attachments
+			slog.Warn("Failed to fetch attachment data",
+				"message_id", msg.Id,
+				"attachment_name", attachment.Name,
+				"error", err)
 		}
 
-		allAttachments = append(allAttachments, filtered...)
+		if allowed || p.isAllowedAttachmentType(attachment) {
+			collected = append(collected, attachment)
+		}
 	}
 
-	return allAttachments
+	return dedupeAttachments(collected)
 }
 
 // extractAttachmentsFromPart recursively extracts attachments from message parts.
@@ -172,6 +193,11 @@ func (p *ContentProcessor) extractAttachmentsFromPart(
 			Size:     part.Body.Size,
 		}
 
+		// Inline images are referenced from the HTML body by Content-ID.
+		if contentID := strings.Trim(partHeader(part, "Content-ID"), "<> "); contentID != "" {
+			attachment.URL = "cid:" + contentID
+		}
+
 		*attachments = append(*attachments, attachment)
 	}
 
@@ -203,14 +229,56 @@ func (p *ContentProcessor) fetchAttachmentData(messageID string, attachment *mod
 			}
 		}
 
+		sniffAttachmentType(attachment, decoded)
+
 		// Store the decoded data as base64 string for embedding in targets
 		attachment.Data = base64.StdEncoding.EncodeToString(decoded)
 		attachment.Size = int64(len(decoded))
+
+		if p.service.filesDir != "" {
+			localPath, err := saveAttachment(p.service.filesDir, messageID, attachment.Name, decoded)
+			if err != nil {
+				return err
+			}
+
+			attachment.LocalPath = localPath
+		}
 	}
 
 	return nil
 }
 
+// saveAttachment writes data to <dir>/<message ID>-<name> and returns the
+// path. A file already there with the same size is not written again.
+func saveAttachment(dir, messageID, name string, data []byte) (string, error) {
+	dest := filepath.Join(dir, messageID+"-"+filepath.Base(naming.Clean(name)))
+
+	if info, err := os.Stat(dest); err == nil && info.Size() == int64(len(data)) {
+		return dest, nil
+	}
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", fmt.Errorf("failed to create attachment directory: %w", err)
+	}
+
+	if err := os.WriteFile(dest, data, 0o644); err != nil {
+		return "", fmt.Errorf("failed to save attachment: %w", err)
+	}
+
+	return dest, nil
+}
+
+// partHeader returns the value of the named header of part, or "".
+func partHeader(part *gmail.MessagePart, name string) string {
+	for _, header := range part.Headers {
+		if strings.EqualFold(header.Name, name) {
+			return header.Value
+		}
+	}
+
+	return ""
+}
+
 // filterAttachments filters attachments based on configuration.
 func (p *ContentProcessor) filterAttachments(attachments []models.Attachment) []models.Attachment {
 	if len(p.config.AttachmentTypes) == 0 {
@@ -229,14 +297,16 @@ func (p *ContentProcessor) filterAttachments(attachments []models.Attachment) []
 }
 
 // isAllowedAttachmentType checks if an attachment type is allowed based on configuration.
+// Attachments without a filename extension are matched by their mime type.
 func (p *ContentProcessor) isAllowedAttachmentType(attachment models.Attachment) bool {
-	// Extract extension from filename
-	parts := strings.Split(attachment.Name, ".")
-	if len(parts) < 2 {
-		return false // No extension
+	extension := strings.TrimPrefix(strings.ToLower(path.Ext(attachment.Name)), ".")
+	if extension == "" {
+		extension = strings.TrimPrefix(extensionForMimeType(attachment.MimeType), ".")
 	}
 
-	extension := strings.ToLower(parts[len(parts)-1])
+	if extension == "" {
+		return false
+	}
 
 	for _, allowedType := range p.config.AttachmentTypes {
 		if strings.ToLower(allowedType) == extension {
@@ -246,3 +316,117 @@ func (p *ContentProcessor) isAllowedAttachmentType(attachment models.Attachment)
 
 	return false
 }
+
+// dedupeAttachments collapses parts that carry the same file, such as an image
+// sent both inline and as an attachment, keeping the first occurrence.
+// Downloaded attachments are keyed by content hash, the others by attachment
+// ID; a part with neither is always kept, since two files in a thread can
+// share a name and size (image001.png). A file split across several messages
+// (message/partial) is not reassembled: Gmail stores each fragment as its own
+// message, so no single message or thread fetch sees the whole file.
+func dedupeAttachments(attachments []models.Attachment) []models.Attachment {
+	if len(attachments) < 2 {
+		return attachments
+	}
+
+	seen := make(map[string]bool, len(attachments))
+	deduped := make([]models.Attachment, 0, len(attachments))
+
+	for _, attachment := range attachments {
+		key := attachmentKey(attachment)
+		if key != "" && seen[key] {
+			slog.Debug("Dropping duplicate attachment part",
+				"attachment_name", attachment.Name,
+				"attachment_id", attachment.ID)
+
+			continue
+		}
+
+		seen[key] = true
+		deduped = append(deduped, attachment)
+	}
+
+	return deduped
+}
+
+// attachmentKey identifies the file behind an attachment part, or returns ""
+// when neither its content nor its attachment ID is known.
+func attachmentKey(attachment models.Attachment) string {
+	if attachment.Data != "" {
+		sum := sha256.Sum256([]byte(attachment.Data))
+
+		return "sha256:" + hex.EncodeToString(sum[:])
+	}
+
+	if attachment.ID != "" {
+		return "id:" + attachment.ID
+	}
+
+	return ""
+}
+
+// sniffableMimeTypes are declared types too vague to trust; Gmail reports them
+// for attachments it could not classify.
+var sniffableMimeTypes = map[string]bool{
+	"":                         true,
+	"application/octet-stream": true,
+	"binary/octet-stream":      true,
+}
+
+// needsContentSniffing reports whether the declared mime type is generic enough
+// that the attachment's content should decide its type.
+func needsContentSniffing(mimeType string) bool {
+	return sniffableMimeTypes[strings.ToLower(strings.TrimSpace(mimeType))]
+}
+
+// sniffAttachmentType replaces a generic MimeType with the type detected from
+// the attachment's first bytes, and gives a bare filename a matching extension.
+// Specific declared types are left alone.
+func sniffAttachmentType(attachment *models.Attachment, data []byte) {
+	if !needsContentSniffing(attachment.MimeType) || len(data) == 0 {
+		return
+	}
+
+	detected, _, err := mime.ParseMediaType(http.DetectContentType(data))
+	if err != nil || needsContentSniffing(detected) {
+		return
+	}
+
+	attachment.MimeType = detected
+
+	if path.Ext(attachment.Name) == "" {
+		attachment.Name += extensionForMimeType(detected)
+	}
+}
+
+// commonExtensions pins the extension for types where the system mime table
+// lists several candidates.
+var commonExtensions = map[string]string{
+	"application/pdf": ".pdf",
+	"application/zip": ".zip",
+	"image/jpeg":      ".jpg",
+	"image/png":       ".png",
+	"image/gif":       ".gif",
+	"image/webp":      ".webp",
+	"text/plain":      ".txt",
+	"text/html":       ".html",
+}
+
+// extensionForMimeType returns the file extension (with leading dot) for a mime
+// type, or "" when none is known.
+func extensionForMimeType(mimeType string) string {
+	mediaType, _, err := mime.ParseMediaType(mimeType)
+	if err != nil {
+		return ""
+	}
+
+	if ext, ok := commonExtensions[mediaType]; ok {
+		return ext
+	}
+
+	if exts, err := mime.ExtensionsByType(mediaType); err == nil && len(exts) > 0 {
+		return exts[0]
+	}
+
+	return ""
+}
@@ -0,0 +1,245 @@
+package gmail
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"pkm-sync/pkg/models"
+
+	"google.golang.org/api/gmail/v1"
+	"google.golang.org/api/option"
+)
+
+var pdfPrefix = []byte("%PDF-1.7\n%\xe2\xe3\xcf\xd3\n1 0 obj\n<< /Type /Catalog >>\nendobj\n")
+
+func TestSniffAttachmentType(t *testing.T) {
+	tests := []struct {
+		name         string
+		attachment   models.Attachment
+		data         []byte
+		wantMimeType string
+		wantName     string
+	}{
+		{
+			name:         "octet-stream PDF resolves to application/pdf",
+			attachment:   models.Attachment{Name: "scan", MimeType: "application/octet-stream"},
+			data:         pdfPrefix,
+			wantMimeType: "application/pdf",
+			wantName:     "scan.pdf",
+		},
+		{
+			name:         "empty mime type is sniffed",
+			attachment:   models.Attachment{Name: "report.pdf"},
+			data:         pdfPrefix,
+			wantMimeType: "application/pdf",
+			wantName:     "report.pdf",
+		},
+		{
+			name:         "PNG keeps existing extension",
+			attachment:   models.Attachment{Name: "image.dat", MimeType: "application/octet-stream"},
+			data:         []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR"),
+			wantMimeType: "image/png",
+			wantName:     "image.dat",
+		},
+		{
+			name:         "specific declared type is not sniffed",
+			attachment:   models.Attachment{Name: "notes", MimeType: "text/markdown"},
+			data:         pdfPrefix,
+			wantMimeType: "text/markdown",
+			wantName:     "notes",
+		},
+		{
+			name:         "unrecognized content stays generic",
+			attachment:   models.Attachment{Name: "blob", MimeType: "application/octet-stream"},
+			data:         []byte{0x00, 0x01, 0x02, 0x03},
+			wantMimeType: "application/octet-stream",
+			wantName:     "blob",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			attachment := tt.attachment
+			sniffAttachmentType(&attachment, tt.data)
+
+			if attachment.MimeType != tt.wantMimeType {
+				t.Errorf("MimeType = %q, want %q", attachment.MimeType, tt.wantMimeType)
+			}
+
+			if attachment.Name != tt.wantName {
+				t.Errorf("Name = %q, want %q", attachment.Name, tt.wantName)
+			}
+		})
+	}
+}
+
+func TestIsAllowedAttachmentType_SniffedType(t *testing.T) {
+	processor := NewContentProcessor(models.GmailSourceConfig{AttachmentTypes: []string{"pdf"}})
+
+	attachment := models.Attachment{Name: "scan", MimeType: "application/octet-stream"}
+	if processor.isAllowedAttachmentType(attachment) {
+		t.Fatal("expected generic attachment without extension to be rejected before sniffing")
+	}
+
+	sniffAttachmentType(&attachment, pdfPrefix)
+
+	if !processor.isAllowedAttachmentType(attachment) {
+		t.Errorf("expected sniffed PDF %q to pass the pdf filter", attachment.Name)
+	}
+
+	if !processor.isAllowedAttachmentType(models.Attachment{Name: "invoice", MimeType: "application/pdf"}) {
+		t.Error("expected extensionless attachment to match by declared mime type")
+	}
+}
+
+func TestProcessEmailAttachments_InlineAndAttachedCopyMerged(t *testing.T) {
+	// Both parts download the same image bytes; the report differs.
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data := []byte("\x89PNG diagram")
+		if strings.HasSuffix(r.URL.Path, "/att-report") {
+			data = pdfPrefix
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(&gmail.MessagePartBody{Data: base64.URLEncoding.EncodeToString(data)})
+	}))
+	t.Cleanup(server.Close)
+
+	api, err := gmail.NewService(t.Context(), option.WithHTTPClient(server.Client()), option.WithEndpoint(server.URL+"/"))
+	if err != nil {
+		t.Fatalf("failed to create gmail client: %v", err)
+	}
+
+	imagePart := func(attachmentID, disposition string) *gmail.MessagePart {
+		return &gmail.MessagePart{
+			MimeType: "image/png",
+			Filename: "diagram.png",
+			Headers: []*gmail.MessagePartHeader{
+				{Name: "Content-Disposition", Value: disposition + `; filename="diagram.png"`},
+			},
+			Body: &gmail.MessagePartBody{AttachmentId: attachmentID, Size: 2048},
+		}
+	}
+
+	msg := &gmail.Message{
+		Id: "msg-inline-dup",
+		Payload: &gmail.MessagePart{
+			MimeType: "multipart/mixed",
+			Parts: []*gmail.MessagePart{
+				{
+					MimeType: "multipart/related",
+					Parts: []*gmail.MessagePart{
+						{MimeType: "text/html", Body: &gmail.MessagePartBody{Data: "PGltZz4="}},
+						imagePart("att-inline", "inline"),
+					},
+				},
+				imagePart("att-attached", "attachment"),
+				{
+					MimeType: "application/pdf",
+					Filename: "report.pdf",
+					Body:     &gmail.MessagePartBody{AttachmentId: "att-report", Size: 4096},
+				},
+			},
+		},
+	}
+
+	config := models.GmailSourceConfig{DownloadAttachments: true}
+	svc := &Service{service: api, config: config, sourceID: "gmail_test"}
+	attachments := NewContentProcessorWithService(config, svc).ProcessEmailAttachments(msg)
+
+	if len(attachments) != 2 {
+		t.Fatalf("expected 2 distinct attachments, got %d: %+v", len(attachments), attachments)
+	}
+
+	if attachments[0].ID != "att-inline" || attachments[1].Name != "report.pdf" {
+		t.Errorf("expected first occurrence of diagram.png followed by report.pdf, got %+v", attachments)
+	}
+}
+
+func TestDedupeAttachments_ByContentHash(t *testing.T) {
+	attachments := []models.Attachment{
+		{ID: "a", Name: "photo.jpg", Data: "AAEC", Size: 3},
+		{ID: "b", Name: "photo (1).jpg", Data: "AAEC", Size: 3},
+		{ID: "c", Name: "photo.jpg", Data: "AwQF", Size: 3},
+	}
+
+	deduped := dedupeAttachments(attachments)
+
+	if len(deduped) != 2 {
+		t.Fatalf("expected 2 attachments after dedupe, got %d", len(deduped))
+	}
+
+	if deduped[0].ID != "a" || deduped[1].ID != "c" {
+		t.Errorf("expected attachments a and c, got %s and %s", deduped[0].ID, deduped[1].ID)
+	}
+}
+
+func TestDedupeAttachments_WithoutContent(t *testing.T) {
+	attachments := []models.Attachment{
+		// Different files that share a name and size in one thread.
+		{Name: "image001.png", Size: 512},
+		{Name: "image001.png", Size: 512},
+		{ID: "att-1", Name: "notes.txt", Size: 10},
+		{ID: "att-1", Name: "notes.txt", Size: 10},
+	}
+
+	deduped := dedupeAttachments(attachments)
+
+	if len(deduped) != 3 {
+		t.Fatalf("expected both image001.png parts and one att-1, got %d: %+v", len(deduped), deduped)
+	}
+}
+
+func TestProcessEmailAttachments_SavesToFilesDir(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(&gmail.MessagePartBody{Data: base64.URLEncoding.EncodeToString(pdfPrefix)})
+	}))
+	t.Cleanup(server.Close)
+
+	api, err := gmail.NewService(t.Context(), option.WithHTTPClient(server.Client()), option.WithEndpoint(server.URL+"/"))
+	if err != nil {
+		t.Fatalf("failed to create gmail client: %v", err)
+	}
+
+	config := models.GmailSourceConfig{DownloadAttachments: true}
+	svc := &Service{service: api, config: config, sourceID: "gmail_test"}
+	svc.SetFilesDir(t.TempDir())
+
+	msg := &gmail.Message{
+		Id: "m1",
+		Payload: &gmail.MessagePart{
+			MimeType: "multipart/related",
+			Parts: []*gmail.MessagePart{{
+				MimeType: "application/pdf",
+				Filename: "report.pdf",
+				Headers:  []*gmail.MessagePartHeader{{Name: "Content-ID", Value: "<report@mail>"}},
+				Body:     &gmail.MessagePartBody{AttachmentId: "att-1", Size: int64(len(pdfPrefix))},
+			}},
+		},
+	}
+
+	attachments := NewContentProcessorWithService(config, svc).ProcessEmailAttachments(msg)
+	if len(attachments) != 1 {
+		t.Fatalf("expected 1 attachment, got %+v", attachments)
+	}
+
+	if got := attachments[0].URL; got != "cid:report@mail" {
+		t.Errorf("URL = %q, want cid:report@mail", got)
+	}
+
+	if want := filepath.Join(svc.filesDir, "m1-report.pdf"); attachments[0].LocalPath != want {
+		t.Errorf("LocalPath = %q, want %q", attachments[0].LocalPath, want)
+	}
+
+	if data, err := os.ReadFile(attachments[0].LocalPath); err != nil || !bytes.Equal(data, pdfPrefix) {
+		t.Errorf("saved attachment = %q, %v; want the decoded data", data, err)
+	}
+}
@@ -19,6 +19,10 @@ const (
 	complexQueryKeyOlderThan     = "older_than"
 )
 
+// chatExclusion keeps Google Chat messages, which Gmail stores under the CHAT
+// label, out of search results.
+const chatExclusion = "-in:chats"
+
 // buildQuery constructs a Gmail search query based on configuration and since time.
 func buildQuery(config models.GmailSourceConfig, since time.Time) string {
 	var parts []string
@@ -134,6 +138,11 @@ func buildQuery(config models.GmailSourceConfig, since time.Time) string {
 		parts = append(parts, "has:attachment")
 	}
 
+	// Google Chat messages are skipped unless include_chats is set.
+	if !config.IncludeChats {
+		parts = append(parts, chatExclusion)
+	}
+
 	finalQuery := strings.Join(parts, " ")
 
 	// Debug logging.
@@ -228,6 +237,11 @@ func buildQueryWithRange(config models.GmailSourceConfig, start, end time.Time)
 		parts = append(parts, "has:attachment")
 	}
 
+	// Google Chat messages are skipped unless include_chats is set.
+	if !config.IncludeChats {
+		parts = append(parts, chatExclusion)
+	}
+
 	return strings.Join(parts, " ")
 }
 
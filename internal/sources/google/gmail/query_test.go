@@ -20,7 +20,7 @@ func TestBuildQuery(t *testing.T) {
 			name:     "basic time filter",
 			config:   models.GmailSourceConfig{},
 			since:    time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
-			expected: "after:2024/01/01",
+			expected: "after:2024/01/01 -in:chats",
 		},
 		{
 			name: "with labels (OR logic)",
@@ -28,7 +28,7 @@ func TestBuildQuery(t *testing.T) {
 				Labels: []string{"IMPORTANT", "STARRED"},
 			},
 			since:    time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
-			expected: "after:2024/01/01 {label:IMPORTANT label:STARRED}",
+			expected: "after:2024/01/01 {label:IMPORTANT label:STARRED} -in:chats",
 		},
 		{
 			name: "with single label",
@@ -36,7 +36,7 @@ func TestBuildQuery(t *testing.T) {
 				Labels: []string{"IMPORTANT"},
 			},
 			since:    time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
-			expected: "after:2024/01/01 {label:IMPORTANT}",
+			expected: "after:2024/01/01 {label:IMPORTANT} -in:chats",
 		},
 		{
 			name: "with multiple labels (6 labels)",
@@ -44,7 +44,7 @@ func TestBuildQuery(t *testing.T) {
 				Labels: []string{"1-gtd", "0-leadership", "0-peers", "0-staff", "IMPORTANT", "STARRED"},
 			},
 			since:    time.Date(2024, 2, 17, 0, 0, 0, 0, time.UTC),
-			expected: "after:2024/02/17 {label:1-gtd label:0-leadership label:0-peers label:0-staff label:IMPORTANT label:STARRED}",
+			expected: "after:2024/02/17 {label:1-gtd label:0-leadership label:0-peers label:0-staff label:IMPORTANT label:STARRED} -in:chats",
 		},
 		{
 			name: "with custom query",
@@ -52,7 +52,7 @@ func TestBuildQuery(t *testing.T) {
 				Query: "has:attachment",
 			},
 			since:    time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
-			expected: "after:2024/01/01 (has:attachment)",
+			expected: "after:2024/01/01 (has:attachment) -in:chats",
 		},
 		{
 			name: "with from domains",
@@ -60,7 +60,7 @@ func TestBuildQuery(t *testing.T) {
 				FromDomains: []string{"company.com", "client.com"},
 			},
 			since:    time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
-			expected: "after:2024/01/01 {from:company.com from:client.com}",
+			expected: "after:2024/01/01 {from:company.com from:client.com} -in:chats",
 		},
 		{
 			name: "with to domains",
@@ -68,7 +68,7 @@ func TestBuildQuery(t *testing.T) {
 				ToDomains: []string{"work.com", "business.com"},
 			},
 			since:    time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
-			expected: "after:2024/01/01 {to:work.com to:business.com}",
+			expected: "after:2024/01/01 {to:work.com to:business.com} -in:chats",
 		},
 		{
 			name: "with exclude domains",
@@ -76,7 +76,7 @@ func TestBuildQuery(t *testing.T) {
 				ExcludeFromDomains: []string{"noreply.com", "spam.com"},
 			},
 			since:    time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
-			expected: "after:2024/01/01 -from:noreply.com -from:spam.com",
+			expected: "after:2024/01/01 -from:noreply.com -from:spam.com -in:chats",
 		},
 		{
 			name: "unread only",
@@ -85,7 +85,7 @@ func TestBuildQuery(t *testing.T) {
 				IncludeRead:   false,
 			},
 			since:    time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
-			expected: "after:2024/01/01 is:unread",
+			expected: "after:2024/01/01 is:unread -in:chats",
 		},
 		{
 			name: "read only",
@@ -94,7 +94,7 @@ func TestBuildQuery(t *testing.T) {
 				IncludeRead:   true,
 			},
 			since:    time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
-			expected: "after:2024/01/01 is:read",
+			expected: "after:2024/01/01 is:read -in:chats",
 		},
 		{
 			name: "both read and unread (no filter)",
@@ -103,7 +103,7 @@ func TestBuildQuery(t *testing.T) {
 				IncludeRead:   true,
 			},
 			since:    time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
-			expected: "after:2024/01/01",
+			expected: "after:2024/01/01 -in:chats",
 		},
 		{
 			name: "require attachments",
@@ -111,7 +111,7 @@ func TestBuildQuery(t *testing.T) {
 				RequireAttachments: true,
 			},
 			since:    time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
-			expected: "after:2024/01/01 has:attachment",
+			expected: "after:2024/01/01 has:attachment -in:chats",
 		},
 		{
 			name: "complex query with all filters",
@@ -126,7 +126,7 @@ func TestBuildQuery(t *testing.T) {
 				RequireAttachments: true,
 			},
 			since:    time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
-			expected: "after:2024/01/01 {label:IMPORTANT} (subject:meeting) {from:company.com} {to:work.com} -from:noreply.com is:unread has:attachment",
+			expected: "after:2024/01/01 {label:IMPORTANT} (subject:meeting) {from:company.com} {to:work.com} -from:noreply.com is:unread has:attachment -in:chats",
 		},
 		{
 			name: "with invalid max email age format",
@@ -134,7 +134,7 @@ func TestBuildQuery(t *testing.T) {
 				MaxEmailAge: "invalid",
 			},
 			since:    time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
-			expected: "after:2024/01/01", // Invalid duration should be ignored
+			expected: "after:2024/01/01 -in:chats", // Invalid duration should be ignored
 		},
 		{
 			name: "empty labels and domains should be filtered",
@@ -144,7 +144,7 @@ func TestBuildQuery(t *testing.T) {
 				ExcludeFromDomains: []string{"", "spam.com", ""},
 			},
 			since:    time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
-			expected: "after:2024/01/01 {label:IMPORTANT} {from:example.com} -from:spam.com",
+			expected: "after:2024/01/01 {label:IMPORTANT} {from:example.com} -from:spam.com -in:chats",
 		},
 	}
 
@@ -171,7 +171,7 @@ func TestBuildQueryWithRange(t *testing.T) {
 			config:   models.GmailSourceConfig{},
 			start:    time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
 			end:      time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC),
-			expected: "after:2024/01/01 before:2024/01/31",
+			expected: "after:2024/01/01 before:2024/01/31 -in:chats",
 		},
 		{
 			name: "range with labels",
@@ -180,7 +180,7 @@ func TestBuildQueryWithRange(t *testing.T) {
 			},
 			start:    time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
 			end:      time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC),
-			expected: "after:2024/01/01 before:2024/01/31 {label:IMPORTANT}",
+			expected: "after:2024/01/01 before:2024/01/31 {label:IMPORTANT} -in:chats",
 		},
 		{
 			name: "range with multiple labels",
@@ -189,7 +189,7 @@ func TestBuildQueryWithRange(t *testing.T) {
 			},
 			start:    time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
 			end:      time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC),
-			expected: "after:2024/01/01 before:2024/01/31 {label:IMPORTANT label:STARRED label:INBOX}",
+			expected: "after:2024/01/01 before:2024/01/31 {label:IMPORTANT label:STARRED label:INBOX} -in:chats",
 		},
 	}
 
@@ -203,6 +203,22 @@ func TestBuildQueryWithRange(t *testing.T) {
 	}
 }
 
+func TestServiceBuildQuery_Until(t *testing.T) {
+	s := &Service{config: models.GmailSourceConfig{Labels: []string{"IMPORTANT"}}}
+	since := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	if got := s.buildQuery(since); strings.Contains(got, "before:") {
+		t.Errorf("buildQuery() = %q, want no end without SetUntil", got)
+	}
+
+	s.SetUntil(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+
+	want := "after:2023/01/01 before:2024/01/01 {label:IMPORTANT} -in:chats"
+	if got := s.buildQuery(since); got != want {
+		t.Errorf("buildQuery() = %q, want %q", got, want)
+	}
+}
+
 func TestParseDuration(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -506,8 +522,8 @@ func TestQueryEdgeCases(t *testing.T) {
 			},
 			since: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
 			validate: func(result string) bool {
-				// Should only contain the since filter.
-				return result == "after:2024/01/01"
+				// Should only contain the since filter and the chat exclusion.
+				return result == "after:2024/01/01 -in:chats"
 			},
 		},
 	}
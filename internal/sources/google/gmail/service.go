@@ -3,6 +3,7 @@ package gmail
 import (
 	"context"
 	"encoding/base64"
+	"errors"
 	"fmt"
 	"log/slog"
 	"net/http"
@@ -38,6 +39,29 @@ type Service struct {
 	// Populated by resolveLabels(); used by buildQuery/buildQueryWithRange
 	// instead of s.config.Labels so we never mutate the original config.
 	resolvedQueryLabels []string
+
+	// cache, when set, serves threads and messages fetched by earlier runs.
+	cache *FetchCache
+
+	// partialThreads maps the threads of the last GetThreads call that were
+	// returned without some of their messages to the missing message IDs.
+	partialMu      sync.Mutex
+	partialThreads map[string][]string
+	// truncatedThreads maps threads cut to max_thread_messages to the number
+	// of older messages left out. Guarded by partialMu.
+	truncatedThreads map[string]int
+
+	// historyID is the newest mailbox history ID seen by GetHistory or
+	// RecordHistoryID; the next incremental run starts from it.
+	historyID uint64
+
+	// until, when set, ends the date queries of GetMessages and GetThreads
+	// (buildQueryWithRange); zero queries up to now.
+	until time.Time
+
+	// filesDir, when set, is where downloaded attachments are saved; their
+	// LocalPath points there.
+	filesDir string
 }
 
 // NewService creates a new Gmail service wrapper.
@@ -66,6 +90,18 @@ func NewService(client *http.Client, config models.GmailSourceConfig, sourceID s
 	return s, nil
 }
 
+// SetFilesDir saves downloaded attachments under dir and records their path in
+// Attachment.LocalPath. Pass "" to keep them in memory only.
+func (s *Service) SetFilesDir(dir string) {
+	s.filesDir = dir
+}
+
+// SetCache enables the disk-backed fetch cache for bulk thread and message
+// fetches. Pass nil to disable it.
+func (s *Service) SetCache(cache *FetchCache) {
+	s.cache = cache
+}
+
 // GetMessages retrieves messages based on the configured filters and time range.
 func (s *Service) GetMessages(since time.Time, limit int) ([]*gmail.Message, error) {
 	// For large mailboxes, use batch processing.
@@ -239,10 +275,21 @@ func (s *Service) GetMessagesInRange(start, end time.Time, limit int) ([]*gmail.
 	return messages, nil
 }
 
-// buildQuery constructs a Gmail search query based on configuration and since time.
+// SetUntil ends the window of later date queries at until; zero removes the
+// end.
+func (s *Service) SetUntil(until time.Time) {
+	s.until = until
+}
+
+// buildQuery constructs a Gmail search query based on configuration and since time,
+// ending at the SetUntil time when one is set.
 // It uses resolvedQueryLabels (if set) instead of the original config labels,
 // so that label IDs are replaced with query-safe names without mutating config.
 func (s *Service) buildQuery(since time.Time) string {
+	if !s.until.IsZero() {
+		return s.buildQueryWithRange(since, s.until)
+	}
+
 	cfg := s.queryConfig()
 
 	return buildQuery(cfg, since)
@@ -393,6 +440,11 @@ func (s *Service) executeWithRetry(fn func() (interface{}, error)) (interface{},
 
 		lastErr = err
 
+		// A canceled sync (e.g. Ctrl-C) must not be retried.
+		if errors.Is(err, context.Canceled) {
+			return nil, err
+		}
+
 		// Check if error is retryable.
 		if googleErr, ok := err.(*googleapi.Error); ok {
 			switch googleErr.Code {
@@ -669,10 +721,15 @@ func (s *Service) GetThreads(since time.Time, limit int) ([]*gmail.Thread, error
 	}
 
 	// Fetch full thread details concurrently.
+	s.resetPartialThreads()
+
 	threads, skippedCount := s.fetchThreadsConcurrently(listResp.Threads)
 
-	if skippedCount > 0 {
-		slog.Info("Thread retrieval completed", "retrieved", len(threads), "skipped", skippedCount)
+	if partial := s.partialThreadCount(threads); skippedCount > 0 || partial > 0 {
+		slog.Info("Thread retrieval completed",
+			"retrieved", len(threads),
+			"skipped", skippedCount,
+			"partial", partial)
 	}
 
 	return threads, nil
@@ -701,18 +758,70 @@ func (s *Service) GetThread(threadID string) (*gmail.Thread, error) {
 }
 
 // fetchThreadsConcurrently fetches full thread details concurrently with rate limiting.
+// A thread that cannot be fetched whole is returned with the messages that could
+// (see getThreadWithFallback).
 // Uses context.Background(); callers can provide a real context once Source.Fetch adds one.
 func (s *Service) fetchThreadsConcurrently(threadList []*gmail.Thread) ([]*gmail.Thread, int) {
+	// The list response carries each thread's historyId, which tells the cache
+	// whether its copy is still current.
+	historyIDs := make(map[string]uint64, len(threadList))
+	for _, t := range threadList {
+		historyIDs[t.Id] = t.HistoryId
+	}
+
 	return fetchConcurrently(
 		context.Background(),
+		s.concurrencyBounds(),
 		s.config.RequestDelay,
 		threadList,
 		func(t *gmail.Thread) string { return t.Id },
-		s.GetThread,
+		func(id string) (*gmail.Thread, error) { return s.getThreadWithFallback(id, historyIDs[id]) },
 		"thread",
 	)
 }
 
+// getThreadCached serves a thread from the fetch cache when possible.
+func (s *Service) getThreadCached(threadID string, historyID uint64) (*gmail.Thread, error) {
+	if s.cache == nil {
+		return s.GetThread(threadID)
+	}
+
+	if thread, ok := s.cache.Thread(threadID, historyID); ok {
+		return thread, nil
+	}
+
+	thread, err := s.GetThread(threadID)
+	if err != nil {
+		return nil, err
+	}
+
+	s.cache.PutThread(thread)
+
+	return thread, nil
+}
+
+// getMessageCached serves a message from the fetch cache when possible. A
+// non-zero historyID rejects copies cached before that change, see
+// FetchCache.Message.
+func (s *Service) getMessageCached(messageID string, historyID uint64) (*gmail.Message, error) {
+	if s.cache == nil {
+		return s.GetMessageWithRetry(messageID)
+	}
+
+	if msg, ok := s.cache.Message(messageID, historyID); ok {
+		return msg, nil
+	}
+
+	msg, err := s.GetMessageWithRetry(messageID)
+	if err != nil {
+		return nil, err
+	}
+
+	s.cache.PutMessage(msg)
+
+	return msg, nil
+}
+
 // isThreadError checks if an error is related to thread fetching.
 func isThreadError(err error) bool {
 	if err == nil {
@@ -744,114 +853,116 @@ func handleThreadError(threadID string, err error) error {
 // Items is the list of stubs, getID extracts an item's ID, fetch retrieves the full
 // item by ID, and itemType is used in log messages (e.g. "message" or "thread").
 // ctx is checked between items so callers can cancel in-flight work.
+//
+// Concurrency adapts to quota feedback within bounds (see adaptiveLimiter).
+// An item that fails with a rate-limit error is requeued, up to
+// maxRateLimitRequeues times, instead of being skipped.
 func fetchConcurrently[T any](
 	ctx context.Context,
+	bounds concurrencyBounds,
 	delay time.Duration,
 	items []T,
 	getID func(T) string,
 	fetch func(string) (T, error),
 	itemType string,
 ) ([]T, int) {
-	// Configure concurrency based on rate limiting needs.
-	maxWorkers := defaultConcurrentWorkers
-	if delay > highDelayThreshold {
-		// If delay is high, reduce concurrency.
-		maxWorkers = throttledConcurrentWorkers
+	type task struct {
+		item     T
+		requeues int
+	}
+
+	if len(items) == 0 {
+		return nil, 0
+	}
+
+	limiter := newAdaptiveLimiter(bounds)
+
+	// The queue never holds more than len(items) tasks: a task is only
+	// requeued after being taken out.
+	queue := make(chan task, len(items))
+	for _, item := range items {
+		queue <- task{item: item}
 	}
 
-	// Create channels for work distribution.
-	itemChan := make(chan T, len(items))
-	resultChan := make(chan T, len(items))
-	errorChan := make(chan error, len(items))
+	var (
+		mu           sync.Mutex
+		results      []T
+		skippedCount int32
+		pending      = int32(len(items))
+		wg           sync.WaitGroup
+	)
 
-	// Use atomic counter to avoid data race.
-	var skippedCount int32
+	// done retires a task; the last one closes the queue so workers exit.
+	done := func() {
+		if atomic.AddInt32(&pending, -1) == 0 {
+			close(queue)
+		}
+	}
 
-	// Start workers.
-	var wg sync.WaitGroup
-	for i := 0; i < maxWorkers; i++ {
+	// Start one worker per slot at the ceiling; the limiter decides how many
+	// of them may call the API at once.
+	for workerID := range bounds.max {
 		wg.Add(1)
 
-		go func(workerID int) {
+		go func() {
 			defer wg.Done()
 
 			for {
+				var (
+					t  task
+					ok bool
+				)
+
 				select {
 				case <-ctx.Done():
 					return
-				case item, ok := <-itemChan:
+				case t, ok = <-queue:
 					if !ok {
 						return
 					}
+				}
 
-					// Apply rate limiting per worker.
-					if delay > 0 {
-						time.Sleep(delay)
-					}
-
-					id := getID(item)
-
-					full, err := fetch(id)
-					if err != nil {
-						slog.Warn("Worker failed to get "+itemType,
-							"worker_id", workerID,
-							itemType+"_id", id,
-							"error", err)
-						atomic.AddInt32(&skippedCount, 1)
+				if !limiter.acquire(ctx) {
+					return
+				}
 
-						errorChan <- err
-					} else {
-						resultChan <- full
-					}
+				// Apply rate limiting per worker.
+				if delay > 0 {
+					time.Sleep(delay)
 				}
-			}
-		}(i)
-	}
 
-	// Send work to workers; close channel on exit so workers drain cleanly.
-	go func() {
-		defer close(itemChan)
+				id := getID(t.item)
 
-		for _, item := range items {
-			select {
-			case <-ctx.Done():
-				return
-			case itemChan <- item:
-			}
-		}
-	}()
+				full, err := fetch(id)
+				rateLimited := isRateLimitError(err)
 
-	// Wait for workers to complete.
-	go func() {
-		wg.Wait()
-		close(resultChan)
-		close(errorChan)
-	}()
+				limiter.release(rateLimited)
 
-	// Collect results.
-	var results []T
+				switch {
+				case err == nil:
+					mu.Lock()
+					results = append(results, full)
+					mu.Unlock()
+				case rateLimited && t.requeues < maxRateLimitRequeues:
+					t.requeues++
+					queue <- t
 
-	// Collect all results.
-	for {
-		select {
-		case result, ok := <-resultChan:
-			if !ok {
-				resultChan = nil
-			} else {
-				results = append(results, result)
-			}
-		case _, ok := <-errorChan:
-			if !ok {
-				errorChan = nil
-			}
-		}
+					continue
+				default:
+					slog.Warn("Worker failed to get "+itemType,
+						"worker_id", workerID,
+						itemType+"_id", id,
+						"error", err)
+					atomic.AddInt32(&skippedCount, 1)
+				}
 
-		// Break when both channels are closed.
-		if resultChan == nil && errorChan == nil {
-			break
-		}
+				done()
+			}
+		}()
 	}
 
+	wg.Wait()
+
 	return results, int(atomic.LoadInt32(&skippedCount))
 }
 
@@ -860,10 +971,11 @@ func fetchConcurrently[T any](
 func (s *Service) fetchMessagesConcurrently(messageList []*gmail.Message) ([]*gmail.Message, int) {
 	return fetchConcurrently(
 		context.Background(),
+		s.concurrencyBounds(),
 		s.config.RequestDelay,
 		messageList,
 		func(msg *gmail.Message) string { return msg.Id },
-		s.GetMessageWithRetry,
+		func(id string) (*gmail.Message, error) { return s.getMessageCached(id, 0) },
 		"message",
 	)
 }
@@ -77,7 +77,7 @@ func TestService_buildQuery(t *testing.T) {
 			name:     "basic time filter",
 			config:   models.GmailSourceConfig{},
 			since:    time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
-			expected: "after:2024/01/01",
+			expected: "after:2024/01/01 -in:chats",
 		},
 		{
 			name: "with labels",
@@ -85,7 +85,7 @@ func TestService_buildQuery(t *testing.T) {
 				Labels: []string{"IMPORTANT", "STARRED"},
 			},
 			since:    time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
-			expected: "after:2024/01/01 {label:IMPORTANT label:STARRED}",
+			expected: "after:2024/01/01 {label:IMPORTANT label:STARRED} -in:chats",
 		},
 		{
 			name: "with custom query",
@@ -93,7 +93,7 @@ func TestService_buildQuery(t *testing.T) {
 				Query: "has:attachment",
 			},
 			since:    time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
-			expected: "after:2024/01/01 (has:attachment)",
+			expected: "after:2024/01/01 (has:attachment) -in:chats",
 		},
 		{
 			name: "with from domains",
@@ -101,7 +101,7 @@ func TestService_buildQuery(t *testing.T) {
 				FromDomains: []string{"company.com", "client.com"},
 			},
 			since:    time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
-			expected: "after:2024/01/01 {from:company.com from:client.com}",
+			expected: "after:2024/01/01 {from:company.com from:client.com} -in:chats",
 		},
 		{
 			name: "with exclude domains",
@@ -109,7 +109,7 @@ func TestService_buildQuery(t *testing.T) {
 				ExcludeFromDomains: []string{"noreply.com"},
 			},
 			since:    time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
-			expected: "after:2024/01/01 -from:noreply.com",
+			expected: "after:2024/01/01 -from:noreply.com -in:chats",
 		},
 		{
 			name: "unread only",
@@ -118,7 +118,7 @@ func TestService_buildQuery(t *testing.T) {
 				IncludeRead:   false,
 			},
 			since:    time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
-			expected: "after:2024/01/01 is:unread",
+			expected: "after:2024/01/01 is:unread -in:chats",
 		},
 		{
 			name: "read only",
@@ -127,7 +127,7 @@ func TestService_buildQuery(t *testing.T) {
 				IncludeRead:   true,
 			},
 			since:    time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
-			expected: "after:2024/01/01 is:read",
+			expected: "after:2024/01/01 is:read -in:chats",
 		},
 		{
 			name: "require attachments",
@@ -135,7 +135,7 @@ func TestService_buildQuery(t *testing.T) {
 				RequireAttachments: true,
 			},
 			since:    time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
-			expected: "after:2024/01/01 has:attachment",
+			expected: "after:2024/01/01 has:attachment -in:chats",
 		},
 		{
 			name: "complex query",
@@ -149,7 +149,7 @@ func TestService_buildQuery(t *testing.T) {
 				RequireAttachments: true,
 			},
 			since:    time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
-			expected: "after:2024/01/01 {label:IMPORTANT} (has:attachment) {from:company.com} -from:noreply.com is:unread has:attachment",
+			expected: "after:2024/01/01 {label:IMPORTANT} (has:attachment) {from:company.com} -from:noreply.com is:unread has:attachment -in:chats",
 		},
 	}
 
@@ -0,0 +1,88 @@
+package gmail
+
+import (
+	"log/slog"
+
+	"google.golang.org/api/gmail/v1"
+)
+
+// getCappedThread enforces max_thread_messages. It lists the thread's message
+// IDs with a minimal fetch and, when there are more than the cap, fetches only
+// the most recent ones and records how many were left out. capped is false
+// when the thread is within the cap, leaving the whole-thread fetch to
+// getThreadWithFallback. Capped threads are not cached, so a later run with a
+// higher cap fetches the rest.
+func (s *Service) getCappedThread(threadID string, historyID uint64) (thread *gmail.Thread, capped bool, err error) {
+	limit := s.config.MaxThreadMessages
+
+	if s.cache != nil {
+		if cached, ok := s.cache.Thread(threadID, historyID); ok {
+			return s.capThread(cached, limit), true, nil
+		}
+	}
+
+	stub, err := s.getThreadStub(threadID)
+	if err != nil {
+		if isRateLimitError(err) {
+			return nil, true, err
+		}
+
+		// Let the regular fetch and its fallback report the failure.
+		return nil, false, nil
+	}
+
+	if len(stub.Messages) <= limit {
+		return nil, false, nil
+	}
+
+	thread, err = s.fetchThreadMessages(stub, stub.Messages[len(stub.Messages)-limit:])
+	if err != nil {
+		return nil, true, err
+	}
+
+	s.recordTruncated(threadID, len(stub.Messages)-limit)
+
+	return thread, true, nil
+}
+
+// capThread returns thread with only its limit most recent messages, recording
+// the cut. Threads within the limit are returned as is.
+func (s *Service) capThread(thread *gmail.Thread, limit int) *gmail.Thread {
+	if len(thread.Messages) <= limit {
+		return thread
+	}
+
+	capped := *thread
+	capped.Messages = thread.Messages[len(thread.Messages)-limit:]
+
+	s.recordTruncated(thread.Id, len(thread.Messages)-limit)
+
+	return &capped
+}
+
+// recordTruncated notes that omitted older messages of threadID were not fetched.
+func (s *Service) recordTruncated(threadID string, omitted int) {
+	slog.Info("Thread exceeds max_thread_messages; keeping the most recent messages",
+		"source_id", s.sourceID,
+		"thread_id", threadID,
+		"kept", s.config.MaxThreadMessages,
+		"omitted", omitted)
+
+	s.partialMu.Lock()
+	defer s.partialMu.Unlock()
+
+	if s.truncatedThreads == nil {
+		s.truncatedThreads = make(map[string]int)
+	}
+
+	s.truncatedThreads[threadID] = omitted
+}
+
+// TruncatedThreadMessages returns how many older messages of threadID the last
+// GetThreads call left out under max_thread_messages, or 0 when none were.
+func (s *Service) TruncatedThreadMessages(threadID string) int {
+	s.partialMu.Lock()
+	defer s.partialMu.Unlock()
+
+	return s.truncatedThreads[threadID]
+}
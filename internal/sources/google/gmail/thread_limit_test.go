@@ -0,0 +1,163 @@
+package gmail
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+
+	"pkm-sync/pkg/models"
+
+	"google.golang.org/api/gmail/v1"
+	"google.golang.org/api/option"
+)
+
+// newLongThreadService serves thread "long" with messages m1..m6 and thread
+// "short" with s1, and records every request as "<format> <id>" for threads
+// and "message <id>" for messages.
+func newLongThreadService(t *testing.T, maxThreadMessages int) (*Service, func() []string) {
+	t.Helper()
+
+	var (
+		mu       sync.Mutex
+		requests []string
+	)
+
+	message := func(id string, n int) *gmail.Message {
+		return &gmail.Message{
+			Id:           id,
+			InternalDate: int64(n * 1000),
+			Payload: &gmail.MessagePart{
+				MimeType: "text/plain",
+				Headers:  []*gmail.MessagePartHeader{{Name: "Subject", Value: "Mailing list"}},
+				Body:     &gmail.MessagePartBody{Data: "Ym9keQ"}, // "body"
+			},
+		}
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.URL.Path[strings.LastIndex(r.URL.Path, "/")+1:]
+
+		var body any
+
+		if strings.Contains(r.URL.Path, "/threads/") {
+			format := r.URL.Query().Get("format")
+
+			mu.Lock()
+			requests = append(requests, format+" "+id)
+			mu.Unlock()
+
+			ids := []string{"s1"}
+			if id == "long" {
+				ids = []string{"m1", "m2", "m3", "m4", "m5", "m6"}
+			}
+
+			thread := &gmail.Thread{Id: id}
+
+			for i, msgID := range ids {
+				if format == "minimal" {
+					thread.Messages = append(thread.Messages, &gmail.Message{Id: msgID})
+				} else {
+					thread.Messages = append(thread.Messages, message(msgID, i+1))
+				}
+			}
+
+			body = thread
+		} else {
+			mu.Lock()
+			requests = append(requests, "message "+id)
+			mu.Unlock()
+
+			var n int
+			_, _ = fmt.Sscanf(id, "m%d", &n)
+			body = message(id, n)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(body)
+	}))
+	t.Cleanup(server.Close)
+
+	api, err := gmail.NewService(t.Context(), option.WithHTTPClient(server.Client()), option.WithEndpoint(server.URL+"/"))
+	if err != nil {
+		t.Fatalf("failed to create gmail client: %v", err)
+	}
+
+	svc := &Service{service: api, config: models.GmailSourceConfig{MaxThreadMessages: maxThreadMessages}}
+
+	return svc, func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+
+		return slices.Clone(requests)
+	}
+}
+
+func TestFetchThreads_CapsLongThreadToMostRecentMessages(t *testing.T) {
+	svc, requests := newLongThreadService(t, 2)
+
+	threads, skipped := svc.fetchThreadsConcurrently([]*gmail.Thread{{Id: "long"}})
+	if skipped != 0 || len(threads) != 1 {
+		t.Fatalf("got %d threads, %d skipped; want the thread kept", len(threads), skipped)
+	}
+
+	if ids := messageIDs(threads[0].Messages); !slices.Equal(ids, []string{"m5", "m6"}) {
+		t.Fatalf("messages = %v, want the two most recent [m5 m6]", ids)
+	}
+
+	// Only the ID listing and the kept messages are fetched.
+	if got, want := requests(), []string{"minimal long", "message m5", "message m6"}; !slices.Equal(got, want) {
+		t.Errorf("requests = %v, want %v", got, want)
+	}
+
+	if got := svc.TruncatedThreadMessages("long"); got != 4 {
+		t.Errorf("TruncatedThreadMessages = %d, want 4", got)
+	}
+
+	item, err := FromGmailThread(threads[0], models.GmailSourceConfig{}, svc)
+	if err != nil {
+		t.Fatalf("FromGmailThread: %v", err)
+	}
+
+	if item.Metadata["thread_truncated"] != true || item.Metadata["omitted_message_count"] != 4 {
+		t.Errorf("metadata = %v, want thread_truncated with 4 omitted messages", item.Metadata)
+	}
+
+	if item.Metadata["message_count"] != 2 {
+		t.Errorf("message_count = %v, want 2", item.Metadata["message_count"])
+	}
+}
+
+func TestFetchThreads_ThreadWithinCapFetchedWhole(t *testing.T) {
+	svc, requests := newLongThreadService(t, 2)
+
+	threads, _ := svc.fetchThreadsConcurrently([]*gmail.Thread{{Id: "short"}})
+	if len(threads) != 1 || len(threads[0].Messages) != 1 {
+		t.Fatalf("expected the short thread with its message, got %v", threads)
+	}
+
+	if got, want := requests(), []string{"minimal short", "full short"}; !slices.Equal(got, want) {
+		t.Errorf("requests = %v, want %v", got, want)
+	}
+
+	if got := svc.TruncatedThreadMessages("short"); got != 0 {
+		t.Errorf("TruncatedThreadMessages = %d, want 0", got)
+	}
+}
+
+func TestFetchThreads_NoCapFetchesWholeThread(t *testing.T) {
+	svc, requests := newLongThreadService(t, 0)
+
+	threads, _ := svc.fetchThreadsConcurrently([]*gmail.Thread{{Id: "long"}})
+	if len(threads) != 1 || len(threads[0].Messages) != 6 {
+		t.Fatalf("expected all 6 messages without a cap, got %v", threads)
+	}
+
+	if got, want := requests(), []string{"full long"}; !slices.Equal(got, want) {
+		t.Errorf("requests = %v, want %v", got, want)
+	}
+}
@@ -1,13 +1,23 @@
 package google
 
 import (
+	"context"
+	"encoding/base64"
+	"errors"
 	"fmt"
 	"log/slog"
+	"mime"
 	"net/http"
+	"net/url"
+	"path"
+	"slices"
+	"strings"
 	"time"
 
 	"golang.org/x/sync/errgroup"
+	gmailapi "google.golang.org/api/gmail/v1"
 
+	"pkm-sync/internal/httpclient"
 	"pkm-sync/internal/sources/google/auth"
 	"pkm-sync/internal/sources/google/calendar"
 	"pkm-sync/internal/sources/google/drive"
@@ -20,6 +30,8 @@ const (
 	driveItemTypeDocument     = "document"
 	driveItemTypeSpreadsheet  = "spreadsheet"
 	driveItemTypePresentation = "presentation"
+	driveItemTypeFile         = "file"
+	driveItemTypeFolderBundle = "folder_bundle"
 	calendarIDPrimary         = "primary"
 )
 
@@ -35,6 +47,9 @@ type driveExporter interface {
 	) ([]*drive.DriveFileInfo, error)
 	ListSharedWithMe(since time.Time, opts drive.ListFilesOptions) ([]*drive.DriveFileInfo, error)
 	ExportAsString(fileID, exportMimeType string, convertToMarkdown bool, maxBytes int64) (string, error)
+	ExportSheetTabs(fileID string, selectors []string, format string, maxBytes int64) (string, error)
+	DownloadAsBytes(fileID string, maxBytes int64) ([]byte, error)
+	GetFileMetadata(fileID string) (*models.DriveFile, error)
 }
 
 const (
@@ -49,8 +64,20 @@ type GoogleSource struct {
 	driveService    driveExporter
 	gmailService    *gmail.Service
 	httpClient      *http.Client
+	transport       *httpclient.Transport
 	config          models.SourceConfig
 	sourceID        string
+
+	// driveFolderNames caches the name of each configured Drive folder ("" when
+	// it cannot be resolved), so it is looked up once per source.
+	driveFolderNames map[string]string
+
+	// startHistoryID, when set, makes Gmail message fetches ask the History
+	// API for changes since it instead of running the date query.
+	startHistoryID string
+
+	// until, when set, ends the fetch window (see SetUntil).
+	until time.Time
 }
 
 func NewGoogleSource() *GoogleSource {
@@ -89,6 +116,9 @@ func (g *GoogleSource) Configure(config map[string]interface{}, client *http.Cli
 		}
 	}
 
+	// Every Google API call goes through this client, so wrapping its transport
+	// applies the per-request timeout and FetchContext cancellation everywhere.
+	client, g.transport = httpclient.NewClient(client, httpclient.ResolveTimeout(g.config.RequestTimeout, 0))
 	g.httpClient = client
 
 	// Initialize services based on source type
@@ -169,6 +199,12 @@ func (g *GoogleSource) configureCalendarService(config map[string]interface{}) {
 			g.calendarService.SetIncludeSelfOnlyEvents(includeBool)
 		}
 	}
+
+	if len(g.config.Google.ResponseStatuses) > 0 {
+		g.calendarService.SetResponseStatuses(g.config.Google.ResponseStatuses)
+	}
+
+	g.calendarService.SetIncludePrivate(g.config.Google.IncludePrivate)
 }
 
 func (g *GoogleSource) Fetch(since time.Time, limit int) ([]models.FullItem, error) {
@@ -182,11 +218,25 @@ func (g *GoogleSource) Fetch(since time.Time, limit int) ([]models.FullItem, err
 	}
 }
 
+// FetchContext implements interfaces.ContextSource. ctx is bound to the HTTP
+// transport for the duration of the fetch, so canceling it aborts in-flight
+// Gmail, Calendar, and Drive requests.
+func (g *GoogleSource) FetchContext(ctx context.Context, since time.Time, limit int) ([]models.FullItem, error) {
+	if g.transport != nil {
+		g.transport.Bind(ctx)
+		defer g.transport.Bind(nil)
+	}
+
+	return g.Fetch(since, limit)
+}
+
 func (g *GoogleSource) fetchGmail(since time.Time, limit int) ([]models.FullItem, error) {
 	if g.gmailService == nil {
 		return nil, fmt.Errorf("gmail service not initialized")
 	}
 
+	g.gmailService.SetUntil(g.until)
+
 	// Use Threads API when thread grouping is enabled for native thread fetching.
 	if g.config.Gmail.IncludeThreads {
 		return g.fetchGmailThreads(since, limit)
@@ -195,9 +245,36 @@ func (g *GoogleSource) fetchGmail(since time.Time, limit int) ([]models.FullItem
 	return g.fetchGmailMessages(since, limit)
 }
 
+// SetStartHistoryID makes the next Gmail fetch return only the messages added
+// or labeled since id, via the History API. It applies to message fetches
+// whose filters are all label-based; other fetches use the date query.
+func (g *GoogleSource) SetStartHistoryID(id string) {
+	g.startHistoryID = id
+}
+
+// SetUntil implements interfaces.UntilSource: Gmail fetches query messages
+// before until, Drive fetches files modified before it and Calendar fetches
+// events starting before it. A backfill window skips the History API.
+func (g *GoogleSource) SetUntil(until time.Time) {
+	g.until = until
+}
+
+// HistoryID returns the Gmail history ID the last fetch reached, to pass to
+// SetStartHistoryID on the next run. Empty when it is unknown or the fetch was
+// a backfill window ending at until. When the delta held more changes than
+// the fetch limit, it is the last change fetched, so the next run continues
+// from there.
+func (g *GoogleSource) HistoryID() string {
+	if g.gmailService == nil || !g.until.IsZero() {
+		return ""
+	}
+
+	return g.gmailService.LatestHistoryID()
+}
+
 // fetchGmailMessages fetches individual messages using the Messages API.
 func (g *GoogleSource) fetchGmailMessages(since time.Time, limit int) ([]models.FullItem, error) {
-	messages, err := g.gmailService.GetMessages(since, limit)
+	messages, err := g.gmailMessages(since, limit)
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch Gmail messages: %w", err)
 	}
@@ -206,16 +283,71 @@ func (g *GoogleSource) fetchGmailMessages(since time.Time, limit int) ([]models.
 
 	for _, message := range messages {
 		legacyItem, err := gmail.FromGmailMessageWithService(message, g.config.Gmail, g.gmailService)
+		if errors.Is(err, gmail.ErrUndatedMessage) {
+			reportUndated(g.sourceID, err)
+
+			continue
+		}
+
+		if errors.Is(err, gmail.ErrChatMessage) {
+			slog.Debug("Skipping Google Chat message", "source_id", g.sourceID, "error", err)
+
+			continue
+		}
+
 		if err != nil {
 			return nil, fmt.Errorf("failed to convert Gmail message to item: %w", err)
 		}
 
 		items = append(items, models.AsFullItem(legacyItem))
+
+		for _, item := range gmail.EmbeddedMessageItems(g.config.Gmail, g.gmailService, legacyItem, message) {
+			items = append(items, models.AsFullItem(item))
+		}
 	}
 
 	return items, nil
 }
 
+// gmailMessages returns the History API delta when a start history ID is set,
+// falling back to the date query when there is none, the filters need a
+// search, or the ID has expired. The date query path records the mailbox's
+// current history ID first so the next run can go incremental, except for a
+// backfill window ending at until: its cursor would skip the mail that
+// arrived between the last sync and now.
+func (g *GoogleSource) gmailMessages(since time.Time, limit int) ([]*gmailapi.Message, error) {
+	if g.startHistoryID != "" && g.until.IsZero() && g.gmailService.HistoryFilterable() {
+		messages, more, err := g.gmailService.GetHistory(g.startHistoryID, limit)
+		if err == nil {
+			if more {
+				fmt.Printf("  → %s: more than %d message(s) changed since history ID %s; "+
+					"syncing the oldest, the next run continues from there\n", g.sourceID, limit, g.startHistoryID)
+			}
+
+			fmt.Printf("  → %s: %d message(s) changed since history ID %s\n", g.sourceID, len(messages), g.startHistoryID)
+
+			return messages, nil
+		}
+
+		if !errors.Is(err, gmail.ErrHistoryExpired) {
+			return nil, err
+		}
+
+		fmt.Printf("  → %s: Gmail history ID %s expired, using date query\n", g.sourceID, g.startHistoryID)
+	}
+
+	if !g.until.IsZero() {
+		return g.gmailService.GetMessages(since, limit)
+	}
+
+	if err := g.gmailService.RecordHistoryID(); err != nil {
+		slog.Warn("Could not read Gmail history ID; next sync will use the date query",
+			"source_id", g.sourceID, "error", err)
+	}
+
+	return g.gmailService.GetMessages(since, limit)
+}
+
 // fetchGmailThreads fetches complete threads using the Threads API.
 func (g *GoogleSource) fetchGmailThreads(since time.Time, limit int) ([]models.FullItem, error) {
 	threads, err := g.gmailService.GetThreads(since, limit)
@@ -224,19 +356,61 @@ func (g *GoogleSource) fetchGmailThreads(since time.Time, limit int) ([]models.F
 	}
 
 	items := make([]models.FullItem, 0, len(threads))
+	partial, truncated := 0, 0
 
 	for _, thread := range threads {
+		if len(g.gmailService.MissingThreadMessages(thread.Id)) > 0 {
+			partial++
+		}
+
+		if g.gmailService.TruncatedThreadMessages(thread.Id) > 0 {
+			truncated++
+		}
+
 		legacyItem, err := gmail.FromGmailThread(thread, g.config.Gmail, g.gmailService)
+		if errors.Is(err, gmail.ErrUndatedMessage) {
+			reportUndated(g.sourceID, err)
+
+			continue
+		}
+
+		if errors.Is(err, gmail.ErrChatMessage) {
+			slog.Debug("Skipping Google Chat message", "source_id", g.sourceID, "error", err)
+
+			continue
+		}
+
 		if err != nil {
 			return nil, fmt.Errorf("failed to convert Gmail thread to item: %w", err)
 		}
 
 		items = append(items, models.AsFullItem(legacyItem))
+
+		for _, item := range gmail.EmbeddedMessageItems(g.config.Gmail, g.gmailService, legacyItem, thread.Messages...) {
+			items = append(items, models.AsFullItem(item))
+		}
+	}
+
+	if partial > 0 {
+		fmt.Printf("Warning: %s: %d thread(s) synced without some of their messages (marked partial_thread)\n",
+			g.sourceID, partial)
+	}
+
+	if truncated > 0 {
+		fmt.Printf("Warning: %s: %d thread(s) cut to their %d most recent messages (marked thread_truncated)\n",
+			g.sourceID, truncated, g.config.Gmail.MaxThreadMessages)
 	}
 
 	return items, nil
 }
 
+// reportUndated warns about a message skipped under the default
+// undated_messages policy.
+func reportUndated(sourceID string, err error) {
+	fmt.Printf("Warning: %s: skipping %v (set gmail.undated_messages to \"sentinel\" or \"now\" to keep it)\n",
+		sourceID, err)
+}
+
 func (g *GoogleSource) fetchCalendar(since time.Time, limit int) ([]models.FullItem, error) {
 	if g.calendarService == nil {
 		return nil, fmt.Errorf("calendar service not initialized")
@@ -252,7 +426,12 @@ func (g *GoogleSource) fetchCalendar(since time.Time, limit int) ([]models.FullI
 		calLimit = 0 // 0 = no limit in Calendar API
 	}
 
-	events, err := g.calendarService.GetEventsInRange(calendarID, since, time.Now().AddDate(0, 1, 0), calLimit)
+	end := time.Now().AddDate(0, 1, 0)
+	if !g.until.IsZero() {
+		end = g.until
+	}
+
+	events, err := g.calendarService.GetEventsInRange(calendarID, since, end, calLimit)
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch calendar events: %w", err)
 	}
@@ -274,6 +453,63 @@ func (g *GoogleSource) SupportsRealtime() bool {
 	return false // Future: implement webhooks
 }
 
+// CheckAuth implements interfaces.AuthChecker: the Gmail profile, the Drive
+// root folder or the configured calendar, depending on the source type.
+func (g *GoogleSource) CheckAuth() (string, error) {
+	switch {
+	case g.config.Type == SourceTypeGmail && g.gmailService != nil:
+		profile, err := g.gmailService.GetProfile()
+		if err != nil {
+			return "", err
+		}
+
+		return profile.EmailAddress, nil
+	case g.config.Type == SourceTypeDrive && g.driveService != nil:
+		if _, err := g.driveService.GetFileMetadata("root"); err != nil {
+			return "", err
+		}
+
+		return "Drive root accessible", nil
+	case g.calendarService != nil:
+		calendarID := g.config.Google.CalendarID
+		if calendarID == "" {
+			calendarID = calendarIDPrimary
+		}
+
+		summary, err := g.calendarService.GetCalendarSummary(calendarID)
+		if err != nil {
+			return "", err
+		}
+
+		return fmt.Sprintf("calendar %q", summary), nil
+	default:
+		return "", fmt.Errorf("source not configured")
+	}
+}
+
+// Capabilities implements interfaces.Source. All three Google APIs filter by
+// since; only Gmail has threads and raw messages.
+func (g *GoogleSource) Capabilities() interfaces.SourceCapabilities {
+	isGmail := g.config.Type == SourceTypeGmail
+
+	return interfaces.SourceCapabilities{
+		SupportsRealtime:    g.SupportsRealtime(),
+		ProvidesRawMessages: isGmail,
+		SupportsIncremental: true,
+		ProducesThreads:     isGmail,
+	}
+}
+
+// RawMessageFetcher implements interfaces.RawMessageSource. It returns nil
+// for non-Gmail sources and before Configure.
+func (g *GoogleSource) RawMessageFetcher() interfaces.RawMessageFetcher {
+	if g.config.Type != SourceTypeGmail || g.gmailService == nil {
+		return nil
+	}
+
+	return g.gmailService
+}
+
 // initializeDriveOnlyService initializes only the Drive service for Drive sources.
 func (g *GoogleSource) initializeDriveOnlyService(client *http.Client) error {
 	svc, err := drive.NewService(client)
@@ -325,9 +561,16 @@ func (g *GoogleSource) fetchDrive(since time.Time, limit int) ([]models.FullItem
 		}
 	}
 
+	// Binary files have arbitrary MIME types, so list everything and filter afterwards.
+	listMimeTypes := mimeTypes
+	if cfg.IncludeBinaryFiles {
+		listMimeTypes = nil
+	}
+
 	listOpts := drive.ListFilesOptions{
-		MimeTypes:           mimeTypes,
+		MimeTypes:           listMimeTypes,
 		ModifiedAfter:       since,
+		ModifiedBefore:      g.until,
 		ExtraQuery:          cfg.Query,
 		IncludeSharedDrives: cfg.IncludeSharedDrives,
 	}
@@ -336,9 +579,23 @@ func (g *GoogleSource) fetchDrive(since time.Time, limit int) ([]models.FullItem
 		listOpts.MaxResults = limit
 	}
 
+	// A bundle note holds every file of its folder, so folders are listed in
+	// full; bundleDriveFolders drops the ones with no change since then.
+	listSince := since
+	if cfg.BundleFolders {
+		listSince = time.Time{}
+		listOpts.ModifiedAfter = time.Time{}
+	}
+
 	// Collect files, deduplicating across folders
 	seen := make(map[string]bool)
 
+	// origins maps a file ID to the tags of the configured folders listing it.
+	origins := make(map[string][]string)
+
+	// listedIn maps a file ID to the first folder listing it, its bundle.
+	listedIn := make(map[string]string)
+
 	var allFiles []*drive.DriveFileInfo
 
 	folderIDs := cfg.FolderIDs
@@ -347,23 +604,44 @@ func (g *GoogleSource) fetchDrive(since time.Time, limit int) ([]models.FullItem
 	}
 
 	for _, folderID := range folderIDs {
-		files, err := g.driveService.ListFilesInFolder(folderID, since, cfg.Recursive, listOpts)
+		files, err := g.driveService.ListFilesInFolder(folderID, listSince, cfg.Recursive, listOpts)
 		if err != nil {
-			return nil, fmt.Errorf("failed to list files in folder %s: %w", folderID, err)
+			if !isPartialListing(err) {
+				return nil, fmt.Errorf("failed to list files in folder %s: %w", folderID, err)
+			}
+
+			slog.Warn("Drive folder listing incomplete; continuing with partial results",
+				"folder_id", folderID, "files", len(files), "error", err)
+		}
+
+		// Only folders the user configured are tagged; the implicit root is not.
+		folderTag := ""
+		if len(cfg.FolderIDs) > 0 {
+			folderTag = g.driveFolderTag(folderID)
 		}
 
 		for _, f := range files {
+			if folderTag != "" && !slices.Contains(origins[f.ID], folderTag) {
+				origins[f.ID] = append(origins[f.ID], folderTag)
+			}
+
 			if !seen[f.ID] {
 				seen[f.ID] = true
+				listedIn[f.ID] = folderID
 				allFiles = append(allFiles, f)
 			}
 		}
 	}
 
 	if cfg.IncludeSharedWithMe {
-		sharedFiles, err := g.driveService.ListSharedWithMe(since, listOpts)
+		sharedFiles, err := g.driveService.ListSharedWithMe(listSince, listOpts)
 		if err != nil {
-			return nil, fmt.Errorf("failed to list shared-with-me files: %w", err)
+			if !isPartialListing(err) {
+				return nil, fmt.Errorf("failed to list shared-with-me files: %w", err)
+			}
+
+			slog.Warn("Drive shared-with-me listing incomplete; continuing with partial results",
+				"files", len(sharedFiles), "error", err)
 		}
 
 		for _, f := range sharedFiles {
@@ -374,6 +652,18 @@ func (g *GoogleSource) fetchDrive(since time.Time, limit int) ([]models.FullItem
 		}
 	}
 
+	if cfg.IncludeBinaryFiles {
+		filtered := allFiles[:0]
+
+		for _, f := range allFiles {
+			if wantDriveFile(f, mimeTypes, cfg) {
+				filtered = append(filtered, f)
+			}
+		}
+
+		allFiles = filtered
+	}
+
 	// Apply size filter before the count limit so oversized files don't consume
 	// slots and silently reduce the number of exportable items.
 	if cfg.MaxFileSizeBytes > 0 {
@@ -433,12 +723,16 @@ func (g *GoogleSource) fetchDrive(since time.Time, limit int) ([]models.FullItem
 
 	var failureCount int
 
-	for _, r := range results {
+	for i, r := range results {
 		if r.err != nil {
 			failureCount++
 
 			slog.Warn("Failed to convert Drive file", "file", r.name, "error", r.err)
 		} else {
+			if tags := origins[allFiles[i].ID]; len(tags) > 0 {
+				r.item.SetTags(append(r.item.GetTags(), tags...))
+			}
+
 			items = append(items, r.item)
 		}
 	}
@@ -451,14 +745,142 @@ func (g *GoogleSource) fetchDrive(since time.Time, limit int) ([]models.FullItem
 		)
 	}
 
+	if cfg.BundleFolders {
+		return g.bundleDriveFolders(items, folderIDs, listedIn, since), nil
+	}
+
 	return items, nil
 }
 
+// bundleDriveFolders replaces the items of each folder with one bundle note,
+// in folder_ids order. A folder none of whose files changed after since is
+// left out, like an unchanged file. Files not listed from a folder (shared
+// with me) stay individual notes.
+func (g *GoogleSource) bundleDriveFolders(
+	items []models.FullItem,
+	folderIDs []string,
+	listedIn map[string]string,
+	since time.Time,
+) []models.FullItem {
+	byFolder := make(map[string][]models.FullItem)
+
+	var result []models.FullItem
+
+	for _, item := range items {
+		folderID, ok := listedIn[item.GetID()]
+		if !ok {
+			result = append(result, item)
+
+			continue
+		}
+
+		byFolder[folderID] = append(byFolder[folderID], item)
+	}
+
+	for _, folderID := range folderIDs {
+		files := byFolder[folderID]
+
+		changed := since.IsZero()
+		for _, f := range files {
+			changed = changed || f.GetUpdatedAt().After(since)
+		}
+
+		if len(files) == 0 || !changed {
+			continue
+		}
+
+		name := g.driveFolderName(folderID)
+		if name == "" {
+			name = folderID
+		}
+
+		result = append(result, buildDriveFolderBundle(folderID, name, files))
+	}
+
+	return result
+}
+
+// buildDriveFolderBundle renders the files of a folder, sorted by title, as
+// sections of one note after a table of contents linking to each section.
+// Each file keeps the content of its own export format.
+func buildDriveFolderBundle(folderID, name string, files []models.FullItem) models.FullItem {
+	files = slices.Clone(files)
+	slices.SortStableFunc(files, func(a, b models.FullItem) int {
+		return strings.Compare(strings.ToLower(a.GetTitle()), strings.ToLower(b.GetTitle()))
+	})
+
+	var (
+		content     strings.Builder
+		links       []models.Link
+		attachments []models.Attachment
+		tags        = []string{}
+		fileIDs     []string
+		created     time.Time
+		updated     time.Time
+	)
+
+	content.WriteString("# " + name + "\n\n## Contents\n\n")
+
+	for _, f := range files {
+		content.WriteString(fmt.Sprintf("- [%s](#%s)\n", f.GetTitle(), url.PathEscape(f.GetTitle())))
+	}
+
+	for _, f := range files {
+		content.WriteString("\n## " + f.GetTitle() + "\n\n")
+
+		if link, _ := f.GetMetadata()["web_view_link"].(string); link != "" {
+			content.WriteString(fmt.Sprintf("[View in Drive](%s)\n\n", link))
+			links = append(links, models.Link{URL: link, Title: f.GetTitle(), Type: driveItemTypeDocument})
+		}
+
+		content.WriteString(strings.TrimSpace(f.GetContent()) + "\n")
+
+		for _, tag := range f.GetTags() {
+			if !slices.Contains(tags, tag) {
+				tags = append(tags, tag)
+			}
+		}
+
+		attachments = append(attachments, f.GetAttachments()...)
+		fileIDs = append(fileIDs, f.GetID())
+
+		if created.IsZero() || f.GetCreatedAt().Before(created) {
+			created = f.GetCreatedAt()
+		}
+
+		if f.GetUpdatedAt().After(updated) {
+			updated = f.GetUpdatedAt()
+		}
+	}
+
+	return &models.BasicItem{
+		ID:          "drive_folder_" + folderID,
+		Title:       name,
+		Content:     content.String(),
+		SourceType:  SourceTypeDrive,
+		ItemType:    driveItemTypeFolderBundle,
+		CreatedAt:   created,
+		UpdatedAt:   updated,
+		Tags:        tags,
+		Attachments: attachments,
+		Links:       links,
+		Metadata: map[string]interface{}{
+			"folder_id": folderID,
+			"file_ids":  fileIDs,
+			"files":     len(files),
+		},
+	}
+}
+
 // convertDriveFile converts a DriveFileInfo to a models.FullItem.
 func (g *GoogleSource) convertDriveFile(
 	file *drive.DriveFileInfo,
 	cfg models.DriveSourceConfig,
 ) (models.FullItem, error) {
+	if !drive.IsGoogleWorkspaceFile(file.MimeType) && !drive.IsGoogleAppsType(file.MimeType) {
+		return g.convertBinaryDriveFile(file, cfg)
+	}
+
 	// Determine export format based on file type
 	var format string
 
@@ -489,11 +911,22 @@ func (g *GoogleSource) convertDriveFile(
 
 	convertToMarkdown := (format == drive.FormatMD)
 
-	content, err := g.driveService.ExportAsString(file.ID, exportMimeType, convertToMarkdown, cfg.MaxFileSizeBytes)
+	var content string
+
+	if file.MimeType == drive.MimeTypeGoogleSheet && len(cfg.SheetTabs) > 0 {
+		content, err = g.driveService.ExportSheetTabs(file.ID, cfg.SheetTabs, format, cfg.MaxFileSizeBytes)
+	} else {
+		content, err = g.driveService.ExportAsString(file.ID, exportMimeType, convertToMarkdown, cfg.MaxFileSizeBytes)
+	}
+
 	if err != nil {
 		return nil, fmt.Errorf("failed to export file '%s': %w", file.Name, err)
 	}
 
+	if file.MimeType == drive.MimeTypeGoogleDoc && cfg.StripDocImages {
+		content = drive.StripImages(content)
+	}
+
 	// Map MIME type to item type
 	var itemType string
 
@@ -539,6 +972,237 @@ func (g *GoogleSource) convertDriveFile(
 	return item, nil
 }
 
+// driveFolderTag returns the "folder:<name>" tag for a configured folder,
+// resolving its name on first use. A folder whose name cannot be resolved is
+// tagged with its ID.
+func (g *GoogleSource) driveFolderTag(folderID string) string {
+	if name := g.driveFolderName(folderID); name != "" {
+		return "folder:" + strings.ToLower(strings.Join(strings.Fields(name), "-"))
+	}
+
+	return "folder:" + folderID
+}
+
+// driveFolderName returns the name of a Drive folder, or "" when it cannot be
+// resolved. Names are looked up once per source.
+func (g *GoogleSource) driveFolderName(folderID string) string {
+	if name, ok := g.driveFolderNames[folderID]; ok {
+		return name
+	}
+
+	name := ""
+
+	if meta, err := g.driveService.GetFileMetadata(folderID); err != nil {
+		slog.Warn("Failed to resolve Drive folder name; using its ID", "folder_id", folderID, "error", err)
+	} else if meta != nil {
+		name = meta.Name
+	}
+
+	if g.driveFolderNames == nil {
+		g.driveFolderNames = make(map[string]string)
+	}
+
+	g.driveFolderNames[folderID] = name
+
+	return name
+}
+
+// isPartialListing reports whether a Drive listing error still returned usable
+// files, in which case the sync proceeds with what was collected.
+func isPartialListing(err error) bool {
+	var partial *drive.PartialListError
+
+	return errors.As(err, &partial)
+}
+
+// wantDriveFile reports whether a listed file should be synced when binary files are
+// enabled: selected Workspace types, and regular files allowed by binary_file_types.
+// Other Google-native types (folders, Forms, shortcuts) have nothing to export.
+func wantDriveFile(file *drive.DriveFileInfo, workspaceMimeTypes []string, cfg models.DriveSourceConfig) bool {
+	if drive.IsGoogleAppsType(file.MimeType) {
+		for _, mt := range workspaceMimeTypes {
+			if file.MimeType == mt {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	return isAllowedBinaryType(file, cfg.BinaryFileTypes)
+}
+
+// isAllowedBinaryType matches the file extension (or, without one, the extension
+// registered for its MIME type) against allowed. An empty list allows everything.
+func isAllowedBinaryType(file *drive.DriveFileInfo, allowed []string) bool {
+	if len(allowed) == 0 {
+		return true
+	}
+
+	ext := strings.TrimPrefix(strings.ToLower(path.Ext(file.Name)), ".")
+	if ext == "" {
+		if exts, err := mime.ExtensionsByType(file.MimeType); err == nil && len(exts) > 0 {
+			ext = strings.TrimPrefix(exts[0], ".")
+		}
+	}
+
+	for _, a := range allowed {
+		if ext != "" && strings.EqualFold(strings.TrimPrefix(a, "."), ext) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// convertBinaryDriveFile downloads a regular (non-Workspace) file and returns a stub
+// note carrying it as an attachment, the same base64 form Gmail attachments use.
+func (g *GoogleSource) convertBinaryDriveFile(
+	file *drive.DriveFileInfo,
+	cfg models.DriveSourceConfig,
+) (models.FullItem, error) {
+	if !cfg.IncludeBinaryFiles {
+		return nil, fmt.Errorf("binary file '%s' (%s) skipped: include_binary_files is disabled",
+			file.Name, file.MimeType)
+	}
+
+	if !isAllowedBinaryType(file, cfg.BinaryFileTypes) {
+		return nil, fmt.Errorf("binary file '%s' (%s) not allowed by binary_file_types", file.Name, file.MimeType)
+	}
+
+	data, err := g.driveService.DownloadAsBytes(file.ID, cfg.MaxFileSizeBytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download file '%s': %w", file.Name, err)
+	}
+
+	mimeType := file.MimeType
+	if mimeType == "" {
+		mimeType = http.DetectContentType(data)
+	}
+
+	metadata := map[string]interface{}{
+		"mime_type":     mimeType,
+		"web_view_link": file.WebViewLink,
+		"owners":        file.Owners,
+		"starred":       file.Starred,
+		"size":          int64(len(data)),
+	}
+
+	var links []models.Link
+
+	if file.WebViewLink != "" {
+		links = append(links, models.Link{
+			URL:   file.WebViewLink,
+			Title: "View in Drive",
+			Type:  driveItemTypeDocument,
+		})
+	}
+
+	content := fmt.Sprintf("%s (%s, %d bytes) is stored in Google Drive and attached to this note.",
+		file.Name, mimeType, len(data))
+	if file.Description != "" {
+		content = file.Description + "\n\n" + content
+	}
+
+	item := &models.BasicItem{
+		ID:         file.ID,
+		Title:      file.Name,
+		Content:    content,
+		SourceType: SourceTypeDrive,
+		ItemType:   driveItemTypeFile,
+		CreatedAt:  file.CreatedTime,
+		UpdatedAt:  file.ModifiedTime,
+		Tags:       []string{},
+		Metadata:   metadata,
+		Links:      links,
+		Attachments: []models.Attachment{{
+			ID:       file.ID,
+			Name:     file.Name,
+			MimeType: mimeType,
+			URL:      file.WebViewLink,
+			Data:     base64.StdEncoding.EncodeToString(data),
+			Size:     int64(len(data)),
+		}},
+	}
+
+	return item, nil
+}
+
+// FetchOne implements interfaces.Fetcher. key is a Gmail thread ID (when
+// include_threads is set) or message ID, or a Drive file ID. Calendar sources
+// do not support single-item fetch.
+func (g *GoogleSource) FetchOne(ctx context.Context, key string) (models.FullItem, error) {
+	if g.transport != nil {
+		g.transport.Bind(ctx)
+		defer g.transport.Bind(nil)
+	}
+
+	switch g.config.Type {
+	case SourceTypeGmail:
+		return g.fetchOneGmail(key)
+	case SourceTypeDrive:
+		return g.fetchOneDrive(key)
+	default:
+		return nil, fmt.Errorf("source type %q does not support single-item fetch", g.config.Type)
+	}
+}
+
+// fetchOneGmail fetches one thread or message, matching how Fetch would convert it.
+func (g *GoogleSource) fetchOneGmail(id string) (models.FullItem, error) {
+	if g.gmailService == nil {
+		return nil, fmt.Errorf("gmail service not initialized")
+	}
+
+	if g.config.Gmail.IncludeThreads {
+		thread, err := g.gmailService.GetThread(id)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch Gmail thread %s: %w", id, err)
+		}
+
+		legacyItem, err := gmail.FromGmailThread(thread, g.config.Gmail, g.gmailService)
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert Gmail thread to item: %w", err)
+		}
+
+		return models.AsFullItem(legacyItem), nil
+	}
+
+	message, err := g.gmailService.GetMessage(id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch Gmail message %s: %w", id, err)
+	}
+
+	legacyItem, err := gmail.FromGmailMessageWithService(message, g.config.Gmail, g.gmailService)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert Gmail message to item: %w", err)
+	}
+
+	return models.AsFullItem(legacyItem), nil
+}
+
+// fetchOneDrive exports one Drive file using the source's export settings.
+func (g *GoogleSource) fetchOneDrive(fileID string) (models.FullItem, error) {
+	if g.driveService == nil {
+		return nil, fmt.Errorf("drive service not initialized")
+	}
+
+	meta, err := g.driveService.GetFileMetadata(fileID)
+	if err != nil {
+		return nil, err
+	}
+
+	file := &drive.DriveFileInfo{
+		ID:           meta.ID,
+		Name:         meta.Name,
+		MimeType:     meta.MimeType,
+		WebViewLink:  meta.WebViewLink,
+		ModifiedTime: meta.ModifiedTime,
+		Owners:       meta.Owners,
+	}
+
+	return g.convertDriveFile(file, g.config.Drive)
+}
+
 // GetGmailService returns the Gmail service for use by external sinks (e.g. ArchiveSink).
 // Returns nil if this source is not a Gmail source or has not been configured.
 func (g *GoogleSource) GetGmailService() *gmail.Service {
@@ -547,3 +1211,15 @@ func (g *GoogleSource) GetGmailService() *gmail.Service {
 
 // Ensure GoogleSource implements Source interface.
 var _ interfaces.Source = (*GoogleSource)(nil)
+
+// Ensure GoogleSource supports context cancellation.
+var _ interfaces.ContextSource = (*GoogleSource)(nil)
+
+// Ensure GoogleSource supports single-item fetch.
+var _ interfaces.Fetcher = (*GoogleSource)(nil)
+
+// Ensure GoogleSource can hand raw Gmail messages to the archive sink.
+var _ interfaces.RawMessageSource = (*GoogleSource)(nil)
+
+// Ensure GoogleSource can verify its credentials for the doctor command.
+var _ interfaces.AuthChecker = (*GoogleSource)(nil)
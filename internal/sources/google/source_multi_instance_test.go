@@ -4,6 +4,8 @@ import (
 	"testing"
 	"time"
 
+	"pkm-sync/internal/sources/google/gmail"
+	"pkm-sync/pkg/interfaces"
 	"pkm-sync/pkg/models"
 
 	"github.com/stretchr/testify/assert"
@@ -157,6 +159,49 @@ func TestGoogleSourceSupportsRealtime(t *testing.T) {
 	assert.False(t, source.SupportsRealtime())
 }
 
+func TestGoogleSourceCapabilities(t *testing.T) {
+	tests := []struct {
+		sourceType string
+		expected   interfaces.SourceCapabilities
+	}{
+		{
+			sourceType: SourceTypeGmail,
+			expected: interfaces.SourceCapabilities{
+				ProvidesRawMessages: true,
+				SupportsIncremental: true,
+				ProducesThreads:     true,
+			},
+		},
+		{
+			sourceType: SourceTypeCalendar,
+			expected:   interfaces.SourceCapabilities{SupportsIncremental: true},
+		},
+		{
+			sourceType: SourceTypeDrive,
+			expected:   interfaces.SourceCapabilities{SupportsIncremental: true},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.sourceType, func(t *testing.T) {
+			source := NewGoogleSourceWithConfig("test", models.SourceConfig{Type: tt.sourceType})
+			assert.Equal(t, tt.expected, source.Capabilities())
+		})
+	}
+}
+
+func TestGoogleSourceRawMessageFetcher(t *testing.T) {
+	gmailSource := NewGoogleSourceWithConfig("mail", models.SourceConfig{Type: SourceTypeGmail})
+	assert.Nil(t, gmailSource.RawMessageFetcher(), "no fetcher before Configure")
+
+	gmailSource.gmailService = &gmail.Service{}
+	assert.NotNil(t, gmailSource.RawMessageFetcher())
+
+	driveSource := NewGoogleSourceWithConfig("docs", models.SourceConfig{Type: SourceTypeDrive})
+	driveSource.gmailService = &gmail.Service{}
+	assert.Nil(t, driveSource.RawMessageFetcher(), "only Gmail sources provide raw messages")
+}
+
 func TestMultipleGmailInstances(t *testing.T) {
 	// Test that we can create multiple Gmail source instances with different configs
 	workConfig := models.SourceConfig{
@@ -1,14 +1,24 @@
 package google
 
 import (
+	"encoding/base64"
+	"encoding/json"
 	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
 	"runtime"
+	"slices"
+	"strings"
 	"sync/atomic"
 	"testing"
 	"time"
 
 	"pkm-sync/internal/sources/google/drive"
+	"pkm-sync/internal/sources/google/gmail"
 	"pkm-sync/pkg/models"
+
+	gmailapi "google.golang.org/api/gmail/v1"
 )
 
 // mockDriveExporter is a test double for driveExporter.
@@ -19,7 +29,25 @@ type mockDriveExporter struct {
 	sharedErr       error
 	exportContent   string
 	exportErr       error
+	metadata        *models.DriveFile
+	metadataErr     error
 	configureCalled bool
+	downloadData    []byte
+	downloadErr     error
+	lastListOpts    drive.ListFilesOptions
+	// lastSheetTabs records the tab selectors passed to ExportSheetTabs.
+	lastSheetTabs []string
+
+	// folderFiles, when set, lists files per folder ID instead of listFiles;
+	// folderNames answers GetFileMetadata for those folders.
+	folderFiles   map[string][]*drive.DriveFileInfo
+	folderNames   map[string]string
+	metadataCalls atomic.Int64
+
+	// exportCalls and downloadCalls tell the Workspace export path from the
+	// binary download path.
+	exportCalls   atomic.Int64
+	downloadCalls atomic.Int64
 
 	// lastMaxBytes is written concurrently by parallel export goroutines;
 	// use atomic to avoid a data race under -race.
@@ -41,11 +69,25 @@ func (m *mockDriveExporter) Configure(_ models.DriveSourceConfig) {
 	m.configureCalled = true
 }
 
-func (m *mockDriveExporter) ListFilesInFolder(_ string, _ time.Time, _ bool, _ drive.ListFilesOptions) ([]*drive.DriveFileInfo, error) {
+func (m *mockDriveExporter) ListFilesInFolder(folderID string, _ time.Time, _ bool, opts drive.ListFilesOptions) ([]*drive.DriveFileInfo, error) {
+	m.lastListOpts = opts
+
+	if m.folderFiles != nil {
+		return m.folderFiles[folderID], m.listErr
+	}
+
 	return m.listFiles, m.listErr
 }
 
+func (m *mockDriveExporter) DownloadAsBytes(_ string, maxBytes int64) ([]byte, error) {
+	m.downloadCalls.Add(1)
+	m.lastMaxBytes.Store(maxBytes)
+
+	return m.downloadData, m.downloadErr
+}
+
 func (m *mockDriveExporter) ExportAsString(_ string, _ string, _ bool, maxBytes int64) (string, error) {
+	m.exportCalls.Add(1)
 	m.lastMaxBytes.Store(maxBytes)
 
 	current := m.inFlight.Add(1)
@@ -76,10 +118,27 @@ func (m *mockDriveExporter) ExportAsString(_ string, _ string, _ bool, maxBytes
 	return m.exportContent, m.exportErr
 }
 
+func (m *mockDriveExporter) ExportSheetTabs(_ string, selectors []string, _ string, _ int64) (string, error) {
+	m.exportCalls.Add(1)
+	m.lastSheetTabs = selectors
+
+	return m.exportContent, m.exportErr
+}
+
 func (m *mockDriveExporter) ListSharedWithMe(_ time.Time, _ drive.ListFilesOptions) ([]*drive.DriveFileInfo, error) {
 	return m.sharedFiles, m.sharedErr
 }
 
+func (m *mockDriveExporter) GetFileMetadata(fileID string) (*models.DriveFile, error) {
+	m.metadataCalls.Add(1)
+
+	if name, ok := m.folderNames[fileID]; ok {
+		return &models.DriveFile{ID: fileID, Name: name}, nil
+	}
+
+	return m.metadata, m.metadataErr
+}
+
 // newTestGoogleDriveSource creates a GoogleSource wired for Drive with the given mock.
 func newTestGoogleDriveSource(mock driveExporter, driveCfg models.DriveSourceConfig) *GoogleSource {
 	return &GoogleSource{
@@ -276,6 +335,44 @@ func TestConvertDriveFile_MaxBytesForwarded(t *testing.T) {
 	}
 }
 
+func TestConvertDriveFile_SheetTabs(t *testing.T) {
+	mock := &mockDriveExporter{exportContent: "## Summary\n\nTotal,42"}
+	cfg := models.DriveSourceConfig{SheetTabs: []string{"Summary"}}
+	src := newTestGoogleDriveSource(mock, cfg)
+
+	file := &drive.DriveFileInfo{ID: "sheet1", Name: "Budget", MimeType: drive.MimeTypeGoogleSheet}
+
+	item, err := src.convertDriveFile(file, cfg)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !slices.Equal(mock.lastSheetTabs, []string{"Summary"}) {
+		t.Errorf("ExportSheetTabs received tabs %v, want [Summary]", mock.lastSheetTabs)
+	}
+
+	if item.GetContent() != mock.exportContent {
+		t.Errorf("Content = %q, want %q", item.GetContent(), mock.exportContent)
+	}
+}
+
+func TestConvertDriveFile_StripDocImages(t *testing.T) {
+	mock := &mockDriveExporter{exportContent: "# Plan\n\n![diagram](https://lh7.googleusercontent.com/d)\n\nShip it."}
+	cfg := models.DriveSourceConfig{StripDocImages: true}
+	src := newTestGoogleDriveSource(mock, cfg)
+
+	file := &drive.DriveFileInfo{ID: "doc6", Name: "Plan", MimeType: drive.MimeTypeGoogleDoc}
+
+	item, err := src.convertDriveFile(file, cfg)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if want := "# Plan\n\n\n\nShip it."; item.GetContent() != want {
+		t.Errorf("Content = %q, want %q", item.GetContent(), want)
+	}
+}
+
 // ---- fetchDrive tests ----
 
 func TestFetchDrive_NotInitialized(t *testing.T) {
@@ -287,6 +384,203 @@ func TestFetchDrive_NotInitialized(t *testing.T) {
 	}
 }
 
+func TestConvertDriveFile_WorkspaceExportsWithoutDownload(t *testing.T) {
+	mock := &mockDriveExporter{exportContent: "# Notes"}
+	cfg := models.DriveSourceConfig{IncludeBinaryFiles: true}
+	src := newTestGoogleDriveSource(mock, cfg)
+
+	item, err := src.convertDriveFile(&drive.DriveFileInfo{ID: "doc1", Name: "Notes", MimeType: drive.MimeTypeGoogleDoc}, cfg)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if mock.exportCalls.Load() != 1 || mock.downloadCalls.Load() != 0 {
+		t.Errorf("expected export only, got %d exports and %d downloads", mock.exportCalls.Load(), mock.downloadCalls.Load())
+	}
+
+	if item.GetContent() != "# Notes" || len(item.GetAttachments()) != 0 {
+		t.Errorf("unexpected Workspace item: content %q, %d attachments", item.GetContent(), len(item.GetAttachments()))
+	}
+}
+
+func TestConvertDriveFile_BinaryDownloadsAsAttachment(t *testing.T) {
+	pdf := []byte("%PDF-1.4 fake")
+	mock := &mockDriveExporter{downloadData: pdf}
+	cfg := models.DriveSourceConfig{IncludeBinaryFiles: true, MaxFileSizeBytes: 1024}
+	src := newTestGoogleDriveSource(mock, cfg)
+
+	file := &drive.DriveFileInfo{
+		ID:          "pdf1",
+		Name:        "report.pdf",
+		MimeType:    "application/pdf",
+		WebViewLink: "https://drive.google.com/file/d/pdf1/view",
+	}
+
+	item, err := src.convertDriveFile(file, cfg)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if mock.exportCalls.Load() != 0 || mock.downloadCalls.Load() != 1 {
+		t.Errorf("expected download only, got %d exports and %d downloads", mock.exportCalls.Load(), mock.downloadCalls.Load())
+	}
+
+	if mock.lastMaxBytes.Load() != 1024 {
+		t.Errorf("expected size limit 1024 forwarded to download, got %d", mock.lastMaxBytes.Load())
+	}
+
+	if item.GetItemType() != driveItemTypeFile || item.GetTitle() != "report.pdf" {
+		t.Errorf("unexpected stub note: type %q, title %q", item.GetItemType(), item.GetTitle())
+	}
+
+	attachments := item.GetAttachments()
+	if len(attachments) != 1 {
+		t.Fatalf("expected 1 attachment, got %d", len(attachments))
+	}
+
+	att := attachments[0]
+	if att.Name != "report.pdf" || att.MimeType != "application/pdf" || att.Size != int64(len(pdf)) {
+		t.Errorf("unexpected attachment: %+v", att)
+	}
+
+	if att.Data != base64.StdEncoding.EncodeToString(pdf) {
+		t.Errorf("attachment data not the downloaded bytes")
+	}
+}
+
+func TestConvertDriveFile_BinaryTypePolicy(t *testing.T) {
+	mock := &mockDriveExporter{downloadData: []byte("x")}
+	cfg := models.DriveSourceConfig{IncludeBinaryFiles: true, BinaryFileTypes: []string{"pdf"}}
+	src := newTestGoogleDriveSource(mock, cfg)
+
+	if _, err := src.convertDriveFile(&drive.DriveFileInfo{ID: "img", Name: "photo.png", MimeType: "image/png"}, cfg); err == nil {
+		t.Error("expected png to be rejected by binary_file_types")
+	}
+
+	if mock.downloadCalls.Load() != 0 {
+		t.Errorf("rejected file should not be downloaded, got %d downloads", mock.downloadCalls.Load())
+	}
+}
+
+func TestFetchDrive_BinaryFilesExcludedByDefault(t *testing.T) {
+	files := []*drive.DriveFileInfo{{ID: "a", Name: "Doc A", MimeType: drive.MimeTypeGoogleDoc}}
+	mock := &mockDriveExporter{listFiles: files, exportContent: "content"}
+	src := newTestGoogleDriveSource(mock, models.DriveSourceConfig{})
+
+	if _, err := src.fetchDrive(time.Now(), 0); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(mock.lastListOpts.MimeTypes) != 3 {
+		t.Errorf("expected listing restricted to Workspace types, got %v", mock.lastListOpts.MimeTypes)
+	}
+}
+
+func TestFetchDrive_IncludeBinaryFiles(t *testing.T) {
+	files := []*drive.DriveFileInfo{
+		{ID: "doc", Name: "Doc", MimeType: drive.MimeTypeGoogleDoc},
+		{ID: "pdf", Name: "scan.pdf", MimeType: "application/pdf"},
+		{ID: "zip", Name: "bundle.zip", MimeType: "application/zip"},
+		{ID: "form", Name: "Survey", MimeType: "application/vnd.google-apps.form"},
+		{ID: "sheet", Name: "Budget", MimeType: drive.MimeTypeGoogleSheet},
+	}
+
+	mock := &mockDriveExporter{listFiles: files, exportContent: "content", downloadData: []byte("bytes")}
+	cfg := models.DriveSourceConfig{
+		IncludeBinaryFiles: true,
+		BinaryFileTypes:    []string{"pdf"},
+		WorkspaceTypes:     []string{driveItemTypeDocument},
+	}
+	src := newTestGoogleDriveSource(mock, cfg)
+
+	items, err := src.fetchDrive(time.Now(), 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(mock.lastListOpts.MimeTypes) != 0 {
+		t.Errorf("expected unfiltered listing with binaries enabled, got %v", mock.lastListOpts.MimeTypes)
+	}
+
+	got := make(map[string]string)
+	for _, item := range items {
+		got[item.GetID()] = item.GetItemType()
+	}
+
+	want := map[string]string{"doc": driveItemTypeDocument, "pdf": driveItemTypeFile}
+	if len(got) != len(want) || got["doc"] != want["doc"] || got["pdf"] != want["pdf"] {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+
+	if mock.exportCalls.Load() != 1 || mock.downloadCalls.Load() != 1 {
+		t.Errorf("expected 1 export and 1 download, got %d and %d", mock.exportCalls.Load(), mock.downloadCalls.Load())
+	}
+}
+
+func TestFetchDrive_BundleFolders(t *testing.T) {
+	now := time.Now()
+	doc := func(id, name string, modified time.Time) *drive.DriveFileInfo {
+		return &drive.DriveFileInfo{
+			ID: id, Name: name, MimeType: drive.MimeTypeGoogleDoc,
+			WebViewLink: "https://docs.google.com/document/d/" + id, ModifiedTime: modified,
+		}
+	}
+
+	mock := &mockDriveExporter{
+		exportContent: "Section body.",
+		folderFiles: map[string][]*drive.DriveFileInfo{
+			"proj": {
+				doc("c", "Roadmap", now.Add(-30*24*time.Hour)),
+				doc("a", "Design Notes", now.Add(-time.Hour)),
+				doc("b", "Meeting Log", now.Add(-30*24*time.Hour)),
+			},
+			"old": {doc("d", "Archive", now.Add(-60*24*time.Hour))},
+		},
+		folderNames: map[string]string{"proj": "Project X", "old": "Old Stuff"},
+	}
+	cfg := models.DriveSourceConfig{FolderIDs: []string{"proj", "old"}, BundleFolders: true}
+	src := newTestGoogleDriveSource(mock, cfg)
+
+	items, err := src.fetchDrive(now.Add(-24*time.Hour), 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(items) != 1 {
+		t.Fatalf("expected one bundle for the changed folder only, got %d items", len(items))
+	}
+
+	bundle := items[0]
+	if bundle.GetID() != "drive_folder_proj" || bundle.GetTitle() != "Project X" ||
+		bundle.GetItemType() != driveItemTypeFolderBundle {
+		t.Errorf("unexpected bundle identity: %s %q %s", bundle.GetID(), bundle.GetTitle(), bundle.GetItemType())
+	}
+
+	content := bundle.GetContent()
+	for _, want := range []string{
+		"## Contents\n\n- [Design Notes](#Design%20Notes)\n- [Meeting Log](#Meeting%20Log)\n- [Roadmap](#Roadmap)\n",
+		"## Design Notes\n\n[View in Drive](https://docs.google.com/document/d/a)\n\nSection body.\n",
+		"## Meeting Log\n\n",
+		"## Roadmap\n\n",
+	} {
+		if !strings.Contains(content, want) {
+			t.Errorf("bundle content missing %q:\n%s", want, content)
+		}
+	}
+
+	if got := len(bundle.GetLinks()); got != 3 {
+		t.Errorf("expected 3 links, got %d", got)
+	}
+
+	if !slices.Contains(bundle.GetTags(), "folder:project-x") {
+		t.Errorf("expected the folder tag, got %v", bundle.GetTags())
+	}
+
+	if !bundle.GetUpdatedAt().Equal(now.Add(-time.Hour)) {
+		t.Errorf("UpdatedAt = %v, want the latest file's", bundle.GetUpdatedAt())
+	}
+}
+
 func TestFetchDrive_AllSucceed(t *testing.T) {
 	files := []*drive.DriveFileInfo{
 		{ID: "a", Name: "Doc A", MimeType: drive.MimeTypeGoogleDoc},
@@ -417,6 +711,26 @@ func TestFetchDrive_ListError(t *testing.T) {
 	}
 }
 
+func TestFetchDrive_PartialListingKeepsCollectedFiles(t *testing.T) {
+	mock := &mockDriveExporter{
+		listFiles: []*drive.DriveFileInfo{
+			{ID: "a", Name: "Doc A", MimeType: drive.MimeTypeGoogleDoc},
+		},
+		listErr:       &drive.PartialListError{Files: 1, Pages: 1, PageToken: "p2", Err: errors.New("503")},
+		exportContent: "content",
+	}
+	src := newTestGoogleDriveSource(mock, models.DriveSourceConfig{})
+
+	items, err := src.fetchDrive(time.Now(), 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(items) != 1 || items[0].GetID() != "a" {
+		t.Errorf("expected the one listed file, got %d items", len(items))
+	}
+}
+
 // TestFetchDrive_ParallelExports verifies that MaxConcurrentExports controls real
 // concurrency. Uses a channel barrier so goroutines are held until maxConcurrent
 // are provably in-flight simultaneously, making the assertion deterministic on
@@ -550,3 +864,263 @@ func TestFetchDrive_SharedWithMe(t *testing.T) {
 
 // Ensure mockDriveExporter satisfies driveExporter (compile-time check).
 var _ driveExporter = (*mockDriveExporter)(nil)
+
+// ---- FetchOne tests ----
+
+func TestFetchOne_DriveExportsSingleFile(t *testing.T) {
+	mock := &mockDriveExporter{
+		exportContent: "# Notes",
+		metadata: &models.DriveFile{
+			ID:       "doc1",
+			Name:     "Team Notes",
+			MimeType: drive.MimeTypeGoogleDoc,
+		},
+	}
+	src := newTestGoogleDriveSource(mock, models.DriveSourceConfig{})
+
+	item, err := src.FetchOne(t.Context(), "doc1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if item.GetID() != "doc1" || item.GetTitle() != "Team Notes" {
+		t.Errorf("got item %q/%q, want doc1/Team Notes", item.GetID(), item.GetTitle())
+	}
+
+	if item.GetContent() != "# Notes" {
+		t.Errorf("Content = %q, want %q", item.GetContent(), "# Notes")
+	}
+}
+
+func TestFetchOne_DriveMetadataError(t *testing.T) {
+	mock := &mockDriveExporter{metadataErr: errors.New("not found")}
+	src := newTestGoogleDriveSource(mock, models.DriveSourceConfig{})
+
+	if _, err := src.FetchOne(t.Context(), "missing"); err == nil {
+		t.Fatal("expected error for missing file, got nil")
+	}
+}
+
+func TestFetchOne_CalendarUnsupported(t *testing.T) {
+	src := NewGoogleSourceWithConfig("cal", models.SourceConfig{Type: SourceTypeCalendar})
+
+	if _, err := src.FetchOne(t.Context(), "event1"); err == nil {
+		t.Fatal("expected error for calendar source, got nil")
+	}
+}
+
+// ---- folder origin tagging ----
+
+func TestFetchDrive_TagsItemsWithOriginFolder(t *testing.T) {
+	doc := func(id string) *drive.DriveFileInfo {
+		return &drive.DriveFileInfo{ID: id, Name: id, MimeType: drive.MimeTypeGoogleDoc}
+	}
+
+	mock := &mockDriveExporter{
+		exportContent: "# Doc",
+		folderFiles: map[string][]*drive.DriveFileInfo{
+			"folder-a": {doc("a1"), doc("shared")},
+			"folder-b": {doc("b1"), doc("shared")},
+		},
+		folderNames: map[string]string{"folder-a": "Team Specs", "folder-b": "Meeting Notes"},
+	}
+	src := newTestGoogleDriveSource(mock, models.DriveSourceConfig{FolderIDs: []string{"folder-a", "folder-b"}})
+
+	items, err := src.fetchDrive(time.Time{}, 0)
+	if err != nil {
+		t.Fatalf("fetchDrive: %v", err)
+	}
+
+	tags := make(map[string][]string)
+	for _, item := range items {
+		tags[item.GetID()] = item.GetTags()
+	}
+
+	want := map[string][]string{
+		"a1":     {"folder:team-specs"},
+		"b1":     {"folder:meeting-notes"},
+		"shared": {"folder:team-specs", "folder:meeting-notes"},
+	}
+
+	for id, wantTags := range want {
+		if got := tags[id]; !slices.Equal(got, wantTags) {
+			t.Errorf("item %s tags = %v, want %v", id, got, wantTags)
+		}
+	}
+
+	// Folder names are resolved once per source, not once per fetch.
+	if _, err := src.fetchDrive(time.Time{}, 0); err != nil {
+		t.Fatalf("second fetchDrive: %v", err)
+	}
+
+	if calls := mock.metadataCalls.Load(); calls != 2 {
+		t.Errorf("expected 2 folder name lookups across two fetches, got %d", calls)
+	}
+}
+
+func TestFetchDrive_NoFolderTagWithoutConfiguredFolders(t *testing.T) {
+	mock := &mockDriveExporter{
+		exportContent: "# Doc",
+		listFiles:     []*drive.DriveFileInfo{{ID: "r1", Name: "r1", MimeType: drive.MimeTypeGoogleDoc}},
+	}
+	src := newTestGoogleDriveSource(mock, models.DriveSourceConfig{})
+
+	items, err := src.fetchDrive(time.Time{}, 0)
+	if err != nil {
+		t.Fatalf("fetchDrive: %v", err)
+	}
+
+	if len(items) != 1 || len(items[0].GetTags()) != 0 {
+		t.Errorf("expected one untagged item from the implicit root, got %v", items)
+	}
+
+	if calls := mock.metadataCalls.Load(); calls != 0 {
+		t.Errorf("root folder should not be resolved, got %d lookups", calls)
+	}
+}
+
+func TestFetchDrive_UnresolvedFolderTaggedWithID(t *testing.T) {
+	mock := &mockDriveExporter{
+		exportContent: "# Doc",
+		metadataErr:   errors.New("forbidden"),
+		folderFiles: map[string][]*drive.DriveFileInfo{
+			"1AbC": {{ID: "x", Name: "x", MimeType: drive.MimeTypeGoogleDoc}},
+		},
+	}
+	src := newTestGoogleDriveSource(mock, models.DriveSourceConfig{FolderIDs: []string{"1AbC"}})
+
+	items, err := src.fetchDrive(time.Time{}, 0)
+	if err != nil {
+		t.Fatalf("fetchDrive: %v", err)
+	}
+
+	if len(items) != 1 || !slices.Equal(items[0].GetTags(), []string{"folder:1AbC"}) {
+		t.Errorf("expected folder ID tag, got %v", items[0].GetTags())
+	}
+}
+
+// rewriteTransport sends every request to server, whatever its host.
+type rewriteTransport struct {
+	server *httptest.Server
+}
+
+func (rt rewriteTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	target, _ := url.Parse(rt.server.URL)
+	r = r.Clone(r.Context())
+	r.URL.Scheme, r.URL.Host = target.Scheme, target.Host
+
+	return rt.server.Client().Transport.RoundTrip(r)
+}
+
+// newGmailTestSource returns a Gmail source whose API answers with an empty
+// date query at history ID 200 and a history delta of m1, m2 and m3 (records 210-230) reaching
+// history ID 300, counting the profile reads in profileCalls.
+func newGmailTestSource(t *testing.T, profileCalls *atomic.Int64) *GoogleSource {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body any
+
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/profile"):
+			profileCalls.Add(1)
+
+			body = &gmailapi.Profile{HistoryId: 200}
+		case strings.HasSuffix(r.URL.Path, "/history"):
+			body = &gmailapi.ListHistoryResponse{
+				HistoryId: 300,
+				History: []*gmailapi.History{
+					{Id: 210, MessagesAdded: []*gmailapi.HistoryMessageAdded{{Message: &gmailapi.Message{Id: "m1"}}}},
+					{Id: 220, MessagesAdded: []*gmailapi.HistoryMessageAdded{{Message: &gmailapi.Message{Id: "m2"}}}},
+					{Id: 230, MessagesAdded: []*gmailapi.HistoryMessageAdded{{Message: &gmailapi.Message{Id: "m3"}}}},
+				},
+			}
+		case strings.Contains(r.URL.Path, "/messages/"):
+			body = &gmailapi.Message{Id: r.URL.Path[strings.LastIndex(r.URL.Path, "/")+1:], LabelIds: []string{"INBOX"}}
+		default:
+			body = &gmailapi.ListMessagesResponse{}
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(body)
+	}))
+	t.Cleanup(server.Close)
+
+	svc, err := gmail.NewService(&http.Client{Transport: rewriteTransport{server: server}},
+		models.GmailSourceConfig{}, "gmail_test")
+	if err != nil {
+		t.Fatalf("NewService: %v", err)
+	}
+
+	return &GoogleSource{sourceID: "gmail_test", gmailService: svc}
+}
+
+func TestGmailMessages_RecordsHistoryID(t *testing.T) {
+	var profileCalls atomic.Int64
+
+	src := newGmailTestSource(t, &profileCalls)
+
+	if _, err := src.gmailMessages(time.Now().AddDate(0, 0, -7), 10); err != nil {
+		t.Fatalf("gmailMessages: %v", err)
+	}
+
+	if got := src.HistoryID(); got != "200" {
+		t.Errorf("HistoryID = %q, want 200", got)
+	}
+}
+
+func TestGmailMessages_UntilKeepsHistoryCursor(t *testing.T) {
+	var profileCalls atomic.Int64
+
+	// A 2023 backfill after an incremental sync must not move the cursor to
+	// now, or the mail since the last sync would never be fetched.
+	src := newGmailTestSource(t, &profileCalls)
+	src.SetStartHistoryID("100")
+	src.SetUntil(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+
+	if _, err := src.gmailMessages(time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC), 10); err != nil {
+		t.Fatalf("gmailMessages: %v", err)
+	}
+
+	if n := profileCalls.Load(); n != 0 {
+		t.Errorf("profile read %d time(s), want none for a backfill window", n)
+	}
+
+	if got := src.HistoryID(); got != "" {
+		t.Errorf("HistoryID = %q, want none for a backfill window", got)
+	}
+}
+
+func TestGmailMessages_CutHistoryDeltaAdvancesCursor(t *testing.T) {
+	tests := []struct {
+		name  string
+		limit int
+		want  string
+	}{
+		{name: "whole delta", limit: 10, want: "300"},
+		// The two oldest changes are synced; the next run starts after them.
+		{name: "cut to the limit", limit: 2, want: "220"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var profileCalls atomic.Int64
+
+			src := newGmailTestSource(t, &profileCalls)
+			src.SetStartHistoryID("100")
+
+			messages, err := src.gmailMessages(time.Now().AddDate(0, 0, -7), tt.limit)
+			if err != nil {
+				t.Fatalf("gmailMessages: %v", err)
+			}
+
+			if want := min(3, tt.limit); len(messages) != want {
+				t.Errorf("got %d messages, want %d", len(messages), want)
+			}
+
+			if got := src.HistoryID(); got != tt.want {
+				t.Errorf("HistoryID = %q, want %s", got, tt.want)
+			}
+		})
+	}
+}
@@ -15,6 +15,9 @@ import (
 	"pkm-sync/pkg/models"
 )
 
+// linkTypeIssueLink is the Link type of an issue's linked issues.
+const linkTypeIssueLink = "issue_link"
+
 // compileExcludePatterns compiles regex patterns, logging warnings for invalid ones.
 func compileExcludePatterns(patterns []string) []*regexp.Regexp {
 	compiled := make([]*regexp.Regexp, 0, len(patterns))
@@ -203,18 +206,45 @@ func issueToItem(issue *jiraclient.Issue, serverURL string, cfg models.JiraSourc
 
 	item.Metadata = meta
 
-	// Set source URL.
+	// Set source URL, then the linked issues titled by relationship
+	// ("blocks PROJ-9").
 	if serverURL != "" {
 		item.Links = append(item.Links, models.Link{
 			URL:   serverURL + "/browse/" + issue.Key,
 			Title: issue.Key,
 			Type:  "external",
 		})
+
+		item.Links = append(item.Links, issueLinkLinks(issue, serverURL)...)
 	}
 
 	return item
 }
 
+// issueLinkLinks returns a browse link per linked issue, in link order.
+func issueLinkLinks(issue *jiraclient.Issue, serverURL string) []models.Link {
+	var links []models.Link
+
+	for _, link := range issue.Fields.IssueLinks {
+		rel, other := link.LinkType.Outward, link.OutwardIssue
+		if other == nil {
+			rel, other = link.LinkType.Inward, link.InwardIssue
+		}
+
+		if other == nil || other.Key == "" {
+			continue
+		}
+
+		links = append(links, models.Link{
+			URL:   serverURL + "/browse/" + other.Key,
+			Title: strings.TrimSpace(rel + " " + other.Key),
+			Type:  linkTypeIssueLink,
+		})
+	}
+
+	return links
+}
+
 // collectContributors extracts unique contributor names from an issue's
 // assignee, reporter, and comment authors.
 //
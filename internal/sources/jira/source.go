@@ -10,6 +10,8 @@ import (
 
 	jiraclient "github.com/ankitpokhrel/jira-cli/pkg/jira"
 
+	"pkm-sync/internal/httpclient"
+	"pkm-sync/pkg/interfaces"
 	"pkm-sync/pkg/models"
 )
 
@@ -21,6 +23,8 @@ type JiraSource struct {
 	serverURL    string
 	currentUser  string
 	installation string // "Cloud" or "Local"
+
+	requestTimeout time.Duration
 }
 
 // isCloud returns true when the Jira instance uses the v3 Cloud API.
@@ -32,8 +36,9 @@ func (s *JiraSource) isCloud() bool {
 // NewJiraSource creates a new JiraSource from a SourceConfig.
 func NewJiraSource(sourceID string, sourceCfg models.SourceConfig) *JiraSource {
 	return &JiraSource{
-		sourceID: sourceID,
-		cfg:      sourceCfg.Jira,
+		sourceID:       sourceID,
+		cfg:            sourceCfg.Jira,
+		requestTimeout: httpclient.ResolveTimeout(sourceCfg.RequestTimeout, 0),
 	}
 }
 
@@ -99,26 +104,60 @@ func (s *JiraSource) Configure(_ map[string]any, _ *http.Client) error {
 	return nil
 }
 
+// CheckAuth implements interfaces.AuthChecker by resolving the current user.
+func (s *JiraSource) CheckAuth() (string, error) {
+	if s.client == nil {
+		return "", fmt.Errorf("source not configured")
+	}
+
+	me, err := s.client.Me()
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve current Jira user: %w", err)
+	}
+
+	return fmt.Sprintf("%s on %s", me.Login, s.serverURL), nil
+}
+
 // SupportsRealtime implements interfaces.Source.
 func (s *JiraSource) SupportsRealtime() bool {
 	return false
 }
 
+// Capabilities implements interfaces.Source. The JQL filters on updated >= since.
+func (s *JiraSource) Capabilities() interfaces.SourceCapabilities {
+	return interfaces.SourceCapabilities{SupportsIncremental: true}
+}
+
 // Fetch implements interfaces.Source.
 func (s *JiraSource) Fetch(since time.Time, limit int) ([]models.FullItem, error) {
+	return s.FetchContext(context.Background(), since, limit)
+}
+
+// FetchContext implements interfaces.ContextSource. ctx is passed to every
+// search request, each of which is additionally bounded by the request timeout.
+func (s *JiraSource) FetchContext(ctx context.Context, since time.Time, limit int) ([]models.FullItem, error) {
 	jql := buildJQL(s.cfg, since, s.currentUser)
 
 	const pageSize = 50
 
 	if s.isCloud() {
-		return s.fetchCloud(jql, limit, pageSize)
+		return s.fetchCloud(ctx, jql, limit, pageSize)
+	}
+
+	return s.fetchLocal(ctx, jql, limit, pageSize)
+}
+
+// requestContext derives a per-request context bounded by the configured timeout.
+func (s *JiraSource) requestContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	if s.requestTimeout <= 0 {
+		return context.WithCancel(ctx)
 	}
 
-	return s.fetchLocal(jql, limit, pageSize)
+	return context.WithTimeout(ctx, s.requestTimeout)
 }
 
 // fetchCloud paginates using cursor-based nextPageToken (v3 /search/jql).
-func (s *JiraSource) fetchCloud(jql string, limit, pageSize int) ([]models.FullItem, error) {
+func (s *JiraSource) fetchCloud(ctx context.Context, jql string, limit, pageSize int) ([]models.FullItem, error) {
 	var (
 		allItems      []models.FullItem
 		nextPageToken string
@@ -135,7 +174,7 @@ func (s *JiraSource) fetchCloud(jql string, limit, pageSize int) ([]models.FullI
 			batch = uint(remaining)
 		}
 
-		result, err := s.searchCloudWithAllFields(jql, batch, nextPageToken)
+		result, err := s.searchCloudWithAllFields(ctx, jql, batch, nextPageToken)
 		if err != nil {
 			return nil, fmt.Errorf("jira search failed: %w", err)
 		}
@@ -155,7 +194,7 @@ func (s *JiraSource) fetchCloud(jql string, limit, pageSize int) ([]models.FullI
 }
 
 // fetchLocal paginates using offset-based startAt (v2 /search).
-func (s *JiraSource) fetchLocal(jql string, limit, pageSize int) ([]models.FullItem, error) {
+func (s *JiraSource) fetchLocal(ctx context.Context, jql string, limit, pageSize int) ([]models.FullItem, error) {
 	var allItems []models.FullItem
 
 	startAt := uint(0)
@@ -171,7 +210,7 @@ func (s *JiraSource) fetchLocal(jql string, limit, pageSize int) ([]models.FullI
 			batch = uint(remaining)
 		}
 
-		result, err := s.searchLocalWithAllFields(jql, startAt, batch)
+		result, err := s.searchLocalWithAllFields(ctx, jql, startAt, batch)
 		if err != nil {
 			return nil, fmt.Errorf("jira search failed: %w", err)
 		}
@@ -202,6 +241,9 @@ func (s *JiraSource) FetchOne(ctx context.Context, key string) (models.FullItem,
 func (s *JiraSource) FetchIssue(ctx context.Context, issueKey string) (models.FullItem, error) {
 	path := fmt.Sprintf("/issue/%s?fields=*all", url.PathEscape(issueKey))
 
+	ctx, cancel := s.requestContext(ctx)
+	defer cancel()
+
 	var (
 		res *http.Response
 		err error
@@ -243,7 +285,7 @@ func (s *JiraSource) FetchIssue(ctx context.Context, issueKey string) (models.Fu
 // searchCloudWithAllFields performs a v3 search with fields=*all.
 // Uses cursor-based pagination via nextPageToken (Cloud /search/jql API).
 func (s *JiraSource) searchCloudWithAllFields(
-	jql string, limit uint, pageToken string,
+	ctx context.Context, jql string, limit uint, pageToken string,
 ) (*jiraclient.SearchResult, error) {
 	path := fmt.Sprintf(
 		"/search/jql?jql=%s&maxResults=%d&fields=*all",
@@ -254,7 +296,10 @@ func (s *JiraSource) searchCloudWithAllFields(
 		path += "&nextPageToken=" + url.QueryEscape(pageToken)
 	}
 
-	res, err := s.client.Get(context.Background(), path, nil)
+	ctx, cancel := s.requestContext(ctx)
+	defer cancel()
+
+	res, err := s.client.Get(ctx, path, nil)
 	if err != nil {
 		return nil, fmt.Errorf("jira cloud search: %w", err)
 	}
@@ -273,13 +318,18 @@ type searchV2Result struct {
 
 // searchLocalWithAllFields performs a v2 search with fields=*all.
 // Uses offset-based pagination via startAt (Server/DC /search API).
-func (s *JiraSource) searchLocalWithAllFields(jql string, startAt, limit uint) (*jiraclient.SearchResult, error) {
+func (s *JiraSource) searchLocalWithAllFields(
+	ctx context.Context, jql string, startAt, limit uint,
+) (*jiraclient.SearchResult, error) {
 	path := fmt.Sprintf(
 		"/search?jql=%s&startAt=%d&maxResults=%d&fields=*all",
 		url.QueryEscape(jql), startAt, limit,
 	)
 
-	res, err := s.client.GetV2(context.Background(), path, nil)
+	ctx, cancel := s.requestContext(ctx)
+	defer cancel()
+
+	res, err := s.client.GetV2(ctx, path, nil)
 	if err != nil {
 		return nil, fmt.Errorf("jira local search: %w", err)
 	}
@@ -8,6 +8,7 @@ import (
 	jiraclient "github.com/ankitpokhrel/jira-cli/pkg/jira"
 	"github.com/stretchr/testify/assert"
 
+	"pkm-sync/pkg/interfaces"
 	"pkm-sync/pkg/models"
 )
 
@@ -146,6 +147,33 @@ func TestIssueToItem_BasicFields(t *testing.T) {
 	assert.Equal(t, "https://issues.example.com/browse/PROJ-123", links[0].URL)
 }
 
+func TestIssueToItem_IssueLinks(t *testing.T) {
+	issue := makeTestIssue()
+	issue.Fields.IssueLinks = make([]struct {
+		ID       string `json:"id"`
+		LinkType struct {
+			Name    string `json:"name"`
+			Inward  string `json:"inward"`
+			Outward string `json:"outward"`
+		} `json:"type"`
+		InwardIssue  *jiraclient.Issue `json:"inwardIssue,omitempty"`
+		OutwardIssue *jiraclient.Issue `json:"outwardIssue,omitempty"`
+	}, 2)
+	issue.Fields.IssueLinks[0].LinkType.Outward = "blocks"
+	issue.Fields.IssueLinks[0].OutwardIssue = &jiraclient.Issue{Key: "PROJ-9"}
+	issue.Fields.IssueLinks[1].LinkType.Inward = "is cloned by"
+	issue.Fields.IssueLinks[1].InwardIssue = &jiraclient.Issue{Key: "OPS-4"}
+
+	item := issueToItem(issue, "https://issues.example.com", models.JiraSourceConfig{})
+
+	assert.Equal(t, []models.Link{
+		{URL: "https://issues.example.com/browse/PROJ-123", Title: "PROJ-123", Type: "external"},
+		{URL: "https://issues.example.com/browse/PROJ-9", Title: "blocks PROJ-9", Type: "issue_link"},
+		{URL: "https://issues.example.com/browse/OPS-4", Title: "is cloned by OPS-4", Type: "issue_link"},
+	}, item.GetLinks())
+	assert.Equal(t, []string{"[[jira/PROJ-9]]"}, item.GetMetadata()["blocks"])
+}
+
 func TestIssueToItem_Timestamps(t *testing.T) {
 	issue := makeTestIssue()
 	item := issueToItem(issue, "", models.JiraSourceConfig{})
@@ -366,3 +394,8 @@ func TestFixInlineCards(t *testing.T) {
 		})
 	}
 }
+
+func TestJiraSource_Capabilities(t *testing.T) {
+	caps := NewJiraSource("jira", models.SourceConfig{Type: "jira"}).Capabilities()
+	assert.Equal(t, interfaces.SourceCapabilities{SupportsIncremental: true}, caps)
+}
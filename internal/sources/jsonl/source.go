@@ -0,0 +1,271 @@
+// Package jsonl implements a source that ingests items from JSON Lines — one
+// serialized models.FullItem per line — so any script can feed data from a
+// system pkm-sync does not support natively through the normal pipeline.
+package jsonl
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"time"
+
+	"pkm-sync/pkg/interfaces"
+	"pkm-sync/pkg/models"
+)
+
+// SourceType is the config type of the JSONL source; "stdin" is an alias.
+const SourceType = "jsonl"
+
+// MaxLineBytes bounds a single serialized item; threads with long bodies can
+// be megabytes. Longer lines are reported as malformed and skipped.
+const MaxLineBytes = 16 * 1024 * 1024
+
+// LineError describes a record that could not be ingested.
+type LineError struct {
+	Line int
+	Err  error
+}
+
+func (e LineError) Error() string {
+	return fmt.Sprintf("line %d: %v", e.Line, e.Err)
+}
+
+func (e LineError) Unwrap() error {
+	return e.Err
+}
+
+// JSONLSource implements interfaces.Source over a JSONL file or stdin.
+type JSONLSource struct {
+	sourceID string
+	cfg      models.JSONLSourceConfig
+
+	// stdin is read when no path is configured; tests substitute it.
+	stdin io.Reader
+	// malformed holds the records skipped by the last Fetch.
+	malformed []LineError
+}
+
+// NewJSONLSource creates a new JSONLSource from a SourceConfig.
+func NewJSONLSource(sourceID string, sourceCfg models.SourceConfig) *JSONLSource {
+	return &JSONLSource{
+		sourceID: sourceID,
+		cfg:      sourceCfg.JSONL,
+		stdin:    os.Stdin,
+	}
+}
+
+// Name implements interfaces.Source.
+func (s *JSONLSource) Name() string {
+	return s.sourceID
+}
+
+// Configure implements interfaces.Source. A configured file must exist.
+func (s *JSONLSource) Configure(_ map[string]any, _ *http.Client) error {
+	if s.readsStdin() {
+		return nil
+	}
+
+	if _, err := os.Stat(s.cfg.Path); err != nil {
+		return fmt.Errorf("jsonl source %s: %w", s.sourceID, err)
+	}
+
+	return nil
+}
+
+// Fetch implements interfaces.Source. Records are taken as emitted: since is
+// ignored because the producing script already chose what to send, while
+// limit caps the number of items. Malformed records are reported and skipped.
+func (s *JSONLSource) Fetch(_ time.Time, limit int) ([]models.FullItem, error) {
+	r := s.stdin
+
+	if !s.readsStdin() {
+		f, err := os.Open(s.cfg.Path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open %s: %w", s.cfg.Path, err)
+		}
+		defer f.Close()
+
+		r = f
+	}
+
+	items, malformed, err := Read(r, limit)
+	s.malformed = malformed
+
+	for _, m := range malformed {
+		fmt.Printf("Warning: %s: skipping malformed record at %s\n", s.sourceID, m.Error())
+	}
+
+	if len(malformed) > 0 {
+		fmt.Printf("Warning: %s: skipped %d malformed record(s), ingested %d\n", s.sourceID, len(malformed), len(items))
+	}
+
+	if err != nil {
+		return items, fmt.Errorf("jsonl source %s: %w", s.sourceID, err)
+	}
+
+	return items, nil
+}
+
+// Malformed returns the records skipped by the last Fetch.
+func (s *JSONLSource) Malformed() []LineError {
+	return s.malformed
+}
+
+// SupportsRealtime implements interfaces.Source.
+func (s *JSONLSource) SupportsRealtime() bool {
+	return false
+}
+
+// Capabilities implements interfaces.Source. The whole input is read on every
+// run; records with a "messages" array become threads.
+func (s *JSONLSource) Capabilities() interfaces.SourceCapabilities {
+	return interfaces.SourceCapabilities{ProducesThreads: true}
+}
+
+func (s *JSONLSource) readsStdin() bool {
+	return s.cfg.Path == "" || s.cfg.Path == "-"
+}
+
+// Read decodes up to limit items (0 = no limit) from r, one per line, and
+// validates each. Invalid records are returned as LineErrors instead of
+// aborting; only a failure to read r is returned as an error. Blank lines
+// are skipped.
+func Read(r io.Reader, limit int) ([]models.FullItem, []LineError, error) {
+	reader := bufio.NewReader(r)
+
+	var (
+		items     []models.FullItem
+		malformed []LineError
+	)
+
+	for lineNum := 1; limit <= 0 || len(items) < limit; lineNum++ {
+		line, err := readLine(reader)
+		if err != nil && !errors.Is(err, io.EOF) && !errors.Is(err, errLineTooLong) {
+			return items, malformed, fmt.Errorf("failed to read line %d: %w", lineNum, err)
+		}
+
+		if errors.Is(err, errLineTooLong) {
+			malformed = append(malformed, LineError{Line: lineNum, Err: err})
+
+			continue
+		}
+
+		if trimmed := bytes.TrimSpace(line); len(trimmed) > 0 {
+			item, decodeErr := DecodeItem(trimmed)
+			if decodeErr == nil {
+				decodeErr = validate(item)
+			}
+
+			if decodeErr != nil {
+				malformed = append(malformed, LineError{Line: lineNum, Err: decodeErr})
+			} else {
+				items = append(items, item)
+			}
+		}
+
+		if errors.Is(err, io.EOF) {
+			break
+		}
+	}
+
+	return items, malformed, nil
+}
+
+var errLineTooLong = fmt.Errorf("record exceeds %d bytes", MaxLineBytes)
+
+// readLine returns the next line without its newline. A line longer than
+// MaxLineBytes is consumed and reported as errLineTooLong.
+func readLine(r *bufio.Reader) ([]byte, error) {
+	var line []byte
+
+	for {
+		chunk, isPrefix, err := r.ReadLine()
+		if err != nil {
+			return line, err
+		}
+
+		if len(line)+len(chunk) > MaxLineBytes {
+			for isPrefix && err == nil {
+				_, isPrefix, err = r.ReadLine()
+			}
+
+			if err != nil && !errors.Is(err, io.EOF) {
+				return nil, err
+			}
+
+			return nil, errLineTooLong
+		}
+
+		line = append(line, chunk...)
+
+		if !isPrefix {
+			return line, nil
+		}
+	}
+}
+
+// DecodeItem decodes one serialized item. Objects carrying a "messages"
+// field are decoded as threads, and dead-letter entries (an "item" object
+// without an id of their own) are unwrapped. Items without an id get a
+// models.DeriveItemID one, so re-importing them updates the same notes.
+func DecodeItem(data []byte) (models.FullItem, error) {
+	var probe struct {
+		ID       string          `json:"id"`
+		Messages json.RawMessage `json:"messages"`
+		Item     json.RawMessage `json:"item"`
+	}
+
+	if err := json.Unmarshal(data, &probe); err != nil {
+		return nil, fmt.Errorf("invalid item JSON: %w", err)
+	}
+
+	if probe.ID == "" && len(probe.Item) > 0 && probe.Item[0] == '{' {
+		return DecodeItem(probe.Item)
+	}
+
+	if len(probe.Messages) > 0 && !bytes.Equal(probe.Messages, []byte("null")) {
+		thread := &models.Thread{}
+		if err := thread.UnmarshalJSON(data); err != nil {
+			return nil, fmt.Errorf("invalid thread JSON: %w", err)
+		}
+
+		models.EnsureID(thread)
+
+		return thread, nil
+	}
+
+	item := &models.BasicItem{}
+	if err := item.UnmarshalJSON(data); err != nil {
+		return nil, fmt.Errorf("invalid item JSON: %w", err)
+	}
+
+	models.EnsureID(item)
+
+	return item, nil
+}
+
+// validate checks the fields every sink relies on and fills in a missing
+// source type so external items are still routed and tagged.
+func validate(item models.FullItem) error {
+	if item.GetID() == "" {
+		return fmt.Errorf("item has no id")
+	}
+
+	if item.GetTitle() == "" {
+		return fmt.Errorf("item %q has no title", item.GetID())
+	}
+
+	if item.GetSourceType() == "" {
+		item.SetSourceType(SourceType)
+	}
+
+	return nil
+}
+
+// Ensure interface compliance.
+var _ interfaces.Source = (*JSONLSource)(nil)
@@ -0,0 +1,246 @@
+package jsonl
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	syncer "pkm-sync/internal/sync"
+	"pkm-sync/pkg/interfaces"
+	"pkm-sync/pkg/models"
+)
+
+// exportJSONL serializes items the way `pkm-sync fetch --format json` does:
+// one MarshalJSON object per line.
+func exportJSONL(t *testing.T, items ...models.FullItem) string {
+	t.Helper()
+
+	var buf bytes.Buffer
+
+	for _, item := range items {
+		data, err := item.MarshalJSON()
+		if err != nil {
+			t.Fatalf("MarshalJSON: %v", err)
+		}
+
+		buf.Write(data)
+		buf.WriteByte('\n')
+	}
+
+	return buf.String()
+}
+
+func sampleItems() (models.FullItem, *models.Thread) {
+	created := time.Date(2026, 2, 3, 10, 0, 0, 0, time.UTC)
+
+	note := models.NewBasicItem("ext-1", "Weekly metrics")
+	note.SetSourceType("grafana")
+	note.SetItemType("report")
+	note.SetContent("p95 latency is down 12%")
+	note.SetCreatedAt(created)
+	note.SetUpdatedAt(created.Add(time.Hour))
+	note.SetTags([]string{"metrics", "weekly"})
+	note.SetMetadata(map[string]interface{}{"dashboard": "api"})
+	note.SetLinks([]models.Link{{URL: "https://grafana.example.com/d/api", Title: "API"}})
+
+	thread := models.NewThread("ext-2", "Forum thread")
+	thread.SetSourceType("discourse")
+	thread.SetCreatedAt(created)
+	thread.AddMessage(models.NewBasicItem("ext-2-1", "First post"))
+	thread.AddMessage(models.NewBasicItem("ext-2-2", "Reply"))
+
+	return note, thread
+}
+
+func TestRead_RoundTripsExport(t *testing.T) {
+	note, thread := sampleItems()
+
+	items, malformed, err := Read(strings.NewReader(exportJSONL(t, note, thread)), 0)
+	if err != nil || len(malformed) != 0 {
+		t.Fatalf("Read: err=%v malformed=%v", err, malformed)
+	}
+
+	if len(items) != 2 {
+		t.Fatalf("got %d items, want 2", len(items))
+	}
+
+	// Re-exporting what was read must reproduce the input byte for byte.
+	if got, want := exportJSONL(t, items...), exportJSONL(t, note, thread); got != want {
+		t.Errorf("round trip changed the items:\ngot  %s\nwant %s", got, want)
+	}
+
+	gotThread, ok := models.AsThread(items[1])
+	if !ok || len(gotThread.GetMessages()) != 2 {
+		t.Errorf("second record should decode as a thread with 2 messages, got %#v", items[1])
+	}
+}
+
+func TestRead_ReportsMalformedWithoutAborting(t *testing.T) {
+	note, _ := sampleItems()
+	valid := strings.TrimSpace(exportJSONL(t, note))
+
+	input := strings.Join([]string{
+		valid,
+		`{"id": "broken", "title": `,
+		``,
+		`{"title": "no id"}`,
+		`{"id": "no-title"}`,
+		`[1, 2, 3]`,
+		`{"id": "ext-9", "title": "Minimal"}`,
+	}, "\n")
+
+	items, malformed, err := Read(strings.NewReader(input), 0)
+	if err != nil {
+		t.Fatalf("Read: %v", err)
+	}
+
+	if len(items) != 3 || items[0].GetID() != "ext-1" || items[2].GetID() != "ext-9" {
+		t.Fatalf("items = %v, want ext-1, a derived ID and ext-9", itemIDs(items))
+	}
+
+	if want := models.DeriveItemID("", "no id", time.Time{}, ""); items[1].GetID() != want {
+		t.Errorf("item without id should get derived ID %q, got %q", want, items[1].GetID())
+	}
+
+	if items[2].GetSourceType() != SourceType {
+		t.Errorf("missing source_type should default to %q, got %q", SourceType, items[2].GetSourceType())
+	}
+
+	wantLines := []int{2, 5, 6}
+	if len(malformed) != len(wantLines) {
+		t.Fatalf("malformed = %v, want lines %v", malformed, wantLines)
+	}
+
+	for i, line := range wantLines {
+		if malformed[i].Line != line {
+			t.Errorf("malformed[%d] is line %d, want %d", i, malformed[i].Line, line)
+		}
+	}
+}
+
+func TestRead_LimitAndOversizedLine(t *testing.T) {
+	input := `{"id": "a", "title": "A"}` + "\n" +
+		`{"id": "big", "title": "` + strings.Repeat("x", MaxLineBytes) + `"}` + "\n" +
+		`{"id": "b", "title": "B"}` + "\n" +
+		`{"id": "c", "title": "C"}`
+
+	items, malformed, err := Read(strings.NewReader(input), 2)
+	if err != nil {
+		t.Fatalf("Read: %v", err)
+	}
+
+	if ids := itemIDs(items); strings.Join(ids, ",") != "a,b" {
+		t.Errorf("items = %v, want [a b]", ids)
+	}
+
+	if len(malformed) != 1 || malformed[0].Line != 2 {
+		t.Errorf("malformed = %v, want the oversized line 2", malformed)
+	}
+}
+
+func TestJSONLSource_FileThroughPipeline(t *testing.T) {
+	note, thread := sampleItems()
+
+	path := filepath.Join(t.TempDir(), "items.jsonl")
+	if err := os.WriteFile(path, []byte(exportJSONL(t, note, thread)+"not json\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	src := NewJSONLSource("external", models.SourceConfig{Type: SourceType, JSONL: models.JSONLSourceConfig{Path: path}})
+	if err := src.Configure(nil, nil); err != nil {
+		t.Fatalf("Configure: %v", err)
+	}
+
+	result, err := syncer.NewMultiSyncer(nil).SyncAll(context.Background(),
+		[]syncer.SourceEntry{{Name: "external", Src: src}}, []interfaces.Sink{}, syncer.MultiSyncOptions{})
+	if err != nil {
+		t.Fatalf("SyncAll: %v", err)
+	}
+
+	if len(result.Items) != 2 {
+		t.Errorf("pipeline received %d items, want 2", len(result.Items))
+	}
+
+	if len(src.Malformed()) != 1 || src.Malformed()[0].Line != 3 {
+		t.Errorf("Malformed() = %v, want line 3", src.Malformed())
+	}
+}
+
+func TestJSONLSource_Stdin(t *testing.T) {
+	note, _ := sampleItems()
+
+	src := NewJSONLSource("piped", models.SourceConfig{Type: SourceType})
+	src.stdin = strings.NewReader(exportJSONL(t, note))
+
+	if err := src.Configure(nil, nil); err != nil {
+		t.Fatalf("Configure: %v", err)
+	}
+
+	items, err := src.Fetch(time.Time{}, 0)
+	if err != nil || len(items) != 1 || items[0].GetTitle() != "Weekly metrics" {
+		t.Errorf("Fetch from stdin = %v, %v", itemIDs(items), err)
+	}
+}
+
+func TestJSONLSource_MissingFile(t *testing.T) {
+	src := NewJSONLSource("external", models.SourceConfig{
+		JSONL: models.JSONLSourceConfig{Path: filepath.Join(t.TempDir(), "missing.jsonl")},
+	})
+
+	if err := src.Configure(nil, nil); err == nil {
+		t.Error("Configure should fail for a missing file")
+	}
+}
+
+func itemIDs(items []models.FullItem) []string {
+	ids := make([]string, 0, len(items))
+	for _, item := range items {
+		ids = append(ids, item.GetID())
+	}
+
+	return ids
+}
+
+func TestRead_DerivesStableIDsForItemsWithoutID(t *testing.T) {
+	record := `{"title": "Standup", "source_type": "notes", "created_at": "2026-03-02T09:00:00Z", "content": "Agenda"}`
+	edited := `{"title": "Standup", "source_type": "notes", "created_at": "2026-03-02T09:00:00Z", "content": "Agenda v2"}`
+
+	firstRun, _, err := Read(strings.NewReader(record), 0)
+	if err != nil {
+		t.Fatalf("Read: %v", err)
+	}
+
+	secondRun, _, err := Read(strings.NewReader(record+"\n"+edited), 0)
+	if err != nil {
+		t.Fatalf("Read: %v", err)
+	}
+
+	if len(firstRun) != 1 || len(secondRun) != 2 {
+		t.Fatalf("got %d and %d items, want 1 and 2", len(firstRun), len(secondRun))
+	}
+
+	if firstRun[0].GetID() != secondRun[0].GetID() {
+		t.Errorf("same record derived different IDs across runs: %q vs %q", firstRun[0].GetID(), secondRun[0].GetID())
+	}
+
+	if secondRun[1].GetID() == secondRun[0].GetID() {
+		t.Errorf("edited record kept ID %q", secondRun[1].GetID())
+	}
+}
+
+func TestJSONLSource_Capabilities(t *testing.T) {
+	caps := NewJSONLSource("import", models.SourceConfig{Type: "jsonl"}).Capabilities()
+
+	// The whole file is re-read every run, so the since window cannot be trusted.
+	if caps.SupportsIncremental {
+		t.Error("JSONL source must not report incremental support")
+	}
+
+	if !caps.ProducesThreads || caps.ProvidesRawMessages {
+		t.Errorf("unexpected capabilities %+v", caps)
+	}
+}
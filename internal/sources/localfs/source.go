@@ -0,0 +1,318 @@
+// Package localfs implements a source that re-ingests Markdown and plain-text
+// notes already on disk, so notes written by other tools can run through the
+// transform, dedup and indexing pipeline without any network access.
+package localfs
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"pkm-sync/internal/state"
+	"pkm-sync/pkg/interfaces"
+	"pkm-sync/pkg/models"
+
+	"gopkg.in/yaml.v3"
+)
+
+// SourceType is the config type of the local files source.
+const SourceType = "localfs"
+
+// itemTypeNote is the item type of an ingested file.
+const itemTypeNote = "note"
+
+// DefaultExtensions are ingested when no extensions are configured.
+var DefaultExtensions = []string{".md", ".markdown", ".txt"}
+
+// LocalFSSource implements interfaces.Source over a directory of notes.
+type LocalFSSource struct {
+	sourceID  string
+	cfg       models.LocalFSSourceConfig
+	fileIndex *state.FileIndex
+}
+
+// NewLocalFSSource creates a new LocalFSSource from a SourceConfig.
+func NewLocalFSSource(sourceID string, sourceCfg models.SourceConfig) *LocalFSSource {
+	return &LocalFSSource{
+		sourceID: sourceID,
+		cfg:      sourceCfg.LocalFS,
+	}
+}
+
+// Name implements interfaces.Source.
+func (s *LocalFSSource) Name() string {
+	return s.sourceID
+}
+
+// SupportsRealtime implements interfaces.Source.
+func (s *LocalFSSource) SupportsRealtime() bool {
+	return false
+}
+
+// Capabilities implements interfaces.Source. Files are filtered by
+// modification time, so only notes edited since the last sync are re-emitted.
+func (s *LocalFSSource) Capabilities() interfaces.SourceCapabilities {
+	return interfaces.SourceCapabilities{SupportsIncremental: true}
+}
+
+// SetFileIndex makes Fetch skip files whose content is unchanged since the
+// index was last saved. Fetch records every emitted file in the index; the
+// caller saves it once the items have been written.
+func (s *LocalFSSource) SetFileIndex(idx *state.FileIndex) {
+	s.fileIndex = idx
+}
+
+// Configure implements interfaces.Source. The root directory must exist.
+func (s *LocalFSSource) Configure(_ map[string]any, _ *http.Client) error {
+	if s.cfg.RootDir == "" {
+		return fmt.Errorf("localfs source %s has no root_dir", s.sourceID)
+	}
+
+	info, err := os.Stat(s.cfg.RootDir)
+	if err != nil {
+		return fmt.Errorf("localfs source %s: %w", s.sourceID, err)
+	}
+
+	if !info.IsDir() {
+		return fmt.Errorf("localfs source %s: %s is not a directory", s.sourceID, s.cfg.RootDir)
+	}
+
+	return nil
+}
+
+// Fetch implements interfaces.Source. It returns every matching file under
+// the root directory modified after since, most recently modified first, at
+// most limit of them (0 = no limit). With a file index set, files whose
+// content has not changed since the last saved run are left out. Files with
+// invalid frontmatter are ingested with the frontmatter left in the content.
+func (s *LocalFSSource) Fetch(since time.Time, limit int) ([]models.FullItem, error) {
+	files, err := s.matchingFiles(since)
+	if err != nil {
+		return nil, fmt.Errorf("localfs source %s: %w", s.sourceID, err)
+	}
+
+	items := make([]models.FullItem, 0, len(files))
+
+	for _, f := range files {
+		if s.fileIndex != nil && s.fileIndex.Unchanged(f.path, f.info) {
+			continue
+		}
+
+		if limit > 0 && len(items) >= limit {
+			continue
+		}
+
+		data, err := os.ReadFile(f.path)
+		if err != nil {
+			fmt.Printf("Warning: %s: skipping %s: %v\n", s.sourceID, f.rel, err)
+
+			continue
+		}
+
+		if s.fileIndex != nil && !s.fileIndex.Observe(f.path, f.info, data) {
+			continue
+		}
+
+		items = append(items, s.fileToItem(f, string(data)))
+	}
+
+	return items, nil
+}
+
+type noteFile struct {
+	path    string
+	rel     string
+	modTime time.Time
+	info    fs.FileInfo
+}
+
+// matchingFiles walks the root directory for files with a configured
+// extension modified after since, newest first.
+func (s *LocalFSSource) matchingFiles(since time.Time) ([]noteFile, error) {
+	extensions := s.cfg.Extensions
+	if len(extensions) == 0 {
+		extensions = DefaultExtensions
+	}
+
+	var files []noteFile
+
+	err := filepath.WalkDir(s.cfg.RootDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if d.IsDir() {
+			if path != s.cfg.RootDir && strings.HasPrefix(d.Name(), ".") {
+				return filepath.SkipDir
+			}
+
+			return nil
+		}
+
+		if !d.Type().IsRegular() || !hasExtension(d.Name(), extensions) {
+			return nil
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+
+		if !since.IsZero() && !info.ModTime().After(since) {
+			return nil
+		}
+
+		rel, err := filepath.Rel(s.cfg.RootDir, path)
+		if err != nil {
+			return err
+		}
+
+		files = append(files, noteFile{path: path, rel: filepath.ToSlash(rel), modTime: info.ModTime(), info: info})
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(files, func(i, j int) bool {
+		if !files[i].modTime.Equal(files[j].modTime) {
+			return files[i].modTime.After(files[j].modTime)
+		}
+
+		return files[i].rel < files[j].rel
+	})
+
+	return files, nil
+}
+
+// fileToItem converts a file to a note item. The ID is derived from the path
+// relative to the root, so an edited file updates the same note.
+func (s *LocalFSSource) fileToItem(f noteFile, content string) models.FullItem {
+	sum := sha256.Sum256([]byte(f.rel))
+	base := filepath.Base(f.rel)
+
+	item := &models.BasicItem{
+		ID:         "localfs_" + hex.EncodeToString(sum[:8]),
+		Title:      strings.TrimSuffix(base, filepath.Ext(base)),
+		Content:    content,
+		SourceType: SourceType,
+		ItemType:   itemTypeNote,
+		CreatedAt:  f.modTime,
+		UpdatedAt:  f.modTime,
+		Tags:       make([]string, 0),
+		Metadata:   map[string]any{"path": f.rel},
+		Links:      make([]models.Link, 0),
+	}
+
+	if !s.cfg.ParseFrontmatter {
+		return item
+	}
+
+	props, body, ok, err := splitFrontmatter(content)
+	if err != nil {
+		fmt.Printf("Warning: %s: keeping frontmatter of %s as content: %v\n", s.sourceID, f.rel, err)
+
+		return item
+	}
+
+	if !ok {
+		return item
+	}
+
+	item.Content = strings.TrimLeft(body, "\n")
+
+	for key, value := range props {
+		switch key {
+		case "tags":
+			item.Tags = tagList(value)
+		case "title":
+			if title, ok := value.(string); ok && strings.TrimSpace(title) != "" {
+				item.Title = strings.TrimSpace(title)
+			}
+		case "path":
+			// The file's location wins over a stale frontmatter path.
+		default:
+			item.Metadata[key] = value
+		}
+	}
+
+	return item
+}
+
+// splitFrontmatter separates a leading "---" YAML block from the body. ok is
+// false when the content has no frontmatter.
+func splitFrontmatter(content string) (props map[string]any, body string, ok bool, err error) {
+	text := strings.ReplaceAll(content, "\r\n", "\n")
+	if !strings.HasPrefix(text, "---\n") {
+		return nil, content, false, nil
+	}
+
+	rest := text[len("---\n"):]
+
+	end := strings.Index(rest, "\n---")
+	if end < 0 || (end+len("\n---") < len(rest) && rest[end+len("\n---")] != '\n') {
+		return nil, content, false, fmt.Errorf("unterminated frontmatter")
+	}
+
+	props = make(map[string]any)
+	if err := yaml.Unmarshal([]byte(rest[:end+1]), &props); err != nil {
+		return nil, content, false, fmt.Errorf("invalid frontmatter: %w", err)
+	}
+
+	body = rest[end+len("\n---"):]
+
+	return props, strings.TrimPrefix(body, "\n"), true, nil
+}
+
+// tagList normalizes a frontmatter tags value: a YAML list, or a string of
+// comma- or space-separated tags. A leading "#" is dropped.
+func tagList(value any) []string {
+	var raw []string
+
+	switch v := value.(type) {
+	case []any:
+		for _, t := range v {
+			raw = append(raw, fmt.Sprint(t))
+		}
+	case string:
+		raw = strings.FieldsFunc(v, func(r rune) bool { return r == ',' || r == ' ' })
+	}
+
+	tags := make([]string, 0, len(raw))
+
+	for _, t := range raw {
+		if t = strings.TrimPrefix(strings.TrimSpace(t), "#"); t != "" {
+			tags = append(tags, t)
+		}
+	}
+
+	return tags
+}
+
+func hasExtension(name string, extensions []string) bool {
+	ext := strings.ToLower(filepath.Ext(name))
+
+	for _, want := range extensions {
+		want = strings.ToLower(want)
+		if !strings.HasPrefix(want, ".") {
+			want = "." + want
+		}
+
+		if ext == want {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Ensure interface compliance.
+var _ interfaces.Source = (*LocalFSSource)(nil)
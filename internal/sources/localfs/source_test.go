@@ -0,0 +1,231 @@
+package localfs
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"pkm-sync/internal/state"
+	"pkm-sync/pkg/models"
+)
+
+func writeNote(t *testing.T, root, rel, content string, modTime time.Time) {
+	t.Helper()
+
+	path := filepath.Join(root, filepath.FromSlash(rel))
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.Chtimes(path, modTime, modTime); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func newTestSource(t *testing.T, cfg models.LocalFSSourceConfig) *LocalFSSource {
+	t.Helper()
+
+	s := NewLocalFSSource("old_notes", models.SourceConfig{Type: SourceType, LocalFS: cfg})
+	if err := s.Configure(nil, nil); err != nil {
+		t.Fatalf("Configure: %v", err)
+	}
+
+	return s
+}
+
+func TestFetch_FiltersByExtensionAndModTime(t *testing.T) {
+	root := t.TempDir()
+	base := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+
+	writeNote(t, root, "projects/roadmap.md", "# Roadmap\n", base.Add(2*time.Hour))
+	writeNote(t, root, "inbox.txt", "call Sam", base.Add(time.Hour))
+	writeNote(t, root, "archive/2019.md", "old", base.Add(-48*time.Hour))
+	writeNote(t, root, "diagram.png", "binary", base.Add(time.Hour))
+	writeNote(t, root, ".obsidian/workspace.md", "ui state", base.Add(time.Hour))
+
+	s := newTestSource(t, models.LocalFSSourceConfig{RootDir: root})
+
+	items, err := s.Fetch(base, 0)
+	if err != nil {
+		t.Fatalf("Fetch: %v", err)
+	}
+
+	var titles []string
+	for _, item := range items {
+		titles = append(titles, item.GetTitle())
+	}
+
+	if want := []string{"roadmap", "inbox"}; !reflect.DeepEqual(titles, want) {
+		t.Fatalf("titles = %v, want %v (newest first)", titles, want)
+	}
+
+	roadmap := items[0]
+	if roadmap.GetContent() != "# Roadmap\n" || roadmap.GetItemType() != "note" || roadmap.GetSourceType() != "localfs" {
+		t.Errorf("unexpected item: %+v", roadmap)
+	}
+
+	if roadmap.GetMetadata()["path"] != "projects/roadmap.md" {
+		t.Errorf("path metadata = %v", roadmap.GetMetadata()["path"])
+	}
+
+	if !roadmap.GetUpdatedAt().Equal(base.Add(2 * time.Hour)) {
+		t.Errorf("UpdatedAt = %v", roadmap.GetUpdatedAt())
+	}
+
+	again, err := s.Fetch(base, 1)
+	if err != nil {
+		t.Fatalf("Fetch: %v", err)
+	}
+
+	if len(again) != 1 || again[0].GetID() != roadmap.GetID() {
+		t.Errorf("expected the same stable ID within limit 1, got %d items", len(again))
+	}
+}
+
+func TestFetch_Extensions(t *testing.T) {
+	root := t.TempDir()
+	now := time.Now()
+
+	writeNote(t, root, "a.md", "a", now)
+	writeNote(t, root, "b.org", "b", now)
+
+	s := newTestSource(t, models.LocalFSSourceConfig{RootDir: root, Extensions: []string{"org"}})
+
+	items, err := s.Fetch(time.Time{}, 0)
+	if err != nil {
+		t.Fatalf("Fetch: %v", err)
+	}
+
+	if len(items) != 1 || items[0].GetTitle() != "b" {
+		t.Errorf("expected only b.org, got %d items", len(items))
+	}
+}
+
+func TestFetch_ParseFrontmatter(t *testing.T) {
+	root := t.TempDir()
+	now := time.Now()
+
+	writeNote(t, root, "meeting.md",
+		"---\ntitle: Planning meeting\ntags: [work, \"#q4\"]\nstatus: draft\n---\n\nAgenda items.\n", now)
+	writeNote(t, root, "inline-tags.md", "---\ntags: work, ideas\n---\nBody\n", now)
+	writeNote(t, root, "broken.md", "---\ntitle: [unclosed\n---\nBody\n", now)
+
+	s := newTestSource(t, models.LocalFSSourceConfig{RootDir: root, ParseFrontmatter: true})
+
+	items, err := s.Fetch(time.Time{}, 0)
+	if err != nil {
+		t.Fatalf("Fetch: %v", err)
+	}
+
+	byPath := make(map[string]models.FullItem)
+	for _, item := range items {
+		byPath[item.GetMetadata()["path"].(string)] = item
+	}
+
+	meeting := byPath["meeting.md"]
+	if meeting.GetTitle() != "Planning meeting" || meeting.GetContent() != "Agenda items.\n" {
+		t.Errorf("meeting: title %q content %q", meeting.GetTitle(), meeting.GetContent())
+	}
+
+	if !reflect.DeepEqual(meeting.GetTags(), []string{"work", "q4"}) {
+		t.Errorf("meeting tags = %v", meeting.GetTags())
+	}
+
+	if meeting.GetMetadata()["status"] != "draft" {
+		t.Errorf("meeting metadata = %v", meeting.GetMetadata())
+	}
+
+	if tags := byPath["inline-tags.md"].GetTags(); !reflect.DeepEqual(tags, []string{"work", "ideas"}) {
+		t.Errorf("inline tags = %v", tags)
+	}
+
+	broken := byPath["broken.md"]
+	if broken.GetTitle() != "broken" || broken.GetContent() != "---\ntitle: [unclosed\n---\nBody\n" {
+		t.Errorf("invalid frontmatter should be kept as content, got %q", broken.GetContent())
+	}
+}
+
+func TestFetch_FrontmatterKeptWhenParsingDisabled(t *testing.T) {
+	root := t.TempDir()
+	content := "---\ntags: [work]\n---\nBody\n"
+
+	writeNote(t, root, "note.md", content, time.Now())
+
+	items, err := newTestSource(t, models.LocalFSSourceConfig{RootDir: root}).Fetch(time.Time{}, 0)
+	if err != nil {
+		t.Fatalf("Fetch: %v", err)
+	}
+
+	if len(items) != 1 || items[0].GetContent() != content || len(items[0].GetTags()) != 0 {
+		t.Errorf("expected the file untouched, got %+v", items)
+	}
+}
+
+func TestFetch_FileIndexSkipsUnchangedFiles(t *testing.T) {
+	root := t.TempDir()
+	configDir := t.TempDir()
+	base := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+
+	writeNote(t, root, "roadmap.md", "# Roadmap\n", base)
+	writeNote(t, root, "inbox.md", "call Sam", base)
+	writeNote(t, root, "ideas.md", "ship it", base)
+
+	run := func() []string {
+		t.Helper()
+
+		idx, err := state.LoadFileIndex(configDir, "old_notes")
+		if err != nil {
+			t.Fatalf("LoadFileIndex: %v", err)
+		}
+
+		s := newTestSource(t, models.LocalFSSourceConfig{RootDir: root})
+		s.SetFileIndex(idx)
+
+		items, err := s.Fetch(time.Time{}, 0)
+		if err != nil {
+			t.Fatalf("Fetch: %v", err)
+		}
+
+		if err := idx.Save(configDir, "old_notes"); err != nil {
+			t.Fatalf("Save: %v", err)
+		}
+
+		var titles []string
+		for _, item := range items {
+			titles = append(titles, item.GetTitle())
+		}
+
+		return titles
+	}
+
+	if got := run(); len(got) != 3 {
+		t.Fatalf("first run: got %v, want all three notes", got)
+	}
+
+	if got := run(); len(got) != 0 {
+		t.Errorf("second run: got %v, want no notes", got)
+	}
+
+	// Touched only: new mtime, same content.
+	writeNote(t, root, "inbox.md", "call Sam", base.Add(time.Hour))
+	writeNote(t, root, "ideas.md", "ship it today", base.Add(time.Hour))
+
+	if got := run(); !reflect.DeepEqual(got, []string{"ideas"}) {
+		t.Errorf("third run: got %v, want [ideas]", got)
+	}
+}
+
+func TestConfigure_RequiresDirectory(t *testing.T) {
+	s := NewLocalFSSource("old_notes", models.SourceConfig{
+		LocalFS: models.LocalFSSourceConfig{RootDir: filepath.Join(t.TempDir(), "missing")},
+	})
+	if err := s.Configure(nil, nil); err == nil {
+		t.Error("expected an error for a missing root_dir")
+	}
+}
@@ -0,0 +1,260 @@
+package notion
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"pkm-sync/internal/httpclient"
+)
+
+const (
+	// defaultBaseURL is the Notion public API.
+	defaultBaseURL = "https://api.notion.com/v1"
+	// apiVersion is the Notion-Version header the client speaks.
+	apiVersion = "2022-06-28"
+	// defaultRequestTimeout bounds a single Notion API request when no
+	// request_timeout is configured.
+	defaultRequestTimeout = 30 * time.Second
+	// pageSize is the largest page the API returns.
+	pageSize = 100
+)
+
+// Client is an HTTP client for the Notion REST API, authenticated with an
+// internal integration token.
+type Client struct {
+	token        string
+	baseURL      string
+	httpClient   *http.Client
+	requestDelay time.Duration
+}
+
+// NewClient creates a Notion API client. Notion allows about three requests
+// per second, so requestDelay defaults to 350ms.
+func NewClient(token string, requestDelay time.Duration) *Client {
+	if requestDelay == 0 {
+		requestDelay = 350 * time.Millisecond
+	}
+
+	return &Client{
+		token:        token,
+		baseURL:      defaultBaseURL,
+		httpClient:   &http.Client{Timeout: defaultRequestTimeout, Transport: httpclient.SharedTransport()},
+		requestDelay: requestDelay,
+	}
+}
+
+// page is a Notion page object.
+type page struct {
+	ID             string                     `json:"id"`
+	URL            string                     `json:"url"`
+	CreatedTime    time.Time                  `json:"created_time"`
+	LastEditedTime time.Time                  `json:"last_edited_time"`
+	Archived       bool                       `json:"archived"`
+	InTrash        bool                       `json:"in_trash"`
+	Properties     map[string]json.RawMessage `json:"properties"`
+}
+
+// richText is one run of Notion rich text.
+type richText struct {
+	PlainText   string `json:"plain_text"`
+	Href        string `json:"href"`
+	Annotations struct {
+		Bold          bool `json:"bold"`
+		Italic        bool `json:"italic"`
+		Strikethrough bool `json:"strikethrough"`
+		Code          bool `json:"code"`
+	} `json:"annotations"`
+}
+
+// block is a Notion block. Its type-specific payload is kept raw and decoded
+// by the converter; children are fetched when HasChildren is set.
+type block struct {
+	ID          string `json:"id"`
+	Type        string `json:"type"`
+	HasChildren bool   `json:"has_children"`
+	payload     json.RawMessage
+	children    []*block
+}
+
+func (b *block) UnmarshalJSON(data []byte) error {
+	type plain block
+
+	if err := json.Unmarshal(data, (*plain)(b)); err != nil {
+		return err
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return err
+	}
+
+	b.payload = fields[b.Type]
+
+	return nil
+}
+
+// listResponse is the paginated envelope of list and query endpoints.
+type listResponse[T any] struct {
+	Results    []T    `json:"results"`
+	HasMore    bool   `json:"has_more"`
+	NextCursor string `json:"next_cursor"`
+}
+
+// QueryDatabase returns the pages of a database edited at or after since (all
+// pages when since is zero), most recently edited first, up to limit.
+func (c *Client) QueryDatabase(databaseID string, since time.Time, limit int) ([]page, error) {
+	body := map[string]any{
+		"page_size": pageSize,
+		"sorts":     []map[string]string{{"timestamp": "last_edited_time", "direction": "descending"}},
+	}
+
+	if !since.IsZero() {
+		body["filter"] = map[string]any{
+			"timestamp":        "last_edited_time",
+			"last_edited_time": map[string]string{"on_or_after": since.UTC().Format(time.RFC3339)},
+		}
+	}
+
+	var pages []page
+
+	for cursor := ""; len(pages) < limit; {
+		if cursor != "" {
+			body["start_cursor"] = cursor
+		}
+
+		var resp listResponse[page]
+		if err := c.do(http.MethodPost, "/databases/"+url.PathEscape(databaseID)+"/query", body, &resp); err != nil {
+			return nil, err
+		}
+
+		pages = append(pages, resp.Results...)
+
+		if !resp.HasMore || resp.NextCursor == "" {
+			break
+		}
+
+		cursor = resp.NextCursor
+	}
+
+	if len(pages) > limit {
+		pages = pages[:limit]
+	}
+
+	return pages, nil
+}
+
+// GetPage returns a single page.
+func (c *Client) GetPage(pageID string) (*page, error) {
+	var p page
+	if err := c.do(http.MethodGet, "/pages/"+url.PathEscape(pageID), nil, &p); err != nil {
+		return nil, err
+	}
+
+	return &p, nil
+}
+
+// BlockTree returns the blocks of a page or block with their nested children.
+// Child pages and databases are not descended into.
+func (c *Client) BlockTree(blockID string) ([]*block, error) {
+	var blocks []*block
+
+	for cursor := ""; ; {
+		path := fmt.Sprintf("/blocks/%s/children?page_size=%d", url.PathEscape(blockID), pageSize)
+		if cursor != "" {
+			path += "&start_cursor=" + url.QueryEscape(cursor)
+		}
+
+		var resp listResponse[*block]
+		if err := c.do(http.MethodGet, path, nil, &resp); err != nil {
+			return nil, err
+		}
+
+		blocks = append(blocks, resp.Results...)
+
+		if !resp.HasMore || resp.NextCursor == "" {
+			break
+		}
+
+		cursor = resp.NextCursor
+	}
+
+	for _, b := range blocks {
+		if !b.HasChildren || b.Type == "child_page" || b.Type == "child_database" {
+			continue
+		}
+
+		children, err := c.BlockTree(b.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		b.children = children
+	}
+
+	return blocks, nil
+}
+
+// do sends a request with an optional JSON body and decodes the JSON response
+// into out.
+func (c *Client) do(method, path string, body any, out any) error {
+	var reader io.Reader
+
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("failed to encode request: %w", err)
+		}
+
+		reader = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequest(method, strings.TrimRight(c.baseURL, "/")+path, reader)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Authorization", "Bearer "+c.token)
+	req.Header.Set("Notion-Version", apiVersion)
+	req.Header.Set("Accept", "application/json")
+
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	if c.requestDelay > 0 {
+		time.Sleep(c.requestDelay)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("request failed: %w", err)
+	}
+
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	switch {
+	case resp.StatusCode == http.StatusUnauthorized:
+		return fmt.Errorf("notion rejected the integration token (HTTP 401): check integration_token")
+	case resp.StatusCode == http.StatusNotFound:
+		return fmt.Errorf("notion object not found (HTTP 404): share the page or database with the integration")
+	case resp.StatusCode != http.StatusOK:
+		return fmt.Errorf("notion API returned HTTP %d: %s", resp.StatusCode, string(data))
+	}
+
+	if err := json.Unmarshal(data, out); err != nil {
+		return fmt.Errorf("failed to parse Notion response: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,264 @@
+package notion
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"pkm-sync/pkg/models"
+)
+
+const (
+	// SourceType is the config type of the Notion source.
+	SourceType = "notion"
+	// itemTypeNote is the item type of a synced page.
+	itemTypeNote = "note"
+)
+
+// pageToItem converts a page and its block tree to an item.
+func pageToItem(p *page, blocks []*block) models.FullItem {
+	title := pageTitle(p)
+
+	item := &models.BasicItem{
+		ID:         "notion_" + strings.ReplaceAll(p.ID, "-", ""),
+		Title:      title,
+		Content:    strings.TrimSpace(renderBlocks(blocks, 0)),
+		SourceType: SourceType,
+		ItemType:   itemTypeNote,
+		CreatedAt:  p.CreatedTime,
+		UpdatedAt:  p.LastEditedTime,
+		Tags:       make([]string, 0),
+		Metadata:   map[string]any{"page_id": p.ID},
+		Links:      make([]models.Link, 0),
+	}
+
+	if p.Archived || p.InTrash {
+		item.Metadata["archived"] = true
+	}
+
+	if p.URL != "" {
+		item.Links = append(item.Links, models.Link{URL: p.URL, Title: title, Type: "external"})
+	}
+
+	return item
+}
+
+// pageTitle returns the plain text of the page's title property.
+func pageTitle(p *page) string {
+	for _, raw := range p.Properties {
+		var prop struct {
+			Type  string     `json:"type"`
+			Title []richText `json:"title"`
+		}
+
+		if err := json.Unmarshal(raw, &prop); err == nil && prop.Type == "title" {
+			if title := strings.TrimSpace(plainText(prop.Title)); title != "" {
+				return title
+			}
+		}
+	}
+
+	return "Untitled"
+}
+
+// blockPayload is the union of the type-specific block fields rendered here.
+type blockPayload struct {
+	RichText   []richText   `json:"rich_text"`
+	Checked    bool         `json:"checked"`
+	Language   string       `json:"language"`
+	Title      string       `json:"title"`
+	URL        string       `json:"url"`
+	Caption    []richText   `json:"caption"`
+	Cells      [][]richText `json:"cells"`
+	Expression string       `json:"expression"`
+	External   struct {
+		URL string `json:"url"`
+	} `json:"external"`
+	File struct {
+		URL string `json:"url"`
+	} `json:"file"`
+}
+
+// renderBlocks renders blocks as Markdown, indenting nested list items and
+// toggle contents by depth. Unsupported block types are skipped.
+func renderBlocks(blocks []*block, depth int) string {
+	var sb strings.Builder
+
+	indent := strings.Repeat("  ", depth)
+	number := 0
+
+	for i, b := range blocks {
+		var p blockPayload
+		if len(b.payload) > 0 {
+			_ = json.Unmarshal(b.payload, &p)
+		}
+
+		if b.Type == "numbered_list_item" {
+			number++
+		} else {
+			number = 0
+		}
+
+		text := markdownText(p.RichText)
+
+		switch b.Type {
+		case "paragraph":
+			sb.WriteString(indent + text + "\n\n")
+		case "heading_1", "heading_2", "heading_3":
+			level := int(b.Type[len(b.Type)-1] - '0')
+			sb.WriteString(strings.Repeat("#", level) + " " + text + "\n\n")
+		case "bulleted_list_item", "toggle":
+			sb.WriteString(indent + "- " + text + "\n")
+		case "numbered_list_item":
+			fmt.Fprintf(&sb, "%s%d. %s\n", indent, number, text)
+		case "to_do":
+			box := "[ ]"
+			if p.Checked {
+				box = "[x]"
+			}
+
+			sb.WriteString(indent + "- " + box + " " + text + "\n")
+		case "quote", "callout":
+			sb.WriteString(indent + "> " + strings.ReplaceAll(text, "\n", "\n"+indent+"> ") + "\n\n")
+		case "code":
+			language := p.Language
+			if language == "plain text" {
+				language = ""
+			}
+
+			sb.WriteString("```" + language + "\n" + plainText(p.RichText) + "\n```\n\n")
+		case "equation":
+			sb.WriteString("$$" + p.Expression + "$$\n\n")
+		case "divider":
+			sb.WriteString("---\n\n")
+		case "child_page", "child_database":
+			sb.WriteString(indent + "- " + p.Title + "\n")
+		case "bookmark", "embed", "link_preview":
+			label := markdownText(p.Caption)
+			if label == "" {
+				label = p.URL
+			}
+
+			sb.WriteString(indent + "[" + label + "](" + p.URL + ")\n\n")
+		case "image", "file", "pdf", "video", "audio":
+			sb.WriteString(indent + renderFile(b.Type, p) + "\n\n")
+		case "table":
+			sb.WriteString(renderTable(b.children) + "\n")
+
+			continue
+		}
+
+		if len(b.children) > 0 {
+			sb.WriteString(renderBlocks(b.children, depth+1))
+		}
+
+		// A list ends with a blank line; nested lists end with their parent.
+		if depth == 0 && isListBlock(b.Type) && (i+1 == len(blocks) || !isListBlock(blocks[i+1].Type)) {
+			sb.WriteString("\n")
+		}
+	}
+
+	return sb.String()
+}
+
+func isListBlock(blockType string) bool {
+	switch blockType {
+	case "bulleted_list_item", "numbered_list_item", "to_do", "toggle", "child_page", "child_database":
+		return true
+	}
+
+	return false
+}
+
+// renderFile links an uploaded or external file; images are embedded.
+func renderFile(blockType string, p blockPayload) string {
+	link := p.File.URL
+	if link == "" {
+		link = p.External.URL
+	}
+
+	label := markdownText(p.Caption)
+	if label == "" {
+		label = blockType
+	}
+
+	if blockType == "image" {
+		return "![" + label + "](" + link + ")"
+	}
+
+	return "[" + label + "](" + link + ")"
+}
+
+// renderTable renders table_row children as a GFM table. The first row is the
+// header row GFM requires, whether or not Notion marks it as one.
+func renderTable(rows []*block) string {
+	var sb strings.Builder
+
+	for i, row := range rows {
+		var p blockPayload
+		if len(row.payload) > 0 {
+			_ = json.Unmarshal(row.payload, &p)
+		}
+
+		cells := make([]string, len(p.Cells))
+		for j, cell := range p.Cells {
+			cells[j] = strings.ReplaceAll(markdownText(cell), "|", `\|`)
+		}
+
+		sb.WriteString("| " + strings.Join(cells, " | ") + " |\n")
+
+		if i == 0 {
+			sb.WriteString("|" + strings.Repeat(" --- |", len(cells)) + "\n")
+		}
+	}
+
+	return sb.String()
+}
+
+// markdownText renders rich text with its annotations and links.
+func markdownText(runs []richText) string {
+	var sb strings.Builder
+
+	for _, run := range runs {
+		text := run.PlainText
+		if text == "" {
+			continue
+		}
+
+		switch {
+		case run.Annotations.Code:
+			text = "`" + text + "`"
+		default:
+			if run.Annotations.Bold {
+				text = "**" + text + "**"
+			}
+
+			if run.Annotations.Italic {
+				text = "*" + text + "*"
+			}
+
+			if run.Annotations.Strikethrough {
+				text = "~~" + text + "~~"
+			}
+		}
+
+		if run.Href != "" {
+			text = "[" + text + "](" + run.Href + ")"
+		}
+
+		sb.WriteString(text)
+	}
+
+	return sb.String()
+}
+
+// plainText joins rich text runs without formatting.
+func plainText(runs []richText) string {
+	var sb strings.Builder
+
+	for _, run := range runs {
+		sb.WriteString(run.PlainText)
+	}
+
+	return sb.String()
+}
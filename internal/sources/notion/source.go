@@ -0,0 +1,165 @@
+// Package notion implements a source that syncs Notion pages, from databases
+// and individually listed pages, as Markdown notes.
+package notion
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"sort"
+	"time"
+
+	"pkm-sync/internal/httpclient"
+	"pkm-sync/pkg/interfaces"
+	"pkm-sync/pkg/models"
+)
+
+// tokenEnvVar supplies the integration token when the config has none.
+const tokenEnvVar = "NOTION_TOKEN"
+
+// NotionSource implements interfaces.Source for Notion.
+type NotionSource struct {
+	sourceID       string
+	cfg            models.NotionSourceConfig
+	client         *Client
+	transport      *httpclient.Transport
+	requestTimeout time.Duration
+}
+
+// NewNotionSource creates a new NotionSource from a SourceConfig.
+func NewNotionSource(sourceID string, sourceCfg models.SourceConfig) *NotionSource {
+	return &NotionSource{
+		sourceID:       sourceID,
+		cfg:            sourceCfg.Notion,
+		requestTimeout: sourceCfg.RequestTimeout,
+	}
+}
+
+// Name implements interfaces.Source.
+func (s *NotionSource) Name() string {
+	return s.sourceID
+}
+
+// SupportsRealtime implements interfaces.Source.
+func (s *NotionSource) SupportsRealtime() bool {
+	return false
+}
+
+// Capabilities implements interfaces.Source. Pages are filtered on their
+// last edited time.
+func (s *NotionSource) Capabilities() interfaces.SourceCapabilities {
+	return interfaces.SourceCapabilities{SupportsIncremental: true}
+}
+
+// Configure implements interfaces.Source. The integration token comes from
+// integration_token, else the NOTION_TOKEN environment variable.
+func (s *NotionSource) Configure(_ map[string]any, _ *http.Client) error {
+	token := s.cfg.IntegrationToken
+	if token == "" {
+		token = os.Getenv(tokenEnvVar)
+	}
+
+	if token == "" {
+		return fmt.Errorf("no Notion integration token for %s: set integration_token or %s", s.sourceID, tokenEnvVar)
+	}
+
+	s.client = NewClient(token, s.cfg.RequestDelay)
+	s.client.httpClient, s.transport = httpclient.NewClient(
+		&http.Client{}, httpclient.ResolveTimeout(s.requestTimeout, defaultRequestTimeout),
+	)
+
+	return nil
+}
+
+// FetchContext implements interfaces.ContextSource by binding ctx to the API
+// client's transport for the duration of the fetch.
+func (s *NotionSource) FetchContext(ctx context.Context, since time.Time, limit int) ([]models.FullItem, error) {
+	if s.transport != nil {
+		s.transport.Bind(ctx)
+		defer s.transport.Bind(nil)
+	}
+
+	return s.Fetch(since, limit)
+}
+
+// Fetch implements interfaces.Source. It returns the pages of the configured
+// databases and the configured pages edited at or after since, newest first.
+// Child pages are listed in their parent's content but not synced themselves.
+func (s *NotionSource) Fetch(since time.Time, limit int) ([]models.FullItem, error) {
+	if s.client == nil {
+		return nil, fmt.Errorf("notion source %s is not configured", s.sourceID)
+	}
+
+	var pages []*page
+
+	seen := make(map[string]bool)
+	add := func(p *page) {
+		if seen[p.ID] || !s.wanted(p, since) {
+			return
+		}
+
+		seen[p.ID] = true
+		pages = append(pages, p)
+	}
+
+	for _, databaseID := range s.cfg.DatabaseIDs {
+		results, err := s.client.QueryDatabase(databaseID, since, limit)
+		if err != nil {
+			return nil, fmt.Errorf("failed to query database %s: %w", databaseID, err)
+		}
+
+		for i := range results {
+			add(&results[i])
+		}
+	}
+
+	for _, pageID := range s.cfg.PageIDs {
+		p, err := s.client.GetPage(pageID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch page %s: %w", pageID, err)
+		}
+
+		add(p)
+	}
+
+	sort.SliceStable(pages, func(i, j int) bool {
+		return pages[i].LastEditedTime.After(pages[j].LastEditedTime)
+	})
+
+	if limit > 0 && len(pages) > limit {
+		pages = pages[:limit]
+	}
+
+	items := make([]models.FullItem, 0, len(pages))
+
+	for _, p := range pages {
+		blocks, err := s.client.BlockTree(p.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch content of page %s: %w", p.ID, err)
+		}
+
+		items = append(items, pageToItem(p, blocks))
+	}
+
+	slog.Debug("Fetched Notion pages", "source", s.sourceID, "pages", len(items))
+
+	return items, nil
+}
+
+// wanted reports whether a page is synced: edited at or after since, and not
+// archived or trashed unless include_archived is set.
+func (s *NotionSource) wanted(p *page, since time.Time) bool {
+	if (p.Archived || p.InTrash) && !s.cfg.IncludeArchived {
+		return false
+	}
+
+	return since.IsZero() || !p.LastEditedTime.Before(since)
+}
+
+// Ensure interface compliance.
+var (
+	_ interfaces.Source        = (*NotionSource)(nil)
+	_ interfaces.ContextSource = (*NotionSource)(nil)
+)
@@ -0,0 +1,186 @@
+package notion
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"pkm-sync/pkg/models"
+)
+
+const (
+	testDatabaseID = "db1"
+	testPageURL    = "https://www.notion.so/Weekly-sync-1111"
+)
+
+// fakeNotion serves one database with a current page and an archived page,
+// and the block tree of the current page.
+func fakeNotion(t *testing.T, queries *[]map[string]any) *httptest.Server {
+	t.Helper()
+
+	pageJSON := func(id, title, edited string, archived bool) map[string]any {
+		return map[string]any{
+			"id": id, "url": testPageURL, "archived": archived,
+			"created_time": "2026-10-01T09:00:00.000Z", "last_edited_time": edited,
+			"properties": map[string]any{
+				"Name": map[string]any{"type": "title", "title": []any{map[string]any{"plain_text": title}}},
+				"Tags": map[string]any{"type": "multi_select"},
+			},
+		}
+	}
+
+	text := func(s string, bold bool) map[string]any {
+		return map[string]any{"plain_text": s, "annotations": map[string]any{"bold": bold}}
+	}
+
+	blocks := map[string][]any{
+		"1111-aaaa": {
+			map[string]any{"id": "b1", "type": "heading_2",
+				"heading_2": map[string]any{"rich_text": []any{text("Agenda", false)}}},
+			map[string]any{"id": "b2", "type": "bulleted_list_item", "has_children": true,
+				"bulleted_list_item": map[string]any{"rich_text": []any{text("Roadmap", true)}}},
+			map[string]any{"id": "b3", "type": "to_do",
+				"to_do": map[string]any{"rich_text": []any{text("Send notes", false)}, "checked": true}},
+			map[string]any{"id": "b4", "type": "code",
+				"code": map[string]any{"rich_text": []any{text("make test", false)}, "language": "shell"}},
+			map[string]any{"id": "b5", "type": "child_page", "has_children": true,
+				"child_page": map[string]any{"title": "Appendix"}},
+		},
+		"b2": {
+			map[string]any{"id": "b6", "type": "bulleted_list_item",
+				"bulleted_list_item": map[string]any{"rich_text": []any{text("Q4 goals", false)}}},
+		},
+	}
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer secret" || r.Header.Get("Notion-Version") != apiVersion {
+			w.WriteHeader(http.StatusUnauthorized)
+
+			return
+		}
+
+		var resp any
+
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/databases/"+testDatabaseID+"/query":
+			body, _ := io.ReadAll(r.Body)
+
+			var query map[string]any
+			_ = json.Unmarshal(body, &query)
+			*queries = append(*queries, query)
+
+			resp = map[string]any{"results": []any{
+				pageJSON("1111-aaaa", "Weekly sync", "2026-10-10T12:00:00.000Z", false),
+				pageJSON("2222-bbbb", "Old plan", "2026-10-09T12:00:00.000Z", true),
+			}}
+		case strings.HasPrefix(r.URL.Path, "/blocks/"):
+			id := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/blocks/"), "/children")
+			if id == "b5" {
+				t.Errorf("child pages must not be descended into")
+			}
+
+			resp = map[string]any{"results": blocks[id]}
+		default:
+			w.WriteHeader(http.StatusNotFound)
+
+			return
+		}
+
+		_ = json.NewEncoder(w).Encode(resp)
+	}))
+}
+
+func newTestSource(t *testing.T, server *httptest.Server, cfg models.NotionSourceConfig) *NotionSource {
+	t.Helper()
+
+	cfg.IntegrationToken = "secret"
+	cfg.RequestDelay = time.Nanosecond
+
+	source := NewNotionSource("notes", models.SourceConfig{Type: SourceType, Notion: cfg})
+	if err := source.Configure(nil, nil); err != nil {
+		t.Fatalf("Configure failed: %v", err)
+	}
+
+	source.client.baseURL = server.URL
+
+	return source
+}
+
+func TestNotionSource_FetchDatabase(t *testing.T) {
+	var queries []map[string]any
+
+	server := fakeNotion(t, &queries)
+	defer server.Close()
+
+	source := newTestSource(t, server, models.NotionSourceConfig{DatabaseIDs: []string{testDatabaseID}})
+	since := time.Date(2026, 10, 5, 0, 0, 0, 0, time.UTC)
+
+	items, err := source.Fetch(since, 10)
+	if err != nil {
+		t.Fatalf("Fetch failed: %v", err)
+	}
+
+	if len(items) != 1 {
+		t.Fatalf("Expected the archived page to be skipped, got %d items", len(items))
+	}
+
+	filter, _ := queries[0]["filter"].(map[string]any)
+	edited, _ := filter["last_edited_time"].(map[string]any)
+
+	if edited["on_or_after"] != "2026-10-05T00:00:00Z" {
+		t.Errorf("Expected the query to filter on since, got %v", queries[0]["filter"])
+	}
+
+	item := items[0]
+	if item.GetTitle() != "Weekly sync" || item.GetSourceType() != "notion" || item.GetItemType() != "note" {
+		t.Errorf("Unexpected item %q (%s/%s)", item.GetTitle(), item.GetSourceType(), item.GetItemType())
+	}
+
+	want := "## Agenda\n\n- **Roadmap**\n  - Q4 goals\n- [x] Send notes\n\n```shell\nmake test\n```\n\n- Appendix"
+	if item.GetContent() != want {
+		t.Errorf("Unexpected content:\n%s\nwant:\n%s", item.GetContent(), want)
+	}
+
+	links := item.GetLinks()
+	if len(links) != 1 || links[0].URL != testPageURL || links[0].Title != "Weekly sync" {
+		t.Errorf("Expected the page URL as a link, got %v", links)
+	}
+}
+
+func TestNotionSource_IncludeArchived(t *testing.T) {
+	var queries []map[string]any
+
+	server := fakeNotion(t, &queries)
+	defer server.Close()
+
+	source := newTestSource(t, server, models.NotionSourceConfig{
+		DatabaseIDs:     []string{testDatabaseID},
+		IncludeArchived: true,
+	})
+
+	items, err := source.Fetch(time.Time{}, 10)
+	if err != nil {
+		t.Fatalf("Fetch failed: %v", err)
+	}
+
+	if len(items) != 2 || items[1].GetMetadata()["archived"] != true {
+		t.Errorf("Expected the archived page marked archived, got %d items", len(items))
+	}
+
+	if _, ok := queries[0]["filter"]; ok {
+		t.Error("Expected no filter without since")
+	}
+}
+
+func TestNotionSource_ConfigureRequiresToken(t *testing.T) {
+	t.Setenv(tokenEnvVar, "")
+
+	source := NewNotionSource("notes", models.SourceConfig{Type: SourceType})
+	if err := source.Configure(nil, nil); err == nil {
+		t.Error("Expected Configure to fail without a token")
+	}
+}
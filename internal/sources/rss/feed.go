@@ -0,0 +1,208 @@
+package rss
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"strings"
+	"time"
+
+	"golang.org/x/net/html/charset"
+)
+
+// entry is one post of an RSS 2.0 or Atom feed.
+type entry struct {
+	ID         string
+	Title      string
+	Link       string
+	Author     string
+	Body       string // HTML or text: full content when the feed has it, else the summary
+	Categories []string
+	Published  time.Time
+	Updated    time.Time
+}
+
+// feed is a parsed RSS 2.0 or Atom feed.
+type feed struct {
+	Title   string
+	Entries []entry
+}
+
+// xmlFeed decodes both formats: RSS puts items under <rss><channel>, Atom
+// puts entries directly under <feed>.
+type xmlFeed struct {
+	XMLName xml.Name
+	Channel struct {
+		Title string    `xml:"title"`
+		Items []rssItem `xml:"item"`
+	} `xml:"channel"`
+	Title   string      `xml:"title"`
+	Entries []atomEntry `xml:"entry"`
+}
+
+type rssItem struct {
+	GUID        string   `xml:"guid"`
+	Title       string   `xml:"title"`
+	Link        string   `xml:"link"`
+	Author      string   `xml:"author"`
+	Creator     string   `xml:"http://purl.org/dc/elements/1.1/ creator"`
+	Description string   `xml:"description"`
+	Encoded     string   `xml:"http://purl.org/rss/1.0/modules/content/ encoded"`
+	Categories  []string `xml:"category"`
+	PubDate     string   `xml:"pubDate"`
+	DCDate      string   `xml:"http://purl.org/dc/elements/1.1/ date"`
+}
+
+type atomEntry struct {
+	ID        string `xml:"id"`
+	Title     string `xml:"title"`
+	Published string `xml:"published"`
+	Updated   string `xml:"updated"`
+	Author    struct {
+		Name string `xml:"name"`
+	} `xml:"author"`
+	Links []struct {
+		Href string `xml:"href,attr"`
+		Rel  string `xml:"rel,attr"`
+	} `xml:"link"`
+	Content    string `xml:"content"`
+	Summary    string `xml:"summary"`
+	Categories []struct {
+		Term string `xml:"term,attr"`
+	} `xml:"category"`
+}
+
+// parseFeed parses an RSS 2.0 or Atom document.
+func parseFeed(data []byte) (*feed, error) {
+	var doc xmlFeed
+
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	decoder.Strict = false
+	decoder.Entity = xml.HTMLEntity
+	decoder.CharsetReader = charset.NewReaderLabel
+
+	if err := decoder.Decode(&doc); err != nil {
+		return nil, fmt.Errorf("failed to parse feed: %w", err)
+	}
+
+	switch doc.XMLName.Local {
+	case "rss":
+		return rssFeed(doc), nil
+	case "feed":
+		return atomFeed(doc), nil
+	default:
+		return nil, fmt.Errorf("unsupported feed document <%s> (want RSS 2.0 or Atom)", doc.XMLName.Local)
+	}
+}
+
+func rssFeed(doc xmlFeed) *feed {
+	f := &feed{Title: strings.TrimSpace(doc.Channel.Title)}
+
+	for _, item := range doc.Channel.Items {
+		published := parseFeedTime(item.PubDate)
+		if published.IsZero() {
+			published = parseFeedTime(item.DCDate)
+		}
+
+		e := entry{
+			ID:         strings.TrimSpace(firstNonEmpty(item.GUID, item.Link, item.Title)),
+			Title:      strings.TrimSpace(item.Title),
+			Link:       strings.TrimSpace(item.Link),
+			Author:     strings.TrimSpace(firstNonEmpty(item.Creator, item.Author)),
+			Body:       firstNonEmpty(item.Encoded, item.Description),
+			Categories: item.Categories,
+			Published:  published,
+			Updated:    published,
+		}
+
+		f.Entries = append(f.Entries, e)
+	}
+
+	return f
+}
+
+func atomFeed(doc xmlFeed) *feed {
+	f := &feed{Title: strings.TrimSpace(doc.Title)}
+
+	for _, item := range doc.Entries {
+		e := entry{
+			ID:        strings.TrimSpace(item.ID),
+			Title:     strings.TrimSpace(item.Title),
+			Author:    strings.TrimSpace(item.Author.Name),
+			Body:      firstNonEmpty(item.Content, item.Summary),
+			Published: parseFeedTime(item.Published),
+			Updated:   parseFeedTime(item.Updated),
+		}
+
+		// The alternate link (rel absent or "alternate") is the post itself.
+		for _, link := range item.Links {
+			if link.Rel == "" || link.Rel == "alternate" {
+				e.Link = strings.TrimSpace(link.Href)
+
+				break
+			}
+		}
+
+		for _, category := range item.Categories {
+			e.Categories = append(e.Categories, category.Term)
+		}
+
+		if e.Published.IsZero() {
+			e.Published = e.Updated
+		}
+
+		if e.Updated.IsZero() {
+			e.Updated = e.Published
+		}
+
+		if e.ID == "" {
+			e.ID = firstNonEmpty(e.Link, e.Title)
+		}
+
+		f.Entries = append(f.Entries, e)
+	}
+
+	return f
+}
+
+// feedTimeLayouts are the date formats seen in the wild: RFC 822/1123
+// variants in RSS, RFC 3339 in Atom and Dublin Core.
+var feedTimeLayouts = []string{
+	time.RFC3339,
+	time.RFC1123Z,
+	time.RFC1123,
+	"Mon, 2 Jan 2006 15:04:05 -0700",
+	"Mon, 2 Jan 2006 15:04:05 MST",
+	"2 Jan 2006 15:04:05 -0700",
+	time.RFC822Z,
+	time.RFC822,
+	"2006-01-02T15:04:05",
+	time.DateOnly,
+}
+
+// parseFeedTime parses a feed date, returning the zero time when no layout
+// matches.
+func parseFeedTime(s string) time.Time {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return time.Time{}
+	}
+
+	for _, layout := range feedTimeLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t
+		}
+	}
+
+	return time.Time{}
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if strings.TrimSpace(v) != "" {
+			return v
+		}
+	}
+
+	return ""
+}
@@ -0,0 +1,302 @@
+// Package rss implements a source that syncs the posts of RSS 2.0 and Atom
+// feeds (newsletters, engineering blogs) as articles.
+package rss
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"pkm-sync/internal/httpclient"
+	"pkm-sync/pkg/interfaces"
+	"pkm-sync/pkg/models"
+
+	mdconverter "github.com/JohannesKaufmann/html-to-markdown/v2"
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+const (
+	// SourceType is the config type of the RSS source.
+	SourceType = "rss"
+	// itemTypeArticle is the item type of a feed post.
+	itemTypeArticle = "article"
+	// defaultRequestTimeout bounds a single feed or article request when no
+	// request_timeout is configured.
+	defaultRequestTimeout = 30 * time.Second
+	// maxResponseBytes bounds a downloaded feed or article.
+	maxResponseBytes = 10 << 20
+)
+
+// RSSSource implements interfaces.Source for RSS and Atom feeds.
+type RSSSource struct {
+	sourceID       string
+	cfg            models.RSSSourceConfig
+	httpClient     *http.Client
+	transport      *httpclient.Transport
+	requestTimeout time.Duration
+}
+
+// NewRSSSource creates a new RSSSource from a SourceConfig.
+func NewRSSSource(sourceID string, sourceCfg models.SourceConfig) *RSSSource {
+	return &RSSSource{
+		sourceID:       sourceID,
+		cfg:            sourceCfg.RSS,
+		requestTimeout: sourceCfg.RequestTimeout,
+	}
+}
+
+// Name implements interfaces.Source.
+func (s *RSSSource) Name() string {
+	return s.sourceID
+}
+
+// SupportsRealtime implements interfaces.Source.
+func (s *RSSSource) SupportsRealtime() bool {
+	return false
+}
+
+// Capabilities implements interfaces.Source. Posts older than since are
+// dropped, though every feed is downloaded in full.
+func (s *RSSSource) Capabilities() interfaces.SourceCapabilities {
+	return interfaces.SourceCapabilities{SupportsIncremental: true}
+}
+
+// Configure implements interfaces.Source.
+func (s *RSSSource) Configure(_ map[string]any, _ *http.Client) error {
+	if len(s.cfg.FeedURLs) == 0 {
+		return fmt.Errorf("rss source %s has no feed_urls", s.sourceID)
+	}
+
+	s.httpClient, s.transport = httpclient.NewClient(
+		&http.Client{}, httpclient.ResolveTimeout(s.requestTimeout, defaultRequestTimeout),
+	)
+
+	return nil
+}
+
+// FetchContext implements interfaces.ContextSource by binding ctx to the HTTP
+// client's transport for the duration of the fetch.
+func (s *RSSSource) FetchContext(ctx context.Context, since time.Time, limit int) ([]models.FullItem, error) {
+	if s.transport != nil {
+		s.transport.Bind(ctx)
+		defer s.transport.Bind(nil)
+	}
+
+	return s.Fetch(since, limit)
+}
+
+// Fetch implements interfaces.Source. It returns the posts of every feed
+// published at or after since, newest first per feed, at most
+// max_items_per_feed per feed. A feed that fails is skipped with a warning
+// unless every feed fails.
+func (s *RSSSource) Fetch(since time.Time, limit int) ([]models.FullItem, error) {
+	if s.httpClient == nil {
+		return nil, fmt.Errorf("rss source %s is not configured", s.sourceID)
+	}
+
+	var (
+		items   []models.FullItem
+		lastErr error
+		failed  int
+	)
+
+	for _, feedURL := range s.cfg.FeedURLs {
+		feedItems, err := s.fetchFeed(feedURL, since)
+		if err != nil {
+			slog.Warn("Skipping feed", "source", s.sourceID, "feed", feedURL, "error", err)
+
+			lastErr = err
+			failed++
+
+			continue
+		}
+
+		items = append(items, feedItems...)
+
+		if limit > 0 && len(items) >= limit {
+			items = items[:limit]
+
+			break
+		}
+	}
+
+	if failed == len(s.cfg.FeedURLs) && lastErr != nil {
+		return nil, fmt.Errorf("all feeds failed: %w", lastErr)
+	}
+
+	return items, nil
+}
+
+// fetchFeed downloads and converts one feed.
+func (s *RSSSource) fetchFeed(feedURL string, since time.Time) ([]models.FullItem, error) {
+	data, err := s.get(feedURL)
+	if err != nil {
+		return nil, err
+	}
+
+	f, err := parseFeed(data)
+	if err != nil {
+		return nil, err
+	}
+
+	var items []models.FullItem
+
+	for _, e := range f.Entries {
+		if !since.IsZero() && !e.Published.IsZero() && e.Published.Before(since) {
+			continue
+		}
+
+		if s.cfg.MaxItemsPerFeed > 0 && len(items) >= s.cfg.MaxItemsPerFeed {
+			break
+		}
+
+		body := e.Body
+
+		if s.cfg.FetchFullContent && e.Link != "" {
+			if page, err := s.get(e.Link); err != nil {
+				slog.Warn("Keeping feed summary; article download failed", "link", e.Link, "error", err)
+			} else {
+				body = articleHTML(page)
+			}
+		}
+
+		items = append(items, entryToItem(e, f.Title, feedURL, body))
+	}
+
+	return items, nil
+}
+
+// get downloads url, failing on non-2xx responses.
+func (s *RSSSource) get(url string) ([]byte, error) {
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("User-Agent", "pkm-sync")
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("%s returned HTTP %d", url, resp.StatusCode)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseBytes))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", url, err)
+	}
+
+	return data, nil
+}
+
+// entryToItem converts a feed entry to an article item, converting an HTML
+// body to Markdown.
+func entryToItem(e entry, feedTitle, feedURL, body string) models.FullItem {
+	sum := sha256.Sum256([]byte(feedURL + "\x00" + e.ID))
+
+	title := e.Title
+	if title == "" {
+		title = "Untitled post"
+	}
+
+	item := &models.BasicItem{
+		ID:         "rss_" + hex.EncodeToString(sum[:8]),
+		Title:      title,
+		Content:    toMarkdown(body),
+		SourceType: SourceType,
+		ItemType:   itemTypeArticle,
+		CreatedAt:  e.Published,
+		UpdatedAt:  e.Updated,
+		Tags:       make([]string, 0),
+		Metadata: map[string]any{
+			"feed_title": feedTitle,
+			"feed_url":   feedURL,
+			"entry_id":   e.ID,
+		},
+		Links: make([]models.Link, 0),
+	}
+
+	if e.Author != "" {
+		item.Metadata["author"] = e.Author
+	}
+
+	if len(e.Categories) > 0 {
+		item.Metadata["categories"] = e.Categories
+	}
+
+	if e.Link != "" {
+		item.Links = append(item.Links, models.Link{URL: e.Link, Title: title, Type: "external"})
+	}
+
+	return item
+}
+
+// toMarkdown converts an HTML body to Markdown; plain text and bodies the
+// converter rejects are kept as they are.
+func toMarkdown(body string) string {
+	body = strings.TrimSpace(body)
+	if !strings.Contains(body, "<") {
+		return body
+	}
+
+	markdown, err := mdconverter.ConvertString(body)
+	if err != nil {
+		return body
+	}
+
+	return strings.TrimSpace(markdown)
+}
+
+// articleHTML returns the main content of an article page: its <article>
+// element, else <main>, else <body>, so navigation and footers are left out
+// where the page marks them.
+func articleHTML(page []byte) string {
+	doc, err := html.Parse(bytes.NewReader(page))
+	if err != nil {
+		return string(page)
+	}
+
+	for _, tag := range []atom.Atom{atom.Article, atom.Main, atom.Body} {
+		if n := findElement(doc, tag); n != nil {
+			var buf bytes.Buffer
+			if err := html.Render(&buf, n); err == nil {
+				return buf.String()
+			}
+		}
+	}
+
+	return string(page)
+}
+
+func findElement(n *html.Node, tag atom.Atom) *html.Node {
+	if n.Type == html.ElementNode && n.DataAtom == tag {
+		return n
+	}
+
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if found := findElement(c, tag); found != nil {
+			return found
+		}
+	}
+
+	return nil
+}
+
+// Ensure interface compliance.
+var (
+	_ interfaces.Source        = (*RSSSource)(nil)
+	_ interfaces.ContextSource = (*RSSSource)(nil)
+)
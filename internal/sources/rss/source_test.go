@@ -0,0 +1,198 @@
+package rss
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"pkm-sync/pkg/models"
+)
+
+const testRSSFeed = `<?xml version="1.0" encoding="UTF-8"?>
+<rss version="2.0" xmlns:dc="http://purl.org/dc/elements/1.1/">
+<channel>
+  <title>Eng Blog</title>
+  <item>
+    <title>Scaling the queue</title>
+    <link>{{base}}/posts/queue</link>
+    <guid>post-2</guid>
+    <dc:creator>Sam Lee</dc:creator>
+    <category>infra</category>
+    <pubDate>Tue, 06 Oct 2026 10:00:00 +0000</pubDate>
+    <description>&lt;p&gt;We &lt;strong&gt;sharded&lt;/strong&gt; it.&lt;/p&gt;</description>
+  </item>
+  <item>
+    <title>Old news</title>
+    <link>{{base}}/posts/old</link>
+    <guid>post-1</guid>
+    <pubDate>Mon, 01 Jun 2026 10:00:00 +0000</pubDate>
+    <description>Stale.</description>
+  </item>
+</channel>
+</rss>`
+
+const testAtomFeed = `<?xml version="1.0" encoding="utf-8"?>
+<feed xmlns="http://www.w3.org/2005/Atom">
+  <title>Newsletter</title>
+  <entry>
+    <title>Issue 42</title>
+    <id>urn:issue:42</id>
+    <link rel="alternate" href="{{base}}/issues/42"/>
+    <link rel="self" href="{{base}}/issues/42.xml"/>
+    <author><name>Ada</name></author>
+    <published>2026-10-05T08:00:00Z</published>
+    <updated>2026-10-05T09:00:00Z</updated>
+    <summary>Short summary.</summary>
+  </entry>
+</feed>`
+
+const testArticlePage = `<html><body><nav>Home | About</nav>
+<article><h1>Scaling the queue</h1><p>The full story.</p></article>
+<footer>(c) Eng</footer></body></html>`
+
+func fakeFeeds(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	var srv *httptest.Server
+
+	srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		serve := func(body string) {
+			_, _ = w.Write([]byte(strings.ReplaceAll(body, "{{base}}", srv.URL)))
+		}
+
+		switch r.URL.Path {
+		case "/rss.xml":
+			serve(testRSSFeed)
+		case "/atom.xml":
+			serve(testAtomFeed)
+		case "/posts/queue":
+			serve(testArticlePage)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	return srv
+}
+
+func newTestSource(t *testing.T, cfg models.RSSSourceConfig) *RSSSource {
+	t.Helper()
+
+	s := NewRSSSource("blogs", models.SourceConfig{Type: SourceType, RSS: cfg})
+	if err := s.Configure(nil, nil); err != nil {
+		t.Fatalf("Configure: %v", err)
+	}
+
+	return s
+}
+
+func TestFetch_RSSAndAtom(t *testing.T) {
+	srv := fakeFeeds(t)
+	s := newTestSource(t, models.RSSSourceConfig{FeedURLs: []string{srv.URL + "/rss.xml", srv.URL + "/atom.xml"}})
+
+	items, err := s.Fetch(time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC), 0)
+	if err != nil {
+		t.Fatalf("Fetch: %v", err)
+	}
+
+	if len(items) != 2 {
+		t.Fatalf("expected 2 items (old post skipped), got %d", len(items))
+	}
+
+	post := items[0]
+	if post.GetTitle() != "Scaling the queue" || post.GetItemType() != "article" || post.GetSourceType() != "rss" {
+		t.Errorf("unexpected post: %q %q %q", post.GetTitle(), post.GetItemType(), post.GetSourceType())
+	}
+
+	if !post.GetCreatedAt().Equal(time.Date(2026, 10, 6, 10, 0, 0, 0, time.UTC)) {
+		t.Errorf("CreatedAt = %v", post.GetCreatedAt())
+	}
+
+	if post.GetContent() != "We **sharded** it." {
+		t.Errorf("Content = %q", post.GetContent())
+	}
+
+	if links := post.GetLinks(); len(links) != 1 || links[0].URL != srv.URL+"/posts/queue" {
+		t.Errorf("Links = %+v", links)
+	}
+
+	meta := post.GetMetadata()
+	if meta["feed_title"] != "Eng Blog" || meta["author"] != "Sam Lee" {
+		t.Errorf("Metadata = %v", meta)
+	}
+
+	issue := items[1]
+	if issue.GetTitle() != "Issue 42" || issue.GetContent() != "Short summary." {
+		t.Errorf("unexpected atom entry: %q %q", issue.GetTitle(), issue.GetContent())
+	}
+
+	if links := issue.GetLinks(); len(links) != 1 || links[0].URL != srv.URL+"/issues/42" {
+		t.Errorf("atom Links = %+v", links)
+	}
+
+	if !strings.HasPrefix(post.GetID(), "rss_") || post.GetID() == issue.GetID() {
+		t.Errorf("unexpected IDs %q, %q", post.GetID(), issue.GetID())
+	}
+}
+
+func TestFetch_MaxItemsPerFeed(t *testing.T) {
+	srv := fakeFeeds(t)
+	s := newTestSource(t, models.RSSSourceConfig{FeedURLs: []string{srv.URL + "/rss.xml"}, MaxItemsPerFeed: 1})
+
+	items, err := s.Fetch(time.Time{}, 0)
+	if err != nil {
+		t.Fatalf("Fetch: %v", err)
+	}
+
+	if len(items) != 1 || items[0].GetTitle() != "Scaling the queue" {
+		t.Errorf("expected only the newest post, got %d items", len(items))
+	}
+}
+
+func TestFetch_FullContent(t *testing.T) {
+	srv := fakeFeeds(t)
+	s := newTestSource(t, models.RSSSourceConfig{FeedURLs: []string{srv.URL + "/rss.xml"}, FetchFullContent: true})
+
+	items, err := s.Fetch(time.Time{}, 0)
+	if err != nil {
+		t.Fatalf("Fetch: %v", err)
+	}
+
+	if len(items) != 2 {
+		t.Fatalf("expected 2 items, got %d", len(items))
+	}
+
+	full := items[0].GetContent()
+	if !strings.Contains(full, "The full story.") || strings.Contains(full, "Home | About") {
+		t.Errorf("expected the article body only, got %q", full)
+	}
+
+	// The old post's page 404s, so its feed summary is kept.
+	if items[1].GetContent() != "Stale." {
+		t.Errorf("expected summary fallback, got %q", items[1].GetContent())
+	}
+}
+
+func TestFetch_FailedFeeds(t *testing.T) {
+	srv := fakeFeeds(t)
+
+	s := newTestSource(t, models.RSSSourceConfig{FeedURLs: []string{srv.URL + "/missing.xml", srv.URL + "/atom.xml"}})
+	if items, err := s.Fetch(time.Time{}, 0); err != nil || len(items) != 1 {
+		t.Errorf("expected the working feed to sync, got %d items, err %v", len(items), err)
+	}
+
+	s = newTestSource(t, models.RSSSourceConfig{FeedURLs: []string{srv.URL + "/missing.xml"}})
+	if _, err := s.Fetch(time.Time{}, 0); err == nil {
+		t.Error("expected an error when every feed fails")
+	}
+}
+
+func TestConfigure_RequiresFeeds(t *testing.T) {
+	s := NewRSSSource("blogs", models.SourceConfig{Type: SourceType})
+	if err := s.Configure(nil, nil); err == nil {
+		t.Error("expected an error without feed_urls")
+	}
+}
@@ -8,8 +8,14 @@ import (
 	"net/url"
 	"strings"
 	"time"
+
+	"pkm-sync/internal/httpclient"
 )
 
+// defaultRequestTimeout bounds a single ServiceNow API request when no
+// request_timeout is configured.
+const defaultRequestTimeout = 30 * time.Second
+
 // Client is an HTTP client for the ServiceNow REST Table API.
 type Client struct {
 	gck          string
@@ -29,7 +35,7 @@ func NewClient(gck, cookieHeader, instanceURL string, requestDelay time.Duration
 		gck:          gck,
 		cookieHeader: cookieHeader,
 		instanceURL:  strings.TrimRight(instanceURL, "/"),
-		httpClient:   &http.Client{Timeout: 30 * time.Second},
+		httpClient:   &http.Client{Timeout: defaultRequestTimeout, Transport: httpclient.SharedTransport()},
 		requestDelay: requestDelay,
 	}
 }
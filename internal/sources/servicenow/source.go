@@ -1,11 +1,14 @@
 package servicenow
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"time"
 
 	"pkm-sync/internal/config"
+	"pkm-sync/internal/httpclient"
+	"pkm-sync/pkg/interfaces"
 	"pkm-sync/pkg/models"
 )
 
@@ -31,17 +34,20 @@ var genericFields = []string{
 
 // ServiceNowSource implements interfaces.Source for ServiceNow.
 type ServiceNowSource struct {
-	sourceID  string
-	cfg       models.ServiceNowSourceConfig
-	configDir string
-	client    *Client
+	sourceID       string
+	cfg            models.ServiceNowSourceConfig
+	configDir      string
+	client         *Client
+	transport      *httpclient.Transport
+	requestTimeout time.Duration
 }
 
 // NewServiceNowSource creates a new ServiceNowSource from a SourceConfig.
 func NewServiceNowSource(sourceID string, sourceCfg models.SourceConfig) *ServiceNowSource {
 	return &ServiceNowSource{
-		sourceID: sourceID,
-		cfg:      sourceCfg.ServiceNow,
+		sourceID:       sourceID,
+		cfg:            sourceCfg.ServiceNow,
+		requestTimeout: sourceCfg.RequestTimeout,
 	}
 }
 
@@ -55,6 +61,11 @@ func (s *ServiceNowSource) SupportsRealtime() bool {
 	return false
 }
 
+// Capabilities implements interfaces.Source. Queries filter on sys_updated_on.
+func (s *ServiceNowSource) Capabilities() interfaces.SourceCapabilities {
+	return interfaces.SourceCapabilities{SupportsIncremental: true}
+}
+
 // Configure implements interfaces.Source.
 func (s *ServiceNowSource) Configure(_ map[string]any, _ *http.Client) error {
 	configDir, err := config.GetConfigDir()
@@ -77,10 +88,24 @@ func (s *ServiceNowSource) Configure(_ map[string]any, _ *http.Client) error {
 	}
 
 	s.client = NewClient(td.GCK, td.CookieHeader, s.cfg.InstanceURL, s.cfg.RequestDelay)
+	s.client.httpClient, s.transport = httpclient.NewClient(
+		&http.Client{}, httpclient.ResolveTimeout(s.requestTimeout, defaultRequestTimeout),
+	)
 
 	return nil
 }
 
+// FetchContext implements interfaces.ContextSource by binding ctx to the API
+// client's transport for the duration of the fetch.
+func (s *ServiceNowSource) FetchContext(ctx context.Context, since time.Time, limit int) ([]models.FullItem, error) {
+	if s.transport != nil {
+		s.transport.Bind(ctx)
+		defer s.transport.Bind(nil)
+	}
+
+	return s.Fetch(since, limit)
+}
+
 // Fetch implements interfaces.Source.
 func (s *ServiceNowSource) Fetch(since time.Time, limit int) ([]models.FullItem, error) {
 	tables := s.cfg.Tables
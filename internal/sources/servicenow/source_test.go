@@ -0,0 +1,16 @@
+package servicenow
+
+import (
+	"testing"
+
+	"pkm-sync/pkg/interfaces"
+	"pkm-sync/pkg/models"
+)
+
+func TestServiceNowSource_Capabilities(t *testing.T) {
+	caps := NewServiceNowSource("snow", models.SourceConfig{Type: "servicenow"}).Capabilities()
+
+	if caps != (interfaces.SourceCapabilities{SupportsIncremental: true}) {
+		t.Errorf("unexpected capabilities %+v", caps)
+	}
+}
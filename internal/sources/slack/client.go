@@ -8,6 +8,8 @@ import (
 	"mime/multipart"
 	"net/http"
 	"time"
+
+	"pkm-sync/internal/httpclient"
 )
 
 // SlackChannel represents a Slack channel or DM.
@@ -34,8 +36,13 @@ type RawMessage struct {
 	ThreadTs   string            `json:"thread_ts"`
 	ReplyCount int               `json:"reply_count"`
 	Blocks     []json.RawMessage `json:"blocks"`
+	Files      []RawFile         `json:"files"`
 }
 
+// defaultRequestTimeout bounds a single Slack API request when no
+// request_timeout is configured.
+const defaultRequestTimeout = 30 * time.Second
+
 // Client calls the Slack internal web API.
 type Client struct {
 	token        string
@@ -60,7 +67,7 @@ func NewClient(token, cookieHeader, apiBaseURL string, rateLimitMs int) *Client
 		token:        token,
 		cookieHeader: cookieHeader,
 		apiBaseURL:   apiBaseURL,
-		httpClient:   &http.Client{Timeout: 30 * time.Second},
+		httpClient:   &http.Client{Timeout: defaultRequestTimeout, Transport: httpclient.SharedTransport()},
 		rateLimitMs:  rateLimitMs,
 	}
 }
@@ -337,23 +344,53 @@ func (c *Client) GetHistory(channelID, oldest, latest, cursor string, limit int)
 	return msgs, nextCursor, nil
 }
 
-// GetReplies fetches all replies for a thread.
+// GetReplies fetches all messages of a thread (the parent first, then the
+// replies oldest first), following pagination cursors.
 func (c *Client) GetReplies(channelID, threadTS string) ([]RawMessage, error) {
-	result, err := c.CallAPI("conversations.replies", map[string]string{
-		channelParamKey: channelID,
-		"ts":            threadTS,
-	})
-	if err != nil {
-		return nil, err
-	}
+	var (
+		all    []RawMessage
+		cursor string
+	)
 
-	if ok, _ := result["ok"].(bool); !ok {
-		errMsg, _ := result["error"].(string)
+	for {
+		params := map[string]string{
+			channelParamKey: channelID,
+			"ts":            threadTS,
+		}
 
-		return nil, fmt.Errorf("conversations.replies failed: %s", errMsg)
-	}
+		if cursor != "" {
+			params["cursor"] = cursor
+		}
+
+		result, err := c.CallAPI("conversations.replies", params)
+		if err != nil {
+			return nil, err
+		}
+
+		if ok, _ := result["ok"].(bool); !ok {
+			errMsg, _ := result["error"].(string)
+
+			return nil, fmt.Errorf("conversations.replies failed: %s", errMsg)
+		}
+
+		msgs, err := parseMessages(result["messages"])
+		if err != nil {
+			return nil, err
+		}
+
+		all = append(all, msgs...)
+
+		cursor = ""
+		if meta, ok := result["response_metadata"].(map[string]any); ok {
+			cursor, _ = meta["next_cursor"].(string)
+		}
 
-	return parseMessages(result["messages"])
+		if cursor == "" {
+			return all, nil
+		}
+
+		time.Sleep(time.Duration(c.rateLimitMs) * time.Millisecond)
+	}
 }
 
 // GetUserInfo fetches profile information for a user.
@@ -121,8 +121,17 @@ func FromSlackMessage(
 		}
 	}
 
+	id := fmt.Sprintf("slack_%s_%s", channelID, msg.Ts)
+
+	// A thread's ID is its parent's item ID, so the parent and every reply
+	// share one thread_id; thread_ts alone is only unique within a channel.
+	threadID := id
+	if msg.ThreadTs != "" {
+		threadID = fmt.Sprintf("slack_%s_%s", channelID, msg.ThreadTs)
+	}
+
 	return &models.BasicItem{
-		ID:          fmt.Sprintf("slack_%s_%s", channelID, msg.Ts),
+		ID:          id,
 		Title:       title,
 		Content:     content,
 		SourceType:  sourceTypeSlack,
@@ -139,8 +148,24 @@ func FromSlackMessage(
 			"author":         author,
 			"ts":             msg.Ts,
 			"thread_ts":      threadTs,
+			"thread_id":      threadID,
 			"is_thread_root": isThreadRoot,
 			"reply_count":    msg.ReplyCount,
 		},
 	}
 }
+
+// addThreadModeMetadata records the source's thread mode on the item so the
+// thread_grouping transformer groups Slack threads the way this source was
+// configured, as it does for Gmail.
+func addThreadModeMetadata(item *models.BasicItem, cfg models.SlackSourceConfig) {
+	if cfg.ThreadMode == "" {
+		return
+	}
+
+	item.Metadata["thread_mode"] = cfg.ThreadMode
+
+	if cfg.ThreadSummaryLength > 0 {
+		item.Metadata["thread_summary_length"] = cfg.ThreadSummaryLength
+	}
+}
@@ -8,6 +8,7 @@ import (
 
 	_ "github.com/mattn/go-sqlite3"
 
+	"pkm-sync/pkg/interfaces"
 	"pkm-sync/pkg/models"
 )
 
@@ -46,6 +47,11 @@ func (s *DBSource) Configure(_ map[string]interface{}, _ *http.Client) error { r
 // SupportsRealtime returns false — DB sources are batch-only.
 func (s *DBSource) SupportsRealtime() bool { return false }
 
+// Capabilities reports incremental reads (created_at >= since) of threaded messages.
+func (s *DBSource) Capabilities() interfaces.SourceCapabilities {
+	return interfaces.SourceCapabilities{SupportsIncremental: true, ProducesThreads: true}
+}
+
 // Fetch returns Slack messages from the local archive newer than since, up to limit items.
 func (s *DBSource) Fetch(since time.Time, limit int) ([]models.FullItem, error) {
 	// limit < 0 means unlimited — appropriate for local DB sources.
@@ -7,6 +7,9 @@ import (
 	"time"
 
 	_ "github.com/mattn/go-sqlite3"
+
+	"pkm-sync/pkg/interfaces"
+	"pkm-sync/pkg/models"
 )
 
 func makeTestSlackDB(t *testing.T) string {
@@ -125,3 +128,15 @@ func TestDBSource_MissingDB(t *testing.T) {
 		t.Error("expected error for missing DB")
 	}
 }
+
+func TestSlackSources_Capabilities(t *testing.T) {
+	want := interfaces.SourceCapabilities{SupportsIncremental: true, ProducesThreads: true}
+
+	if got := NewSlackSource("slack", models.SourceConfig{Type: "slack"}).Capabilities(); got != want {
+		t.Errorf("SlackSource capabilities = %+v, want %+v", got, want)
+	}
+
+	if got := (&DBSource{}).Capabilities(); got != want {
+		t.Errorf("DBSource capabilities = %+v, want %+v", got, want)
+	}
+}
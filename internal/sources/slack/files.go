@@ -0,0 +1,227 @@
+package slack
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"pkm-sync/internal/naming"
+	"pkm-sync/pkg/models"
+)
+
+// RawFile is a file shared in a Slack message.
+type RawFile struct {
+	ID                 string `json:"id"`
+	Name               string `json:"name"`
+	Title              string `json:"title"`
+	Mimetype           string `json:"mimetype"`
+	Filetype           string `json:"filetype"`
+	Size               int64  `json:"size"`
+	Mode               string `json:"mode"` // "hosted", "external", "snippet", "tombstone", ...
+	IsExternal         bool   `json:"is_external"`
+	URLPrivate         string `json:"url_private"`
+	URLPrivateDownload string `json:"url_private_download"`
+	Permalink          string `json:"permalink"`
+}
+
+// errFileTooLarge is returned by DownloadFile when a file exceeds maxBytes.
+var errFileTooLarge = errors.New("file exceeds size limit")
+
+// fileTypeCategories maps the category names accepted in file_types to MIME
+// type prefixes, so ["img"] matches every image without listing extensions.
+var fileTypeCategories = map[string]string{
+	"img":   "image/",
+	"image": "image/",
+	"video": "video/",
+	"audio": "audio/",
+}
+
+// DownloadFile fetches a private Slack file URL. Slack only serves these to
+// the workspace session, so the request carries the token as a bearer
+// Authorization header plus the session cookies. maxBytes caps the download;
+// 0 means no limit.
+func (c *Client) DownloadFile(fileURL string, maxBytes int64) ([]byte, error) {
+	req, err := http.NewRequest(http.MethodGet, fileURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Authorization", "Bearer "+c.token)
+
+	if c.cookieHeader != "" {
+		req.Header.Set("Cookie", c.cookieHeader)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("HTTP request failed: %w", err)
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("download failed with status %d", resp.StatusCode)
+	}
+
+	// An unauthenticated request is redirected to the HTML sign-in page
+	// rather than rejected, so an HTML body means the token was not accepted.
+	if strings.HasPrefix(resp.Header.Get("Content-Type"), "text/html") {
+		return nil, fmt.Errorf("download returned a sign-in page; the Slack token may have expired")
+	}
+
+	reader := io.Reader(resp.Body)
+	if maxBytes > 0 {
+		reader = io.LimitReader(resp.Body, maxBytes+1)
+	}
+
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file: %w", err)
+	}
+
+	if maxBytes > 0 && int64(len(data)) > maxBytes {
+		return nil, errFileTooLarge
+	}
+
+	return data, nil
+}
+
+// attachFiles downloads the files shared in msg that pass the type and size
+// policy into the files directory and records them as item attachments.
+// Files that cannot be fetched are skipped with a warning; the message is
+// still synced.
+func (s *SlackSource) attachFiles(item *models.BasicItem, msg *RawMessage) {
+	if !s.cfg.IncludeFiles || len(msg.Files) == 0 {
+		return
+	}
+
+	for i := range msg.Files {
+		file := &msg.Files[i]
+
+		if reason := s.skipFileReason(file); reason != "" {
+			if reason != skipReasonType {
+				fmt.Printf("Warning: skipping Slack file %q: %s\n", file.Name, reason)
+			}
+
+			continue
+		}
+
+		localPath, err := s.saveFile(file)
+		if err != nil {
+			fmt.Printf("Warning: failed to download Slack file %q: %v\n", file.Name, err)
+
+			continue
+		}
+
+		item.Attachments = append(item.Attachments, models.Attachment{
+			ID:        file.ID,
+			Name:      file.Name,
+			MimeType:  file.Mimetype,
+			URL:       firstNonEmpty(file.Permalink, file.URLPrivate),
+			LocalPath: localPath,
+			Size:      file.Size,
+		})
+	}
+}
+
+const skipReasonType = "type not in file_types"
+
+// skipFileReason explains why a file is not downloaded, or returns "".
+func (s *SlackSource) skipFileReason(file *RawFile) string {
+	switch {
+	case file.IsExternal || file.Mode == "external":
+		return "externally hosted file (e.g. Google Drive link) cannot be downloaded"
+	case file.Mode == "tombstone" || file.Mode == "hidden_by_limit":
+		return "file was deleted or is hidden by the workspace plan"
+	case file.URLPrivateDownload == "" && file.URLPrivate == "":
+		return "no download URL"
+	case !fileTypeAllowed(file, s.cfg.FileTypes):
+		return skipReasonType
+	case s.cfg.MaxFileSizeBytes > 0 && file.Size > s.cfg.MaxFileSizeBytes:
+		return fmt.Sprintf("%d bytes exceeds max_file_size_bytes (%d)", file.Size, s.cfg.MaxFileSizeBytes)
+	}
+
+	return ""
+}
+
+// saveFile downloads file into the files directory and returns its path. A
+// file already on disk with the expected size is not downloaded again. With
+// an attachment store set, content stored by any earlier run is referenced
+// at its existing path instead of being written again.
+func (s *SlackSource) saveFile(file *RawFile) (string, error) {
+	dest := filepath.Join(s.filesDir(), file.ID+"-"+filepath.Base(naming.Clean(file.Name)))
+
+	if info, err := os.Stat(dest); err == nil && info.Size() == file.Size {
+		return dest, nil
+	}
+
+	data, err := s.client.DownloadFile(firstNonEmpty(file.URLPrivateDownload, file.URLPrivate), s.cfg.MaxFileSizeBytes)
+	if err != nil {
+		return "", err
+	}
+
+	if s.attachmentStore != nil {
+		return s.attachmentStore.Save(dest, data)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(dest), 0o755); err != nil {
+		return "", fmt.Errorf("failed to create files directory: %w", err)
+	}
+
+	if err := os.WriteFile(dest, data, 0o644); err != nil {
+		return "", fmt.Errorf("failed to save file: %w", err)
+	}
+
+	return dest, nil
+}
+
+// filesDir is files_dir, defaulting to <config dir>/slack-files/<workspace>.
+func (s *SlackSource) filesDir() string {
+	if s.cfg.FilesDir != "" {
+		return s.cfg.FilesDir
+	}
+
+	return filepath.Join(s.configDir, "slack-files", workspaceName(s.cfg.WorkspaceURL))
+}
+
+// fileTypeAllowed matches a file against file_types entries, which may be a
+// Slack filetype ("pdf", "docx"), an extension, or a category ("img", "video",
+// "audio"). An empty list allows every type.
+func fileTypeAllowed(file *RawFile, fileTypes []string) bool {
+	if len(fileTypes) == 0 {
+		return true
+	}
+
+	ext := strings.TrimPrefix(strings.ToLower(path.Ext(file.Name)), ".")
+	mimeType, _, _ := mime.ParseMediaType(file.Mimetype)
+
+	for _, ft := range fileTypes {
+		ft = strings.TrimPrefix(strings.ToLower(strings.TrimSpace(ft)), ".")
+
+		if ft == strings.ToLower(file.Filetype) || (ext != "" && ft == ext) {
+			return true
+		}
+
+		if prefix, ok := fileTypeCategories[ft]; ok && strings.HasPrefix(mimeType, prefix) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+
+	return ""
+}
@@ -0,0 +1,215 @@
+package slack
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"pkm-sync/internal/attachments"
+	"pkm-sync/pkg/models"
+)
+
+const testFileToken = "xoxc-test-token"
+
+// newFileServer serves /files/<name> only to requests carrying the test token,
+// answering others with the HTML sign-in page as Slack does.
+func newFileServer(t *testing.T, files map[string]string) *httptest.Server {
+	t.Helper()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer "+testFileToken {
+			w.Header().Set("Content-Type", "text/html")
+			_, _ = w.Write([]byte("<html>Sign in to Slack</html>"))
+
+			return
+		}
+
+		body, ok := files[filepath.Base(r.URL.Path)]
+		if !ok {
+			http.NotFound(w, r)
+
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/octet-stream")
+		_, _ = w.Write([]byte(body))
+	}))
+	t.Cleanup(srv.Close)
+
+	return srv
+}
+
+func newFileTestSource(t *testing.T, token string, cfg models.SlackSourceConfig) *SlackSource {
+	t.Helper()
+
+	cfg.IncludeFiles = true
+	cfg.FilesDir = t.TempDir()
+
+	return &SlackSource{cfg: cfg, client: NewClient(token, "d=session", "", 1)}
+}
+
+func TestAttachFiles_AuthenticatedDownload(t *testing.T) {
+	srv := newFileServer(t, map[string]string{"report.pdf": "%PDF-1.7 quarterly"})
+	src := newFileTestSource(t, testFileToken, models.SlackSourceConfig{})
+
+	msg := &RawMessage{Files: []RawFile{{
+		ID: "F1", Name: "report.pdf", Mimetype: "application/pdf", Filetype: "pdf", Size: 18, Mode: "hosted",
+		URLPrivateDownload: srv.URL + "/files/report.pdf", Permalink: "https://acme.slack.com/files/U1/F1/report.pdf",
+	}}}
+	item := &models.BasicItem{}
+
+	src.attachFiles(item, msg)
+
+	if len(item.Attachments) != 1 {
+		t.Fatalf("expected 1 attachment, got %d", len(item.Attachments))
+	}
+
+	att := item.Attachments[0]
+	if att.LocalPath != filepath.Join(src.cfg.FilesDir, "F1-report.pdf") {
+		t.Errorf("LocalPath = %q", att.LocalPath)
+	}
+
+	if att.URL != "https://acme.slack.com/files/U1/F1/report.pdf" || att.MimeType != "application/pdf" {
+		t.Errorf("unexpected attachment %+v", att)
+	}
+
+	data, err := os.ReadFile(att.LocalPath)
+	if err != nil || string(data) != "%PDF-1.7 quarterly" {
+		t.Errorf("saved file = %q, %v", data, err)
+	}
+}
+
+func TestAttachFiles_RejectedTokenSkipsFile(t *testing.T) {
+	srv := newFileServer(t, map[string]string{"report.pdf": "%PDF"})
+	src := newFileTestSource(t, "xoxc-stale", models.SlackSourceConfig{})
+
+	msg := &RawMessage{Files: []RawFile{{
+		ID: "F1", Name: "report.pdf", Filetype: "pdf", Size: 4, URLPrivateDownload: srv.URL + "/files/report.pdf",
+	}}}
+	item := &models.BasicItem{}
+
+	src.attachFiles(item, msg)
+
+	if len(item.Attachments) != 0 {
+		t.Errorf("sign-in page must not be saved as the file, got %+v", item.Attachments)
+	}
+}
+
+func TestAttachFiles_TypeSizeAndExternalPolicy(t *testing.T) {
+	srv := newFileServer(t, map[string]string{
+		"diagram.png": "png-bytes",
+		"notes.pdf":   "pdf-bytes",
+		"huge.png":    "0123456789abcdef",
+		"sheet.xlsx":  "xlsx-bytes",
+	})
+	src := newFileTestSource(t, testFileToken, models.SlackSourceConfig{
+		FileTypes:        []string{"pdf", "img"},
+		MaxFileSizeBytes: 12,
+	})
+
+	file := func(id, name, mimetype, filetype string, size int64) RawFile {
+		return RawFile{
+			ID: id, Name: name, Mimetype: mimetype, Filetype: filetype, Size: size,
+			URLPrivateDownload: srv.URL + "/files/" + name,
+		}
+	}
+
+	external := file("F5", "plan.gdoc", "application/vnd.google-apps.document", "gdoc", 0)
+	external.IsExternal = true
+	external.Mode = "external"
+
+	msg := &RawMessage{Files: []RawFile{
+		file("F1", "diagram.png", "image/png", "png", 9),
+		file("F2", "notes.pdf", "application/pdf", "pdf", 9),
+		file("F3", "huge.png", "image/png", "png", 16),
+		file("F4", "sheet.xlsx", "application/vnd.ms-excel", "xlsx", 10),
+		external,
+	}}
+	item := &models.BasicItem{}
+
+	src.attachFiles(item, msg)
+
+	got := make([]string, 0, len(item.Attachments))
+	for _, att := range item.Attachments {
+		got = append(got, att.ID)
+	}
+
+	if len(got) != 2 || got[0] != "F1" || got[1] != "F2" {
+		t.Errorf("attached %v, want [F1 F2] (image category and pdf, within size, not external)", got)
+	}
+}
+
+func TestAttachFiles_GlobalDedupAcrossRuns(t *testing.T) {
+	srv := newFileServer(t, map[string]string{"report.pdf": "%PDF-1.7 quarterly", "report-v1.pdf": "%PDF-1.7 quarterly"})
+	dbPath := filepath.Join(t.TempDir(), attachments.DefaultDBFile)
+
+	// Each run opens the index afresh, as separate sync invocations do.
+	run := func(id, name string) (models.Attachment, string) {
+		store, err := attachments.NewStore(dbPath)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer store.Close()
+
+		src := newFileTestSource(t, testFileToken, models.SlackSourceConfig{})
+		src.SetAttachmentStore(store)
+
+		item := &models.BasicItem{}
+		src.attachFiles(item, &RawMessage{Files: []RawFile{{
+			ID: id, Name: name, Filetype: "pdf", Size: 18, URLPrivateDownload: srv.URL + "/files/" + name,
+		}}})
+
+		if len(item.Attachments) != 1 {
+			t.Fatalf("expected 1 attachment, got %d", len(item.Attachments))
+		}
+
+		return item.Attachments[0], src.cfg.FilesDir
+	}
+
+	first, _ := run("F1", "report.pdf")
+	second, secondDir := run("F9", "report-v1.pdf")
+
+	if second.LocalPath != first.LocalPath {
+		t.Errorf("second run should reference %q, got %q", first.LocalPath, second.LocalPath)
+	}
+
+	if entries, _ := os.ReadDir(secondDir); len(entries) != 0 {
+		t.Errorf("second run wrote %d files, want none", len(entries))
+	}
+}
+
+func TestAttachFiles_DisabledByDefault(t *testing.T) {
+	src := &SlackSource{}
+	item := &models.BasicItem{}
+
+	src.attachFiles(item, &RawMessage{Files: []RawFile{{ID: "F1", Name: "a.pdf", URLPrivate: "http://x"}}})
+
+	if len(item.Attachments) != 0 {
+		t.Errorf("include_files is off; expected no attachments, got %d", len(item.Attachments))
+	}
+}
+
+func TestFileTypeAllowed(t *testing.T) {
+	tests := []struct {
+		name      string
+		file      RawFile
+		fileTypes []string
+		want      bool
+	}{
+		{"empty list allows all", RawFile{Name: "a.zip", Filetype: "zip"}, nil, true},
+		{"filetype match", RawFile{Name: "a", Filetype: "pdf"}, []string{"pdf"}, true},
+		{"extension match", RawFile{Name: "a.DOCX", Filetype: "binary"}, []string{".docx"}, true},
+		{"image category", RawFile{Name: "a.jpg", Mimetype: "image/jpeg", Filetype: "jpg"}, []string{"img"}, true},
+		{"no match", RawFile{Name: "a.mp4", Mimetype: "video/mp4", Filetype: "mp4"}, []string{"pdf", "img"}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := fileTypeAllowed(&tt.file, tt.fileTypes); got != tt.want {
+				t.Errorf("fileTypeAllowed() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
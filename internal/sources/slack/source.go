@@ -1,12 +1,16 @@
 package slack
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"strings"
 	"time"
 
+	"pkm-sync/internal/attachments"
 	"pkm-sync/internal/config"
+	"pkm-sync/internal/httpclient"
+	"pkm-sync/pkg/interfaces"
 	"pkm-sync/pkg/models"
 )
 
@@ -27,22 +31,33 @@ var systemSubtypes = map[string]bool{
 
 // SlackSource implements interfaces.Source for Slack.
 type SlackSource struct {
-	sourceID    string
-	cfg         models.SlackSourceConfig
-	configDir   string
-	client      *Client
-	userCache   *UserCache
-	rateLimitMs int
+	sourceID       string
+	cfg            models.SlackSourceConfig
+	configDir      string
+	client         *Client
+	transport      *httpclient.Transport
+	userCache      *UserCache
+	rateLimitMs    int
+	requestTimeout time.Duration
+
+	attachmentStore *attachments.Store
 }
 
 // NewSlackSource creates a new SlackSource from a SourceConfig.
 func NewSlackSource(sourceID string, sourceCfg models.SourceConfig) *SlackSource {
 	return &SlackSource{
-		sourceID: sourceID,
-		cfg:      sourceCfg.Slack,
+		sourceID:       sourceID,
+		cfg:            sourceCfg.Slack,
+		requestTimeout: sourceCfg.RequestTimeout,
 	}
 }
 
+// SetAttachmentStore makes downloaded files deduplicate against every file
+// the store has indexed, across runs and sources.
+func (s *SlackSource) SetAttachmentStore(store *attachments.Store) {
+	s.attachmentStore = store
+}
+
 // Name implements interfaces.Source.
 func (s *SlackSource) Name() string {
 	return s.sourceID
@@ -77,6 +92,9 @@ func (s *SlackSource) Configure(_ map[string]any, _ *http.Client) error {
 
 	s.rateLimitMs = rateLimitMs
 	s.client = NewClient(td.Token, td.CookieHeader, apiURL, rateLimitMs)
+	s.client.httpClient, s.transport = httpclient.NewClient(
+		&http.Client{}, httpclient.ResolveTimeout(s.requestTimeout, defaultRequestTimeout),
+	)
 	s.userCache = NewUserCache(configDir)
 
 	return nil
@@ -87,11 +105,52 @@ func (s *SlackSource) Client() *Client {
 	return s.client
 }
 
+// CheckAuth implements interfaces.AuthChecker with Slack's auth.test, which
+// names the user the token belongs to.
+func (s *SlackSource) CheckAuth() (string, error) {
+	if s.client == nil {
+		return "", fmt.Errorf("source not configured")
+	}
+
+	result, err := s.client.CallAPI("auth.test", nil)
+	if err != nil {
+		return "", err
+	}
+
+	if ok, _ := result["ok"].(bool); !ok {
+		errMsg, _ := result["error"].(string)
+
+		return "", fmt.Errorf("auth.test failed: %s", errMsg)
+	}
+
+	user, _ := result["user"].(string)
+	team, _ := result["team"].(string)
+
+	return fmt.Sprintf("%s in %s", user, team), nil
+}
+
 // SupportsRealtime implements interfaces.Source.
 func (s *SlackSource) SupportsRealtime() bool {
 	return false
 }
 
+// Capabilities implements interfaces.Source. History is fetched from the
+// since timestamp and replies carry thread_ts.
+func (s *SlackSource) Capabilities() interfaces.SourceCapabilities {
+	return interfaces.SourceCapabilities{SupportsIncremental: true, ProducesThreads: true}
+}
+
+// FetchContext implements interfaces.ContextSource by binding ctx to the API
+// client's transport for the duration of the fetch.
+func (s *SlackSource) FetchContext(ctx context.Context, since time.Time, limit int) ([]models.FullItem, error) {
+	if s.transport != nil {
+		s.transport.Bind(ctx)
+		defer s.transport.Bind(nil)
+	}
+
+	return s.Fetch(since, limit)
+}
+
 // Fetch implements interfaces.Source.
 func (s *SlackSource) Fetch(since time.Time, limit int) ([]models.FullItem, error) {
 	oldest := ""
@@ -255,6 +314,8 @@ func (s *SlackSource) fetchChannel(ch SlackChannel, oldest string, maxMessages i
 
 		author := resolveAuthor(msg, s.userCache, s.client)
 		item := FromSlackMessage(msg, ch.ID, channelName, s.cfg.WorkspaceURL, author, false)
+		addThreadModeMetadata(item, s.cfg)
+		s.attachFiles(item, msg)
 
 		// Tag DMs and group DMs additionally.
 		if ch.IsIM {
@@ -299,6 +360,8 @@ func (s *SlackSource) fetchReplies(ch SlackChannel, msg *RawMessage, channelName
 
 		replyAuthor := resolveAuthor(&replies[j], s.userCache, s.client)
 		replyItem := FromSlackMessage(&replies[j], ch.ID, channelName, s.cfg.WorkspaceURL, replyAuthor, true)
+		addThreadModeMetadata(replyItem, s.cfg)
+		s.attachFiles(replyItem, &replies[j])
 
 		if ch.IsIM {
 			replyItem.Tags = append(replyItem.Tags, fmt.Sprintf("dm:%s", channelName))
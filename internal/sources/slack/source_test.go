@@ -0,0 +1,174 @@
+package slack
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"pkm-sync/internal/transform"
+	"pkm-sync/pkg/models"
+)
+
+// newThreadServer serves a channel whose history holds one thread parent
+// (ts 100) and one standalone message (ts 300). The thread's replies come in
+// two conversations.replies pages.
+func newThreadServer(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	parent := map[string]any{
+		"ts": "100.000", "thread_ts": "100.000", "reply_count": 2, "user": "U1", "text": "Deploy today?",
+	}
+	replyA := map[string]any{"ts": "110.000", "thread_ts": "100.000", "user": "U2", "text": "After lunch"}
+	replyB := map[string]any{"ts": "120.000", "thread_ts": "100.000", "user": "U1", "text": "Works for me"}
+	other := map[string]any{"ts": "300.000", "user": "U2", "text": "Unrelated"}
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseMultipartForm(1 << 20); err != nil {
+			t.Errorf("parse form: %v", err)
+		}
+
+		var body map[string]any
+
+		switch r.URL.Path {
+		case "/api/conversations.history":
+			body = map[string]any{"ok": true, "messages": []any{other, parent}}
+		case "/api/conversations.replies":
+			if r.FormValue("cursor") == "" {
+				body = map[string]any{
+					"ok": true, "messages": []any{parent, replyA},
+					"response_metadata": map[string]any{"next_cursor": "page2"},
+				}
+			} else {
+				body = map[string]any{"ok": true, "messages": []any{replyB}}
+			}
+		case "/api/users.info":
+			names := map[string]string{"U1": "Ada", "U2": "Grace"}
+			body = map[string]any{"ok": true, "user": map[string]any{"real_name": names[r.FormValue("user")]}}
+		default:
+			http.NotFound(w, r)
+
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(body)
+	}))
+	t.Cleanup(srv.Close)
+
+	return srv
+}
+
+func newThreadTestSource(t *testing.T, cfg models.SlackSourceConfig) *SlackSource {
+	t.Helper()
+
+	srv := newThreadServer(t)
+	cfg.WorkspaceURL = "https://acme.slack.com"
+
+	return &SlackSource{
+		cfg:       cfg,
+		client:    NewClient("xoxc-test", "", srv.URL, 1),
+		userCache: NewUserCache(t.TempDir()),
+	}
+}
+
+func TestFetchChannel_RepliesShareParentThreadID(t *testing.T) {
+	src := newThreadTestSource(t, models.SlackSourceConfig{IncludeThreads: true, ThreadMode: "consolidated"})
+
+	items, err := src.fetchChannel(SlackChannel{ID: "C1", Name: "ops"}, "", 100)
+	if err != nil {
+		t.Fatalf("fetchChannel: %v", err)
+	}
+
+	if len(items) != 4 {
+		t.Fatalf("got %d items, want the standalone message, the parent and both replies", len(items))
+	}
+
+	threadIDs := make(map[string]string)
+
+	for _, item := range items {
+		meta := item.GetMetadata()
+		threadIDs[item.GetID()], _ = meta["thread_id"].(string)
+
+		if meta["thread_mode"] != "consolidated" {
+			t.Errorf("%s: thread_mode = %v, want consolidated", item.GetID(), meta["thread_mode"])
+		}
+	}
+
+	for _, id := range []string{"slack_C1_100.000", "slack_C1_110.000", "slack_C1_120.000"} {
+		if threadIDs[id] != "slack_C1_100.000" {
+			t.Errorf("%s: thread_id = %q, want the parent's ID", id, threadIDs[id])
+		}
+	}
+
+	if threadIDs["slack_C1_300.000"] != "slack_C1_300.000" {
+		t.Errorf("standalone message thread_id = %q, want its own ID", threadIDs["slack_C1_300.000"])
+	}
+}
+
+func TestFetchChannel_ThreadConsolidatesInChronologicalOrder(t *testing.T) {
+	src := newThreadTestSource(t, models.SlackSourceConfig{IncludeThreads: true, ThreadMode: "consolidated"})
+
+	items, err := src.fetchChannel(SlackChannel{ID: "C1", Name: "ops"}, "", 100)
+	if err != nil {
+		t.Fatalf("fetchChannel: %v", err)
+	}
+
+	grouped, err := transform.NewThreadGroupingTransformer().Transform(items)
+	if err != nil {
+		t.Fatalf("Transform: %v", err)
+	}
+
+	if len(grouped) != 2 {
+		t.Fatalf("got %d items, want one thread and the standalone message", len(grouped))
+	}
+
+	var thread models.FullItem
+
+	for _, item := range grouped {
+		if item.GetID() == "thread_slack_C1_100.000" {
+			thread = item
+		}
+	}
+
+	if thread == nil {
+		t.Fatalf("no consolidated thread among %v", grouped)
+	}
+
+	content := thread.GetContent()
+	first := strings.Index(content, "Deploy today?")
+	second := strings.Index(content, "After lunch")
+	third := strings.Index(content, "Works for me")
+
+	if first < 0 || !(first < second && second < third) {
+		t.Errorf("thread content not in chronological order:\n%s", content)
+	}
+
+	if !strings.Contains(content, "**Participants:** Ada, Grace") {
+		t.Errorf("thread content missing Slack participants:\n%s", content)
+	}
+
+	if !thread.GetCreatedAt().Equal(time.Unix(100, 0)) {
+		t.Errorf("thread created = %v, want the parent's time", thread.GetCreatedAt())
+	}
+}
+
+func TestFetchChannel_IndividualModeKeepsMessages(t *testing.T) {
+	src := newThreadTestSource(t, models.SlackSourceConfig{IncludeThreads: true, ThreadMode: "individual"})
+
+	items, err := src.fetchChannel(SlackChannel{ID: "C1", Name: "ops"}, "", 100)
+	if err != nil {
+		t.Fatalf("fetchChannel: %v", err)
+	}
+
+	grouped, err := transform.NewThreadGroupingTransformer().Transform(items)
+	if err != nil {
+		t.Fatalf("Transform: %v", err)
+	}
+
+	if len(grouped) != len(items) {
+		t.Errorf("got %d items, want all %d messages kept individually", len(grouped), len(items))
+	}
+}
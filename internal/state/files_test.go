@@ -0,0 +1,122 @@
+package state
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// scanVault mimics a filesystem source run: it returns the files it would
+// re-emit and saves the index afterwards.
+func scanVault(t *testing.T, configDir, vault string) []string {
+	t.Helper()
+
+	idx, err := LoadFileIndex(configDir, "notes")
+	if err != nil {
+		t.Fatalf("LoadFileIndex: %v", err)
+	}
+
+	entries, err := os.ReadDir(vault)
+	if err != nil {
+		t.Fatalf("ReadDir: %v", err)
+	}
+
+	var emitted []string
+
+	for _, entry := range entries {
+		path := filepath.Join(vault, entry.Name())
+
+		info, err := entry.Info()
+		if err != nil {
+			t.Fatalf("Info: %v", err)
+		}
+
+		if idx.Unchanged(path, info) {
+			continue
+		}
+
+		content, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatalf("ReadFile: %v", err)
+		}
+
+		if idx.Observe(path, info, content) {
+			emitted = append(emitted, entry.Name())
+		}
+	}
+
+	if err := idx.Save(configDir, "notes"); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	return emitted
+}
+
+func writeNote(t *testing.T, path, content string, modTime time.Time) {
+	t.Helper()
+
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+
+	if err := os.Chtimes(path, modTime, modTime); err != nil {
+		t.Fatalf("Chtimes: %v", err)
+	}
+}
+
+func TestFileIndex_UnchangedFileSkippedOnSecondRun(t *testing.T) {
+	configDir := t.TempDir()
+	vault := t.TempDir()
+	base := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	writeNote(t, filepath.Join(vault, "a.md"), "# A", base)
+	writeNote(t, filepath.Join(vault, "b.md"), "# B", base)
+
+	if got := scanVault(t, configDir, vault); len(got) != 2 {
+		t.Fatalf("first run: expected 2 files emitted, got %v", got)
+	}
+
+	if got := scanVault(t, configDir, vault); len(got) != 0 {
+		t.Errorf("second run: expected no files emitted, got %v", got)
+	}
+
+	// Touching a file without changing it must not re-emit it.
+	writeNote(t, filepath.Join(vault, "a.md"), "# A", base.Add(time.Hour))
+
+	if got := scanVault(t, configDir, vault); len(got) != 0 {
+		t.Errorf("after touch: expected no files emitted, got %v", got)
+	}
+
+	// Editing content re-emits only that file.
+	writeNote(t, filepath.Join(vault, "b.md"), "# B edited", base.Add(2*time.Hour))
+
+	got := scanVault(t, configDir, vault)
+	if len(got) != 1 || got[0] != "b.md" {
+		t.Errorf("after edit: expected only b.md emitted, got %v", got)
+	}
+}
+
+func TestFileIndex_SaveDropsDeletedFiles(t *testing.T) {
+	configDir := t.TempDir()
+	vault := t.TempDir()
+	base := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	writeNote(t, filepath.Join(vault, "gone.md"), "bye", base)
+	scanVault(t, configDir, vault)
+
+	if err := os.Remove(filepath.Join(vault, "gone.md")); err != nil {
+		t.Fatal(err)
+	}
+
+	scanVault(t, configDir, vault)
+
+	idx, err := LoadFileIndex(configDir, "notes")
+	if err != nil {
+		t.Fatalf("LoadFileIndex: %v", err)
+	}
+
+	if len(idx.Files) != 0 {
+		t.Errorf("expected deleted file to be pruned, got %v", idx.Files)
+	}
+}
@@ -0,0 +1,101 @@
+package state
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+const historyFileName = "sync-history.jsonl"
+
+// historyMu serializes appends from the sync command's concurrent groups.
+var historyMu sync.Mutex
+
+// HistoryRecord is one source's part of a sync run, as kept in the sync
+// history (sync-history.jsonl, one JSON record per line, oldest first).
+type HistoryRecord struct {
+	Source    string        `json:"source"`
+	StartedAt time.Time     `json:"started_at"`
+	Duration  time.Duration `json:"duration_ns"`
+	Items     int           `json:"items"`
+	// Error is the reason the source failed; empty for a successful run.
+	Error string `json:"error,omitempty"`
+}
+
+// HistoryPath returns the sync history file in configDir.
+func HistoryPath(configDir string) string {
+	return filepath.Join(configDir, historyFileName)
+}
+
+// AppendHistory adds records to the sync history in configDir.
+func AppendHistory(configDir string, records ...HistoryRecord) error {
+	if len(records) == 0 {
+		return nil
+	}
+
+	historyMu.Lock()
+	defer historyMu.Unlock()
+
+	if err := os.MkdirAll(configDir, 0700); err != nil {
+		return fmt.Errorf("creating config directory: %w", err)
+	}
+
+	f, err := os.OpenFile(HistoryPath(configDir), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return fmt.Errorf("opening sync history: %w", err)
+	}
+
+	enc := json.NewEncoder(f)
+
+	for _, record := range records {
+		if err := enc.Encode(record); err != nil {
+			_ = f.Close()
+
+			return fmt.Errorf("writing sync history: %w", err)
+		}
+	}
+
+	return f.Close()
+}
+
+// LoadHistory returns the sync history in configDir in the order it was
+// recorded. A missing file is an empty history.
+func LoadHistory(configDir string) ([]HistoryRecord, error) {
+	f, err := os.Open(HistoryPath(configDir))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("reading sync history: %w", err)
+	}
+
+	defer func() { _ = f.Close() }()
+
+	var records []HistoryRecord
+
+	scanner := bufio.NewScanner(f)
+	for line := 1; scanner.Scan(); line++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+
+		var record HistoryRecord
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			return nil, fmt.Errorf("parsing sync history line %d: %w", line, err)
+		}
+
+		records = append(records, record)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("reading sync history: %w", err)
+	}
+
+	return records, nil
+}
@@ -0,0 +1,68 @@
+package state
+
+import (
+	"os"
+	"testing"
+	"time"
+)
+
+func TestLoadHistoryMissing(t *testing.T) {
+	records, err := LoadHistory(t.TempDir())
+	if err != nil || len(records) != 0 {
+		t.Fatalf("LoadHistory on missing file = %v, %v; want empty history", records, err)
+	}
+}
+
+func TestAppendHistoryKeepsOrder(t *testing.T) {
+	dir := t.TempDir()
+	start := time.Date(2026, 5, 1, 9, 0, 0, 0, time.UTC)
+
+	if err := AppendHistory(dir, HistoryRecord{Source: "gmail_work", StartedAt: start, Items: 12}); err != nil {
+		t.Fatalf("AppendHistory: %v", err)
+	}
+
+	err := AppendHistory(dir,
+		HistoryRecord{Source: "gmail_work", StartedAt: start.Add(time.Hour), Duration: 2 * time.Second, Items: 3},
+		HistoryRecord{Source: "slack", StartedAt: start.Add(time.Hour), Error: "token expired"},
+	)
+	if err != nil {
+		t.Fatalf("AppendHistory: %v", err)
+	}
+
+	records, err := LoadHistory(dir)
+	if err != nil {
+		t.Fatalf("LoadHistory: %v", err)
+	}
+
+	if len(records) != 3 {
+		t.Fatalf("got %d records, want 3", len(records))
+	}
+
+	if records[0].Items != 12 || records[1].Duration != 2*time.Second || records[2].Error != "token expired" {
+		t.Errorf("records out of order or altered: %+v", records)
+	}
+
+	if !records[1].StartedAt.Equal(start.Add(time.Hour)) {
+		t.Errorf("StartedAt = %v, want %v", records[1].StartedAt, start.Add(time.Hour))
+	}
+
+	info, err := os.Stat(HistoryPath(dir))
+	if err != nil {
+		t.Fatalf("Stat: %v", err)
+	}
+
+	if perm := info.Mode().Perm(); perm != 0600 {
+		t.Errorf("history permissions = %o, want 600", perm)
+	}
+}
+
+func TestLoadHistoryRejectsCorruptLine(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(HistoryPath(dir), []byte("{\"source\":\"a\"}\nnot json\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := LoadHistory(dir); err == nil {
+		t.Error("expected an error for a corrupt history line")
+	}
+}
@@ -5,9 +5,19 @@
 // sync. This allows newly added sub-items to be detected and given a full
 // lookback window rather than an incremental one.
 //
-// Last-synced timestamps are NOT stored here — they are inferred at sync time
-// by querying vectors.db for MAX(updated_at) per source, which is populated by
-// the always-on VectorSink.
+// It also keeps each source's high-water mark: the newest item timestamp
+// written by the last successful sync (see MultiSyncOptions.UseState in
+// internal/sync). The next incremental sync resumes from it. Sources synced
+// before the mark existed fall back to MAX(updated_at) in vectors.db.
+//
+// Sources with a provider-side change cursor (Gmail's history ID) also keep it
+// here, so the next run can ask the provider for changes only.
+//
+// Filesystem-backed sources additionally keep a per-source file index
+// (file-index-<source>.json, see FileIndex) so unchanged files are not re-read.
+//
+// The sync history (sync-history.jsonl, see AppendHistory) records each
+// source's part of every sync run for the history command.
 package state
 
 import (
@@ -36,9 +46,18 @@ type SourceState struct {
 	// When the current config contains items absent from this list, those new
 	// items trigger a full-window lookback rather than an incremental one.
 	KnownSubItems []string `json:"known_sub_items,omitempty"`
+
+	// HistoryID is the provider change cursor recorded after the last
+	// successful sync (Gmail's historyId). Empty when the source has none.
+	HistoryID string `json:"history_id,omitempty"`
+
+	// HighWater is the newest UpdatedAt (or CreatedAt) of the items written by
+	// the last successful sync. Zero when none has been recorded.
+	HighWater time.Time `json:"high_water,omitzero"`
 }
 
-// SyncState records per-source sub-item membership. It is safe for concurrent
+// SyncState records per-source sub-item membership, change cursors and
+// high-water marks. It is safe for concurrent
 // use across goroutines; all exported methods acquire the internal mutex.
 type SyncState struct {
 	mu      sync.Mutex
@@ -52,8 +71,9 @@ func New() *SyncState {
 
 // Load reads the state file from configDir.
 // Transparently migrates legacy formats (where source values were bare
-// timestamps or objects with a last_synced field) — those fields are ignored
-// since timestamps are now inferred from the vector store.
+// timestamps or objects with a last_synced field) — those fields are ignored;
+// the next sync infers the window from the vector store and records a fresh
+// high-water mark.
 // Returns a fresh empty state when the file does not exist yet.
 func Load(configDir string) (*SyncState, error) {
 	path := filepath.Join(configDir, stateFileName)
@@ -81,7 +101,7 @@ func Load(configDir string) (*SyncState, error) {
 	for name, rawVal := range raw.Sources {
 		// Current format: {"known_sub_items": [...]}
 		// Also handles previous format: {"last_synced": "...", "known_sub_items": [...]}
-		// (last_synced is silently dropped since timestamps now come from vectors.db)
+		// (last_synced is silently dropped; high_water replaces it)
 		var ss SourceState
 		if err := json.Unmarshal(rawVal, &ss); err == nil {
 			s.Sources[name] = ss
@@ -157,3 +177,55 @@ func (s *SyncState) NewSubItems(sourceName string, current []string) []string {
 
 	return newItems
 }
+
+// HistoryID returns the change cursor recorded for sourceName, or "" when
+// none has been recorded.
+func (s *SyncState) HistoryID(sourceName string) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.Sources[sourceName].HistoryID
+}
+
+// SetHistoryID records the change cursor for sourceName. An empty id is
+// ignored so a run that could not read a cursor keeps the previous one.
+func (s *SyncState) SetHistoryID(sourceName, id string) {
+	if id == "" {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ss := s.Sources[sourceName]
+	ss.HistoryID = id
+	s.Sources[sourceName] = ss
+}
+
+// HighWater returns the high-water mark recorded for sourceName, or the zero
+// time when none has been recorded.
+func (s *SyncState) HighWater(sourceName string) time.Time {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.Sources[sourceName].HighWater
+}
+
+// SetHighWater records t as the high-water mark of sourceName. The mark only
+// moves forward: a zero t, or one before the recorded mark, is ignored.
+func (s *SyncState) SetHighWater(sourceName string, t time.Time) {
+	if t.IsZero() {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ss := s.Sources[sourceName]
+	if !t.After(ss.HighWater) {
+		return
+	}
+
+	ss.HighWater = t
+	s.Sources[sourceName] = ss
+}
@@ -4,6 +4,7 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 )
 
 func TestNewState(t *testing.T) {
@@ -142,3 +143,64 @@ func TestSinceOverlap(t *testing.T) {
 		t.Error("SinceOverlap should be positive")
 	}
 }
+
+func TestHistoryIDRoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	s := New()
+	s.UpdateSubItems("gmail_work", []string{"INBOX"})
+	s.SetHistoryID("gmail_work", "12345")
+	s.SetHistoryID("gmail_work", "") // ignored
+
+	if err := s.Save(dir); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	loaded, err := Load(dir)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+
+	if got := loaded.HistoryID("gmail_work"); got != "12345" {
+		t.Errorf("HistoryID = %q, want 12345", got)
+	}
+
+	if got := loaded.Sources["gmail_work"].KnownSubItems; len(got) != 1 {
+		t.Errorf("sub-items lost after SetHistoryID: %v", got)
+	}
+
+	if got := loaded.HistoryID("slack"); got != "" {
+		t.Errorf("HistoryID for unknown source = %q, want empty", got)
+	}
+}
+
+func TestHighWaterOnlyMovesForward(t *testing.T) {
+	dir := t.TempDir()
+	mark := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	s := New()
+	s.SetHistoryID("gmail_work", "12345")
+	s.SetHighWater("gmail_work", mark)
+	s.SetHighWater("gmail_work", mark.Add(-time.Hour)) // older, ignored
+	s.SetHighWater("gmail_work", time.Time{})          // zero, ignored
+
+	if err := s.Save(dir); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	loaded, err := Load(dir)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+
+	if got := loaded.HighWater("gmail_work"); !got.Equal(mark) {
+		t.Errorf("HighWater = %v, want %v", got, mark)
+	}
+
+	if got := loaded.HistoryID("gmail_work"); got != "12345" {
+		t.Errorf("history ID lost after SetHighWater: %q", got)
+	}
+
+	if got := loaded.HighWater("slack"); !got.IsZero() {
+		t.Errorf("HighWater for unknown source = %v, want zero", got)
+	}
+}
@@ -0,0 +1,187 @@
+package sync
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+// RunSummarySchemaVersion is bumped whenever a RunSummary field is renamed or
+// removed, so consumers can detect incompatible files.
+const RunSummarySchemaVersion = 1
+
+// RunSummary is the machine-readable record of one sync run, written as JSON
+// so cron jobs and CI can read results without scraping log output. Groups
+// (one per source type) may report concurrently.
+type RunSummary struct {
+	mu sync.Mutex
+
+	SchemaVersion   int              `json:"schema_version"`
+	StartedAt       time.Time        `json:"started_at"`
+	FinishedAt      time.Time        `json:"finished_at"`
+	DurationSeconds float64          `json:"duration_seconds"`
+	DryRun          bool             `json:"dry_run"`
+	Success         bool             `json:"success"`
+	Totals          RunTotals        `json:"totals"`
+	Sources         []SourceSummary  `json:"sources"`
+	Sinks           []SinkSummary    `json:"sinks"`
+	Skipped         []SkippedSource  `json:"skipped"`
+	Errors          []RunErrorRecord `json:"errors"`
+}
+
+// RunTotals aggregates counts across every group in the run.
+type RunTotals struct {
+	Fetched  int `json:"fetched"`
+	Exported int `json:"exported"`
+	Written  int `json:"written"`
+	Failed   int `json:"failed"`
+	Errors   int `json:"errors"`
+}
+
+// SourceSummary is the fetch outcome of one source.
+type SourceSummary struct {
+	Group string `json:"group"`
+	Name  string `json:"name"`
+	Items int    `json:"items"`
+	Error string `json:"error,omitempty"`
+}
+
+// SinkSummary is the write outcome of one sink within a group.
+type SinkSummary struct {
+	Group           string  `json:"group"`
+	Name            string  `json:"name"`
+	Written         int     `json:"written"`
+	Failed          int     `json:"failed"`
+	DurationSeconds float64 `json:"duration_seconds"`
+}
+
+// SkippedSource is a source that was not synced at all, with the reason.
+type SkippedSource struct {
+	Name   string `json:"name"`
+	Reason string `json:"reason"`
+}
+
+// RunErrorRecord is an error that failed a source or a whole group.
+type RunErrorRecord struct {
+	Group  string `json:"group"`
+	Source string `json:"source,omitempty"`
+	Error  string `json:"error"`
+}
+
+// NewRunSummary starts a summary for a run beginning at startedAt.
+func NewRunSummary(startedAt time.Time, dryRun bool) *RunSummary {
+	return &RunSummary{
+		SchemaVersion: RunSummarySchemaVersion,
+		StartedAt:     startedAt,
+		DryRun:        dryRun,
+		Sources:       []SourceSummary{},
+		Sinks:         []SinkSummary{},
+		Skipped:       []SkippedSource{},
+		Errors:        []RunErrorRecord{},
+	}
+}
+
+// AddResult records the outcome of one group's SyncAll call. Failed sources
+// count as errors; the run continues past them just as SyncAll does.
+func (r *RunSummary) AddResult(group string, result *MultiSyncResult) {
+	if result == nil {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.Totals.Fetched += result.Fetched
+	r.Totals.Exported += len(result.Items)
+
+	for _, sr := range result.SourceResults {
+		entry := SourceSummary{Group: group, Name: sr.Name, Items: sr.ItemCount}
+		if sr.Err != nil {
+			entry.Error = sr.Err.Error()
+			r.Errors = append(r.Errors, RunErrorRecord{Group: group, Source: sr.Name, Error: sr.Err.Error()})
+		}
+
+		r.Sources = append(r.Sources, entry)
+	}
+
+	for _, sk := range result.SinkResults {
+		r.Sinks = append(r.Sinks, SinkSummary{
+			Group:           group,
+			Name:            sk.Name,
+			Written:         sk.Written,
+			Failed:          sk.Failed,
+			DurationSeconds: sk.Duration.Seconds(),
+		})
+		r.Totals.Written += sk.Written
+		r.Totals.Failed += sk.Failed
+	}
+}
+
+// AddError records an error that aborted a whole group.
+func (r *RunSummary) AddError(group string, err error) {
+	if err == nil {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.Errors = append(r.Errors, RunErrorRecord{Group: group, Error: err.Error()})
+}
+
+// AddSkipped records a source that was not synced.
+func (r *RunSummary) AddSkipped(name, reason string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.Skipped = append(r.Skipped, SkippedSource{Name: name, Reason: reason})
+}
+
+// Finish stamps the end time and derives totals and the success flag.
+// A run succeeds when no source or group reported an error.
+func (r *RunSummary) Finish(finishedAt time.Time) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.FinishedAt = finishedAt
+	r.DurationSeconds = finishedAt.Sub(r.StartedAt).Seconds()
+	r.Totals.Errors = len(r.Errors)
+	r.Success = len(r.Errors) == 0
+
+	// Groups report in completion order; sort for stable output.
+	sort.SliceStable(r.Sources, func(i, j int) bool { return r.Sources[i].Group < r.Sources[j].Group })
+	sort.SliceStable(r.Sinks, func(i, j int) bool { return r.Sinks[i].Group < r.Sinks[j].Group })
+	sort.SliceStable(r.Errors, func(i, j int) bool { return r.Errors[i].Group < r.Errors[j].Group })
+}
+
+// WriteFile writes the summary as indented JSON to path, replacing any
+// previous run's file. The write goes through a temp file so readers never
+// see a half-written summary.
+func (r *RunSummary) WriteFile(path string) error {
+	r.mu.Lock()
+	data, err := json.MarshalIndent(r, "", "  ")
+	r.mu.Unlock()
+
+	if err != nil {
+		return fmt.Errorf("failed to encode run summary: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("failed to create run summary directory: %w", err)
+	}
+
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("failed to write run summary: %w", err)
+	}
+
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("failed to write run summary: %w", err)
+	}
+
+	return nil
+}
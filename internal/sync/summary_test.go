@@ -0,0 +1,161 @@
+package sync
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"pkm-sync/pkg/interfaces"
+	"pkm-sync/pkg/models"
+)
+
+// dropOneSink writes every item but reports one as dead-lettered.
+type dropOneSink struct{ MockSink }
+
+func (d *dropOneSink) LastWriteFailures() int {
+	return 1
+}
+
+func summaryItems(prefix string, n int) []models.FullItem {
+	items := make([]models.FullItem, 0, n)
+	for i := range n {
+		items = append(items, models.AsFullItem(&models.Item{ID: fmt.Sprintf("%s-%d", prefix, i), Title: "t"}))
+	}
+
+	return items
+}
+
+func TestRunSummary_JSONSchemaAndCounts(t *testing.T) {
+	started := time.Date(2026, 3, 1, 8, 0, 0, 0, time.UTC)
+	summary := NewRunSummary(started, false)
+
+	result, err := NewMultiSyncer(nil).SyncAll(context.Background(),
+		[]SourceEntry{
+			{Name: "gmail_work", Src: &MockSource{itemsToReturn: summaryItems("w", 3)}},
+			{Name: "gmail_home", Src: &FailingMockSource{err: errors.New("token expired")}},
+			{Name: "gmail_news", Src: &MockSource{itemsToReturn: summaryItems("n", 2)}},
+		},
+		[]interfaces.Sink{&MockSink{name: "vector"}, &dropOneSink{MockSink{name: "obsidian"}}},
+		MultiSyncOptions{},
+	)
+	if err != nil {
+		t.Fatalf("SyncAll: %v", err)
+	}
+
+	summary.AddResult("Gmail", result)
+	summary.AddError("Slack", errors.New("no valid Slack sources could be initialized"))
+	summary.AddSkipped("jira_old", "not configured")
+	summary.Finish(started.Add(90 * time.Second))
+
+	path := filepath.Join(t.TempDir(), "runs", "last.json")
+	if err := summary.WriteFile(path); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile: %v", err)
+	}
+
+	// Schema: every top-level key is present with the expected JSON type.
+	var raw map[string]any
+	if err := json.Unmarshal(data, &raw); err != nil {
+		t.Fatalf("summary is not valid JSON: %v", err)
+	}
+
+	wantTypes := map[string]string{
+		"schema_version": "number", "started_at": "string", "finished_at": "string",
+		"duration_seconds": "number", "dry_run": "bool", "success": "bool", "totals": "object",
+		"sources": "array", "sinks": "array", "skipped": "array", "errors": "array",
+	}
+	for key, want := range wantTypes {
+		if got := jsonType(raw[key]); got != want {
+			t.Errorf("%s: JSON type %s, want %s", key, got, want)
+		}
+	}
+
+	if len(raw) != len(wantTypes) {
+		t.Errorf("summary has %d top-level keys, want %d: %v", len(raw), len(wantTypes), raw)
+	}
+
+	var got RunSummary
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("decode summary: %v", err)
+	}
+
+	wantTotals := RunTotals{Fetched: 5, Exported: 5, Written: 9, Failed: 1, Errors: 2}
+	if got.Totals != wantTotals {
+		t.Errorf("totals = %+v, want %+v", got.Totals, wantTotals)
+	}
+
+	if got.Success || got.DurationSeconds != 90 || got.SchemaVersion != RunSummarySchemaVersion {
+		t.Errorf("success=%v duration=%v version=%d", got.Success, got.DurationSeconds, got.SchemaVersion)
+	}
+
+	if len(got.Sources) != 3 || got.Sources[0].Items != 3 || got.Sources[1].Error != "token expired" {
+		t.Errorf("sources = %+v", got.Sources)
+	}
+
+	if len(got.Sinks) != 2 || got.Sinks[0].Written != 5 {
+		t.Errorf("sinks = %+v", got.Sinks)
+	} else if obsidian := got.Sinks[1]; obsidian.Name != "obsidian" || obsidian.Written != 4 || obsidian.Failed != 1 {
+		t.Errorf("obsidian sink = %+v, want 4 written and 1 failed", obsidian)
+	}
+
+	if len(got.Skipped) != 1 || got.Skipped[0].Name != "jira_old" {
+		t.Errorf("skipped = %+v", got.Skipped)
+	}
+
+	// Errors are ordered by group: the Gmail source failure, then the Slack group.
+	if len(got.Errors) != 2 || got.Errors[0].Source != "gmail_home" || got.Errors[1].Group != "Slack" {
+		t.Errorf("errors = %+v", got.Errors)
+	}
+}
+
+func TestRunSummary_EmptyRunSucceeds(t *testing.T) {
+	summary := NewRunSummary(time.Now(), true)
+	summary.Finish(time.Now())
+
+	data, err := json.Marshal(summary)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+
+	var raw map[string]any
+	if err := json.Unmarshal(data, &raw); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+
+	// Empty lists encode as [] rather than null so consumers can iterate.
+	for _, key := range []string{"sources", "sinks", "skipped", "errors"} {
+		if jsonType(raw[key]) != "array" {
+			t.Errorf("%s should be an empty array, got %v", key, raw[key])
+		}
+	}
+
+	if raw["success"] != true || raw["dry_run"] != true {
+		t.Errorf("success=%v dry_run=%v", raw["success"], raw["dry_run"])
+	}
+}
+
+func jsonType(v any) string {
+	switch v.(type) {
+	case float64:
+		return "number"
+	case string:
+		return "string"
+	case bool:
+		return "bool"
+	case map[string]any:
+		return "object"
+	case []any:
+		return "array"
+	default:
+		return "null"
+	}
+}
@@ -2,12 +2,15 @@ package sync
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	"golang.org/x/sync/errgroup"
 
 	"pkm-sync/internal/resolve"
+	"pkm-sync/internal/state"
 	"pkm-sync/pkg/interfaces"
 	"pkm-sync/pkg/models"
 )
@@ -18,8 +21,28 @@ type SourceEntry struct {
 	Src   interfaces.Source
 	Since time.Time // zero = use MultiSyncOptions.DefaultSince
 	Limit int       // 0 = use MultiSyncOptions.DefaultLimit
+	// Until ends the fetch window; zero uses MultiSyncOptions.DefaultUntil.
+	// It applies to sources implementing interfaces.UntilSource.
+	Until time.Time
+	// OmitBody writes the source's items as stub notes: title, metadata, tags
+	// and links are kept, the content is dropped after transformation.
+	OmitBody bool
+	// OnEmptyResult is what a fetch returning no items means: "ignore" (or
+	// empty), "warn", or "error", which records ErrEmptyResult for the source.
+	OnEmptyResult string
 }
 
+// Empty result policies (SourceEntry.OnEmptyResult).
+const (
+	EmptyResultIgnore = "ignore"
+	EmptyResultWarn   = "warn"
+	EmptyResultError  = "error"
+)
+
+// ErrEmptyResult is recorded for a source with on_empty_result: error that
+// returned no items, typically a wrong query or an expired token.
+var ErrEmptyResult = errors.New("source returned no items")
+
 // MultiSyncOptions controls the behavior of MultiSyncer.SyncAll.
 type MultiSyncOptions struct {
 	DefaultSince time.Time
@@ -28,10 +51,29 @@ type MultiSyncOptions struct {
 	TransformCfg models.TransformConfig
 	DryRun       bool
 
+	// DefaultUntil ends the fetch window of entries without their own Until;
+	// zero fetches up to now.
+	DefaultUntil time.Time
+
+	// SourceTagPrefix and SourceTagSeparator shape the source tag, see
+	// SourceTag. Empty values use "source" and ":".
+	SourceTagPrefix    string
+	SourceTagSeparator string
+
 	// ResolveRefs enables cross-source reference resolution between Transform
 	// and Sink phases. Requires the MultiSyncer to have a non-nil resolver.
 	ResolveRefs  bool
 	ResolveDepth int // 0 defaults to 1 inside the resolve engine
+
+	// Concurrency caps how many sources are fetched at once. Zero or less
+	// fetches every entry at the same time.
+	Concurrency int
+
+	// UseState records each fetched source's MaxTimestamp as its high-water
+	// mark in State once every sink was written, so the next incremental
+	// sync can resume from it. Failed sources keep their previous mark.
+	UseState bool
+	State    *state.SyncState
 }
 
 // SourceResult records the outcome of fetching a single source.
@@ -44,14 +86,39 @@ type SourceResult struct {
 	// error. Callers use this to anchor the next incremental sync window to
 	// the actual data rather than to the wall-clock time of the sync run.
 	MaxTimestamp time.Time
+	// Started and Duration time the source's fetch.
+	Started  time.Time
+	Duration time.Duration
+}
+
+// SinkResult records how many items a single sink wrote.
+type SinkResult struct {
+	Name    string
+	Written int
+	// Failed counts items the sink set aside (e.g. to the dead-letter file)
+	// instead of writing; only sinks implementing FailureCounter report it.
+	Failed   int
+	Duration time.Duration
+}
+
+// FailureCounter is implemented by sinks that can skip individual items
+// without failing the whole Write. LastWriteFailures reports how many items
+// the most recent Write skipped.
+type FailureCounter interface {
+	LastWriteFailures() int
 }
 
 // MultiSyncResult is returned by SyncAll.
 type MultiSyncResult struct {
 	SourceResults []SourceResult
+	// SinkResults is in sink order; empty in dry-run mode.
+	SinkResults []SinkResult
 	// Items holds the transformed items ready for export.
 	// In dry-run mode sinks are not written to but Items is still populated.
 	Items []models.FullItem
+	// Fetched is the number of items collected from sources before transformation.
+	Fetched  int
+	Duration time.Duration
 }
 
 // fetchResult holds the outcome of fetching a single source.
@@ -80,7 +147,8 @@ func NewMultiSyncerWithResolver(pipeline interfaces.TransformPipeline, resolver
 
 // SyncAll executes the full Sources → Transform → Sinks pipeline.
 //
-// It fetches from each source in entries concurrently, applies source tags if
+// It fetches from each source in entries concurrently (at most
+// opts.Concurrency at a time when set), applies source tags if
 // requested, runs the transformer pipeline, and writes to all sinks concurrently
 // (unless DryRun is set). Source failures are non-fatal: they are recorded in
 // the result and the remaining sources continue to be processed. Sink failures
@@ -91,12 +159,16 @@ func (m *MultiSyncer) SyncAll(
 	sinks []interfaces.Sink,
 	opts MultiSyncOptions,
 ) (*MultiSyncResult, error) {
+	start := time.Now()
 	result := &MultiSyncResult{}
 
 	// --- Phase 1: Fetch from all sources (concurrent) ---
 	// Pre-allocate indexed slice so each goroutine writes to its own position.
 	results := make([]fetchResult, len(entries))
 	g, gCtx := errgroup.WithContext(ctx)
+	if opts.Concurrency > 0 {
+		g.SetLimit(opts.Concurrency)
+	}
 
 	for i, entry := range entries {
 		g.Go(func() error {
@@ -118,18 +190,54 @@ func (m *MultiSyncer) SyncAll(
 				limit = 1000
 			}
 
-			items, err := entry.Src.Fetch(since, limit)
+			until := opts.DefaultUntil
+			if !entry.Until.IsZero() {
+				until = entry.Until
+			}
+
+			if !until.IsZero() {
+				if bounded, ok := entry.Src.(interfaces.UntilSource); ok {
+					bounded.SetUntil(until)
+				} else {
+					fmt.Printf("Warning: source '%s' cannot stop at an end date; fetching up to now\n", entry.Name)
+				}
+			}
+
+			started := time.Now()
+
+			items, err := fetchEntry(gCtx, entry.Src, since, limit)
 			if err != nil {
 				fmt.Printf("Warning: failed to fetch from source '%s': %v, skipping\n", entry.Name, err)
-				results[i] = fetchResult{sr: SourceResult{Name: entry.Name, Err: err}}
+				results[i] = fetchResult{sr: SourceResult{
+					Name: entry.Name, Err: err, Started: started, Duration: time.Since(started),
+				}}
 
 				return nil
 			}
 
-			// Apply source tag when enabled
-			if opts.SourceTags {
-				for _, item := range items {
-					item.SetTags(append(item.GetTags(), "source:"+entry.Name))
+			if len(items) == 0 {
+				switch entry.OnEmptyResult {
+				case EmptyResultWarn:
+					fmt.Printf("Warning: source '%s' returned no items\n", entry.Name)
+				case EmptyResultError:
+					err := fmt.Errorf("source '%s': %w (on_empty_result: error)", entry.Name, ErrEmptyResult)
+					fmt.Printf("Error: %v\n", err)
+					results[i] = fetchResult{sr: SourceResult{
+						Name: entry.Name, Err: err, Started: started, Duration: time.Since(started),
+					}}
+
+					return nil
+				}
+			}
+
+			// Record the source name for sinks, and tag it when enabled.
+			tag := SourceTag(opts.SourceTagPrefix, opts.SourceTagSeparator, entry.Name)
+
+			for _, item := range items {
+				stampSourceName(item, entry.Name)
+
+				if opts.SourceTags {
+					item.SetTags(append(item.GetTags(), tag))
 				}
 			}
 
@@ -150,7 +258,10 @@ func (m *MultiSyncer) SyncAll(
 			}
 
 			results[i] = fetchResult{
-				sr:    SourceResult{Name: entry.Name, ItemCount: len(items), MaxTimestamp: maxTS},
+				sr: SourceResult{
+					Name: entry.Name, ItemCount: len(items), MaxTimestamp: maxTS,
+					Started: started, Duration: time.Since(started),
+				},
 				items: items,
 			}
 
@@ -163,6 +274,12 @@ func (m *MultiSyncer) SyncAll(
 		return nil, err
 	}
 
+	// A canceled context (e.g. SIGINT) aborts the run before anything is
+	// transformed or written, so a partial fetch never reaches the sinks.
+	if err := ctx.Err(); err != nil {
+		return nil, fmt.Errorf("sync canceled: %w", err)
+	}
+
 	// Merge results in entry order into allItems and SourceResults.
 	var allItems []models.FullItem
 
@@ -171,6 +288,7 @@ func (m *MultiSyncer) SyncAll(
 		allItems = append(allItems, r.items...)
 	}
 
+	result.Fetched = len(allItems)
 	fmt.Printf("Total items collected: %d\n", len(allItems))
 
 	// --- Phase 2: Transform ---
@@ -201,19 +319,36 @@ func (m *MultiSyncer) SyncAll(
 		allItems = resolved
 	}
 
+	omitBodies(allItems, entries)
+
 	result.Items = allItems
 
 	// --- Phase 3: Write to sinks (concurrent, skipped in dry-run mode) ---
 	// First sink failure cancels remaining sinks via errgroup context.
 	if !opts.DryRun {
 		gw, gwCtx := errgroup.WithContext(ctx)
+		result.SinkResults = make([]SinkResult, len(sinks))
 
-		for _, sink := range sinks {
+		for i, sink := range sinks {
 			gw.Go(func() error {
+				sinkStart := time.Now()
+
 				if err := sink.Write(gwCtx, allItems); err != nil {
 					return fmt.Errorf("sink '%s' write failed: %w", sink.Name(), err)
 				}
 
+				failed := 0
+				if fc, ok := sink.(FailureCounter); ok {
+					failed = fc.LastWriteFailures()
+				}
+
+				result.SinkResults[i] = SinkResult{
+					Name:     sink.Name(),
+					Written:  len(allItems) - failed,
+					Failed:   failed,
+					Duration: time.Since(sinkStart),
+				}
+
 				return nil
 			})
 		}
@@ -221,7 +356,89 @@ func (m *MultiSyncer) SyncAll(
 		if err := gw.Wait(); err != nil {
 			return nil, err
 		}
+
+		if opts.UseState && opts.State != nil {
+			for _, r := range result.SourceResults {
+				if r.Err == nil {
+					opts.State.SetHighWater(r.Name, r.MaxTimestamp)
+				}
+			}
+		}
 	}
 
+	result.Duration = time.Since(start)
+
 	return result, nil
 }
+
+// SourceTag builds the tag added to items from the named source: prefix,
+// separator, name. The defaults give "source:<name>"; a prefix such as
+// "sync/source" with separator "/" nests as "sync/source/<name>" in Obsidian.
+func SourceTag(prefix, separator, name string) string {
+	if prefix == "" {
+		prefix = "source"
+	}
+
+	if separator == "" {
+		separator = ":"
+	}
+
+	return strings.TrimSuffix(prefix, separator) + separator + name
+}
+
+// stampSourceName records name under models.MetadataSourceName unless the
+// source already set it.
+func stampSourceName(item models.FullItem, name string) {
+	metadata := item.GetMetadata()
+	if metadata == nil {
+		metadata = make(map[string]any)
+	}
+
+	if _, ok := metadata[models.MetadataSourceName]; ok {
+		return
+	}
+
+	metadata[models.MetadataSourceName] = name
+	item.SetMetadata(metadata)
+}
+
+// omitBodies clears the content of items from entries with OmitBody set,
+// including every message of a consolidated thread. It runs after the
+// transformers so links and action items extracted from the body survive.
+func omitBodies(items []models.FullItem, entries []SourceEntry) {
+	omit := make(map[string]bool)
+
+	for _, entry := range entries {
+		if entry.OmitBody {
+			omit[entry.Name] = true
+		}
+	}
+
+	if len(omit) == 0 {
+		return
+	}
+
+	for _, item := range items {
+		if name, _ := item.GetMetadata()[models.MetadataSourceName].(string); !omit[name] {
+			continue
+		}
+
+		item.SetContent("")
+
+		if thread, ok := models.AsThread(item); ok {
+			for _, msg := range thread.GetMessages() {
+				msg.SetContent("")
+			}
+		}
+	}
+}
+
+// fetchEntry calls FetchContext when the source supports cancellation and
+// falls back to the context-free Fetch otherwise.
+func fetchEntry(ctx context.Context, src interfaces.Source, since time.Time, limit int) ([]models.FullItem, error) {
+	if cs, ok := src.(interfaces.ContextSource); ok {
+		return cs.FetchContext(ctx, since, limit)
+	}
+
+	return src.Fetch(since, limit)
+}
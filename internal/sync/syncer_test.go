@@ -5,9 +5,11 @@ import (
 	"errors"
 	"net/http"
 	"strings"
+	"sync/atomic"
 	"testing"
 	"time"
 
+	"pkm-sync/internal/state"
 	"pkm-sync/internal/transform"
 	"pkm-sync/pkg/interfaces"
 	"pkm-sync/pkg/models"
@@ -39,6 +41,10 @@ func (m *MockSource) SupportsRealtime() bool {
 	return false
 }
 
+func (m *MockSource) Capabilities() interfaces.SourceCapabilities {
+	return interfaces.SourceCapabilities{}
+}
+
 // FailingMockSource is a mock Source that always returns an error from Fetch.
 type FailingMockSource struct {
 	name string
@@ -65,6 +71,10 @@ func (f *FailingMockSource) SupportsRealtime() bool {
 	return false
 }
 
+func (f *FailingMockSource) Capabilities() interfaces.SourceCapabilities {
+	return interfaces.SourceCapabilities{}
+}
+
 // MockSink is a mock implementation of the Sink interface for testing.
 type MockSink struct {
 	name         string
@@ -209,6 +219,62 @@ func TestSyncAllConcurrentFetch(t *testing.T) {
 	}
 }
 
+// countingSource records the most fetches it saw in flight at once.
+type countingSource struct {
+	MockSource
+	inFlight *atomic.Int32
+	peak     *atomic.Int32
+}
+
+func (c *countingSource) Fetch(since time.Time, limit int) ([]models.FullItem, error) {
+	n := c.inFlight.Add(1)
+	defer c.inFlight.Add(-1)
+
+	for {
+		peak := c.peak.Load()
+		if n <= peak || c.peak.CompareAndSwap(peak, n) {
+			break
+		}
+	}
+
+	time.Sleep(10 * time.Millisecond)
+
+	return c.MockSource.Fetch(since, limit)
+}
+
+func TestSyncAllConcurrencyLimit(t *testing.T) {
+	var inFlight, peak atomic.Int32
+
+	entries := make([]SourceEntry, 0, 4)
+
+	for _, name := range []string{"a", "b", "c", "d"} {
+		src := &countingSource{
+			MockSource: MockSource{name: name, itemsToReturn: []models.FullItem{
+				models.AsFullItem(&models.Item{ID: name + "1", Title: name}),
+			}},
+			inFlight: &inFlight,
+			peak:     &peak,
+		}
+		entries = append(entries, SourceEntry{Name: name, Src: src})
+	}
+
+	result, err := NewMultiSyncer(nil).SyncAll(context.Background(), entries, nil, MultiSyncOptions{Concurrency: 2})
+	if err != nil {
+		t.Fatalf("SyncAll failed: %v", err)
+	}
+
+	if got := peak.Load(); got > 2 {
+		t.Errorf("peak concurrent fetches = %d, want at most 2", got)
+	}
+
+	// Results stay in entry order whatever order the fetches finish in.
+	for i, want := range []string{"a1", "b1", "c1", "d1"} {
+		if got := result.Items[i].GetID(); got != want {
+			t.Errorf("Items[%d] = %s, want %s", i, got, want)
+		}
+	}
+}
+
 func TestSyncAllConcurrentSinks(t *testing.T) {
 	source := &MockSource{
 		name: "source_a",
@@ -326,3 +392,265 @@ func TestSyncAllSinkErrorFatal(t *testing.T) {
 		t.Errorf("Expected error to contain sink name 'bad_sink', got: %v", err)
 	}
 }
+
+// BlockingContextSource is a ContextSource whose fetch blocks until ctx is done.
+type BlockingContextSource struct {
+	MockSource
+
+	fetchedWithContext bool
+}
+
+func (b *BlockingContextSource) FetchContext(ctx context.Context, _ time.Time, _ int) ([]models.FullItem, error) {
+	b.fetchedWithContext = true
+
+	<-ctx.Done()
+
+	return nil, ctx.Err()
+}
+
+var _ interfaces.ContextSource = (*BlockingContextSource)(nil)
+
+func TestSyncAllCanceledContextAbortsBeforeSinks(t *testing.T) {
+	source := &BlockingContextSource{MockSource: MockSource{name: "slow_source"}}
+	sink := &MockSink{}
+	ms := NewMultiSyncer(nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(20*time.Millisecond, cancel)
+
+	_, err := ms.SyncAll(
+		ctx,
+		[]SourceEntry{{Name: "slow_source", Src: source}},
+		[]interfaces.Sink{sink},
+		MultiSyncOptions{},
+	)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("Expected context.Canceled, got: %v", err)
+	}
+
+	if !source.fetchedWithContext {
+		t.Error("Expected SyncAll to call FetchContext on a ContextSource")
+	}
+
+	if sink.writtenItems != nil {
+		t.Errorf("Expected no items written after cancellation, got %d", len(sink.writtenItems))
+	}
+}
+
+func TestSourceTag(t *testing.T) {
+	tests := []struct {
+		prefix, separator, name, want string
+	}{
+		{"", "", "gmail_work", "source:gmail_work"},
+		{"sync/source", "/", "gmail-work", "sync/source/gmail-work"},
+		{"sync/source/", "/", "slack", "sync/source/slack"},
+		{"src", "-", "jira", "src-jira"},
+	}
+
+	for _, tt := range tests {
+		if got := SourceTag(tt.prefix, tt.separator, tt.name); got != tt.want {
+			t.Errorf("SourceTag(%q, %q, %q) = %q, want %q", tt.prefix, tt.separator, tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestSyncAllNamespacedSourceTagsAndSourceName(t *testing.T) {
+	item := models.AsFullItem(&models.Item{ID: "1", Title: "t"})
+	sink := &MockSink{}
+
+	_, err := NewMultiSyncer(nil).SyncAll(context.Background(),
+		[]SourceEntry{{Name: "gmail_work", Src: &MockSource{itemsToReturn: []models.FullItem{item}}}},
+		[]interfaces.Sink{sink},
+		MultiSyncOptions{SourceTags: true, SourceTagPrefix: "sync/source", SourceTagSeparator: "/"},
+	)
+	if err != nil {
+		t.Fatalf("SyncAll: %v", err)
+	}
+
+	got := sink.writtenItems[0]
+	if tags := got.GetTags(); len(tags) != 1 || tags[0] != "sync/source/gmail_work" {
+		t.Errorf("tags = %v, want [sync/source/gmail_work]", tags)
+	}
+
+	if name := got.GetMetadata()[models.MetadataSourceName]; name != "gmail_work" {
+		t.Errorf("source_name metadata = %v, want gmail_work", name)
+	}
+}
+
+func TestSyncAllOmitBodyKeepsMetadataAndLinks(t *testing.T) {
+	event := models.NewBasicItem("evt-1", "Planning")
+	event.SetContent("Confidential agenda")
+	event.SetTags([]string{"meeting"})
+	event.SetLinks([]models.Link{{URL: "https://meet.example.com/abc", Type: "meeting_url"}})
+	event.SetMetadata(map[string]interface{}{"organizer": "alice@example.com"})
+
+	email := models.NewBasicItem("msg-1", "Hello")
+	email.SetContent("Full body")
+
+	sink := &MockSink{}
+
+	_, err := NewMultiSyncer(nil).SyncAll(context.Background(),
+		[]SourceEntry{
+			{Name: "calendar", Src: &MockSource{itemsToReturn: []models.FullItem{event}}, OmitBody: true},
+			{Name: "gmail", Src: &MockSource{itemsToReturn: []models.FullItem{email}}},
+		},
+		[]interfaces.Sink{sink},
+		MultiSyncOptions{},
+	)
+	if err != nil {
+		t.Fatalf("SyncAll: %v", err)
+	}
+
+	stub, full := sink.writtenItems[0], sink.writtenItems[1]
+
+	if stub.GetContent() != "" {
+		t.Errorf("content = %q, want it omitted", stub.GetContent())
+	}
+
+	if stub.GetTitle() != "Planning" || len(stub.GetTags()) != 1 {
+		t.Errorf("title/tags = %q/%v, want them kept", stub.GetTitle(), stub.GetTags())
+	}
+
+	if links := stub.GetLinks(); len(links) != 1 || links[0].URL != "https://meet.example.com/abc" {
+		t.Errorf("links = %v, want the meeting link kept", links)
+	}
+
+	if organizer := stub.GetMetadata()["organizer"]; organizer != "alice@example.com" {
+		t.Errorf("organizer metadata = %v, want it kept", organizer)
+	}
+
+	if full.GetContent() != "Full body" {
+		t.Errorf("content of a source without omit_body = %q, want it kept", full.GetContent())
+	}
+}
+
+func TestSyncAllOnEmptyResult(t *testing.T) {
+	item := models.NewBasicItem("1", "kept")
+
+	tests := []struct {
+		policy  string
+		wantErr bool
+	}{
+		{"", false},
+		{EmptyResultIgnore, false},
+		{EmptyResultWarn, false},
+		{EmptyResultError, true},
+	}
+
+	for _, tt := range tests {
+		t.Run("policy "+tt.policy, func(t *testing.T) {
+			sink := &MockSink{}
+
+			result, err := NewMultiSyncer(nil).SyncAll(context.Background(),
+				[]SourceEntry{
+					{Name: "gmail_empty", Src: &MockSource{}, OnEmptyResult: tt.policy},
+					{Name: "gmail_full", Src: &MockSource{itemsToReturn: []models.FullItem{item}}},
+				},
+				[]interfaces.Sink{sink},
+				MultiSyncOptions{},
+			)
+			if err != nil {
+				t.Fatalf("SyncAll: %v", err)
+			}
+
+			gotErr := result.SourceResults[0].Err
+			if tt.wantErr != errors.Is(gotErr, ErrEmptyResult) {
+				t.Errorf("empty source error = %v, want ErrEmptyResult: %v", gotErr, tt.wantErr)
+			}
+
+			if len(sink.writtenItems) != 1 {
+				t.Errorf("expected the other source's item to be written, got %d items", len(sink.writtenItems))
+			}
+		})
+	}
+}
+
+// boundedMockSource records the end time SyncAll gives it.
+type boundedMockSource struct {
+	MockSource
+	until time.Time
+}
+
+func (b *boundedMockSource) SetUntil(until time.Time) {
+	b.until = until
+}
+
+func TestSyncAllPassesUntil(t *testing.T) {
+	defaultUntil := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	entryUntil := time.Date(2023, 7, 1, 0, 0, 0, 0, time.UTC)
+
+	byDefault, byEntry := &boundedMockSource{}, &boundedMockSource{}
+
+	_, err := NewMultiSyncer(nil).SyncAll(context.Background(),
+		[]SourceEntry{
+			{Name: "default", Src: byDefault},
+			{Name: "entry", Src: byEntry, Until: entryUntil},
+			{Name: "unbounded", Src: &MockSource{}},
+		},
+		[]interfaces.Sink{&MockSink{}},
+		MultiSyncOptions{DefaultUntil: defaultUntil},
+	)
+	if err != nil {
+		t.Fatalf("SyncAll: %v", err)
+	}
+
+	if !byDefault.until.Equal(defaultUntil) {
+		t.Errorf("default entry until = %v, want %v", byDefault.until, defaultUntil)
+	}
+
+	if !byEntry.until.Equal(entryUntil) {
+		t.Errorf("entry until = %v, want %v", byEntry.until, entryUntil)
+	}
+}
+
+var _ interfaces.UntilSource = (*boundedMockSource)(nil)
+
+func TestSyncAllUseStateRecordsHighWater(t *testing.T) {
+	older := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	newer := older.Add(48 * time.Hour)
+
+	source := &MockSource{
+		name: "good_source",
+		itemsToReturn: []models.FullItem{
+			models.AsFullItem(&models.Item{ID: "1", Title: "Old", CreatedAt: older, UpdatedAt: older}),
+			models.AsFullItem(&models.Item{ID: "2", Title: "New", CreatedAt: older, UpdatedAt: newer}),
+		},
+	}
+	failing := &FailingMockSource{name: "bad_source", err: errors.New("network timeout")}
+
+	st := state.New()
+	st.SetHighWater("bad_source", older)
+
+	entries := []SourceEntry{
+		{Name: "good_source", Src: source},
+		{Name: "bad_source", Src: failing},
+	}
+
+	_, err := NewMultiSyncer(nil).SyncAll(context.Background(), entries, []interfaces.Sink{&MockSink{}},
+		MultiSyncOptions{UseState: true, State: st})
+	if err != nil {
+		t.Fatalf("SyncAll failed: %v", err)
+	}
+
+	if got := st.HighWater("good_source"); !got.Equal(newer) {
+		t.Errorf("good_source high-water = %v, want %v", got, newer)
+	}
+
+	if got := st.HighWater("bad_source"); !got.Equal(older) {
+		t.Errorf("bad_source high-water = %v, want unchanged %v", got, older)
+	}
+
+	// A failed sink write records nothing.
+	st = state.New()
+
+	_, err = NewMultiSyncer(nil).SyncAll(context.Background(), entries[:1],
+		[]interfaces.Sink{&FailingMockSink{name: "bad_sink", err: errors.New("disk full")}},
+		MultiSyncOptions{UseState: true, State: st})
+	if err == nil {
+		t.Fatal("Expected error from failing sink, got nil")
+	}
+
+	if got := st.HighWater("good_source"); !got.IsZero() {
+		t.Errorf("high-water after failed sink = %v, want zero", got)
+	}
+}
@@ -0,0 +1,295 @@
+package transform
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"pkm-sync/pkg/interfaces"
+	"pkm-sync/pkg/models"
+)
+
+const (
+	transformerNameActionItems = "action_items"
+
+	// metadataKeyActionItems holds the extracted action items as []string.
+	metadataKeyActionItems = "action_items"
+
+	defaultActionItemsHeading = "## Action Items"
+)
+
+// defaultActionItemPatterns match a single line after any list marker has been
+// stripped. The "task" group is the action text; "assignee" is optional.
+var defaultActionItemPatterns = []string{
+	`(?i)^(?:action items?|todo|to-do|follow[- ]up)\s*[:\-]\s*(?P<task>.+)$`,
+	`^\[ \]\s+(?P<task>.+)$`,
+	`^@(?P<assignee>[\w.-]+)\s+to\s+(?P<task>.+)$`,
+	`(?i)^please\s+(?P<task>.+)$`,
+}
+
+// courtesyPhrases follow "please" in ordinary email prose rather than in requests.
+var courtesyPhrases = []string{
+	"find", "see", "note", "advise", "feel free",
+	"let me know", "let us know", "do not hesitate", "don't hesitate",
+}
+
+var listMarkerRegex = regexp.MustCompile(`^(?:[-*+]|\d+[.)])\s+`)
+
+// ActionItemsTransformer collects TODOs and action items from content into
+// Metadata["action_items"], optionally prepending a checklist to the note.
+type ActionItemsTransformer struct {
+	config   map[string]interface{}
+	patterns []*regexp.Regexp
+}
+
+func NewActionItemsTransformer() *ActionItemsTransformer {
+	t := &ActionItemsTransformer{config: make(map[string]interface{})}
+
+	for _, p := range defaultActionItemPatterns {
+		t.patterns = append(t.patterns, regexp.MustCompile(p))
+	}
+
+	return t
+}
+
+func (t *ActionItemsTransformer) Name() string {
+	return transformerNameActionItems
+}
+
+// RunsAfter avoids extracting "please ..." lines from signatures and quoted markup.
+func (t *ActionItemsTransformer) RunsAfter() []string {
+	return []string{transformerNameContentCleanup, transformerNameSignatureRemoval, transformerNameThreadGrouping}
+}
+
+// Configure accepts:
+//   - patterns: extra regexes, matched per line; a "task" (or first) capture group is the action text
+//   - merge_with_defaults: keep the built-in patterns alongside custom ones (default true)
+//   - prepend_checklist: add a checklist section at the top of the content (default false)
+//   - checklist_heading: heading for that section (default "## Action Items")
+func (t *ActionItemsTransformer) Configure(config map[string]interface{}) error {
+	t.config = config
+
+	raw, exists := config["patterns"]
+	if !exists {
+		return nil
+	}
+
+	list, ok := raw.([]interface{})
+	if !ok {
+		return fmt.Errorf("action_items: patterns must be a list of strings, got %T", raw)
+	}
+
+	var patterns []*regexp.Regexp
+
+	if t.shouldMergeWithDefaults() {
+		for _, p := range defaultActionItemPatterns {
+			patterns = append(patterns, regexp.MustCompile(p))
+		}
+	}
+
+	for _, p := range list {
+		s, ok := p.(string)
+		if !ok {
+			return fmt.Errorf("action_items: pattern must be a string, got %T", p)
+		}
+
+		compiled, err := regexp.Compile(s)
+		if err != nil {
+			return fmt.Errorf("action_items: invalid pattern %q: %w", s, err)
+		}
+
+		patterns = append(patterns, compiled)
+	}
+
+	t.patterns = patterns
+
+	return nil
+}
+
+func (t *ActionItemsTransformer) Transform(items []models.FullItem) ([]models.FullItem, error) {
+	transformedItems := make([]models.FullItem, len(items))
+
+	for i, item := range items {
+		actions := t.ExtractActionItems(item.GetContent())
+		if len(actions) == 0 {
+			transformedItems[i] = item
+
+			continue
+		}
+
+		newItem := withMetadata(item, map[string]interface{}{metadataKeyActionItems: actions})
+
+		if t.shouldPrependChecklist() {
+			newItem.SetContent(t.prependChecklist(item.GetContent(), actions))
+		}
+
+		transformedItems[i] = newItem
+	}
+
+	return transformedItems, nil
+}
+
+// ExtractActionItems returns the normalized, de-duplicated action items found in content.
+func (t *ActionItemsTransformer) ExtractActionItems(content string) []string {
+	var actions []string
+
+	seen := make(map[string]bool)
+	inCodeBlock := false
+
+	for _, line := range strings.Split(content, "\n") {
+		trimmed := strings.TrimSpace(line)
+
+		if strings.HasPrefix(trimmed, "```") {
+			inCodeBlock = !inCodeBlock
+
+			continue
+		}
+
+		if inCodeBlock || trimmed == "" || strings.HasPrefix(trimmed, ">") {
+			continue // skip code and quoted replies
+		}
+
+		trimmed = listMarkerRegex.ReplaceAllString(trimmed, "")
+
+		action, ok := t.matchLine(trimmed)
+		if !ok {
+			continue
+		}
+
+		key := strings.ToLower(action)
+		if !seen[key] {
+			seen[key] = true
+			actions = append(actions, action)
+		}
+	}
+
+	return actions
+}
+
+// matchLine applies the patterns to one line and returns the normalized action.
+func (t *ActionItemsTransformer) matchLine(line string) (string, bool) {
+	for _, pattern := range t.patterns {
+		match := pattern.FindStringSubmatch(line)
+		if match == nil {
+			continue
+		}
+
+		taskIdx := pattern.SubexpIndex("task")
+		if taskIdx < 0 && pattern.NumSubexp() > 0 {
+			taskIdx = 1
+		}
+
+		task := match[0]
+		if taskIdx > 0 {
+			task = match[taskIdx]
+		}
+
+		var assignee string
+		if idx := pattern.SubexpIndex("assignee"); idx > 0 {
+			assignee = match[idx]
+		}
+
+		if strings.HasPrefix(strings.ToLower(line), "please") && isCourtesyPhrase(task) {
+			continue
+		}
+
+		if action := normalizeActionItem(task, assignee); action != "" {
+			return action, true
+		}
+	}
+
+	return "", false
+}
+
+func isCourtesyPhrase(task string) bool {
+	lower := strings.ToLower(task)
+	for _, phrase := range courtesyPhrases {
+		if strings.HasPrefix(lower, phrase) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// normalizeActionItem capitalizes the task, drops trailing punctuation and
+// appends the assignee as "(@name)".
+func normalizeActionItem(task, assignee string) string {
+	task = strings.TrimSpace(strings.TrimRight(strings.TrimSpace(task), ".!;,"))
+	if task == "" {
+		return ""
+	}
+
+	r, size := utf8.DecodeRuneInString(task)
+	task = string(unicode.ToUpper(r)) + task[size:]
+
+	if assignee != "" {
+		task += " (@" + assignee + ")"
+	}
+
+	return task
+}
+
+// prependChecklist adds a checklist section before content unless one is already there.
+func (t *ActionItemsTransformer) prependChecklist(content string, actions []string) string {
+	heading := t.checklistHeading()
+	if strings.HasPrefix(strings.TrimSpace(content), heading) {
+		return content
+	}
+
+	var b strings.Builder
+
+	b.WriteString(heading)
+	b.WriteString("\n\n")
+
+	for _, action := range actions {
+		b.WriteString("- [ ] ")
+		b.WriteString(action)
+		b.WriteString("\n")
+	}
+
+	b.WriteString("\n")
+	b.WriteString(content)
+
+	return b.String()
+}
+
+// Configuration helper methods
+
+func (t *ActionItemsTransformer) shouldMergeWithDefaults() bool {
+	if val, exists := t.config["merge_with_defaults"]; exists {
+		if b, ok := val.(bool); ok {
+			return b
+		}
+	}
+
+	return true // Default: keep built-in patterns
+}
+
+func (t *ActionItemsTransformer) shouldPrependChecklist() bool {
+	if val, exists := t.config["prepend_checklist"]; exists {
+		if b, ok := val.(bool); ok {
+			return b
+		}
+	}
+
+	return false
+}
+
+func (t *ActionItemsTransformer) checklistHeading() string {
+	if val, exists := t.config["checklist_heading"]; exists {
+		if s, ok := val.(string); ok && s != "" {
+			return s
+		}
+	}
+
+	return defaultActionItemsHeading
+}
+
+// Ensure interface compliance.
+var (
+	_ interfaces.Transformer        = (*ActionItemsTransformer)(nil)
+	_ interfaces.OrderedTransformer = (*ActionItemsTransformer)(nil)
+)
@@ -0,0 +1,128 @@
+package transform
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"pkm-sync/pkg/models"
+)
+
+const meetingNotes = `# Weekly sync
+
+We reviewed the roadmap and agreed the todo list is getting long.
+Please find attached the slides from last week.
+Action item: send the revised budget to finance.
+- [ ] Book the offsite venue
+- [x] Circulate last week's notes
+* TODO: update the onboarding doc.
+@dana to review the vendor contract
+Please confirm the launch date by Friday.
+Let me know if you have questions, please.
+
+> Action item: this is quoted from an earlier email
+
+` + "```" + `
+TODO: not an action, just code
+` + "```" + `
+`
+
+func TestActionItemsTransformer_ExtractsMixedContent(t *testing.T) {
+	transformer := NewActionItemsTransformer()
+
+	got := transformer.ExtractActionItems(meetingNotes)
+	want := []string{
+		"Send the revised budget to finance",
+		"Book the offsite venue",
+		"Update the onboarding doc",
+		"Review the vendor contract (@dana)",
+		"Confirm the launch date by Friday",
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ExtractActionItems() =\n  %q\nwant\n  %q", got, want)
+	}
+}
+
+func TestActionItemsTransformer_NoFalsePositivesOnProse(t *testing.T) {
+	prose := `Thanks for the update on the migration.
+Please see the attached report and let me know what you think.
+Please note that the office is closed on Monday.
+I added this to my todo list for later.
+The team will follow up on pricing next quarter.`
+
+	if got := NewActionItemsTransformer().ExtractActionItems(prose); len(got) != 0 {
+		t.Errorf("expected no action items from prose, got %q", got)
+	}
+}
+
+func TestActionItemsTransformer_Transform(t *testing.T) {
+	transformer := NewActionItemsTransformer()
+	if err := transformer.Configure(map[string]interface{}{"prepend_checklist": true}); err != nil {
+		t.Fatalf("Configure failed: %v", err)
+	}
+
+	withActions := models.NewBasicItem("1", "Notes")
+	withActions.SetContent("Intro\nTODO: ship it")
+	withActions.SetMetadata(map[string]interface{}{"existing": "kept"})
+
+	without := models.NewBasicItem("2", "Plain")
+	without.SetContent("Nothing to do here.")
+
+	result, err := transformer.Transform([]models.FullItem{withActions, without})
+	if err != nil {
+		t.Fatalf("Transform failed: %v", err)
+	}
+
+	metadata := result[0].GetMetadata()
+	if actions, ok := metadata["action_items"].([]string); !ok || len(actions) != 1 || actions[0] != "Ship it" {
+		t.Errorf("expected action_items [Ship it], got %v", metadata["action_items"])
+	}
+
+	if metadata["existing"] != "kept" {
+		t.Error("expected existing metadata to be preserved")
+	}
+
+	if !strings.HasPrefix(result[0].GetContent(), "## Action Items\n\n- [ ] Ship it\n\nIntro") {
+		t.Errorf("expected checklist prepended, got %q", result[0].GetContent())
+	}
+
+	if _, exists := withActions.GetMetadata()["action_items"]; exists {
+		t.Error("expected original item to be left unmodified")
+	}
+
+	if result[1] != without {
+		t.Error("expected item without action items to pass through unchanged")
+	}
+
+	// A second pass must not stack another checklist on top.
+	again, err := transformer.Transform(result)
+	if err != nil {
+		t.Fatalf("second Transform failed: %v", err)
+	}
+
+	if strings.Count(again[0].GetContent(), "## Action Items") != 1 {
+		t.Errorf("expected a single checklist after re-running, got %q", again[0].GetContent())
+	}
+}
+
+func TestActionItemsTransformer_CustomPatterns(t *testing.T) {
+	transformer := NewActionItemsTransformer()
+
+	err := transformer.Configure(map[string]interface{}{
+		"patterns":            []interface{}{`^AR\((?P<assignee>\w+)\):\s*(?P<task>.+)$`},
+		"merge_with_defaults": false,
+	})
+	if err != nil {
+		t.Fatalf("Configure failed: %v", err)
+	}
+
+	got := transformer.ExtractActionItems("AR(sam): draft the RFC\nTODO: ignored without defaults")
+	if len(got) != 1 || got[0] != "Draft the RFC (@sam)" {
+		t.Errorf("expected custom pattern match only, got %q", got)
+	}
+
+	if err := transformer.Configure(map[string]interface{}{"patterns": []interface{}{"("}}); err == nil {
+		t.Error("expected error for invalid pattern")
+	}
+}
@@ -12,6 +12,7 @@ import (
 	"strings"
 	"time"
 
+	"pkm-sync/internal/httpclient"
 	"pkm-sync/pkg/interfaces"
 	"pkm-sync/pkg/models"
 )
@@ -103,7 +104,7 @@ func NewHTTPBackend(url string, headers map[string]string, model string, timeout
 		headers: headers,
 		model:   model,
 		timeout: timeout,
-		client:  &http.Client{},
+		client:  httpclient.NewSharedClient(),
 	}
 }
 
@@ -238,6 +239,11 @@ func (t *AIAnalysisTransformer) Name() string {
 	return transformerNameAIAnalysis
 }
 
+// RunsAfter sends the model cleaned content without signatures.
+func (t *AIAnalysisTransformer) RunsAfter() []string {
+	return []string{transformerNameContentCleanup, transformerNameSignatureRemoval, transformerNameThreadGrouping}
+}
+
 // Configure parses the ai_analysis transformer config block.
 //
 // Supported keys:
@@ -662,4 +668,7 @@ func GetAIActionItems(item models.FullItem) []string {
 }
 
 // Ensure interface compliance.
-var _ interfaces.Transformer = (*AIAnalysisTransformer)(nil)
+var (
+	_ interfaces.Transformer        = (*AIAnalysisTransformer)(nil)
+	_ interfaces.OrderedTransformer = (*AIAnalysisTransformer)(nil)
+)
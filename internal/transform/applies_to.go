@@ -0,0 +1,100 @@
+package transform
+
+import (
+	"fmt"
+	"slices"
+
+	"pkm-sync/pkg/models"
+)
+
+// appliesToKey is the per-transformer config key that limits a transformer to
+// items whose source type or item type is listed.
+const appliesToKey = "applies_to"
+
+// parseAppliesTo reads the applies_to list from a transformer's config. A
+// missing or empty list means the transformer runs on every item.
+func parseAppliesTo(name string, config map[string]interface{}) ([]string, error) {
+	raw, exists := config[appliesToKey]
+	if !exists || raw == nil {
+		return nil, nil
+	}
+
+	switch v := raw.(type) {
+	case string:
+		return []string{v}, nil
+	case []string:
+		return v, nil
+	case []interface{}:
+		result := make([]string, 0, len(v))
+
+		for _, elem := range v {
+			s, ok := elem.(string)
+			if !ok {
+				return nil, fmt.Errorf("transformer '%s': %s entries must be strings, got %T", name, appliesToKey, elem)
+			}
+
+			result = append(result, s)
+		}
+
+		return result, nil
+	default:
+		return nil, fmt.Errorf("transformer '%s': %s must be a list of strings, got %T", name, appliesToKey, raw)
+	}
+}
+
+// itemInScope reports whether item's source type or item type is in scope.
+func itemInScope(item models.FullItem, scope []string) bool {
+	return slices.Contains(scope, item.GetSourceType()) || slices.Contains(scope, item.GetItemType())
+}
+
+// scopeItems returns the items a scoped transformer should see and their
+// positions in items.
+func scopeItems(items []models.FullItem, scope []string) ([]models.FullItem, []int) {
+	var (
+		matched   []models.FullItem
+		positions []int
+	)
+
+	for i, item := range items {
+		if itemInScope(item, scope) {
+			matched = append(matched, item)
+			positions = append(positions, i)
+		}
+	}
+
+	return matched, positions
+}
+
+// mergeScoped puts a scoped transformer's output back among the items it did
+// not see. When the transformer kept the item count, each result takes its
+// input's position; otherwise (filtering, grouping) the results are placed
+// where the first in-scope item was.
+func mergeScoped(items []models.FullItem, positions []int, transformed []models.FullItem) []models.FullItem {
+	if len(transformed) == len(positions) {
+		merged := slices.Clone(items)
+		for i, pos := range positions {
+			merged[pos] = transformed[i]
+		}
+
+		return merged
+	}
+
+	merged := make([]models.FullItem, 0, len(items)-len(positions)+len(transformed))
+	next := 0
+
+	for i, item := range items {
+		if next < len(positions) && positions[next] == i {
+			if next == 0 {
+				merged = append(merged, transformed...)
+			}
+
+			next++
+
+			continue
+		}
+
+		merged = append(merged, item)
+	}
+
+	return merged
+}
@@ -0,0 +1,228 @@
+package transform
+
+import (
+	"fmt"
+	"maps"
+	"path"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"pkm-sync/pkg/interfaces"
+	"pkm-sync/pkg/models"
+)
+
+const (
+	transformerNameAttachmentLinkRewrite = "attachment_link_rewrite"
+
+	defaultAttachmentFolder = "Attachments"
+)
+
+// attachmentURLRegex finds http(s) URLs, and the cid: URLs of inline email
+// images, in content, stopping at the brackets and quotes that delimit
+// Markdown and HTML links.
+var attachmentURLRegex = regexp.MustCompile(`(?:https?://|cid:)[^\s<>"'()\[\]]+`)
+
+// AttachmentLinkRewriteTransformer points links to downloaded attachments at
+// their local copies. Every URL in the content, and every entry in the
+// item's links, that equals the URL of an attachment with a LocalPath is
+// replaced by <attachment_folder>/<file name>, as is the attachment's own URL.
+// File sinks copy the files into that folder of the vault (see
+// RewrittenAttachmentFolder), so the note keeps working offline. Other URLs
+// are left alone.
+type AttachmentLinkRewriteTransformer struct {
+	config map[string]interface{}
+	folder string
+}
+
+func NewAttachmentLinkRewriteTransformer() *AttachmentLinkRewriteTransformer {
+	return &AttachmentLinkRewriteTransformer{
+		config: make(map[string]interface{}),
+		folder: defaultAttachmentFolder,
+	}
+}
+
+func (t *AttachmentLinkRewriteTransformer) Name() string {
+	return transformerNameAttachmentLinkRewrite
+}
+
+// RunsAfter rewrites the links other transformers extract or merge, and the
+// content of consolidated threads.
+func (t *AttachmentLinkRewriteTransformer) RunsAfter() []string {
+	return []string{transformerNameLinkExtraction, transformerNameThreadGrouping, transformerNameFuzzyDedup}
+}
+
+// Configure accepts:
+//   - attachment_folder: vault-relative folder holding downloaded attachments
+//     (default "Attachments", or the Obsidian target's attachment_folder)
+func (t *AttachmentLinkRewriteTransformer) Configure(config map[string]interface{}) error {
+	t.config = config
+	t.folder = defaultAttachmentFolder
+
+	if raw, exists := config["attachment_folder"]; exists {
+		folder, ok := raw.(string)
+		if !ok {
+			return fmt.Errorf("attachment_link_rewrite: attachment_folder must be a string, got %T", raw)
+		}
+
+		if folder != "" {
+			t.folder = folder
+		}
+	}
+
+	return nil
+}
+
+func (t *AttachmentLinkRewriteTransformer) Transform(items []models.FullItem) ([]models.FullItem, error) {
+	transformedItems := make([]models.FullItem, len(items))
+
+	for i, item := range items {
+		local := t.localLinks(item.GetAttachments())
+		if len(local) == 0 {
+			transformedItems[i] = item
+
+			continue
+		}
+
+		content, contentChanged := rewriteAttachmentURLs(item.GetContent(), local)
+		links, linksChanged := rewriteLinkURLs(item.GetLinks(), local)
+		attachments := rewriteAttachmentLinks(item.GetAttachments(), local)
+
+		newItem := cloneFullItem(item)
+		newItem.SetAttachments(attachments)
+
+		if contentChanged {
+			newItem.SetContent(content)
+		}
+
+		if linksChanged {
+			newItem.SetLinks(links)
+		}
+
+		transformedItems[i] = newItem
+	}
+
+	return transformedItems, nil
+}
+
+// localLinks maps the remote URL of each downloaded attachment to its link
+// under the attachment folder, with spaces escaped for Markdown links.
+func (t *AttachmentLinkRewriteTransformer) localLinks(attachments []models.Attachment) map[string]string {
+	local := make(map[string]string)
+
+	for _, attachment := range attachments {
+		if attachment.URL == "" || attachment.LocalPath == "" {
+			continue
+		}
+
+		link := path.Join(filepath.ToSlash(t.folder), filepath.Base(attachment.LocalPath))
+		local[attachment.URL] = strings.ReplaceAll(link, " ", "%20")
+	}
+
+	return local
+}
+
+// rewriteAttachmentURLs replaces the URLs in content that are keys of local.
+// A URL followed by sentence punctuation still matches without it.
+func rewriteAttachmentURLs(content string, local map[string]string) (string, bool) {
+	changed := false
+
+	rewritten := attachmentURLRegex.ReplaceAllStringFunc(content, func(found string) string {
+		trimmed := strings.TrimRight(found, ".,;:!?")
+		if link, ok := local[trimmed]; ok {
+			changed = true
+
+			return link + found[len(trimmed):]
+		}
+
+		return found
+	})
+
+	return rewritten, changed
+}
+
+// rewriteLinkURLs returns a copy of links with the URLs in local replaced.
+func rewriteLinkURLs(links []models.Link, local map[string]string) ([]models.Link, bool) {
+	changed := false
+	rewritten := make([]models.Link, len(links))
+
+	for i, link := range links {
+		if target, ok := local[link.URL]; ok {
+			link.URL = target
+			changed = true
+		}
+
+		rewritten[i] = link
+	}
+
+	return rewritten, changed
+}
+
+// rewriteAttachmentLinks returns a copy of attachments whose URLs in local
+// point at the local copy.
+func rewriteAttachmentLinks(attachments []models.Attachment, local map[string]string) []models.Attachment {
+	rewritten := slices.Clone(attachments)
+
+	for i, attachment := range rewritten {
+		if target, ok := local[attachment.URL]; ok {
+			rewritten[i].URL = target
+		}
+	}
+
+	return rewritten
+}
+
+// RewrittenAttachmentFolder returns the vault-relative folder the
+// attachment_link_rewrite transformer links downloaded attachments to, and
+// false when config does not run it. File sinks copy the files there.
+func RewrittenAttachmentFolder(config models.TransformConfig) (string, bool) {
+	if !config.Enabled || !slices.Contains(SelectedTransformers(config), transformerNameAttachmentLinkRewrite) {
+		return "", false
+	}
+
+	if folder, ok := config.Transformers[transformerNameAttachmentLinkRewrite]["attachment_folder"].(string); ok &&
+		folder != "" {
+		return folder, true
+	}
+
+	return defaultAttachmentFolder, true
+}
+
+// WithAttachmentFolder returns config with folder as the attachment_link_rewrite
+// transformer's attachment_folder when the pipeline runs it without one, so
+// the Obsidian target's attachment_folder need not be repeated. Config that
+// does not run the transformer is returned as is.
+func WithAttachmentFolder(config models.TransformConfig, folder string) models.TransformConfig {
+	if folder == "" || !config.Enabled ||
+		!slices.Contains(SelectedTransformers(config), transformerNameAttachmentLinkRewrite) {
+		return config
+	}
+
+	settings := config.Transformers[transformerNameAttachmentLinkRewrite]
+	if _, explicit := settings["attachment_folder"]; explicit {
+		return config
+	}
+
+	settings = maps.Clone(settings)
+	if settings == nil {
+		settings = make(map[string]interface{})
+	}
+
+	settings["attachment_folder"] = folder
+
+	config.Transformers = maps.Clone(config.Transformers)
+	if config.Transformers == nil {
+		config.Transformers = make(map[string]map[string]interface{})
+	}
+
+	config.Transformers[transformerNameAttachmentLinkRewrite] = settings
+
+	return config
+}
+
+// Ensure interface compliance.
+var (
+	_ interfaces.Transformer        = (*AttachmentLinkRewriteTransformer)(nil)
+	_ interfaces.OrderedTransformer = (*AttachmentLinkRewriteTransformer)(nil)
+)
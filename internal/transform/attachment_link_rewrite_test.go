@@ -0,0 +1,203 @@
+package transform
+
+import (
+	"reflect"
+	"testing"
+
+	"pkm-sync/pkg/models"
+)
+
+func newAttachmentLinkRewrite(t *testing.T, config map[string]interface{}) *AttachmentLinkRewriteTransformer {
+	t.Helper()
+
+	transformer := NewAttachmentLinkRewriteTransformer()
+	if err := transformer.Configure(config); err != nil {
+		t.Fatalf("Configure failed: %v", err)
+	}
+
+	return transformer
+}
+
+func TestAttachmentLinkRewrite_RewritesContentAndLinks(t *testing.T) {
+	const fileURL = "https://files.slack.com/files-pri/T1-F1/q3_plan.pdf"
+
+	item := models.NewBasicItem("msg-1", "Plan")
+	item.SetContent("See [the plan](" + fileURL + ") and " + fileURL + ". Also https://example.com/other")
+	item.SetAttachments([]models.Attachment{
+		{ID: "F1", Name: "q3 plan.pdf", URL: fileURL, LocalPath: "/data/slack/files/F1_q3 plan.pdf"},
+		{ID: "F2", Name: "remote.png", URL: "https://example.com/other"},
+	})
+	item.SetLinks([]models.Link{
+		{URL: fileURL, Title: "q3 plan.pdf", Type: "external"},
+		{URL: "https://example.com/other", Title: "Other"},
+	})
+
+	got, err := newAttachmentLinkRewrite(t, map[string]interface{}{"attachment_folder": "files"}).
+		Transform([]models.FullItem{item})
+	if err != nil {
+		t.Fatalf("Transform failed: %v", err)
+	}
+
+	wantContent := "See [the plan](files/F1_q3%20plan.pdf) and files/F1_q3%20plan.pdf. Also https://example.com/other"
+	if got[0].GetContent() != wantContent {
+		t.Errorf("Expected content %q, got %q", wantContent, got[0].GetContent())
+	}
+
+	wantLinks := []models.Link{
+		{URL: "files/F1_q3%20plan.pdf", Title: "q3 plan.pdf", Type: "external"},
+		{URL: "https://example.com/other", Title: "Other"},
+	}
+	if !reflect.DeepEqual(got[0].GetLinks(), wantLinks) {
+		t.Errorf("Expected links %v, got %v", wantLinks, got[0].GetLinks())
+	}
+
+	if got[0].GetAttachments()[0].URL != "files/F1_q3%20plan.pdf" ||
+		got[0].GetAttachments()[1].URL != "https://example.com/other" {
+		t.Errorf("Expected only the downloaded attachment's URL rewritten, got %+v", got[0].GetAttachments())
+	}
+
+	if item.GetLinks()[0].URL != fileURL || item.GetContent() == got[0].GetContent() ||
+		item.GetAttachments()[0].URL != fileURL {
+		t.Error("Expected the input item to be left unchanged")
+	}
+}
+
+func TestAttachmentLinkRewrite_InlineEmailImages(t *testing.T) {
+	item := models.NewBasicItem("msg-2", "Diagram")
+	item.SetContent("Here it is: ![diagram](cid:diagram@mail)")
+	item.SetAttachments([]models.Attachment{
+		{ID: "att-1", Name: "diagram.png", URL: "cid:diagram@mail", LocalPath: "/data/gmail/msg-2-diagram.png"},
+	})
+
+	got, err := newAttachmentLinkRewrite(t, nil).Transform([]models.FullItem{item})
+	if err != nil {
+		t.Fatalf("Transform failed: %v", err)
+	}
+
+	if want := "Here it is: ![diagram](Attachments/msg-2-diagram.png)"; got[0].GetContent() != want {
+		t.Errorf("Expected content %q, got %q", want, got[0].GetContent())
+	}
+}
+
+func TestRewrittenAttachmentFolder(t *testing.T) {
+	config := models.TransformConfig{Enabled: true, PipelineOrder: []string{"attachment_link_rewrite"}}
+
+	if folder, ok := RewrittenAttachmentFolder(config); !ok || folder != "Attachments" {
+		t.Errorf("RewrittenAttachmentFolder = %q, %v; want Attachments, true", folder, ok)
+	}
+
+	config = WithAttachmentFolder(config, "Files")
+	if folder, ok := RewrittenAttachmentFolder(config); !ok || folder != "Files" {
+		t.Errorf("RewrittenAttachmentFolder = %q, %v; want Files, true", folder, ok)
+	}
+
+	config.PipelineOrder = []string{"content_cleanup"}
+	if _, ok := RewrittenAttachmentFolder(config); ok {
+		t.Error("RewrittenAttachmentFolder reported a folder for a pipeline without the transformer")
+	}
+}
+
+func TestAttachmentLinkRewrite_LeavesItemsWithoutDownloadsAlone(t *testing.T) {
+	item := models.NewBasicItem("1", "Remote only")
+	item.SetContent("https://example.com/a.pdf")
+	item.SetAttachments([]models.Attachment{{Name: "a.pdf", URL: "https://example.com/a.pdf"}})
+
+	got, err := newAttachmentLinkRewrite(t, nil).Transform([]models.FullItem{item})
+	if err != nil {
+		t.Fatalf("Transform failed: %v", err)
+	}
+
+	if got[0] != models.FullItem(item) {
+		t.Error("Expected the item without downloaded attachments to pass through")
+	}
+}
+
+func TestAttachmentLinkRewrite_DefaultFolderAndInvalidConfig(t *testing.T) {
+	item := models.NewBasicItem("1", "Doc")
+	item.SetContent("<https://example.com/a.png>")
+	item.SetAttachments([]models.Attachment{{URL: "https://example.com/a.png", LocalPath: "/tmp/a.png"}})
+
+	got, err := newAttachmentLinkRewrite(t, nil).Transform([]models.FullItem{item})
+	if err != nil {
+		t.Fatalf("Transform failed: %v", err)
+	}
+
+	if want := "<Attachments/a.png>"; got[0].GetContent() != want {
+		t.Errorf("Expected %q, got %q", want, got[0].GetContent())
+	}
+
+	if err := NewAttachmentLinkRewriteTransformer().Configure(map[string]interface{}{"attachment_folder": 3}); err == nil {
+		t.Error("Expected a non-string attachment_folder to be rejected")
+	}
+}
+
+func TestAttachmentLinkRewrite_RunsBeforeLinkNormalization(t *testing.T) {
+	pipeline := NewPipeline()
+	for _, transformer := range GetAllContentProcessingTransformers() {
+		if err := pipeline.AddTransformer(transformer); err != nil {
+			t.Fatalf("AddTransformer failed: %v", err)
+		}
+	}
+
+	order, err := pipeline.ResolveOrder([]string{"link_normalization", "attachment_link_rewrite", "link_extraction"})
+	if err != nil {
+		t.Fatalf("ResolveOrder failed: %v", err)
+	}
+
+	want := []string{"link_extraction", "attachment_link_rewrite", "link_normalization"}
+	if !reflect.DeepEqual(order, want) {
+		t.Errorf("Expected order %v, got %v", want, order)
+	}
+}
+
+func TestWithAttachmentFolder(t *testing.T) {
+	t.Run("fills in the folder when the transformer runs", func(t *testing.T) {
+		cfg := models.TransformConfig{
+			Enabled:      true,
+			Transformers: map[string]map[string]interface{}{"attachment_link_rewrite": {}},
+		}
+
+		got := WithAttachmentFolder(cfg, "Files")
+		if folder := got.Transformers["attachment_link_rewrite"]["attachment_folder"]; folder != "Files" {
+			t.Errorf("attachment_folder = %v, want Files", folder)
+		}
+
+		if _, set := cfg.Transformers["attachment_link_rewrite"]["attachment_folder"]; set {
+			t.Error("WithAttachmentFolder modified the caller's transformer map")
+		}
+	})
+
+	t.Run("pipeline order without an entry", func(t *testing.T) {
+		cfg := models.TransformConfig{Enabled: true, PipelineOrder: []string{"attachment_link_rewrite"}}
+
+		got := WithAttachmentFolder(cfg, "Files")
+		if got.Transformers["attachment_link_rewrite"]["attachment_folder"] != "Files" {
+			t.Errorf("Transformers = %v, want the folder set", got.Transformers)
+		}
+	})
+
+	t.Run("explicit folder wins", func(t *testing.T) {
+		cfg := models.TransformConfig{
+			Enabled: true,
+			Transformers: map[string]map[string]interface{}{
+				"attachment_link_rewrite": {"attachment_folder": "mine"},
+			},
+		}
+
+		got := WithAttachmentFolder(cfg, "Files")
+		if folder := got.Transformers["attachment_link_rewrite"]["attachment_folder"]; folder != "mine" {
+			t.Errorf("attachment_folder = %v, want the explicit mine", folder)
+		}
+	})
+
+	t.Run("transformer not selected", func(t *testing.T) {
+		cfg := models.TransformConfig{
+			Enabled:      true,
+			Transformers: map[string]map[string]interface{}{"content_cleanup": {}},
+		}
+
+		if got := WithAttachmentFolder(cfg, "Files"); !reflect.DeepEqual(got, cfg) {
+			t.Errorf("got %+v, want unchanged", got)
+		}
+	})
+}
@@ -52,6 +52,11 @@ func (t *EnhancedAutoTaggingTransformer) Name() string {
 	return transformerNameAutoTagging
 }
 
+// RunsAfter tags cleaned, grouped items so patterns match what is exported.
+func (t *EnhancedAutoTaggingTransformer) RunsAfter() []string {
+	return []string{transformerNameContentCleanup, transformerNameThreadGrouping}
+}
+
 // Configure parses the tagging configuration.
 //
 // Supported config keys:
@@ -284,4 +289,7 @@ func (t *EnhancedAutoTaggingTransformer) cloneWithTags(item models.FullItem, new
 }
 
 // Ensure interface compliance.
-var _ interfaces.Transformer = (*EnhancedAutoTaggingTransformer)(nil)
+var (
+	_ interfaces.Transformer        = (*EnhancedAutoTaggingTransformer)(nil)
+	_ interfaces.OrderedTransformer = (*EnhancedAutoTaggingTransformer)(nil)
+)
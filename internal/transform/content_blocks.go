@@ -0,0 +1,458 @@
+package transform
+
+import (
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	nethtml "golang.org/x/net/html"
+)
+
+// Values of the content_cleanup table_format option.
+const (
+	tableFormatMarkdown = "markdown"
+	tableFormatHTML     = "html"
+)
+
+// protectStart and protectEnd (private-use runes) wrap code and raw HTML
+// tables in the intermediate markdown, so the entity and whitespace passes of
+// ProcessHTMLContent leave them verbatim. mapUnprotected removes them.
+const (
+	protectStart = "\uE000"
+	protectEnd   = "\uE001"
+)
+
+var htmlWhitespace = regexp.MustCompile(`[ \t\r\n\f]+`)
+
+// codeBlockPlaceholder matches the single-line stand-ins withFencedCodeHidden
+// puts where fenced code blocks were.
+var codeBlockPlaceholder = regexp.MustCompile(protectStart + `(\d+)` + protectEnd)
+
+// protect writes block so that post-processing passes skip it.
+func protect(markdown *strings.Builder, block string) {
+	markdown.WriteString(protectStart)
+	markdown.WriteString(block)
+	markdown.WriteString(protectEnd)
+}
+
+// mapUnprotected applies fn to the text outside protected blocks and strips
+// the protection markers.
+func mapUnprotected(s string, fn func(string) string) string {
+	var out strings.Builder
+
+	for {
+		start := strings.Index(s, protectStart)
+		if start < 0 {
+			out.WriteString(fn(s))
+
+			return out.String()
+		}
+
+		end := strings.Index(s[start:], protectEnd)
+		if end < 0 {
+			out.WriteString(fn(s[:start]))
+			out.WriteString(s[start+len(protectStart):])
+
+			return out.String()
+		}
+
+		out.WriteString(fn(s[:start]))
+		out.WriteString(s[start+len(protectStart) : start+end])
+		s = s[start+end+len(protectEnd):]
+	}
+}
+
+// withFencedCodeHidden applies fn to markdown with every fenced code block
+// (``` or ~~~, closed by a fence of the same character at least as long, or
+// running to the end) replaced by a one-line placeholder, then puts the blocks
+// back verbatim. Line-based cleanup such as blank-line collapsing and quote
+// stripping thus only sees prose. A block whose placeholder fn drops is
+// dropped with it.
+func withFencedCodeHidden(markdown string, fn func(string) string) string {
+	var (
+		blocks []string
+		prose  strings.Builder
+		block  strings.Builder
+		fence  string
+	)
+
+	for _, line := range strings.SplitAfter(markdown, "\n") {
+		if fence == "" {
+			if fence = openingFence(line); fence == "" {
+				prose.WriteString(line)
+
+				continue
+			}
+		} else if isClosingFence(line, fence) {
+			fence = ""
+		}
+
+		block.WriteString(line)
+
+		if fence == "" {
+			prose.WriteString(hideCodeBlock(&blocks, &block))
+		}
+	}
+
+	if block.Len() > 0 {
+		prose.WriteString(hideCodeBlock(&blocks, &block))
+	}
+
+	if len(blocks) == 0 {
+		return fn(markdown)
+	}
+
+	return codeBlockPlaceholder.ReplaceAllStringFunc(fn(prose.String()), func(placeholder string) string {
+		n, _ := strconv.Atoi(codeBlockPlaceholder.FindStringSubmatch(placeholder)[1])
+
+		return blocks[n]
+	})
+}
+
+// hideCodeBlock records the block being collected and returns its placeholder,
+// keeping the block's trailing newline outside so the prose around it keeps
+// its line structure.
+func hideCodeBlock(blocks *[]string, block *strings.Builder) string {
+	text, newline := strings.CutSuffix(block.String(), "\n")
+	block.Reset()
+
+	*blocks = append(*blocks, text)
+	placeholder := protectStart + strconv.Itoa(len(*blocks)-1) + protectEnd
+
+	if newline {
+		return placeholder + "\n"
+	}
+
+	return placeholder
+}
+
+// openingFence returns the fence (``` or ~~~, three or more) opening a fenced
+// code block on line, indented by at most three spaces, or "".
+func openingFence(line string) string {
+	trimmed := strings.TrimLeft(line, " ")
+	if len(line)-len(trimmed) > 3 || len(trimmed) < 3 {
+		return ""
+	}
+
+	char := trimmed[0]
+	if char != '`' && char != '~' {
+		return ""
+	}
+
+	n := 0
+	for n < len(trimmed) && trimmed[n] == char {
+		n++
+	}
+
+	// A backtick fence's info string cannot contain backticks.
+	if n < 3 || (char == '`' && strings.Contains(trimmed[n:], "`")) {
+		return ""
+	}
+
+	return trimmed[:n]
+}
+
+// isClosingFence reports whether line closes a block opened by fence.
+func isClosingFence(line, fence string) bool {
+	trimmed := strings.TrimLeft(line, " ")
+	if len(line)-len(trimmed) > 3 {
+		return false
+	}
+
+	trimmed = strings.TrimRight(trimmed, " \t\r\n")
+
+	return len(trimmed) >= len(fence) && strings.Trim(trimmed, fence[:1]) == ""
+}
+
+// convertPre renders a <pre> block as a fenced code block, keeping its text
+// exactly: no markdown markup and no entity rewriting. A language-* or lang-*
+// class on the <pre> or its <code> becomes the fence's info string.
+func (t *ContentCleanupTransformer) convertPre(n *nethtml.Node, markdown *strings.Builder) {
+	code := strings.TrimPrefix(rawText(n), "\n")
+	code = strings.TrimRight(code, "\n")
+	fence := strings.Repeat("`", max(3, longestRun(code, '`')+1))
+
+	markdown.WriteString("\n")
+	protect(markdown, fence+t.codeLanguage(n)+"\n"+code+"\n"+fence)
+	markdown.WriteString("\n\n")
+}
+
+// convertInlineCode renders <code> outside <pre> as a code span whose
+// delimiter is longer than any backtick run inside it.
+func (t *ContentCleanupTransformer) convertInlineCode(n *nethtml.Node, markdown *strings.Builder) {
+	code := rawText(n)
+	if code == "" {
+		return
+	}
+
+	delimiter := strings.Repeat("`", longestRun(code, '`')+1)
+	if strings.HasPrefix(code, "`") || strings.HasSuffix(code, "`") {
+		code = " " + code + " "
+	}
+
+	protect(markdown, delimiter+code+delimiter)
+}
+
+// convertTable renders a data table as a GFM table (or, with table_format:
+// html, as its original HTML). Layout tables, which emails use to position
+// content, are unwrapped into their cells' content instead.
+func (t *ContentCleanupTransformer) convertTable(n *nethtml.Node, markdown *strings.Builder) {
+	rows := tableRows(n)
+
+	if t.isLayoutTable(n, rows) {
+		for _, row := range rows {
+			for _, cell := range row {
+				t.convertChildNodes(cell, markdown)
+				markdown.WriteString("\n")
+			}
+		}
+
+		return
+	}
+
+	if t.getTableFormat() == tableFormatHTML {
+		var rendered strings.Builder
+		if err := nethtml.Render(&rendered, n); err == nil {
+			markdown.WriteString("\n")
+			protect(markdown, rendered.String())
+			markdown.WriteString("\n\n")
+
+			return
+		}
+	}
+
+	grid := make([][]string, len(rows))
+	width := 0
+
+	for i, row := range rows {
+		for _, cell := range row {
+			grid[i] = append(grid[i], t.tableCellText(cell))
+
+			for range t.colspan(cell) - 1 {
+				grid[i] = append(grid[i], "")
+			}
+		}
+
+		width = max(width, len(grid[i]))
+	}
+
+	markdown.WriteString("\n")
+
+	// GFM requires a header row; the first row serves as one.
+	for i, cells := range grid {
+		writeTableRow(markdown, cells, width)
+
+		if i == 0 {
+			writeTableRow(markdown, slices.Repeat([]string{"---"}, width), width)
+		}
+	}
+
+	markdown.WriteString("\n")
+}
+
+// tableCellText converts a cell to a single line of markdown. Whitespace in
+// the HTML source is collapsed; explicit breaks (<br>, paragraphs) become
+// <br>, which GFM tables render as line breaks.
+func (t *ContentCleanupTransformer) tableCellText(cell *nethtml.Node) string {
+	collapseTextWhitespace(cell)
+
+	var content strings.Builder
+
+	t.convertChildNodes(cell, &content)
+
+	var lines []string
+
+	for _, line := range strings.Split(content.String(), "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			lines = append(lines, line)
+		}
+	}
+
+	return strings.ReplaceAll(strings.Join(lines, "<br>"), "|", `\|`)
+}
+
+func writeTableRow(markdown *strings.Builder, cells []string, width int) {
+	markdown.WriteString("|")
+
+	for i := range width {
+		cell := ""
+		if i < len(cells) {
+			cell = cells[i]
+		}
+
+		markdown.WriteString(" ")
+		markdown.WriteString(cell)
+		markdown.WriteString(" |")
+	}
+
+	markdown.WriteString("\n")
+}
+
+// tableRows returns the cells of each row of table, looking through
+// thead/tbody/tfoot but not into nested tables.
+func tableRows(table *nethtml.Node) [][]*nethtml.Node {
+	var rows [][]*nethtml.Node
+
+	var walk func(n *nethtml.Node)
+
+	walk = func(n *nethtml.Node) {
+		for child := n.FirstChild; child != nil; child = child.NextSibling {
+			if child.Type != nethtml.ElementNode {
+				continue
+			}
+
+			switch child.Data {
+			case "tr":
+				var cells []*nethtml.Node
+
+				for cell := child.FirstChild; cell != nil; cell = cell.NextSibling {
+					if cell.Type == nethtml.ElementNode && (cell.Data == htmlTagTd || cell.Data == htmlTagTh) {
+						cells = append(cells, cell)
+					}
+				}
+
+				rows = append(rows, cells)
+			case "table":
+				// Rows of a nested table belong to that table.
+			default:
+				walk(child)
+			}
+		}
+	}
+
+	walk(table)
+
+	return rows
+}
+
+// isLayoutTable reports whether a table positions content rather than holding
+// data: it is marked role="presentation", has a single column, or its cells
+// contain tables or code blocks.
+func (t *ContentCleanupTransformer) isLayoutTable(table *nethtml.Node, rows [][]*nethtml.Node) bool {
+	if role := t.getAttributeValue(table, "role"); role == "presentation" || role == "none" {
+		return true
+	}
+
+	columns := 0
+
+	for _, row := range rows {
+		columns = max(columns, len(row))
+
+		for _, cell := range row {
+			if containsElement(cell, "table") || containsElement(cell, "pre") {
+				return true
+			}
+		}
+	}
+
+	return columns <= 1
+}
+
+func containsElement(n *nethtml.Node, tag string) bool {
+	for child := n.FirstChild; child != nil; child = child.NextSibling {
+		if child.Type == nethtml.ElementNode && child.Data == tag {
+			return true
+		}
+
+		if containsElement(child, tag) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (t *ContentCleanupTransformer) colspan(cell *nethtml.Node) int {
+	span, err := strconv.Atoi(t.getAttributeValue(cell, "colspan"))
+	if err != nil {
+		return 1
+	}
+
+	return min(max(span, 1), 100)
+}
+
+// collapseTextWhitespace collapses whitespace runs in the text under n, as a
+// browser would when rendering it.
+func collapseTextWhitespace(n *nethtml.Node) {
+	for child := n.FirstChild; child != nil; child = child.NextSibling {
+		if child.Type == nethtml.TextNode {
+			child.Data = htmlWhitespace.ReplaceAllString(child.Data, " ")
+		}
+
+		collapseTextWhitespace(child)
+	}
+}
+
+// rawText returns the text under n as written, with <br> as a newline.
+func rawText(n *nethtml.Node) string {
+	var text strings.Builder
+
+	var walk func(n *nethtml.Node)
+
+	walk = func(n *nethtml.Node) {
+		for child := n.FirstChild; child != nil; child = child.NextSibling {
+			switch {
+			case child.Type == nethtml.TextNode:
+				text.WriteString(child.Data)
+			case child.Type == nethtml.ElementNode && child.Data == "br":
+				text.WriteString("\n")
+			default:
+				walk(child)
+			}
+		}
+	}
+
+	walk(n)
+
+	return text.String()
+}
+
+// codeLanguage reads a language-* or lang-* class from a <pre> or the <code>
+// directly inside it.
+func (t *ContentCleanupTransformer) codeLanguage(pre *nethtml.Node) string {
+	nodes := []*nethtml.Node{pre}
+
+	for child := pre.FirstChild; child != nil; child = child.NextSibling {
+		if child.Type == nethtml.ElementNode && child.Data == "code" {
+			nodes = append(nodes, child)
+		}
+	}
+
+	for _, n := range nodes {
+		for _, class := range strings.Fields(t.getAttributeValue(n, "class")) {
+			for _, prefix := range []string{"language-", "lang-"} {
+				if lang, ok := strings.CutPrefix(class, prefix); ok && lang != "" {
+					return lang
+				}
+			}
+		}
+	}
+
+	return ""
+}
+
+// longestRun returns the length of the longest run of r in s.
+func longestRun(s string, r byte) int {
+	longest, current := 0, 0
+
+	for i := range len(s) {
+		if s[i] == r {
+			current++
+			longest = max(longest, current)
+		} else {
+			current = 0
+		}
+	}
+
+	return longest
+}
+
+// getTableFormat returns table_format: "markdown" (default) or "html".
+func (t *ContentCleanupTransformer) getTableFormat() string {
+	if val, ok := t.config["table_format"].(string); ok && val == tableFormatHTML {
+		return tableFormatHTML
+	}
+
+	return tableFormatMarkdown
+}
@@ -0,0 +1,173 @@
+package transform
+
+import (
+	"strings"
+	"testing"
+
+	"pkm-sync/pkg/models"
+)
+
+const tableAndCodeEmail = `<html><body>
+<p>Hi team, numbers for the week:</p>
+<table>
+  <thead>
+    <tr><th>Service</th><th>p95 (ms)</th><th>Notes</th></tr>
+  </thead>
+  <tbody>
+    <tr><td>api</td><td>120</td><td>down from
+        140</td></tr>
+    <tr><td><b>auth</b></td><td>95</td><td>a | b<br>rollout done</td></tr>
+  </tbody>
+</table>
+<p>The fix:</p>
+<pre><code class="language-go">if err != nil &amp;&amp; retries &lt; 3 {
+	return fmt.Errorf("retry: %w", err)
+}
+
+
+// "quoted" ****
+</code></pre>
+<p>Use <code>a &lt; b</code> in the filter.</p>
+</body></html>`
+
+func TestContentCleanup_ConvertsTablesAndCode(t *testing.T) {
+	transformer := NewContentCleanupTransformer()
+
+	got := transformer.ProcessHTMLContent(tableAndCodeEmail)
+
+	wantTable := "| Service | p95 (ms) | Notes |\n" +
+		"| --- | --- | --- |\n" +
+		"| api | 120 | down from 140 |\n" +
+		"| **auth** | 95 | a \\| b<br>rollout done |\n"
+	if !strings.Contains(got, wantTable) {
+		t.Errorf("table not converted to GFM:\n%s", got)
+	}
+
+	wantCode := "```go\n" +
+		"if err != nil && retries < 3 {\n" +
+		"\treturn fmt.Errorf(\"retry: %w\", err)\n" +
+		"}\n\n\n" +
+		"// \"quoted\" ****\n" +
+		"```"
+	if !strings.Contains(got, wantCode) {
+		t.Errorf("code block not fenced verbatim:\n%s", got)
+	}
+
+	if !strings.Contains(got, "Use `a < b` in the filter.") {
+		t.Errorf("inline code not converted:\n%s", got)
+	}
+
+	if strings.ContainsAny(got, protectStart+protectEnd) {
+		t.Errorf("protection markers leaked into output: %q", got)
+	}
+}
+
+func TestContentCleanup_TableFormatHTML(t *testing.T) {
+	transformer := NewContentCleanupTransformer()
+	if err := transformer.Configure(map[string]interface{}{"table_format": "html"}); err != nil {
+		t.Fatal(err)
+	}
+
+	got := transformer.ProcessHTMLContent(tableAndCodeEmail)
+
+	if !strings.Contains(got, "<table>") || !strings.Contains(got, "<td>a | b<br/>rollout done</td>") {
+		t.Errorf("table should be kept as HTML:\n%s", got)
+	}
+
+	if strings.Contains(got, "| --- |") {
+		t.Errorf("table should not be converted to markdown:\n%s", got)
+	}
+
+	if !strings.Contains(got, "```go\n") {
+		t.Errorf("code blocks are converted regardless of table_format:\n%s", got)
+	}
+}
+
+func TestContentCleanup_LayoutTablesUnwrapped(t *testing.T) {
+	transformer := NewContentCleanupTransformer()
+
+	got := transformer.ProcessHTMLContent(`<table><tr><td>
+		<table role="presentation"><tr><td><p>Newsletter body</p></td><td>Sidebar</td></tr></table>
+	</td></tr></table>`)
+
+	if strings.Contains(got, "| --- |") {
+		t.Errorf("layout table rendered as a GFM table:\n%s", got)
+	}
+
+	if !strings.Contains(got, "Newsletter body") || !strings.Contains(got, "Sidebar") {
+		t.Errorf("layout table content lost:\n%s", got)
+	}
+}
+
+func TestContentCleanup_TableRowsPaddedAndColspan(t *testing.T) {
+	transformer := NewContentCleanupTransformer()
+
+	got := transformer.ProcessHTMLContent(`<table>
+		<tr><td colspan="2">Total</td><td>3</td></tr>
+		<tr><td>a</td><td>b</td><td>c</td></tr>
+		<tr><td>short</td></tr>
+	</table>`)
+
+	want := "| Total |  | 3 |\n| --- | --- | --- |\n| a | b | c |\n| short |  |  |"
+	if !strings.Contains(got, want) {
+		t.Errorf("got:\n%s\nwant table:\n%s", got, want)
+	}
+}
+
+func TestContentCleanup_CodeFenceLongerThanContent(t *testing.T) {
+	transformer := NewContentCleanupTransformer()
+
+	got := transformer.ProcessHTMLContent("<pre>```\nnested fence\n```</pre>")
+
+	if !strings.HasPrefix(got, "````\n```\nnested fence\n```\n````") {
+		t.Errorf("fence must outrun backticks in the code:\n%s", got)
+	}
+}
+
+func TestContentCleanup_TransformKeepsCodeBlock(t *testing.T) {
+	transformer := NewContentCleanupTransformer()
+
+	item := models.NewBasicItem("1", "Re: Snippet")
+	item.SetContent("<p>See:</p><pre>x := 1\ny := 2</pre>")
+
+	out, err := transformer.Transform([]models.FullItem{item})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := out[0].GetContent(); !strings.Contains(got, "```\nx := 1\ny := 2\n```") {
+		t.Errorf("code block lost in Transform:\n%s", got)
+	}
+}
+
+func TestContentCleanup_FencedCodeKeepsBlankLines(t *testing.T) {
+	transformer := NewContentCleanupTransformer()
+
+	code := "```python\ndef first():\n    pass\n\n\n\ndef second():\n    return 1\n```"
+	tildeCode := "~~~\n> not a quote\n\n\n-- not a signature\n~~~"
+
+	item := models.NewBasicItem("1", "Snippet")
+	item.SetContent("\n\nIntro line\n\n\n\n" + code + "\n\n\n\nMiddle prose\n\n\n" + tildeCode + "\n\n\n\nOutro\n\n")
+
+	out, err := transformer.Transform([]models.FullItem{item})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := "Intro line\n\n" + code + "\n\nMiddle prose\n\n" + tildeCode + "\n\nOutro"
+	if got := out[0].GetContent(); got != want {
+		t.Errorf("fenced code must survive cleanup unchanged while prose is normalized:\ngot:\n%q\nwant:\n%q", got, want)
+	}
+}
+
+func TestWithFencedCodeHidden_UnclosedFenceRunsToEnd(t *testing.T) {
+	content := "Prose\n\n\n\n```\nunclosed\n\n\n\ncode"
+
+	got := withFencedCodeHidden(content, func(prose string) string {
+		return strings.ReplaceAll(prose, "\n\n\n", "\n\n")
+	})
+
+	if want := "Prose\n\n\n```\nunclosed\n\n\n\ncode"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
@@ -108,6 +108,16 @@ func (t *ContentCleanupTransformer) Transform(items []models.FullItem) ([]models
 			}
 		}
 
+		// Drop images and tracking pixels, including those already in Markdown
+		if t.shouldStripImages() || t.shouldStripTrackingPixels() {
+			cleanedContent := t.stripMarkdownImages(newItem.GetContent())
+			if cleanedContent != newItem.GetContent() {
+				newItem.SetContent(cleanedContent)
+
+				transformed = true
+			}
+		}
+
 		// Apply content cleanup
 		if t.shouldRemoveExtraWhitespace() {
 			cleanedContent := t.cleanupWhitespace(newItem.GetContent())
@@ -118,9 +128,23 @@ func (t *ContentCleanupTransformer) Transform(items []models.FullItem) ([]models
 			}
 		}
 
+		if maxBlank, ok := t.getMaxBlankLines(); ok {
+			cleanedContent := limitBlankLines(newItem.GetContent(), maxBlank)
+			if cleanedContent != newItem.GetContent() {
+				newItem.SetContent(cleanedContent)
+
+				transformed = true
+			}
+		}
+
 		// Strip quoted text if enabled
 		if t.shouldStripQuotedText() {
-			cleanedContent := t.StripQuotedText(newItem.GetContent())
+			lang := itemLanguage(item)
+			if lang == "" {
+				lang = detectLanguage(newItem.GetContent())
+			}
+
+			cleanedContent := t.stripQuotedText(newItem.GetContent(), lang)
 			if cleanedContent != newItem.GetContent() {
 				newItem.SetContent(cleanedContent)
 
@@ -161,23 +185,37 @@ func (t *ContentCleanupTransformer) ProcessHTMLContent(htmlContent string) strin
 
 	t.convertNodeToMarkdown(doc, &markdown)
 
-	result := markdown.String()
+	// Code blocks and raw HTML tables are protected from these passes.
+	result := mapUnprotected(markdown.String(), func(text string) string {
+		// Apply additional entity processing for any that weren't handled by the parser
+		text = t.unescapeHTMLEntities(text)
 
-	// Apply additional entity processing for any that weren't handled by the parser
-	result = t.unescapeHTMLEntities(result)
+		// Clean up whitespace and formatting issues
+		text = t.whitespaceCleanupRegex.ReplaceAllString(text, "\n\n")
 
-	// Clean up whitespace and formatting issues
-	result = t.whitespaceCleanupRegex.ReplaceAllString(result, "\n\n")
-
-	// Fix consecutive asterisks that can occur from malformed HTML
-	result = t.consecutiveAsterisks.ReplaceAllString(result, "***")
+		// Fix consecutive asterisks that can occur from malformed HTML
+		return t.consecutiveAsterisks.ReplaceAllString(text, "***")
+	})
 
 	return strings.TrimSpace(result)
 }
 
 // StripQuotedText removes quoted text from email content with enhanced detection.
-// Extracted from Gmail's ContentProcessor.StripQuotedText.
+// Extracted from Gmail's ContentProcessor.StripQuotedText. Reply and forward
+// headers are matched for the detected language of content.
 func (t *ContentCleanupTransformer) StripQuotedText(content string) string {
+	return t.stripQuotedText(content, detectLanguage(content))
+}
+
+func (t *ContentCleanupTransformer) stripQuotedText(content, lang string) string {
+	// Lines inside fenced code ("> prompt", "--") are not quote boundaries.
+	return withFencedCodeHidden(content, func(prose string) string {
+		return t.stripQuotedLines(prose, lang)
+	})
+}
+
+func (t *ContentCleanupTransformer) stripQuotedLines(content, lang string) string {
+	locales := patternsFor(lang, configuredLanguages(t.config))
 	lines := strings.Split(content, "\n")
 	result := make([]string, 0, len(lines))
 
@@ -189,23 +227,9 @@ func (t *ContentCleanupTransformer) StripQuotedText(content string) string {
 			break // Stop processing at first quoted line
 		}
 
-		// Check for "On [date] [person] wrote:" patterns
-		if strings.HasPrefix(trimmed, "On ") && strings.Contains(trimmed, " wrote:") {
-			break
-		}
-
-		// Check for "From: [email]" patterns (often indicates forwarded content)
-		if strings.HasPrefix(trimmed, "From: ") && strings.Contains(trimmed, "@") {
-			break
-		}
-
-		// Check for "-----Original Message-----" patterns
-		if strings.Contains(trimmed, "Original Message") || strings.Contains(trimmed, "original message") {
-			break
-		}
-
-		// Check for forwarding indicators
-		if strings.HasPrefix(trimmed, "---------- Forwarded message") {
+		// Check for "On [date] [person] wrote:" and forwarded/original message
+		// headers ("From: [email]", "-----Original Message-----") per language
+		if isQuoteBoundary(trimmed, locales) {
 			break
 		}
 
@@ -224,6 +248,17 @@ func (t *ContentCleanupTransformer) StripQuotedText(content string) string {
 	return strings.TrimSpace(strings.Join(result, "\n"))
 }
 
+// isQuoteBoundary reports whether line starts quoted or forwarded content.
+func isQuoteBoundary(line string, locales []localePatterns) bool {
+	for _, locale := range locales {
+		if matchesAnyPattern(locale.replyHeaders, line) || matchesAnyPattern(locale.forwardHeaders, line) {
+			return true
+		}
+	}
+
+	return false
+}
+
 // convertNodeToMarkdown recursively converts HTML nodes to markdown.
 // Extracted from Gmail's ContentProcessor.convertNodeToMarkdown.
 func (t *ContentCleanupTransformer) convertNodeToMarkdown(n *nethtml.Node, markdown *strings.Builder) {
@@ -272,13 +307,9 @@ func (t *ContentCleanupTransformer) convertNodeToMarkdown(n *nethtml.Node, markd
 			t.convertChildNodes(n, markdown)
 			markdown.WriteString("*")
 		case "code":
-			markdown.WriteString("`")
-			t.convertChildNodes(n, markdown)
-			markdown.WriteString("`")
+			t.convertInlineCode(n, markdown)
 		case "pre":
-			markdown.WriteString("```\n")
-			t.convertChildNodes(n, markdown)
-			markdown.WriteString("\n```\n")
+			t.convertPre(n, markdown)
 		case "blockquote":
 			// Process blockquote content and add > prefix to each line
 			var blockquoteContent strings.Builder
@@ -323,8 +354,10 @@ func (t *ContentCleanupTransformer) convertNodeToMarkdown(n *nethtml.Node, markd
 		case "img":
 			src := t.getAttributeValue(n, "src")
 			alt := t.getAttributeValue(n, "alt")
+			width := t.getAttributeValue(n, "width")
+			height := t.getAttributeValue(n, "height")
 
-			if src != "" {
+			if src != "" && !t.dropImage(src, width, height) {
 				markdown.WriteString("![")
 				markdown.WriteString(alt)
 				markdown.WriteString("](")
@@ -335,13 +368,7 @@ func (t *ContentCleanupTransformer) convertNodeToMarkdown(n *nethtml.Node, markd
 			t.convertChildNodes(n, markdown)
 			markdown.WriteString("\n")
 		case "table":
-			markdown.WriteString("\n")
-			t.convertChildNodes(n, markdown)
-			markdown.WriteString("\n")
-		case "tr":
-			t.convertTableRow(n, markdown)
-		case htmlTagTd, htmlTagTh:
-			t.convertChildNodes(n, markdown)
+			t.convertTable(n, markdown)
 		case "style", "script":
 			// Skip style and script tags completely
 			return
@@ -363,48 +390,6 @@ func (t *ContentCleanupTransformer) convertChildNodes(n *nethtml.Node, markdown
 	}
 }
 
-// convertTableRow processes a table row with proper cell separation.
-func (t *ContentCleanupTransformer) convertTableRow(n *nethtml.Node, markdown *strings.Builder) {
-	markdown.WriteString("| ")
-
-	// Count cells first
-	var cells []*nethtml.Node
-
-	for child := n.FirstChild; child != nil; child = child.NextSibling {
-		if child.Type == nethtml.ElementNode && (child.Data == "td" || child.Data == "th") {
-			cells = append(cells, child)
-		}
-	}
-
-	// Process each cell
-	for i, cell := range cells {
-		t.convertChildNodes(cell, markdown)
-
-		if i < len(cells)-1 {
-			markdown.WriteString(" | ")
-		} else {
-			// Check if this row has header cells - if so, add trailing space
-			hasHeaders := false
-
-			for _, c := range cells {
-				if c.Data == "th" {
-					hasHeaders = true
-
-					break
-				}
-			}
-
-			if hasHeaders {
-				markdown.WriteString(" | ")
-			} else {
-				markdown.WriteString(" |")
-			}
-		}
-	}
-
-	markdown.WriteString("\n")
-}
-
 // getAttributeValue gets the value of an HTML attribute.
 func (t *ContentCleanupTransformer) getAttributeValue(n *nethtml.Node, attrName string) string {
 	for _, attr := range n.Attr {
@@ -447,19 +432,20 @@ func (t *ContentCleanupTransformer) unescapeHTMLEntities(text string) string {
 	return replacer.Replace(text)
 }
 
-// cleanupWhitespace removes excessive whitespace.
+// cleanupWhitespace removes excessive whitespace from prose; fenced code
+// blocks keep their blank lines and line endings.
 func (t *ContentCleanupTransformer) cleanupWhitespace(content string) string {
-	content = strings.TrimSpace(content)
-
-	// Replace multiple newlines with double newlines
-	for strings.Contains(content, "\n\n\n") {
-		content = strings.ReplaceAll(content, "\n\n\n", "\n\n")
-	}
+	return withFencedCodeHidden(content, func(prose string) string {
+		prose = strings.TrimSpace(prose)
 
-	// Remove carriage returns
-	content = strings.ReplaceAll(content, "\r", "")
+		// Replace multiple newlines with double newlines
+		for strings.Contains(prose, "\n\n\n") {
+			prose = strings.ReplaceAll(prose, "\n\n\n", "\n\n")
+		}
 
-	return content
+		// Remove carriage returns
+		return strings.ReplaceAll(prose, "\r", "")
+	})
 }
 
 // cleanupTitle removes common email prefixes and cleans up title.
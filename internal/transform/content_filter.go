@@ -52,6 +52,11 @@ func (t *ContentFilterTransformer) Name() string {
 	return transformerNameContentFilter
 }
 
+// RunsAfter filters on the final tags and content.
+func (t *ContentFilterTransformer) RunsAfter() []string {
+	return []string{transformerNameContentCleanup, transformerNameAutoTagging}
+}
+
 // Configure parses and validates the transformer configuration.
 func (t *ContentFilterTransformer) Configure(config map[string]interface{}) error {
 	t.raw = config
@@ -328,4 +333,7 @@ func (t *ContentFilterTransformer) ruleMatches(rule FilterRule, item models.Full
 }
 
 // Ensure interface compliance.
-var _ interfaces.Transformer = (*ContentFilterTransformer)(nil)
+var (
+	_ interfaces.Transformer        = (*ContentFilterTransformer)(nil)
+	_ interfaces.OrderedTransformer = (*ContentFilterTransformer)(nil)
+)
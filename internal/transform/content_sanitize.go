@@ -0,0 +1,144 @@
+package transform
+
+import (
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// trackingPixelDomains are hosts (and their subdomains) that serve email open
+// trackers, whatever the image's size.
+var trackingPixelDomains = []string{
+	"list-manage.com",
+	"sendgrid.net",
+	"mandrillapp.com",
+	"exct.net",
+	"google-analytics.com",
+	"doubleclick.net",
+	"mailtrack.io",
+	"pixel.wp.com",
+	"track.hubspot.com",
+}
+
+var (
+	// markdownImageRegex matches ![alt](src "title"), capturing src.
+	markdownImageRegex = regexp.MustCompile(`!\[[^\]]*\]\(\s*<?([^)\s>]*)>?(?:\s+"[^"]*")?\s*\)`)
+
+	// emptyLinkRegex matches the [](href) left when a linked image is dropped.
+	emptyLinkRegex = regexp.MustCompile(`\[\s*\]\([^)]*\)`)
+)
+
+// dropImage reports whether an image is left out of the converted content:
+// every image with strip_images, tracking pixels with strip_tracking_pixels.
+// width and height are the <img> attributes, empty for Markdown images.
+func (t *ContentCleanupTransformer) dropImage(src, width, height string) bool {
+	if t.shouldStripImages() {
+		return true
+	}
+
+	return t.shouldStripTrackingPixels() && isTrackingPixel(src, width, height)
+}
+
+// stripMarkdownImages removes the Markdown images dropImage rejects from
+// content outside fenced code, then the empty [](href) links that wrapped
+// them (in Markdown, or in HTML already converted without the image).
+func (t *ContentCleanupTransformer) stripMarkdownImages(content string) string {
+	return withFencedCodeHidden(content, func(prose string) string {
+		prose = markdownImageRegex.ReplaceAllStringFunc(prose, func(image string) string {
+			if t.dropImage(markdownImageRegex.FindStringSubmatch(image)[1], "", "") {
+				return ""
+			}
+
+			return image
+		})
+
+		return emptyLinkRegex.ReplaceAllString(prose, "")
+	})
+}
+
+// isTrackingPixel reports whether an image is an open tracker: at most 1x1
+// pixels by its width and height attributes, or served from a known tracking
+// domain.
+func isTrackingPixel(src, width, height string) bool {
+	if w, ok := pixelDimension(width); ok {
+		if h, ok := pixelDimension(height); ok && w <= 1 && h <= 1 {
+			return true
+		}
+	}
+
+	parsed, err := url.Parse(src)
+	if err != nil {
+		return false
+	}
+
+	host := strings.ToLower(parsed.Hostname())
+	for _, domain := range trackingPixelDomains {
+		if host == domain || strings.HasSuffix(host, "."+domain) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// pixelDimension parses an <img> width or height such as "1" or "1px".
+func pixelDimension(value string) (int, bool) {
+	n, err := strconv.Atoi(strings.TrimSuffix(strings.TrimSpace(value), "px"))
+	if err != nil {
+		return 0, false
+	}
+
+	return n, true
+}
+
+// limitBlankLines keeps at most maxBlank consecutive blank (or whitespace
+// only) lines in the prose of content; fenced code blocks are left as is.
+func limitBlankLines(content string, maxBlank int) string {
+	return withFencedCodeHidden(content, func(prose string) string {
+		lines := strings.Split(prose, "\n")
+		kept := make([]string, 0, len(lines))
+		blank := 0
+
+		for _, line := range lines {
+			if strings.TrimSpace(line) != "" {
+				blank = 0
+				kept = append(kept, line)
+
+				continue
+			}
+
+			blank++
+			if blank <= maxBlank {
+				kept = append(kept, "")
+			}
+		}
+
+		return strings.Join(kept, "\n")
+	})
+}
+
+func (t *ContentCleanupTransformer) shouldStripImages() bool {
+	b, _ := t.config["strip_images"].(bool)
+
+	return b // Default: disabled
+}
+
+func (t *ContentCleanupTransformer) shouldStripTrackingPixels() bool {
+	b, _ := t.config["strip_tracking_pixels"].(bool)
+
+	return b // Default: disabled
+}
+
+// getMaxBlankLines returns max_blank_lines and whether it is set; unset or
+// negative leaves blank lines alone.
+func (t *ContentCleanupTransformer) getMaxBlankLines() (int, bool) {
+	switch val := t.config["max_blank_lines"].(type) {
+	case int:
+		return val, val >= 0
+	case float64:
+		return int(val), val >= 0
+	default:
+		return 0, false
+	}
+}
@@ -0,0 +1,115 @@
+package transform
+
+import (
+	"strings"
+	"testing"
+
+	"pkm-sync/pkg/models"
+)
+
+const marketingEmail = `<html><body>
+<p>Big <b>sale</b> this week!</p>
+<a href="https://shop.example.com/deals"><img src="https://cdn.example.com/banner.png" alt="Banner"></a>
+<img src="https://shop.example.com/o.gif" width="1" height="1">
+<img src="https://mc.us1.list-manage.com/track/open.php?u=1&id=2" alt="">
+<p>See you soon.</p>
+</body></html>`
+
+func cleanupContent(t *testing.T, config map[string]interface{}, content string) string {
+	t.Helper()
+
+	transformer := NewContentCleanupTransformer()
+	if err := transformer.Configure(config); err != nil {
+		t.Fatalf("Configure failed: %v", err)
+	}
+
+	item := models.NewBasicItem("1", "Sale")
+	item.SetContent(content)
+
+	result, err := transformer.Transform([]models.FullItem{item})
+	if err != nil {
+		t.Fatalf("Transform failed: %v", err)
+	}
+
+	return result[0].GetContent()
+}
+
+func TestContentCleanup_ImagesKeptByDefault(t *testing.T) {
+	got := cleanupContent(t, nil, marketingEmail)
+
+	for _, want := range []string{"banner.png", "o.gif", "list-manage.com"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("Expected %q in default output:\n%s", want, got)
+		}
+	}
+}
+
+func TestContentCleanup_StripTrackingPixels(t *testing.T) {
+	got := cleanupContent(t, map[string]interface{}{"strip_tracking_pixels": true}, marketingEmail)
+
+	if !strings.Contains(got, "[![Banner](https://cdn.example.com/banner.png)](https://shop.example.com/deals)") {
+		t.Errorf("Expected the linked banner to be kept:\n%s", got)
+	}
+
+	for _, gone := range []string{"o.gif", "list-manage.com"} {
+		if strings.Contains(got, gone) {
+			t.Errorf("Expected tracking pixel %q to be stripped:\n%s", gone, got)
+		}
+	}
+}
+
+func TestContentCleanup_StripImages(t *testing.T) {
+	got := cleanupContent(t, map[string]interface{}{"strip_images": true}, marketingEmail)
+
+	if strings.Contains(got, "![") || strings.Contains(got, "](") {
+		t.Errorf("Expected every image and the emptied link to be stripped:\n%s", got)
+	}
+
+	if !strings.Contains(got, "Big **sale** this week!") || !strings.Contains(got, "See you soon.") {
+		t.Errorf("Expected the text to be kept:\n%s", got)
+	}
+
+	markdown := "Logo ![logo](https://example.com/logo.png \"Logo\")\n\n```\n![code](x.png)\n```"
+	got = cleanupContent(t, map[string]interface{}{"strip_images": true}, markdown)
+
+	if want := "Logo \n\n```\n![code](x.png)\n```"; got != want {
+		t.Errorf("Expected Markdown images outside code to be stripped\nwant %q\ngot  %q", want, got)
+	}
+}
+
+func TestContentCleanup_MaxBlankLines(t *testing.T) {
+	content := "one\n\n\n  \n\ntwo\n\n```\na\n\n\n\nb\n```"
+	config := map[string]interface{}{"remove_extra_whitespace": false, "max_blank_lines": 1}
+
+	got := cleanupContent(t, config, content)
+	if want := "one\n\ntwo\n\n```\na\n\n\n\nb\n```"; got != want {
+		t.Errorf("max_blank_lines 1\nwant %q\ngot  %q", want, got)
+	}
+
+	config["max_blank_lines"] = float64(0)
+
+	got = cleanupContent(t, config, content)
+	if want := "one\ntwo\n```\na\n\n\n\nb\n```"; got != want {
+		t.Errorf("max_blank_lines 0\nwant %q\ngot  %q", want, got)
+	}
+}
+
+func TestIsTrackingPixel(t *testing.T) {
+	tests := []struct {
+		src, width, height string
+		want               bool
+	}{
+		{"https://example.com/p.gif", "1", "1", true},
+		{"https://example.com/p.gif", "1px", "0", true},
+		{"https://example.com/p.gif", "1", "", false},
+		{"https://example.com/logo.png", "120", "40", false},
+		{"https://u123.ct.sendgrid.net/wf/open?upn=x", "", "", true},
+		{"https://notsendgrid.net/logo.png", "", "", false},
+	}
+
+	for _, tt := range tests {
+		if got := isTrackingPixel(tt.src, tt.width, tt.height); got != tt.want {
+			t.Errorf("isTrackingPixel(%q, %q, %q) = %v, want %v", tt.src, tt.width, tt.height, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,180 @@
+package transform
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"pkm-sync/pkg/interfaces"
+	"pkm-sync/pkg/models"
+)
+
+const (
+	transformerNameDedup = "dedup"
+
+	// metadataKeyDedupedFrom lists the IDs of the exact duplicates dropped in
+	// favor of the item that was kept.
+	metadataKeyDedupedFrom = "deduped_from"
+
+	DedupStrategyID      = "id"
+	DedupStrategyTitle   = "title"
+	DedupStrategyContent = "content"
+	DedupStrategyNone    = "none"
+)
+
+// DeduplicationTransformer drops items whose ID, title or normalized content
+// was already seen earlier in the batch, e.g. the same email synced from two
+// accounts. The first item wins and records the dropped IDs. Unlike
+// fuzzy_dedup it only collapses exact matches, so it is cheap on any batch.
+type DeduplicationTransformer struct {
+	config   map[string]interface{}
+	strategy string
+}
+
+func NewDeduplicationTransformer() *DeduplicationTransformer {
+	return &DeduplicationTransformer{
+		config:   make(map[string]interface{}),
+		strategy: DedupStrategyID,
+	}
+}
+
+func (t *DeduplicationTransformer) Name() string {
+	return transformerNameDedup
+}
+
+// RunsAfter compares content once markup, quoted text and signatures are gone.
+func (t *DeduplicationTransformer) RunsAfter() []string {
+	return []string{transformerNameContentCleanup, transformerNameSignatureRemoval}
+}
+
+// Configure accepts:
+//   - strategy: what makes two items duplicates, "id" (default), "title",
+//     "content" (lower-cased, whitespace-collapsed SHA-256) or "none"
+func (t *DeduplicationTransformer) Configure(config map[string]interface{}) error {
+	t.config = config
+	t.strategy = DedupStrategyID
+
+	if raw, exists := config["strategy"]; exists {
+		strategy, _ := raw.(string)
+
+		switch strategy {
+		case DedupStrategyID, DedupStrategyTitle, DedupStrategyContent, DedupStrategyNone:
+			t.strategy = strategy
+		default:
+			return fmt.Errorf("dedup: strategy must be %q, %q, %q or %q, got %v",
+				DedupStrategyID, DedupStrategyTitle, DedupStrategyContent, DedupStrategyNone, raw)
+		}
+	}
+
+	return nil
+}
+
+func (t *DeduplicationTransformer) Transform(items []models.FullItem) ([]models.FullItem, error) {
+	if t.strategy == DedupStrategyNone || len(items) < 2 {
+		return items, nil
+	}
+
+	// first maps a dedup key to the index of the item that claimed it in kept.
+	first := make(map[string]int, len(items))
+	kept := make([]models.FullItem, 0, len(items))
+	dropped := make(map[int][]string)
+
+	for _, item := range items {
+		key := t.key(item)
+		if key == "" {
+			kept = append(kept, item)
+
+			continue
+		}
+
+		if i, seen := first[key]; seen {
+			dropped[i] = append(dropped[i], item.GetID())
+
+			continue
+		}
+
+		first[key] = len(kept)
+		kept = append(kept, item)
+	}
+
+	for _, i := range slices.Sorted(maps.Keys(dropped)) {
+		kept[i] = withMetadata(kept[i], map[string]interface{}{metadataKeyDedupedFrom: dropped[i]})
+	}
+
+	return kept, nil
+}
+
+// key returns the value two items must share to be duplicates under the
+// strategy. Items with an empty key (no title, no content) are never dropped.
+func (t *DeduplicationTransformer) key(item models.FullItem) string {
+	switch t.strategy {
+	case DedupStrategyTitle:
+		return strings.TrimSpace(item.GetTitle())
+	case DedupStrategyContent:
+		return contentHash(item.GetContent())
+	default:
+		return item.GetID()
+	}
+}
+
+// contentHash is the SHA-256 of content lower-cased with runs of whitespace
+// collapsed, so re-wrapped or re-indented copies hash alike. Blank content
+// hashes to "".
+func contentHash(content string) string {
+	normalized := strings.Join(strings.Fields(strings.ToLower(content)), " ")
+	if normalized == "" {
+		return ""
+	}
+
+	sum := sha256.Sum256([]byte(normalized))
+
+	return hex.EncodeToString(sum[:])
+}
+
+// WithDeduplication returns config with the dedup transformer set to strategy,
+// for sync.deduplicate_by. An empty or "none" strategy returns config as is,
+// and an explicit transformers.dedup entry wins over it. When the pipeline is
+// disabled only dedup is enabled, so no other configured transformer starts
+// running.
+func WithDeduplication(config models.TransformConfig, strategy string) models.TransformConfig {
+	if strategy == "" || strategy == DedupStrategyNone {
+		return config
+	}
+
+	if config.Enabled {
+		if _, explicit := config.Transformers[transformerNameDedup]; explicit {
+			return config
+		}
+
+		config.Transformers = maps.Clone(config.Transformers)
+		if config.Transformers == nil {
+			config.Transformers = make(map[string]map[string]interface{})
+		}
+
+		config.Transformers[transformerNameDedup] = map[string]interface{}{"strategy": strategy}
+
+		if len(config.PipelineOrder) > 0 && !slices.Contains(config.PipelineOrder, transformerNameDedup) {
+			config.PipelineOrder = append(slices.Clone(config.PipelineOrder), transformerNameDedup)
+		}
+
+		return config
+	}
+
+	return models.TransformConfig{
+		Enabled:       true,
+		PipelineOrder: []string{transformerNameDedup},
+		ErrorStrategy: config.ErrorStrategy,
+		Transformers: map[string]map[string]interface{}{
+			transformerNameDedup: {"strategy": strategy},
+		},
+	}
+}
+
+// Ensure interface compliance.
+var (
+	_ interfaces.Transformer        = (*DeduplicationTransformer)(nil)
+	_ interfaces.OrderedTransformer = (*DeduplicationTransformer)(nil)
+)
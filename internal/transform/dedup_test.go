@@ -0,0 +1,148 @@
+package transform
+
+import (
+	"reflect"
+	"testing"
+
+	"pkm-sync/pkg/models"
+)
+
+func newDedup(t *testing.T, strategy string) *DeduplicationTransformer {
+	t.Helper()
+
+	transformer := NewDeduplicationTransformer()
+	if err := transformer.Configure(map[string]interface{}{"strategy": strategy}); err != nil {
+		t.Fatalf("Configure failed: %v", err)
+	}
+
+	return transformer
+}
+
+func dedupItem(id, title, content string) models.FullItem {
+	item := models.NewBasicItem(id, title)
+	item.SetContent(content)
+
+	return item
+}
+
+func itemIDs(items []models.FullItem) []string {
+	ids := make([]string, len(items))
+	for i, item := range items {
+		ids[i] = item.GetID()
+	}
+
+	return ids
+}
+
+func TestDedup_Strategies(t *testing.T) {
+	items := []models.FullItem{
+		dedupItem("a", "Weekly report", "Numbers are UP this week."),
+		dedupItem("b", "Fwd: Weekly report", "numbers are up\n   this   week."),
+		dedupItem("a", "Weekly report (copy)", "Something else"),
+		dedupItem("c", "Weekly report", "Different body"),
+	}
+
+	tests := []struct {
+		strategy string
+		wantIDs  []string
+		wantFrom []string // deduped_from on the first item
+	}{
+		{DedupStrategyID, []string{"a", "b", "c"}, []string{"a"}},
+		{DedupStrategyTitle, []string{"a", "b", "a"}, []string{"c"}},
+		{DedupStrategyContent, []string{"a", "a", "c"}, []string{"b"}},
+		{DedupStrategyNone, []string{"a", "b", "a", "c"}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.strategy, func(t *testing.T) {
+			got, err := newDedup(t, tt.strategy).Transform(items)
+			if err != nil {
+				t.Fatalf("Transform failed: %v", err)
+			}
+
+			if ids := itemIDs(got); !reflect.DeepEqual(ids, tt.wantIDs) {
+				t.Errorf("IDs = %v, want %v", ids, tt.wantIDs)
+			}
+
+			from, _ := got[0].GetMetadata()[metadataKeyDedupedFrom].([]string)
+			if !reflect.DeepEqual(from, tt.wantFrom) {
+				t.Errorf("deduped_from = %v, want %v", from, tt.wantFrom)
+			}
+		})
+	}
+
+	if _, ok := items[0].GetMetadata()[metadataKeyDedupedFrom]; ok {
+		t.Error("Transform modified the input item's metadata")
+	}
+}
+
+func TestDedup_BlankKeysAreKept(t *testing.T) {
+	items := []models.FullItem{dedupItem("a", "", "  "), dedupItem("b", "", "")}
+
+	got, err := newDedup(t, DedupStrategyContent).Transform(items)
+	if err != nil {
+		t.Fatalf("Transform failed: %v", err)
+	}
+
+	if len(got) != 2 {
+		t.Errorf("got %d items, want both blank items kept", len(got))
+	}
+}
+
+func TestDedup_InvalidStrategy(t *testing.T) {
+	err := NewDeduplicationTransformer().Configure(map[string]interface{}{"strategy": "fuzzy"})
+	if err == nil {
+		t.Fatal("expected an error for an unknown strategy")
+	}
+}
+
+func TestWithDeduplication(t *testing.T) {
+	t.Run("none leaves config alone", func(t *testing.T) {
+		cfg := models.TransformConfig{Enabled: false}
+		if got := WithDeduplication(cfg, DedupStrategyNone); !reflect.DeepEqual(got, cfg) {
+			t.Errorf("got %+v, want unchanged", got)
+		}
+	})
+
+	t.Run("disabled pipeline runs only dedup", func(t *testing.T) {
+		cfg := models.TransformConfig{Transformers: map[string]map[string]interface{}{"auto_tagging": {}}}
+
+		got := WithDeduplication(cfg, DedupStrategyContent)
+		if !got.Enabled || !reflect.DeepEqual(got.PipelineOrder, []string{"dedup"}) {
+			t.Errorf("got %+v, want enabled with only dedup", got)
+		}
+
+		if got.Transformers["dedup"]["strategy"] != DedupStrategyContent {
+			t.Errorf("dedup config = %v", got.Transformers["dedup"])
+		}
+	})
+
+	t.Run("enabled pipeline gains dedup", func(t *testing.T) {
+		cfg := models.TransformConfig{
+			Enabled:       true,
+			PipelineOrder: []string{"content_cleanup"},
+			Transformers:  map[string]map[string]interface{}{"content_cleanup": {}},
+		}
+
+		got := WithDeduplication(cfg, DedupStrategyTitle)
+		if !reflect.DeepEqual(got.PipelineOrder, []string{"content_cleanup", "dedup"}) {
+			t.Errorf("PipelineOrder = %v", got.PipelineOrder)
+		}
+
+		if _, ok := cfg.Transformers["dedup"]; ok {
+			t.Error("WithDeduplication modified the caller's transformer map")
+		}
+	})
+
+	t.Run("explicit dedup entry wins", func(t *testing.T) {
+		cfg := models.TransformConfig{
+			Enabled:      true,
+			Transformers: map[string]map[string]interface{}{"dedup": {"strategy": "id"}},
+		}
+
+		got := WithDeduplication(cfg, DedupStrategyContent)
+		if got.Transformers["dedup"]["strategy"] != "id" {
+			t.Errorf("strategy = %v, want the explicit id", got.Transformers["dedup"]["strategy"])
+		}
+	})
+}
@@ -0,0 +1,290 @@
+package transform
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"pkm-sync/internal/naming"
+	"pkm-sync/pkg/interfaces"
+	"pkm-sync/pkg/models"
+)
+
+const (
+	transformerNameDriveAttachmentLinks = "drive_attachment_links"
+
+	sourceTypeGoogleDrive = "google_drive"
+
+	linkStyleWikilink = "wikilink"
+	linkStyleMarkdown = "markdown"
+
+	// metadataKeyLinkedDriveFiles lists the Drive file IDs an event now links to.
+	metadataKeyLinkedDriveFiles = "linked_drive_files"
+	// metadataKeyLinkedFromEvents lists the calendar event IDs that attach a Drive file.
+	metadataKeyLinkedFromEvents = "linked_from_events"
+
+	defaultRelatedDocsHeading = "## Related Documents"
+)
+
+// DriveDocIndex records the Drive documents synced during one run so that a
+// calendar batch transformed separately (the sync command runs each source type
+// in its own pipeline) can still link to them. It is safe for concurrent use.
+type DriveDocIndex struct {
+	mu     sync.Mutex
+	titles map[string]string
+}
+
+// NewDriveDocIndex returns an empty index.
+func NewDriveDocIndex() *DriveDocIndex {
+	return &DriveDocIndex{titles: make(map[string]string)}
+}
+
+// Record remembers a synced Drive document by file ID.
+func (d *DriveDocIndex) Record(id, title string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.titles[id] = title
+}
+
+// Lookup returns the title of a recorded Drive document.
+func (d *DriveDocIndex) Lookup(id string) (string, bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	title, ok := d.titles[id]
+
+	return title, ok
+}
+
+// DriveAttachmentLinksTransformer stores a Drive document once when it is both
+// attached to a calendar event and synced by a Drive source in the same run.
+// The event's attachment entry is replaced by a link to the Drive note, and the
+// Drive note records which events reference it. Attachments whose file is not
+// part of the run are left alone.
+type DriveAttachmentLinksTransformer struct {
+	config map[string]interface{}
+	index  *DriveDocIndex
+}
+
+func NewDriveAttachmentLinksTransformer() *DriveAttachmentLinksTransformer {
+	return &DriveAttachmentLinksTransformer{
+		config: make(map[string]interface{}),
+	}
+}
+
+// SetDriveDocIndex shares an index across pipelines of the same run: Drive items
+// transformed here are recorded in it, and calendar attachments are matched
+// against it as well as against Drive items in the same batch.
+func (t *DriveAttachmentLinksTransformer) SetDriveDocIndex(index *DriveDocIndex) {
+	t.index = index
+}
+
+func (t *DriveAttachmentLinksTransformer) Name() string {
+	return transformerNameDriveAttachmentLinks
+}
+
+// Configure accepts:
+//   - link_style: "wikilink" (default) or "markdown"
+//   - target: "obsidian" (default) or "logseq"; decides how note filenames are derived
+//   - link_path_prefix: path prepended to markdown link targets, e.g. "../drive/" (default "")
+//   - keep_attachments: keep the event's attachment entry alongside the link (default false)
+//   - heading: heading for the links section (default "## Related Documents")
+func (t *DriveAttachmentLinksTransformer) Configure(config map[string]interface{}) error {
+	t.config = config
+
+	if style := t.linkStyle(); style != linkStyleWikilink && style != linkStyleMarkdown {
+		return fmt.Errorf("drive_attachment_links: link_style must be %q or %q, got %q",
+			linkStyleWikilink, linkStyleMarkdown, style)
+	}
+
+	if target := t.target(); target != "obsidian" && target != "logseq" {
+		return fmt.Errorf("drive_attachment_links: target must be \"obsidian\" or \"logseq\", got %q", target)
+	}
+
+	return nil
+}
+
+func (t *DriveAttachmentLinksTransformer) Transform(items []models.FullItem) ([]models.FullItem, error) {
+	driveTitles := make(map[string]string)
+
+	for _, item := range items {
+		if item.GetSourceType() == sourceTypeGoogleDrive {
+			driveTitles[item.GetID()] = item.GetTitle()
+
+			if t.index != nil {
+				t.index.Record(item.GetID(), item.GetTitle())
+			}
+		}
+	}
+
+	if len(driveTitles) == 0 && t.index == nil {
+		return items, nil
+	}
+
+	transformedItems := make([]models.FullItem, len(items))
+	linkedFrom := make(map[string][]string)
+
+	for i, item := range items {
+		if item.GetSourceType() != models.SourceTypeGoogleCalendar {
+			transformedItems[i] = item
+
+			continue
+		}
+
+		var (
+			kept         []models.Attachment
+			linkedIDs    []string
+			linkedTitles []string
+		)
+
+		for _, attachment := range item.GetAttachments() {
+			title, ok := t.lookupDriveDoc(driveTitles, attachment.ID)
+			if !ok {
+				kept = append(kept, attachment)
+
+				continue
+			}
+
+			if t.shouldKeepAttachments() {
+				kept = append(kept, attachment)
+			}
+
+			linkedIDs = append(linkedIDs, attachment.ID)
+			linkedTitles = append(linkedTitles, title)
+			linkedFrom[attachment.ID] = append(linkedFrom[attachment.ID], item.GetID())
+		}
+
+		if len(linkedIDs) == 0 {
+			transformedItems[i] = item
+
+			continue
+		}
+
+		newItem := withMetadata(item, map[string]interface{}{metadataKeyLinkedDriveFiles: linkedIDs})
+		newItem.SetAttachments(kept)
+		newItem.SetContent(t.appendLinks(item.GetContent(), linkedTitles))
+		transformedItems[i] = newItem
+	}
+
+	for i, item := range transformedItems {
+		if events, ok := linkedFrom[item.GetID()]; ok && item.GetSourceType() == sourceTypeGoogleDrive {
+			transformedItems[i] = withMetadata(item, map[string]interface{}{metadataKeyLinkedFromEvents: events})
+		}
+	}
+
+	return transformedItems, nil
+}
+
+// lookupDriveDoc finds a Drive document synced in this batch or, through the
+// shared index, earlier in the same run.
+func (t *DriveAttachmentLinksTransformer) lookupDriveDoc(batch map[string]string, id string) (string, bool) {
+	if id == "" {
+		return "", false
+	}
+
+	if title, ok := batch[id]; ok {
+		return title, true
+	}
+
+	if t.index != nil {
+		return t.index.Lookup(id)
+	}
+
+	return "", false
+}
+
+// appendLinks adds a section linking to each Drive note after the event's content.
+func (t *DriveAttachmentLinksTransformer) appendLinks(content string, titles []string) string {
+	var b strings.Builder
+
+	b.WriteString(strings.TrimRight(content, "\n"))
+
+	if content != "" {
+		b.WriteString("\n\n")
+	}
+
+	b.WriteString(t.heading())
+	b.WriteString("\n\n")
+
+	for _, title := range titles {
+		b.WriteString("- ")
+		b.WriteString(t.NoteLink(title))
+		b.WriteString("\n")
+	}
+
+	return b.String()
+}
+
+// NoteLink returns a link to the note the configured target writes for title.
+func (t *DriveAttachmentLinksTransformer) NoteLink(title string) string {
+	filename := naming.Slug(title)
+	if t.target() == "logseq" {
+		filename = naming.Clean(title)
+	}
+
+	if t.linkStyle() == linkStyleMarkdown {
+		return fmt.Sprintf("[%s](%s%s.md)", title, t.linkPathPrefix(), strings.ReplaceAll(filename, " ", "%20"))
+	}
+
+	if filename == title {
+		return "[[" + title + "]]"
+	}
+
+	return "[[" + filename + "|" + title + "]]"
+}
+
+// Configuration helper methods
+
+func (t *DriveAttachmentLinksTransformer) linkStyle() string {
+	if val, exists := t.config["link_style"]; exists {
+		if s, ok := val.(string); ok && s != "" {
+			return s
+		}
+	}
+
+	return linkStyleWikilink
+}
+
+func (t *DriveAttachmentLinksTransformer) target() string {
+	if val, exists := t.config["target"]; exists {
+		if s, ok := val.(string); ok && s != "" {
+			return s
+		}
+	}
+
+	return "obsidian"
+}
+
+func (t *DriveAttachmentLinksTransformer) linkPathPrefix() string {
+	if val, exists := t.config["link_path_prefix"]; exists {
+		if s, ok := val.(string); ok {
+			return s
+		}
+	}
+
+	return ""
+}
+
+func (t *DriveAttachmentLinksTransformer) shouldKeepAttachments() bool {
+	if val, exists := t.config["keep_attachments"]; exists {
+		if b, ok := val.(bool); ok {
+			return b
+		}
+	}
+
+	return false
+}
+
+func (t *DriveAttachmentLinksTransformer) heading() string {
+	if val, exists := t.config["heading"]; exists {
+		if s, ok := val.(string); ok && s != "" {
+			return s
+		}
+	}
+
+	return defaultRelatedDocsHeading
+}
+
+// Ensure interface compliance.
+var _ interfaces.Transformer = (*DriveAttachmentLinksTransformer)(nil)
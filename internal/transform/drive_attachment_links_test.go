@@ -0,0 +1,155 @@
+package transform
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"pkm-sync/pkg/models"
+)
+
+func newCalendarEvent(id, title string, attachments ...models.Attachment) models.FullItem {
+	item := models.NewBasicItem(id, title)
+	item.SetSourceType(models.SourceTypeGoogleCalendar)
+	item.SetItemType("event")
+	item.SetContent("Agenda")
+	item.SetAttachments(attachments)
+
+	return item
+}
+
+func newDriveDoc(id, title string) models.FullItem {
+	item := models.NewBasicItem(id, title)
+	item.SetSourceType(sourceTypeGoogleDrive)
+	item.SetItemType("document")
+	item.SetContent("# " + title)
+
+	return item
+}
+
+func TestDriveAttachmentLinks_LinksSyncedDocOnce(t *testing.T) {
+	transformer := NewDriveAttachmentLinksTransformer()
+
+	event := newCalendarEvent("evt1", "Planning",
+		models.Attachment{ID: "doc1", Name: "Q3 Plan", URL: "https://docs.google.com/document/d/doc1/edit"},
+		models.Attachment{ID: "doc2", Name: "Unsynced Sheet", URL: "https://docs.google.com/spreadsheets/d/doc2"},
+	)
+	doc := newDriveDoc("doc1", "Q3 Plan")
+
+	out, err := transformer.Transform([]models.FullItem{event, doc})
+	if err != nil {
+		t.Fatalf("Transform failed: %v", err)
+	}
+
+	if len(out) != 2 {
+		t.Fatalf("expected 2 items, got %d", len(out))
+	}
+
+	gotEvent := out[0]
+
+	attachments := gotEvent.GetAttachments()
+	if len(attachments) != 1 || attachments[0].ID != "doc2" {
+		t.Errorf("expected only the unsynced attachment to remain, got %+v", attachments)
+	}
+
+	if !strings.Contains(gotEvent.GetContent(), "## Related Documents\n\n- [[Q3-Plan|Q3 Plan]]") {
+		t.Errorf("expected wikilink to the Drive note, got:\n%s", gotEvent.GetContent())
+	}
+
+	if got := gotEvent.GetMetadata()[metadataKeyLinkedDriveFiles]; !reflect.DeepEqual(got, []string{"doc1"}) {
+		t.Errorf("linked_drive_files = %v, want [doc1]", got)
+	}
+
+	if got := out[1].GetMetadata()[metadataKeyLinkedFromEvents]; !reflect.DeepEqual(got, []string{"evt1"}) {
+		t.Errorf("linked_from_events = %v, want [evt1]", got)
+	}
+
+	if len(event.GetAttachments()) != 2 {
+		t.Error("expected the input event to be left unmodified")
+	}
+}
+
+func TestDriveAttachmentLinks_NoDriveItemsPassThrough(t *testing.T) {
+	transformer := NewDriveAttachmentLinksTransformer()
+	event := newCalendarEvent("evt1", "Planning", models.Attachment{ID: "doc1", Name: "Q3 Plan"})
+
+	out, err := transformer.Transform([]models.FullItem{event})
+	if err != nil {
+		t.Fatalf("Transform failed: %v", err)
+	}
+
+	if out[0] != event || len(out[0].GetAttachments()) != 1 {
+		t.Error("expected event without synced Drive docs to pass through unchanged")
+	}
+}
+
+func TestDriveAttachmentLinks_MarkdownAndKeepAttachments(t *testing.T) {
+	transformer := NewDriveAttachmentLinksTransformer()
+
+	err := transformer.Configure(map[string]interface{}{
+		"link_style":       "markdown",
+		"target":           "logseq",
+		"link_path_prefix": "../drive/",
+		"keep_attachments": true,
+	})
+	if err != nil {
+		t.Fatalf("Configure failed: %v", err)
+	}
+
+	event := newCalendarEvent("evt1", "Planning", models.Attachment{ID: "doc1", Name: "Q3 Plan"})
+
+	out, err := transformer.Transform([]models.FullItem{event, newDriveDoc("doc1", "Q3 Plan")})
+	if err != nil {
+		t.Fatalf("Transform failed: %v", err)
+	}
+
+	if !strings.Contains(out[0].GetContent(), "- [Q3 Plan](../drive/Q3%20Plan.md)") {
+		t.Errorf("expected markdown link to the Logseq page, got:\n%s", out[0].GetContent())
+	}
+
+	if len(out[0].GetAttachments()) != 1 {
+		t.Error("expected keep_attachments to retain the attachment entry")
+	}
+}
+
+func TestDriveAttachmentLinks_InvalidConfig(t *testing.T) {
+	tests := []map[string]interface{}{
+		{"link_style": "html"},
+		{"target": "notion"},
+	}
+
+	for _, cfg := range tests {
+		if err := NewDriveAttachmentLinksTransformer().Configure(cfg); err == nil {
+			t.Errorf("expected error for config %v", cfg)
+		}
+	}
+}
+
+func TestDriveAttachmentLinks_SharedIndexAcrossBatches(t *testing.T) {
+	index := NewDriveDocIndex()
+
+	driveRun := NewDriveAttachmentLinksTransformer()
+	driveRun.SetDriveDocIndex(index)
+
+	if _, err := driveRun.Transform([]models.FullItem{newDriveDoc("doc1", "Q3 Plan")}); err != nil {
+		t.Fatalf("Drive Transform failed: %v", err)
+	}
+
+	calendarRun := NewDriveAttachmentLinksTransformer()
+	calendarRun.SetDriveDocIndex(index)
+
+	event := newCalendarEvent("evt1", "Planning", models.Attachment{ID: "doc1", Name: "Q3 Plan"})
+
+	out, err := calendarRun.Transform([]models.FullItem{event})
+	if err != nil {
+		t.Fatalf("Calendar Transform failed: %v", err)
+	}
+
+	if len(out[0].GetAttachments()) != 0 {
+		t.Errorf("expected attachment synced by the Drive batch to be replaced, got %+v", out[0].GetAttachments())
+	}
+
+	if !strings.Contains(out[0].GetContent(), "[[Q3-Plan|Q3 Plan]]") {
+		t.Errorf("expected link to Drive note, got:\n%s", out[0].GetContent())
+	}
+}
@@ -31,6 +31,11 @@ func (t *FilterTransformer) Name() string {
 	return transformerNameFilter
 }
 
+// RunsAfter makes required_tags see the tags auto_tagging adds.
+func (t *FilterTransformer) RunsAfter() []string {
+	return []string{transformerNameAutoTagging}
+}
+
 func (t *FilterTransformer) Configure(config map[string]interface{}) error {
 	t.config = config
 
@@ -175,13 +180,21 @@ func GetAllExampleTransformers() []interfaces.Transformer {
 // These include the enhanced transformers extracted from Gmail processing logic.
 func GetAllContentProcessingTransformers() []interfaces.Transformer {
 	return []interfaces.Transformer{
-		NewContentCleanupTransformer(),      // Enhanced HTML processing from content_cleanup.go
-		NewLinkExtractionTransformer(),      // URL extraction from link_extraction.go
-		NewSignatureRemovalTransformer(),    // Signature detection from signature_removal.go
-		NewThreadGroupingTransformer(),      // Thread consolidation from thread_grouping.go
-		NewEnhancedAutoTaggingTransformer(), // Pattern/regex tagging from auto_tagging.go
-		NewContentFilterTransformer(),       // Include/exclude filtering from content_filter.go
-		NewFilterTransformer(),              // Legacy filter transformer
-		NewAIAnalysisTransformer(),          // AI-powered content analysis (disabled until configured)
+		NewContentCleanupTransformer(),        // Enhanced HTML processing from content_cleanup.go
+		NewLinkExtractionTransformer(),        // URL extraction from link_extraction.go
+		NewSignatureRemovalTransformer(),      // Signature detection from signature_removal.go
+		NewThreadGroupingTransformer(),        // Thread consolidation from thread_grouping.go
+		NewEnhancedAutoTaggingTransformer(),   // Pattern/regex tagging from auto_tagging.go
+		NewContentFilterTransformer(),         // Include/exclude filtering from content_filter.go
+		NewFilterTransformer(),                // Legacy filter transformer
+		NewAIAnalysisTransformer(),            // AI-powered content analysis (disabled until configured)
+		NewActionItemsTransformer(),           // TODO/action item extraction from action_items.go
+		NewDriveAttachmentLinksTransformer(),  // Calendar/Drive attachment dedup from drive_attachment_links.go
+		NewPriorityScoringTransformer(),       // Email importance scoring from priority_scoring.go
+		NewFuzzyDedupTransformer(),            // Near-duplicate collapsing from fuzzy_dedup.go
+		NewDeduplicationTransformer(),         // Exact ID/title/content dedup from dedup.go
+		NewMetadataRulesTransformer(),         // Rule-based metadata injection from metadata_rules.go
+		NewLinkNormalizationTransformer(),     // Canonical URLs and merged duplicate links from link_normalization.go
+		NewAttachmentLinkRewriteTransformer(), // Local links to downloaded attachments from attachment_link_rewrite.go
 	}
 }
@@ -263,17 +263,19 @@ func TestFilterTransformerInvalidConfig(t *testing.T) {
 func TestGetAllExampleTransformers(t *testing.T) {
 	// GetAllExampleTransformers returns all registered transformers
 	// (content_cleanup, link_extraction, signature_removal, thread_grouping,
-	// auto_tagging, content_filter, filter, ai_analysis).
+	// auto_tagging, content_filter, filter, ai_analysis, action_items,
+	// drive_attachment_links, priority_scoring, fuzzy_dedup, dedup,
+	// metadata_rules, link_normalization).
 	transformers := GetAllExampleTransformers()
-	if len(transformers) != 8 {
-		t.Errorf("Expected 8 transformers, got %d", len(transformers))
+	if len(transformers) != 16 {
+		t.Errorf("Expected 16 transformers, got %d", len(transformers))
 	}
 }
 
 func TestGetAllContentProcessingTransformers(t *testing.T) {
 	transformers := GetAllContentProcessingTransformers()
-	if len(transformers) != 8 {
-		t.Errorf("Expected 8 content processing transformers, got %d", len(transformers))
+	if len(transformers) != 16 {
+		t.Errorf("Expected 16 content processing transformers, got %d", len(transformers))
 	}
 }
 
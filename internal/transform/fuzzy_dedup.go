@@ -0,0 +1,455 @@
+package transform
+
+import (
+	"fmt"
+	"hash/fnv"
+	"math"
+	"slices"
+	"strings"
+	"unicode"
+
+	"pkm-sync/pkg/interfaces"
+	"pkm-sync/pkg/models"
+)
+
+const (
+	transformerNameFuzzyDedup = "fuzzy_dedup"
+
+	// metadataKeyMergedDuplicates lists the IDs of the near-duplicates folded
+	// into the item that was kept.
+	metadataKeyMergedDuplicates = "merged_duplicates"
+
+	defaultFuzzyDedupThreshold = 0.85
+	defaultFuzzyDedupShingle   = 3
+	defaultFuzzyDedupHashes    = 128
+
+	fuzzyDedupKeepLongest  = "longest"
+	fuzzyDedupKeepEarliest = "earliest"
+)
+
+// FuzzyDedupTransformer collapses items whose content is nearly identical (an
+// email and its forward, a document and its copy). Content is split into word
+// shingles; MinHash signatures bucketed by locality-sensitive hashing find
+// candidate pairs cheaply, and a pair is a duplicate when the Jaccard
+// similarity of its shingle sets reaches the threshold. Each cluster keeps one
+// item (the longest or the earliest) carrying the tags and links of all.
+type FuzzyDedupTransformer struct {
+	config      map[string]interface{}
+	threshold   float64
+	shingleSize int
+	numHashes   int
+	keep        string
+}
+
+func NewFuzzyDedupTransformer() *FuzzyDedupTransformer {
+	return &FuzzyDedupTransformer{
+		config:      make(map[string]interface{}),
+		threshold:   defaultFuzzyDedupThreshold,
+		shingleSize: defaultFuzzyDedupShingle,
+		numHashes:   defaultFuzzyDedupHashes,
+		keep:        fuzzyDedupKeepLongest,
+	}
+}
+
+func (t *FuzzyDedupTransformer) Name() string {
+	return transformerNameFuzzyDedup
+}
+
+// RunsAfter compares content once markup, quoted text and signatures are gone.
+func (t *FuzzyDedupTransformer) RunsAfter() []string {
+	return []string{transformerNameContentCleanup, transformerNameSignatureRemoval}
+}
+
+// Configure accepts:
+//   - threshold: Jaccard similarity (0-1] at which two items are duplicates (default 0.85)
+//   - shingle_size: words per shingle (default 3)
+//   - num_hashes: MinHash signature length (default 128)
+//   - keep: which item of a cluster survives, "longest" (default) or "earliest"
+func (t *FuzzyDedupTransformer) Configure(config map[string]interface{}) error {
+	t.config = config
+	t.threshold = defaultFuzzyDedupThreshold
+	t.shingleSize = defaultFuzzyDedupShingle
+	t.numHashes = defaultFuzzyDedupHashes
+	t.keep = fuzzyDedupKeepLongest
+
+	if raw, exists := config["threshold"]; exists {
+		switch v := raw.(type) {
+		case float64:
+			t.threshold = v
+		case int:
+			t.threshold = float64(v)
+		default:
+			return fmt.Errorf("fuzzy_dedup: threshold must be a number, got %T", raw)
+		}
+
+		if t.threshold <= 0 || t.threshold > 1 {
+			return fmt.Errorf("fuzzy_dedup: threshold must be in (0, 1], got %v", t.threshold)
+		}
+	}
+
+	for key, target := range map[string]*int{"shingle_size": &t.shingleSize, "num_hashes": &t.numHashes} {
+		raw, exists := config[key]
+		if !exists {
+			continue
+		}
+
+		switch v := raw.(type) {
+		case int:
+			*target = v
+		case float64:
+			*target = int(v)
+		default:
+			return fmt.Errorf("fuzzy_dedup: %s must be a number, got %T", key, raw)
+		}
+
+		if *target < 1 {
+			return fmt.Errorf("fuzzy_dedup: %s must be at least 1, got %d", key, *target)
+		}
+	}
+
+	if raw, exists := config["keep"]; exists {
+		keep, _ := raw.(string)
+		if keep != fuzzyDedupKeepLongest && keep != fuzzyDedupKeepEarliest {
+			return fmt.Errorf("fuzzy_dedup: keep must be %q or %q, got %v",
+				fuzzyDedupKeepLongest, fuzzyDedupKeepEarliest, raw)
+		}
+
+		t.keep = keep
+	}
+
+	return nil
+}
+
+func (t *FuzzyDedupTransformer) Transform(items []models.FullItem) ([]models.FullItem, error) {
+	if len(items) < 2 {
+		return items, nil
+	}
+
+	shingles := make([]map[uint64]struct{}, len(items))
+	for i, item := range items {
+		shingles[i] = contentShingles(item.GetContent(), t.shingleSize)
+	}
+
+	clusters := newUnionFind(len(items))
+
+	for _, pair := range t.candidatePairs(shingles) {
+		if clusters.find(pair[0]) == clusters.find(pair[1]) {
+			continue
+		}
+
+		if jaccard(shingles[pair[0]], shingles[pair[1]]) >= t.threshold {
+			clusters.union(pair[0], pair[1])
+		}
+	}
+
+	members := make(map[int][]int)
+	for i := range items {
+		root := clusters.find(i)
+		members[root] = append(members[root], i)
+	}
+
+	// Pairs link transitively (A~B, B~C), so A and C can share a cluster while
+	// being dissimilar. Only members similar to the survivor are merged into
+	// it; the rest are kept as they are.
+	merged := make(map[int][]int) // first merged member -> survivor first, then its duplicates
+	absorbed := make(map[int]bool)
+
+	for _, cluster := range members {
+		if len(cluster) == 1 {
+			continue
+		}
+
+		survivor := t.survivor(items, cluster)
+		group := []int{survivor}
+
+		for _, i := range cluster {
+			if i != survivor && jaccard(shingles[i], shingles[survivor]) >= t.threshold {
+				group = append(group, i)
+			}
+		}
+
+		if len(group) == 1 {
+			continue
+		}
+
+		first := slices.Min(group)
+		merged[first] = group
+
+		for _, i := range group {
+			absorbed[i] = i != first
+		}
+	}
+
+	result := make([]models.FullItem, 0, len(items))
+
+	for i, item := range items {
+		switch {
+		case absorbed[i]:
+			// Emitted with its group, where the group's first member was.
+		case merged[i] != nil:
+			result = append(result, t.mergeGroup(items, merged[i]))
+		default:
+			result = append(result, item)
+		}
+	}
+
+	return result, nil
+}
+
+// candidatePairs returns the index pairs that share at least one LSH band of
+// their MinHash signatures. Bands are sized so pairs well below the threshold
+// are still found; the exact similarity check rejects the false positives.
+func (t *FuzzyDedupTransformer) candidatePairs(shingles []map[uint64]struct{}) [][2]int {
+	seeds := minHashSeeds(t.numHashes)
+	rows := lshRows(t.numHashes, t.threshold)
+
+	buckets := make(map[string][]int)
+
+	for i, set := range shingles {
+		if len(set) == 0 {
+			continue
+		}
+
+		signature := minHashSignature(set, seeds)
+
+		for band := 0; band*rows < len(signature); band++ {
+			key := fmt.Sprint(band, signature[band*rows:(band+1)*rows])
+			buckets[key] = append(buckets[key], i)
+		}
+	}
+
+	seen := make(map[[2]int]bool)
+
+	var pairs [][2]int
+
+	for _, bucket := range buckets {
+		for a := 0; a < len(bucket); a++ {
+			for b := a + 1; b < len(bucket); b++ {
+				pair := [2]int{bucket[a], bucket[b]}
+				if !seen[pair] {
+					seen[pair] = true
+					pairs = append(pairs, pair)
+				}
+			}
+		}
+	}
+
+	return pairs
+}
+
+// survivor returns the member of cluster that the keep policy keeps.
+func (t *FuzzyDedupTransformer) survivor(items []models.FullItem, cluster []int) int {
+	survivor := cluster[0]
+	for _, i := range cluster[1:] {
+		if t.prefer(items[i], items[survivor]) {
+			survivor = i
+		}
+	}
+
+	return survivor
+}
+
+// mergeGroup returns group[0], the survivor, with the tags and
+// links of every member and the IDs of the members it replaces.
+func (t *FuzzyDedupTransformer) mergeGroup(items []models.FullItem, group []int) models.FullItem {
+	survivor := group[0]
+
+	var (
+		tags     []string
+		links    []models.Link
+		merged   []string
+		seenTag  = make(map[string]bool)
+		seenLink = make(map[string]bool)
+	)
+
+	// The survivor's own tags and links come first.
+	for _, i := range group {
+		item := items[i]
+
+		for _, tag := range item.GetTags() {
+			if !seenTag[tag] {
+				seenTag[tag] = true
+				tags = append(tags, tag)
+			}
+		}
+
+		for _, link := range item.GetLinks() {
+			if !seenLink[link.URL] {
+				seenLink[link.URL] = true
+				links = append(links, link)
+			}
+		}
+	}
+
+	for _, i := range group[1:] {
+		merged = append(merged, items[i].GetID())
+	}
+
+	newItem := withMetadata(items[survivor], map[string]interface{}{metadataKeyMergedDuplicates: merged})
+	newItem.SetTags(tags)
+	newItem.SetLinks(links)
+
+	return newItem
+}
+
+// prefer reports whether candidate should replace current as a cluster's
+// survivor under the keep policy; the other criterion breaks ties.
+func (t *FuzzyDedupTransformer) prefer(candidate, current models.FullItem) bool {
+	candidateLen, currentLen := len(candidate.GetContent()), len(current.GetContent())
+	candidateAt, currentAt := candidate.GetCreatedAt(), current.GetCreatedAt()
+
+	if t.keep == fuzzyDedupKeepEarliest {
+		if !candidateAt.Equal(currentAt) {
+			return candidateAt.Before(currentAt)
+		}
+
+		return candidateLen > currentLen
+	}
+
+	if candidateLen != currentLen {
+		return candidateLen > currentLen
+	}
+
+	return candidateAt.Before(currentAt)
+}
+
+// contentShingles hashes every run of size consecutive lower-cased words.
+// Content shorter than one shingle has no shingles, so it is never a
+// duplicate: two unrelated one-line replies ("Thanks!") would otherwise
+// match exactly.
+func contentShingles(content string, size int) map[uint64]struct{} {
+	words := strings.FieldsFunc(strings.ToLower(content), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	set := make(map[uint64]struct{})
+	if len(words) < size {
+		return set
+	}
+
+	for i := 0; i+size <= len(words); i++ {
+		h := fnv.New64a()
+		_, _ = h.Write([]byte(strings.Join(words[i:i+size], " ")))
+		set[h.Sum64()] = struct{}{}
+	}
+
+	return set
+}
+
+// jaccard returns |a ∩ b| / |a ∪ b|; two empty sets are not similar.
+func jaccard(a, b map[uint64]struct{}) float64 {
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+
+	if len(a) > len(b) {
+		a, b = b, a
+	}
+
+	shared := 0
+
+	for shingle := range a {
+		if _, ok := b[shingle]; ok {
+			shared++
+		}
+	}
+
+	return float64(shared) / float64(len(a)+len(b)-shared)
+}
+
+// minHashSeeds derives n deterministic (odd multiplier, offset) pairs for the
+// hash family h(x) = a*x + b (mod 2^64) with splitmix64.
+func minHashSeeds(n int) [][2]uint64 {
+	seeds := make([][2]uint64, n)
+	state := uint64(0x9e3779b97f4a7c15)
+
+	next := func() uint64 {
+		state += 0x9e3779b97f4a7c15
+		z := state
+		z = (z ^ (z >> 30)) * 0xbf58476d1ce4e5b9
+		z = (z ^ (z >> 27)) * 0x94d049bb133111eb
+
+		return z ^ (z >> 31)
+	}
+
+	for i := range seeds {
+		seeds[i] = [2]uint64{next() | 1, next()}
+	}
+
+	return seeds
+}
+
+func minHashSignature(set map[uint64]struct{}, seeds [][2]uint64) []uint64 {
+	signature := make([]uint64, len(seeds))
+	for i := range signature {
+		signature[i] = math.MaxUint64
+	}
+
+	for shingle := range set {
+		for i, seed := range seeds {
+			if h := seed[0]*shingle + seed[1]; h < signature[i] {
+				signature[i] = h
+			}
+		}
+	}
+
+	return signature
+}
+
+// lshRows picks the most rows per band (fewest spurious candidates) whose
+// detection point (1/bands)^(1/rows) stays 0.1 below threshold, so pairs at
+// the threshold are almost always bucketed together.
+func lshRows(numHashes int, threshold float64) int {
+	for rows := numHashes; rows > 1; rows-- {
+		if numHashes%rows != 0 {
+			continue
+		}
+
+		bands := float64(numHashes / rows)
+		if math.Pow(1/bands, 1/float64(rows)) <= threshold-0.1 {
+			return rows
+		}
+	}
+
+	return 1
+}
+
+// unionFind tracks which items have been merged into the same cluster.
+type unionFind struct {
+	parent []int
+}
+
+func newUnionFind(n int) *unionFind {
+	parent := make([]int, n)
+	for i := range parent {
+		parent[i] = i
+	}
+
+	return &unionFind{parent: parent}
+}
+
+func (u *unionFind) find(i int) int {
+	for u.parent[i] != i {
+		u.parent[i] = u.parent[u.parent[i]]
+		i = u.parent[i]
+	}
+
+	return i
+}
+
+// union joins two clusters under the lower index so a cluster's root is its
+// first member.
+func (u *unionFind) union(a, b int) {
+	ra, rb := u.find(a), u.find(b)
+	if ra > rb {
+		ra, rb = rb, ra
+	}
+
+	u.parent[rb] = ra
+}
+
+// Ensure interface compliance.
+var (
+	_ interfaces.Transformer        = (*FuzzyDedupTransformer)(nil)
+	_ interfaces.OrderedTransformer = (*FuzzyDedupTransformer)(nil)
+)
@@ -0,0 +1,203 @@
+package transform
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"pkm-sync/pkg/models"
+)
+
+// nearDuplicatePair returns two 200-word items where three spread-out words
+// differ, about 90% similar by 3-word shingles.
+func nearDuplicatePair() (models.FullItem, models.FullItem) {
+	words := make([]string, 200)
+	for i := range words {
+		words[i] = fmt.Sprintf("word%d", i)
+	}
+
+	original := strings.Join(words, " ")
+
+	words[50], words[100], words[150] = "changed", "edited", "replaced"
+	forwarded := "Forwarded: " + strings.Join(words, " ")
+
+	base := time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC)
+
+	a := models.NewBasicItem("orig", "Quarterly report")
+	a.SetContent(original)
+	a.SetCreatedAt(base)
+	a.SetTags([]string{"report"})
+	a.SetLinks([]models.Link{{URL: "https://example.com/a"}})
+
+	b := models.NewBasicItem("fwd", "Fwd: Quarterly report")
+	b.SetContent(forwarded)
+	b.SetCreatedAt(base.Add(time.Hour))
+	b.SetTags([]string{"report", "forwarded"})
+	b.SetLinks([]models.Link{{URL: "https://example.com/a"}, {URL: "https://example.com/b"}})
+
+	return a, b
+}
+
+func newFuzzyDedup(t *testing.T, config map[string]interface{}) *FuzzyDedupTransformer {
+	t.Helper()
+
+	transformer := NewFuzzyDedupTransformer()
+	if err := transformer.Configure(config); err != nil {
+		t.Fatalf("Configure failed: %v", err)
+	}
+
+	return transformer
+}
+
+func TestFuzzyDedup_Threshold(t *testing.T) {
+	a, b := nearDuplicatePair()
+
+	similarity := jaccard(contentShingles(a.GetContent(), 3), contentShingles(b.GetContent(), 3))
+	if similarity < 0.85 || similarity > 0.93 {
+		t.Fatalf("Expected test items to be about 90%% similar, got %.3f", similarity)
+	}
+
+	tests := []struct {
+		threshold float64
+		wantCount int
+	}{
+		{0.8, 1},
+		{0.95, 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprint(tt.threshold), func(t *testing.T) {
+			transformer := newFuzzyDedup(t, map[string]interface{}{"threshold": tt.threshold})
+
+			got, err := transformer.Transform([]models.FullItem{a, b})
+			if err != nil {
+				t.Fatalf("Transform failed: %v", err)
+			}
+
+			if len(got) != tt.wantCount {
+				t.Errorf("Expected %d items at threshold %v, got %d", tt.wantCount, tt.threshold, len(got))
+			}
+		})
+	}
+}
+
+func TestFuzzyDedup_MergesTagsAndLinks(t *testing.T) {
+	a, b := nearDuplicatePair()
+	other := models.NewBasicItem("other", "Lunch")
+	other.SetContent("Shall we get lunch on Friday at the usual place?")
+
+	transformer := newFuzzyDedup(t, map[string]interface{}{"threshold": 0.8})
+
+	got, err := transformer.Transform([]models.FullItem{other, a, b})
+	if err != nil {
+		t.Fatalf("Transform failed: %v", err)
+	}
+
+	if len(got) != 2 || got[0].GetID() != "other" {
+		t.Fatalf("Expected the unrelated item followed by the merged item, got %d items", len(got))
+	}
+
+	kept := got[1]
+	if kept.GetID() != "fwd" {
+		t.Errorf("Expected the longest item to be kept, got %s", kept.GetID())
+	}
+
+	if want := []string{"report", "forwarded"}; !reflect.DeepEqual(kept.GetTags(), want) {
+		t.Errorf("Expected tags %v, got %v", want, kept.GetTags())
+	}
+
+	if len(kept.GetLinks()) != 2 {
+		t.Errorf("Expected 2 merged links, got %v", kept.GetLinks())
+	}
+
+	if want := []string{"orig"}; !reflect.DeepEqual(kept.GetMetadata()[metadataKeyMergedDuplicates], want) {
+		t.Errorf("Expected merged_duplicates %v, got %v", want, kept.GetMetadata()[metadataKeyMergedDuplicates])
+	}
+}
+
+func TestFuzzyDedup_KeepEarliest(t *testing.T) {
+	a, b := nearDuplicatePair()
+
+	transformer := newFuzzyDedup(t, map[string]interface{}{"threshold": 0.8, "keep": "earliest"})
+
+	got, err := transformer.Transform([]models.FullItem{b, a})
+	if err != nil {
+		t.Fatalf("Transform failed: %v", err)
+	}
+
+	if len(got) != 1 || got[0].GetID() != "orig" {
+		t.Errorf("Expected only the earliest item to be kept, got %v", got)
+	}
+}
+
+func TestFuzzyDedup_ShortContentNotMerged(t *testing.T) {
+	a := models.NewBasicItem("a", "Re: Budget")
+	a.SetContent("Thanks!")
+
+	b := models.NewBasicItem("b", "Re: Offsite")
+	b.SetContent("Thanks!")
+
+	got, err := newFuzzyDedup(t, map[string]interface{}{}).Transform([]models.FullItem{a, b})
+	if err != nil {
+		t.Fatalf("Transform failed: %v", err)
+	}
+
+	if len(got) != 2 {
+		t.Errorf("Expected replies shorter than one shingle to be kept apart, got %d items", len(got))
+	}
+}
+
+func TestFuzzyDedup_ChainMergesOnlyMembersSimilarToSurvivor(t *testing.T) {
+	words := make([]string, 200)
+	for i := range words {
+		words[i] = fmt.Sprintf("word%d", i)
+	}
+
+	base := time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC)
+	item := func(id string, offset time.Duration) models.FullItem {
+		it := models.NewBasicItem(id, id)
+		it.SetContent(strings.Join(words, " "))
+		it.SetCreatedAt(base.Add(offset))
+
+		return it
+	}
+
+	// a~b and b~c are about 91% similar, a~c only about 83%.
+	a := item("a", 0)
+	words[50], words[100], words[150] = "changed", "edited", "replaced"
+	b := item("b", time.Hour)
+	words[25], words[75], words[125] = "moved", "renamed", "rewritten"
+	c := item("c", 2*time.Hour)
+
+	transformer := newFuzzyDedup(t, map[string]interface{}{"keep": "earliest"})
+
+	got, err := transformer.Transform([]models.FullItem{a, b, c})
+	if err != nil {
+		t.Fatalf("Transform failed: %v", err)
+	}
+
+	if len(got) != 2 || got[0].GetID() != "a" || got[1].GetID() != "c" {
+		t.Fatalf("Expected a (with b merged) and c, got %d items", len(got))
+	}
+
+	if want := []string{"b"}; !reflect.DeepEqual(got[0].GetMetadata()[metadataKeyMergedDuplicates], want) {
+		t.Errorf("Expected merged_duplicates %v, got %v", want, got[0].GetMetadata()[metadataKeyMergedDuplicates])
+	}
+}
+
+func TestFuzzyDedup_ConfigureErrors(t *testing.T) {
+	tests := []map[string]interface{}{
+		{"threshold": 1.5},
+		{"threshold": "high"},
+		{"shingle_size": 0},
+		{"keep": "newest"},
+	}
+
+	for _, config := range tests {
+		if err := NewFuzzyDedupTransformer().Configure(config); err == nil {
+			t.Errorf("Expected Configure(%v) to fail", config)
+		}
+	}
+}
@@ -2,11 +2,16 @@ package transform
 
 import (
 	"context"
+	"encoding/base64"
+	"fmt"
 	"net/http"
 	"strings"
 	"testing"
 	"time"
 
+	gmailapi "google.golang.org/api/gmail/v1"
+
+	"pkm-sync/internal/sources/google/gmail"
 	"pkm-sync/internal/sync"
 	"pkm-sync/pkg/interfaces"
 	"pkm-sync/pkg/models"
@@ -33,6 +38,10 @@ func (m *MockSource) SupportsRealtime() bool {
 	return false
 }
 
+func (m *MockSource) Capabilities() interfaces.SourceCapabilities {
+	return interfaces.SourceCapabilities{}
+}
+
 // MockSink captures written items for assertion.
 type MockSink struct {
 	writtenItems []models.FullItem
@@ -664,3 +673,112 @@ func TestTransformersWithDriveItems(t *testing.T) {
 		t.Errorf("Expected 2 drive items after processing, got %d", len(result))
 	}
 }
+
+// gmailThreadMessages builds count messages that share one Gmail thread ID.
+func gmailThreadMessages(threadID string, count int) []*gmailapi.Message {
+	base := time.Date(2025, 1, 10, 9, 0, 0, 0, time.UTC)
+	messages := make([]*gmailapi.Message, count)
+
+	for i := range messages {
+		subject := "Quarterly planning"
+		if i > 0 {
+			subject = "Re: " + subject
+		}
+
+		messages[i] = &gmailapi.Message{
+			Id:       fmt.Sprintf("%s-msg-%d", threadID, i),
+			ThreadId: threadID,
+			Payload: &gmailapi.MessagePart{
+				MimeType: "text/plain",
+				Headers: []*gmailapi.MessagePartHeader{
+					{Name: "Subject", Value: subject},
+					{Name: "From", Value: fmt.Sprintf("person%d@example.com", i)},
+					{Name: "Date", Value: base.Add(time.Duration(i) * time.Hour).Format(time.RFC1123Z)},
+				},
+				Body: &gmailapi.MessagePartBody{
+					Data: base64.URLEncoding.EncodeToString([]byte(fmt.Sprintf("Message body %d", i))),
+				},
+			},
+		}
+	}
+
+	return messages
+}
+
+// TestGmailThreadModeConsolidatesOnce verifies that a multi-message Gmail thread
+// is consolidated exactly once, whether the messages arrive individually with the
+// source's thread_mode or already aggregated by FromGmailThread.
+func TestGmailThreadModeConsolidatesOnce(t *testing.T) {
+	cfg := models.GmailSourceConfig{ThreadMode: "consolidated"}
+	messages := gmailThreadMessages("thread-abc", 3)
+
+	// The transformer is configured as individual; the source's ThreadMode must win.
+	transformer := NewThreadGroupingTransformer()
+	if err := transformer.Configure(map[string]interface{}{"mode": "individual"}); err != nil {
+		t.Fatalf("Configure failed: %v", err)
+	}
+
+	t.Run("per-message items", func(t *testing.T) {
+		items := make([]models.FullItem, 0, len(messages))
+
+		for _, msg := range messages {
+			item, err := gmail.FromGmailMessage(msg, cfg)
+			if err != nil {
+				t.Fatalf("FromGmailMessage failed: %v", err)
+			}
+
+			items = append(items, models.AsFullItem(item))
+		}
+
+		result, err := transformer.Transform(items)
+		if err != nil {
+			t.Fatalf("Transform failed: %v", err)
+		}
+
+		if len(result) != 1 {
+			t.Fatalf("Expected 1 consolidated item, got %d", len(result))
+		}
+
+		if result[0].GetID() != "thread_thread-abc" {
+			t.Errorf("Expected ID thread_thread-abc, got %s", result[0].GetID())
+		}
+
+		if count := result[0].GetMetadata()["item_count"]; count != 3 {
+			t.Errorf("Expected item_count 3, got %v", count)
+		}
+
+		// Running the output through again must not re-consolidate it.
+		again, err := transformer.Transform(result)
+		if err != nil {
+			t.Fatalf("second Transform failed: %v", err)
+		}
+
+		if len(again) != 1 || again[0].GetTitle() != result[0].GetTitle() {
+			t.Errorf("Expected consolidated item to pass through unchanged, got %d items", len(again))
+		}
+	})
+
+	t.Run("thread item from FromGmailThread", func(t *testing.T) {
+		threadItem, err := gmail.FromGmailThread(&gmailapi.Thread{Id: "thread-abc", Messages: messages}, cfg, nil)
+		if err != nil {
+			t.Fatalf("FromGmailThread failed: %v", err)
+		}
+
+		result, err := transformer.Transform([]models.FullItem{models.AsFullItem(threadItem)})
+		if err != nil {
+			t.Fatalf("Transform failed: %v", err)
+		}
+
+		if len(result) != 1 {
+			t.Fatalf("Expected 1 item, got %d", len(result))
+		}
+
+		if result[0].GetTitle() != threadItem.Title {
+			t.Errorf("Expected thread item to pass through untouched, got title %q", result[0].GetTitle())
+		}
+
+		if strings.Contains(result[0].GetContent(), "# Thread:") {
+			t.Error("Expected thread item not to be wrapped in a second consolidation")
+		}
+	})
+}
@@ -0,0 +1,232 @@
+package transform
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+
+	"pkm-sync/pkg/models"
+)
+
+// localePatterns are the language-specific markers used to cut quoted replies
+// and signatures out of email bodies.
+type localePatterns struct {
+	// replyHeaders match the attribution line above a quoted reply,
+	// e.g. "On Mon, Jan 5, 2026 at 9:00 AM Alice <a@x.com> wrote:".
+	replyHeaders []*regexp.Regexp
+	// forwardHeaders match the header block of forwarded/original messages.
+	forwardHeaders []*regexp.Regexp
+	// signoffs match closing lines that start a signature.
+	signoffs []*regexp.Regexp
+}
+
+// supportedLanguages lists the languages with localized patterns, in the
+// order they are tried when no language is known.
+var supportedLanguages = []string{"en", "de", "fr", "es"}
+
+var localizedPatterns = map[string]localePatterns{
+	"en": {
+		replyHeaders: compileAll(`^On\s.*\swrote:`),
+		forwardHeaders: compileAll(
+			`^From:\s.*@`,
+			`Original Message|original message`,
+			`^-{5,}\s*Forwarded message`,
+		),
+		signoffs: compileAll(
+			`(?i)^Best regards?,?`,
+			`(?i)^Sincerely,?`,
+			`(?i)^Thanks[,!]?\s*$`,
+			`(?i)^Cheers?,?`,
+			`(?i)^Sent from my`,
+			`(?i)^Get Outlook for`,
+		),
+	},
+	"de": {
+		// "Am 05.01.2026 um 09:00 schrieb Hans Müller <hans@example.de>:"
+		replyHeaders: compileAll(`(?i)^Am\s.*\sschrieb\s.*:\s*$`, `(?i)^.+\sschrieb:\s*$`),
+		forwardHeaders: compileAll(
+			`^Von:\s.*@`,
+			`(?i)ursprüngliche nachricht`,
+			`(?i)^-{5,}\s*weitergeleitete nachricht`,
+		),
+		signoffs: compileAll(
+			`(?i)^(mit\s+)?(freundlichen|besten|herzlichen|lieben)\s+grü(ß|ss)en`,
+			`(?i)^(viele|beste|liebe|schöne)\s+grü(ß|ss)e`,
+			`(?i)^gru(ß|ss),?\s*$`,
+			`(?i)^mfg,?\s*$`,
+			`(?i)^danke[,!]?\s*$`,
+			`(?i)^von meinem .* gesendet`,
+		),
+	},
+	"fr": {
+		// "Le lun. 5 janv. 2026 à 09:00, Marie Dupont <marie@example.fr> a écrit :"
+		replyHeaders: compileAll(`(?i)^Le\s.*\sa\s+écrit\s*:`),
+		forwardHeaders: compileAll(
+			`^De\s*:\s.*@`,
+			`(?i)message d'origine`,
+			`(?i)^-{5,}\s*message transféré`,
+		),
+		signoffs: compileAll(
+			`(?i)^cordialement,?`,
+			`(?i)^bien\s+(à vous|cordialement|amicalement)`,
+			`(?i)^(bonne|belle)\s+(journée|soirée)`,
+			`(?i)^merci[,!]?\s*$`,
+			`(?i)^envoyé de mon`,
+		),
+	},
+	"es": {
+		// "El lun, 5 ene 2026 a las 9:00, Juan Pérez (<juan@example.es>) escribió:"
+		replyHeaders: compileAll(`(?i)^El\s.*\sescribió:`),
+		forwardHeaders: compileAll(
+			`^De:\s.*@`,
+			`(?i)mensaje original`,
+			`(?i)^-{5,}\s*mensaje reenviado`,
+		),
+		signoffs: compileAll(
+			`(?i)^(un\s+)?(cordial\s+)?saludos?,?`,
+			`(?i)^atentamente,?`,
+			`(?i)^cordialmente,?`,
+			`(?i)^gracias[,!]?\s*$`,
+			`(?i)^enviado desde mi`,
+		),
+	},
+}
+
+// languageStopwords are frequent function words used to guess the language
+// of a message. Words shared between languages still count for both.
+var languageStopwords = map[string]map[string]bool{
+	"en": wordSet("the and you is to of for with have this that are be we not it"),
+	"de": wordSet("der die das und ist nicht ich sie mit für ein eine zu auf wir den dem es sind haben"),
+	"fr": wordSet("le la les et est vous pour une des avec pas que nous je du au ce sont dans"),
+	"es": wordSet("el los las y es para una con por que no del se lo su al muy estamos"),
+}
+
+// detectLanguage guesses the language of content from stopword counts and
+// returns "" when the text is too short or ambiguous to call.
+func detectLanguage(content string) string {
+	scores := make(map[string]int, len(languageStopwords))
+
+	for _, word := range strings.FieldsFunc(strings.ToLower(content), func(r rune) bool {
+		return !unicode.IsLetter(r)
+	}) {
+		for lang, words := range languageStopwords {
+			if words[word] {
+				scores[lang]++
+			}
+		}
+	}
+
+	best, bestScore, runnerUp := "", 0, 0
+
+	for _, lang := range supportedLanguages {
+		switch score := scores[lang]; {
+		case score > bestScore:
+			best, bestScore, runnerUp = lang, score, bestScore
+		case score > runnerUp:
+			runnerUp = score
+		}
+	}
+
+	// Require a few hits and a clear lead over the next language.
+	if bestScore < 3 || bestScore*2 < runnerUp*3 {
+		return ""
+	}
+
+	return best
+}
+
+// itemLanguage returns the language recorded in item metadata (by a source
+// or a language-detection transformer), if any.
+func itemLanguage(item models.FullItem) string {
+	if lang, ok := item.GetMetadata()["language"].(string); ok {
+		return strings.ToLower(lang)
+	}
+
+	return ""
+}
+
+// patternsFor returns the pattern sets to apply to content in lang. English
+// markers always apply, since mail clients often write English reply headers
+// whatever the body language. Beyond that, lang's own set is used when it is
+// among the configured languages; an unknown lang gets every configured set.
+func patternsFor(lang string, languages []string) []localePatterns {
+	sets := []localePatterns{localizedPatterns["en"]}
+
+	if lang == "en" {
+		return sets
+	}
+
+	for _, l := range languages {
+		if l == lang {
+			return append(sets, localizedPatterns[lang])
+		}
+	}
+
+	for _, l := range languages {
+		if l != "en" {
+			sets = append(sets, localizedPatterns[l])
+		}
+	}
+
+	return sets
+}
+
+// configuredLanguages reads the "languages" setting, keeping supported codes.
+// Unset or empty means every supported language.
+func configuredLanguages(config map[string]interface{}) []string {
+	var raw []string
+
+	switch v := config["languages"].(type) {
+	case []string:
+		raw = v
+	case []interface{}:
+		for _, item := range v {
+			if s, ok := item.(string); ok {
+				raw = append(raw, s)
+			}
+		}
+	}
+
+	languages := make([]string, 0, len(raw))
+
+	for _, lang := range raw {
+		lang = strings.ToLower(strings.TrimSpace(lang))
+		if _, ok := localizedPatterns[lang]; ok {
+			languages = append(languages, lang)
+		}
+	}
+
+	if len(languages) == 0 {
+		return supportedLanguages
+	}
+
+	return languages
+}
+
+func matchesAnyPattern(patterns []*regexp.Regexp, line string) bool {
+	for _, p := range patterns {
+		if p.MatchString(line) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func compileAll(patterns ...string) []*regexp.Regexp {
+	compiled := make([]*regexp.Regexp, 0, len(patterns))
+	for _, p := range patterns {
+		compiled = append(compiled, regexp.MustCompile(p))
+	}
+
+	return compiled
+}
+
+func wordSet(words string) map[string]bool {
+	set := make(map[string]bool)
+	for _, w := range strings.Fields(words) {
+		set[w] = true
+	}
+
+	return set
+}
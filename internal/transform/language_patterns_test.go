@@ -0,0 +1,176 @@
+package transform
+
+import (
+	"strings"
+	"testing"
+
+	"pkm-sync/pkg/models"
+)
+
+const germanReplyChain = `Hallo Anna,
+
+die Unterlagen sind fertig und ich schicke sie dir morgen mit der Post.
+Wir sehen uns dann auf der Sitzung.
+
+Viele Grüße
+Hans Müller
+
+Am Mo., 5. Jan. 2026 um 09:12 Uhr schrieb Anna Schmidt <anna@example.de>:
+> Hallo Hans, sind die Unterlagen schon fertig?`
+
+const frenchReplyChain = `Bonjour Marie,
+
+Merci pour le document, je vous envoie les corrections dans la journée.
+Nous pouvons en parler avec l'équipe jeudi.
+
+Cordialement,
+Pierre Martin
+
+Le lun. 5 janv. 2026 à 09:12, Marie Dupont <marie@example.fr> a écrit :
+> Bonjour Pierre, avez-vous reçu le document ?`
+
+func TestDetectLanguage(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{"german", germanReplyChain, "de"},
+		{"french", frenchReplyChain, "fr"},
+		{"spanish", "Hola Juan, gracias por el informe. Lo revisamos con el equipo y te escribo para la reunión.", "es"},
+		{"english", "Thanks for the notes. I have added them to the doc and we can review with the team.", "en"},
+		{"too short", "OK", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := detectLanguage(tt.content); got != tt.want {
+				t.Errorf("detectLanguage() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStripQuotedText_LocalizedReplyChains(t *testing.T) {
+	transformer := NewContentCleanupTransformer()
+
+	tests := []struct {
+		name     string
+		content  string
+		keep     string
+		stripped string
+	}{
+		{"german", germanReplyChain, "Viele Grüße", "schrieb Anna Schmidt"},
+		{"french", frenchReplyChain, "Cordialement", "a écrit"},
+		{
+			"german outlook header",
+			"Ich bin einverstanden und die Frist ist gut.\n\n" +
+				"Von: Anna Schmidt <anna@example.de>\nGesendet: Montag\nBetreff: Frist",
+			"einverstanden",
+			"Von: Anna",
+		},
+		{
+			"french original message",
+			"Je vous confirme que la date est bonne pour nous.\n\n-------- Message d'origine --------\nDe : Marie",
+			"date est bonne",
+			"Message d'origine",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := transformer.StripQuotedText(tt.content)
+
+			if !strings.Contains(result, tt.keep) {
+				t.Errorf("expected %q to be kept, got:\n%s", tt.keep, result)
+			}
+
+			if strings.Contains(result, tt.stripped) {
+				t.Errorf("expected %q to be stripped, got:\n%s", tt.stripped, result)
+			}
+		})
+	}
+}
+
+func TestStripQuotedText_LanguagesConfig(t *testing.T) {
+	transformer := NewContentCleanupTransformer()
+	if err := transformer.Configure(map[string]interface{}{"languages": []interface{}{"fr"}}); err != nil {
+		t.Fatalf("Configure: %v", err)
+	}
+
+	// German is not configured, so its reply header is left in place; English
+	// headers are always recognized.
+	if result := transformer.StripQuotedText(germanReplyChain); !strings.Contains(result, "schrieb Anna Schmidt") {
+		t.Errorf("German header stripped although only fr is configured:\n%s", result)
+	}
+
+	english := "Sounds good.\n\nOn Mon, Jan 5, 2026 at 9:12 AM Anna <anna@example.com> wrote:\n> Hi"
+	if result := transformer.StripQuotedText(english); result != "Sounds good." {
+		t.Errorf("English header not stripped: %q", result)
+	}
+}
+
+func TestSignatureRemoval_LocalizedSignoffs(t *testing.T) {
+	// Keep the greeting ("Hallo Anna") out of the signature window.
+	transformer := NewSignatureRemovalTransformer()
+	if err := transformer.Configure(map[string]interface{}{"max_signature_lines": 3}); err != nil {
+		t.Fatalf("Configure: %v", err)
+	}
+
+	german := "Hallo Anna,\n\ndie Unterlagen sind fertig und ich schicke sie dir morgen.\n\n" +
+		"Mit freundlichen Grüßen\nHans"
+
+	result := transformer.ExtractSignatures(german)
+	if strings.Contains(result, "Grüßen") || !strings.Contains(result, "morgen") {
+		t.Errorf("German signature not removed cleanly:\n%s", result)
+	}
+
+	french := "Bonjour Marie,\n\nje vous envoie les corrections dans la journée pour le rapport.\n\nBien à vous,\nPierre"
+	if result := transformer.ExtractSignatures(french); strings.Contains(result, "Bien à vous") {
+		t.Errorf("French signature not removed:\n%s", result)
+	}
+}
+
+func TestSignatureRemoval_LanguageFromMetadata(t *testing.T) {
+	transformer := NewSignatureRemovalTransformer()
+
+	// Too short to detect, so every language's sign-offs apply by default.
+	item := models.NewBasicItem("1", "Kurz")
+	item.SetContent("Passt, 10 Uhr.\n\nDanke\nHans")
+
+	result, err := transformer.Transform([]models.FullItem{item})
+	if err != nil {
+		t.Fatalf("Transform: %v", err)
+	}
+
+	if got := result[0].GetContent(); got != "Passt, 10 Uhr." {
+		t.Errorf("undetected language: content = %q, want %q", got, "Passt, 10 Uhr.")
+	}
+
+	// A language recorded in metadata narrows the patterns to that language.
+	item.SetMetadata(map[string]interface{}{"language": "fr"})
+
+	result, err = transformer.Transform([]models.FullItem{item})
+	if err != nil {
+		t.Fatalf("Transform: %v", err)
+	}
+
+	if got := result[0].GetContent(); got != item.GetContent() {
+		t.Errorf("French item should keep the German sign-off, got %q", got)
+	}
+}
+
+func TestSignatureRemoval_CustomPatternsDisableLocalized(t *testing.T) {
+	transformer := NewSignatureRemovalTransformer()
+	if err := transformer.Configure(map[string]interface{}{
+		"merge_with_defaults": false,
+		"patterns":            []interface{}{`^ACME Corp`},
+	}); err != nil {
+		t.Fatalf("Configure: %v", err)
+	}
+
+	content := "Bonjour,\n\nle rapport est prêt pour la réunion avec vous et nous.\n\nCordialement"
+	if result := transformer.ExtractSignatures(content); !strings.Contains(result, "Cordialement") {
+		t.Errorf("custom-only patterns should not apply localized sign-offs:\n%s", result)
+	}
+}
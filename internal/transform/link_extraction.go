@@ -40,6 +40,11 @@ func (t *LinkExtractionTransformer) Name() string {
 	return transformerNameLinkExtraction
 }
 
+// RunsAfter keeps links found only in signatures or markup out of the results.
+func (t *LinkExtractionTransformer) RunsAfter() []string {
+	return []string{transformerNameContentCleanup, transformerNameSignatureRemoval}
+}
+
 func (t *LinkExtractionTransformer) Configure(config map[string]interface{}) error {
 	t.config = config
 
@@ -383,4 +388,7 @@ func (t *LinkExtractionTransformer) shouldAlwaysProcessLinks() bool {
 }
 
 // Ensure interface compliance.
-var _ interfaces.Transformer = (*LinkExtractionTransformer)(nil)
+var (
+	_ interfaces.Transformer        = (*LinkExtractionTransformer)(nil)
+	_ interfaces.OrderedTransformer = (*LinkExtractionTransformer)(nil)
+)
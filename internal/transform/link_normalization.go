@@ -0,0 +1,238 @@
+package transform
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"pkm-sync/pkg/interfaces"
+	"pkm-sync/pkg/models"
+)
+
+const transformerNameLinkNormalization = "link_normalization"
+
+// defaultTrackingParams are the query parameters stripped from links unless
+// strip_query_params is configured. A trailing * matches a prefix.
+var defaultTrackingParams = []string{"utm_*", "fbclid", "gclid", "mc_cid", "mc_eid"}
+
+// LinkNormalizationTransformer is a final pass over every item's links, from
+// the source (a calendar meeting_url, a Drive webViewLink) and from
+// link_extraction alike. It canonicalizes each URL and merges links that are
+// then the same, keeping the position of the first, the most descriptive
+// title and a specific type (e.g. meeting_url) over a generic one.
+type LinkNormalizationTransformer struct {
+	config             map[string]interface{}
+	stripFragments     bool
+	stripTrailingSlash bool
+	stripQueryParams   []string
+}
+
+func NewLinkNormalizationTransformer() *LinkNormalizationTransformer {
+	return &LinkNormalizationTransformer{
+		config:             make(map[string]interface{}),
+		stripFragments:     true,
+		stripTrailingSlash: true,
+		stripQueryParams:   defaultTrackingParams,
+	}
+}
+
+func (t *LinkNormalizationTransformer) Name() string {
+	return transformerNameLinkNormalization
+}
+
+// RunsAfter sees the links of every transformer that adds or merges them, and
+// leaves attachment URLs as downloaded so attachment_link_rewrite matches them.
+func (t *LinkNormalizationTransformer) RunsAfter() []string {
+	return []string{
+		transformerNameLinkExtraction,
+		transformerNameDriveAttachmentLinks,
+		transformerNameThreadGrouping,
+		transformerNameFuzzyDedup,
+		transformerNameAttachmentLinkRewrite,
+	}
+}
+
+// Configure accepts:
+//   - strip_fragments: drop #fragments (default true)
+//   - strip_trailing_slash: drop a trailing / from the path (default true)
+//   - strip_query_params: query parameters to drop, * suffix for a prefix
+//     (default utm_*, fbclid, gclid, mc_cid, mc_eid; [] keeps all)
+func (t *LinkNormalizationTransformer) Configure(config map[string]interface{}) error {
+	t.config = config
+	t.stripFragments = true
+	t.stripTrailingSlash = true
+	t.stripQueryParams = defaultTrackingParams
+
+	for key, target := range map[string]*bool{
+		"strip_fragments":      &t.stripFragments,
+		"strip_trailing_slash": &t.stripTrailingSlash,
+	} {
+		raw, exists := config[key]
+		if !exists {
+			continue
+		}
+
+		v, ok := raw.(bool)
+		if !ok {
+			return fmt.Errorf("link_normalization: %s must be a boolean, got %T", key, raw)
+		}
+
+		*target = v
+	}
+
+	if raw, exists := config["strip_query_params"]; exists {
+		list, ok := raw.([]interface{})
+		if !ok {
+			return fmt.Errorf("link_normalization: strip_query_params must be a list, got %T", raw)
+		}
+
+		t.stripQueryParams = make([]string, 0, len(list))
+
+		for _, elem := range list {
+			param, ok := elem.(string)
+			if !ok {
+				return fmt.Errorf("link_normalization: strip_query_params entries must be strings, got %T", elem)
+			}
+
+			t.stripQueryParams = append(t.stripQueryParams, param)
+		}
+	}
+
+	return nil
+}
+
+func (t *LinkNormalizationTransformer) Transform(items []models.FullItem) ([]models.FullItem, error) {
+	transformedItems := make([]models.FullItem, len(items))
+
+	for i, item := range items {
+		links := item.GetLinks()
+		if len(links) == 0 {
+			transformedItems[i] = item
+
+			continue
+		}
+
+		newItem := cloneFullItem(item)
+		newItem.SetLinks(t.normalizeLinks(links))
+		transformedItems[i] = newItem
+	}
+
+	return transformedItems, nil
+}
+
+// normalizeLinks canonicalizes links and merges those with the same URL.
+func (t *LinkNormalizationTransformer) normalizeLinks(links []models.Link) []models.Link {
+	normalized := make([]models.Link, 0, len(links))
+	index := make(map[string]int)
+
+	for _, link := range links {
+		link.URL = t.canonicalURL(link.URL)
+
+		i, seen := index[link.URL]
+		if !seen {
+			index[link.URL] = len(normalized)
+			normalized = append(normalized, link)
+
+			continue
+		}
+
+		kept := &normalized[i]
+		if linkTitleScore(link) > linkTitleScore(*kept) {
+			kept.Title = link.Title
+		}
+
+		if isGenericLinkType(kept.Type) && !isGenericLinkType(link.Type) {
+			kept.Type = link.Type
+		}
+	}
+
+	return normalized
+}
+
+// canonicalURL lower-cases the scheme and host of an http(s) URL and strips
+// the configured fragment, trailing slash and query parameters. Other URLs
+// are only trimmed.
+func (t *LinkNormalizationTransformer) canonicalURL(raw string) string {
+	raw = strings.TrimSpace(raw)
+
+	u, err := url.Parse(raw)
+	if err != nil || u.Host == "" {
+		return raw
+	}
+
+	u.Scheme = strings.ToLower(u.Scheme)
+	if u.Scheme != schemeHTTP && u.Scheme != schemeHTTPS {
+		return raw
+	}
+
+	u.Host = strings.ToLower(u.Host)
+
+	if t.stripFragments {
+		u.Fragment, u.RawFragment = "", ""
+	}
+
+	if t.stripTrailingSlash && strings.HasSuffix(u.Path, "/") {
+		u.Path = strings.TrimRight(u.Path, "/")
+		u.RawPath = ""
+	}
+
+	if u.RawQuery != "" && len(t.stripQueryParams) > 0 {
+		u.RawQuery = t.stripParams(u.RawQuery)
+	}
+
+	return u.String()
+}
+
+// stripParams removes the configured parameters from a raw query, keeping the
+// order and encoding of the rest.
+func (t *LinkNormalizationTransformer) stripParams(rawQuery string) string {
+	var kept []string
+
+	for _, pair := range strings.Split(rawQuery, "&") {
+		name, _, _ := strings.Cut(pair, "=")
+		if name, err := url.QueryUnescape(name); err == nil && t.isStrippedParam(name) {
+			continue
+		}
+
+		kept = append(kept, pair)
+	}
+
+	return strings.Join(kept, "&")
+}
+
+func (t *LinkNormalizationTransformer) isStrippedParam(name string) bool {
+	name = strings.ToLower(name)
+
+	for _, param := range t.stripQueryParams {
+		param = strings.ToLower(param)
+
+		if prefix, ok := strings.CutSuffix(param, "*"); ok && strings.HasPrefix(name, prefix) || name == param {
+			return true
+		}
+	}
+
+	return false
+}
+
+// linkTitleScore ranks how descriptive a link's title is: none, or the URL
+// itself, scores 0; otherwise longer titles win.
+func linkTitleScore(link models.Link) int {
+	title := strings.TrimSpace(link.Title)
+	if title == "" || strings.Contains(title, "://") {
+		return 0
+	}
+
+	return len([]rune(title))
+}
+
+// isGenericLinkType reports whether a link type says no more than that the
+// link was found, so a merged duplicate's specific type replaces it.
+func isGenericLinkType(linkType string) bool {
+	return linkType == "" || linkType == linkTypeExternal
+}
+
+// Ensure interface compliance.
+var (
+	_ interfaces.Transformer        = (*LinkNormalizationTransformer)(nil)
+	_ interfaces.OrderedTransformer = (*LinkNormalizationTransformer)(nil)
+)
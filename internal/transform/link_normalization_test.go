@@ -0,0 +1,110 @@
+package transform
+
+import (
+	"reflect"
+	"testing"
+
+	"pkm-sync/pkg/models"
+)
+
+func newLinkNormalization(t *testing.T, config map[string]interface{}) *LinkNormalizationTransformer {
+	t.Helper()
+
+	transformer := NewLinkNormalizationTransformer()
+	if err := transformer.Configure(config); err != nil {
+		t.Fatalf("Configure failed: %v", err)
+	}
+
+	return transformer
+}
+
+func TestLinkNormalization_MergesMeetingURLWithExtractedURL(t *testing.T) {
+	event := models.NewBasicItem("evt-1", "Standup")
+	event.SetContent("Join at https://meet.google.com/abc-defg-hij/ today")
+	event.SetLinks([]models.Link{{URL: "https://meet.google.com/abc-defg-hij", Title: "Meeting Link", Type: "meeting_url"}})
+
+	extractor := NewLinkExtractionTransformer()
+	if err := extractor.Configure(map[string]interface{}{}); err != nil {
+		t.Fatalf("Configure failed: %v", err)
+	}
+
+	extracted, err := extractor.Transform([]models.FullItem{event})
+	if err != nil {
+		t.Fatalf("Transform failed: %v", err)
+	}
+
+	if n := len(extracted[0].GetLinks()); n != 2 {
+		t.Fatalf("Expected link_extraction to keep both spellings, got %d links", n)
+	}
+
+	got, err := newLinkNormalization(t, nil).Transform(extracted)
+	if err != nil {
+		t.Fatalf("Transform failed: %v", err)
+	}
+
+	want := []models.Link{{URL: "https://meet.google.com/abc-defg-hij", Title: "Meeting Link", Type: "meeting_url"}}
+	if !reflect.DeepEqual(got[0].GetLinks(), want) {
+		t.Errorf("Expected %v, got %v", want, got[0].GetLinks())
+	}
+
+	if len(event.GetLinks()) != 1 || len(extracted[0].GetLinks()) != 2 {
+		t.Error("Expected the input items to be left unchanged")
+	}
+}
+
+func TestLinkNormalization_PrefersDescriptiveTitleAndSpecificType(t *testing.T) {
+	item := models.NewBasicItem("1", "Doc")
+	item.SetLinks([]models.Link{
+		{URL: "https://Docs.Google.com/document/d/xyz/edit#heading=h.1", Title: "https://docs.google.com/...", Type: "external"},
+		{URL: "https://docs.google.com/document/d/xyz/edit", Title: "Q3 planning doc", Type: "document"},
+		{URL: "https://example.com/page?utm_source=mail&id=7&fbclid=abc", Title: "Page"},
+		{URL: "https://example.com/page?id=7"},
+	})
+
+	got, err := newLinkNormalization(t, nil).Transform([]models.FullItem{item})
+	if err != nil {
+		t.Fatalf("Transform failed: %v", err)
+	}
+
+	want := []models.Link{
+		{URL: "https://docs.google.com/document/d/xyz/edit", Title: "Q3 planning doc", Type: "document"},
+		{URL: "https://example.com/page?id=7", Title: "Page"},
+	}
+	if !reflect.DeepEqual(got[0].GetLinks(), want) {
+		t.Errorf("Expected %v, got %v", want, got[0].GetLinks())
+	}
+}
+
+func TestLinkNormalization_Config(t *testing.T) {
+	transformer := newLinkNormalization(t, map[string]interface{}{
+		"strip_fragments":      false,
+		"strip_trailing_slash": false,
+		"strip_query_params":   []interface{}{"ref"},
+	})
+
+	tests := map[string]string{
+		"https://example.com/a/#top":          "https://example.com/a/#top",
+		"https://example.com/a?ref=x&utm_x=1": "https://example.com/a?utm_x=1",
+		"mailto:someone@example.com":          "mailto:someone@example.com",
+	}
+
+	for in, want := range tests {
+		if got := transformer.canonicalURL(in); got != want {
+			t.Errorf("canonicalURL(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestLinkNormalization_ConfigureErrors(t *testing.T) {
+	tests := []map[string]interface{}{
+		{"strip_fragments": "yes"},
+		{"strip_query_params": "utm_*"},
+		{"strip_query_params": []interface{}{1}},
+	}
+
+	for _, config := range tests {
+		if err := NewLinkNormalizationTransformer().Configure(config); err == nil {
+			t.Errorf("Expected Configure(%v) to fail", config)
+		}
+	}
+}
@@ -0,0 +1,253 @@
+package transform
+
+import (
+	"fmt"
+	"strings"
+
+	"pkm-sync/pkg/interfaces"
+	"pkm-sync/pkg/models"
+)
+
+const transformerNameMetadataRules = "metadata_rules"
+
+// MetadataRule adds static metadata to items matching all of its conditions.
+// Conditions use the Gmail tagging_rules syntax, extended to every source:
+//
+//	from:<text>    sender address, Slack author or Jira reporter contains text
+//	subject:<text> title contains text
+//	tag:<tag>      item has the tag
+//	source:<type>  source type equals type
+//	type:<type>    item type equals type
+//
+// Matching is case-insensitive.
+type MetadataRule struct {
+	Conditions []string
+	Metadata   map[string]interface{}
+	// Overwrite replaces keys the item already has; by default they are kept.
+	Overwrite bool
+}
+
+// MetadataRulesTransformer stamps metadata from config rules, e.g. items from
+// a client's domain get project: alpha. The metadata reaches note frontmatter
+// (Obsidian) and page properties (Logseq) like any source metadata. Rules are
+// applied in order, so with overwrite a later rule wins.
+type MetadataRulesTransformer struct {
+	config map[string]interface{}
+	rules  []MetadataRule
+}
+
+func NewMetadataRulesTransformer() *MetadataRulesTransformer {
+	return &MetadataRulesTransformer{
+		config: make(map[string]interface{}),
+	}
+}
+
+func (t *MetadataRulesTransformer) Name() string {
+	return transformerNameMetadataRules
+}
+
+// RunsAfter matches on the final tags and thread titles.
+func (t *MetadataRulesTransformer) RunsAfter() []string {
+	return []string{transformerNameThreadGrouping, transformerNameAutoTagging}
+}
+
+// Configure accepts a rules list; each rule has:
+//   - condition: one condition or a list that must all match
+//   - metadata: map of keys and values to add
+//   - overwrite: replace keys the item already has (default false)
+func (t *MetadataRulesTransformer) Configure(config map[string]interface{}) error {
+	t.config = config
+	t.rules = nil
+
+	raw, exists := config["rules"]
+	if !exists {
+		return nil
+	}
+
+	list, ok := raw.([]interface{})
+	if !ok {
+		return fmt.Errorf("metadata_rules: 'rules' must be a list, got %T", raw)
+	}
+
+	for i, entry := range list {
+		m, ok := entry.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("metadata_rules: rules[%d] must be a map, got %T", i, entry)
+		}
+
+		rule, err := parseMetadataRule(m, i)
+		if err != nil {
+			return err
+		}
+
+		t.rules = append(t.rules, rule)
+	}
+
+	return nil
+}
+
+func parseMetadataRule(m map[string]interface{}, index int) (MetadataRule, error) {
+	var rule MetadataRule
+
+	switch v := m["condition"].(type) {
+	case string:
+		rule.Conditions = []string{v}
+	case []interface{}:
+		for _, elem := range v {
+			s, ok := elem.(string)
+			if !ok {
+				return rule, fmt.Errorf("metadata_rules: rules[%d].condition entries must be strings, got %T", index, elem)
+			}
+
+			rule.Conditions = append(rule.Conditions, s)
+		}
+	default:
+		return rule, fmt.Errorf("metadata_rules: rules[%d].condition must be a string or list, got %T",
+			index, m["condition"])
+	}
+
+	for _, condition := range rule.Conditions {
+		kind, _, found := strings.Cut(condition, ":")
+		if !found || !isMetadataConditionKind(strings.ToLower(strings.TrimSpace(kind))) {
+			return rule, fmt.Errorf("metadata_rules: rules[%d]: unknown condition %q "+
+				"(use from:, subject:, tag:, source: or type:)", index, condition)
+		}
+	}
+
+	metadata, ok := m["metadata"].(map[string]interface{})
+	if !ok || len(metadata) == 0 {
+		return rule, fmt.Errorf("metadata_rules: rules[%d].metadata must be a non-empty map", index)
+	}
+
+	rule.Metadata = metadata
+
+	if v, exists := m["overwrite"]; exists {
+		overwrite, ok := v.(bool)
+		if !ok {
+			return rule, fmt.Errorf("metadata_rules: rules[%d].overwrite must be a boolean, got %T", index, v)
+		}
+
+		rule.Overwrite = overwrite
+	}
+
+	return rule, nil
+}
+
+func isMetadataConditionKind(kind string) bool {
+	switch kind {
+	case "from", "subject", "tag", "source", "type":
+		return true
+	default:
+		return false
+	}
+}
+
+func (t *MetadataRulesTransformer) Transform(items []models.FullItem) ([]models.FullItem, error) {
+	if len(t.rules) == 0 {
+		return items, nil
+	}
+
+	transformedItems := make([]models.FullItem, len(items))
+
+	for i, item := range items {
+		extra := make(map[string]interface{})
+		existing := item.GetMetadata()
+
+		for _, rule := range t.rules {
+			if !rule.matches(item) {
+				continue
+			}
+
+			for key, value := range rule.Metadata {
+				if _, has := existing[key]; has && !rule.Overwrite {
+					continue
+				}
+
+				if _, set := extra[key]; set && !rule.Overwrite {
+					continue
+				}
+
+				extra[key] = value
+			}
+		}
+
+		if len(extra) == 0 {
+			transformedItems[i] = item
+
+			continue
+		}
+
+		transformedItems[i] = withMetadata(item, extra)
+	}
+
+	return transformedItems, nil
+}
+
+// matches reports whether item satisfies every condition of the rule.
+func (r MetadataRule) matches(item models.FullItem) bool {
+	for _, condition := range r.Conditions {
+		kind, value, _ := strings.Cut(condition, ":")
+		value = strings.ToLower(strings.TrimSpace(value))
+
+		var ok bool
+
+		switch strings.ToLower(strings.TrimSpace(kind)) {
+		case "from":
+			ok = senderContains(item, value)
+		case "subject":
+			ok = strings.Contains(strings.ToLower(item.GetTitle()), value)
+		case "tag":
+			for _, tag := range item.GetTags() {
+				if strings.ToLower(tag) == value {
+					ok = true
+
+					break
+				}
+			}
+		case "source":
+			ok = strings.ToLower(item.GetSourceType()) == value
+		case "type":
+			ok = strings.ToLower(item.GetItemType()) == value
+		}
+
+		if !ok {
+			return false
+		}
+	}
+
+	return true
+}
+
+// senderContains reports whether the sender (Gmail "from", Slack "author",
+// Jira "reporter") of the item or of any message in its thread contains text.
+func senderContains(item models.FullItem, text string) bool {
+	messages := []models.FullItem{item}
+	if thread, ok := models.AsThread(item); ok {
+		messages = append(messages, thread.GetMessages()...)
+	}
+
+	for _, msg := range messages {
+		metadata := msg.GetMetadata()
+
+		if email := recipientEmail(metadata["from"]); email != "" && strings.Contains(email, text) {
+			return true
+		}
+
+		if from, ok := metadata["from"].(string); ok && strings.Contains(strings.ToLower(from), text) {
+			return true
+		}
+
+		for _, key := range []string{"author", "reporter"} {
+			if name, ok := metadata[key].(string); ok && strings.Contains(strings.ToLower(name), text) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+var (
+	_ interfaces.Transformer        = (*MetadataRulesTransformer)(nil)
+	_ interfaces.OrderedTransformer = (*MetadataRulesTransformer)(nil)
+)
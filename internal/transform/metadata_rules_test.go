@@ -0,0 +1,129 @@
+package transform
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"pkm-sync/internal/sinks"
+	"pkm-sync/pkg/models"
+)
+
+func newMetadataRules(t *testing.T, rules ...interface{}) *MetadataRulesTransformer {
+	t.Helper()
+
+	transformer := NewMetadataRulesTransformer()
+	if err := transformer.Configure(map[string]interface{}{"rules": rules}); err != nil {
+		t.Fatalf("Configure failed: %v", err)
+	}
+
+	return transformer
+}
+
+func TestMetadataRules_SenderRuleReachesFrontmatter(t *testing.T) {
+	transformer := newMetadataRules(t, map[string]interface{}{
+		"condition": "from:@alpha-client.com",
+		"metadata":  map[string]interface{}{"project": "alpha"},
+	})
+
+	matched := newEmail("1", "pm@alpha-client.com", nil, nil, "Kickoff notes")
+	other := newEmail("2", "friend@example.com", nil, nil, "Lunch?")
+
+	got, err := transformer.Transform([]models.FullItem{matched, other})
+	if err != nil {
+		t.Fatalf("Transform failed: %v", err)
+	}
+
+	if project := got[0].GetMetadata()["project"]; project != "alpha" {
+		t.Errorf("Expected project alpha on the matched email, got %v", project)
+	}
+
+	if _, ok := got[1].GetMetadata()["project"]; ok {
+		t.Error("Expected no project on an email from another sender")
+	}
+
+	if _, ok := matched.GetMetadata()["project"]; ok {
+		t.Error("Expected the input item to be left unchanged")
+	}
+
+	dir := t.TempDir()
+
+	sink, err := sinks.NewFileSink("obsidian", dir, nil)
+	if err != nil {
+		t.Fatalf("NewFileSink failed: %v", err)
+	}
+
+	if err := sink.Write(context.Background(), got[:1]); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+
+	var note []byte
+
+	err = filepath.WalkDir(dir, func(path string, d os.DirEntry, err error) error {
+		if err == nil && !d.IsDir() && strings.HasSuffix(path, ".md") {
+			note, err = os.ReadFile(path)
+		}
+
+		return err
+	})
+	if err != nil {
+		t.Fatalf("reading note: %v", err)
+	}
+
+	frontmatter, _, _ := strings.Cut(strings.TrimPrefix(string(note), "---\n"), "\n---")
+	if !strings.Contains(frontmatter, "project: alpha") {
+		t.Errorf("Expected project: alpha in frontmatter, got:\n%s", note)
+	}
+}
+
+func TestMetadataRules_AllConditionsAndOverwrite(t *testing.T) {
+	transformer := newMetadataRules(t,
+		map[string]interface{}{
+			"condition": []interface{}{"source:gmail", "subject:invoice"},
+			"metadata":  map[string]interface{}{"category": "finance", "status": "new"},
+		},
+		map[string]interface{}{
+			"condition": "tag:paid",
+			"metadata":  map[string]interface{}{"status": "paid"},
+			"overwrite": true,
+		},
+	)
+
+	invoice := newEmail("1", "billing@vendor.com", nil, nil, "Attached")
+	invoice.SetTitle("Invoice #42")
+	invoice.SetTags([]string{"paid"})
+
+	notInvoice := newEmail("2", "billing@vendor.com", nil, nil, "Hello")
+	notInvoice.SetMetadata(map[string]interface{}{"category": "keep"})
+	notInvoice.SetTitle("Invoice question")
+
+	got, err := transformer.Transform([]models.FullItem{invoice, notInvoice})
+	if err != nil {
+		t.Fatalf("Transform failed: %v", err)
+	}
+
+	if metadata := got[0].GetMetadata(); metadata["category"] != "finance" || metadata["status"] != "paid" {
+		t.Errorf("Expected category finance and status paid, got %v", metadata)
+	}
+
+	if category := got[1].GetMetadata()["category"]; category != "keep" {
+		t.Errorf("Expected existing category to be kept without overwrite, got %v", category)
+	}
+}
+
+func TestMetadataRules_ConfigureErrors(t *testing.T) {
+	tests := []map[string]interface{}{
+		{"rules": "not a list"},
+		{"rules": []interface{}{map[string]interface{}{"condition": "label:x", "metadata": map[string]interface{}{"a": 1}}}},
+		{"rules": []interface{}{map[string]interface{}{"condition": "from:a"}}},
+		{"rules": []interface{}{map[string]interface{}{"metadata": map[string]interface{}{"a": 1}}}},
+	}
+
+	for _, config := range tests {
+		if err := NewMetadataRulesTransformer().Configure(config); err == nil {
+			t.Errorf("Expected Configure(%v) to fail", config)
+		}
+	}
+}
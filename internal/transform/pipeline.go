@@ -1,8 +1,12 @@
 package transform
 
 import (
+	"errors"
 	"fmt"
 	"log"
+	"slices"
+	"sort"
+	"strings"
 
 	"pkm-sync/pkg/interfaces"
 	"pkm-sync/pkg/models"
@@ -20,6 +24,9 @@ type DefaultTransformPipeline struct {
 	transformers        []interfaces.Transformer
 	config              models.TransformConfig
 	transformerRegistry map[string]interfaces.Transformer
+	registrationOrder   []string
+	// appliesTo holds each scoped transformer's applies_to list.
+	appliesTo map[string][]string
 }
 
 // NewPipeline creates a new transform pipeline using FullItem.
@@ -40,6 +47,7 @@ func (p *DefaultTransformPipeline) Configure(config models.TransformConfig) erro
 
 	// Clear existing transformers
 	p.transformers = make([]interfaces.Transformer, 0)
+	p.appliesTo = make(map[string][]string)
 
 	// Validate no duplicate transformers in pipeline order
 	seenTransformers := make(map[string]bool)
@@ -51,8 +59,18 @@ func (p *DefaultTransformPipeline) Configure(config models.TransformConfig) erro
 		seenTransformers[name] = true
 	}
 
-	// Add transformers in the specified order
-	for _, name := range config.PipelineOrder {
+	order := config.PipelineOrder
+	if len(order) == 0 {
+		resolved, err := p.ResolveOrder(SelectedTransformers(config))
+		if err != nil {
+			return err
+		}
+
+		order = resolved
+	}
+
+	// Add transformers in the resolved order
+	for _, name := range order {
 		transformer, exists := p.transformerRegistry[name]
 		if !exists {
 			return fmt.Errorf("transformer '%s' not found in registry", name)
@@ -63,6 +81,15 @@ func (p *DefaultTransformPipeline) Configure(config models.TransformConfig) erro
 			if err := transformer.Configure(transformerConfig); err != nil {
 				return fmt.Errorf("failed to configure transformer '%s': %w", name, err)
 			}
+
+			scope, err := parseAppliesTo(name, transformerConfig)
+			if err != nil {
+				return err
+			}
+
+			if len(scope) > 0 {
+				p.appliesTo[name] = scope
+			}
 		}
 
 		p.transformers = append(p.transformers, transformer)
@@ -71,6 +98,146 @@ func (p *DefaultTransformPipeline) Configure(config models.TransformConfig) erro
 	return nil
 }
 
+// ValidateConfig checks a transformer config without building a pipeline:
+// every name in pipeline_order and under transformers must be a registered
+// transformer (GetAllContentProcessingTransformers), and error_strategy, when
+// set, must be fail_fast, log_and_continue or skip_item. All problems are
+// returned together.
+func ValidateConfig(config models.TransformConfig) error {
+	registered := make(map[string]bool)
+	for _, t := range GetAllContentProcessingTransformers() {
+		registered[t.Name()] = true
+	}
+
+	var errs []error
+
+	for _, name := range config.PipelineOrder {
+		if !registered[name] {
+			errs = append(errs, fmt.Errorf("pipeline_order: unknown transformer %q", name))
+		}
+	}
+
+	names := make([]string, 0, len(config.Transformers))
+	for name := range config.Transformers {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	for _, name := range names {
+		if !registered[name] {
+			errs = append(errs, fmt.Errorf("transformers: unknown transformer %q", name))
+		}
+	}
+
+	switch config.ErrorStrategy {
+	case "", errorStrategyFailFast, errorStrategyLogAndContinue, errorStrategySkipItem:
+	default:
+		errs = append(errs, fmt.Errorf("error_strategy must be %q, %q or %q, got %q",
+			errorStrategyFailFast, errorStrategyLogAndContinue, errorStrategySkipItem, config.ErrorStrategy))
+	}
+
+	return errors.Join(errs...)
+}
+
+// SelectedTransformers returns the names of the transformers a config runs: the
+// explicit pipeline_order when set, otherwise every transformer with an entry
+// under transformers (sorted by name; Configure orders them by dependency).
+func SelectedTransformers(config models.TransformConfig) []string {
+	if len(config.PipelineOrder) > 0 {
+		return config.PipelineOrder
+	}
+
+	names := make([]string, 0, len(config.Transformers))
+	for name := range config.Transformers {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return names
+}
+
+// ResolveOrder sorts names so every transformer runs after the ones it declares
+// through interfaces.OrderedTransformer. Dependencies outside names are ignored.
+// Ties keep registration order, so the result is deterministic. A dependency
+// cycle is an error naming the transformers involved.
+func (p *DefaultTransformPipeline) ResolveOrder(names []string) ([]string, error) {
+	selected := make(map[string]bool, len(names))
+
+	for _, name := range names {
+		if _, exists := p.transformerRegistry[name]; !exists {
+			return nil, fmt.Errorf("transformer '%s' not found in registry", name)
+		}
+
+		selected[name] = true
+	}
+
+	// Candidates in registration order drive the tie-break.
+	candidates := make([]string, 0, len(selected))
+
+	for _, name := range p.registrationOrder {
+		if selected[name] {
+			candidates = append(candidates, name)
+		}
+	}
+
+	pending := make(map[string]int, len(candidates))
+	dependents := make(map[string][]string)
+
+	for _, name := range candidates {
+		ordered, ok := p.transformerRegistry[name].(interfaces.OrderedTransformer)
+		if !ok {
+			continue
+		}
+
+		for _, dep := range ordered.RunsAfter() {
+			if !selected[dep] || dep == name || slices.Contains(dependents[dep], name) {
+				continue
+			}
+
+			pending[name]++
+			dependents[dep] = append(dependents[dep], name)
+		}
+	}
+
+	resolved := make([]string, 0, len(candidates))
+	done := make(map[string]bool, len(candidates))
+
+	for len(resolved) < len(candidates) {
+		next := ""
+
+		for _, name := range candidates {
+			if !done[name] && pending[name] == 0 {
+				next = name
+
+				break
+			}
+		}
+
+		if next == "" {
+			var cycle []string
+
+			for _, name := range candidates {
+				if !done[name] {
+					cycle = append(cycle, name)
+				}
+			}
+
+			return nil, fmt.Errorf("transformer dependency cycle among: %s", strings.Join(cycle, ", "))
+		}
+
+		done[next] = true
+		resolved = append(resolved, next)
+
+		for _, dependent := range dependents[next] {
+			pending[dependent]--
+		}
+	}
+
+	return resolved, nil
+}
+
 // AddTransformer adds a transformer to the registry.
 func (p *DefaultTransformPipeline) AddTransformer(transformer interfaces.Transformer) error {
 	if transformer == nil {
@@ -82,12 +249,18 @@ func (p *DefaultTransformPipeline) AddTransformer(transformer interfaces.Transfo
 		return fmt.Errorf("transformer name cannot be empty")
 	}
 
+	if _, exists := p.transformerRegistry[name]; !exists {
+		p.registrationOrder = append(p.registrationOrder, name)
+	}
+
 	p.transformerRegistry[name] = transformer
 
 	return nil
 }
 
-// Transform processes items through the configured pipeline.
+// Transform processes items through the configured pipeline. A transformer
+// with an applies_to list only sees the items whose source type or item type
+// is listed; the others pass it unchanged.
 func (p *DefaultTransformPipeline) Transform(items []models.FullItem) ([]models.FullItem, error) {
 	if !p.config.Enabled || len(p.transformers) == 0 {
 		return items, nil
@@ -96,18 +269,33 @@ func (p *DefaultTransformPipeline) Transform(items []models.FullItem) ([]models.
 	currentItems := items
 
 	for _, transformer := range p.transformers {
-		transformedItems, err := p.processWithErrorHandling(transformer, currentItems)
+		batch, positions := currentItems, []int(nil)
+
+		scope, scoped := p.appliesTo[transformer.Name()]
+		if scoped {
+			batch, positions = scopeItems(currentItems, scope)
+			if len(batch) == 0 {
+				continue
+			}
+		}
+
+		transformedItems, err := p.processWithErrorHandling(transformer, batch)
 		if err != nil {
-			if err := p.handleTransformerError(transformer, currentItems, err); err != nil {
+			if err := p.handleTransformerError(transformer, batch, err); err != nil {
 				return nil, err
 			}
-			// currentItems remains unchanged for log_and_continue, or becomes empty for skip_item
+			// The batch remains unchanged for log_and_continue, or is dropped for skip_item
+			transformedItems = batch
 			if p.config.ErrorStrategy == errorStrategySkipItem {
-				currentItems = []models.FullItem{}
+				transformedItems = []models.FullItem{}
 			}
-		} else {
-			currentItems = transformedItems
 		}
+
+		if scoped {
+			transformedItems = mergeScoped(currentItems, positions, transformedItems)
+		}
+
+		currentItems = transformedItems
 	}
 
 	return currentItems, nil
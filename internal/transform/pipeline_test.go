@@ -2,6 +2,7 @@ package transform
 
 import (
 	"fmt"
+	"reflect"
 	"strings"
 	"testing"
 
@@ -490,3 +491,315 @@ func TestGetRegisteredTransformers(t *testing.T) {
 		t.Error("Missing expected transformer names")
 	}
 }
+
+// orderedMockTransformer declares dependencies through interfaces.OrderedTransformer.
+type orderedMockTransformer struct {
+	MockTransformer
+	after []string
+}
+
+func (m *orderedMockTransformer) RunsAfter() []string {
+	return m.after
+}
+
+func newOrderedMock(name string, after ...string) *orderedMockTransformer {
+	return &orderedMockTransformer{MockTransformer: MockTransformer{name: name}, after: after}
+}
+
+func configuredNames(pipeline *DefaultTransformPipeline) []string {
+	names := make([]string, 0, len(pipeline.transformers))
+	for _, transformer := range pipeline.transformers {
+		names = append(names, transformer.Name())
+	}
+
+	return names
+}
+
+func TestConfigureAutoOrdersByDependencies(t *testing.T) {
+	pipeline := NewPipeline()
+
+	pipeline.AddTransformer(newOrderedMock("tagging", "cleanup"))
+	pipeline.AddTransformer(newOrderedMock("filter", "tagging"))
+	pipeline.AddTransformer(&MockTransformer{name: "cleanup"})
+	pipeline.AddTransformer(&MockTransformer{name: "unused"})
+
+	config := models.TransformConfig{
+		Enabled: true,
+		Transformers: map[string]map[string]interface{}{
+			"filter":  {},
+			"tagging": {},
+			"cleanup": {},
+		},
+	}
+
+	if err := pipeline.Configure(config); err != nil {
+		t.Fatalf("Configure() failed: %v", err)
+	}
+
+	got := configuredNames(pipeline)
+	if want := []string{"cleanup", "tagging", "filter"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected auto order %v, got %v", want, got)
+	}
+}
+
+func TestConfigureExplicitOrderOverridesDependencies(t *testing.T) {
+	pipeline := NewPipeline()
+
+	pipeline.AddTransformer(&MockTransformer{name: "cleanup"})
+	pipeline.AddTransformer(newOrderedMock("tagging", "cleanup"))
+
+	config := models.TransformConfig{
+		Enabled:       true,
+		PipelineOrder: []string{"tagging", "cleanup"},
+	}
+
+	if err := pipeline.Configure(config); err != nil {
+		t.Fatalf("Configure() failed: %v", err)
+	}
+
+	got := configuredNames(pipeline)
+	if want := []string{"tagging", "cleanup"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected explicit order %v, got %v", want, got)
+	}
+}
+
+func TestResolveOrderIgnoresUnselectedDependencies(t *testing.T) {
+	pipeline := NewPipeline()
+
+	pipeline.AddTransformer(&MockTransformer{name: "cleanup"})
+	pipeline.AddTransformer(newOrderedMock("tagging", "cleanup"))
+	pipeline.AddTransformer(&MockTransformer{name: "filter"})
+
+	got, err := pipeline.ResolveOrder([]string{"filter", "tagging"})
+	if err != nil {
+		t.Fatalf("ResolveOrder() failed: %v", err)
+	}
+
+	if want := []string{"tagging", "filter"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected registration order for independent transformers %v, got %v", want, got)
+	}
+}
+
+func TestResolveOrderDetectsCycle(t *testing.T) {
+	pipeline := NewPipeline()
+
+	pipeline.AddTransformer(newOrderedMock("a", "c"))
+	pipeline.AddTransformer(newOrderedMock("b", "a"))
+	pipeline.AddTransformer(newOrderedMock("c", "b"))
+	pipeline.AddTransformer(&MockTransformer{name: "d"})
+
+	_, err := pipeline.ResolveOrder([]string{"a", "b", "c", "d"})
+	if err == nil {
+		t.Fatal("Expected error for dependency cycle")
+	}
+
+	if !strings.Contains(err.Error(), "cycle among: a, b, c") {
+		t.Errorf("Expected cycle error naming a, b, c, got: %v", err)
+	}
+}
+
+func TestResolveOrderBuiltinTransformers(t *testing.T) {
+	pipeline := NewPipeline()
+	for _, transformer := range GetAllContentProcessingTransformers() {
+		pipeline.AddTransformer(transformer)
+	}
+
+	got, err := pipeline.ResolveOrder(pipeline.GetRegisteredTransformers())
+	if err != nil {
+		t.Fatalf("ResolveOrder() failed for built-in transformers: %v", err)
+	}
+
+	position := make(map[string]int, len(got))
+	for i, name := range got {
+		position[name] = i
+	}
+
+	for _, name := range got {
+		ordered, ok := pipeline.transformerRegistry[name].(interfaces.OrderedTransformer)
+		if !ok {
+			continue
+		}
+
+		for _, dep := range ordered.RunsAfter() {
+			if position[dep] > position[name] {
+				t.Errorf("Expected %s to run after %s, got order %v", name, dep, got)
+			}
+		}
+	}
+}
+
+func TestTransformAppliesToScopesTransformer(t *testing.T) {
+	pipeline := NewPipeline()
+	pipeline.AddTransformer(&MockTransformer{name: "email_only"})
+	pipeline.AddTransformer(&MockTransformer{name: "everything"})
+
+	config := models.TransformConfig{
+		Enabled:       true,
+		PipelineOrder: []string{"email_only", "everything"},
+		ErrorStrategy: "fail_fast",
+		Transformers: map[string]map[string]interface{}{
+			"email_only": {"applies_to": []interface{}{"gmail", "chat"}},
+		},
+	}
+	if err := pipeline.Configure(config); err != nil {
+		t.Fatalf("Configure() failed: %v", err)
+	}
+
+	items := []models.FullItem{
+		models.AsFullItem(&models.Item{ID: "mail", SourceType: "gmail", ItemType: "email", Tags: []string{}}),
+		models.AsFullItem(&models.Item{ID: "doc", SourceType: "google_drive", ItemType: "document", Tags: []string{}}),
+		models.AsFullItem(&models.Item{ID: "chat", SourceType: "slack", ItemType: "chat", Tags: []string{}}),
+	}
+
+	result, err := pipeline.Transform(items)
+	if err != nil {
+		t.Fatalf("Transform() failed: %v", err)
+	}
+
+	want := map[string][]string{
+		"mail": {"transformed_by_email_only", "transformed_by_everything"},
+		"doc":  {"transformed_by_everything"},
+		"chat": {"transformed_by_email_only", "transformed_by_everything"},
+	}
+
+	if len(result) != len(items) {
+		t.Fatalf("Expected %d items, got %d", len(items), len(result))
+	}
+
+	for i, item := range result {
+		if item.GetID() != items[i].GetID() {
+			t.Errorf("Expected item %d to be %s, got %s", i, items[i].GetID(), item.GetID())
+		}
+
+		if !reflect.DeepEqual(item.GetTags(), want[item.GetID()]) {
+			t.Errorf("Item %s: expected tags %v, got %v", item.GetID(), want[item.GetID()], item.GetTags())
+		}
+	}
+}
+
+func TestTransformAppliesToKeepsOutOfScopeItemsWhenFiltering(t *testing.T) {
+	pipeline := NewPipeline()
+	dropAll := &MockTransformer{
+		name: "drop_all",
+		TransformFunc: func(_ []models.FullItem) ([]models.FullItem, error) {
+			return nil, nil
+		},
+	}
+	pipeline.AddTransformer(dropAll)
+
+	config := models.TransformConfig{
+		Enabled:       true,
+		PipelineOrder: []string{"drop_all"},
+		ErrorStrategy: "fail_fast",
+		Transformers: map[string]map[string]interface{}{
+			"drop_all": {"applies_to": "gmail"},
+		},
+	}
+	if err := pipeline.Configure(config); err != nil {
+		t.Fatalf("Configure() failed: %v", err)
+	}
+
+	items := []models.FullItem{
+		models.AsFullItem(&models.Item{ID: "mail", SourceType: "gmail"}),
+		models.AsFullItem(&models.Item{ID: "event", SourceType: "google_calendar"}),
+	}
+
+	result, err := pipeline.Transform(items)
+	if err != nil {
+		t.Fatalf("Transform() failed: %v", err)
+	}
+
+	if len(result) != 1 || result[0].GetID() != "event" {
+		t.Errorf("Expected only the out-of-scope event to remain, got %v", result)
+	}
+}
+
+func TestTransformAppliesToSkipsTransformerWithoutMatches(t *testing.T) {
+	pipeline := NewPipeline()
+	called := false
+	pipeline.AddTransformer(&MockTransformer{
+		name: "drive_only",
+		TransformFunc: func(items []models.FullItem) ([]models.FullItem, error) {
+			called = true
+
+			return items, nil
+		},
+	})
+
+	config := models.TransformConfig{
+		Enabled:       true,
+		PipelineOrder: []string{"drive_only"},
+		ErrorStrategy: "fail_fast",
+		Transformers: map[string]map[string]interface{}{
+			"drive_only": {"applies_to": []string{"google_drive"}},
+		},
+	}
+	if err := pipeline.Configure(config); err != nil {
+		t.Fatalf("Configure() failed: %v", err)
+	}
+
+	items := []models.FullItem{models.AsFullItem(&models.Item{ID: "mail", SourceType: "gmail"})}
+
+	if _, err := pipeline.Transform(items); err != nil {
+		t.Fatalf("Transform() failed: %v", err)
+	}
+
+	if called {
+		t.Error("Expected transformer scoped to google_drive not to run on gmail items")
+	}
+}
+
+func TestConfigureRejectsInvalidAppliesTo(t *testing.T) {
+	pipeline := NewPipeline()
+	pipeline.AddTransformer(&MockTransformer{name: "transformer1"})
+
+	config := models.TransformConfig{
+		Enabled:       true,
+		PipelineOrder: []string{"transformer1"},
+		Transformers: map[string]map[string]interface{}{
+			"transformer1": {"applies_to": []interface{}{"gmail", 42}},
+		},
+	}
+
+	err := pipeline.Configure(config)
+	if err == nil || !strings.Contains(err.Error(), "applies_to") {
+		t.Errorf("Expected applies_to error, got %v", err)
+	}
+}
+
+func TestValidateConfig(t *testing.T) {
+	valid := models.TransformConfig{
+		Enabled:       true,
+		PipelineOrder: []string{"content_cleanup", "auto_tagging"},
+		ErrorStrategy: "log_and_continue",
+		Transformers:  map[string]map[string]interface{}{"auto_tagging": {}},
+	}
+	if err := ValidateConfig(valid); err != nil {
+		t.Errorf("expected a valid config, got %v", err)
+	}
+
+	if err := ValidateConfig(models.TransformConfig{}); err != nil {
+		t.Errorf("expected the empty config to be valid, got %v", err)
+	}
+
+	err := ValidateConfig(models.TransformConfig{
+		Enabled:       true,
+		PipelineOrder: []string{"content_cleanup", "auto_taging", "dedupe"},
+		ErrorStrategy: "retry",
+		Transformers:  map[string]map[string]interface{}{"signature_remover": {}},
+	})
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+
+	for _, want := range []string{
+		`pipeline_order: unknown transformer "auto_taging"`,
+		`pipeline_order: unknown transformer "dedupe"`,
+		`transformers: unknown transformer "signature_remover"`,
+		`got "retry"`,
+	} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("expected %s in the aggregated error, got:\n%v", want, err)
+		}
+	}
+}
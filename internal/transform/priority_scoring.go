@@ -0,0 +1,489 @@
+package transform
+
+import (
+	"fmt"
+	"net/mail"
+	"reflect"
+	"regexp"
+	"strings"
+
+	"pkm-sync/pkg/interfaces"
+	"pkm-sync/pkg/models"
+)
+
+const (
+	transformerNamePriorityScoring = "priority_scoring"
+
+	// metadataKeyPriorityScore holds the item's 0-100 importance score as an int.
+	metadataKeyPriorityScore = "priority_score"
+	// metadataKeyPriorityReasons lists the signals that contributed to the score.
+	metadataKeyPriorityReasons = "priority_reasons"
+
+	defaultHighPriorityThreshold = 50
+	defaultHighPriorityTag       = "priority:high"
+	maxPriorityScore             = 100
+)
+
+// defaultPriorityWeights are the points each signal adds. thread_message is
+// added per message beyond the first, up to thread_max.
+var defaultPriorityWeights = map[string]int{
+	"vip_sender":       40,
+	"vip_domain":       25,
+	"direct_recipient": 20,
+	"cc_recipient":     5,
+	"thread_message":   3,
+	"thread_max":       15,
+	"question":         10,
+	"action_items":     15,
+}
+
+// questionRegex matches a question mark ending a sentence, not one inside a URL query.
+var questionRegex = regexp.MustCompile(`\?(\s|$)`)
+
+// PriorityScoringTransformer assigns each email an importance score from its
+// sender, whether the user was addressed directly or only copied, how long the
+// thread is, and whether it asks questions or contains action items. The score
+// and the reasons behind it are stored in metadata; items at or above the
+// threshold can be tagged for triage.
+type PriorityScoringTransformer struct {
+	config      map[string]interface{}
+	vipSenders  map[string]bool
+	vipDomains  []string
+	myAddresses map[string]bool
+	weights     map[string]int
+	actionItems *ActionItemsTransformer
+}
+
+func NewPriorityScoringTransformer() *PriorityScoringTransformer {
+	return &PriorityScoringTransformer{
+		config:      make(map[string]interface{}),
+		vipSenders:  make(map[string]bool),
+		myAddresses: make(map[string]bool),
+		weights:     copyWeights(defaultPriorityWeights),
+		actionItems: NewActionItemsTransformer(),
+	}
+}
+
+func (t *PriorityScoringTransformer) Name() string {
+	return transformerNamePriorityScoring
+}
+
+// RunsAfter scores threads once grouped, and reuses action items already extracted.
+func (t *PriorityScoringTransformer) RunsAfter() []string {
+	return []string{
+		transformerNameContentCleanup, transformerNameSignatureRemoval,
+		transformerNameThreadGrouping, transformerNameActionItems,
+	}
+}
+
+// Configure accepts:
+//   - vip_senders: sender addresses that always score high
+//   - vip_domains: sender domains (subdomains included) that score high
+//   - my_addresses: the user's own addresses, to tell direct recipients from CCs
+//   - weights: overrides for vip_sender, vip_domain, direct_recipient, cc_recipient,
+//     thread_message, thread_max, question, action_items
+//   - high_priority_threshold: score at which an item is high priority (default 50)
+//   - high_priority_tag: tag added to high-priority items (default "priority:high", "" disables)
+//   - source_types: source types to score (default ["gmail"])
+func (t *PriorityScoringTransformer) Configure(config map[string]interface{}) error {
+	t.config = config
+	t.vipSenders = make(map[string]bool)
+	t.myAddresses = make(map[string]bool)
+	t.weights = copyWeights(defaultPriorityWeights)
+
+	senders, err := priorityStringList(config, "vip_senders")
+	if err != nil {
+		return err
+	}
+
+	for _, s := range senders {
+		t.vipSenders[strings.ToLower(strings.TrimSpace(s))] = true
+	}
+
+	domains, err := priorityStringList(config, "vip_domains")
+	if err != nil {
+		return err
+	}
+
+	t.vipDomains = nil
+	for _, d := range domains {
+		t.vipDomains = append(t.vipDomains, strings.ToLower(strings.TrimPrefix(strings.TrimSpace(d), "@")))
+	}
+
+	addresses, err := priorityStringList(config, "my_addresses")
+	if err != nil {
+		return err
+	}
+
+	for _, a := range addresses {
+		t.myAddresses[strings.ToLower(strings.TrimSpace(a))] = true
+	}
+
+	if raw, exists := config["weights"]; exists {
+		weights, ok := raw.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("priority_scoring: weights must be a map, got %T", raw)
+		}
+
+		for name, value := range weights {
+			if _, known := defaultPriorityWeights[name]; !known {
+				return fmt.Errorf("priority_scoring: unknown weight %q", name)
+			}
+
+			switch v := value.(type) {
+			case int:
+				t.weights[name] = v
+			case float64:
+				t.weights[name] = int(v)
+			default:
+				return fmt.Errorf("priority_scoring: weight %q must be a number, got %T", name, value)
+			}
+		}
+	}
+
+	return nil
+}
+
+func (t *PriorityScoringTransformer) Transform(items []models.FullItem) ([]models.FullItem, error) {
+	transformedItems := make([]models.FullItem, len(items))
+	sourceTypes := t.sourceTypes()
+
+	for i, item := range items {
+		if !sourceTypes[item.GetSourceType()] {
+			transformedItems[i] = item
+
+			continue
+		}
+
+		score, reasons := t.Score(item)
+
+		newItem := withMetadata(item, map[string]interface{}{
+			metadataKeyPriorityScore:   score,
+			metadataKeyPriorityReasons: reasons,
+		})
+
+		if tag := t.highPriorityTag(); tag != "" && score >= t.highPriorityThreshold() && !hasTag(newItem, tag) {
+			newItem.SetTags(append(append([]string{}, newItem.GetTags()...), tag))
+		}
+
+		transformedItems[i] = newItem
+	}
+
+	return transformedItems, nil
+}
+
+// Score returns the item's priority score, capped at 100, and the signals
+// that contributed to it in a stable order.
+func (t *PriorityScoringTransformer) Score(item models.FullItem) (int, []string) {
+	messages := []models.FullItem{item}
+	if thread, ok := models.AsThread(item); ok && len(thread.GetMessages()) > 0 {
+		messages = thread.GetMessages()
+	}
+
+	var (
+		score   int
+		reasons []string
+	)
+
+	add := func(weight string, reason string) {
+		if points := t.weights[weight]; points != 0 {
+			score += points
+			reasons = append(reasons, reason)
+		}
+	}
+
+	switch sender := t.vipSender(messages); {
+	case sender.vip:
+		add("vip_sender", "vip_sender:"+sender.email)
+	case sender.domain != "":
+		add("vip_domain", "vip_domain:"+sender.domain)
+	}
+
+	switch t.recipientRole(messages) {
+	case "to":
+		add("direct_recipient", "direct_recipient")
+	case "cc":
+		add("cc_recipient", "cc_recipient")
+	}
+
+	if count := threadLength(item); count > 1 {
+		points := min((count-1)*t.weights["thread_message"], t.weights["thread_max"])
+		if points > 0 {
+			score += points
+			reasons = append(reasons, fmt.Sprintf("thread_length:%d", count))
+		}
+	}
+
+	if hasQuestion(item.GetContent()) {
+		add("question", "question")
+	}
+
+	if t.hasActionItems(item) {
+		add("action_items", "action_items")
+	}
+
+	return min(score, maxPriorityScore), reasons
+}
+
+type senderMatch struct {
+	vip    bool
+	email  string
+	domain string
+}
+
+// vipSender reports the strongest VIP match among the senders of messages.
+func (t *PriorityScoringTransformer) vipSender(messages []models.FullItem) senderMatch {
+	var best senderMatch
+
+	for _, msg := range messages {
+		email := recipientEmail(msg.GetMetadata()["from"])
+		if email == "" {
+			continue
+		}
+
+		if t.vipSenders[email] {
+			return senderMatch{vip: true, email: email}
+		}
+
+		if best.domain == "" {
+			best.domain = t.vipDomainOf(email)
+		}
+	}
+
+	return best
+}
+
+func (t *PriorityScoringTransformer) vipDomainOf(email string) string {
+	_, domain, found := strings.Cut(email, "@")
+	if !found {
+		return ""
+	}
+
+	for _, vip := range t.vipDomains {
+		if domain == vip || strings.HasSuffix(domain, "."+vip) {
+			return vip
+		}
+	}
+
+	return ""
+}
+
+// recipientRole returns "to" when the user is a direct recipient of any
+// message, "cc" when only copied, and "" when unknown or not addressed.
+func (t *PriorityScoringTransformer) recipientRole(messages []models.FullItem) string {
+	if len(t.myAddresses) == 0 {
+		return ""
+	}
+
+	role := ""
+
+	for _, msg := range messages {
+		metadata := msg.GetMetadata()
+
+		for _, email := range recipientEmails(metadata["to"]) {
+			if t.myAddresses[email] {
+				return "to"
+			}
+		}
+
+		for _, email := range recipientEmails(metadata["cc"]) {
+			if t.myAddresses[email] {
+				role = "cc"
+			}
+		}
+	}
+
+	return role
+}
+
+func (t *PriorityScoringTransformer) hasActionItems(item models.FullItem) bool {
+	if existing, ok := item.GetMetadata()[metadataKeyActionItems]; ok {
+		switch v := existing.(type) {
+		case []string:
+			return len(v) > 0
+		case []interface{}:
+			return len(v) > 0
+		}
+	}
+
+	return len(t.actionItems.ExtractActionItems(item.GetContent())) > 0
+}
+
+// threadLength prefers the thread's own messages and falls back to the
+// message_count the Gmail source records on consolidated threads.
+func threadLength(item models.FullItem) int {
+	if thread, ok := models.AsThread(item); ok && len(thread.GetMessages()) > 0 {
+		return len(thread.GetMessages())
+	}
+
+	switch v := item.GetMetadata()["message_count"].(type) {
+	case int:
+		return v
+	case float64:
+		return int(v)
+	}
+
+	return 1
+}
+
+// hasQuestion reports a question in the message itself, ignoring quoted replies.
+func hasQuestion(content string) bool {
+	for _, line := range strings.Split(content, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, ">") {
+			continue
+		}
+
+		if questionRegex.MatchString(trimmed) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func hasTag(item models.FullItem, tag string) bool {
+	for _, existing := range item.GetTags() {
+		if existing == tag {
+			return true
+		}
+	}
+
+	return false
+}
+
+// emailAddresser is satisfied by the Gmail converter's EmailRecipient.
+type emailAddresser interface {
+	Address() string
+}
+
+// recipientEmail extracts a lower-cased address from the converter's typed
+// recipient or from the string and map forms left by a JSON round-trip.
+func recipientEmail(value interface{}) string {
+	var email string
+
+	switch v := value.(type) {
+	case emailAddresser:
+		email = v.Address()
+	case map[string]interface{}:
+		email, _ = v["email"].(string)
+	case string:
+		if addr, err := mail.ParseAddress(v); err == nil {
+			email = addr.Address
+		} else {
+			email = v
+		}
+	}
+
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+// recipientEmails accepts any slice of recipients (typed, maps or strings) or
+// a comma-separated address list.
+func recipientEmails(value interface{}) []string {
+	if s, ok := value.(string); ok {
+		list, err := mail.ParseAddressList(s)
+		if err != nil {
+			return nil
+		}
+
+		emails := make([]string, 0, len(list))
+		for _, addr := range list {
+			emails = append(emails, strings.ToLower(addr.Address))
+		}
+
+		return emails
+	}
+
+	rv := reflect.ValueOf(value)
+	if rv.Kind() != reflect.Slice {
+		return nil
+	}
+
+	emails := make([]string, 0, rv.Len())
+	for i := range rv.Len() {
+		if email := recipientEmail(rv.Index(i).Interface()); email != "" {
+			emails = append(emails, email)
+		}
+	}
+
+	return emails
+}
+
+func copyWeights(weights map[string]int) map[string]int {
+	copied := make(map[string]int, len(weights))
+	for k, v := range weights {
+		copied[k] = v
+	}
+
+	return copied
+}
+
+func priorityStringList(config map[string]interface{}, key string) ([]string, error) {
+	raw, exists := config[key]
+	if !exists {
+		return nil, nil
+	}
+
+	list, ok := raw.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("priority_scoring: %s must be a list of strings, got %T", key, raw)
+	}
+
+	result := make([]string, 0, len(list))
+
+	for _, elem := range list {
+		s, ok := elem.(string)
+		if !ok {
+			return nil, fmt.Errorf("priority_scoring: %s entries must be strings, got %T", key, elem)
+		}
+
+		result = append(result, s)
+	}
+
+	return result, nil
+}
+
+// Configuration helper methods
+
+func (t *PriorityScoringTransformer) highPriorityThreshold() int {
+	if val, exists := t.config["high_priority_threshold"]; exists {
+		switch v := val.(type) {
+		case int:
+			return v
+		case float64:
+			return int(v)
+		}
+	}
+
+	return defaultHighPriorityThreshold
+}
+
+func (t *PriorityScoringTransformer) highPriorityTag() string {
+	if val, exists := t.config["high_priority_tag"]; exists {
+		if s, ok := val.(string); ok {
+			return s
+		}
+	}
+
+	return defaultHighPriorityTag
+}
+
+func (t *PriorityScoringTransformer) sourceTypes() map[string]bool {
+	types := map[string]bool{"gmail": true}
+
+	if list, err := priorityStringList(t.config, "source_types"); err == nil && len(list) > 0 {
+		types = make(map[string]bool, len(list))
+		for _, s := range list {
+			types[s] = true
+		}
+	}
+
+	return types
+}
+
+// Ensure interface compliance.
+var (
+	_ interfaces.Transformer        = (*PriorityScoringTransformer)(nil)
+	_ interfaces.OrderedTransformer = (*PriorityScoringTransformer)(nil)
+)
@@ -0,0 +1,210 @@
+package transform
+
+import (
+	"reflect"
+	"testing"
+
+	"pkm-sync/internal/sources/google/gmail"
+	"pkm-sync/pkg/models"
+)
+
+func newEmail(id, from string, to, cc []string, content string) models.FullItem {
+	toRecipients := make([]gmail.EmailRecipient, 0, len(to))
+	for _, addr := range to {
+		toRecipients = append(toRecipients, gmail.EmailRecipient{Email: addr})
+	}
+
+	ccRecipients := make([]gmail.EmailRecipient, 0, len(cc))
+	for _, addr := range cc {
+		ccRecipients = append(ccRecipients, gmail.EmailRecipient{Email: addr})
+	}
+
+	item := models.NewBasicItem(id, "Subject "+id)
+	item.SetSourceType("gmail")
+	item.SetItemType("email")
+	item.SetContent(content)
+	item.SetMetadata(map[string]interface{}{
+		"from": gmail.EmailRecipient{Name: "Sender", Email: from},
+		"to":   toRecipients,
+		"cc":   ccRecipients,
+	})
+
+	return item
+}
+
+func newPriorityScorer(t *testing.T) *PriorityScoringTransformer {
+	t.Helper()
+
+	transformer := NewPriorityScoringTransformer()
+
+	err := transformer.Configure(map[string]interface{}{
+		"vip_senders":  []interface{}{"ceo@acme.com"},
+		"vip_domains":  []interface{}{"bigcustomer.com"},
+		"my_addresses": []interface{}{"me@acme.com"},
+	})
+	if err != nil {
+		t.Fatalf("Configure failed: %v", err)
+	}
+
+	return transformer
+}
+
+func TestPriorityScoring_RepresentativeEmails(t *testing.T) {
+	transformer := newPriorityScorer(t)
+
+	tests := []struct {
+		name        string
+		item        models.FullItem
+		wantScore   int
+		wantReasons []string
+	}{
+		{
+			name: "VIP sender asking me directly",
+			item: newEmail("1", "CEO@acme.com", []string{"me@acme.com"}, nil,
+				"Can you send me the board deck by Friday?"),
+			wantScore:   70,
+			wantReasons: []string{"vip_sender:ceo@acme.com", "direct_recipient", "question"},
+		},
+		{
+			name: "customer domain with action item, me on CC",
+			item: newEmail("2", "buyer@eu.bigcustomer.com", []string{"sales@acme.com"}, []string{"me@acme.com"},
+				"Action item: confirm the renewal pricing."),
+			wantScore:   45,
+			wantReasons: []string{"vip_domain:bigcustomer.com", "cc_recipient", "action_items"},
+		},
+		{
+			name: "newsletter to a list",
+			item: newEmail("3", "news@vendor.io", []string{"all@acme.com"}, nil,
+				"Read our latest post: https://vendor.io/?ref=mail"),
+			wantScore: 0,
+		},
+		{
+			name: "quoted question does not count",
+			item: newEmail("4", "peer@acme.com", []string{"me@acme.com"}, nil,
+				"Sounds good.\n> Are we still on for lunch?"),
+			wantScore:   20,
+			wantReasons: []string{"direct_recipient"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			score, reasons := transformer.Score(tt.item)
+			if score != tt.wantScore {
+				t.Errorf("score = %d, want %d (reasons %v)", score, tt.wantScore, reasons)
+			}
+
+			if !reflect.DeepEqual(reasons, tt.wantReasons) {
+				t.Errorf("reasons = %v, want %v", reasons, tt.wantReasons)
+			}
+		})
+	}
+}
+
+func TestPriorityScoring_ThreadLengthAndCap(t *testing.T) {
+	transformer := newPriorityScorer(t)
+
+	thread := models.NewThread("t1", "Escalation")
+	thread.SetSourceType("gmail")
+	thread.SetContent("Who owns this?\nTODO: write the postmortem")
+
+	for i := 0; i < 8; i++ {
+		thread.AddMessage(newEmail("m", "ceo@acme.com", []string{"me@acme.com"}, nil, "ping"))
+	}
+
+	score, reasons := transformer.Score(thread)
+	if score != maxPriorityScore {
+		t.Errorf("score = %d, want capped %d (reasons %v)", score, maxPriorityScore, reasons)
+	}
+
+	if len(reasons) < 3 || reasons[2] != "thread_length:8" {
+		t.Errorf("expected thread_length:8 reason, got %v", reasons)
+	}
+
+	consolidated := models.NewBasicItem("thread_x", "Long thread")
+	consolidated.SetSourceType("gmail")
+	consolidated.SetMetadata(map[string]interface{}{"message_count": 3})
+
+	if score, _ := transformer.Score(consolidated); score != 6 {
+		t.Errorf("consolidated 3-message thread score = %d, want 6", score)
+	}
+}
+
+func TestPriorityScoring_TransformTagsHighPriority(t *testing.T) {
+	transformer := newPriorityScorer(t)
+
+	vip := newEmail("1", "ceo@acme.com", []string{"me@acme.com"}, nil, "Quick question?")
+	low := newEmail("2", "news@vendor.io", []string{"all@acme.com"}, nil, "Newsletter")
+
+	event := models.NewBasicItem("evt", "Standup")
+	event.SetSourceType(models.SourceTypeGoogleCalendar)
+
+	out, err := transformer.Transform([]models.FullItem{vip, low, event})
+	if err != nil {
+		t.Fatalf("Transform failed: %v", err)
+	}
+
+	if out[0].GetMetadata()[metadataKeyPriorityScore] != 70 || !hasTag(out[0], defaultHighPriorityTag) {
+		t.Errorf("expected VIP email scored 70 and tagged, got %v %v", out[0].GetMetadata(), out[0].GetTags())
+	}
+
+	if out[1].GetMetadata()[metadataKeyPriorityScore] != 0 || hasTag(out[1], defaultHighPriorityTag) {
+		t.Errorf("expected newsletter scored 0 and untagged, got %v %v", out[1].GetMetadata(), out[1].GetTags())
+	}
+
+	if out[2] != event {
+		t.Error("expected non-email item to pass through unchanged")
+	}
+
+	if _, exists := vip.GetMetadata()[metadataKeyPriorityScore]; exists {
+		t.Error("expected input item to be left unmodified")
+	}
+
+	again, err := transformer.Transform(out[:1])
+	if err != nil {
+		t.Fatalf("second Transform failed: %v", err)
+	}
+
+	if got := again[0].GetTags(); len(got) != 1 {
+		t.Errorf("expected the tag once after re-running, got %v", got)
+	}
+}
+
+func TestPriorityScoring_ConfigOverrides(t *testing.T) {
+	transformer := NewPriorityScoringTransformer()
+
+	err := transformer.Configure(map[string]interface{}{
+		"weights":           map[string]interface{}{"question": 60.0},
+		"high_priority_tag": "",
+		"source_types":      []interface{}{"slack"},
+	})
+	if err != nil {
+		t.Fatalf("Configure failed: %v", err)
+	}
+
+	msg := models.NewBasicItem("s1", "Slack")
+	msg.SetSourceType("slack")
+	msg.SetContent("Is the deploy done?")
+	msg.SetMetadata(map[string]interface{}{"from": "Dev <dev@acme.com>"})
+
+	out, err := transformer.Transform([]models.FullItem{msg})
+	if err != nil {
+		t.Fatalf("Transform failed: %v", err)
+	}
+
+	if out[0].GetMetadata()[metadataKeyPriorityScore] != 60 || len(out[0].GetTags()) != 0 {
+		t.Errorf("expected score 60 without tag, got %v %v", out[0].GetMetadata(), out[0].GetTags())
+	}
+
+	invalid := []map[string]interface{}{
+		{"weights": map[string]interface{}{"bogus": 1}},
+		{"weights": map[string]interface{}{"question": "high"}},
+		{"vip_senders": "ceo@acme.com"},
+	}
+
+	for _, cfg := range invalid {
+		if err := NewPriorityScoringTransformer().Configure(cfg); err == nil {
+			t.Errorf("expected error for config %v", cfg)
+		}
+	}
+}
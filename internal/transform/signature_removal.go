@@ -2,6 +2,7 @@ package transform
 
 import (
 	"regexp"
+	"slices"
 	"strings"
 
 	"pkm-sync/pkg/interfaces"
@@ -15,8 +16,35 @@ type SignatureRemovalTransformer struct {
 
 	// Pre-compiled signature patterns for performance
 	signatureRegexPatterns []*regexp.Regexp
+
+	// customPatternsOnly is set when configured patterns replace the
+	// defaults, which also turns off the localized sign-offs.
+	customPatternsOnly bool
+
+	// closingPhrases are the lowercased closings ("best regards", "thanks")
+	// that start a signature when a name line follows them.
+	closingPhrases []string
+}
+
+// defaultClosingPhrases are the closings used when closing_phrases is unset.
+var defaultClosingPhrases = []string{
+	"best", "best regards", "kind regards", "warm regards", "regards", "best wishes", "all the best",
+	"thanks", "thank you", "many thanks", "thanks again", "cheers", "sincerely", "talk soon",
 }
 
+// mobileFooterPatterns match the footers mail apps append to every message.
+// They apply even when custom patterns replace the defaults.
+var mobileFooterPatterns = []*regexp.Regexp{
+	regexp.MustCompile(`(?i)^Sent from my \S`),
+	regexp.MustCompile(`(?i)^Sent from (Mail for Windows|Outlook|Yahoo Mail|Gmail|Proton ?Mail)\b`),
+	regexp.MustCompile(`(?i)^Sent (via|with) \S`),
+	regexp.MustCompile(`(?i)^Get Outlook for (iOS|Android)`),
+}
+
+// nameLinePattern matches a line holding only a name, such as "Jane",
+// "J. Smith" or "- Mary-Jane O'Neil", the line that follows a closing.
+var nameLinePattern = regexp.MustCompile(`^[-~–—]?\s*\p{Lu}[\p{L}.'-]*(\s+\p{Lu}[\p{L}.'-]*){0,3}$`)
+
 func NewSignatureRemovalTransformer() *SignatureRemovalTransformer {
 	// Default signature patterns compiled once for performance
 	defaultPatterns := []*regexp.Regexp{
@@ -34,6 +62,7 @@ func NewSignatureRemovalTransformer() *SignatureRemovalTransformer {
 	return &SignatureRemovalTransformer{
 		config:                 make(map[string]interface{}),
 		signatureRegexPatterns: defaultPatterns,
+		closingPhrases:         defaultClosingPhrases,
 	}
 }
 
@@ -43,6 +72,11 @@ func (t *SignatureRemovalTransformer) Name() string {
 	return transformerNameSignatureRemoval
 }
 
+// RunsAfter lets content_cleanup strip HTML before signatures are detected.
+func (t *SignatureRemovalTransformer) RunsAfter() []string {
+	return []string{transformerNameContentCleanup}
+}
+
 func (t *SignatureRemovalTransformer) Configure(config map[string]interface{}) error {
 	t.config = config
 
@@ -51,6 +85,8 @@ func (t *SignatureRemovalTransformer) Configure(config map[string]interface{}) e
 		t.loadCustomPatterns(patterns)
 	}
 
+	t.closingPhrases = t.getClosingPhrases()
+
 	return nil
 }
 
@@ -58,7 +94,12 @@ func (t *SignatureRemovalTransformer) Transform(items []models.FullItem) ([]mode
 	transformedItems := make([]models.FullItem, len(items))
 
 	for i, item := range items {
-		cleanedContent := t.ExtractSignatures(item.GetContent())
+		lang := itemLanguage(item)
+		if lang == "" {
+			lang = detectLanguage(item.GetContent())
+		}
+
+		cleanedContent := t.extractSignatures(item.GetContent(), lang)
 
 		if cleanedContent != item.GetContent() {
 			// Create a new item copy (preserving type)
@@ -108,15 +149,44 @@ func (t *SignatureRemovalTransformer) Transform(items []models.FullItem) ([]mode
 }
 
 // ExtractSignatures extracts email signatures from content.
-// Extracted from Gmail's ContentProcessor.ExtractSignatures.
+// Extracted from Gmail's ContentProcessor.ExtractSignatures. Sign-offs are
+// matched for the detected language of content.
 func (t *SignatureRemovalTransformer) ExtractSignatures(content string) string {
+	return t.extractSignatures(content, detectLanguage(content))
+}
+
+func (t *SignatureRemovalTransformer) extractSignatures(content, lang string) string {
+	var locales []localePatterns
+	if !t.customPatternsOnly {
+		locales = patternsFor(lang, configuredLanguages(t.config))
+	}
+
 	lines := strings.Split(content, "\n")
 
-	var (
-		contentLines []string
-		inSignature  bool
-	)
+	start := t.signatureStart(lines, locales)
 
+	// A message that is mostly "signature" is more likely a short reply such
+	// as "Thanks,\nJane" than a body with a signature; keep all of it.
+	if removesMostOf(lines, start) {
+		start = len(lines)
+	}
+
+	// Join content lines
+	result := strings.Join(lines[:start], "\n")
+
+	// Additional cleanup if enabled
+	if t.shouldTrimEmptyLines() {
+		result = t.trimTrailingEmptyLines(result)
+	}
+	// Note: When trim_empty_lines is false, we preserve all content as-is
+
+	return result
+}
+
+// signatureStart returns the index of the line the signature starts at, or
+// len(lines) when there is none. The "-- " delimiter (RFC 3676) counts
+// anywhere; other indicators only in the last max_signature_lines lines.
+func (t *SignatureRemovalTransformer) signatureStart(lines []string, locales []localePatterns) int {
 	maxSignatureLines := t.getMaxSignatureLines()
 
 	for i, line := range lines {
@@ -124,46 +194,66 @@ func (t *SignatureRemovalTransformer) ExtractSignatures(content string) string {
 
 		// Common signature indicators
 		if trimmed == "--" || strings.HasPrefix(trimmed, "-- ") {
-			inSignature = true
+			return i
+		}
 
+		// Check if we're near the end and this looks like signature content
+		if len(lines)-i > maxSignatureLines {
 			continue
 		}
 
-		// Look for patterns that might indicate signatures
-		if !inSignature {
-			// Check if we're near the end and this looks like signature content
-			remainingLines := len(lines) - i
-			if remainingLines <= maxSignatureLines {
-				if t.looksLikeSignature(trimmed) {
-					inSignature = true
-					// Don't include this line either
-
-					continue
-				}
-			}
+		if matchesAnyPattern(mobileFooterPatterns, trimmed) ||
+			t.startsClosing(trimmed, lines[i+1:]) ||
+			t.looksLikeSignature(trimmed, locales) {
+			return i
 		}
+	}
+
+	return len(lines)
+}
 
-		if !inSignature {
-			contentLines = append(contentLines, line)
+// startsClosing reports whether line is a configured closing phrase, ignoring
+// case and trailing punctuation, and the next non-empty line is a name.
+func (t *SignatureRemovalTransformer) startsClosing(line string, rest []string) bool {
+	phrase := strings.ToLower(strings.TrimRight(line, ",.!: "))
+	if phrase == "" || !slices.Contains(t.closingPhrases, phrase) {
+		return false
+	}
+
+	for _, next := range rest {
+		if next = strings.TrimSpace(next); next != "" {
+			return nameLinePattern.MatchString(next)
 		}
 	}
 
-	// Join content lines
-	result := strings.Join(contentLines, "\n")
+	return false
+}
 
-	// Additional cleanup if enabled
-	if t.shouldTrimEmptyLines() {
-		result = t.trimTrailingEmptyLines(result)
+// removesMostOf reports whether cutting lines at start would remove more than
+// half of the non-whitespace text.
+func removesMostOf(lines []string, start int) bool {
+	var kept, removed int
+
+	for i, line := range lines {
+		n := len(strings.Join(strings.Fields(line), ""))
+		if i < start {
+			kept += n
+		} else {
+			removed += n
+		}
 	}
-	// Note: When trim_empty_lines is false, we preserve all content as-is
 
-	return result
+	return removed > kept
 }
 
 // looksLikeSignature checks if a line looks like it could be part of a signature.
-func (t *SignatureRemovalTransformer) looksLikeSignature(line string) bool {
-	for _, pattern := range t.signatureRegexPatterns {
-		if pattern.MatchString(line) {
+func (t *SignatureRemovalTransformer) looksLikeSignature(line string, locales []localePatterns) bool {
+	if matchesAnyPattern(t.signatureRegexPatterns, line) {
+		return true
+	}
+
+	for _, locale := range locales {
+		if matchesAnyPattern(locale.signoffs, line) {
 			return true
 		}
 	}
@@ -223,6 +313,33 @@ func (t *SignatureRemovalTransformer) shouldTrimEmptyLines() bool {
 	return true // Default: trim trailing empty lines
 }
 
+func (t *SignatureRemovalTransformer) getClosingPhrases() []string {
+	var raw []string
+
+	switch v := t.config["closing_phrases"].(type) {
+	case []string:
+		raw = v
+	case []interface{}:
+		for _, item := range v {
+			if s, ok := item.(string); ok {
+				raw = append(raw, s)
+			}
+		}
+	default:
+		return defaultClosingPhrases // Default: common English closings
+	}
+
+	phrases := make([]string, 0, len(raw))
+
+	for _, phrase := range raw {
+		if phrase = strings.ToLower(strings.TrimRight(strings.TrimSpace(phrase), ",.!: ")); phrase != "" {
+			phrases = append(phrases, phrase)
+		}
+	}
+
+	return phrases
+}
+
 // GetDefaultPatterns returns the default signature patterns for reference.
 func (t *SignatureRemovalTransformer) GetDefaultPatterns() []string {
 	return []string{
@@ -263,8 +380,12 @@ func (t *SignatureRemovalTransformer) loadCustomPatterns(patterns interface{}) {
 
 	if len(customPatterns) > 0 {
 		t.signatureRegexPatterns = customPatterns
+		t.customPatternsOnly = !t.shouldMergeWithDefaults()
 	}
 }
 
 // Ensure interface compliance.
-var _ interfaces.Transformer = (*SignatureRemovalTransformer)(nil)
+var (
+	_ interfaces.Transformer        = (*SignatureRemovalTransformer)(nil)
+	_ interfaces.OrderedTransformer = (*SignatureRemovalTransformer)(nil)
+)
@@ -67,12 +67,12 @@ Some important information.`,
 		},
 		{
 			name: "Remove signature with 'Sent from my'",
-			input: `Quick message.
+			input: `Quick message about tomorrow's planning meeting.
 
 Thanks!
 
 Sent from my iPhone`,
-			expected: `Quick message.`, // "Thanks!" is also detected as signature pattern
+			expected: `Quick message about tomorrow's planning meeting.`, // "Thanks!" is also detected as signature pattern
 		},
 		{
 			name: "Remove signature with email address",
@@ -98,7 +98,7 @@ This should remain unchanged.`,
 		},
 		{
 			name: "Multiple signature patterns",
-			input: `Email content.
+			input: `Email content with the quarterly numbers attached.
 
 Thanks for your time.
 
@@ -106,7 +106,7 @@ Best regards,
 John Smith
 john@example.com
 555-123-4567`,
-			expected: `Email content.
+			expected: `Email content with the quarterly numbers attached.
 
 Thanks for your time.`, // Only "Best regards," block is detected as signature
 		},
@@ -117,7 +117,11 @@ Thanks for your time.`, // Only "Best regards," block is detected as signature
 Best regards mentioned in content.
 
 More content after the mention.`,
-			expected: `Email content.`, // Current logic detects "Best regards" as signature due to proximity to end
+			expected: `Email content.
+
+Best regards mentioned in content.
+
+More content after the mention.`, // Cutting at "Best regards" would remove most of the message
 		},
 		{
 			name:     "Empty content",
@@ -153,14 +157,14 @@ func TestSignatureRemovalTransformer_Transform(t *testing.T) {
 				models.AsFullItem(&models.Item{
 					ID:      "1",
 					Title:   "Test Email",
-					Content: "Email content.\n\n--\nBest regards,\nJohn",
+					Content: "Email content with the agenda for next week.\n\n--\nBest regards,\nJohn",
 				}),
 			},
 			expected: []models.FullItem{
 				models.AsFullItem(&models.Item{
 					ID:      "1",
 					Title:   "Test Email",
-					Content: "Email content.",
+					Content: "Email content with the agenda for next week.",
 				}),
 			},
 		},
@@ -187,7 +191,7 @@ func TestSignatureRemovalTransformer_Transform(t *testing.T) {
 				models.AsFullItem(&models.Item{
 					ID:      "3",
 					Title:   "Email 1",
-					Content: "Content 1\n\nBest regards,\nSender",
+					Content: "Content 1, the body of the first email\n\nBest regards,\nSender",
 				}),
 				models.AsFullItem(&models.Item{
 					ID:      "4",
@@ -199,7 +203,7 @@ func TestSignatureRemovalTransformer_Transform(t *testing.T) {
 				models.AsFullItem(&models.Item{
 					ID:      "3",
 					Title:   "Email 1",
-					Content: "Content 1",
+					Content: "Content 1, the body of the first email",
 				}),
 				models.AsFullItem(&models.Item{
 					ID:      "4",
@@ -266,7 +270,7 @@ func TestSignatureRemovalTransformer_looksLikeSignature(t *testing.T) {
 	}
 
 	for _, tt := range tests {
-		result := transformer.looksLikeSignature(tt.line)
+		result := transformer.looksLikeSignature(tt.line, nil)
 		if result != tt.expected {
 			t.Errorf("looksLikeSignature(%q) = %v, expected %v", tt.line, result, tt.expected)
 		}
@@ -293,13 +297,15 @@ func TestSignatureRemovalTransformer_CustomPatterns(t *testing.T) {
 	input := `Email content here.
 
 Important message.
+Please review the attached proposal before Monday.
 
 Custom signature pattern
 This should be removed.`
 
 	expected := `Email content here.
 
-Important message.`
+Important message.
+Please review the attached proposal before Monday.`
 
 	result := transformer.ExtractSignatures(input)
 	result = strings.TrimSpace(result)
@@ -344,7 +350,7 @@ Line 4`, // Only last 3 lines checked for signatures
 			config: map[string]interface{}{
 				"trim_empty_lines": false,
 			},
-			input: `Content.
+			input: `Content of the message.
 
 --
 Signature
@@ -352,7 +358,7 @@ Signature
 
 
 `,
-			expected: "Content.\n", // Empty lines preserved
+			expected: "Content of the message.\n", // Empty lines preserved
 		},
 	}
 
@@ -437,3 +443,82 @@ func TestSignatureRemovalTransformer_trimTrailingEmptyLines(t *testing.T) {
 		}
 	}
 }
+
+func TestSignatureRemovalTransformer_ClosingsAndFooters(t *testing.T) {
+	body := "The deploy went out this morning and the dashboards look healthy."
+
+	tests := []struct {
+		name     string
+		config   map[string]interface{}
+		input    string
+		expected string
+	}{
+		{
+			name:     "RFC 3676 delimiter",
+			input:    body + "\n\n-- \nJane Smith\nStaff Engineer",
+			expected: body,
+		},
+		{
+			name:     "Closing followed by a name",
+			input:    body + "\n\nKind regards,\nJ. Smith",
+			expected: body,
+		},
+		{
+			name:     "Closing followed by prose is kept",
+			input:    body + "\n\nThank you\nfor sending the logs over so quickly.",
+			expected: body + "\n\nThank you\nfor sending the logs over so quickly.",
+		},
+		{
+			name:     "Mobile footer",
+			input:    body + "\n\nSent from Yahoo Mail on Android",
+			expected: body,
+		},
+		{
+			name: "Mobile footer with custom patterns only",
+			config: map[string]interface{}{
+				"patterns":            []interface{}{"^Company confidential"},
+				"merge_with_defaults": false,
+			},
+			input:    body + "\n\nSent from my iPhone",
+			expected: body,
+		},
+		{
+			name:     "Configured closing phrases",
+			config:   map[string]interface{}{"closing_phrases": []interface{}{"Ta,"}},
+			input:    body + "\n\nTa\nJane",
+			expected: body,
+		},
+		{
+			name:     "Configured closing phrases replace the defaults",
+			config:   map[string]interface{}{"closing_phrases": []interface{}{"Ta"}},
+			input:    body + "\n\nWarm regards,\nJane",
+			expected: body + "\n\nWarm regards,\nJane",
+		},
+		{
+			name:     "Whole message looks like a signature",
+			input:    "Thanks,\nJane",
+			expected: "Thanks,\nJane",
+		},
+		{
+			name:     "More than half would be removed",
+			input:    "Sounds good.\n\n--\nJane Smith\njane.smith@example.com",
+			expected: "Sounds good.\n\n--\nJane Smith\njane.smith@example.com",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			transformer := NewSignatureRemovalTransformer()
+
+			if tt.config != nil {
+				if err := transformer.Configure(tt.config); err != nil {
+					t.Fatalf("Failed to configure: %v", err)
+				}
+			}
+
+			if result := transformer.ExtractSignatures(tt.input); result != tt.expected {
+				t.Errorf("Expected:\n'%s'\nGot:\n'%s'", tt.expected, result)
+			}
+		})
+	}
+}
@@ -13,10 +13,18 @@ import (
 
 const (
 	// DefaultThreadSummaryLength is the default number of messages to include in thread summaries.
-	DefaultThreadSummaryLength    = 5
+	DefaultThreadSummaryLength = 5
+	// DefaultMaxConsolidatedItems caps how many messages a consolidated thread note holds.
+	DefaultMaxConsolidatedItems = 100
+	// DefaultTrivialReplyMaxWords is the longest reply, in words, that
+	// collapse_trivial_replies treats as an acknowledgement ("Thanks!", "+1").
+	DefaultTrivialReplyMaxWords   = 5
+	consolidatedOverflowTruncate  = "truncate"
+	consolidatedOverflowSummary   = "summary"
 	transformerNameThreadGrouping = "thread_grouping"
 	threadModeConsolidated        = "consolidated"
 	threadModeSummary             = "summary"
+	threadModeIndividual          = "individual"
 	sourceTypeGmail               = "gmail"
 )
 
@@ -47,6 +55,11 @@ func (t *ThreadGroupingTransformer) Name() string {
 	return transformerNameThreadGrouping
 }
 
+// RunsAfter groups messages only once their content has been cleaned.
+func (t *ThreadGroupingTransformer) RunsAfter() []string {
+	return []string{transformerNameContentCleanup, transformerNameSignatureRemoval}
+}
+
 func (t *ThreadGroupingTransformer) Configure(config map[string]interface{}) error {
 	t.config = config
 
@@ -70,24 +83,39 @@ func (t *ThreadGroupingTransformer) Transform(items []models.FullItem) ([]models
 		legacyItems[i] = models.AsItemStruct(item)
 	}
 
-	// Group items by thread ID
-	threadGroups := t.groupItemsByThread(legacyItems)
+	// Bucket items by their effective thread mode. Sources may set a
+	// per-item thread_mode; items already consolidated at the source
+	// (thread_consolidated) pass through so they are never grouped twice.
+	buckets := make(map[string][]*models.Item)
+
+	for _, item := range legacyItems {
+		if item == nil {
+			continue
+		}
+
+		mode := strings.ToLower(t.itemThreadMode(item))
+		if t.isConsolidated(item) {
+			mode = threadModeIndividual
+		}
 
-	// Apply the configured thread processing mode
-	mode := t.getThreadMode()
+		buckets[mode] = append(buckets[mode], item)
+	}
 
 	var resultLegacyItems []*models.Item
 
-	switch strings.ToLower(mode) {
-	case threadModeConsolidated:
-		resultLegacyItems = t.consolidateThreads(threadGroups)
-	case threadModeSummary:
-		resultLegacyItems = t.summarizeThreads(threadGroups)
-	case "individual", "":
-		// Default: return individual items
-		resultLegacyItems = legacyItems
-	default:
-		return nil, fmt.Errorf("unknown thread mode: %s (supported: individual, consolidated, summary)", mode)
+	for _, mode := range sortedModes(buckets) {
+		bucket := buckets[mode]
+
+		switch mode {
+		case threadModeConsolidated:
+			resultLegacyItems = append(resultLegacyItems, t.consolidateThreads(t.groupItemsByThread(bucket))...)
+		case threadModeSummary:
+			resultLegacyItems = append(resultLegacyItems, t.summarizeThreads(t.groupItemsByThread(bucket))...)
+		case threadModeIndividual, "":
+			resultLegacyItems = append(resultLegacyItems, bucket...)
+		default:
+			return nil, fmt.Errorf("unknown thread mode: %s (supported: individual, consolidated, summary)", mode)
+		}
 	}
 
 	// Convert back to FullItem
@@ -176,6 +204,31 @@ func (t *ThreadGroupingTransformer) consolidateThreads(threadGroups map[string]*
 			continue
 		}
 
+		// A thread of one message and acknowledgements keeps just the message.
+		trivialReplies := t.shouldCollapseTrivialReplies() && t.hasOnlyTrivialReplies(group)
+
+		// Threads beyond max_consolidated_items are truncated to their most
+		// recent messages, or rendered as a summary instead.
+		maxItems := t.getMaxConsolidatedItems()
+		overflow := maxItems > 0 && len(group.Items) > maxItems && !trivialReplies
+
+		if overflow && t.getConsolidatedOverflow() == consolidatedOverflowSummary {
+			consolidatedItems = append(consolidatedItems, t.summaryItem(group))
+
+			continue
+		}
+
+		if !overflow {
+			maxItems = 0
+		}
+
+		var content string
+		if trivialReplies {
+			content = t.buildCollapsedContent(group)
+		} else {
+			content = t.buildConsolidatedContent(group, maxItems)
+		}
+
 		// Create consolidated thread item
 		title := fmt.Sprintf("Thread_%s_%d-items",
 			utils.SanitizeThreadSubject(group.Subject, group.ThreadID),
@@ -184,7 +237,7 @@ func (t *ThreadGroupingTransformer) consolidateThreads(threadGroups map[string]*
 		consolidated := &models.Item{
 			ID:          fmt.Sprintf("thread_%s", group.ThreadID),
 			Title:       title,
-			Content:     t.buildConsolidatedContent(group),
+			Content:     content,
 			SourceType:  t.inferSourceType(group.Items),
 			ItemType:    t.inferConsolidatedItemType(group.Items),
 			CreatedAt:   group.StartTime,
@@ -195,6 +248,15 @@ func (t *ThreadGroupingTransformer) consolidateThreads(threadGroups map[string]*
 			Attachments: t.consolidateAttachments(group.Items),
 		}
 
+		if trivialReplies {
+			consolidated.Metadata["trivial_replies"] = len(group.Items) - 1
+		}
+
+		if overflow {
+			consolidated.Metadata["thread_truncated"] = true
+			consolidated.Metadata["omitted_items"] = len(group.Items) - maxItems
+		}
+
 		consolidatedItems = append(consolidatedItems, consolidated)
 	}
 
@@ -213,38 +275,45 @@ func (t *ThreadGroupingTransformer) summarizeThreads(threadGroups map[string]*Th
 			continue
 		}
 
-		// Create thread summary
-		maxItems := t.getThreadSummaryLength()
-		if maxItems <= 0 {
-			maxItems = DefaultThreadSummaryLength
-		}
-
-		title := fmt.Sprintf("Thread-Summary_%s_%d-items",
-			utils.SanitizeThreadSubject(group.Subject, group.ThreadID),
-			group.ItemCount)
-
-		summary := &models.Item{
-			ID:          fmt.Sprintf("thread_summary_%s", group.ThreadID),
-			Title:       title,
-			Content:     t.buildThreadSummary(group, maxItems),
-			SourceType:  t.inferSourceType(group.Items),
-			ItemType:    t.inferSummaryItemType(group.Items),
-			CreatedAt:   group.StartTime,
-			UpdatedAt:   group.EndTime,
-			Metadata:    t.buildThreadMetadata(group),
-			Tags:        t.buildThreadTags(group),
-			Links:       t.consolidateLinks(group.Items),
-			Attachments: t.consolidateAttachments(group.Items),
-		}
-
-		summarizedItems = append(summarizedItems, summary)
+		summarizedItems = append(summarizedItems, t.summaryItem(group))
 	}
 
 	return summarizedItems
 }
 
-// buildConsolidatedContent builds content for consolidated thread (all items).
-func (t *ThreadGroupingTransformer) buildConsolidatedContent(group *ThreadGroup) string {
+// summaryItem builds the summary representation of a multi-item thread.
+func (t *ThreadGroupingTransformer) summaryItem(group *ThreadGroup) *models.Item {
+	// Create thread summary
+	maxItems := t.groupSummaryLength(group)
+	if maxItems <= 0 {
+		maxItems = DefaultThreadSummaryLength
+	}
+
+	title := fmt.Sprintf("Thread-Summary_%s_%d-items",
+		utils.SanitizeThreadSubject(group.Subject, group.ThreadID),
+		group.ItemCount)
+
+	summary := &models.Item{
+		ID:          fmt.Sprintf("thread_summary_%s", group.ThreadID),
+		Title:       title,
+		Content:     t.buildThreadSummary(group, maxItems),
+		SourceType:  t.inferSourceType(group.Items),
+		ItemType:    t.inferSummaryItemType(group.Items),
+		CreatedAt:   group.StartTime,
+		UpdatedAt:   group.EndTime,
+		Metadata:    t.buildThreadMetadata(group),
+		Tags:        t.buildThreadTags(group),
+		Links:       t.consolidateLinks(group.Items),
+		Attachments: t.consolidateAttachments(group.Items),
+	}
+
+	return summary
+}
+
+// buildConsolidatedContent builds content for consolidated thread. When maxItems
+// is positive and the thread is longer, only the most recent maxItems items are
+// rendered, after a note saying how many earlier ones were omitted.
+func (t *ThreadGroupingTransformer) buildConsolidatedContent(group *ThreadGroup, maxItems int) string {
 	var content strings.Builder
 
 	content.WriteString(fmt.Sprintf("# Thread: %s\n\n", group.Subject))
@@ -255,25 +324,120 @@ func (t *ThreadGroupingTransformer) buildConsolidatedContent(group *ThreadGroup)
 		group.StartTime.Format("2006-01-02 15:04"),
 		group.EndTime.Format("2006-01-02 15:04")))
 
+	items := group.Items
+	omitted := 0
+
+	if maxItems > 0 && len(items) > maxItems {
+		omitted = len(items) - maxItems
+		items = items[omitted:]
+
+		content.WriteString(fmt.Sprintf("*%d earlier messages omitted.*", omitted))
+
+		if url := t.fullThreadURL(group); url != "" {
+			content.WriteString(fmt.Sprintf(" [View the full thread](%s)", url))
+		}
+
+		content.WriteString("\n\n")
+	}
+
 	content.WriteString("---\n\n")
 
-	for i, item := range group.Items {
-		content.WriteString(fmt.Sprintf("## Item %d: %s\n\n", i+1, item.Title))
+	collapse := t.shouldCollapseConsecutiveSenders()
+	prevAuthor := ""
+
+	for i, item := range items {
+		author := t.extractAuthor(item)
+
+		// A run of messages from one sender shares a section: later messages
+		// become timestamped paragraphs under the first one's header.
+		if collapse && i > 0 && author != "" && author == prevAuthor {
+			content.WriteString(fmt.Sprintf("*Item %d · %s*\n\n",
+				omitted+i+1, item.CreatedAt.Format("2006-01-02 15:04:05")))
+			content.WriteString(item.Content)
+			content.WriteString("\n\n")
+
+			continue
+		}
+
+		if i > 0 {
+			content.WriteString("---\n\n")
+		}
+
+		prevAuthor = author
+
+		content.WriteString(fmt.Sprintf("## Item %d: %s\n\n", omitted+i+1, item.Title))
 		content.WriteString(fmt.Sprintf("**Date:** %s  \n", item.CreatedAt.Format("2006-01-02 15:04:05")))
 
 		// Add author/sender information if available
-		if author := t.extractAuthor(item); author != "" {
+		if author != "" {
 			content.WriteString(fmt.Sprintf("**From:** %s  \n", author))
 		}
 
 		content.WriteString("\n")
 		content.WriteString(item.Content)
-		content.WriteString("\n\n---\n\n")
+		content.WriteString("\n\n")
+	}
+
+	if len(items) > 0 {
+		content.WriteString("---\n\n")
 	}
 
 	return content.String()
 }
 
+// hasOnlyTrivialReplies reports whether every message after the first is a
+// short acknowledgement, at most trivial_reply_max_words words long.
+func (t *ThreadGroupingTransformer) hasOnlyTrivialReplies(group *ThreadGroup) bool {
+	maxWords := t.getTrivialReplyMaxWords()
+
+	for _, item := range group.Items[1:] {
+		if len(strings.Fields(item.Content)) > maxWords {
+			return false
+		}
+	}
+
+	return true
+}
+
+// buildCollapsedContent renders a thread whose replies are all trivial as its
+// first message followed by a note counting the replies and their senders.
+func (t *ThreadGroupingTransformer) buildCollapsedContent(group *ThreadGroup) string {
+	first := *group
+	first.Items = group.Items[:1]
+
+	var content strings.Builder
+
+	content.WriteString(t.buildConsolidatedContent(&first, 0))
+
+	replies := len(group.Items) - 1
+	noun := "replies"
+
+	if replies == 1 {
+		noun = "reply"
+	}
+
+	content.WriteString(fmt.Sprintf("*%d short %s collapsed", replies, noun))
+
+	var authors []string
+
+	seen := make(map[string]bool)
+
+	for _, item := range group.Items[1:] {
+		if author := t.extractAuthor(item); author != "" && !seen[author] {
+			seen[author] = true
+			authors = append(authors, author)
+		}
+	}
+
+	if len(authors) > 0 {
+		content.WriteString(" from " + strings.Join(authors, ", "))
+	}
+
+	content.WriteString(".*\n")
+
+	return content.String()
+}
+
 // buildThreadSummary builds content for thread summary (key items only).
 func (t *ThreadGroupingTransformer) buildThreadSummary(group *ThreadGroup, maxItems int) string {
 	var content strings.Builder
@@ -412,6 +576,35 @@ func (t *ThreadGroupingTransformer) extractThreadID(item *models.Item) string {
 	return ""
 }
 
+// itemThreadMode returns the thread mode a source stamped on the item, falling
+// back to the transformer's configured mode.
+func (t *ThreadGroupingTransformer) itemThreadMode(item *models.Item) string {
+	if mode, ok := item.Metadata["thread_mode"].(string); ok && mode != "" {
+		return mode
+	}
+
+	return t.getThreadMode()
+}
+
+// isConsolidated reports whether the source already aggregated the thread into this item.
+func (t *ThreadGroupingTransformer) isConsolidated(item *models.Item) bool {
+	consolidated, _ := item.Metadata["thread_consolidated"].(bool)
+
+	return consolidated
+}
+
+// groupSummaryLength prefers a thread_summary_length stamped by the source on
+// the thread's first item over the transformer's max_thread_items.
+func (t *ThreadGroupingTransformer) groupSummaryLength(group *ThreadGroup) int {
+	if len(group.Items) > 0 {
+		if length, ok := group.Items[0].Metadata["thread_summary_length"].(int); ok && length > 0 {
+			return length
+		}
+	}
+
+	return t.getThreadSummaryLength()
+}
+
 func (t *ThreadGroupingTransformer) extractThreadSubject(item *models.Item) string {
 	// Clean up subject line (remove Re:, Fwd:, etc.)
 	subject := item.Title
@@ -444,23 +637,15 @@ func (t *ThreadGroupingTransformer) extractThreadSubject(item *models.Item) stri
 func (t *ThreadGroupingTransformer) extractParticipants(item *models.Item) []string {
 	var participants []string
 
-	// Extract from metadata if available
-	if from, exists := item.Metadata["from"]; exists {
-		if author := t.extractEmailFromRecipient(from); author != "" {
-			participants = append(participants, author)
-		}
+	if author := t.extractAuthor(item); author != "" {
+		participants = append(participants, author)
 	}
 
 	return participants
 }
 
 func (t *ThreadGroupingTransformer) updateParticipants(group *ThreadGroup, item *models.Item) {
-	from, exists := item.Metadata["from"]
-	if !exists {
-		return
-	}
-
-	author := t.extractEmailFromRecipient(from)
+	author := t.extractAuthor(item)
 	if author == "" {
 		return
 	}
@@ -474,11 +659,17 @@ func (t *ThreadGroupingTransformer) updateParticipants(group *ThreadGroup, item
 	group.Participants = append(group.Participants, author)
 }
 
+// extractAuthor returns the sender of an email ("from") or the author of a
+// chat message such as a Slack message ("author").
 func (t *ThreadGroupingTransformer) extractAuthor(item *models.Item) string {
 	if from, exists := item.Metadata["from"]; exists {
 		return t.extractEmailFromRecipient(from)
 	}
 
+	if author, ok := item.Metadata["author"].(string); ok {
+		return author
+	}
+
 	return ""
 }
 
@@ -529,6 +720,14 @@ func (t *ThreadGroupingTransformer) buildThreadMetadata(group *ThreadGroup) map[
 	metadata["participants"] = group.Participants
 	metadata["start_time"] = group.StartTime
 	metadata["end_time"] = group.EndTime
+	metadata["thread_consolidated"] = true
+
+	// Keep the configured source name so sinks can still attribute the thread.
+	if len(group.Items) > 0 {
+		if name, ok := group.Items[0].Metadata[models.MetadataSourceName]; ok {
+			metadata[models.MetadataSourceName] = name
+		}
+	}
 
 	// Safe duration calculation
 	if !group.StartTime.IsZero() && !group.EndTime.IsZero() {
@@ -588,6 +787,20 @@ func (t *ThreadGroupingTransformer) inferSummaryItemType(items []*models.Item) s
 	return "thread_summary"
 }
 
+// fullThreadURL links to the complete thread: Gmail's web UI for Gmail threads,
+// otherwise the first link on the thread's first item.
+func (t *ThreadGroupingTransformer) fullThreadURL(group *ThreadGroup) string {
+	if t.inferSourceType(group.Items) == sourceTypeGmail && group.ThreadID != "" {
+		return "https://mail.google.com/mail/u/0/#all/" + group.ThreadID
+	}
+
+	if len(group.Items) > 0 && len(group.Items[0].Links) > 0 {
+		return group.Items[0].Links[0].URL
+	}
+
+	return ""
+}
+
 // Configuration helper methods
 
 func (t *ThreadGroupingTransformer) isEnabled() bool {
@@ -623,6 +836,64 @@ func (t *ThreadGroupingTransformer) getThreadSummaryLength() int {
 	return DefaultThreadSummaryLength
 }
 
+// getMaxConsolidatedItems is the consolidated-mode counterpart of max_thread_items:
+// the most messages one consolidated note holds. Zero or negative means no cap.
+func (t *ThreadGroupingTransformer) getMaxConsolidatedItems() int {
+	if val, exists := t.config["max_consolidated_items"]; exists {
+		switch v := val.(type) {
+		case int:
+			return v
+		case float64:
+			return int(v)
+		}
+	}
+
+	return DefaultMaxConsolidatedItems
+}
+
+// getConsolidatedOverflow says what happens to threads over the cap: "truncate"
+// keeps the most recent messages, "summary" switches to the summary representation.
+func (t *ThreadGroupingTransformer) getConsolidatedOverflow() string {
+	if val, ok := t.config["consolidated_overflow"].(string); ok && val != "" {
+		return strings.ToLower(val)
+	}
+
+	return consolidatedOverflowTruncate
+}
+
+// shouldCollapseConsecutiveSenders reports whether consolidated notes merge
+// back-to-back messages from the same sender into one section. Default: off.
+func (t *ThreadGroupingTransformer) shouldCollapseConsecutiveSenders() bool {
+	if val, ok := t.config["collapse_consecutive_senders"].(bool); ok {
+		return val
+	}
+
+	return false
+}
+
+// shouldCollapseTrivialReplies reports whether consolidated threads whose
+// replies are all acknowledgements keep only their first message. Default: off.
+func (t *ThreadGroupingTransformer) shouldCollapseTrivialReplies() bool {
+	if val, ok := t.config["collapse_trivial_replies"].(bool); ok {
+		return val
+	}
+
+	return false
+}
+
+func (t *ThreadGroupingTransformer) getTrivialReplyMaxWords() int {
+	if val, exists := t.config["trivial_reply_max_words"]; exists {
+		switch v := val.(type) {
+		case int:
+			return v
+		case float64:
+			return int(v)
+		}
+	}
+
+	return DefaultTrivialReplyMaxWords
+}
+
 // consolidateLinks merges links from all items in a thread, removing duplicates.
 func (t *ThreadGroupingTransformer) consolidateLinks(items []*models.Item) []models.Link {
 	seenURLs := make(map[string]bool)
@@ -660,6 +931,18 @@ func (t *ThreadGroupingTransformer) consolidateAttachments(items []*models.Item)
 	return allAttachments
 }
 
+// sortedModes returns the bucket keys in a stable order.
+func sortedModes(buckets map[string][]*models.Item) []string {
+	modes := make([]string, 0, len(buckets))
+	for mode := range buckets {
+		modes = append(modes, mode)
+	}
+
+	sort.Strings(modes)
+
+	return modes
+}
+
 // minInt returns the smaller of two integers.
 func minInt(a, b int) int {
 	if a < b {
@@ -670,4 +953,7 @@ func minInt(a, b int) int {
 }
 
 // Ensure interface compliance.
-var _ interfaces.Transformer = (*ThreadGroupingTransformer)(nil)
+var (
+	_ interfaces.Transformer        = (*ThreadGroupingTransformer)(nil)
+	_ interfaces.OrderedTransformer = (*ThreadGroupingTransformer)(nil)
+)
@@ -1,6 +1,7 @@
 package transform
 
 import (
+	"fmt"
 	"strings"
 	"testing"
 	"time"
@@ -579,3 +580,331 @@ func TestThreadGroupingTransformer_ErrorHandling(t *testing.T) {
 		t.Error("Expected error with invalid mode")
 	}
 }
+
+// longThread returns n gmail messages in one thread, an hour apart.
+func longThread(threadID string, n int) []models.FullItem {
+	start := time.Date(2026, 1, 5, 9, 0, 0, 0, time.UTC)
+	items := make([]models.FullItem, 0, n)
+
+	for i := range n {
+		items = append(items, models.AsFullItem(&models.Item{
+			ID:         fmt.Sprintf("msg-%03d", i+1),
+			Title:      "Re: Incident review",
+			Content:    fmt.Sprintf("message body %03d", i+1),
+			SourceType: "gmail",
+			CreatedAt:  start.Add(time.Duration(i) * time.Hour),
+			Metadata: map[string]interface{}{
+				"thread_id": threadID,
+				"from":      fmt.Sprintf("user%d@example.com", i%4),
+			},
+		}))
+	}
+
+	return items
+}
+
+func TestThreadGroupingTransformer_ConsolidatedCapTruncates(t *testing.T) {
+	transformer := NewThreadGroupingTransformer()
+	if err := transformer.Configure(map[string]interface{}{"mode": "consolidated"}); err != nil {
+		t.Fatalf("Failed to configure: %v", err)
+	}
+
+	result, err := transformer.Transform(longThread("big-thread", 200))
+	if err != nil {
+		t.Fatalf("Transform failed: %v", err)
+	}
+
+	if len(result) != 1 {
+		t.Fatalf("expected 1 consolidated item, got %d", len(result))
+	}
+
+	content := result[0].GetContent()
+
+	omission := "*100 earlier messages omitted.* [View the full thread](https://mail.google.com/mail/u/0/#all/big-thread)"
+	if !strings.Contains(content, omission) {
+		t.Errorf("missing omission note, content starts: %.400s", content)
+	}
+
+	if strings.Contains(content, "message body 100\n") || !strings.Contains(content, "message body 101\n") {
+		t.Error("expected only the 100 most recent messages")
+	}
+
+	if !strings.Contains(content, "## Item 200: ") || strings.Count(content, "## Item ") != 100 {
+		t.Errorf("expected items 101-200, got %d item headings", strings.Count(content, "## Item "))
+	}
+
+	meta := result[0].GetMetadata()
+	if meta["thread_truncated"] != true || meta["omitted_items"] != 100 {
+		t.Errorf("unexpected truncation metadata: %v / %v", meta["thread_truncated"], meta["omitted_items"])
+	}
+
+	if !strings.HasSuffix(result[0].GetTitle(), "_200-items") {
+		t.Errorf("title should still report the full thread length, got %q", result[0].GetTitle())
+	}
+}
+
+func TestThreadGroupingTransformer_ConsolidatedCapSwitchesToSummary(t *testing.T) {
+	transformer := NewThreadGroupingTransformer()
+	if err := transformer.Configure(map[string]interface{}{
+		"mode":                   "consolidated",
+		"max_consolidated_items": 50,
+		"consolidated_overflow":  "summary",
+		"max_thread_items":       4,
+	}); err != nil {
+		t.Fatalf("Failed to configure: %v", err)
+	}
+
+	result, err := transformer.Transform(append(longThread("big", 200), longThread("small", 10)...))
+	if err != nil {
+		t.Fatalf("Transform failed: %v", err)
+	}
+
+	if len(result) != 2 {
+		t.Fatalf("expected 2 items, got %d", len(result))
+	}
+
+	byID := make(map[string]models.FullItem)
+	for _, item := range result {
+		byID[item.GetID()] = item
+	}
+
+	summary, ok := byID["thread_summary_big"]
+	if !ok {
+		t.Fatalf("expected the 200-message thread as a summary, got IDs %v", byID)
+	}
+
+	if strings.Count(summary.GetContent(), "## Key Item ") != 4 {
+		t.Errorf("summary should show max_thread_items key items, got %d",
+			strings.Count(summary.GetContent(), "## Key Item "))
+	}
+
+	small, ok := byID["thread_small"]
+	if !ok || strings.Count(small.GetContent(), "## Item ") != 10 {
+		t.Error("thread under the cap should stay fully consolidated")
+	}
+}
+
+func TestThreadGroupingTransformer_ConsolidatedCapDisabled(t *testing.T) {
+	transformer := NewThreadGroupingTransformer()
+	err := transformer.Configure(map[string]interface{}{"mode": "consolidated", "max_consolidated_items": 0})
+	if err != nil {
+		t.Fatalf("Failed to configure: %v", err)
+	}
+
+	result, err := transformer.Transform(longThread("big", 200))
+	if err != nil {
+		t.Fatalf("Transform failed: %v", err)
+	}
+
+	content := result[0].GetContent()
+	if strings.Contains(content, "omitted") || strings.Count(content, "## Item ") != 200 {
+		t.Error("expected all 200 messages with no cap")
+	}
+}
+
+// burstThread has alice reply three times in a row between messages from bob.
+func burstThread() []models.FullItem {
+	start := time.Date(2026, 2, 3, 10, 0, 0, 0, time.UTC)
+	senders := []string{
+		"bob@example.com", "alice@example.com", "alice@example.com", "alice@example.com", "bob@example.com",
+	}
+	items := make([]models.FullItem, 0, len(senders))
+
+	for i, sender := range senders {
+		items = append(items, models.AsFullItem(&models.Item{
+			ID:         fmt.Sprintf("burst-%d", i+1),
+			Title:      "Re: Launch checklist",
+			Content:    fmt.Sprintf("burst body %d", i+1),
+			SourceType: "gmail",
+			CreatedAt:  start.Add(time.Duration(i) * 5 * time.Minute),
+			Metadata:   map[string]interface{}{"thread_id": "burst", "from": sender},
+		}))
+	}
+
+	return items
+}
+
+func TestThreadGroupingTransformer_CollapseConsecutiveSenders(t *testing.T) {
+	transformer := NewThreadGroupingTransformer()
+	err := transformer.Configure(map[string]interface{}{"mode": "consolidated", "collapse_consecutive_senders": true})
+	if err != nil {
+		t.Fatalf("Failed to configure: %v", err)
+	}
+
+	result, err := transformer.Transform(burstThread())
+	if err != nil {
+		t.Fatalf("Transform failed: %v", err)
+	}
+
+	content := result[0].GetContent()
+
+	if got := strings.Count(content, "## Item "); got != 3 {
+		t.Errorf("expected 3 sections (bob, alice x3, bob), got %d", got)
+	}
+
+	if got := strings.Count(content, "**From:** alice@example.com"); got != 1 {
+		t.Errorf("expected alice's header once, got %d", got)
+	}
+
+	for _, want := range []string{
+		"## Item 2: Re: Launch checklist",
+		"*Item 3 · 2026-02-03 10:10:00*\n\nburst body 3",
+		"*Item 4 · 2026-02-03 10:15:00*\n\nburst body 4",
+		"## Item 5: Re: Launch checklist",
+	} {
+		if !strings.Contains(content, want) {
+			t.Errorf("content missing %q", want)
+		}
+	}
+
+	alice := content[strings.Index(content, "## Item 2: "):strings.Index(content, "## Item 5: ")]
+	if strings.Count(alice, "---") != 1 {
+		t.Errorf("alice's messages should share one section, got:\n%s", alice)
+	}
+}
+
+func TestThreadGroupingTransformer_CollapseConsecutiveSendersDefaultOff(t *testing.T) {
+	transformer := NewThreadGroupingTransformer()
+	if err := transformer.Configure(map[string]interface{}{"mode": "consolidated"}); err != nil {
+		t.Fatalf("Failed to configure: %v", err)
+	}
+
+	result, err := transformer.Transform(burstThread())
+	if err != nil {
+		t.Fatalf("Transform failed: %v", err)
+	}
+
+	content := result[0].GetContent()
+	if strings.Count(content, "## Item ") != 5 || strings.Count(content, "**From:** alice@example.com") != 3 {
+		t.Error("without the option every message should keep its own section")
+	}
+}
+
+func TestThreadGroupingTransformer_ConsolidatedKeepsSourceName(t *testing.T) {
+	transformer := NewThreadGroupingTransformer()
+	if err := transformer.Configure(map[string]interface{}{"mode": "consolidated"}); err != nil {
+		t.Fatalf("Failed to configure: %v", err)
+	}
+
+	items := longThread("named", 3)
+	for _, item := range items {
+		item.GetMetadata()[models.MetadataSourceName] = "gmail_work"
+	}
+
+	result, err := transformer.Transform(items)
+	if err != nil {
+		t.Fatalf("Transform failed: %v", err)
+	}
+
+	if got := result[0].GetMetadata()[models.MetadataSourceName]; got != "gmail_work" {
+		t.Errorf("consolidated thread source_name = %v, want gmail_work", got)
+	}
+}
+
+// thanksThread is one substantive message followed by acknowledgements.
+func thanksThread(replies ...string) []models.FullItem {
+	start := time.Date(2026, 3, 9, 9, 0, 0, 0, time.UTC)
+	items := []models.FullItem{models.AsFullItem(&models.Item{
+		ID:         "thanks-0",
+		Title:      "Q1 roadmap",
+		Content:    "Here is the Q1 roadmap: search first, then the mobile app, then billing.",
+		SourceType: "gmail",
+		CreatedAt:  start,
+		Metadata:   map[string]interface{}{"thread_id": "thanks", "from": "lead@example.com"},
+	})}
+
+	senders := []string{"ann@example.com", "ben@example.com", "ann@example.com", "cy@example.com"}
+
+	for i, reply := range replies {
+		items = append(items, models.AsFullItem(&models.Item{
+			ID:         fmt.Sprintf("thanks-%d", i+1),
+			Title:      "Re: Q1 roadmap",
+			Content:    reply,
+			SourceType: "gmail",
+			CreatedAt:  start.Add(time.Duration(i+1) * time.Hour),
+			Metadata:   map[string]interface{}{"thread_id": "thanks", "from": senders[i%len(senders)]},
+		}))
+	}
+
+	return items
+}
+
+func TestThreadGroupingTransformer_CollapseTrivialReplies(t *testing.T) {
+	transformer := NewThreadGroupingTransformer()
+	err := transformer.Configure(map[string]interface{}{"mode": "consolidated", "collapse_trivial_replies": true})
+	if err != nil {
+		t.Fatalf("Failed to configure: %v", err)
+	}
+
+	result, err := transformer.Transform(thanksThread("Thanks!", "+1", "thanks, looks great", "Thank you!"))
+	if err != nil {
+		t.Fatalf("Transform failed: %v", err)
+	}
+
+	if len(result) != 1 {
+		t.Fatalf("expected 1 item, got %d", len(result))
+	}
+
+	content := result[0].GetContent()
+
+	if got := strings.Count(content, "## Item "); got != 1 {
+		t.Errorf("expected only the substantive message, got %d sections", got)
+	}
+
+	if !strings.Contains(content, "search first, then the mobile app") {
+		t.Error("expected the first message's content")
+	}
+
+	if strings.Contains(content, "looks great") {
+		t.Error("expected the replies to be collapsed")
+	}
+
+	note := "*4 short replies collapsed from ann@example.com, ben@example.com, cy@example.com.*"
+	if !strings.Contains(content, note) {
+		t.Errorf("missing reply-count note, got:\n%s", content)
+	}
+
+	if got := result[0].GetMetadata()["trivial_replies"]; got != 4 {
+		t.Errorf("trivial_replies = %v, want 4", got)
+	}
+
+	if !strings.HasSuffix(result[0].GetTitle(), "_5-items") {
+		t.Errorf("title should still report the full thread length, got %q", result[0].GetTitle())
+	}
+}
+
+func TestThreadGroupingTransformer_CollapseTrivialRepliesThreshold(t *testing.T) {
+	replies := []string{"Thanks!", "Agreed, but billing has to come before the mobile app this quarter."}
+
+	tests := []struct {
+		name     string
+		maxWords interface{}
+		sections int
+	}{
+		{"substantive reply keeps the thread", nil, 3},
+		{"higher threshold collapses it", 20, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := map[string]interface{}{"mode": "consolidated", "collapse_trivial_replies": true}
+			if tt.maxWords != nil {
+				config["trivial_reply_max_words"] = tt.maxWords
+			}
+
+			transformer := NewThreadGroupingTransformer()
+			if err := transformer.Configure(config); err != nil {
+				t.Fatalf("Failed to configure: %v", err)
+			}
+
+			result, err := transformer.Transform(thanksThread(replies...))
+			if err != nil {
+				t.Fatalf("Transform failed: %v", err)
+			}
+
+			if got := strings.Count(result[0].GetContent(), "## Item "); got != tt.sections {
+				t.Errorf("expected %d sections, got %d", tt.sections, got)
+			}
+		})
+	}
+}
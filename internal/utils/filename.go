@@ -1,117 +1,42 @@
 package utils
 
 import (
-	"path/filepath"
+	"crypto/sha256"
+	"encoding/hex"
 	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"pkm-sync/internal/naming"
+
+	"golang.org/x/text/unicode/norm"
 )
 
 const (
-	safeFilename    = "safe-filename"
-	defaultFilename = "default-filename"
+	safeFilename    = naming.FallbackName
+	defaultFilename = naming.EmptyName
 	emailThread     = "email-thread"
-)
-
-// SanitizeFilename sanitizes a string to be safe for use as a filename
-// This function prevents path traversal attacks and removes unsafe characters.
-func SanitizeFilename(filename string) string {
-	// Input validation
-	if filename == "" {
-		return defaultFilename
-	}
-
-	// Optimized string replacements using strings.Replacer for better performance
-	// Create replacer with all replacement patterns including security ones
-	replacer := strings.NewReplacer(
-		// Security: Remove path traversal sequences (order matters - longer patterns first)
-		"../", "",
-		"./", "",
-		"..", "",
-		"~", "",
-		// Control characters
-		"\n", "",
-		"\r", "",
-		"\t", "",
-		"\x00", "",
-		// Filename-friendly replacements
-		" ", "-",
-		"/", "-",
-		"\\", "-",
-		":", "-",
-		"*", "",
-		"?", "",
-		"\"", "",
-		"<", "",
-		">", "",
-		"|", "-",
-		"[", "",
-		"]", "",
-		"(", "",
-		")", "",
-		"@", "-at-",
-		"#", "-",
-		"!", "",
-		"&", "-and-",
-		".", "", // Remove dots to handle .hidden files
-	)
-
-	// Apply all replacements in one pass
-	cleaned := replacer.Replace(filename)
-
-	// Remove multiple consecutive hyphens using an efficient approach
-	var result strings.Builder
-
-	result.Grow(len(cleaned)) // Pre-allocate capacity
-
-	prevWasHyphen := false
-
-	for _, char := range cleaned {
-		if char == '-' {
-			// Skip additional consecutive hyphens
-			if !prevWasHyphen {
-				result.WriteRune(char)
-
-				prevWasHyphen = true
-			}
-		} else {
-			result.WriteRune(char)
-
-			prevWasHyphen = false
-		}
-	}
 
-	cleaned = result.String()
+	// MaxThreadSubjectLength bounds the readable part of SanitizeThreadSubject in
+	// bytes, so "Thread_<subject>-<hash>_<n>-items" stays within naming.MaxSlugLength.
+	MaxThreadSubjectLength = 48
 
-	// Remove leading/trailing hyphens and limit length
-	cleaned = strings.Trim(cleaned, "-")
-
-	// Limit length to avoid very long filenames
-	if len(cleaned) > 80 {
-		// Ensure we don't slice beyond string length
-		if len(cleaned) >= 80 {
-			cleaned = cleaned[:80]
-		}
-
-		cleaned = strings.Trim(cleaned, "-")
-	}
-
-	// Security: Use filepath.Clean to prevent path traversal and validate result
-	cleaned = filepath.Base(filepath.Clean(cleaned))
-
-	// Additional security validation: ensure it's a safe filename
-	if cleaned == "." || cleaned == ".." || strings.Contains(cleaned, string(filepath.Separator)) {
-		cleaned = safeFilename
-	}
-
-	// Final validation - ensure we have a valid filename
-	if cleaned == "" || cleaned == "-" {
-		cleaned = safeFilename
-	}
+	// subjectHashBytes is the hash prefix length; 3 bytes give 6 hex characters.
+	subjectHashBytes = 3
+)
 
-	return cleaned
+// SanitizeFilename sanitizes a string to be safe for use as a filename.
+// It delegates to naming.Slug, which also applies the cross-platform rules.
+func SanitizeFilename(filename string) string {
+	return naming.Slug(filename)
 }
 
-// SanitizeThreadSubject sanitizes a thread subject for use in filenames
-// with fallback handling for empty subjects and thread ID collision prevention.
+// SanitizeThreadSubject turns a thread subject into the subject part of a
+// thread note title. Unicode letters and digits are kept (NFC-normalized), any
+// other run of characters becomes a single hyphen, and the result is bounded
+// to MaxThreadSubjectLength bytes. A short hash of the subject is appended so
+// subjects that sanitize to the same text ("plan/budget", "plan budget") still
+// get distinct names. Empty subjects fall back to the thread ID.
 func SanitizeThreadSubject(subject, threadID string) string {
 	if subject == "" {
 		if threadID != "" {
@@ -127,14 +52,59 @@ func SanitizeThreadSubject(subject, threadID string) string {
 		cleaned = subject // Fallback to original if extraction fails
 	}
 
-	sanitized := SanitizeFilename(cleaned)
+	cleaned = norm.NFC.String(cleaned)
 
-	// If sanitization results in a generic name and we have a thread ID, append it
-	if (sanitized == safeFilename || sanitized == defaultFilename || sanitized == emailThread) && threadID != "" {
-		sanitized = sanitized + "-" + SanitizeFilename(threadID)
+	sanitized := subjectWords(cleaned)
+	if sanitized == "" {
+		// Nothing readable survived (e.g. only symbols or emoji).
+		sanitized = emailThread
+		if threadID != "" {
+			return sanitized + "-" + SanitizeFilename(threadID)
+		}
+	}
+
+	return sanitized + "-" + subjectHash(cleaned)
+}
+
+// subjectWords keeps letters, combining marks and digits from subject, joins
+// the remaining words with hyphens and truncates on a rune boundary.
+func subjectWords(subject string) string {
+	var b strings.Builder
+
+	pendingSep := false
+
+	for _, r := range subject {
+		if !unicode.IsLetter(r) && !unicode.IsMark(r) && !unicode.IsDigit(r) {
+			pendingSep = b.Len() > 0
+
+			continue
+		}
+
+		if pendingSep {
+			if b.Len()+1+utf8.RuneLen(r) > MaxThreadSubjectLength {
+				break
+			}
+
+			b.WriteByte('-')
+
+			pendingSep = false
+		}
+
+		if b.Len()+utf8.RuneLen(r) > MaxThreadSubjectLength {
+			break
+		}
+
+		b.WriteRune(r)
 	}
 
-	return sanitized
+	return b.String()
+}
+
+// subjectHash returns a short, stable fingerprint of a cleaned subject.
+func subjectHash(subject string) string {
+	sum := sha256.Sum256([]byte(subject))
+
+	return hex.EncodeToString(sum[:subjectHashBytes])
 }
 
 // cleanEmailSubject removes common email prefixes like Re:, Fwd:, etc.
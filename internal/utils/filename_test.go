@@ -3,6 +3,11 @@ package utils
 import (
 	"strings"
 	"testing"
+	"unicode/utf8"
+
+	"pkm-sync/internal/naming"
+
+	"golang.org/x/text/unicode/norm"
 )
 
 func TestSanitizeFilename_Security(t *testing.T) {
@@ -188,7 +193,7 @@ func TestSanitizeThreadSubject_WithThreadID(t *testing.T) {
 			name:     "normal subject with thread ID",
 			subject:  "Important Meeting",
 			threadID: "abc123",
-			expected: "Important-Meeting",
+			expected: "Important-Meeting-" + subjectHash("Important Meeting"),
 		},
 		{
 			name:     "empty subject with thread ID",
@@ -200,13 +205,13 @@ func TestSanitizeThreadSubject_WithThreadID(t *testing.T) {
 			name:     "subject with Re: prefix",
 			subject:  "Re: Follow up",
 			threadID: "def456",
-			expected: "Follow-up",
+			expected: "Follow-up-" + subjectHash("Follow up"),
 		},
 		{
-			name:     "subject becomes unsafe after sanitization",
+			name:     "subject with no letters falls back to thread ID",
 			subject:  "!!!@@@###",
 			threadID: "ghi789",
-			expected: "at-at-at",
+			expected: "email-thread-ghi789",
 		},
 		{
 			name:     "empty subject, empty thread ID",
@@ -218,7 +223,7 @@ func TestSanitizeThreadSubject_WithThreadID(t *testing.T) {
 			name:     "thread ID with unsafe characters",
 			subject:  "Test",
 			threadID: "thread/../123",
-			expected: "Test",
+			expected: "Test-" + subjectHash("Test"),
 		},
 	}
 
@@ -233,6 +238,72 @@ func TestSanitizeThreadSubject_WithThreadID(t *testing.T) {
 	}
 }
 
+func TestSanitizeThreadSubject_Unicode(t *testing.T) {
+	tests := []struct {
+		name    string
+		subject string
+		words   string
+	}{
+		{"CJK", "会议安排：下周一", "会议安排-下周一"},
+		{"accents in NFD form", "Résumé review", "Résumé-review"},
+		{"emoji dropped", "🚀 Launch 🎉 party!", "Launch-party"},
+		{"slashes and separators collapse", "Q3 plan / budget -- draft", "Q3-plan-budget-draft"},
+		{"reply prefix ignored", "Re: FW: Café", "Café"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := SanitizeThreadSubject(tt.subject, "t1")
+
+			want := tt.words + "-" + subjectHash(norm.NFC.String(cleanEmailSubject(tt.subject)))
+			if got != want {
+				t.Errorf("SanitizeThreadSubject(%q) = %q, want %q", tt.subject, got, want)
+			}
+
+			if naming.Slug(got) != got {
+				t.Errorf("result %q is not stable under naming.Slug (%q)", got, naming.Slug(got))
+			}
+		})
+	}
+}
+
+func TestSanitizeThreadSubject_DistinctSubjectsDoNotCollide(t *testing.T) {
+	// Both of these used to sanitize to "Q3-plan-budget".
+	a := SanitizeThreadSubject("Q3 plan/budget", "t1")
+	b := SanitizeThreadSubject("Q3 plan budget?", "t2")
+
+	if a == b {
+		t.Fatalf("different subjects collided: %q", a)
+	}
+
+	if !strings.HasPrefix(a, "Q3-plan-budget-") || !strings.HasPrefix(b, "Q3-plan-budget-") {
+		t.Errorf("expected readable prefix, got %q and %q", a, b)
+	}
+
+	// The same subject, with or without reply prefixes, keeps one name.
+	if c := SanitizeThreadSubject("Re: Q3 plan/budget", "t3"); c != a {
+		t.Errorf("reply changed the name: %q vs %q", c, a)
+	}
+}
+
+func TestSanitizeThreadSubject_BoundsLength(t *testing.T) {
+	subject := strings.Repeat("長い件名 ", 40)
+	got := SanitizeThreadSubject(subject, "t1")
+
+	words, hash, _ := strings.Cut(got, "-"+subjectHash(norm.NFC.String(strings.TrimSpace(subject))))
+	if hash != "" || words == got {
+		t.Fatalf("expected hash suffix, got %q", got)
+	}
+
+	if len(words) > MaxThreadSubjectLength {
+		t.Errorf("subject part is %d bytes, want at most %d", len(words), MaxThreadSubjectLength)
+	}
+
+	if !utf8.ValidString(got) || strings.HasSuffix(words, "-") {
+		t.Errorf("truncation produced %q", got)
+	}
+}
+
 func TestCleanEmailSubject(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -6,7 +6,9 @@ import (
 	"encoding/binary"
 	"encoding/json"
 	"fmt"
+	"math"
 	"os"
+	"sort"
 	"time"
 
 	sqlite_vec "github.com/asg017/sqlite-vec-go-bindings/cgo"
@@ -27,21 +29,36 @@ type Document struct {
 	CreatedAt    time.Time
 	UpdatedAt    time.Time
 	IndexedAt    time.Time
+	// ContentHash identifies the thread content that was embedded, so a thread
+	// that gained messages is re-indexed. Written by UpsertDocument only.
+	ContentHash string
+}
+
+// IndexedThread is what the store remembers about an indexed thread to decide
+// whether it needs re-indexing.
+type IndexedThread struct {
+	ContentHash  string // empty for rows indexed before content hashes existed
+	MessageCount int
 }
 
 // SearchResult represents a search result with similarity score.
 type SearchResult struct {
 	Document
 
+	// Distance is the vec0 (L2) distance used to find the neighbor.
 	Distance float64
-	Score    float64
+	// Score is the cosine similarity between the query and the document,
+	// from -1 to 1; higher is more similar.
+	Score float64
 }
 
 // SearchFilters defines optional filters for search queries.
 type SearchFilters struct {
 	SourceType string
 	SourceName string
-	MinScore   float64
+	// MinScore drops results whose cosine similarity is below it; 0 keeps
+	// every neighbor.
+	MinScore float64
 }
 
 // StoreStats contains statistics about the vector store.
@@ -132,6 +149,7 @@ func (s *Store) createSchema() error {
 			created_at    DATETIME NOT NULL,
 			updated_at    DATETIME NOT NULL,
 			indexed_at    DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+			content_hash  TEXT NOT NULL DEFAULT '',
 			UNIQUE(thread_id, source_name)
 		);
 
@@ -144,6 +162,10 @@ func (s *Store) createSchema() error {
 		return err
 	}
 
+	if err := s.addContentHashColumn(); err != nil {
+		return err
+	}
+
 	if s.dimensions > 0 {
 		vecSchema := fmt.Sprintf(`
 			CREATE VIRTUAL TABLE IF NOT EXISTS vec_documents USING vec0(
@@ -160,6 +182,21 @@ func (s *Store) createSchema() error {
 	return nil
 }
 
+// addContentHashColumn adds documents.content_hash to databases created before
+// it was part of the schema.
+func (s *Store) addContentHashColumn() error {
+	var count int
+
+	err := s.db.QueryRow("SELECT COUNT(*) FROM pragma_table_info('documents') WHERE name = 'content_hash'").Scan(&count)
+	if err != nil || count > 0 {
+		return err
+	}
+
+	_, err = s.db.Exec("ALTER TABLE documents ADD COLUMN content_hash TEXT NOT NULL DEFAULT ''")
+
+	return err
+}
+
 // UpsertDocument inserts or updates a document and, when a non-nil embedding
 // is provided, stores it in vec_documents for semantic search. Passing nil (or
 // an empty slice) writes the document metadata only — useful when no embedding
@@ -190,9 +227,9 @@ func (s *Store) UpsertDocument(doc Document, embedding []float32) error {
 	result, err := tx.Exec(`
 		INSERT INTO documents (
 			source_id, thread_id, title, content, source_type, source_name,
-			message_count, metadata, created_at, updated_at, indexed_at
+			message_count, metadata, created_at, updated_at, indexed_at, content_hash
 		)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, CURRENT_TIMESTAMP)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, CURRENT_TIMESTAMP, ?)
 		ON CONFLICT(thread_id, source_name) DO UPDATE SET
 			source_id = excluded.source_id,
 			title = excluded.title,
@@ -202,10 +239,11 @@ func (s *Store) UpsertDocument(doc Document, embedding []float32) error {
 			metadata = excluded.metadata,
 			created_at = excluded.created_at,
 			updated_at = excluded.updated_at,
-			indexed_at = CURRENT_TIMESTAMP
+			indexed_at = CURRENT_TIMESTAMP,
+			content_hash = excluded.content_hash
 	`,
 		doc.SourceID, doc.ThreadID, doc.Title, doc.Content, doc.SourceType, doc.SourceName,
-		doc.MessageCount, metadataJSON, createdAtStr, updatedAtStr,
+		doc.MessageCount, metadataJSON, createdAtStr, updatedAtStr, doc.ContentHash,
 	)
 	if err != nil {
 		return fmt.Errorf("failed to upsert document: %w", err)
@@ -244,7 +282,8 @@ func (s *Store) UpsertDocument(doc Document, embedding []float32) error {
 	return tx.Commit()
 }
 
-// Search performs a KNN search for similar documents.
+// Search returns the limit nearest documents to queryEmbedding, ranked by
+// cosine similarity, without those scoring below filters.MinScore.
 func (s *Store) Search(queryEmbedding []float32, limit int, filters SearchFilters) ([]SearchResult, error) {
 	if len(queryEmbedding) != s.dimensions {
 		return nil, fmt.Errorf("query embedding dimensions mismatch: expected %d, got %d", s.dimensions, len(queryEmbedding))
@@ -262,7 +301,7 @@ func (s *Store) Search(queryEmbedding []float32, limit int, filters SearchFilter
 		SELECT
 			d.id, d.source_id, d.thread_id, d.title, d.content, d.source_type, d.source_name,
 			d.message_count, d.metadata, d.created_at, d.updated_at, d.indexed_at,
-			v.distance
+			v.distance, v.embedding
 		FROM vec_documents v
 		JOIN documents d ON v.document_id = d.id
 		WHERE v.embedding MATCH ? AND k = ?
@@ -297,12 +336,13 @@ func (s *Store) Search(queryEmbedding []float32, limit int, filters SearchFilter
 			result                          SearchResult
 			metadataJSON                    string
 			createdAt, updatedAt, indexedAt string
+			embeddingBlob                   []byte
 		)
 
 		err := rows.Scan(
 			&result.ID, &result.SourceID, &result.ThreadID, &result.Title, &result.Content,
 			&result.SourceType, &result.SourceName, &result.MessageCount, &metadataJSON,
-			&createdAt, &updatedAt, &indexedAt, &result.Distance,
+			&createdAt, &updatedAt, &indexedAt, &result.Distance, &embeddingBlob,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan result: %w", err)
@@ -318,8 +358,7 @@ func (s *Store) Search(queryEmbedding []float32, limit int, filters SearchFilter
 		result.UpdatedAt, _ = time.Parse(time.RFC3339, updatedAt)
 		result.IndexedAt, _ = time.Parse(time.RFC3339, indexedAt)
 
-		// Calculate score (1 / (1 + distance))
-		result.Score = 1.0 / (1.0 + result.Distance)
+		result.Score = cosineSimilarity(queryEmbedding, bytesToFloat32Slice(embeddingBlob))
 
 		// Apply score filter
 		if filters.MinScore > 0 && result.Score < filters.MinScore {
@@ -329,7 +368,37 @@ func (s *Store) Search(queryEmbedding []float32, limit int, filters SearchFilter
 		results = append(results, result)
 	}
 
-	return results, rows.Err()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	// vec0 ranks by L2 distance, which only matches the cosine order for
+	// normalized embeddings.
+	sort.SliceStable(results, func(i, j int) bool { return results[i].Score > results[j].Score })
+
+	return results, nil
+}
+
+// cosineSimilarity returns the cosine of the angle between a and b, or 0 when
+// either is a zero vector or their lengths differ.
+func cosineSimilarity(a, b []float32) float64 {
+	if len(a) != len(b) {
+		return 0
+	}
+
+	var dot, normA, normB float64
+
+	for i := range a {
+		dot += float64(a[i]) * float64(b[i])
+		normA += float64(a[i]) * float64(a[i])
+		normB += float64(b[i]) * float64(b[i])
+	}
+
+	if normA == 0 || normB == 0 {
+		return 0
+	}
+
+	return dot / (math.Sqrt(normA) * math.Sqrt(normB))
 }
 
 // IsIndexed checks if a thread is already indexed.
@@ -368,6 +437,92 @@ func (s *Store) GetIndexedThreadIDs(sourceName string) (map[string]bool, error)
 	return indexed, rows.Err()
 }
 
+// GetIndexedThreads returns the content hash and message count of every
+// thread indexed for a source, keyed by thread ID.
+func (s *Store) GetIndexedThreads(sourceName string) (map[string]IndexedThread, error) {
+	rows, err := s.db.Query(
+		"SELECT thread_id, content_hash, message_count FROM documents WHERE source_name = ?", sourceName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query indexed threads: %w", err)
+	}
+	defer rows.Close()
+
+	indexed := make(map[string]IndexedThread)
+
+	for rows.Next() {
+		var (
+			threadID string
+			thread   IndexedThread
+		)
+
+		if err := rows.Scan(&threadID, &thread.ContentHash, &thread.MessageCount); err != nil {
+			return nil, fmt.Errorf("failed to scan indexed thread: %w", err)
+		}
+
+		indexed[threadID] = thread
+	}
+
+	return indexed, rows.Err()
+}
+
+// GetDocuments returns the documents whose thread or source ID is id,
+// optionally limited to one source (empty sourceName matches any).
+func (s *Store) GetDocuments(id, sourceName string) ([]Document, error) {
+	query := `
+		SELECT id, source_id, thread_id, title, content, source_type, source_name,
+			message_count, metadata, created_at, updated_at, indexed_at
+		FROM documents
+		WHERE (thread_id = ? OR source_id = ?)
+	`
+
+	args := []interface{}{id, id}
+
+	if sourceName != "" {
+		query += " AND source_name = ?"
+
+		args = append(args, sourceName)
+	}
+
+	query += " ORDER BY source_name"
+
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query documents: %w", err)
+	}
+	defer rows.Close()
+
+	var docs []Document
+
+	for rows.Next() {
+		var (
+			doc                             Document
+			metadataJSON                    string
+			createdAt, updatedAt, indexedAt string
+		)
+
+		err := rows.Scan(
+			&doc.ID, &doc.SourceID, &doc.ThreadID, &doc.Title, &doc.Content,
+			&doc.SourceType, &doc.SourceName, &doc.MessageCount, &metadataJSON,
+			&createdAt, &updatedAt, &indexedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan document: %w", err)
+		}
+
+		if err := json.Unmarshal([]byte(metadataJSON), &doc.Metadata); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal metadata: %w", err)
+		}
+
+		doc.CreatedAt, _ = time.Parse(time.RFC3339, createdAt)
+		doc.UpdatedAt, _ = time.Parse(time.RFC3339, updatedAt)
+		doc.IndexedAt, _ = time.Parse(time.RFC3339, indexedAt)
+
+		docs = append(docs, doc)
+	}
+
+	return docs, rows.Err()
+}
+
 // NewestDocumentTimeBySource returns the most recent updated_at timestamp for
 // documents from the given source, or a zero Time if none exist yet.
 func (s *Store) NewestDocumentTimeBySource(sourceName string) (time.Time, error) {
@@ -478,11 +633,50 @@ func (s *Store) Stats() (*StoreStats, error) {
 	return stats, nil
 }
 
+// Backup writes a consistent, compacted copy of the store to path using
+// VACUUM INTO. path must not exist yet.
+func (s *Store) Backup(path string) error {
+	if _, err := s.db.Exec("VACUUM INTO ?", path); err != nil {
+		return fmt.Errorf("failed to back up vector database to %s: %w", path, err)
+	}
+
+	return nil
+}
+
+// Compact checkpoints the WAL and rebuilds the database file with VACUUM,
+// reclaiming the pages left free by deleted or replaced documents.
+func (s *Store) Compact() error {
+	if _, err := s.db.Exec("PRAGMA wal_checkpoint(TRUNCATE)"); err != nil {
+		return fmt.Errorf("failed to checkpoint WAL: %w", err)
+	}
+
+	if _, err := s.db.Exec("VACUUM"); err != nil {
+		return fmt.Errorf("failed to vacuum vector database: %w", err)
+	}
+
+	// VACUUM in WAL mode writes the rebuilt pages through the WAL.
+	if _, err := s.db.Exec("PRAGMA wal_checkpoint(TRUNCATE)"); err != nil {
+		return fmt.Errorf("failed to checkpoint WAL: %w", err)
+	}
+
+	return nil
+}
+
 // Close closes the database connection.
 func (s *Store) Close() error {
 	return s.db.Close()
 }
 
+// bytesToFloat32Slice decodes an embedding stored by float32SliceToBytes.
+func bytesToFloat32Slice(data []byte) []float32 {
+	values := make([]float32, len(data)/4)
+	for i := range values {
+		values[i] = math.Float32frombits(binary.LittleEndian.Uint32(data[i*4:]))
+	}
+
+	return values
+}
+
 // float32SliceToBytes converts a []float32 to a byte slice in binary format.
 func float32SliceToBytes(data []float32) ([]byte, error) {
 	buf := new(bytes.Buffer)
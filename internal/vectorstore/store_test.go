@@ -1,6 +1,12 @@
 package vectorstore
 
 import (
+	"database/sql"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 )
@@ -371,6 +377,51 @@ func TestStore_GetIndexedThreadIDs(t *testing.T) {
 	}
 }
 
+func TestStore_GetDocuments(t *testing.T) {
+	store, err := NewStore(":memory:", 3)
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+	defer store.Close()
+
+	now := time.Now().UTC().Truncate(time.Second)
+
+	for _, doc := range []Document{
+		{SourceID: "msg1", ThreadID: "thread1", Title: "Work copy", SourceName: "gmail_work"},
+		{SourceID: "msg1", ThreadID: "thread1", Title: "Personal copy", SourceName: "gmail_personal"},
+		{SourceID: "msg2", ThreadID: "thread2", Title: "Other", SourceName: "gmail_work"},
+	} {
+		doc.SourceType = "gmail"
+		doc.Metadata = map[string]interface{}{"from": "alice@example.com"}
+		doc.CreatedAt, doc.UpdatedAt = now, now
+
+		if err := store.UpsertDocument(doc, nil); err != nil {
+			t.Fatalf("failed to insert document: %v", err)
+		}
+	}
+
+	docs, err := store.GetDocuments("thread1", "")
+	if err != nil {
+		t.Fatalf("GetDocuments: %v", err)
+	}
+
+	if len(docs) != 2 || docs[0].SourceName != "gmail_personal" || docs[1].SourceName != "gmail_work" {
+		t.Fatalf("expected thread1 from both sources ordered by name, got %+v", docs)
+	}
+
+	if docs[1].Metadata["from"] != "alice@example.com" || !docs[1].CreatedAt.Equal(now) {
+		t.Errorf("document fields not decoded: %+v", docs[1])
+	}
+
+	if docs, _ := store.GetDocuments("msg2", "gmail_work"); len(docs) != 1 || docs[0].ThreadID != "thread2" {
+		t.Errorf("lookup by source ID within a source: got %+v", docs)
+	}
+
+	if docs, _ := store.GetDocuments("thread1", "slack"); len(docs) != 0 {
+		t.Errorf("expected no documents for another source, got %+v", docs)
+	}
+}
+
 func TestStore_Stats(t *testing.T) {
 	store, err := NewStore(":memory:", 3)
 	if err != nil {
@@ -547,3 +598,203 @@ func TestStore_NewestDocumentTimeBySource(t *testing.T) {
 		t.Errorf("expected %v for slack_redhat, got %v", newer, ts)
 	}
 }
+
+// fileSize returns the size of a SQLite database including its WAL file.
+func fileSize(t *testing.T, path string) int64 {
+	t.Helper()
+
+	var total int64
+
+	for _, p := range []string{path, path + "-wal"} {
+		if info, err := os.Stat(p); err == nil {
+			total += info.Size()
+		}
+	}
+
+	return total
+}
+
+func TestStore_CompactPreservesDocumentsAndShrinksFile(t *testing.T) {
+	dir := t.TempDir()
+	dbPath := filepath.Join(dir, "vectors.db")
+
+	store, err := NewStore(dbPath, 3)
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+	defer store.Close()
+
+	for i := range 200 {
+		doc := Document{
+			SourceID:   fmt.Sprintf("msg%d", i),
+			ThreadID:   fmt.Sprintf("thread%d", i),
+			Title:      "Document",
+			Content:    strings.Repeat("padding ", 500),
+			SourceType: "gmail",
+			SourceName: "gmail_work",
+			CreatedAt:  time.Now(),
+			UpdatedAt:  time.Now(),
+		}
+
+		if err := store.UpsertDocument(doc, []float32{float32(i), 0.5, 0.25}); err != nil {
+			t.Fatalf("failed to upsert document: %v", err)
+		}
+	}
+
+	// Simulate retention removing most of the documents.
+	if _, err := store.db.Exec("DELETE FROM vec_documents WHERE document_id > 20"); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := store.db.Exec("DELETE FROM documents WHERE id > 20"); err != nil {
+		t.Fatal(err)
+	}
+
+	before := fileSize(t, dbPath)
+
+	backupPath := filepath.Join(dir, "vectors-backup.db")
+	if err := store.Backup(backupPath); err != nil {
+		t.Fatalf("Backup failed: %v", err)
+	}
+
+	if err := store.Compact(); err != nil {
+		t.Fatalf("Compact failed: %v", err)
+	}
+
+	if after := fileSize(t, dbPath); after >= before {
+		t.Errorf("expected compaction to shrink the database, got %d -> %d bytes", before, after)
+	}
+
+	for _, path := range []string{dbPath, backupPath} {
+		check, err := NewStore(path, 3)
+		if err != nil {
+			t.Fatalf("failed to open %s: %v", path, err)
+		}
+
+		stats, err := check.Stats()
+		if err != nil {
+			t.Fatalf("Stats failed: %v", err)
+		}
+
+		results, err := check.Search([]float32{20, 0.5, 0.25}, 1, SearchFilters{})
+		if err != nil {
+			t.Fatalf("Search failed: %v", err)
+		}
+
+		check.Close()
+
+		if stats.TotalDocuments != 20 {
+			t.Errorf("%s: expected 20 documents, got %d", filepath.Base(path), stats.TotalDocuments)
+		}
+
+		if len(results) != 1 || results[0].ThreadID != "thread19" {
+			t.Errorf("%s: expected embeddings to survive, got %v", filepath.Base(path), results)
+		}
+	}
+}
+
+func TestStore_AddsContentHashToExistingDatabase(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "vectors.db")
+
+	db, err := sql.Open("sqlite3", dbPath)
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+
+	_, err = db.Exec(`
+		CREATE TABLE documents (
+			id            INTEGER PRIMARY KEY AUTOINCREMENT,
+			source_id     TEXT NOT NULL,
+			thread_id     TEXT NOT NULL DEFAULT '',
+			title         TEXT NOT NULL DEFAULT '',
+			content       TEXT NOT NULL DEFAULT '',
+			source_type   TEXT NOT NULL DEFAULT '',
+			source_name   TEXT NOT NULL DEFAULT '',
+			message_count INTEGER NOT NULL DEFAULT 1,
+			metadata      TEXT NOT NULL DEFAULT '{}',
+			created_at    DATETIME NOT NULL,
+			updated_at    DATETIME NOT NULL,
+			indexed_at    DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+			UNIQUE(thread_id, source_name)
+		);
+		INSERT INTO documents (source_id, thread_id, source_name, message_count, created_at, updated_at)
+		VALUES ('msg1', 'thread1', 'gmail_work', 2, '2024-01-01T00:00:00Z', '2024-01-01T00:00:00Z');
+	`)
+	if err != nil {
+		t.Fatalf("failed to create legacy schema: %v", err)
+	}
+
+	db.Close()
+
+	store, err := NewStore(dbPath, 0)
+	if err != nil {
+		t.Fatalf("failed to open legacy store: %v", err)
+	}
+	defer store.Close()
+
+	indexed, err := store.GetIndexedThreads("gmail_work")
+	if err != nil {
+		t.Fatalf("GetIndexedThreads failed: %v", err)
+	}
+
+	if got := indexed["thread1"]; got.ContentHash != "" || got.MessageCount != 2 {
+		t.Errorf("expected legacy row with no hash and 2 messages, got %+v", got)
+	}
+}
+
+func TestStore_Search_CosineScoreAndMinScore(t *testing.T) {
+	store, err := NewStore(":memory:", 2)
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+	defer store.Close()
+
+	// Same direction as the query but far away (cosine 1), a near neighbor
+	// at 45 degrees (cosine ~0.71), and an orthogonal one (cosine 0).
+	embeddings := map[string][]float32{
+		"same-direction": {10, 0},
+		"diagonal":       {0.7, 0.7},
+		"orthogonal":     {0, 1},
+	}
+
+	for threadID, embedding := range embeddings {
+		doc := Document{
+			SourceID:   threadID,
+			ThreadID:   threadID,
+			Title:      threadID,
+			SourceType: "gmail",
+			SourceName: "gmail_work",
+			Metadata:   map[string]interface{}{},
+			CreatedAt:  time.Now(),
+			UpdatedAt:  time.Now(),
+		}
+
+		if err := store.UpsertDocument(doc, embedding); err != nil {
+			t.Fatalf("failed to insert document: %v", err)
+		}
+	}
+
+	query := []float32{1, 0}
+
+	results, err := store.Search(query, 10, SearchFilters{})
+	if err != nil {
+		t.Fatalf("failed to search: %v", err)
+	}
+
+	if len(results) != 3 || results[0].ThreadID != "same-direction" || results[2].ThreadID != "orthogonal" {
+		t.Fatalf("expected results ranked by cosine similarity, got %v", results)
+	}
+
+	if math.Abs(results[0].Score-1) > 1e-6 || math.Abs(results[1].Score-math.Sqrt2/2) > 1e-6 {
+		t.Errorf("unexpected scores %f and %f", results[0].Score, results[1].Score)
+	}
+
+	results, err = store.Search(query, 10, SearchFilters{MinScore: 0.5})
+	if err != nil {
+		t.Fatalf("failed to search: %v", err)
+	}
+
+	if len(results) != 2 {
+		t.Errorf("expected min_score 0.5 to drop the orthogonal document, got %d results", len(results))
+	}
+}
@@ -0,0 +1,14 @@
+package interfaces
+
+// AuthChecker verifies a configured source's credentials with one lightweight
+// API call (the user's profile, the Drive root) that fetches no items.
+// Sources implement it alongside Source when such a call exists.
+//
+// The command layer discovers this capability via a runtime type assertion:
+//
+//	if c, ok := src.(interfaces.AuthChecker); ok { ... }
+type AuthChecker interface {
+	// CheckAuth returns a short description of the authenticated account, or
+	// the error the API returned.
+	CheckAuth() (string, error)
+}
@@ -15,13 +15,59 @@ type Source interface {
 	Configure(config map[string]interface{}, client *http.Client) error
 	Fetch(since time.Time, limit int) ([]models.FullItem, error)
 	SupportsRealtime() bool
+	// Capabilities describes what the source can do, so commands and the sync
+	// engine can decide without switching on source types.
+	Capabilities() SourceCapabilities
+}
+
+// SourceCapabilities is the feature set a source declares.
+type SourceCapabilities struct {
+	// SupportsRealtime matches Source.SupportsRealtime: changes are pushed
+	// rather than only polled.
+	SupportsRealtime bool
+	// ProvidesRawMessages means the source implements RawMessageSource, so
+	// original messages can be archived.
+	ProvidesRawMessages bool
+	// SupportsIncremental means Fetch honors since, so a rerun fetches only
+	// items changed after it.
+	SupportsIncremental bool
+	// ProducesThreads means items may be threads or carry thread_id metadata
+	// for the thread_grouping transformer.
+	ProducesThreads bool
+}
+
+// RawMessageFetcher retrieves a message's original RFC 5322 bytes.
+type RawMessageFetcher interface {
+	GetMessageRaw(messageID string) ([]byte, error)
+}
+
+// RawMessageSource is implemented by sources that declare
+// ProvidesRawMessages. RawMessageFetcher returns nil until the source is
+// configured.
+type RawMessageSource interface {
+	RawMessageFetcher() RawMessageFetcher
+}
+
+// ContextSource is implemented by sources whose Fetch can be aborted through a
+// context (e.g. on Ctrl-C). MultiSyncer discovers this capability via a runtime
+// type assertion and falls back to Fetch for sources that do not implement it.
+type ContextSource interface {
+	FetchContext(ctx context.Context, since time.Time, limit int) ([]models.FullItem, error)
+}
+
+// UntilSource is implemented by sources whose Fetch can stop at an end time,
+// so a historical window can be backfilled. MultiSyncer calls SetUntil before
+// Fetch when the entry has an end time; a zero time fetches up to now.
+type UntilSource interface {
+	SetUntil(until time.Time)
 }
 
 // FilePreview represents what would happen to a file during sync.
 type FilePreview struct {
 	FilePath        string // Full path where file would be created
-	Action          string // "create", "update", "skip"
+	Action          string // "create", "update", "skip", "append"
 	Content         string // Full content that would be written
+	ContentPreview  string // First lines of Content when the sink was asked for them
 	ExistingContent string // Current content if file exists
 	Conflict        bool   // True if there would be a conflict
 }
@@ -41,6 +87,14 @@ type Transformer interface {
 	Configure(config map[string]interface{}) error
 }
 
+// OrderedTransformer is implemented by transformers that must run after others.
+// When pipeline_order is left empty the pipeline discovers this capability via a
+// runtime type assertion and sorts the configured transformers to satisfy it.
+// Names that are not part of the pipeline are ignored.
+type OrderedTransformer interface {
+	RunsAfter() []string
+}
+
 // ContentTransformer represents a transformer that only needs to access and modify core content.
 // Useful for transformers that only need basic item properties.
 type ContentTransformer interface {
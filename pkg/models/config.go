@@ -72,15 +72,74 @@ type SyncConfig struct {
 	OnConflict    string `json:"on_conflict"    yaml:"on_conflict"`    // "skip", "overwrite", "prompt"
 	DeduplicateBy string `json:"deduplicate_by" yaml:"deduplicate_by"` // "id", "title", "content", "none"
 
+	// Source tag namespacing: tags are <prefix><separator><source name>. Empty
+	// prefix uses the Obsidian target's tag_prefix + "source", else "source";
+	// empty separator is "/" when the prefix nests with "/", else ":".
+	SourceTagPrefix    string `json:"source_tag_prefix,omitempty"    yaml:"source_tag_prefix,omitempty"`
+	SourceTagSeparator string `json:"source_tag_separator,omitempty" yaml:"source_tag_separator,omitempty"`
+
 	// File management
 	CreateSubdirs   bool   `json:"create_subdirs"    yaml:"create_subdirs"`
 	SubdirFormat    string `json:"subdir_format"     yaml:"subdir_format"` // "yyyy/mm", "yyyy-mm", "source", "flat"
 	MaxFileAge      string `json:"max_file_age"      yaml:"max_file_age"`  // "30d", "6m", "1y"
 	ArchiveOldFiles bool   `json:"archive_old_files" yaml:"archive_old_files"`
 
+	// Items a target fails to write are appended here as JSONL for `reprocess`.
+	// Empty means <config dir>/dead-letter.jsonl.
+	DeadLetterPath string `json:"dead_letter_path" yaml:"dead_letter_path"`
+
+	// When set, a JSON summary of each sync run (per-source counts, sink writes,
+	// errors, duration) is written here. `sync --summary` overrides it.
+	SummaryPath string `json:"summary_path" yaml:"summary_path"`
+
+	// Maximum number of sources in one sync group fetched at once; 0 fetches
+	// them all at the same time.
+	FetchConcurrency int `json:"fetch_concurrency,omitempty" yaml:"fetch_concurrency,omitempty"`
+
 	// Cross-source reference resolution
 	ResolveReferences bool `json:"resolve_references" yaml:"resolve_references"` // global default
 	ResolveDepth      int  `json:"resolve_depth"      yaml:"resolve_depth"`      // max depth (0 defaults to 1)
+
+	// Reference attachment files already stored by an earlier run (any source)
+	// instead of writing duplicates; indexed by content hash in
+	// <config dir>/attachments.db. `sync --dedupe-attachments-global` enables it.
+	DedupeAttachmentsGlobal bool `json:"dedupe_attachments_global" yaml:"dedupe_attachments_global"`
+
+	// Write stub notes (title, metadata, tags, links) without the content
+	// body, e.g. for a calendar index. `sync --exclude-body` enables it for
+	// one run and `sync --include-body` disables it.
+	OmitBody bool `json:"omit_body" yaml:"omit_body"`
+
+	// Daily inbox review note listing everything synced that day
+	Digest DigestConfig `json:"digest" yaml:"digest"`
+
+	// Combined .ics file of synced calendar events
+	ICSExport ICSExportConfig `json:"ics_export" yaml:"ics_export"`
+}
+
+// DigestConfig controls the consolidated "inbox review" note: one dated note per
+// day listing every item synced that day with a checkbox and a link to its note.
+type DigestConfig struct {
+	Enabled bool `json:"enabled" yaml:"enabled"`
+	// Folder under the output directory for review notes (default: "Reviews")
+	Folder string `json:"folder" yaml:"folder"`
+	// GroupBy is "source" (default), "type", or "none"
+	GroupBy string `json:"group_by" yaml:"group_by"`
+	// Sections to render, in order: "stats", "items" (default: both)
+	Sections []string `json:"sections,omitempty" yaml:"sections,omitempty"`
+	// TopSenders is how many senders the stats list (default: 5; negative hides them)
+	TopSenders int `json:"top_senders" yaml:"top_senders"`
+}
+
+// ICSExportConfig controls the iCalendar export of synced calendar events, so
+// the filtered and tagged subset can be imported into another calendar app.
+type ICSExportConfig struct {
+	Enabled bool `json:"enabled" yaml:"enabled"`
+	// Path of the .ics file; relative paths are under the output directory
+	// (default: "calendar.ics")
+	Path string `json:"path" yaml:"path"`
+	// CalendarName is written as X-WR-CALNAME (default: "pkm-sync")
+	CalendarName string `json:"calendar_name" yaml:"calendar_name"`
 }
 
 type SourceConfig struct {
@@ -95,9 +154,18 @@ type SourceConfig struct {
 	SyncInterval time.Duration `json:"sync_interval,omitempty" yaml:"sync_interval,omitempty"`
 	Since        string        `json:"since,omitempty"         yaml:"since,omitempty"`
 	Priority     int           `json:"priority,omitempty"      yaml:"priority,omitempty"`
+	// RequestTimeout bounds each API request made by this source, overriding
+	// AppConfig.RequestTimeout. Zero means inherit.
+	RequestTimeout time.Duration `json:"request_timeout,omitempty" yaml:"request_timeout,omitempty"`
 	// ResolveReferences overrides the global SyncConfig.ResolveReferences for this source.
 	// nil means inherit from the global setting.
 	ResolveReferences *bool `json:"resolve_references,omitempty" yaml:"resolve_references,omitempty"`
+	// OmitBody overrides the global SyncConfig.OmitBody for this source.
+	// nil means inherit from the global setting.
+	OmitBody *bool `json:"omit_body,omitempty" yaml:"omit_body,omitempty"`
+	// OnEmptyResult is what a fetch returning no items means: "ignore"
+	// (default), "warn", or "error" to fail the run (useful under cron).
+	OnEmptyResult string `json:"on_empty_result,omitempty" yaml:"on_empty_result,omitempty"`
 
 	// Source-specific configurations
 	Google     GoogleSourceConfig     `json:"google,omitempty"     yaml:"google,omitempty"`
@@ -106,6 +174,11 @@ type SourceConfig struct {
 	Jira       JiraSourceConfig       `json:"jira,omitempty"       yaml:"jira,omitempty"`
 	Drive      DriveSourceConfig      `json:"drive,omitempty"      yaml:"drive,omitempty"`
 	ServiceNow ServiceNowSourceConfig `json:"servicenow,omitempty" yaml:"servicenow,omitempty"`
+	JSONL      JSONLSourceConfig      `json:"jsonl,omitempty"      yaml:"jsonl,omitempty"`
+	Notion     NotionSourceConfig     `json:"notion,omitempty"     yaml:"notion,omitempty"`
+	RSS        RSSSourceConfig        `json:"rss,omitempty"        yaml:"rss,omitempty"`
+	LocalFS    LocalFSSourceConfig    `json:"localfs,omitempty"    yaml:"localfs,omitempty"`
+	GitHub     GitHubSourceConfig     `json:"github,omitempty"     yaml:"github,omitempty"`
 }
 
 // DriveSourceConfig defines configuration for a Google Drive source.
@@ -129,6 +202,22 @@ type DriveSourceConfig struct {
 	SheetExportFormat string `json:"sheet_export_format" yaml:"sheet_export_format"` // "csv" (default), "html"
 	SlideExportFormat string `json:"slide_export_format" yaml:"slide_export_format"` // "txt" (default), "html"
 
+	// SheetTabs exports only these spreadsheet tabs, by name or 1-based position
+	// (e.g. ["Summary", "3"]), each under its own heading. Empty = the default
+	// export, which holds the first tab for csv.
+	SheetTabs []string `json:"sheet_tabs,omitempty" yaml:"sheet_tabs,omitempty"`
+	// StripDocImages drops embedded images from exported Docs.
+	StripDocImages bool `json:"strip_doc_images,omitempty" yaml:"strip_doc_images,omitempty"`
+	// BundleFolders writes the files of each configured folder as one note,
+	// with a section per file and a table of contents, instead of a note each.
+	BundleFolders bool `json:"bundle_folders,omitempty" yaml:"bundle_folders,omitempty"`
+
+	// IncludeBinaryFiles also syncs regular uploaded files (PDF, docx, images, ...). Each is
+	// downloaded and saved as an attachment on a stub note. Default: Workspace files only.
+	IncludeBinaryFiles bool `json:"include_binary_files" yaml:"include_binary_files"`
+	// BinaryFileTypes limits downloaded files to these extensions, e.g. ["pdf", "png"] (empty = all)
+	BinaryFileTypes []string `json:"binary_file_types" yaml:"binary_file_types"`
+
 	// Custom Drive API query (appended with AND to the generated query)
 	Query string `json:"query" yaml:"query"`
 
@@ -158,6 +247,9 @@ type GoogleSourceConfig struct {
 	RequireMultipleAttendees bool `json:"require_multiple_attendees" yaml:"require_multiple_attendees"`
 	// include events where you're the only attendee (default: false)
 	IncludeSelfOnlyEvents bool `json:"include_self_only_events" yaml:"include_self_only_events"`
+	// only include events where your response is one of these:
+	// "accepted", "tentative", "declined", "needsAction" (default: all)
+	ResponseStatuses []string `json:"response_statuses,omitempty" yaml:"response_statuses,omitempty"`
 
 	// Drive settings
 	DownloadDocs  bool     `json:"download_docs"  yaml:"download_docs"`
@@ -183,6 +275,30 @@ type TargetConfig struct {
 
 	// Logseq-specific settings
 	Logseq LogseqTargetConfig `json:"logseq,omitempty" yaml:"logseq,omitempty"`
+
+	// Roam-specific settings
+	Roam RoamTargetConfig `json:"roam,omitempty" yaml:"roam,omitempty"`
+
+	// JSONL-specific settings
+	JSONL JSONLTargetConfig `json:"jsonl,omitempty" yaml:"jsonl,omitempty"`
+
+	// Metadata selects which item metadata keys appear in frontmatter/properties
+	Metadata MetadataFilterConfig `json:"metadata,omitempty" yaml:"metadata,omitempty"`
+
+	// TagHierarchySeparator marks tag levels in item tags ("priority:high");
+	// the target renders them with its own nesting syntax. Default ":".
+	TagHierarchySeparator string `json:"tag_hierarchy_separator,omitempty" yaml:"tag_hierarchy_separator,omitempty"`
+
+	// TruncateAt cuts note bodies longer than this many characters and links
+	// to the item's archived copy. Only items in the archive are truncated;
+	// 0 disables truncation.
+	TruncateAt int `json:"truncate_at,omitempty" yaml:"truncate_at,omitempty"`
+
+	// ContentPrefix and ContentSuffix are Go templates (formatter template
+	// fields and functions) rendered before and after every note body, e.g. a
+	// Dataview query or a "synced by pkm-sync" footer. Frontmatter stays first.
+	ContentPrefix string `json:"content_prefix,omitempty" yaml:"content_prefix,omitempty"`
+	ContentSuffix string `json:"content_suffix,omitempty" yaml:"content_suffix,omitempty"`
 }
 
 // FormatterSpec holds the Go template strings used by a configurable formatter.
@@ -216,6 +332,14 @@ type FormatterConfig struct {
 	Spec FormatterSpec `json:"spec" yaml:"spec"`
 }
 
+// MetadataFilterConfig is a per-target allowlist/denylist of metadata keys.
+// Entries may be exact keys or glob patterns such as "gmail_*". When neither
+// list is set, a built-in denylist hides internal keys (headers, snippet, ...).
+type MetadataFilterConfig struct {
+	Include []string `json:"include,omitempty" yaml:"include,omitempty"` // Only render these keys ("*" = all)
+	Exclude []string `json:"exclude,omitempty" yaml:"exclude,omitempty"` // Never render these keys
+}
+
 type ObsidianTargetConfig struct {
 	// Vault organization (vault path is the output directory)
 	DefaultFolder string `json:"default_folder" yaml:"default_folder"` // "Calendar", "Inbox"
@@ -254,6 +378,22 @@ type LogseqTargetConfig struct {
 	JournalDateFormat string `json:"journal_date_format" yaml:"journal_date_format"`
 }
 
+// RoamTargetConfig defines settings for the Roam Research target, which
+// writes one JSON import file per item.
+type RoamTargetConfig struct {
+	// DailyNoteRefs renders created dates as daily note references
+	// ("[[October 1st, 2026]]") instead of RFC 3339 timestamps.
+	DailyNoteRefs bool `json:"daily_note_refs" yaml:"daily_note_refs"`
+}
+
+// JSONLTargetConfig defines settings for the JSONL target, which appends
+// every synced item to one newline-delimited JSON file.
+type JSONLTargetConfig struct {
+	// Path of the file; relative paths are under the output directory
+	// (default: "items.jsonl").
+	Path string `json:"path,omitempty" yaml:"path,omitempty"`
+}
+
 type AuthConfig struct {
 	// OAuth settings
 	CredentialsPath string `json:"credentials_path" yaml:"credentials_path"`
@@ -287,11 +427,37 @@ type AppConfig struct {
 	CacheDir     string        `json:"cache_dir"     yaml:"cache_dir"`
 	CacheTTL     time.Duration `json:"cache_ttl"     yaml:"cache_ttl"`
 
+	// Date rendering in notes: IANA zone (e.g. "Asia/Tokyo"; empty keeps each
+	// time's own zone) and Go date layout for template dates (default "2006-01-02")
+	Timezone   string `json:"timezone,omitempty"    yaml:"timezone,omitempty"`
+	DateFormat string `json:"date_format,omitempty" yaml:"date_format,omitempty"`
+
+	// Network: bounds each source API request (default: 2m)
+	RequestTimeout time.Duration `json:"request_timeout,omitempty" yaml:"request_timeout,omitempty"`
+
+	// HTTP transport shared by all API clients (proxy, custom CA, mTLS)
+	HTTP HTTPConfig `json:"http,omitempty" yaml:"http,omitempty"`
+
 	// Notifications
 	NotifyOnSuccess bool `json:"notify_on_success" yaml:"notify_on_success"`
 	NotifyOnError   bool `json:"notify_on_error"   yaml:"notify_on_error"`
 }
 
+// HTTPConfig configures the transport every API client is built on.
+type HTTPConfig struct {
+	// ProxyURL routes requests through a proxy; empty uses HTTP(S)_PROXY.
+	ProxyURL string `json:"proxy_url,omitempty" yaml:"proxy_url,omitempty"`
+	// NoProxy lists hosts that bypass ProxyURL (NO_PROXY syntax).
+	NoProxy string `json:"no_proxy,omitempty" yaml:"no_proxy,omitempty"`
+	// CABundle is a PEM file of CAs trusted in addition to the system pool.
+	CABundle string `json:"ca_bundle,omitempty" yaml:"ca_bundle,omitempty"`
+	// ClientCert and ClientKey are PEM files presented for mTLS.
+	ClientCert string `json:"client_cert,omitempty" yaml:"client_cert,omitempty"`
+	ClientKey  string `json:"client_key,omitempty"  yaml:"client_key,omitempty"`
+	// InsecureSkipVerify disables certificate verification. Testing only.
+	InsecureSkipVerify bool `json:"insecure_skip_verify,omitempty" yaml:"insecure_skip_verify,omitempty"`
+}
+
 // Future source configurations (placeholders for planned integrations)
 
 type SlackSourceConfig struct {
@@ -316,10 +482,18 @@ type SlackSourceConfig struct {
 	MinLength    int      `json:"min_length"    yaml:"min_length"` // Minimum message length
 	IncludeFiles bool     `json:"include_files" yaml:"include_files"`
 	FileTypes    []string `json:"file_types"    yaml:"file_types"` // ["pdf", "doc", "img"]
+	// MaxFileSizeBytes skips shared files larger than this (0 = no limit).
+	MaxFileSizeBytes int64 `json:"max_file_size_bytes,omitempty" yaml:"max_file_size_bytes,omitempty"`
+	// FilesDir is where downloaded files are saved (default <config dir>/slack-files/<workspace>).
+	FilesDir string `json:"files_dir,omitempty" yaml:"files_dir,omitempty"`
 
 	// Rate limiting and performance
 	RateLimitMs           int `json:"rate_limit_ms"            yaml:"rate_limit_ms"`
 	MaxMessagesPerChannel int `json:"max_messages_per_channel" yaml:"max_messages_per_channel"`
+
+	// ExportToVault also writes the messages as notes to the sync target,
+	// besides the Slack archive.
+	ExportToVault bool `json:"export_to_vault,omitempty" yaml:"export_to_vault,omitempty"`
 }
 
 type GmailSourceConfig struct {
@@ -335,14 +509,28 @@ type GmailSourceConfig struct {
 	IncludeUnread  bool   `json:"include_unread"  yaml:"include_unread"`
 	IncludeRead    bool   `json:"include_read"    yaml:"include_read"`
 	IncludeThreads bool   `json:"include_threads" yaml:"include_threads"`
+	// Sync Google Chat messages (CHAT label) as chat items; skipped by default
+	IncludeChats bool `json:"include_chats,omitempty" yaml:"include_chats,omitempty"`
 	// "individual", "consolidated", "summary"
 	ThreadMode string `json:"thread_mode,omitempty" yaml:"thread_mode,omitempty"`
 	// Max messages in summary (default: 5)
 	ThreadSummaryLength int `json:"thread_summary_length,omitempty" yaml:"thread_summary_length,omitempty"`
+	// Fetch at most this many of a thread's most recent messages (0 = all)
+	MaxThreadMessages int `json:"max_thread_messages,omitempty" yaml:"max_thread_messages,omitempty"`
 	// e.g., "30d", "1y"
 	MaxEmailAge string `json:"max_email_age" yaml:"max_email_age"`
 	// e.g., "1d" (exclude very recent)
 	MinEmailAge string `json:"min_email_age,omitempty" yaml:"min_email_age,omitempty"`
+	// What to do with a message that has neither a parseable Date header nor an
+	// internal date: "skip" (default), "sentinel" (date it UndatedSentinelDate)
+	// or "now" (date it at sync time).
+	UndatedMessages string `json:"undated_messages,omitempty" yaml:"undated_messages,omitempty"`
+	// Date used by undated_messages: sentinel, as YYYY-MM-DD (default: 1970-01-01).
+	UndatedSentinelDate string `json:"undated_sentinel_date,omitempty" yaml:"undated_sentinel_date,omitempty"`
+	// What to do with emails attached as message/rfc822 parts (forwarded "as
+	// attachment"): "off" (default), "inline" (append them to the note) or
+	// "item" (emit each as its own item linked to the carrying message).
+	EmbeddedMessages string `json:"embedded_messages,omitempty" yaml:"embedded_messages,omitempty"`
 
 	// Sender/recipient filtering (NEW)
 	// e.g., ["company.com"]
@@ -375,6 +563,11 @@ type GmailSourceConfig struct {
 	RequestDelay time.Duration `json:"request_delay,omitempty" yaml:"request_delay,omitempty"` // Delay between requests
 	MaxRequests  int           `json:"max_requests,omitempty"  yaml:"max_requests,omitempty"`  // Max requests per sync
 	BatchSize    int           `json:"batch_size,omitempty"    yaml:"batch_size,omitempty"`    // Messages per API call
+	// Bounds for concurrent thread/message fetches. Concurrency drops toward
+	// MinConcurrency on rate-limit errors and climbs back toward MaxConcurrency
+	// as calls succeed (defaults: 1 and 5, or 2 when request_delay > 100ms).
+	MinConcurrency int `json:"min_concurrency,omitempty" yaml:"min_concurrency,omitempty"`
+	MaxConcurrency int `json:"max_concurrency,omitempty" yaml:"max_concurrency,omitempty"`
 
 	// Output customization
 	// e.g., "{{date}}-{{from}}-{{subject}}"
@@ -382,6 +575,10 @@ type GmailSourceConfig struct {
 	IncludeThreadContext bool          `json:"include_thread_context,omitempty" yaml:"include_thread_context,omitempty"`
 	GroupByThread        bool          `json:"group_by_thread,omitempty"        yaml:"group_by_thread,omitempty"`
 	TaggingRules         []TaggingRule `json:"tagging_rules,omitempty"          yaml:"tagging_rules,omitempty"`
+	// Vault folder per label, highest precedence first; a message goes to the
+	// folder of its first matching entry. When set, system labels not listed
+	// fall back to default folders (STARRED, IMPORTANT, SENT).
+	Folders []GmailFolderMapping `json:"folders,omitempty" yaml:"folders,omitempty"`
 }
 
 type TaggingRule struct {
@@ -389,6 +586,11 @@ type TaggingRule struct {
 	Tags      []string `json:"tags"      yaml:"tags"`      // ["urgent", "work"]
 }
 
+type GmailFolderMapping struct {
+	Label  string `json:"label"  yaml:"label"`  // "STARRED", "Label_123"
+	Folder string `json:"folder" yaml:"folder"` // "Mail/Starred"
+}
+
 type JiraSourceConfig struct {
 	// Instance and authentication
 	InstanceURL  string   `json:"instance_url" yaml:"instance_url"` // "https://company.atlassian.net"
@@ -427,10 +629,92 @@ type ServiceNowSourceConfig struct {
 	RequestDelay time.Duration `json:"request_delay,omitempty" yaml:"request_delay,omitempty"`
 }
 
+// JSONLSourceConfig defines configuration for a JSONL ingest source, which
+// reads items emitted by external scripts: one serialized models.FullItem per
+// line, the format `pkm-sync fetch --format json` writes.
+type JSONLSourceConfig struct {
+	// Path is the JSONL file to read. Empty or "-" reads stdin.
+	Path string `json:"path,omitempty" yaml:"path,omitempty"`
+}
+
+// NotionSourceConfig defines configuration for a Notion source, which syncs
+// the pages of databases and individual pages shared with an internal
+// integration.
+type NotionSourceConfig struct {
+	// IntegrationToken is the internal integration secret. Empty reads the
+	// NOTION_TOKEN environment variable.
+	IntegrationToken string `json:"integration_token,omitempty" yaml:"integration_token,omitempty"`
+
+	// DatabaseIDs lists databases whose pages are synced.
+	DatabaseIDs []string `json:"database_ids,omitempty" yaml:"database_ids,omitempty"`
+
+	// PageIDs lists individual pages to sync.
+	PageIDs []string `json:"page_ids,omitempty" yaml:"page_ids,omitempty"`
+
+	// IncludeArchived also syncs archived and trashed pages (default: false).
+	IncludeArchived bool `json:"include_archived,omitempty" yaml:"include_archived,omitempty"`
+
+	// RequestDelay is the delay between successive API requests (default: 350ms).
+	RequestDelay time.Duration `json:"request_delay,omitempty" yaml:"request_delay,omitempty"`
+}
+
+// RSSSourceConfig defines configuration for an RSS/Atom feed source.
+type RSSSourceConfig struct {
+	// FeedURLs lists the RSS 2.0 or Atom feeds to sync.
+	FeedURLs []string `json:"feed_urls" yaml:"feed_urls"`
+
+	// MaxItemsPerFeed caps the posts taken from each feed per run (0 = all).
+	MaxItemsPerFeed int `json:"max_items_per_feed,omitempty" yaml:"max_items_per_feed,omitempty"`
+
+	// FetchFullContent downloads each post's page and converts its article to
+	// Markdown instead of using the feed's summary (default: false).
+	FetchFullContent bool `json:"fetch_full_content,omitempty" yaml:"fetch_full_content,omitempty"`
+}
+
+// LocalFSSourceConfig defines configuration for a local files source, which
+// re-ingests Markdown and plain-text notes already on disk.
+type LocalFSSourceConfig struct {
+	// RootDir is the directory walked for notes. Hidden directories such as
+	// .git and .obsidian are skipped.
+	RootDir string `json:"root_dir" yaml:"root_dir"`
+
+	// Extensions lists the file extensions to ingest (default: .md, .markdown, .txt).
+	Extensions []string `json:"extensions,omitempty" yaml:"extensions,omitempty"`
+
+	// ParseFrontmatter moves YAML frontmatter keys into item metadata and its
+	// tags list into item tags instead of keeping it in the content.
+	ParseFrontmatter bool `json:"parse_frontmatter,omitempty" yaml:"parse_frontmatter,omitempty"`
+}
+
+// GitHubSourceConfig defines configuration for a GitHub source, which syncs
+// issues and pull requests.
+type GitHubSourceConfig struct {
+	// Token is a personal access token. Empty reads the GITHUB_TOKEN
+	// environment variable.
+	Token string `json:"token,omitempty" yaml:"token,omitempty"`
+
+	// Repos lists "owner/name" repositories to sync. Empty syncs the issues
+	// assigned to the token's user across all repositories.
+	Repos []string `json:"repos,omitempty" yaml:"repos,omitempty"`
+
+	// IncludePRs also syncs pull requests (default: issues only).
+	IncludePRs bool `json:"include_prs,omitempty" yaml:"include_prs,omitempty"`
+
+	// Assignee limits repos to issues assigned to this login ("none" or "*" as
+	// in the GitHub API).
+	Assignee string `json:"assignee,omitempty" yaml:"assignee,omitempty"`
+
+	// Labels limits results to issues carrying all of these labels.
+	Labels []string `json:"labels,omitempty" yaml:"labels,omitempty"`
+}
+
 // VectorDBConfig defines vector database configuration.
 type VectorDBConfig struct {
 	DBPath    string `json:"db_path"    yaml:"db_path"`    // Path to SQLite database file
 	AutoIndex bool   `json:"auto_index" yaml:"auto_index"` // Auto-index on sync
+	// MinScore is the default cosine similarity below which search results
+	// are dropped (search --min-score, /api/search min_score); 0 keeps all.
+	MinScore float64 `json:"min_score,omitempty" yaml:"min_score,omitempty"`
 }
 
 // EmbeddingsConfig defines embeddings provider configuration.
@@ -440,6 +724,9 @@ type EmbeddingsConfig struct {
 	APIURL     string `json:"api_url"    yaml:"api_url"`    // API base URL
 	APIKey     string `json:"api_key"    yaml:"api_key"`    // API key (for OpenAI)
 	Dimensions int    `json:"dimensions" yaml:"dimensions"` // Embedding dimensions
+	// DimensionMismatch decides what happens when the model's embeddings are
+	// not Dimensions long: "fail" (default) or "correct" (use the model's).
+	DimensionMismatch string `json:"dimension_mismatch,omitempty" yaml:"dimension_mismatch,omitempty"`
 }
 
 // SlackConfig defines configuration for the Slack archive sink.
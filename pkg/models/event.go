@@ -27,11 +27,13 @@ type CalendarEvent struct {
 	StartTime        time.Time
 	EndTime          time.Time
 	IsAllDay         bool
+	TimeZone         string // IANA zone the event was scheduled in, e.g. "Europe/Berlin"; empty if unknown
 	Location         string
 	Attendees        []Attendee
 	MyResponseStatus string // The calendar owner's response: "accepted", "declined", "tentative", "needsAction"
 	MeetingURL       string
 	Attachments      []CalendarAttachment
+	Redacted         bool // Private event whose details were left out (include_private: false)
 }
 
 type CalendarAttachment struct {
@@ -9,6 +9,11 @@ import (
 // SourceTypeGoogleCalendar is the canonical source type for Google Calendar items.
 const SourceTypeGoogleCalendar = "google_calendar"
 
+// MetadataSourceName is the metadata key holding the configured name of the
+// source an item was fetched from (e.g. "gmail_work"). Sinks should read it
+// rather than parse source tags, whose format is user-configurable.
+const MetadataSourceName = "source_name"
+
 // CoreItem provides essential identity and content methods (6 methods).
 type CoreItem interface {
 	GetID() string
@@ -186,6 +191,18 @@ func FromCalendarEvent(event *CalendarEvent) *Item {
 		},
 	}
 
+	if event.IsAllDay {
+		item.Metadata["all_day"] = true
+	}
+
+	if event.TimeZone != "" {
+		item.Metadata["time_zone"] = event.TimeZone
+	}
+
+	if event.Redacted {
+		item.Metadata["redacted"] = true
+	}
+
 	// Convert Calendar attachments
 	for _, attachment := range event.Attachments {
 		item.Attachments = append(item.Attachments, Attachment{
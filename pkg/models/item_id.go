@@ -0,0 +1,49 @@
+package models
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DerivedIDPrefix marks item IDs computed by DeriveItemID rather than issued
+// by the source.
+const DerivedIDPrefix = "derived-"
+
+// DeriveItemID returns a deterministic ID for an item whose source has no
+// stable one (a feed entry without a guid, a local file). It hashes the source
+// type, title, date and content, so the same logical item gets the same ID on
+// every run and sinks can upsert or skip it; changing any of them yields a new
+// ID. Surrounding whitespace, line endings and the date's time zone do not
+// affect the result.
+func DeriveItemID(sourceType, title string, date time.Time, content string) string {
+	content = strings.ReplaceAll(content, "\r\n", "\n")
+
+	stamp := ""
+	if !date.IsZero() {
+		stamp = date.UTC().Format(time.RFC3339)
+	}
+
+	hash := sha256.New()
+
+	for _, field := range []string{sourceType, strings.TrimSpace(title), stamp, strings.TrimSpace(content)} {
+		// Length-prefixing keeps ("ab", "c") and ("a", "bc") apart.
+		hash.Write([]byte(strconv.Itoa(len(field)) + ":" + field))
+	}
+
+	return DerivedIDPrefix + hex.EncodeToString(hash.Sum(nil))[:32]
+}
+
+// EnsureID gives item a DeriveItemID ID, from its creation time, when it has
+// none. It reports whether an ID was derived.
+func EnsureID(item FullItem) bool {
+	if item.GetID() != "" {
+		return false
+	}
+
+	item.SetID(DeriveItemID(item.GetSourceType(), item.GetTitle(), item.GetCreatedAt(), item.GetContent()))
+
+	return true
+}
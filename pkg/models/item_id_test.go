@@ -0,0 +1,65 @@
+package models
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDeriveItemID_StableAcrossRuns(t *testing.T) {
+	published := time.Date(2026, 5, 4, 9, 30, 0, 0, time.UTC)
+
+	// Two runs read the same entry; the second sees it in another time zone
+	// and with CRLF line endings, which must not change the ID.
+	first := DeriveItemID("rss", "Release notes 1.4", published, "Fixes:\n- sync\n")
+	second := DeriveItemID("rss", " Release notes 1.4 ",
+		published.In(time.FixedZone("CEST", 2*3600)), "Fixes:\r\n- sync\r\n")
+
+	if first != second {
+		t.Errorf("same item derived different IDs: %q vs %q", first, second)
+	}
+
+	if !strings.HasPrefix(first, DerivedIDPrefix) {
+		t.Errorf("derived ID %q lacks prefix %q", first, DerivedIDPrefix)
+	}
+}
+
+func TestDeriveItemID_ChangedItemGetsNewID(t *testing.T) {
+	published := time.Date(2026, 5, 4, 9, 30, 0, 0, time.UTC)
+	base := DeriveItemID("rss", "Release notes", published, "Fixes")
+
+	changed := map[string]string{
+		"content":     DeriveItemID("rss", "Release notes", published, "Fixes and features"),
+		"title":       DeriveItemID("rss", "Release notes (updated)", published, "Fixes"),
+		"date":        DeriveItemID("rss", "Release notes", published.Add(time.Minute), "Fixes"),
+		"source type": DeriveItemID("local_markdown", "Release notes", published, "Fixes"),
+		"field split": DeriveItemID("rss", "Release notesF", published, "ixes"),
+	}
+
+	for field, id := range changed {
+		if id == base {
+			t.Errorf("changing the %s kept ID %q", field, id)
+		}
+	}
+}
+
+func TestEnsureID(t *testing.T) {
+	item := NewBasicItem("", "Meeting notes")
+	item.SetSourceType("local")
+	item.SetContent("Agenda")
+	item.SetCreatedAt(time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC))
+
+	if !EnsureID(item) {
+		t.Fatal("expected an ID to be derived for an item without one")
+	}
+
+	want := DeriveItemID("local", "Meeting notes", item.GetCreatedAt(), "Agenda")
+	if item.GetID() != want {
+		t.Errorf("ID = %q, want %q", item.GetID(), want)
+	}
+
+	native := NewBasicItem("native-1", "Meeting notes")
+	if EnsureID(native) || native.GetID() != "native-1" {
+		t.Errorf("native ID must be kept, got %q", native.GetID())
+	}
+}
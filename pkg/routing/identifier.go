@@ -1,7 +1,10 @@
 // Package routing provides argument parsing utilities for verb-centric CLI commands.
 package routing
 
-import "strings"
+import (
+	"sort"
+	"strings"
+)
 
 // ParsedIdentifier is the result of parsing a `fetch` or `search` argument.
 // The argument can be a bare URL, a source-qualified key ("jira/PROJ-123"), or
@@ -80,6 +83,8 @@ var sourceTypeAliases = map[string]string{
 	"slack":      "slack",
 	"snow":       canonicalServiceNow,
 	"servicenow": canonicalServiceNow,
+	"jsonl":      "jsonl",
+	"stdin":      "jsonl",
 }
 
 // CanonicalSourceType converts a short alias (e.g. "drive") to the canonical
@@ -104,6 +109,25 @@ var knownCanonicalTypes = func() map[string]bool {
 	return m
 }()
 
+// SourceTypeNames returns every accepted source type name, aliases and
+// canonical types alike, sorted.
+func SourceTypeNames() []string {
+	seen := make(map[string]bool)
+	for alias, canonical := range sourceTypeAliases {
+		seen[alias] = true
+		seen[canonical] = true
+	}
+
+	names := make([]string, 0, len(seen))
+	for name := range seen {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return names
+}
+
 // IsCanonicalType reports whether s is a canonical source type string
 // (e.g. "google_drive", "jira") as opposed to an alias or source name.
 func IsCanonicalType(s string) bool {